@@ -43,13 +43,25 @@ func (c *APIClient) delete(path string) ([]byte, int, error) {
 }
 
 func (c *APIClient) do(method, path string, body any) ([]byte, int, error) {
+	if body == nil {
+		return c.send(method, path, "", nil)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal request: %w", err)
+	}
+	return c.send(method, path, "application/json", data)
+}
+
+// postRaw posts a body that is already encoded, such as a config file.
+func (c *APIClient) postRaw(path, contentType string, body []byte) ([]byte, int, error) {
+	return c.send(http.MethodPost, path, contentType, body)
+}
+
+func (c *APIClient) send(method, path, contentType string, body []byte) ([]byte, int, error) {
 	var reqBody io.Reader
 	if body != nil {
-		data, err := json.Marshal(body)
-		if err != nil {
-			return nil, 0, fmt.Errorf("marshal request: %w", err)
-		}
-		reqBody = bytes.NewReader(data)
+		reqBody = bytes.NewReader(body)
 	}
 
 	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
@@ -58,8 +70,8 @@ func (c *APIClient) do(method, path string, body any) ([]byte, int, error) {
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	resp, err := c.httpClient.Do(req)
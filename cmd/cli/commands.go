@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 )
@@ -458,6 +460,8 @@ func cmdIncidents(args []string) {
 	switch sub {
 	case "list", "ls":
 		incidentsList(subArgs)
+	case "declare":
+		incidentsDeclare(subArgs)
 	case "ack", "acknowledge":
 		if len(subArgs) < 1 {
 			fatal("usage: watchdog incidents ack <id>")
@@ -479,7 +483,14 @@ func incidentsHelp() {
 	fmt.Println(`Usage: watchdog incidents <subcommand>
 
 Subcommands:
-  list [--resolved]        List incidents (default: active)
+  list [--resolved] [--severity <list>]
+                           List incidents (default: active); --severity takes
+                           a comma-separated list, e.g. critical,major
+  declare --title <title> [--description <text>] [--severity <level>]
+          [--owner <user-id>] [--monitor <id>]...
+                           Declare an incident no monitor detects, e.g. a
+                           vendor outage; --monitor marks an affected monitor
+                           and may be repeated
   ack <id>                 Acknowledge an incident
   resolve <id>             Resolve an incident
 
@@ -491,13 +502,19 @@ func incidentsList(args []string) {
 	cfg := mustLoadConfig()
 	client := newClient(cfg)
 
-	path := "/incidents"
-	for _, a := range args {
-		if a == "--resolved" {
-			path = "/incidents?status=resolved"
-			break
+	query := url.Values{}
+	for i, a := range args {
+		switch {
+		case a == "--resolved":
+			query.Set("status", "resolved")
+		case a == "--severity" && i+1 < len(args):
+			query.Set("severity", args[i+1])
 		}
 	}
+	path := "/incidents"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
 
 	body, status, err := client.get(path)
 	if err != nil {
@@ -511,10 +528,12 @@ func incidentsList(args []string) {
 		Data []struct {
 			ID             string  `json:"id"`
 			MonitorID      string  `json:"monitor_id"`
+			MonitorName    string  `json:"monitor_name"`
 			Status         string  `json:"status"`
 			StartedAt      string  `json:"started_at"`
 			ResolvedAt     *string `json:"resolved_at"`
 			AcknowledgedAt *string `json:"acknowledged_at"`
+			Severity       string  `json:"severity"`
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(body, &resp); err != nil {
@@ -526,10 +545,15 @@ func incidentsList(args []string) {
 		return
 	}
 
-	headers := []string{"ID", "MONITOR", "STATUS", "STARTED"}
+	headers := []string{"ID", "MONITOR", "STATUS", "SEVERITY", "STARTED"}
 	var rows [][]string
 	for _, i := range resp.Data {
-		rows = append(rows, []string{i.ID[:8], i.MonitorID[:8], i.Status, i.StartedAt})
+		// Declared incidents have no monitor and show their title.
+		monitor := i.MonitorName
+		if len(i.MonitorID) >= 8 {
+			monitor = i.MonitorID[:8]
+		}
+		rows = append(rows, []string{i.ID[:8], monitor, i.Status, i.Severity, i.StartedAt})
 	}
 
 	if len(rows) == 0 {
@@ -539,6 +563,63 @@ func incidentsList(args []string) {
 	printTable(headers, rows)
 }
 
+func incidentsDeclare(args []string) {
+	reqBody := map[string]any{}
+	monitorIDs := []string{}
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			fatal("missing value for %s", args[i])
+		}
+		switch args[i] {
+		case "--title":
+			reqBody["title"] = args[i+1]
+		case "--description":
+			reqBody["description"] = args[i+1]
+		case "--severity":
+			reqBody["severity"] = args[i+1]
+		case "--owner":
+			reqBody["owner_id"] = args[i+1]
+		case "--monitor":
+			monitorIDs = append(monitorIDs, args[i+1])
+		default:
+			fatal("unknown flag: %s", args[i])
+		}
+		i++
+	}
+	if reqBody["title"] == nil {
+		fatal("usage: watchdog incidents declare --title <title> [--description <text>] [--severity <level>] [--owner <user-id>] [--monitor <id>]...")
+	}
+	reqBody["monitor_ids"] = monitorIDs
+
+	cfg := mustLoadConfig()
+	client := newClient(cfg)
+
+	body, status, err := client.post("/incidents", reqBody)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if status != 201 {
+		fatal(apiError(body, status))
+	}
+
+	var resp struct {
+		Data struct {
+			ID       string `json:"id"`
+			Title    string `json:"title"`
+			Severity string `json:"severity"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		fatal("parse response: %v", err)
+	}
+
+	if jsonOutput {
+		printJSON(resp.Data)
+		return
+	}
+	fmt.Printf("Incident declared: %s [%s] (%s)\n", resp.Data.Title, resp.Data.Severity, resp.Data.ID)
+}
+
 func incidentsAck(id string) {
 	cfg := mustLoadConfig()
 	client := newClient(cfg)
@@ -614,6 +695,210 @@ Shows a summary of your infrastructure: agents, monitors, and active incidents.`
 	fmt.Printf("Incidents:  %d active\n", stats.ActiveIncidents)
 }
 
+// --- config ---
+
+func cmdExport(args []string) {
+	args = filterArgs(args)
+
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		fmt.Println(`Usage: watchdog export [-f <file>]
+
+Exports agents, monitors, alert channels, status pages and maintenance
+windows as a declarative spec. Writes YAML to stdout, or to <file> (JSON
+when the file name ends in .json). Alert channel passwords are redacted;
+applying a redacted value keeps the stored one.
+
+Flags:
+  -f, --file <file>        Write the spec to a file
+  --json                   Print JSON instead of YAML`)
+		return
+	}
+
+	file := configFileArg(args)
+	asJSON := jsonOutput || strings.HasSuffix(file, ".json")
+
+	cfg := mustLoadConfig()
+	client := newClient(cfg)
+
+	path := "/config/export?format=yaml"
+	if asJSON {
+		path = "/config/export"
+	}
+	body, status, err := client.get(path)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if status != 200 {
+		fatal(apiError(body, status))
+	}
+
+	if asJSON {
+		var resp struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			fatal("parse response: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, resp.Data, "", "  "); err != nil {
+			fatal("parse response: %v", err)
+		}
+		buf.WriteByte('\n')
+		body = buf.Bytes()
+	}
+
+	if file == "" {
+		fmt.Print(string(body))
+		return
+	}
+	if err := os.WriteFile(file, body, 0o644); err != nil {
+		fatal("write %s: %v", file, err)
+	}
+	fmt.Printf("Config exported to %s\n", file)
+}
+
+func cmdPlan(args []string) {
+	args = filterArgs(args)
+
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		fmt.Println(`Usage: watchdog plan -f <file>
+
+Shows the creates, updates and deletes that applying the spec in <file>
+would make, without making them. The file may be YAML or JSON.
+
+Flags:
+  -f, --file <file>        Spec to compare against the hub
+  --json                   Output as JSON`)
+		return
+	}
+
+	body := postConfigFile("/config/plan", args)
+
+	var resp struct {
+		Data struct {
+			Changes []configChange `json:"changes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		fatal("parse response: %v", err)
+	}
+
+	if jsonOutput {
+		printJSON(resp.Data)
+		return
+	}
+	if len(resp.Data.Changes) == 0 {
+		fmt.Println("No changes. The hub matches the spec.")
+		return
+	}
+	printChanges(resp.Data.Changes)
+	fmt.Printf("\nPlan: %d change(s). Run 'watchdog apply' with the same file to make them.\n", len(resp.Data.Changes))
+}
+
+func cmdApply(args []string) {
+	args = filterArgs(args)
+
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		fmt.Println(`Usage: watchdog apply -f <file>
+
+Brings the hub in line with the spec in <file>, in one transaction: either
+every change is made or none is. Sections left out of the spec are not
+touched; resources missing from a section that is present are deleted.
+Agents are created but never deleted. The file may be YAML or JSON.
+
+Flags:
+  -f, --file <file>        Spec to apply
+  --json                   Output as JSON`)
+		return
+	}
+
+	body := postConfigFile("/config/apply", args)
+
+	var resp struct {
+		Data struct {
+			Changes   []configChange    `json:"changes"`
+			AgentKeys map[string]string `json:"agent_keys,omitempty"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		fatal("parse response: %v", err)
+	}
+
+	if jsonOutput {
+		printJSON(resp.Data)
+		return
+	}
+	if len(resp.Data.Changes) == 0 {
+		fmt.Println("No changes. The hub matches the spec.")
+		return
+	}
+	printChanges(resp.Data.Changes)
+	fmt.Printf("\nApplied %d change(s).\n", len(resp.Data.Changes))
+	for name, key := range resp.Data.AgentKeys {
+		fmt.Printf("API Key for agent %s: %s\n", name, key)
+	}
+	if len(resp.Data.AgentKeys) > 0 {
+		fmt.Println("\nSave these API keys — they cannot be retrieved again.")
+	}
+}
+
+type configChange struct {
+	Action   string   `json:"action"`
+	Resource string   `json:"resource"`
+	Name     string   `json:"name"`
+	Fields   []string `json:"fields,omitempty"`
+}
+
+func printChanges(changes []configChange) {
+	headers := []string{"ACTION", "RESOURCE", "NAME", "FIELDS"}
+	var rows [][]string
+	for _, ch := range changes {
+		rows = append(rows, []string{ch.Action, ch.Resource, ch.Name, strings.Join(ch.Fields, ", ")})
+	}
+	printTable(headers, rows)
+}
+
+// postConfigFile sends the spec named by -f to path and returns the
+// successful response body.
+func postConfigFile(path string, args []string) []byte {
+	file := configFileArg(args)
+	if file == "" {
+		fatal("a spec file is required: -f <file>")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fatal("read %s: %v", file, err)
+	}
+
+	// YAML unless the file says otherwise; JSON is valid YAML anyway.
+	contentType := "application/yaml"
+	if strings.HasSuffix(file, ".json") {
+		contentType = "application/json"
+	}
+
+	cfg := mustLoadConfig()
+	client := newClient(cfg)
+
+	body, status, err := client.postRaw(path, contentType, data)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if status != 200 {
+		fatal(apiError(body, status))
+	}
+	return body
+}
+
+// configFileArg returns the value of -f or --file, or "".
+func configFileArg(args []string) string {
+	for i, a := range args {
+		if (a == "-f" || a == "--file") && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
 // --- helpers ---
 
 func mustLoadConfig() *CLIConfig {
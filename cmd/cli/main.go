@@ -41,6 +41,12 @@ func main() {
 		cmdIncidents(args)
 	case "status":
 		cmdStatus(args)
+	case "export":
+		cmdExport(args)
+	case "plan":
+		cmdPlan(args)
+	case "apply":
+		cmdApply(args)
 	case "version":
 		fmt.Printf("watchdog-cli v%s\n", version)
 	case "help", "--help", "-h":
@@ -59,8 +65,11 @@ Commands:
   login                    Authenticate with a WatchDog hub
   monitors                 Manage monitors (list, get, create, delete)
   agents                   Manage agents (list, create, delete)
-  incidents                Manage incidents (list, ack, resolve)
+  incidents                Manage incidents (list, declare, ack, resolve)
   status                   Show infrastructure overview
+  export                   Export the setup as a YAML or JSON spec
+  plan                     Show what applying a spec would change
+  apply                    Apply a spec to the hub
   version                  Print version
 
 Run 'watchdog <command> --help' for details on a command.`)
@@ -42,6 +42,7 @@ type Agent struct {
 	Version               string
 	Fingerprint           map[string]string
 	FingerprintVerifiedAt *time.Time
+	Hub                   bool // virtual agent whose checks run inside the hub
 	TenantID              string
 	CreatedAt             time.Time
 }
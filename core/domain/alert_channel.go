@@ -11,34 +11,51 @@ import (
 type AlertChannelType string
 
 const (
-	AlertChannelDiscord   AlertChannelType = "discord"
-	AlertChannelSlack     AlertChannelType = "slack"
-	AlertChannelEmail     AlertChannelType = "email"
-	AlertChannelTelegram  AlertChannelType = "telegram"
-	AlertChannelPagerDuty AlertChannelType = "pagerduty"
-	AlertChannelWebhook   AlertChannelType = "webhook"
+	AlertChannelDiscord      AlertChannelType = "discord"
+	AlertChannelSlack        AlertChannelType = "slack"
+	AlertChannelEmail        AlertChannelType = "email"
+	AlertChannelTelegram     AlertChannelType = "telegram"
+	AlertChannelPagerDuty    AlertChannelType = "pagerduty"
+	AlertChannelWebhook      AlertChannelType = "webhook"
+	AlertChannelTeams        AlertChannelType = "teams"
+	AlertChannelMattermost   AlertChannelType = "mattermost"
+	AlertChannelOpsgenie     AlertChannelType = "opsgenie"
+	AlertChannelSplunkOnCall AlertChannelType = "splunk_oncall"
+)
+
+// Opsgenie account regions, set in an opsgenie channel's "region" config;
+// empty is US.
+const (
+	OpsgenieRegionUS = "us"
+	OpsgenieRegionEU = "eu"
 )
 
 // ValidAlertChannelTypes is the set of supported alert channel types.
 var ValidAlertChannelTypes = map[AlertChannelType]bool{
-	AlertChannelDiscord:   true,
-	AlertChannelSlack:     true,
-	AlertChannelEmail:     true,
-	AlertChannelTelegram:  true,
-	AlertChannelPagerDuty: true,
-	AlertChannelWebhook:   true,
+	AlertChannelDiscord:      true,
+	AlertChannelSlack:        true,
+	AlertChannelEmail:        true,
+	AlertChannelTelegram:     true,
+	AlertChannelPagerDuty:    true,
+	AlertChannelWebhook:      true,
+	AlertChannelTeams:        true,
+	AlertChannelMattermost:   true,
+	AlertChannelOpsgenie:     true,
+	AlertChannelSplunkOnCall: true,
 }
 
 // AlertChannel represents a user-configured notification channel.
 type AlertChannel struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Type      AlertChannelType
-	Name      string
-	Config    map[string]string // decrypted config, never persisted in plaintext
-	Enabled   bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID      uuid.UUID
+	UserID  uuid.UUID
+	Type    AlertChannelType
+	Name    string
+	Config  map[string]string // decrypted config, never persisted in plaintext
+	Enabled bool
+	// DeliverySchedule limits when the channel delivers; nil delivers always.
+	DeliverySchedule *DeliverySchedule
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 // NewAlertChannel creates a new AlertChannel with defaults.
@@ -65,7 +82,7 @@ func (ac *AlertChannel) Validate() error {
 	}
 
 	switch ac.Type {
-	case AlertChannelDiscord, AlertChannelSlack:
+	case AlertChannelDiscord, AlertChannelSlack, AlertChannelTeams, AlertChannelMattermost:
 		if ac.Config["webhook_url"] == "" {
 			return fmt.Errorf("webhook_url is required for %s", ac.Type)
 		}
@@ -74,18 +91,68 @@ func (ac *AlertChannel) Validate() error {
 			return fmt.Errorf("url is required for webhook")
 		}
 	case AlertChannelEmail:
-		if ac.Config["host"] == "" || ac.Config["from"] == "" || ac.Config["to"] == "" {
+		if ac.Config["host"] == "" || ac.Config["from"] == "" || (ac.Config["to"] == "" && !ac.IsOnCall()) {
 			return fmt.Errorf("host, from, and to are required for email")
 		}
 	case AlertChannelTelegram:
-		if ac.Config["bot_token"] == "" || ac.Config["chat_id"] == "" {
+		if ac.Config["bot_token"] == "" || (ac.Config["chat_id"] == "" && !ac.IsOnCall()) {
 			return fmt.Errorf("bot_token and chat_id are required for telegram")
 		}
 	case AlertChannelPagerDuty:
 		if ac.Config["routing_key"] == "" {
 			return fmt.Errorf("routing_key is required for pagerduty")
 		}
+	case AlertChannelOpsgenie:
+		if ac.Config["api_key"] == "" {
+			return fmt.Errorf("api_key is required for opsgenie")
+		}
+		switch ac.Config["region"] {
+		case "", OpsgenieRegionUS, OpsgenieRegionEU:
+		default:
+			return fmt.Errorf("region must be %s or %s for opsgenie", OpsgenieRegionUS, OpsgenieRegionEU)
+		}
+	case AlertChannelSplunkOnCall:
+		if ac.Config["api_key"] == "" || ac.Config["routing_key"] == "" {
+			return fmt.Errorf("api_key and routing_key are required for splunk_oncall")
+		}
+	}
+
+	if ac.IsOnCall() {
+		if ac.Type != AlertChannelEmail && ac.Type != AlertChannelTelegram {
+			return fmt.Errorf("%s is only supported for email and telegram", OnCallScheduleConfigKey)
+		}
+		if _, ok := ac.OnCallScheduleID(); !ok {
+			return fmt.Errorf("invalid %s", OnCallScheduleConfigKey)
+		}
+	}
+
+	if _, err := ac.NotificationPolicy(); err != nil {
+		return err
+	}
+
+	if ac.DeliverySchedule != nil {
+		if err := ac.DeliverySchedule.Validate(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// IsIncidentManagement returns true for incident-management integrations,
+// which open and resolve incidents on every event rather than post messages.
+func (t AlertChannelType) IsIncidentManagement() bool {
+	return t == AlertChannelPagerDuty || t == AlertChannelOpsgenie || t == AlertChannelSplunkOnCall
+}
+
+// IsOnCall returns true if the channel pages whoever is on call for a
+// schedule: its recipient (email) or chat (Telegram) is resolved at send time.
+func (ac *AlertChannel) IsOnCall() bool {
+	return ac.Config[OnCallScheduleConfigKey] != ""
+}
+
+// OnCallScheduleID returns the schedule an on-call channel pages.
+func (ac *AlertChannel) OnCallScheduleID() (uuid.UUID, bool) {
+	id, err := uuid.Parse(ac.Config[OnCallScheduleConfigKey])
+	return id, err == nil
+}
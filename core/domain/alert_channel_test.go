@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertChannel_ValidateWebhookURL(t *testing.T) {
+	for _, channelType := range []AlertChannelType{AlertChannelDiscord, AlertChannelSlack, AlertChannelTeams, AlertChannelMattermost} {
+		ch := NewAlertChannel(uuid.New(), channelType, "ops", map[string]string{})
+		assert.EqualError(t, ch.Validate(), "webhook_url is required for "+string(channelType))
+
+		ch.Config["webhook_url"] = "https://chat.example.com/hooks/x"
+		assert.NoError(t, ch.Validate(), channelType)
+	}
+}
+
+func TestAlertChannel_ValidateIncidentManagement(t *testing.T) {
+	opsgenie := NewAlertChannel(uuid.New(), AlertChannelOpsgenie, "ops", map[string]string{})
+	assert.EqualError(t, opsgenie.Validate(), "api_key is required for opsgenie")
+
+	opsgenie.Config["api_key"] = "key"
+	assert.NoError(t, opsgenie.Validate())
+	opsgenie.Config["region"] = OpsgenieRegionEU
+	assert.NoError(t, opsgenie.Validate())
+	opsgenie.Config["region"] = "apac"
+	assert.EqualError(t, opsgenie.Validate(), "region must be us or eu for opsgenie")
+
+	splunk := NewAlertChannel(uuid.New(), AlertChannelSplunkOnCall, "ops", map[string]string{"api_key": "key"})
+	assert.EqualError(t, splunk.Validate(), "api_key and routing_key are required for splunk_oncall")
+
+	splunk.Config["routing_key"] = "database"
+	assert.NoError(t, splunk.Validate())
+
+	assert.True(t, AlertChannelOpsgenie.IsIncidentManagement())
+	assert.False(t, AlertChannelSlack.IsIncidentManagement())
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultAlertGroupWindow is how long correlated incidents are collected
+// before an alert group is announced.
+const DefaultAlertGroupWindow = 30 * time.Second
+
+// MaxAlertGroupSummaryMonitors caps the monitors named in a grouped notification.
+const MaxAlertGroupSummaryMonitors = 10
+
+// ErrInvalidAlertGroupChange is returned when a merge or split is not allowed.
+var ErrInvalidAlertGroupChange = errors.New("invalid alert group change")
+
+// AlertGroupReason says what the incidents of an alert group have in common.
+type AlertGroupReason string
+
+const (
+	AlertGroupReasonAgent      AlertGroupReason = "agent"
+	AlertGroupReasonDependency AlertGroupReason = "dependency"
+	AlertGroupReasonSubnet     AlertGroupReason = "subnet"
+	AlertGroupReasonTag        AlertGroupReason = "tag"
+	// AlertGroupReasonManual marks groups formed by merging or splitting.
+	AlertGroupReasonManual AlertGroupReason = "manual"
+)
+
+// AlertGroupStatus is the lifecycle state of an alert group.
+type AlertGroupStatus string
+
+const (
+	AlertGroupStatusOpen     AlertGroupStatus = "open"
+	AlertGroupStatusResolved AlertGroupStatus = "resolved"
+)
+
+// IsValid checks if the status is a valid AlertGroupStatus.
+func (s AlertGroupStatus) IsValid() bool {
+	return s == AlertGroupStatusOpen || s == AlertGroupStatusResolved
+}
+
+// CorrelationKeys returns the keys incidents on a monitor are correlated by,
+// most specific first: its agent, itself and the monitors it depends on, the
+// subnet of its target when that is an IP address, and each of its tags.
+func CorrelationKeys(m *Monitor, parentIDs []uuid.UUID) []string {
+	keys := []string{"agent:" + m.AgentID.String(), "dependency:" + m.ID.String()}
+	for _, id := range parentIDs {
+		keys = append(keys, "dependency:"+id.String())
+	}
+	if subnet := targetSubnet(m.Target); subnet != "" {
+		keys = append(keys, "subnet:"+subnet)
+	}
+	for k, v := range m.Metadata {
+		if k == EscalationPolicyTag || v == "" {
+			continue
+		}
+		keys = append(keys, "tag:"+k+"="+v)
+	}
+	return keys
+}
+
+// CorrelationKeyReason returns the reason a correlation key groups incidents
+// by, and the value it matched on (an ID, subnet or tag).
+func CorrelationKeyReason(key string) (AlertGroupReason, string) {
+	reason, value, _ := strings.Cut(key, ":")
+	return AlertGroupReason(reason), value
+}
+
+// targetSubnet returns the /24 (IPv4) or /64 (IPv6) network of a target
+// whose host is an IP address, or "" for hostnames.
+func targetSubnet(target string) string {
+	host := target
+	if strings.Contains(target, "://") {
+		if u, err := url.Parse(target); err == nil {
+			host = u.Hostname()
+		}
+	} else if h, _, err := net.SplitHostPort(target); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+// AlertGroup collects incidents opened close together that share an agent,
+// a dependency, a subnet or a tag, so they are announced by one notification
+// and resolved together. A new group is pending until it is announced one
+// window after it formed; incidents that correlate with it until then, or
+// within a window of its latest incident afterwards, join it silently.
+type AlertGroup struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Reason AlertGroupReason
+	Title  string
+	// Keys are the correlation keys of the group's incidents. Groups formed
+	// by hand have none and take no new incidents.
+	Keys []string
+	// LeadIncidentID is the incident whose notifications announce the group.
+	LeadIncidentID *uuid.UUID
+	Status         AlertGroupStatus
+	IncidentCount  int // populated by list queries
+	CreatedAt      time.Time
+	LastIncidentAt time.Time
+	NotifiedAt     *time.Time
+	ResolvedAt     *time.Time
+}
+
+// NewAlertGroup creates a pending group for an incident with the given
+// correlation keys.
+func NewAlertGroup(userID uuid.UUID, keys []string, at time.Time) *AlertGroup {
+	return &AlertGroup{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Keys:           keys,
+		Status:         AlertGroupStatusOpen,
+		CreatedAt:      at,
+		LastIncidentAt: at,
+	}
+}
+
+// Match returns the first of keys the group's incidents share.
+func (g *AlertGroup) Match(keys []string) (string, bool) {
+	for _, key := range keys {
+		for _, k := range g.Keys {
+			if k == key {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Add records an incident with the given keys joining the group at t.
+func (g *AlertGroup) Add(keys []string, t time.Time) {
+	for _, key := range keys {
+		if _, ok := g.Match([]string{key}); !ok {
+			g.Keys = append(g.Keys, key)
+		}
+	}
+	if t.After(g.LastIncidentAt) {
+		g.LastIncidentAt = t
+	}
+}
+
+// Accepts returns true if an incident opened at t may join the group.
+func (g *AlertGroup) Accepts(t time.Time, window time.Duration) bool {
+	return g.IsOpen() && len(g.Keys) > 0 && !t.After(g.LastIncidentAt.Add(window))
+}
+
+// IsOpen returns true if the group has incidents that have not recovered.
+func (g *AlertGroup) IsOpen() bool {
+	return g.Status == AlertGroupStatusOpen
+}
+
+// IsPending returns true if the group has not been announced yet.
+func (g *AlertGroup) IsPending() bool {
+	return g.IsOpen() && g.NotifiedAt == nil
+}
+
+// AlertGroupSummary describes an alert group in the notification announcing
+// it.
+type AlertGroupSummary struct {
+	ID    uuid.UUID
+	Title string
+	// Monitors names up to MaxAlertGroupSummaryMonitors of the group's
+	// monitors; Total counts all of its incidents.
+	Monitors []string
+	Total    int
+}
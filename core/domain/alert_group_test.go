@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationKeys(t *testing.T) {
+	parent := uuid.New()
+	m := &Monitor{
+		ID:       uuid.New(),
+		AgentID:  uuid.New(),
+		Target:   "https://10.0.3.17:8443/health",
+		Metadata: map[string]string{"team": "payments", EscalationPolicyTag: "primary", "empty": ""},
+	}
+
+	keys := CorrelationKeys(m, []uuid.UUID{parent})
+	assert.Equal(t, []string{
+		"agent:" + m.AgentID.String(),
+		"dependency:" + m.ID.String(),
+		"dependency:" + parent.String(),
+		"subnet:10.0.3.0/24",
+		"tag:team=payments",
+	}, keys)
+
+	reason, value := CorrelationKeyReason(keys[3])
+	assert.Equal(t, AlertGroupReasonSubnet, reason)
+	assert.Equal(t, "10.0.3.0/24", value)
+}
+
+func TestTargetSubnet(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"192.168.1.20", "192.168.1.0/24"},
+		{"192.168.1.20:5432", "192.168.1.0/24"},
+		{"http://192.168.1.20/ping", "192.168.1.0/24"},
+		{"[2001:db8::1]:443", "2001:db8::/64"},
+		{"db.internal:5432", ""},
+		{"https://example.com", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, targetSubnet(tt.target), tt.target)
+	}
+}
+
+func TestAlertGroup_MatchAddAccepts(t *testing.T) {
+	start := time.Now()
+	g := NewAlertGroup(uuid.New(), []string{"agent:a", "tag:team=payments"}, start)
+	assert.True(t, g.IsPending())
+
+	key, ok := g.Match([]string{"agent:b", "tag:team=payments"})
+	assert.True(t, ok)
+	assert.Equal(t, "tag:team=payments", key)
+	_, ok = g.Match([]string{"agent:b"})
+	assert.False(t, ok)
+
+	g.Add([]string{"agent:b", "tag:team=payments"}, start.Add(20*time.Second))
+	assert.Equal(t, []string{"agent:a", "tag:team=payments", "agent:b"}, g.Keys)
+	assert.Equal(t, start.Add(20*time.Second), g.LastIncidentAt)
+
+	// The window runs from the group's latest incident.
+	assert.True(t, g.Accepts(start.Add(50*time.Second), 30*time.Second))
+	assert.False(t, g.Accepts(start.Add(51*time.Second), 30*time.Second))
+
+	manual := NewAlertGroup(uuid.New(), nil, start)
+	assert.False(t, manual.Accepts(start, 30*time.Second))
+
+	g.Status = AlertGroupStatusResolved
+	assert.False(t, g.Accepts(start, 30*time.Second))
+	assert.False(t, g.IsPending())
+}
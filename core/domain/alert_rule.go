@@ -0,0 +1,382 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Alert rule limits.
+const (
+	MaxAlertRules          = 100
+	MaxAlertRuleNameLength = 100
+	MaxAlertRuleChannels   = 20
+	MaxAlertRuleConditions = 50
+)
+
+// AlertRuleTimeLayout is the format of a rule's time-of-day window bounds.
+const AlertRuleTimeLayout = "15:04"
+
+// ErrInvalidAlertRule is returned when an alert rule fails validation.
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+// AlertEventKind is the kind of event an alert announces.
+type AlertEventKind string
+
+const (
+	AlertEventIncidentOpened   AlertEventKind = "incident_opened"
+	AlertEventIncidentResolved AlertEventKind = "incident_resolved"
+	AlertEventAgentOffline     AlertEventKind = "agent_offline"
+	AlertEventAgentOnline      AlertEventKind = "agent_online"
+)
+
+// IsValid returns true if the event kind is recognized.
+func (k AlertEventKind) IsValid() bool {
+	switch k {
+	case AlertEventIncidentOpened, AlertEventIncidentResolved, AlertEventAgentOffline, AlertEventAgentOnline:
+		return true
+	default:
+		return false
+	}
+}
+
+// AlertRuleAction is what happens to an event an alert rule matches.
+type AlertRuleAction string
+
+const (
+	// AlertRuleActionRoute sends the event to the rule's channels, and the
+	// channels of earlier continue rules, and stops evaluation.
+	AlertRuleActionRoute AlertRuleAction = "route"
+	// AlertRuleActionSuppress drops the event and stops evaluation.
+	AlertRuleActionSuppress AlertRuleAction = "suppress"
+	// AlertRuleActionContinue adds the rule's channels and keeps evaluating.
+	AlertRuleActionContinue AlertRuleAction = "continue"
+)
+
+// IsValid returns true if the action is recognized.
+func (a AlertRuleAction) IsValid() bool {
+	switch a {
+	case AlertRuleActionRoute, AlertRuleActionSuppress, AlertRuleActionContinue:
+		return true
+	default:
+		return false
+	}
+}
+
+// AlertTimeWindow matches events between Start and End, in Timezone, on the
+// given weekdays (0 is Sunday; empty means every day). A window whose End is
+// not after its Start runs past midnight; the weekday is the one it started on.
+type AlertTimeWindow struct {
+	Start    string         `json:"start"`
+	End      string         `json:"end"`
+	Days     []time.Weekday `json:"days,omitempty"`
+	Timezone string         `json:"timezone,omitempty"`
+}
+
+func (w *AlertTimeWindow) validate() error {
+	start, err := time.Parse(AlertRuleTimeLayout, w.Start)
+	if err != nil {
+		return fmt.Errorf("%w: time_of_day.start must be HH:MM", ErrInvalidAlertRule)
+	}
+	end, err := time.Parse(AlertRuleTimeLayout, w.End)
+	if err != nil {
+		return fmt.Errorf("%w: time_of_day.end must be HH:MM", ErrInvalidAlertRule)
+	}
+	if start.Equal(end) {
+		return fmt.Errorf("%w: time_of_day.start and end must differ", ErrInvalidAlertRule)
+	}
+	for _, d := range w.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return fmt.Errorf("%w: time_of_day.days must be between 0 (Sunday) and 6", ErrInvalidAlertRule)
+		}
+	}
+	if w.Timezone == "" {
+		w.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidAlertRule, w.Timezone)
+	}
+	return nil
+}
+
+// Contains returns true if t falls inside the window.
+func (w *AlertTimeWindow) Contains(t time.Time) bool {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	start, _ := time.Parse(AlertRuleTimeLayout, w.Start)
+	end, _ := time.Parse(AlertRuleTimeLayout, w.End)
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	now := t.Hour()*60 + t.Minute()
+
+	day := t.Weekday()
+	switch {
+	case from < to:
+		if now < from || now >= to {
+			return false
+		}
+	case now >= from:
+		// Before midnight in a window that runs past it.
+	case now < to:
+		// After midnight: the window started the day before.
+		day = (day + 6) % 7
+	default:
+		return false
+	}
+	return len(w.Days) == 0 || slices.Contains(w.Days, day)
+}
+
+// AlertRuleMatch lists the conditions an event must meet for a rule to
+// match. Each set condition must hold; a list matches when any of its
+// entries does. A rule with no conditions matches every event.
+type AlertRuleMatch struct {
+	// Tags must all be present, with these values, in the monitor's metadata.
+	Tags         map[string]string  `json:"tags,omitempty"`
+	MonitorTypes []MonitorType      `json:"monitor_types,omitempty"`
+	AgentIDs     []uuid.UUID        `json:"agent_ids,omitempty"`
+	Severities   []IncidentSeverity `json:"severities,omitempty"`
+	Events       []AlertEventKind   `json:"events,omitempty"`
+	TimeOfDay    *AlertTimeWindow   `json:"time_of_day,omitempty"`
+}
+
+// Matches returns true if the event meets every condition.
+func (m *AlertRuleMatch) Matches(event *AlertEvent) bool {
+	if len(m.Events) > 0 && !slices.Contains(m.Events, event.Kind) {
+		return false
+	}
+	if len(m.Severities) > 0 && !slices.Contains(m.Severities, event.Severity) {
+		return false
+	}
+	if len(m.AgentIDs) > 0 && (event.AgentID == nil || !slices.Contains(m.AgentIDs, *event.AgentID)) {
+		return false
+	}
+	if len(m.MonitorTypes) > 0 && (event.Monitor == nil || !slices.Contains(m.MonitorTypes, event.Monitor.Type)) {
+		return false
+	}
+	for k, v := range m.Tags {
+		if event.Monitor == nil || event.Monitor.Metadata[k] != v {
+			return false
+		}
+	}
+	if m.TimeOfDay != nil && !m.TimeOfDay.Contains(event.At) {
+		return false
+	}
+	return true
+}
+
+func (m *AlertRuleMatch) validate() error {
+	conditions := len(m.Tags) + len(m.MonitorTypes) + len(m.AgentIDs) + len(m.Severities) + len(m.Events)
+	if conditions > MaxAlertRuleConditions {
+		return fmt.Errorf("%w: at most %d match conditions are allowed", ErrInvalidAlertRule, MaxAlertRuleConditions)
+	}
+	for k := range m.Tags {
+		if strings.TrimSpace(k) == "" {
+			return fmt.Errorf("%w: tag keys must not be empty", ErrInvalidAlertRule)
+		}
+	}
+	for _, t := range m.MonitorTypes {
+		if !t.IsValid() && t != MonitorType(IncidentKindDeclared) {
+			return fmt.Errorf("%w: unknown monitor type %q", ErrInvalidAlertRule, t)
+		}
+	}
+	for _, s := range m.Severities {
+		if !s.IsValid() {
+			return fmt.Errorf("%w: %s", ErrInvalidAlertRule, ErrInvalidIncidentSeverity.Error())
+		}
+	}
+	for _, e := range m.Events {
+		if !e.IsValid() {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidAlertRule, e)
+		}
+	}
+	if m.TimeOfDay != nil {
+		return m.TimeOfDay.validate()
+	}
+	return nil
+}
+
+// AlertRule routes the events it matches. A user's rules are evaluated in
+// Position order.
+type AlertRule struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Position   int
+	Enabled    bool
+	Match      AlertRuleMatch
+	Action     AlertRuleAction
+	ChannelIDs []uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewAlertRule creates a validated, enabled alert rule.
+func NewAlertRule(userID uuid.UUID, name string, position int, match AlertRuleMatch, action AlertRuleAction, channelIDs []uuid.UUID) (*AlertRule, error) {
+	now := time.Now()
+	r := &AlertRule{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       name,
+		Position:   position,
+		Enabled:    true,
+		Match:      match,
+		Action:     action,
+		ChannelIDs: channelIDs,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate checks the rule's name, conditions, action and channels.
+func (r *AlertRule) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAlertRule)
+	}
+	if len(r.Name) > MaxAlertRuleNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidAlertRule, MaxAlertRuleNameLength)
+	}
+	if r.Position < 0 {
+		return fmt.Errorf("%w: position must not be negative", ErrInvalidAlertRule)
+	}
+	if err := r.Match.validate(); err != nil {
+		return err
+	}
+	if !r.Action.IsValid() {
+		return fmt.Errorf("%w: action must be route, suppress or continue", ErrInvalidAlertRule)
+	}
+	switch {
+	case r.Action == AlertRuleActionSuppress && len(r.ChannelIDs) > 0:
+		return fmt.Errorf("%w: suppress rules take no channels", ErrInvalidAlertRule)
+	case r.Action == AlertRuleActionRoute && len(r.ChannelIDs) == 0:
+		return fmt.Errorf("%w: route rules need at least one channel", ErrInvalidAlertRule)
+	case len(r.ChannelIDs) > MaxAlertRuleChannels:
+		return fmt.Errorf("%w: at most %d channels are allowed", ErrInvalidAlertRule, MaxAlertRuleChannels)
+	}
+	return nil
+}
+
+// AlertEvent is an event to route to a user's alert channels. Monitor is
+// nil for agent events; AgentID is nil for declared incidents.
+type AlertEvent struct {
+	UserID   uuid.UUID
+	Kind     AlertEventKind
+	Monitor  *Monitor
+	AgentID  *uuid.UUID
+	Severity IncidentSeverity
+	At       time.Time
+}
+
+// NewIncidentAlertEvent describes an incident opening or resolving.
+func NewIncidentAlertEvent(userID uuid.UUID, incident *Incident, monitor *Monitor, opened bool) *AlertEvent {
+	event := &AlertEvent{
+		UserID:   userID,
+		Kind:     AlertEventIncidentResolved,
+		Monitor:  monitor,
+		Severity: incident.Severity,
+		At:       time.Now(),
+	}
+	if opened {
+		event.Kind = AlertEventIncidentOpened
+	}
+	if monitor != nil && monitor.AgentID != uuid.Nil {
+		agentID := monitor.AgentID
+		event.AgentID = &agentID
+	}
+	return event
+}
+
+// NewAgentAlertEvent describes an agent going offline or coming back.
+func NewAgentAlertEvent(userID, agentID uuid.UUID, online bool) *AlertEvent {
+	event := &AlertEvent{
+		UserID:  userID,
+		Kind:    AlertEventAgentOffline,
+		AgentID: &agentID,
+		At:      time.Now(),
+	}
+	if online {
+		event.Kind = AlertEventAgentOnline
+	}
+	return event
+}
+
+// AlertRuleHit records a rule that matched an event.
+type AlertRuleHit struct {
+	RuleID uuid.UUID
+	Name   string
+	Action AlertRuleAction
+}
+
+// AlertRouting is where an event goes. An event no rule matches takes the
+// default route: every enabled channel of the user.
+type AlertRouting struct {
+	Hits       []AlertRuleHit
+	ChannelIDs []uuid.UUID
+	Suppressed bool
+	Default    bool
+	// Channels are the enabled channels the event goes to, once resolved.
+	Channels []*AlertChannel
+}
+
+// Select returns the channels, out of a user's enabled ones, the routing
+// sends to. Channels the rules name that are disabled or gone are skipped.
+func (r *AlertRouting) Select(enabled []*AlertChannel) []*AlertChannel {
+	if r.Default {
+		return enabled
+	}
+	var out []*AlertChannel
+	for _, ch := range enabled {
+		if slices.Contains(r.ChannelIDs, ch.ID) {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+// RouteAlert evaluates enabled rules in order against an event. Channels of
+// matching continue rules accumulate until a route rule adds its own and
+// stops, or a suppress rule drops the event. When no rule stops it and no
+// matching rule named a channel, the event takes the default route.
+func RouteAlert(rules []*AlertRule, event *AlertEvent) *AlertRouting {
+	routing := &AlertRouting{}
+	add := func(ids []uuid.UUID) {
+		for _, id := range ids {
+			if !slices.Contains(routing.ChannelIDs, id) {
+				routing.ChannelIDs = append(routing.ChannelIDs, id)
+			}
+		}
+	}
+
+	ordered := slices.Clone(rules)
+	slices.SortStableFunc(ordered, func(a, b *AlertRule) int { return a.Position - b.Position })
+	for _, r := range ordered {
+		if !r.Enabled || !r.Match.Matches(event) {
+			continue
+		}
+		routing.Hits = append(routing.Hits, AlertRuleHit{RuleID: r.ID, Name: r.Name, Action: r.Action})
+		switch r.Action {
+		case AlertRuleActionSuppress:
+			routing.Suppressed = true
+			routing.ChannelIDs = nil
+			return routing
+		case AlertRuleActionRoute:
+			add(r.ChannelIDs)
+			return routing
+		default:
+			add(r.ChannelIDs)
+		}
+	}
+	routing.Default = len(routing.ChannelIDs) == 0
+	return routing
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAlertRule_Validation(t *testing.T) {
+	user, ch := uuid.New(), uuid.New()
+	tests := []struct {
+		name     string
+		match    AlertRuleMatch
+		action   AlertRuleAction
+		channels []uuid.UUID
+		ok       bool
+	}{
+		{"route", AlertRuleMatch{}, AlertRuleActionRoute, []uuid.UUID{ch}, true},
+		{"route without channels", AlertRuleMatch{}, AlertRuleActionRoute, nil, false},
+		{"suppress with channels", AlertRuleMatch{}, AlertRuleActionSuppress, []uuid.UUID{ch}, false},
+		{"continue without channels", AlertRuleMatch{}, AlertRuleActionContinue, nil, true},
+		{"unknown action", AlertRuleMatch{}, "page", []uuid.UUID{ch}, false},
+		{"unknown event", AlertRuleMatch{Events: []AlertEventKind{"opened"}}, AlertRuleActionSuppress, nil, false},
+		{"declared type", AlertRuleMatch{MonitorTypes: []MonitorType{"declared"}}, AlertRuleActionSuppress, nil, true},
+		{"bad time", AlertRuleMatch{TimeOfDay: &AlertTimeWindow{Start: "9am", End: "17:00"}}, AlertRuleActionSuppress, nil, false},
+		{"bad timezone", AlertRuleMatch{TimeOfDay: &AlertTimeWindow{Start: "09:00", End: "17:00", Timezone: "Mars/Olympus"}}, AlertRuleActionSuppress, nil, false},
+	}
+	for _, tt := range tests {
+		rule, err := NewAlertRule(user, " "+tt.name+" ", 0, tt.match, tt.action, tt.channels)
+		if !tt.ok {
+			assert.True(t, errors.Is(err, ErrInvalidAlertRule), tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.name, rule.Name)
+		assert.True(t, rule.Enabled)
+	}
+}
+
+func TestAlertTimeWindow_Contains(t *testing.T) {
+	// Nights from 22:00 to 06:00 starting Monday to Friday.
+	w := &AlertTimeWindow{Start: "22:00", End: "06:00", Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}
+	require.NoError(t, w.validate())
+	assert.Equal(t, "UTC", w.Timezone)
+
+	at := func(day, hour, minute int) time.Time {
+		// 2026-01-05 is a Monday.
+		return time.Date(2026, 1, 5+day, hour, minute, 0, 0, time.UTC)
+	}
+	assert.True(t, w.Contains(at(0, 23, 0)), "Monday night")
+	assert.True(t, w.Contains(at(1, 5, 59)), "Tuesday morning belongs to Monday night")
+	assert.False(t, w.Contains(at(1, 6, 0)), "window end is exclusive")
+	assert.False(t, w.Contains(at(0, 12, 0)), "midday")
+	assert.True(t, w.Contains(at(5, 3, 0)), "Saturday morning belongs to Friday night")
+	assert.False(t, w.Contains(at(5, 23, 0)), "Saturday night")
+	assert.False(t, w.Contains(at(0, 3, 0)), "Monday morning belongs to Sunday night")
+
+	day := &AlertTimeWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"}
+	require.NoError(t, day.validate())
+	assert.True(t, day.Contains(time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC)), "09:00 in New York")
+	assert.False(t, day.Contains(time.Date(2026, 1, 5, 13, 59, 0, 0, time.UTC)), "08:59 in New York")
+}
+
+func TestAlertRuleMatch_Matches(t *testing.T) {
+	agentID := uuid.New()
+	monitor := &Monitor{ID: uuid.New(), AgentID: agentID, Type: MonitorTypeHTTP, Metadata: map[string]string{"team": "payments", "env": "prod"}}
+	incident := NewIncident(monitor.ID)
+	incident.Severity = IncidentSeverityMajor
+	opened := NewIncidentAlertEvent(uuid.New(), incident, monitor, true)
+
+	tests := []struct {
+		name  string
+		match AlertRuleMatch
+		want  bool
+	}{
+		{"empty", AlertRuleMatch{}, true},
+		{"tags", AlertRuleMatch{Tags: map[string]string{"team": "payments"}}, true},
+		{"tag value differs", AlertRuleMatch{Tags: map[string]string{"team": "search"}}, false},
+		{"all tags", AlertRuleMatch{Tags: map[string]string{"team": "payments", "env": "staging"}}, false},
+		{"type", AlertRuleMatch{MonitorTypes: []MonitorType{MonitorTypeTCP, MonitorTypeHTTP}}, true},
+		{"agent", AlertRuleMatch{AgentIDs: []uuid.UUID{uuid.New()}}, false},
+		{"severity", AlertRuleMatch{Severities: []IncidentSeverity{IncidentSeverityMajor}}, true},
+		{"event", AlertRuleMatch{Events: []AlertEventKind{AlertEventIncidentResolved}}, false},
+		{"all conditions", AlertRuleMatch{
+			Tags:       map[string]string{"env": "prod"},
+			AgentIDs:   []uuid.UUID{agentID},
+			Severities: []IncidentSeverity{IncidentSeverityCritical, IncidentSeverityMajor},
+			Events:     []AlertEventKind{AlertEventIncidentOpened},
+		}, true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.match.Matches(opened), tt.name)
+	}
+
+	// Agent events have no monitor, so monitor conditions never match them.
+	offline := NewAgentAlertEvent(opened.UserID, agentID, false)
+	assert.True(t, (&AlertRuleMatch{AgentIDs: []uuid.UUID{agentID}}).Matches(offline))
+	assert.False(t, (&AlertRuleMatch{Tags: map[string]string{"team": "payments"}}).Matches(offline))
+}
+
+func TestRouteAlert(t *testing.T) {
+	user := uuid.New()
+	oncall, slack, email := uuid.New(), uuid.New(), uuid.New()
+	monitor := &Monitor{ID: uuid.New(), AgentID: uuid.New(), Type: MonitorTypeHTTP, Metadata: map[string]string{"team": "payments"}}
+	incident := NewIncident(monitor.ID)
+	event := NewIncidentAlertEvent(user, incident, monitor, true)
+
+	rule := func(name string, position int, match AlertRuleMatch, action AlertRuleAction, channels ...uuid.UUID) *AlertRule {
+		r, err := NewAlertRule(user, name, position, match, action, channels)
+		require.NoError(t, err)
+		return r
+	}
+	payments := AlertRuleMatch{Tags: map[string]string{"team": "payments"}}
+	search := AlertRuleMatch{Tags: map[string]string{"team": "search"}}
+
+	// No rules: every enabled channel.
+	routing := RouteAlert(nil, event)
+	assert.True(t, routing.Default)
+	assert.Empty(t, routing.Hits)
+
+	// Continue rules collect channels until a route rule stops evaluation;
+	// rules are taken by position, not slice order.
+	rules := []*AlertRule{
+		rule("never reached", 3, AlertRuleMatch{}, AlertRuleActionRoute, email),
+		rule("payments to slack", 2, payments, AlertRuleActionRoute, slack, oncall),
+		rule("search to email", 1, search, AlertRuleActionRoute, email),
+		rule("always page on-call", 0, AlertRuleMatch{}, AlertRuleActionContinue, oncall),
+	}
+	routing = RouteAlert(rules, event)
+	assert.False(t, routing.Default)
+	assert.False(t, routing.Suppressed)
+	assert.Equal(t, []uuid.UUID{oncall, slack}, routing.ChannelIDs)
+	require.Len(t, routing.Hits, 2)
+	assert.Equal(t, "always page on-call", routing.Hits[0].Name)
+	assert.Equal(t, "payments to slack", routing.Hits[1].Name)
+
+	enabled := []*AlertChannel{{ID: oncall}, {ID: slack}, {ID: email}}
+	assert.Equal(t, []*AlertChannel{enabled[0], enabled[1]}, routing.Select(enabled))
+
+	// A suppress rule drops whatever was collected.
+	quiet := rule("quiet payments", 1, payments, AlertRuleActionSuppress)
+	routing = RouteAlert(append(rules, quiet), event)
+	assert.True(t, routing.Suppressed)
+	assert.Empty(t, routing.ChannelIDs)
+	assert.Empty(t, routing.Select(enabled))
+
+	// Disabled rules are skipped.
+	quiet.Enabled = false
+	routing = RouteAlert(append(rules, quiet), event)
+	assert.False(t, routing.Suppressed)
+
+	// Matching continue rules without channels fall through to the default.
+	tagOnly := rule("note payments", 0, payments, AlertRuleActionContinue)
+	routing = RouteAlert([]*AlertRule{tagOnly}, event)
+	assert.True(t, routing.Default)
+	assert.Len(t, routing.Hits, 1)
+	assert.Equal(t, enabled, routing.Select(enabled))
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Seasonal baseline slots. Each monitor learns one baseline per hour of the
+// week (0 = Monday 00:00 UTC) plus an all-hours slot used while an hour has
+// not been seen often enough yet.
+const (
+	HoursPerWeek       = 7 * 24
+	BaselineAllHours   = HoursPerWeek
+	MinBaselineSamples = 3
+)
+
+// AnomalyAction is what the detector does when a monitor's latency deviates
+// from its baseline.
+type AnomalyAction string
+
+const (
+	// AnomalyActionIncident opens an anomaly incident and alerts.
+	AnomalyActionIncident AnomalyAction = "incident"
+	// AnomalyActionDegraded marks the monitor degraded through a silent
+	// anomaly incident; no alerts are sent.
+	AnomalyActionDegraded AnomalyAction = "degraded"
+)
+
+// IsValid checks if the action is a valid AnomalyAction.
+func (a AnomalyAction) IsValid() bool {
+	return a == AnomalyActionIncident || a == AnomalyActionDegraded
+}
+
+// HourOfWeek returns the baseline slot t falls into.
+func HourOfWeek(t time.Time) int {
+	t = t.UTC()
+	day := (int(t.Weekday()) + 6) % 7 // Monday first
+	return day*24 + t.Hour()
+}
+
+// LatencyBaseline is the learned latency of one monitor in one hour-of-week
+// slot: exponentially weighted mean and variance of the hourly p50 and p95.
+type LatencyBaseline struct {
+	MonitorID  uuid.UUID
+	HourOfWeek int
+	P50Mean    float64
+	P50Var     float64
+	P95Mean    float64
+	P95Var     float64
+	Samples    int
+	LastBucket time.Time
+}
+
+// NewLatencyBaseline creates an empty baseline for a slot.
+func NewLatencyBaseline(monitorID uuid.UUID, hourOfWeek int) *LatencyBaseline {
+	return &LatencyBaseline{MonitorID: monitorID, HourOfWeek: hourOfWeek}
+}
+
+// Observe folds one hourly observation into the baseline. alpha is the EWMA
+// weight of the new observation; the first observation seeds the mean.
+func (b *LatencyBaseline) Observe(p50, p95, alpha float64) {
+	if b.Samples == 0 {
+		b.P50Mean, b.P95Mean = p50, p95
+		b.P50Var, b.P95Var = 0, 0
+	} else {
+		b.P50Mean, b.P50Var = ewma(b.P50Mean, b.P50Var, p50, alpha)
+		b.P95Mean, b.P95Var = ewma(b.P95Mean, b.P95Var, p95, alpha)
+	}
+	b.Samples++
+}
+
+func ewma(mean, variance, x, alpha float64) (float64, float64) {
+	diff := x - mean
+	incr := alpha * diff
+	return mean + incr, (1 - alpha) * (variance + diff*incr)
+}
+
+// IsWarm returns true once the baseline has seen enough observations to be
+// trusted for detection.
+func (b *LatencyBaseline) IsWarm() bool {
+	return b.Samples >= MinBaselineSamples
+}
+
+// Sigma returns how many standard deviations p95 lies above the learned p95.
+// The deviation is floored at 10% of the mean (and 1ms) so a perfectly
+// steady history does not turn every millisecond of jitter into an anomaly.
+func (b *LatencyBaseline) Sigma(p95 float64) float64 {
+	stddev := math.Max(math.Sqrt(b.P95Var), math.Max(b.P95Mean*0.1, 1))
+	return (p95 - b.P95Mean) / stddev
+}
+
+// LatencyAnomaly records one detection: observed latency against the
+// baseline it deviated from. IncidentID is set when an incident was opened.
+type LatencyAnomaly struct {
+	ID          uuid.UUID
+	MonitorID   uuid.UUID
+	DetectedAt  time.Time
+	HourOfWeek  int
+	ObservedP50 int
+	ObservedP95 int
+	BaselineP95 int
+	Sigma       float64
+	IncidentID  *uuid.UUID
+}
+
+// NewLatencyAnomaly creates a detection record for the given observation.
+func NewLatencyAnomaly(monitorID uuid.UUID, at time.Time, observed LatencyTrendSummary, baseline *LatencyBaseline) *LatencyAnomaly {
+	return &LatencyAnomaly{
+		ID:          uuid.New(),
+		MonitorID:   monitorID,
+		DetectedAt:  at,
+		HourOfWeek:  HourOfWeek(at),
+		ObservedP50: observed.P50,
+		ObservedP95: observed.P95,
+		BaselineP95: int(math.Round(baseline.P95Mean)),
+		Sigma:       baseline.Sigma(float64(observed.P95)),
+	}
+}
+
+// NewAnomalyIncident creates a new open incident for a latency anomaly.
+func NewAnomalyIncident(monitorID uuid.UUID) *Incident {
+	incident := NewIncident(monitorID)
+	incident.Kind = IncidentKindAnomaly
+	return incident
+}
+
+// IsAnomaly returns true if the incident was opened by the latency anomaly
+// detector. The detector, not heartbeats, resolves these.
+func (i *Incident) IsAnomaly() bool {
+	return i.Kind == IncidentKindAnomaly
+}
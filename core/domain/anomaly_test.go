@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHourOfWeek(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want int
+	}{
+		{"monday midnight", time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), 0},
+		{"monday 9am", time.Date(2026, 10, 12, 9, 30, 0, 0, time.UTC), 9},
+		{"sunday 11pm", time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC), 167},
+		{"converted to UTC", time.Date(2026, 10, 13, 1, 0, 0, 0, time.FixedZone("CEST", 2*3600)), 23},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HourOfWeek(tt.t))
+		})
+	}
+}
+
+func TestLatencyBaseline_Observe(t *testing.T) {
+	b := NewLatencyBaseline(uuid.New(), 9)
+
+	b.Observe(40, 100, 0.5)
+	assert.Equal(t, 100.0, b.P95Mean, "first observation seeds the mean")
+	assert.Zero(t, b.P95Var)
+	assert.False(t, b.IsWarm())
+
+	b.Observe(40, 200, 0.5)
+	assert.Equal(t, 150.0, b.P95Mean)
+	assert.Equal(t, 2500.0, b.P95Var)
+	assert.Equal(t, 40.0, b.P50Mean)
+	assert.Zero(t, b.P50Var, "steady p50 has no variance")
+
+	b.Observe(40, 150, 0.5)
+	assert.Equal(t, 3, b.Samples)
+	assert.True(t, b.IsWarm())
+}
+
+func TestLatencyBaseline_Sigma(t *testing.T) {
+	b := &LatencyBaseline{P95Mean: 100, P95Var: 400, Samples: 5}
+	assert.Equal(t, 3.0, b.Sigma(160))
+	assert.Negative(t, b.Sigma(80), "faster than baseline")
+
+	steady := &LatencyBaseline{P95Mean: 200, Samples: 5}
+	assert.Equal(t, 2.0, steady.Sigma(240), "steady history falls back to 10% of the mean")
+
+	fast := &LatencyBaseline{P95Mean: 2, Samples: 5}
+	assert.InDelta(t, 3.0, fast.Sigma(5), 1e-9, "near-zero latency falls back to 1ms")
+}
+
+func TestNewAnomalyIncident(t *testing.T) {
+	incident := NewAnomalyIncident(uuid.New())
+
+	assert.True(t, incident.Kind.IsValid())
+	assert.True(t, incident.IsAnomaly())
+	assert.True(t, incident.IsDegraded(), "anomalies count as degraded performance")
+	assert.Equal(t, MonitorStatusDegraded, incident.Kind.MonitorStatus())
+	assert.False(t, NewDegradedIncident(uuid.New()).IsAnomaly())
+}
@@ -0,0 +1,327 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AssertionType is the part of an HTTP response an assertion checks.
+type AssertionType string
+
+const (
+	AssertionStatusCode   AssertionType = "status_code"
+	AssertionHeader       AssertionType = "header"
+	AssertionBody         AssertionType = "body"
+	AssertionJSONPath     AssertionType = "json_path"
+	AssertionResponseSize AssertionType = "response_size"
+)
+
+// AssertionOperator compares the reported value with the assertion target.
+type AssertionOperator string
+
+const (
+	OpEquals      AssertionOperator = "equals"
+	OpNotEquals   AssertionOperator = "not_equals"
+	OpContains    AssertionOperator = "contains"
+	OpNotContains AssertionOperator = "not_contains"
+	OpMatches     AssertionOperator = "matches"
+	OpLessThan    AssertionOperator = "less_than"
+	OpGreaterThan AssertionOperator = "greater_than"
+	OpBetween     AssertionOperator = "between"
+	OpExists      AssertionOperator = "exists"
+)
+
+// Keys HTTP check results are reported under in HeartbeatPayload.Metadata.
+// Assertions are evaluated against these on the hub.
+const (
+	ResultStatusCode   = "status_code"
+	ResultResponseSize = "response_size"
+	ResultBody         = "body"
+	ResultHeaderPrefix = "header."
+)
+
+// MaxAssertions caps how many assertions a single monitor may carry.
+const MaxAssertions = 20
+
+// ErrInvalidAssertion is wrapped by every assertion validation error.
+var ErrInvalidAssertion = errors.New("invalid assertion")
+
+// Assertion is one check evaluated on the hub against an HTTP monitor's
+// reported response. Property names the header or JSON path for header and
+// json_path assertions. Target is the expected value; between takes an
+// inclusive "min-max" range.
+type Assertion struct {
+	Type     AssertionType     `json:"type"`
+	Property string            `json:"property,omitempty"`
+	Operator AssertionOperator `json:"operator"`
+	Target   string            `json:"target,omitempty"`
+}
+
+// allowedOperators lists the operators each assertion type accepts.
+var allowedOperators = map[AssertionType][]AssertionOperator{
+	AssertionStatusCode:   {OpEquals, OpNotEquals, OpLessThan, OpGreaterThan, OpBetween},
+	AssertionHeader:       {OpEquals, OpNotEquals, OpContains, OpNotContains, OpMatches, OpExists},
+	AssertionBody:         {OpContains, OpNotContains, OpMatches},
+	AssertionJSONPath:     {OpEquals, OpNotEquals, OpContains, OpMatches, OpLessThan, OpGreaterThan, OpExists},
+	AssertionResponseSize: {OpEquals, OpLessThan, OpGreaterThan, OpBetween},
+}
+
+// String describes the assertion, e.g. `header "Content-Type" contains "json"`.
+func (a Assertion) String() string {
+	var b strings.Builder
+	b.WriteString(string(a.Type))
+	if a.Property != "" {
+		fmt.Fprintf(&b, " %q", a.Property)
+	}
+	b.WriteString(" " + string(a.Operator))
+	if a.Operator != OpExists {
+		fmt.Fprintf(&b, " %q", a.Target)
+	}
+	return b.String()
+}
+
+// Validate checks the assertion's type, operator and target syntax.
+func (a Assertion) Validate() error {
+	ops, ok := allowedOperators[a.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidAssertion, a.Type)
+	}
+	allowed := false
+	for _, op := range ops {
+		if op == a.Operator {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: operator %q is not supported for %s", ErrInvalidAssertion, a.Operator, a.Type)
+	}
+
+	if (a.Type == AssertionHeader || a.Type == AssertionJSONPath) && strings.TrimSpace(a.Property) == "" {
+		return fmt.Errorf("%w: %s requires a property", ErrInvalidAssertion, a.Type)
+	}
+	if a.Type == AssertionJSONPath {
+		if _, err := parseJSONPath(a.Property); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+		}
+	}
+
+	switch a.Operator {
+	case OpExists:
+		return nil
+	case OpMatches:
+		if _, err := regexp.Compile(a.Target); err != nil {
+			return fmt.Errorf("%w: bad regex %q: %v", ErrInvalidAssertion, a.Target, err)
+		}
+	case OpBetween:
+		if _, _, err := parseRange(a.Target); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+		}
+	case OpLessThan, OpGreaterThan:
+		if _, err := strconv.ParseFloat(a.Target, 64); err != nil {
+			return fmt.Errorf("%w: %s needs a number, got %q", ErrInvalidAssertion, a.Operator, a.Target)
+		}
+	}
+	if (a.Type == AssertionStatusCode || a.Type == AssertionResponseSize) && (a.Operator == OpEquals || a.Operator == OpNotEquals) {
+		if _, err := strconv.Atoi(a.Target); err != nil {
+			return fmt.Errorf("%w: %s needs an integer, got %q", ErrInvalidAssertion, a.Type, a.Target)
+		}
+	}
+	return nil
+}
+
+// ValidateAssertions validates a monitor's assertion list.
+func ValidateAssertions(assertions []Assertion) error {
+	if len(assertions) > MaxAssertions {
+		return fmt.Errorf("%w: at most %d assertions are allowed", ErrInvalidAssertion, MaxAssertions)
+	}
+	for i, a := range assertions {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("assertions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// FailedAssertion returns a message naming the first of the monitor's
+// assertions that the reported result does not satisfy, or "" when all pass.
+func (m *Monitor) FailedAssertion(result map[string]string) string {
+	return FirstFailedAssertion(m.Assertions, result)
+}
+
+// FirstFailedAssertion returns a message naming the first assertion that the
+// reported result does not satisfy, or "" when all pass.
+func FirstFailedAssertion(assertions []Assertion, result map[string]string) string {
+	for _, a := range assertions {
+		if ok, got := a.Evaluate(result); !ok {
+			return fmt.Sprintf("assertion failed: %s (got %s)", a, got)
+		}
+	}
+	return ""
+}
+
+// Evaluate checks the assertion against a reported HTTP result. It returns
+// whether it passed and a short description of the value it saw.
+func (a Assertion) Evaluate(result map[string]string) (bool, string) {
+	var (
+		value string
+		found bool
+	)
+	switch a.Type {
+	case AssertionStatusCode:
+		value, found = result[ResultStatusCode]
+	case AssertionResponseSize:
+		value, found = result[ResultResponseSize]
+	case AssertionBody:
+		value, found = result[ResultBody]
+	case AssertionHeader:
+		value, found = result[ResultHeaderPrefix+strings.ToLower(a.Property)]
+	case AssertionJSONPath:
+		body, ok := result[ResultBody]
+		if !ok {
+			return false, "no body reported"
+		}
+		value, found = lookupJSONPath(body, a.Property)
+	}
+
+	if a.Operator == OpExists {
+		if !found {
+			return false, "missing"
+		}
+		return true, strconv.Quote(value)
+	}
+	if !found {
+		return false, "no value reported"
+	}
+	return compare(a.Operator, value, a.Target), quoteShort(value)
+}
+
+func compare(op AssertionOperator, value, target string) bool {
+	switch op {
+	case OpEquals:
+		return value == target
+	case OpNotEquals:
+		return value != target
+	case OpContains:
+		return strings.Contains(value, target)
+	case OpNotContains:
+		return !strings.Contains(value, target)
+	case OpMatches:
+		re, err := regexp.Compile(target)
+		return err == nil && re.MatchString(value)
+	case OpLessThan, OpGreaterThan:
+		v, err1 := strconv.ParseFloat(value, 64)
+		t, err2 := strconv.ParseFloat(target, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if op == OpLessThan {
+			return v < t
+		}
+		return v > t
+	case OpBetween:
+		v, err := strconv.ParseFloat(value, 64)
+		lo, hi, rangeErr := parseRange(target)
+		return err == nil && rangeErr == nil && v >= lo && v <= hi
+	}
+	return false
+}
+
+// parseRange parses an inclusive "min-max" range.
+func parseRange(s string) (float64, float64, error) {
+	lo, hi, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("range must look like min-max, got %q", s)
+	}
+	low, err1 := strconv.ParseFloat(strings.TrimSpace(lo), 64)
+	high, err2 := strconv.ParseFloat(strings.TrimSpace(hi), 64)
+	if err1 != nil || err2 != nil || low > high {
+		return 0, 0, fmt.Errorf("range must look like min-max, got %q", s)
+	}
+	return low, high, nil
+}
+
+// quoteShort quotes a reported value, clipped so long bodies stay readable in
+// error messages.
+func quoteShort(v string) string {
+	const limit = 80
+	if len(v) > limit {
+		return strconv.Quote(v[:limit]) + "..."
+	}
+	return strconv.Quote(v)
+}
+
+// parseJSONPath splits a path like "$.data.items[0].status" into keys and
+// array indexes. The leading "$." is optional.
+func parseJSONPath(path string) ([]any, error) {
+	p := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if p == "" {
+		return nil, fmt.Errorf("empty JSON path %q", path)
+	}
+	var steps []any
+	for _, part := range strings.Split(p, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name != "" {
+			steps = append(steps, name)
+		}
+		for rest != "" {
+			idx, after, ok := strings.Cut(rest, "]")
+			n, err := strconv.Atoi(idx)
+			if !ok || err != nil || n < 0 {
+				return nil, fmt.Errorf("bad index in JSON path %q", path)
+			}
+			steps = append(steps, n)
+			rest = strings.TrimPrefix(after, "[")
+			if after != "" && !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("bad index in JSON path %q", path)
+			}
+		}
+		if name == "" && !strings.Contains(part, "[") {
+			return nil, fmt.Errorf("empty segment in JSON path %q", path)
+		}
+	}
+	return steps, nil
+}
+
+// lookupJSONPath resolves a path in a JSON document. Strings are returned
+// as-is; other values in their JSON encoding.
+func lookupJSONPath(body, path string) (string, bool) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return "", false
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return "", false
+	}
+	for _, step := range steps {
+		switch s := step.(type) {
+		case string:
+			obj, ok := doc.(map[string]any)
+			if !ok {
+				return "", false
+			}
+			if doc, ok = obj[s]; !ok {
+				return "", false
+			}
+		case int:
+			arr, ok := doc.([]any)
+			if !ok || s >= len(arr) {
+				return "", false
+			}
+			doc = arr[s]
+		}
+	}
+	if str, ok := doc.(string); ok {
+		return str, true
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
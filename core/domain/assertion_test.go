@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertion_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion Assertion
+		wantErr   bool
+	}{
+		{"status range", Assertion{Type: AssertionStatusCode, Operator: OpBetween, Target: "200-299"}, false},
+		{"status equals", Assertion{Type: AssertionStatusCode, Operator: OpEquals, Target: "204"}, false},
+		{"header exists", Assertion{Type: AssertionHeader, Property: "X-Request-Id", Operator: OpExists}, false},
+		{"body regex", Assertion{Type: AssertionBody, Operator: OpMatches, Target: `"status":\s*"ok"`}, false},
+		{"json path", Assertion{Type: AssertionJSONPath, Property: "$.data.items[0].state", Operator: OpEquals, Target: "ready"}, false},
+		{"size limit", Assertion{Type: AssertionResponseSize, Operator: OpLessThan, Target: "1048576"}, false},
+		{"unknown type", Assertion{Type: "cookie", Operator: OpEquals, Target: "x"}, true},
+		{"operator not allowed", Assertion{Type: AssertionBody, Operator: OpLessThan, Target: "5"}, true},
+		{"header without name", Assertion{Type: AssertionHeader, Operator: OpExists}, true},
+		{"bad regex", Assertion{Type: AssertionBody, Operator: OpMatches, Target: "("}, true},
+		{"bad range", Assertion{Type: AssertionStatusCode, Operator: OpBetween, Target: "299-200"}, true},
+		{"non-numeric status", Assertion{Type: AssertionStatusCode, Operator: OpEquals, Target: "ok"}, true},
+		{"bad json path", Assertion{Type: AssertionJSONPath, Property: "$.items[x]", Operator: OpExists}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.assertion.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAssertion)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAssertions_Limit(t *testing.T) {
+	list := make([]Assertion, MaxAssertions+1)
+	for i := range list {
+		list[i] = Assertion{Type: AssertionStatusCode, Operator: OpEquals, Target: "200"}
+	}
+	assert.ErrorIs(t, ValidateAssertions(list), ErrInvalidAssertion)
+	assert.NoError(t, ValidateAssertions(list[:MaxAssertions]))
+}
+
+func TestAssertion_Evaluate(t *testing.T) {
+	result := map[string]string{
+		ResultStatusCode:                    "201",
+		ResultResponseSize:                  "512",
+		ResultBody:                          `{"status":"ok","items":[{"id":7,"tags":["a"]}]}`,
+		ResultHeaderPrefix + "content-type": "application/json; charset=utf-8",
+	}
+	tests := []struct {
+		name      string
+		assertion Assertion
+		want      bool
+	}{
+		{"status in range", Assertion{Type: AssertionStatusCode, Operator: OpBetween, Target: "200-299"}, true},
+		{"status mismatch", Assertion{Type: AssertionStatusCode, Operator: OpEquals, Target: "200"}, false},
+		{"header is case-insensitive", Assertion{Type: AssertionHeader, Property: "Content-Type", Operator: OpContains, Target: "json"}, true},
+		{"missing header", Assertion{Type: AssertionHeader, Property: "X-Cache", Operator: OpExists}, false},
+		{"body keyword", Assertion{Type: AssertionBody, Operator: OpContains, Target: `"ok"`}, true},
+		{"body forbidden keyword", Assertion{Type: AssertionBody, Operator: OpNotContains, Target: "error"}, true},
+		{"body regex", Assertion{Type: AssertionBody, Operator: OpMatches, Target: `"id":\d+`}, true},
+		{"json string", Assertion{Type: AssertionJSONPath, Property: "$.status", Operator: OpEquals, Target: "ok"}, true},
+		{"json number", Assertion{Type: AssertionJSONPath, Property: "items[0].id", Operator: OpGreaterThan, Target: "5"}, true},
+		{"json array", Assertion{Type: AssertionJSONPath, Property: "$.items[0].tags", Operator: OpEquals, Target: `["a"]`}, true},
+		{"json missing", Assertion{Type: AssertionJSONPath, Property: "$.items[3].id", Operator: OpExists}, false},
+		{"size under limit", Assertion{Type: AssertionResponseSize, Operator: OpLessThan, Target: "1024"}, true},
+		{"size over limit", Assertion{Type: AssertionResponseSize, Operator: OpLessThan, Target: "100"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := tt.assertion.Evaluate(result)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMonitor_FailedAssertion(t *testing.T) {
+	monitor := NewMonitor(uuid.New(), "api", MonitorTypeHTTP, "https://example.com")
+	monitor.Assertions = []Assertion{
+		{Type: AssertionStatusCode, Operator: OpBetween, Target: "200-299"},
+		{Type: AssertionBody, Operator: OpContains, Target: "healthy"},
+	}
+
+	assert.Empty(t, monitor.FailedAssertion(map[string]string{ResultStatusCode: "200", ResultBody: "healthy"}))
+	assert.Equal(t, `assertion failed: body contains "healthy" (got "degraded")`,
+		monitor.FailedAssertion(map[string]string{ResultStatusCode: "200", ResultBody: "degraded"}))
+}
@@ -21,6 +21,9 @@ const (
 	AuditMonitorDeleted    AuditAction = "monitor_deleted"
 	AuditIncidentAcked     AuditAction = "incident_acknowledged"
 	AuditIncidentResolved  AuditAction = "incident_resolved"
+	AuditIncidentUpdated   AuditAction = "incident_updated"
+	AuditIncidentDeclared  AuditAction = "incident_declared"
+	AuditIncidentOwnerChanged AuditAction = "incident_owner_changed"
 	AuditSettingsChanged         AuditAction = "settings_changed"
 	AuditPasswordResetByAdmin    AuditAction = "password_reset_by_admin"
 	AuditPasswordChanged         AuditAction = "password_changed"
@@ -33,12 +36,46 @@ const (
 	AuditLogout                AuditAction = "logout"
 	AuditChannelCreated        AuditAction = "channel_created"
 	AuditChannelDeleted        AuditAction = "channel_deleted"
+	AuditChannelUpdated        AuditAction = "channel_updated"
 
 	AuditMaintenanceWindowCreated    AuditAction = "maintenance_window_created"
 	AuditMaintenanceWindowUpdated    AuditAction = "maintenance_window_updated"
 	AuditMaintenanceWindowDeleted    AuditAction = "maintenance_window_deleted"
 	AuditMaintenanceAlertsSuppressed AuditAction = "maintenance_alerts_suppressed"
 	AuditMaintenanceWindowExpired    AuditAction = "maintenance_window_expired"
+
+	AuditDependencyCreated AuditAction = "dependency_created"
+	AuditDependencyDeleted AuditAction = "dependency_deleted"
+
+	AuditConfigApplied AuditAction = "config_applied"
+
+	AuditIncidentNoteAdded       AuditAction = "incident_note_added"
+	AuditIncidentNoteUpdated     AuditAction = "incident_note_updated"
+	AuditIncidentNoteDeleted     AuditAction = "incident_note_deleted"
+	AuditIncidentPostmortemSaved AuditAction = "incident_postmortem_saved"
+
+	AuditEscalationPolicyCreated AuditAction = "escalation_policy_created"
+	AuditEscalationPolicyUpdated AuditAction = "escalation_policy_updated"
+	AuditEscalationPolicyDeleted AuditAction = "escalation_policy_deleted"
+
+	AuditOnCallScheduleCreated AuditAction = "oncall_schedule_created"
+	AuditOnCallScheduleUpdated AuditAction = "oncall_schedule_updated"
+	AuditOnCallScheduleDeleted AuditAction = "oncall_schedule_deleted"
+	AuditOnCallOverrideCreated AuditAction = "oncall_override_created"
+	AuditOnCallOverrideDeleted AuditAction = "oncall_override_deleted"
+
+	AuditAlertGroupMerged AuditAction = "alert_group_merged"
+	AuditAlertGroupSplit  AuditAction = "alert_group_split"
+
+	AuditAlertRuleCreated AuditAction = "alert_rule_created"
+	AuditAlertRuleUpdated AuditAction = "alert_rule_updated"
+	AuditAlertRuleDeleted AuditAction = "alert_rule_deleted"
+
+	AuditMessageTemplateCreated AuditAction = "message_template_created"
+	AuditMessageTemplateUpdated AuditAction = "message_template_updated"
+	AuditMessageTemplateDeleted AuditAction = "message_template_deleted"
+
+	AuditNotificationRedelivered AuditAction = "notification_redelivered"
 )
 
 // AuditQueryOpts defines filters for paginated audit log queries.
@@ -65,6 +65,7 @@ func TestAuditAction_Constants(t *testing.T) {
 		{"monitor deleted", AuditMonitorDeleted, "monitor_deleted"},
 		{"incident acknowledged", AuditIncidentAcked, "incident_acknowledged"},
 		{"incident resolved", AuditIncidentResolved, "incident_resolved"},
+		{"incident updated", AuditIncidentUpdated, "incident_updated"},
 		{"settings changed", AuditSettingsChanged, "settings_changed"},
 	}
 
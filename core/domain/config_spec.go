@@ -0,0 +1,574 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidConfig is returned when a declarative config spec cannot be applied.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// RedactedSecret replaces secret alert channel settings in exported specs.
+// Applying a spec that still carries it keeps the stored value.
+const RedactedSecret = "••••••"
+
+// ConfigSpec is the declarative description of a user's monitoring setup,
+// exchanged as YAML or JSON by config export, plan and apply. Resources are
+// matched by name (status pages by slug); IDs never appear in a spec.
+//
+// A section that is omitted is left alone; a section that is present, even
+// empty, is reconciled: resources missing from it are deleted. Agents are
+// the exception and are only ever created, never deleted.
+type ConfigSpec struct {
+	Agents             []AgentSpec             `json:"agents"`
+	Monitors           []MonitorSpec           `json:"monitors"`
+	AlertChannels      []AlertChannelSpec      `json:"alert_channels"`
+	StatusPages        []StatusPageSpec        `json:"status_pages"`
+	MaintenanceWindows []MaintenanceWindowSpec `json:"maintenance_windows"`
+}
+
+// AgentSpec declares an agent. Hub only applies when the agent is created.
+type AgentSpec struct {
+	Name string `json:"name"`
+	Hub  bool   `json:"hub,omitempty"`
+}
+
+// MonitorSpec declares a monitor. Zero values fall back to the monitor
+// defaults; Tags holds the monitor's metadata. Transaction monitors declare
+// Steps instead of a target.
+type MonitorSpec struct {
+	Name              string             `json:"name"`
+	Agent             string             `json:"agent"`
+	Type              MonitorType        `json:"type"`
+	Target            string             `json:"target,omitempty"`
+	Enabled           *bool              `json:"enabled,omitempty"`
+	IntervalSeconds   int                `json:"interval_seconds,omitempty"`
+	TimeoutSeconds    int                `json:"timeout_seconds,omitempty"`
+	FailureThreshold  int                `json:"failure_threshold,omitempty"`
+	RecoveryThreshold int                `json:"recovery_threshold,omitempty"`
+	Tags              map[string]string  `json:"tags,omitempty"`
+	SLATargetPercent  *float64           `json:"sla_target_percent,omitempty"`
+	Degraded          *DegradedSpec      `json:"degraded,omitempty"`
+	FlapDetection     *FlapDetectionSpec `json:"flap_detection,omitempty"`
+	Locations         *LocationsSpec     `json:"locations,omitempty"`
+	PushGraceSeconds  *int               `json:"push_grace_seconds,omitempty"`
+	Assertions        []Assertion        `json:"assertions,omitempty"`
+	Steps             []TransactionStep  `json:"steps,omitempty"`
+	Severity          IncidentSeverity   `json:"severity,omitempty"`
+}
+
+// DegradedSpec declares a monitor's degraded rules. A zero latency_ms or
+// failure_percent leaves that rule off.
+type DegradedSpec struct {
+	LatencyMs      int `json:"latency_ms,omitempty"`
+	LatencyChecks  int `json:"latency_checks,omitempty"`
+	FailurePercent int `json:"failure_percent,omitempty"`
+	Window         int `json:"window,omitempty"`
+}
+
+// FlapDetectionSpec declares a monitor's flap detector.
+type FlapDetectionSpec struct {
+	Window           int `json:"window"`
+	ThresholdPercent int `json:"threshold_percent,omitempty"`
+}
+
+// LocationsSpec declares the agents, by name, that probe a monitor.
+type LocationsSpec struct {
+	Agents []string `json:"agents"`
+	Quorum int      `json:"quorum,omitempty"`
+}
+
+// AlertChannelSpec declares an alert channel.
+type AlertChannelSpec struct {
+	Name    string            `json:"name"`
+	Type    AlertChannelType  `json:"type"`
+	Enabled *bool             `json:"enabled,omitempty"`
+	Config  map[string]string `json:"config,omitempty"`
+}
+
+// StatusPageSpec declares a status page and the monitors, by name, it shows.
+type StatusPageSpec struct {
+	Slug        string   `json:"slug"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Public      *bool    `json:"public,omitempty"`
+	Monitors    []string `json:"monitors,omitempty"`
+}
+
+// MaintenanceWindowSpec declares a maintenance window for an agent.
+type MaintenanceWindowSpec struct {
+	Name       string    `json:"name"`
+	Agent      string    `json:"agent"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	Recurrence string    `json:"recurrence,omitempty"`
+}
+
+// ConfigAction is what applying a spec does to one resource.
+type ConfigAction string
+
+const (
+	ConfigActionCreate ConfigAction = "create"
+	ConfigActionUpdate ConfigAction = "update"
+	ConfigActionDelete ConfigAction = "delete"
+)
+
+// ConfigResource names the kind of resource a change applies to.
+type ConfigResource string
+
+const (
+	ConfigResourceAgent             ConfigResource = "agent"
+	ConfigResourceMonitor           ConfigResource = "monitor"
+	ConfigResourceAlertChannel      ConfigResource = "alert_channel"
+	ConfigResourceStatusPage        ConfigResource = "status_page"
+	ConfigResourceMaintenanceWindow ConfigResource = "maintenance_window"
+)
+
+// ConfigChange is one step of a plan. Fields lists the spec fields an
+// update changes.
+type ConfigChange struct {
+	Action   ConfigAction   `json:"action"`
+	Resource ConfigResource `json:"resource"`
+	Name     string         `json:"name"`
+	Fields   []string       `json:"fields,omitempty"`
+}
+
+// ConfigPlan is the ordered set of changes that brings the stored setup in
+// line with a spec.
+type ConfigPlan struct {
+	Changes []ConfigChange `json:"changes"`
+}
+
+// Validate checks that every resource is named and that names are unique
+// within their section.
+func (s *ConfigSpec) Validate() error {
+	names := make(map[ConfigResource][]string)
+	for _, a := range s.Agents {
+		names[ConfigResourceAgent] = append(names[ConfigResourceAgent], a.Name)
+	}
+	for _, m := range s.Monitors {
+		names[ConfigResourceMonitor] = append(names[ConfigResourceMonitor], m.Name)
+	}
+	for _, ch := range s.AlertChannels {
+		names[ConfigResourceAlertChannel] = append(names[ConfigResourceAlertChannel], ch.Name)
+	}
+	for _, p := range s.StatusPages {
+		names[ConfigResourceStatusPage] = append(names[ConfigResourceStatusPage], p.Slug)
+	}
+	for _, w := range s.MaintenanceWindows {
+		names[ConfigResourceMaintenanceWindow] = append(names[ConfigResourceMaintenanceWindow], w.Name)
+	}
+
+	for resource, list := range names {
+		seen := make(map[string]bool, len(list))
+		for _, name := range list {
+			if name == "" {
+				return fmt.Errorf("%w: every %s needs a name", ErrInvalidConfig, resource)
+			}
+			if seen[name] {
+				return fmt.Errorf("%w: %s %q is declared more than once", ErrInvalidConfig, resource, name)
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
+
+// MonitorSpecFrom describes a stored monitor as a spec. agentNames maps agent
+// IDs to names.
+func MonitorSpecFrom(m *Monitor, agentNames map[uuid.UUID]string) MonitorSpec {
+	spec := MonitorSpec{
+		Name:              m.Name,
+		Agent:             agentNames[m.AgentID],
+		Type:              m.Type,
+		Target:            m.Target,
+		IntervalSeconds:   m.IntervalSeconds,
+		TimeoutSeconds:    m.TimeoutSeconds,
+		FailureThreshold:  m.FailureThreshold,
+		RecoveryThreshold: m.RecoveryThreshold,
+		SLATargetPercent:  m.SLATargetPercent,
+		Assertions:        m.Assertions,
+	}
+	if len(m.Metadata) > 0 {
+		spec.Tags = m.Metadata
+	}
+	if m.Severity != DefaultIncidentSeverity {
+		spec.Severity = m.Severity
+	}
+	if !m.Enabled {
+		enabled := false
+		spec.Enabled = &enabled
+	}
+	if m.DegradedLatencyMs != nil || m.DegradedFailurePercent != nil {
+		spec.Degraded = &DegradedSpec{}
+		if m.DegradedLatencyMs != nil {
+			spec.Degraded.LatencyMs = *m.DegradedLatencyMs
+			spec.Degraded.LatencyChecks = m.DegradedLatencyChecks
+		}
+		if m.DegradedFailurePercent != nil {
+			spec.Degraded.FailurePercent = *m.DegradedFailurePercent
+			spec.Degraded.Window = m.DegradedWindow
+		}
+	}
+	if m.FlapWindow > 0 {
+		spec.FlapDetection = &FlapDetectionSpec{Window: m.FlapWindow, ThresholdPercent: m.FlapThresholdPercent}
+	}
+	if m.IsMultiLocation() {
+		spec.Locations = &LocationsSpec{Quorum: m.Quorum}
+		for _, id := range m.LocationAgentIDs {
+			spec.Locations.Agents = append(spec.Locations.Agents, agentNames[id])
+		}
+	}
+	if m.IsPush() {
+		grace := m.PushGraceSeconds
+		spec.PushGraceSeconds = &grace
+	}
+	if m.Transaction != nil {
+		// The target follows the first step.
+		spec.Target = ""
+		spec.Steps = m.Transaction.Steps
+	}
+	return spec
+}
+
+// ApplyTo sets every setting the spec declares on m, resetting the ones it
+// leaves out to their defaults. agents maps agent names to agents. The
+// monitor's type is not changed; a spec of another type is rejected.
+func (s MonitorSpec) ApplyTo(m *Monitor, agents map[string]*Agent) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: monitor %q: %s", ErrInvalidConfig, s.Name, fmt.Sprintf(format, args...))
+	}
+
+	if !s.Type.IsValid() {
+		return invalid("invalid type %q", s.Type)
+	}
+	if s.Type != m.Type {
+		return invalid("type cannot change from %s to %s", m.Type, s.Type)
+	}
+	agent := agents[s.Agent]
+	if agent == nil {
+		return invalid("unknown agent %q", s.Agent)
+	}
+	if agent.Hub && !s.Type.IsHubProbeable() && s.Type != MonitorTypePush {
+		return invalid("hub agents only run http, tcp, dns, tls, ping and transaction monitors")
+	}
+	if s.Target == "" && s.Type != MonitorTypePush && s.Type != MonitorTypeTransaction {
+		return invalid("target is required")
+	}
+
+	m.Name = s.Name
+	m.AgentID = agent.ID
+	m.Target = s.Target
+	m.Enabled = s.Enabled == nil || *s.Enabled
+	m.Metadata = make(map[string]string, len(s.Tags))
+	for k, v := range s.Tags {
+		m.Metadata[k] = v
+	}
+
+	interval := s.IntervalSeconds
+	if interval == 0 {
+		interval = DefaultIntervalSeconds
+	}
+	if m.IsPush() {
+		grace := DefaultPushGraceSeconds
+		if s.PushGraceSeconds != nil {
+			grace = *s.PushGraceSeconds
+		}
+		if !m.SetPushSchedule(interval, grace) {
+			return invalid("interval_seconds must be between %d and %d and push_grace_seconds between 0 and %d", MinIntervalSeconds, MaxPushIntervalSeconds, MaxPushGraceSeconds)
+		}
+	} else {
+		if s.PushGraceSeconds != nil {
+			return invalid("push_grace_seconds is only supported on push monitors")
+		}
+		if !m.SetInterval(interval) {
+			return invalid("interval_seconds must be between %d and %d", MinIntervalSeconds, MaxIntervalSeconds)
+		}
+	}
+
+	timeout := s.TimeoutSeconds
+	if timeout == 0 {
+		timeout = DefaultTimeoutSeconds
+	}
+	if !m.SetTimeout(timeout) {
+		return invalid("timeout_seconds must be between %d and %d", MinTimeoutSeconds, MaxTimeoutSeconds)
+	}
+
+	threshold := s.FailureThreshold
+	if threshold == 0 {
+		threshold = DefaultFailureThreshold
+		if m.IsPush() {
+			threshold = 1
+		}
+	}
+	if threshold < MinFailureThreshold || threshold > MaxFailureThreshold {
+		return invalid("failure_threshold must be between %d and %d", MinFailureThreshold, MaxFailureThreshold)
+	}
+	m.FailureThreshold = threshold
+
+	recovery := s.RecoveryThreshold
+	if recovery == 0 {
+		recovery = DefaultRecoveryThreshold
+	}
+	if !m.SetRecoveryThreshold(recovery) {
+		return invalid("recovery_threshold must be between %d and %d", MinRecoveryThreshold, MaxRecoveryThreshold)
+	}
+
+	if s.SLATargetPercent != nil && (*s.SLATargetPercent < 0 || *s.SLATargetPercent > 100) {
+		return invalid("sla_target_percent must be between 0 and 100")
+	}
+	m.SLATargetPercent = s.SLATargetPercent
+
+	severity := s.Severity
+	if severity == "" {
+		severity = DefaultIncidentSeverity
+	}
+	if !severity.IsValid() {
+		return invalid("%v", ErrInvalidIncidentSeverity)
+	}
+	m.Severity = severity
+
+	if err := s.applyDegraded(m); err != nil {
+		return invalid("%v", err)
+	}
+
+	flapWindow, flapPercent := 0, DefaultFlapThresholdPercent
+	if s.FlapDetection != nil {
+		flapWindow = s.FlapDetection.Window
+		if s.FlapDetection.ThresholdPercent != 0 {
+			flapPercent = s.FlapDetection.ThresholdPercent
+		}
+	}
+	if !m.SetFlapDetection(flapWindow, flapPercent) {
+		return invalid("flap_detection.window must be 0 or between %d and %d and flap_detection.threshold_percent between 1 and 100", MinFlapWindow, MaxFlapWindow)
+	}
+
+	if err := s.applyLocations(m, agents); err != nil {
+		return invalid("%v", err)
+	}
+
+	if len(s.Assertions) > 0 {
+		if s.Type != MonitorTypeHTTP {
+			return invalid("assertions are only supported on http monitors")
+		}
+		if err := ValidateAssertions(s.Assertions); err != nil {
+			return invalid("%v", err)
+		}
+		m.Assertions = s.Assertions
+	} else {
+		m.Assertions = nil
+	}
+
+	if s.Type != MonitorTypeTransaction {
+		if len(s.Steps) > 0 {
+			return invalid("steps are only supported on transaction monitors")
+		}
+		return nil
+	}
+	if err := ValidateTransactionSteps(s.Steps); err != nil {
+		return invalid("%v", err)
+	}
+	m.SetTransaction(s.Steps)
+	m.Target = s.Steps[0].URL
+	return nil
+}
+
+func (s MonitorSpec) applyDegraded(m *Monitor) error {
+	var d DegradedSpec
+	if s.Degraded != nil {
+		d = *s.Degraded
+	}
+	checks, window := d.LatencyChecks, d.Window
+	if checks == 0 {
+		checks = DefaultDegradedLatencyChecks
+	}
+	if window == 0 {
+		window = DefaultDegradedWindow
+	}
+	m.DegradedLatencyChecks, m.DegradedWindow = DefaultDegradedLatencyChecks, DefaultDegradedWindow
+	if !m.SetDegradedLatency(d.LatencyMs, checks) {
+		return fmt.Errorf("degraded.latency_ms must be positive and degraded.latency_checks between %d and %d", MinDegradedLatencyChecks, MaxDegradedLatencyChecks)
+	}
+	if !m.SetDegradedFailureRate(d.FailurePercent, window) {
+		return fmt.Errorf("degraded.failure_percent must be between 1 and 100 and degraded.window between %d and %d", MinDegradedWindow, MaxDegradedWindow)
+	}
+	return nil
+}
+
+func (s MonitorSpec) applyLocations(m *Monitor, agents map[string]*Agent) error {
+	if s.Locations == nil {
+		m.SetLocations(nil, 0)
+		return nil
+	}
+	if m.IsPush() {
+		return errors.New("push monitors cannot have locations")
+	}
+	ids := make([]uuid.UUID, 0, len(s.Locations.Agents))
+	for _, name := range s.Locations.Agents {
+		agent := agents[name]
+		if agent == nil {
+			return fmt.Errorf("unknown agent %q in locations", name)
+		}
+		if agent.Hub && !m.Type.IsHubProbeable() {
+			return errors.New("hub agents only run http, tcp, dns, tls, ping and transaction monitors")
+		}
+		ids = append(ids, agent.ID)
+	}
+	if !m.SetLocations(ids, s.Locations.Quorum) {
+		return fmt.Errorf("locations allow at most %d agents and quorum must be between 0 and the number of locations", MaxMonitorLocations)
+	}
+	return nil
+}
+
+// AlertChannelSpecFrom describes a stored alert channel as a spec, with its
+// password redacted.
+func AlertChannelSpecFrom(ch *AlertChannel) AlertChannelSpec {
+	spec := AlertChannelSpec{Name: ch.Name, Type: ch.Type}
+	if !ch.Enabled {
+		enabled := false
+		spec.Enabled = &enabled
+	}
+	if len(ch.Config) > 0 {
+		spec.Config = make(map[string]string, len(ch.Config))
+		for k, v := range ch.Config {
+			if k == "password" && v != "" {
+				v = RedactedSecret
+			}
+			spec.Config[k] = v
+		}
+	}
+	return spec
+}
+
+// ApplyTo sets the declared type, state and settings on ch. A redacted
+// password keeps the one already stored.
+func (s AlertChannelSpec) ApplyTo(ch *AlertChannel) error {
+	config := make(map[string]string, len(s.Config))
+	for k, v := range s.Config {
+		if v == RedactedSecret {
+			stored, ok := ch.Config[k]
+			if !ok {
+				return fmt.Errorf("%w: alert channel %q: config.%s must be set", ErrInvalidConfig, s.Name, k)
+			}
+			v = stored
+		}
+		config[k] = v
+	}
+	ch.Name = s.Name
+	ch.Type = s.Type
+	ch.Enabled = s.Enabled == nil || *s.Enabled
+	ch.Config = config
+	if err := ch.Validate(); err != nil {
+		return fmt.Errorf("%w: alert channel %q: %v", ErrInvalidConfig, s.Name, err)
+	}
+	return nil
+}
+
+// StatusPageSpecFrom describes a stored status page as a spec. monitorNames
+// maps monitor IDs to names.
+func StatusPageSpecFrom(p *StatusPage, monitorNames map[uuid.UUID]string) StatusPageSpec {
+	spec := StatusPageSpec{Slug: p.Slug, Name: p.Name, Description: p.Description}
+	if !p.IsPublic {
+		public := false
+		spec.Public = &public
+	}
+	for _, id := range p.MonitorIDs {
+		if name, ok := monitorNames[id]; ok {
+			spec.Monitors = append(spec.Monitors, name)
+		}
+	}
+	return spec
+}
+
+// ApplyTo sets the declared settings and monitors on p. monitors maps
+// monitor names to IDs.
+func (s StatusPageSpec) ApplyTo(p *StatusPage, monitors map[string]uuid.UUID) error {
+	if GenerateSlug(s.Slug) != s.Slug {
+		return fmt.Errorf("%w: status page %q: slug may only contain lowercase letters, digits and dashes", ErrInvalidConfig, s.Slug)
+	}
+	if s.Name == "" {
+		return fmt.Errorf("%w: status page %q: name is required", ErrInvalidConfig, s.Slug)
+	}
+	ids := make([]uuid.UUID, 0, len(s.Monitors))
+	for _, name := range s.Monitors {
+		id, ok := monitors[name]
+		if !ok {
+			return fmt.Errorf("%w: status page %q: unknown monitor %q", ErrInvalidConfig, s.Slug, name)
+		}
+		ids = append(ids, id)
+	}
+	p.Slug = s.Slug
+	p.Name = s.Name
+	p.Description = s.Description
+	p.IsPublic = s.Public == nil || *s.Public
+	p.MonitorIDs = ids
+	return nil
+}
+
+// MaintenanceWindowSpecFrom describes a stored maintenance window as a spec.
+func MaintenanceWindowSpecFrom(w *MaintenanceWindow, agentNames map[uuid.UUID]string) MaintenanceWindowSpec {
+	return MaintenanceWindowSpec{
+		Name:       w.Name,
+		Agent:      agentNames[w.AgentID],
+		StartsAt:   w.StartsAt.UTC(),
+		EndsAt:     w.EndsAt.UTC(),
+		Recurrence: w.Recurrence,
+	}
+}
+
+// ApplyTo sets the declared agent and schedule on w.
+func (s MaintenanceWindowSpec) ApplyTo(w *MaintenanceWindow, agents map[string]*Agent) error {
+	agent := agents[s.Agent]
+	if agent == nil {
+		return fmt.Errorf("%w: maintenance window %q: unknown agent %q", ErrInvalidConfig, s.Name, s.Agent)
+	}
+	w.Name = s.Name
+	w.AgentID = agent.ID
+	w.StartsAt = s.StartsAt
+	w.EndsAt = s.EndsAt
+	w.Recurrence = s.Recurrence
+	if err := w.Validate(); err != nil {
+		return fmt.Errorf("%w: maintenance window %q: %v", ErrInvalidConfig, s.Name, err)
+	}
+	return nil
+}
+
+// ChangedFields compares two specs of the same kind and returns the names of
+// the fields that differ, sorted.
+func ChangedFields(current, desired any) []string {
+	a, b := specFields(current), specFields(desired)
+	var fields []string
+	for k, v := range b {
+		if a[k] != v {
+			fields = append(fields, k)
+		}
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func specFields(spec any) map[string]string {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	out := make(map[string]string, len(fields))
+	for k, v := range fields {
+		out[k] = string(v)
+	}
+	return out
+}
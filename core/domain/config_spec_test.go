@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSpec_Validate(t *testing.T) {
+	ok := &ConfigSpec{
+		Agents:   []AgentSpec{{Name: "edge"}},
+		Monitors: []MonitorSpec{{Name: "api"}, {Name: "web"}},
+	}
+	assert.NoError(t, ok.Validate())
+
+	dup := &ConfigSpec{Monitors: []MonitorSpec{{Name: "api"}, {Name: "api"}}}
+	assert.True(t, errors.Is(dup.Validate(), ErrInvalidConfig))
+
+	unnamed := &ConfigSpec{StatusPages: []StatusPageSpec{{Name: "Public"}}}
+	assert.True(t, errors.Is(unnamed.Validate(), ErrInvalidConfig))
+}
+
+func TestMonitorSpec_ApplyTo_RoundTrip(t *testing.T) {
+	edge := &Agent{ID: uuid.New(), Name: "edge"}
+	eu := &Agent{ID: uuid.New(), Name: "eu"}
+	agents := map[string]*Agent{"edge": edge, "eu": eu}
+	names := map[uuid.UUID]string{edge.ID: "edge", eu.ID: "eu"}
+
+	spec := MonitorSpec{
+		Name:            "api",
+		Agent:           "edge",
+		Type:            MonitorTypeHTTP,
+		Target:          "https://api.example.com/healthz",
+		IntervalSeconds: 60,
+		Tags:            map[string]string{"env": "prod"},
+		Degraded:        &DegradedSpec{LatencyMs: 500},
+		FlapDetection:   &FlapDetectionSpec{Window: 10},
+		Locations:       &LocationsSpec{Agents: []string{"edge", "eu"}},
+	}
+	m := NewMonitor(uuid.Nil, "api", MonitorTypeHTTP, "")
+	require.NoError(t, spec.ApplyTo(m, agents))
+
+	assert.Equal(t, edge.ID, m.AgentID)
+	assert.Equal(t, 60, m.IntervalSeconds)
+	assert.Equal(t, DefaultTimeoutSeconds, m.TimeoutSeconds)
+	assert.Equal(t, 500, *m.DegradedLatencyMs)
+	assert.Equal(t, DefaultDegradedLatencyChecks, m.DegradedLatencyChecks)
+	assert.Equal(t, DefaultFlapThresholdPercent, m.FlapThresholdPercent)
+	assert.Equal(t, []uuid.UUID{edge.ID, eu.ID}, m.LocationAgentIDs)
+
+	exported := MonitorSpecFrom(m, names)
+	assert.Equal(t, "edge", exported.Agent)
+	assert.Equal(t, []string{"edge", "eu"}, exported.Locations.Agents)
+
+	// Applying the exported spec changes nothing.
+	again := *m
+	require.NoError(t, exported.ApplyTo(&again, agents))
+	assert.Empty(t, ChangedFields(exported, MonitorSpecFrom(&again, names)))
+}
+
+func TestMonitorSpec_ApplyTo_ResetsOmittedSettings(t *testing.T) {
+	agent := &Agent{ID: uuid.New(), Name: "edge"}
+	agents := map[string]*Agent{"edge": agent}
+	names := map[uuid.UUID]string{agent.ID: "edge"}
+
+	m := NewMonitor(agent.ID, "api", MonitorTypeHTTP, "https://example.com")
+	m.SetDegradedLatency(800, 5)
+	m.SetFlapDetection(20, 40)
+	m.Severity = IncidentSeverityMinor
+	m.Disable()
+
+	spec := MonitorSpec{Name: "api", Agent: "edge", Type: MonitorTypeHTTP, Target: "https://example.com"}
+	before := MonitorSpecFrom(m, names)
+	require.NoError(t, spec.ApplyTo(m, agents))
+
+	assert.True(t, m.Enabled)
+	assert.Nil(t, m.DegradedLatencyMs)
+	assert.Zero(t, m.FlapWindow)
+	assert.Equal(t, DefaultIncidentSeverity, m.Severity)
+	assert.Equal(t, []string{"degraded", "enabled", "flap_detection", "severity"}, ChangedFields(before, MonitorSpecFrom(m, names)))
+}
+
+func TestMonitorSpec_ApplyTo_Invalid(t *testing.T) {
+	hub := &Agent{ID: uuid.New(), Name: "hub", Hub: true}
+	edge := &Agent{ID: uuid.New(), Name: "edge"}
+	agents := map[string]*Agent{"hub": hub, "edge": edge}
+
+	tests := []struct {
+		name string
+		spec MonitorSpec
+	}{
+		{"unknown agent", MonitorSpec{Name: "a", Agent: "nope", Type: MonitorTypeHTTP, Target: "https://x"}},
+		{"missing target", MonitorSpec{Name: "a", Agent: "edge", Type: MonitorTypeHTTP}},
+		{"hub runs no docker checks", MonitorSpec{Name: "a", Agent: "hub", Type: MonitorTypeDocker, Target: "web"}},
+		{"interval out of range", MonitorSpec{Name: "a", Agent: "edge", Type: MonitorTypeHTTP, Target: "https://x", IntervalSeconds: 1}},
+		{"assertions on tcp", MonitorSpec{Name: "a", Agent: "edge", Type: MonitorTypeTCP, Target: "x:1", Assertions: []Assertion{{Type: AssertionStatusCode, Operator: OpEquals, Target: "200"}}}},
+		{"push grace on http", MonitorSpec{Name: "a", Agent: "edge", Type: MonitorTypeHTTP, Target: "https://x", PushGraceSeconds: new(int)}},
+		{"transaction without steps", MonitorSpec{Name: "a", Agent: "edge", Type: MonitorTypeTransaction}},
+		{"unknown severity", MonitorSpec{Name: "a", Agent: "edge", Type: MonitorTypeHTTP, Target: "https://x", Severity: "sev1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonitor(uuid.Nil, tt.spec.Name, tt.spec.Type, "")
+			err := tt.spec.ApplyTo(m, agents)
+			assert.True(t, errors.Is(err, ErrInvalidConfig), "got %v", err)
+		})
+	}
+}
+
+func TestMonitorSpec_ApplyTo_Push(t *testing.T) {
+	agent := &Agent{ID: uuid.New(), Name: "edge"}
+	m := NewMonitor(agent.ID, "backup", MonitorTypePush, "")
+	m.EnablePush("wd_push_x")
+
+	spec := MonitorSpec{Name: "backup", Agent: "edge", Type: MonitorTypePush, IntervalSeconds: 86400}
+	require.NoError(t, spec.ApplyTo(m, map[string]*Agent{"edge": agent}))
+
+	assert.Equal(t, 86400, m.IntervalSeconds)
+	assert.Equal(t, DefaultPushGraceSeconds, m.PushGraceSeconds)
+	assert.Equal(t, 1, m.FailureThreshold, "push monitors fail on the first missed run")
+	assert.Equal(t, "wd_push_x", m.PushToken)
+}
+
+func TestMonitorSpec_ApplyTo_TransactionKeepsVersion(t *testing.T) {
+	agent := &Agent{ID: uuid.New(), Name: "edge"}
+	agents := map[string]*Agent{"edge": agent}
+	steps := []TransactionStep{{Name: "home", Method: "GET", URL: "https://example.com/"}}
+	spec := MonitorSpec{Name: "login", Agent: "edge", Type: MonitorTypeTransaction, Steps: steps}
+
+	m := NewMonitor(agent.ID, "login", MonitorTypeTransaction, "")
+	require.NoError(t, spec.ApplyTo(m, agents))
+	require.NoError(t, spec.ApplyTo(m, agents))
+
+	assert.Equal(t, 1, m.Transaction.Version, "unchanged steps keep the version")
+	assert.Equal(t, "https://example.com/", m.Target)
+}
+
+func TestAlertChannelSpec_RedactedPassword(t *testing.T) {
+	ch := NewAlertChannel(uuid.New(), AlertChannelEmail, "ops", map[string]string{
+		"host": "smtp.example.com", "from": "a@example.com", "to": "b@example.com", "password": "hunter2",
+	})
+
+	spec := AlertChannelSpecFrom(ch)
+	assert.Equal(t, RedactedSecret, spec.Config["password"])
+
+	updated := *ch
+	require.NoError(t, spec.ApplyTo(&updated))
+	assert.Equal(t, "hunter2", updated.Config["password"], "redacted value keeps the stored password")
+
+	fresh := NewAlertChannel(uuid.New(), AlertChannelEmail, "ops", nil)
+	assert.True(t, errors.Is(spec.ApplyTo(fresh), ErrInvalidConfig), "new channels need the real password")
+}
+
+func TestStatusPageSpec_ApplyTo(t *testing.T) {
+	api := uuid.New()
+	page := NewStatusPage(uuid.New(), "", "")
+
+	spec := StatusPageSpec{Slug: "public", Name: "Public", Monitors: []string{"api"}}
+	require.NoError(t, spec.ApplyTo(page, map[string]uuid.UUID{"api": api}))
+	assert.Equal(t, []uuid.UUID{api}, page.MonitorIDs)
+	assert.True(t, page.IsPublic)
+
+	bad := StatusPageSpec{Slug: "Not A Slug", Name: "x"}
+	assert.True(t, errors.Is(bad.ApplyTo(page, nil), ErrInvalidConfig))
+
+	unknown := StatusPageSpec{Slug: "public", Name: "Public", Monitors: []string{"web"}}
+	assert.True(t, errors.Is(unknown.ApplyTo(page, nil), ErrInvalidConfig))
+}
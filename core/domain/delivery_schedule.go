@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidDeliverySchedule is returned when a delivery schedule fails
+// validation.
+var ErrInvalidDeliverySchedule = errors.New("invalid delivery schedule")
+
+// MaxDeliveryWindows caps the windows of a delivery schedule.
+const MaxDeliveryWindows = 14
+
+// DeliveryPolicy decides what happens to an event outside a channel's
+// delivery windows.
+type DeliveryPolicy string
+
+const (
+	// DeliveryPolicyDrop drops the event.
+	DeliveryPolicyDrop DeliveryPolicy = "drop"
+	// DeliveryPolicyDefer holds the event until the next window opens.
+	DeliveryPolicyDefer DeliveryPolicy = "defer"
+	// DeliveryPolicyCriticalOnly delivers critical incidents and drops the rest.
+	DeliveryPolicyCriticalOnly DeliveryPolicy = "critical_only"
+)
+
+// IsValid reports whether p is a known delivery policy.
+func (p DeliveryPolicy) IsValid() bool {
+	switch p {
+	case DeliveryPolicyDrop, DeliveryPolicyDefer, DeliveryPolicyCriticalOnly:
+		return true
+	}
+	return false
+}
+
+// DeliveryWindow is a weekly time range in which a channel delivers. A
+// window whose End is not after its Start runs past midnight; the weekday is
+// the one it started on.
+type DeliveryWindow struct {
+	Days  []time.Weekday `json:"days,omitempty"` // 0 is Sunday; empty means every day
+	Start string         `json:"start"`          // HH:MM
+	End   string         `json:"end"`            // HH:MM
+}
+
+// DeliverySchedule limits when a channel delivers notifications: inside one
+// of its windows, in Timezone, events are sent as usual; outside them the
+// Outside policy applies.
+type DeliverySchedule struct {
+	Timezone string           `json:"timezone"`
+	Windows  []DeliveryWindow `json:"windows"`
+	Outside  DeliveryPolicy   `json:"outside"`
+}
+
+// Validate checks the schedule and defaults its timezone to UTC.
+func (s *DeliverySchedule) Validate() error {
+	if len(s.Windows) == 0 {
+		return fmt.Errorf("%w: at least one window is required", ErrInvalidDeliverySchedule)
+	}
+	if len(s.Windows) > MaxDeliveryWindows {
+		return fmt.Errorf("%w: at most %d windows are allowed", ErrInvalidDeliverySchedule, MaxDeliveryWindows)
+	}
+	if !s.Outside.IsValid() {
+		return fmt.Errorf("%w: outside must be drop, defer or critical_only", ErrInvalidDeliverySchedule)
+	}
+	if s.Timezone == "" {
+		s.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidDeliverySchedule, s.Timezone)
+	}
+	for i, w := range s.Windows {
+		start, err := time.Parse(AlertRuleTimeLayout, w.Start)
+		if err != nil {
+			return fmt.Errorf("%w: windows[%d].start must be HH:MM", ErrInvalidDeliverySchedule, i)
+		}
+		end, err := time.Parse(AlertRuleTimeLayout, w.End)
+		if err != nil {
+			return fmt.Errorf("%w: windows[%d].end must be HH:MM", ErrInvalidDeliverySchedule, i)
+		}
+		if start.Equal(end) {
+			return fmt.Errorf("%w: windows[%d].start and end must differ", ErrInvalidDeliverySchedule, i)
+		}
+		for _, d := range w.Days {
+			if d < time.Sunday || d > time.Saturday {
+				return fmt.Errorf("%w: windows[%d].days must be between 0 (Sunday) and 6", ErrInvalidDeliverySchedule, i)
+			}
+		}
+	}
+	return nil
+}
+
+// IsOpen returns true if t falls inside one of the schedule's windows.
+func (s *DeliverySchedule) IsOpen(t time.Time) bool {
+	for _, w := range s.Windows {
+		tw := AlertTimeWindow{Start: w.Start, End: w.End, Days: w.Days, Timezone: s.Timezone}
+		if tw.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns when the next window opens after t, or t itself if a
+// window is open at t.
+func (s *DeliverySchedule) NextOpen(t time.Time) time.Time {
+	if s.IsOpen(t) {
+		return t
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+
+	var next time.Time
+	for offset := 0; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		for _, w := range s.Windows {
+			if len(w.Days) > 0 && !slices.Contains(w.Days, day.Weekday()) {
+				continue
+			}
+			start, _ := time.Parse(AlertRuleTimeLayout, w.Start)
+			opens := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+			if opens.After(t) && (next.IsZero() || opens.Before(next)) {
+				next = opens
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+	return next
+}
+
+// DeliveryDecision is what a delivery schedule does with an event.
+type DeliveryDecision string
+
+const (
+	DeliveryNow   DeliveryDecision = "deliver"
+	DeliveryDrop  DeliveryDecision = "drop"
+	DeliveryDefer DeliveryDecision = "defer"
+)
+
+// Decide returns what happens to an event at t, and for deferred events when
+// they are delivered. Critical events are those about a critical incident.
+func (s *DeliverySchedule) Decide(t time.Time, critical bool) (DeliveryDecision, time.Time) {
+	if s.IsOpen(t) {
+		return DeliveryNow, t
+	}
+	switch s.Outside {
+	case DeliveryPolicyDefer:
+		return DeliveryDefer, s.NextOpen(t)
+	case DeliveryPolicyCriticalOnly:
+		if critical {
+			return DeliveryNow, t
+		}
+	}
+	return DeliveryDrop, time.Time{}
+}
+
+// DeferredEventMaintenance is the event of a deferred agent maintenance
+// notification.
+const DeferredEventMaintenance = "agent_maintenance"
+
+// MaxDeferredAttempts caps how often delivery of a deferred notification is
+// tried before it is given up.
+const MaxDeferredAttempts = 5
+
+// DeferredNotification is an event held for a channel until its delivery
+// schedule opens.
+type DeferredNotification struct {
+	ID        uuid.UUID
+	ChannelID uuid.UUID
+	Event     string // an AlertEventKind or DeferredEventMaintenance
+	Payload   DeferredPayload
+	DeliverAt time.Time
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// DeferredPayload holds what is needed to send an event later: a deferred
+// event, or a notification delivery that is retried or redelivered. Only the
+// fields notifiers read are kept.
+type DeferredPayload struct {
+	Incident *Incident `json:"incident,omitempty"`
+	// AlertContext is carried separately: Incident does not serialize it.
+	AlertContext *AlertContext       `json:"alert_context,omitempty"`
+	Monitor      *Monitor            `json:"monitor,omitempty"`
+	Agent        *Agent              `json:"agent,omitempty"`
+	Count        int                 `json:"count,omitempty"` // affected monitors or resolved incidents
+	WindowName   string              `json:"window_name,omitempty"`
+	Digest       *NotificationDigest `json:"digest,omitempty"`
+}
+
+// NewDeferredNotification creates a notification for a channel to deliver at
+// the given time.
+func NewDeferredNotification(channelID uuid.UUID, event string, payload DeferredPayload, deliverAt time.Time) *DeferredNotification {
+	return &DeferredNotification{
+		ID:        uuid.New(),
+		ChannelID: channelID,
+		Event:     event,
+		Payload:   payload,
+		DeliverAt: deliverAt,
+		CreatedAt: time.Now(),
+	}
+}
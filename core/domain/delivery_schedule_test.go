@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverySchedule_Validate(t *testing.T) {
+	valid := func() *DeliverySchedule {
+		return &DeliverySchedule{
+			Windows: []DeliveryWindow{{Days: []time.Weekday{time.Monday}, Start: "09:00", End: "17:00"}},
+			Outside: DeliveryPolicyDefer,
+		}
+	}
+
+	s := valid()
+	require.NoError(t, s.Validate())
+	assert.Equal(t, "UTC", s.Timezone, "timezone defaults to UTC")
+
+	tests := []struct {
+		name   string
+		mutate func(*DeliverySchedule)
+	}{
+		{"no windows", func(s *DeliverySchedule) { s.Windows = nil }},
+		{"unknown policy", func(s *DeliverySchedule) { s.Outside = "later" }},
+		{"unknown timezone", func(s *DeliverySchedule) { s.Timezone = "Mars/Olympus" }},
+		{"bad start", func(s *DeliverySchedule) { s.Windows[0].Start = "9am" }},
+		{"empty window", func(s *DeliverySchedule) { s.Windows[0].End = "09:00" }},
+		{"bad day", func(s *DeliverySchedule) { s.Windows[0].Days = []time.Weekday{7} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.mutate(s)
+			err := s.Validate()
+			assert.True(t, errors.Is(err, ErrInvalidDeliverySchedule), "got %v", err)
+		})
+	}
+
+	ch := &AlertChannel{Type: AlertChannelSlack, Name: "ops", Config: map[string]string{"webhook_url": "https://hooks.slack.test/x"},
+		DeliverySchedule: &DeliverySchedule{Outside: DeliveryPolicyDrop}}
+	assert.ErrorIs(t, ch.Validate(), ErrInvalidDeliverySchedule)
+}
+
+func TestDeliverySchedule_OpenAndNextOpen(t *testing.T) {
+	// Weekdays 08:00-22:00 and Saturday nights 22:00-02:00, in Berlin.
+	s := &DeliverySchedule{
+		Timezone: "Europe/Berlin",
+		Windows: []DeliveryWindow{
+			{Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, Start: "08:00", End: "22:00"},
+			{Days: []time.Weekday{time.Saturday}, Start: "22:00", End: "02:00"},
+		},
+		Outside: DeliveryPolicyDefer,
+	}
+	require.NoError(t, s.Validate())
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, berlin) // March 2nd is a Monday
+	}
+
+	assert.True(t, s.IsOpen(at(2, 8, 0)))
+	assert.False(t, s.IsOpen(at(2, 3, 0)), "3am on a weekday is outside")
+	assert.True(t, s.IsOpen(at(8, 1, 30)), "Saturday's window runs past midnight into Sunday")
+	assert.False(t, s.IsOpen(at(8, 12, 0)))
+
+	assert.Equal(t, at(2, 8, 0), s.NextOpen(at(2, 3, 0)), "opens later the same day")
+	assert.Equal(t, at(3, 8, 0), s.NextOpen(at(2, 23, 0)), "opens the next morning")
+	assert.Equal(t, at(7, 22, 0), s.NextOpen(at(6, 23, 0)), "Friday night waits for Saturday's window")
+	assert.Equal(t, at(9, 8, 0), s.NextOpen(at(8, 3, 0)), "Sunday waits for Monday")
+	assert.True(t, s.NextOpen(at(2, 3, 0).UTC()).Equal(at(2, 8, 0)), "times in other zones are converted")
+}
+
+func TestDeliverySchedule_Decide(t *testing.T) {
+	s := &DeliverySchedule{Timezone: "UTC", Windows: []DeliveryWindow{{Start: "09:00", End: "17:00"}}}
+	night := time.Date(2026, time.March, 2, 3, 0, 0, 0, time.UTC)
+	day := night.Add(7 * time.Hour)
+
+	for _, policy := range []DeliveryPolicy{DeliveryPolicyDrop, DeliveryPolicyDefer, DeliveryPolicyCriticalOnly} {
+		s.Outside = policy
+		d, when := s.Decide(day, false)
+		assert.Equal(t, DeliveryNow, d, "%s: inside the window", policy)
+		assert.Equal(t, day, when)
+	}
+
+	s.Outside = DeliveryPolicyDrop
+	d, _ := s.Decide(night, true)
+	assert.Equal(t, DeliveryDrop, d)
+
+	s.Outside = DeliveryPolicyDefer
+	d, when := s.Decide(night, false)
+	assert.Equal(t, DeliveryDefer, d)
+	assert.Equal(t, time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC), when)
+
+	s.Outside = DeliveryPolicyCriticalOnly
+	d, _ = s.Decide(night, true)
+	assert.Equal(t, DeliveryNow, d)
+	d, _ = s.Decide(night, false)
+	assert.Equal(t, DeliveryDrop, d)
+}
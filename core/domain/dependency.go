@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dependency errors.
+var (
+	ErrDependencySelf  = errors.New("a monitor cannot depend on itself")
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+)
+
+// MaxDependencyDepth bounds how far dependency chains are walked, both when
+// building trees and when following suppressed incidents to their root cause.
+const MaxDependencyDepth = 10
+
+// MonitorDependency is a parent/child edge between monitors: MonitorID depends
+// on ParentID. While the parent has an active incident, incidents on the child
+// are suppressed and attached to the parent's incident.
+type MonitorDependency struct {
+	MonitorID uuid.UUID
+	ParentID  uuid.UUID
+	CreatedAt time.Time
+}
+
+// NewMonitorDependency creates a dependency edge, rejecting self-references.
+func NewMonitorDependency(monitorID, parentID uuid.UUID) (*MonitorDependency, error) {
+	if monitorID == parentID {
+		return nil, ErrDependencySelf
+	}
+	return &MonitorDependency{
+		MonitorID: monitorID,
+		ParentID:  parentID,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// WouldCreateCycle reports whether adding the edge child -> parent to the
+// existing edges creates a cycle, i.e. whether child is already reachable
+// upstream from parent.
+func WouldCreateCycle(edges []*MonitorDependency, child, parent uuid.UUID) bool {
+	if child == parent {
+		return true
+	}
+	parents := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		parents[e.MonitorID] = append(parents[e.MonitorID], e.ParentID)
+	}
+
+	seen := map[uuid.UUID]bool{parent: true}
+	queue := []uuid.UUID{parent}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, p := range parents[current] {
+			if p == child {
+				return true
+			}
+			if !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	return false
+}
+
+// DependencyNode is one monitor in a dependency tree. Parents lead upstream
+// towards the root cause; Children are the monitors that depend on it.
+type DependencyNode struct {
+	MonitorID  uuid.UUID         `json:"monitor_id"`
+	Name       string            `json:"name"`
+	Status     MonitorStatus     `json:"status"`
+	IncidentID *uuid.UUID        `json:"incident_id,omitempty"`
+	Suppressed bool              `json:"suppressed"`
+	RootCause  bool              `json:"root_cause"`
+	Parents    []*DependencyNode `json:"parents,omitempty"`
+	Children   []*DependencyNode `json:"children,omitempty"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMonitorDependency_RejectsSelf(t *testing.T) {
+	id := uuid.New()
+	_, err := NewMonitorDependency(id, id)
+	assert.ErrorIs(t, err, ErrDependencySelf)
+
+	dep, err := NewMonitorDependency(id, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, id, dep.MonitorID)
+}
+
+func TestWouldCreateCycle(t *testing.T) {
+	// app -> api -> db
+	app, api, db, other := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	edges := []*MonitorDependency{
+		{MonitorID: app, ParentID: api},
+		{MonitorID: api, ParentID: db},
+	}
+
+	tests := []struct {
+		name   string
+		child  uuid.UUID
+		parent uuid.UUID
+		want   bool
+	}{
+		{"self", app, app, true},
+		{"direct reverse", api, app, true},
+		{"transitive reverse", db, app, true},
+		{"shortcut down the chain", app, db, false},
+		{"unrelated", other, app, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WouldCreateCycle(edges, tt.child, tt.parent))
+		})
+	}
+}
+
+func TestIncident_SuppressUnder(t *testing.T) {
+	incident := NewIncident(uuid.New())
+	assert.False(t, incident.IsSuppressed())
+
+	parentID := uuid.New()
+	incident.SuppressUnder(parentID)
+	assert.True(t, incident.IsSuppressed())
+	assert.Equal(t, parentID, *incident.ParentIncidentID)
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscalationPolicyTag is the monitor tag that selects an escalation policy
+// by name when the monitor does not reference one directly.
+const EscalationPolicyTag = "escalation_policy"
+
+// Escalation policy limits.
+const (
+	MaxEscalationLevels       = 10
+	MaxEscalationTargets      = 20
+	MaxEscalationRepeat       = 5
+	MaxEscalationDelayMinutes = 24 * 60
+	MaxEscalationNameLength   = 100
+)
+
+// ErrInvalidEscalationPolicy is returned when a policy fails validation.
+var ErrInvalidEscalationPolicy = errors.New("invalid escalation policy")
+
+// EscalationLevel is one step of an escalation policy. When an incident is
+// still unacknowledged DelayMinutes after the previous level was paged (or
+// after the incident opened, for the first level), the level's channels and
+// the enabled channels of its users and of whoever is on call for its
+// schedules are paged.
+type EscalationLevel struct {
+	DelayMinutes int         `json:"delay_minutes"`
+	ChannelIDs   []uuid.UUID `json:"channel_ids"`
+	UserIDs      []uuid.UUID `json:"user_ids"`
+	ScheduleIDs  []uuid.UUID `json:"schedule_ids,omitempty"`
+}
+
+// Delay returns the level's delay as a duration.
+func (l EscalationLevel) Delay() time.Duration {
+	return time.Duration(l.DelayMinutes) * time.Minute
+}
+
+// EscalationPolicy pages ordered levels of responders until an incident is
+// acknowledged. After the last level the policy starts over from the first,
+// Repeat more times.
+type EscalationPolicy struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Levels    []EscalationLevel
+	Repeat    int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewEscalationPolicy creates a validated escalation policy.
+func NewEscalationPolicy(userID uuid.UUID, name string, levels []EscalationLevel, repeat int) (*EscalationPolicy, error) {
+	now := time.Now()
+	p := &EscalationPolicy{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Levels:    levels,
+		Repeat:    repeat,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks the policy's name, levels and repeat count.
+func (p *EscalationPolicy) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidEscalationPolicy)
+	}
+	if len(p.Name) > MaxEscalationNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidEscalationPolicy, MaxEscalationNameLength)
+	}
+	if len(p.Levels) == 0 || len(p.Levels) > MaxEscalationLevels {
+		return fmt.Errorf("%w: between 1 and %d levels are required", ErrInvalidEscalationPolicy, MaxEscalationLevels)
+	}
+	for i, l := range p.Levels {
+		if l.DelayMinutes < 0 || l.DelayMinutes > MaxEscalationDelayMinutes {
+			return fmt.Errorf("%w: levels[%d]: delay_minutes must be between 0 and %d", ErrInvalidEscalationPolicy, i, MaxEscalationDelayMinutes)
+		}
+		targets := len(l.ChannelIDs) + len(l.UserIDs) + len(l.ScheduleIDs)
+		if targets == 0 {
+			return fmt.Errorf("%w: levels[%d]: at least one channel, user or schedule is required", ErrInvalidEscalationPolicy, i)
+		}
+		if targets > MaxEscalationTargets {
+			return fmt.Errorf("%w: levels[%d]: at most %d channels, users and schedules are allowed", ErrInvalidEscalationPolicy, i, MaxEscalationTargets)
+		}
+	}
+	if p.Repeat < 0 || p.Repeat > MaxEscalationRepeat {
+		return fmt.Errorf("%w: repeat must be between 0 and %d", ErrInvalidEscalationPolicy, MaxEscalationRepeat)
+	}
+	if p.Repeat > 0 && p.Levels[0].DelayMinutes == 0 {
+		return fmt.Errorf("%w: the first level needs a delay when the policy repeats", ErrInvalidEscalationPolicy)
+	}
+	return nil
+}
+
+// Steps returns the levels in paging order, with the repeats unrolled.
+func (p *EscalationPolicy) Steps() []EscalationLevel {
+	steps := make([]EscalationLevel, 0, len(p.Levels)*(p.Repeat+1))
+	for range p.Repeat + 1 {
+		steps = append(steps, p.Levels...)
+	}
+	return steps
+}
+
+// Escalation is a policy running for one open incident. Steps is the
+// policy's unrolled levels at the time the incident opened, so later edits
+// to the policy do not affect escalations already in flight. Step is the
+// index of the next level to page; ResumeAt is set while the escalation
+// waits out that level's delay.
+type Escalation struct {
+	IncidentID uuid.UUID
+	PolicyID   uuid.UUID
+	WorkflowID uuid.UUID
+	Steps      []EscalationLevel
+	Step       int
+	ResumeAt   *time.Time
+	CreatedAt  time.Time
+}
+
+// NewEscalation starts tracking policy for an incident.
+func NewEscalation(incidentID uuid.UUID, policy *EscalationPolicy) *Escalation {
+	return &Escalation{
+		IncidentID: incidentID,
+		PolicyID:   policy.ID,
+		Steps:      policy.Steps(),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Current returns the level to page next, or false once every level was paged.
+func (e *Escalation) Current() (EscalationLevel, bool) {
+	if e.Step < 0 || e.Step >= len(e.Steps) {
+		return EscalationLevel{}, false
+	}
+	return e.Steps[e.Step], true
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEscalationPolicy(t *testing.T) {
+	channel := []uuid.UUID{uuid.New()}
+
+	p, err := NewEscalationPolicy(uuid.New(), "  primary  ", []EscalationLevel{{ChannelIDs: channel}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", p.Name)
+
+	tooMany := make([]uuid.UUID, MaxEscalationTargets+1)
+	tests := []struct {
+		name   string
+		pname  string
+		levels []EscalationLevel
+		repeat int
+	}{
+		{"empty name", " ", []EscalationLevel{{ChannelIDs: channel}}, 0},
+		{"long name", strings.Repeat("x", MaxEscalationNameLength+1), []EscalationLevel{{ChannelIDs: channel}}, 0},
+		{"no levels", "p", nil, 0},
+		{"too many levels", "p", make([]EscalationLevel, MaxEscalationLevels+1), 0},
+		{"negative delay", "p", []EscalationLevel{{DelayMinutes: -1, ChannelIDs: channel}}, 0},
+		{"delay too long", "p", []EscalationLevel{{DelayMinutes: MaxEscalationDelayMinutes + 1, ChannelIDs: channel}}, 0},
+		{"no targets", "p", []EscalationLevel{{DelayMinutes: 5}}, 0},
+		{"too many targets", "p", []EscalationLevel{{ChannelIDs: tooMany}}, 0},
+		{"repeat too high", "p", []EscalationLevel{{DelayMinutes: 5, ChannelIDs: channel}}, MaxEscalationRepeat + 1},
+		{"repeat without first delay", "p", []EscalationLevel{{ChannelIDs: channel}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEscalationPolicy(uuid.New(), tt.pname, tt.levels, tt.repeat)
+			assert.True(t, errors.Is(err, ErrInvalidEscalationPolicy), "got %v", err)
+		})
+	}
+}
+
+func TestEscalationPolicy_Steps(t *testing.T) {
+	first := EscalationLevel{DelayMinutes: 10, ChannelIDs: []uuid.UUID{uuid.New()}}
+	second := EscalationLevel{DelayMinutes: 5, UserIDs: []uuid.UUID{uuid.New()}}
+	p := &EscalationPolicy{Levels: []EscalationLevel{first, second}, Repeat: 2}
+
+	steps := p.Steps()
+	require.Len(t, steps, 6)
+	assert.Equal(t, first, steps[0])
+	assert.Equal(t, second, steps[1])
+	assert.Equal(t, first, steps[4])
+	assert.Equal(t, second, steps[5])
+}
+
+func TestEscalation_Current(t *testing.T) {
+	level := EscalationLevel{DelayMinutes: 10, ChannelIDs: []uuid.UUID{uuid.New()}}
+	policy := &EscalationPolicy{ID: uuid.New(), Levels: []EscalationLevel{level}}
+	esc := NewEscalation(uuid.New(), policy)
+	assert.Equal(t, policy.ID, esc.PolicyID)
+
+	got, ok := esc.Current()
+	require.True(t, ok)
+	assert.Equal(t, level, got)
+
+	esc.Step++
+	_, ok = esc.Current()
+	assert.False(t, ok)
+}
@@ -44,6 +44,13 @@ type AlertContext struct {
 	AgentName     string
 	Interval      int // check interval in seconds
 	Threshold     int // failure threshold count
+	Severity      IncidentSeverity
+	// Group is set when the alert announces an alert group.
+	Group *AlertGroupSummary
+	// Suppressed counts the channel's notifications rate limited,
+	// deduplicated or dropped outside its delivery schedule since its last
+	// message.
+	Suppressed int
 }
 
 // LatencyPoint represents an aggregated latency data point for charts.
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// HeartbeatRollup names the table a heartbeat aggregate query reads: the raw
+// heartbeats hypertable or one of its continuous aggregates.
+type HeartbeatRollup string
+
+const (
+	HeartbeatRollupRaw HeartbeatRollup = "heartbeats"
+	HeartbeatRollup1m  HeartbeatRollup = "heartbeats_1m"
+	HeartbeatRollup1h  HeartbeatRollup = "heartbeats_1h"
+	HeartbeatRollup1d  HeartbeatRollup = "heartbeats_1d"
+)
+
+// HeartbeatRollups lists the continuous aggregates, finest first.
+var HeartbeatRollups = []HeartbeatRollup{HeartbeatRollup1m, HeartbeatRollup1h, HeartbeatRollup1d}
+
+// MinRollupBuckets is the fewest rollup rows a query may read. A coarser
+// rollup would blur the start of the range, where a partial bucket is
+// counted whole.
+const MinRollupBuckets = 60
+
+// Width returns the bucket width of the rollup, or 0 for raw heartbeats.
+func (r HeartbeatRollup) Width() time.Duration {
+	switch r {
+	case HeartbeatRollup1m:
+		return time.Minute
+	case HeartbeatRollup1h:
+		return time.Hour
+	case HeartbeatRollup1d:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Start returns the start of the rollup bucket containing t.
+func (r HeartbeatRollup) Start(t time.Time) time.Time {
+	if r.Width() == 0 {
+		return t
+	}
+	return t.Truncate(r.Width())
+}
+
+// HeartbeatRollupFor picks the coarsest rollup for a query over span whose
+// results are grouped into buckets of the given width (0 for a single
+// total). The rollup must fit MinRollupBuckets into the span and divide the
+// result buckets evenly; when none does, raw heartbeats are read.
+func HeartbeatRollupFor(span, bucket time.Duration) HeartbeatRollup {
+	for i := len(HeartbeatRollups) - 1; i >= 0; i-- {
+		r := HeartbeatRollups[i]
+		w := r.Width()
+		if w*MinRollupBuckets > span {
+			continue
+		}
+		if bucket != 0 && (bucket < w || bucket%w != 0) {
+			continue
+		}
+		return r
+	}
+	return HeartbeatRollupRaw
+}
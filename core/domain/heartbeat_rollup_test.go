@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeatRollupFor(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		name   string
+		span   time.Duration
+		bucket time.Duration
+		want   HeartbeatRollup
+	}{
+		{"short range reads raw", 30 * time.Minute, time.Minute, HeartbeatRollupRaw},
+		{"sub-minute buckets read raw", day, 30 * time.Second, HeartbeatRollupRaw},
+		{"1h chart", time.Hour, time.Minute, HeartbeatRollup1m},
+		{"24h chart", day, 15 * time.Minute, HeartbeatRollup1m},
+		{"7d chart", 7 * day, time.Hour, HeartbeatRollup1h},
+		{"30d chart", 30 * day, 6 * time.Hour, HeartbeatRollup1h},
+		{"uneven buckets fall back", 7 * day, 90 * time.Minute, HeartbeatRollup1m},
+		{"7d uptime", 7 * day, 0, HeartbeatRollup1h},
+		{"30d uptime", 30 * day, 0, HeartbeatRollup1h},
+		{"90d uptime", 90 * day, 0, HeartbeatRollup1d},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HeartbeatRollupFor(tt.span, tt.bucket))
+		})
+	}
+}
+
+func TestHeartbeatRollup_Start(t *testing.T) {
+	ts := time.Date(2026, 5, 4, 13, 45, 30, 0, time.UTC)
+	assert.Equal(t, ts, HeartbeatRollupRaw.Start(ts))
+	assert.Equal(t, time.Date(2026, 5, 4, 13, 45, 0, 0, time.UTC), HeartbeatRollup1m.Start(ts))
+	assert.Equal(t, time.Date(2026, 5, 4, 13, 0, 0, 0, time.UTC), HeartbeatRollup1h.Start(ts))
+	assert.Equal(t, time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC), HeartbeatRollup1d.Start(ts))
+}
@@ -2,7 +2,11 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -14,12 +18,15 @@ const (
 	IncidentStatusOpen         IncidentStatus = "open"
 	IncidentStatusAcknowledged IncidentStatus = "acknowledged"
 	IncidentStatusResolved     IncidentStatus = "resolved"
+	// IncidentStatusFlapping holds an incident whose monitor is oscillating.
+	// Open/resolve notifications are suppressed until the monitor stabilises.
+	IncidentStatusFlapping IncidentStatus = "flapping"
 )
 
 // IsValid checks if the status is a valid IncidentStatus.
 func (s IncidentStatus) IsValid() bool {
 	switch s {
-	case IncidentStatusOpen, IncidentStatusAcknowledged, IncidentStatusResolved:
+	case IncidentStatusOpen, IncidentStatusAcknowledged, IncidentStatusResolved, IncidentStatusFlapping:
 		return true
 	default:
 		return false
@@ -28,9 +35,88 @@ func (s IncidentStatus) IsValid() bool {
 
 // IsActive returns true if the incident is still active (not resolved).
 func (s IncidentStatus) IsActive() bool {
-	return s == IncidentStatusOpen || s == IncidentStatusAcknowledged
+	return s == IncidentStatusOpen || s == IncidentStatusAcknowledged || s == IncidentStatusFlapping
 }
 
+// IncidentKind distinguishes outages from lower-severity incidents.
+type IncidentKind string
+
+const (
+	IncidentKindDown     IncidentKind = "down"
+	IncidentKindDegraded IncidentKind = "degraded"
+	// IncidentKindAnomaly is opened when latency deviates from the
+	// monitor's learned baseline.
+	IncidentKindAnomaly IncidentKind = "anomaly"
+	// IncidentKindDeclared is opened by an operator for an event no monitor
+	// detects, such as a vendor outage or a security event.
+	IncidentKindDeclared IncidentKind = "declared"
+)
+
+// IsValid checks if the kind is a valid IncidentKind.
+func (k IncidentKind) IsValid() bool {
+	switch k {
+	case IncidentKindDown, IncidentKindDegraded, IncidentKindAnomaly, IncidentKindDeclared:
+		return true
+	default:
+		return false
+	}
+}
+
+// MonitorStatus returns the monitor status implied by an active incident of this kind.
+func (k IncidentKind) MonitorStatus() MonitorStatus {
+	if k == IncidentKindDegraded || k == IncidentKindAnomaly {
+		return MonitorStatusDegraded
+	}
+	return MonitorStatusDown
+}
+
+// IncidentSeverity ranks how urgently an incident needs attention.
+type IncidentSeverity string
+
+const (
+	IncidentSeverityCritical IncidentSeverity = "critical"
+	IncidentSeverityMajor    IncidentSeverity = "major"
+	IncidentSeverityMinor    IncidentSeverity = "minor"
+	IncidentSeverityInfo     IncidentSeverity = "info"
+)
+
+// DefaultIncidentSeverity is the severity of monitors that do not set one.
+const DefaultIncidentSeverity = IncidentSeverityCritical
+
+// IncidentSeverities lists the valid severities, most severe first.
+var IncidentSeverities = []IncidentSeverity{IncidentSeverityCritical, IncidentSeverityMajor, IncidentSeverityMinor, IncidentSeverityInfo}
+
+// IsValid checks if the severity is a valid IncidentSeverity.
+func (s IncidentSeverity) IsValid() bool {
+	switch s {
+	case IncidentSeverityCritical, IncidentSeverityMajor, IncidentSeverityMinor, IncidentSeverityInfo:
+		return true
+	default:
+		return false
+	}
+}
+
+// Lower returns the next less severe level. Info is the lowest.
+func (s IncidentSeverity) Lower() IncidentSeverity {
+	switch s {
+	case IncidentSeverityCritical:
+		return IncidentSeverityMajor
+	case IncidentSeverityMajor:
+		return IncidentSeverityMinor
+	default:
+		return IncidentSeverityInfo
+	}
+}
+
+// IsLow returns true for minor and info, the severities notification
+// digests batch.
+func (s IncidentSeverity) IsLow() bool {
+	return s == IncidentSeverityMinor || s == IncidentSeverityInfo
+}
+
+// ErrInvalidIncidentSeverity is returned for a severity outside IncidentSeverities.
+var ErrInvalidIncidentSeverity = errors.New("severity must be one of critical, major, minor, info")
+
 // Errors for incident state transitions.
 var (
 	ErrIncidentAlreadyResolved     = errors.New("incident is already resolved")
@@ -38,6 +124,16 @@ var (
 	ErrIncidentNotAcknowledged     = errors.New("incident must be acknowledged before resolving")
 )
 
+// Limits for declared incidents.
+const (
+	MaxDeclaredIncidentTitleLength       = 200
+	MaxDeclaredIncidentDescriptionLength = 10000
+	MaxDeclaredIncidentMonitors          = 50
+)
+
+// ErrInvalidDeclaredIncident is returned when a declared incident fails validation.
+var ErrInvalidDeclaredIncident = errors.New("invalid declared incident")
+
 // Incident represents a monitoring incident (downtime event).
 type Incident struct {
 	ID             uuid.UUID
@@ -48,8 +144,22 @@ type Incident struct {
 	AcknowledgedBy *uuid.UUID
 	AcknowledgedAt *time.Time
 	Status         IncidentStatus
+	Kind           IncidentKind
+	Severity       IncidentSeverity
 	CreatedAt      time.Time
-	AlertContext   *AlertContext `json:"-"` // transient, populated at dispatch time
+	// ParentIncidentID is set on sub-incidents suppressed because an upstream
+	// dependency already has an active incident.
+	ParentIncidentID *uuid.UUID
+	// AlertGroupID is set on incidents correlated into an alert group.
+	AlertGroupID *uuid.UUID
+	// Declared incidents have no MonitorID; UserID owns them instead, and
+	// the fields below describe them.
+	UserID             *uuid.UUID
+	Title              string
+	Description        string
+	OwnerID            *uuid.UUID    // the user responsible for the incident
+	AffectedMonitorIDs []uuid.UUID   // monitors, and so status page components, it affects
+	AlertContext       *AlertContext `json:"-"` // transient, populated at dispatch time
 }
 
 // NewIncident creates a new open incident.
@@ -60,10 +170,84 @@ func NewIncident(monitorID uuid.UUID) *Incident {
 		MonitorID: monitorID,
 		StartedAt: now,
 		Status:    IncidentStatusOpen,
+		Kind:      IncidentKindDown,
+		Severity:  DefaultIncidentSeverity,
 		CreatedAt: now,
 	}
 }
 
+// NewDeclaredIncident creates an open incident declared by a user. It is
+// owned by the user until reassigned. An empty severity uses the default.
+func NewDeclaredIncident(userID uuid.UUID, title, description string, severity IncidentSeverity, affectedMonitorIDs []uuid.UUID) (*Incident, error) {
+	if severity == "" {
+		severity = DefaultIncidentSeverity
+	}
+	incident := NewIncident(uuid.Nil)
+	incident.Kind = IncidentKindDeclared
+	incident.Severity = severity
+	incident.UserID = &userID
+	incident.OwnerID = &userID
+	incident.Title = strings.TrimSpace(title)
+	incident.Description = strings.TrimSpace(description)
+	for _, id := range affectedMonitorIDs {
+		if !slices.Contains(incident.AffectedMonitorIDs, id) {
+			incident.AffectedMonitorIDs = append(incident.AffectedMonitorIDs, id)
+		}
+	}
+	if err := incident.validateDeclared(); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+func (i *Incident) validateDeclared() error {
+	if i.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidDeclaredIncident)
+	}
+	if utf8.RuneCountInString(i.Title) > MaxDeclaredIncidentTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", ErrInvalidDeclaredIncident, MaxDeclaredIncidentTitleLength)
+	}
+	if utf8.RuneCountInString(i.Description) > MaxDeclaredIncidentDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", ErrInvalidDeclaredIncident, MaxDeclaredIncidentDescriptionLength)
+	}
+	if !i.Severity.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidDeclaredIncident, ErrInvalidIncidentSeverity.Error())
+	}
+	if len(i.AffectedMonitorIDs) > MaxDeclaredIncidentMonitors {
+		return fmt.Errorf("%w: at most %d affected monitors", ErrInvalidDeclaredIncident, MaxDeclaredIncidentMonitors)
+	}
+	return nil
+}
+
+// NewDegradedIncident creates a new open incident for a degraded monitor.
+func NewDegradedIncident(monitorID uuid.UUID) *Incident {
+	incident := NewIncident(monitorID)
+	incident.Kind = IncidentKindDegraded
+	return incident
+}
+
+// DeriveSeverity sets the incident's severity from its monitor's default.
+// Degraded performance and latency anomalies rank one level below an outage.
+func (i *Incident) DeriveSeverity(m *Monitor) {
+	severity := m.Severity
+	if !severity.IsValid() {
+		severity = DefaultIncidentSeverity
+	}
+	if i.IsDegraded() {
+		severity = severity.Lower()
+	}
+	i.Severity = severity
+}
+
+// SetSeverity overrides the incident's derived severity.
+func (i *Incident) SetSeverity(severity IncidentSeverity) error {
+	if !severity.IsValid() {
+		return ErrInvalidIncidentSeverity
+	}
+	i.Severity = severity
+	return nil
+}
+
 // Acknowledge marks the incident as acknowledged by a user.
 func (i *Incident) Acknowledge(userID uuid.UUID) error {
 	if i.Status == IncidentStatusResolved {
@@ -125,3 +309,57 @@ func (i *Incident) IsResolved() bool {
 func (i *Incident) IsActive() bool {
 	return i.Status.IsActive()
 }
+
+// IsFlapping returns true if the incident is held in the flapping state.
+func (i *Incident) IsFlapping() bool {
+	return i.Status == IncidentStatusFlapping
+}
+
+// IsDegraded returns true if the incident tracks degraded performance rather than an outage.
+// Latency anomalies count as degraded performance.
+func (i *Incident) IsDegraded() bool {
+	return i.Kind == IncidentKindDegraded || i.Kind == IncidentKindAnomaly
+}
+
+// SuppressUnder attaches the incident to an upstream parent incident.
+func (i *Incident) SuppressUnder(parentID uuid.UUID) {
+	i.ParentIncidentID = &parentID
+}
+
+// IsDeclared returns true if an operator declared the incident rather than
+// a monitor opening it.
+func (i *Incident) IsDeclared() bool {
+	return i.Kind == IncidentKindDeclared
+}
+
+// DeclaredBy returns true if the incident was declared by the user.
+func (i *Incident) DeclaredBy(userID uuid.UUID) bool {
+	return i.IsDeclared() && i.UserID != nil && *i.UserID == userID
+}
+
+// declaredTargetLength caps the description shown in place of a target.
+const declaredTargetLength = 300
+
+// DeclaredMonitor returns the stand-in monitor a declared incident's
+// notifications are rendered with: the title names it and the start of the
+// description takes the place of its target.
+func (i *Incident) DeclaredMonitor() *Monitor {
+	target := i.Description
+	if utf8.RuneCountInString(target) > declaredTargetLength {
+		target = string([]rune(target)[:declaredTargetLength-1]) + "…"
+	}
+	return &Monitor{
+		Name:     i.Title,
+		Type:     MonitorType(IncidentKindDeclared),
+		Target:   target,
+		Status:   MonitorStatusDown,
+		Severity: i.Severity,
+		Metadata: map[string]string{},
+	}
+}
+
+// IsSuppressed returns true if the incident is a sub-incident of an upstream
+// dependency's incident. Suppressed incidents send no notifications.
+func (i *Incident) IsSuppressed() bool {
+	return i.ParentIncidentID != nil
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentActivityKind distinguishes the entries of an incident's activity log.
+type IncidentActivityKind string
+
+const (
+	// IncidentActivityNote is a free-text note left by a responder.
+	IncidentActivityNote IncidentActivityKind = "note"
+	// IncidentActivityLink points at an external resource such as a runbook,
+	// dashboard or ticket.
+	IncidentActivityLink IncidentActivityKind = "link"
+	// IncidentActivityStatusChange records a responder moving the incident
+	// to another status. Status changes cannot be edited or deleted.
+	IncidentActivityStatusChange IncidentActivityKind = "status_change"
+)
+
+// IsValid checks if the kind is a valid IncidentActivityKind.
+func (k IncidentActivityKind) IsValid() bool {
+	switch k {
+	case IncidentActivityNote, IncidentActivityLink, IncidentActivityStatusChange:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsEditable reports whether responders may edit or delete entries of this kind.
+func (k IncidentActivityKind) IsEditable() bool {
+	return k == IncidentActivityNote || k == IncidentActivityLink
+}
+
+// Incident activity limits.
+const (
+	MaxIncidentNoteLength    = 10000
+	MaxIncidentLinkLength    = 2048
+	MaxPostmortemTextLength  = 20000
+	MaxPostmortemActionItems = 50
+)
+
+// Incident activity and postmortem errors.
+var (
+	ErrInvalidIncidentActivity   = errors.New("invalid incident activity")
+	ErrIncidentActivityImmutable = errors.New("status changes cannot be edited or deleted")
+	ErrInvalidPostmortem         = errors.New("invalid postmortem")
+	ErrPostmortemUnresolved      = errors.New("a postmortem can only be written once the incident is resolved")
+)
+
+// IncidentActivity is one entry in an incident's activity log. AuthorID is
+// nil when the author has since been deleted; AuthorEmail is filled on reads.
+type IncidentActivity struct {
+	ID          uuid.UUID
+	IncidentID  uuid.UUID
+	Kind        IncidentActivityKind
+	AuthorID    *uuid.UUID
+	AuthorEmail string
+	Body        string
+	URL         string
+	Status      IncidentStatus
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewIncidentNote creates a note or link entry. Links carry the URL and an
+// optional description in Body; notes require a body and no URL.
+func NewIncidentNote(incidentID, authorID uuid.UUID, kind IncidentActivityKind, body, link string) (*IncidentActivity, error) {
+	if !kind.IsEditable() {
+		return nil, fmt.Errorf("%w: kind must be note or link", ErrInvalidIncidentActivity)
+	}
+	now := time.Now()
+	a := &IncidentActivity{
+		ID:         uuid.New(),
+		IncidentID: incidentID,
+		Kind:       kind,
+		AuthorID:   &authorID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := a.Edit(body, link); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NewIncidentStatusChange records a responder moving an incident to status.
+func NewIncidentStatusChange(incidentID, authorID uuid.UUID, status IncidentStatus) *IncidentActivity {
+	now := time.Now()
+	return &IncidentActivity{
+		ID:         uuid.New(),
+		IncidentID: incidentID,
+		Kind:       IncidentActivityStatusChange,
+		AuthorID:   &authorID,
+		Status:     status,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Edit replaces the body and URL of a note or link after validating them.
+// The entry is left unchanged when validation fails.
+func (a *IncidentActivity) Edit(body, link string) error {
+	if !a.Kind.IsEditable() {
+		return ErrIncidentActivityImmutable
+	}
+	body = strings.TrimSpace(body)
+	link = strings.TrimSpace(link)
+	if len(body) > MaxIncidentNoteLength {
+		return fmt.Errorf("%w: body exceeds %d characters", ErrInvalidIncidentActivity, MaxIncidentNoteLength)
+	}
+	switch a.Kind {
+	case IncidentActivityNote:
+		if body == "" {
+			return fmt.Errorf("%w: body is required", ErrInvalidIncidentActivity)
+		}
+		if link != "" {
+			return fmt.Errorf("%w: notes cannot have a url", ErrInvalidIncidentActivity)
+		}
+	case IncidentActivityLink:
+		if len(link) > MaxIncidentLinkLength {
+			return fmt.Errorf("%w: url exceeds %d characters", ErrInvalidIncidentActivity, MaxIncidentLinkLength)
+		}
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidIncidentActivity)
+		}
+	}
+	a.Body = body
+	a.URL = link
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// TimelineEvent describes the entry for the investigation timeline.
+func (a *IncidentActivity) TimelineEvent() TimelineEvent {
+	ev := TimelineEvent{Time: a.CreatedAt, Severity: "info"}
+	switch a.Kind {
+	case IncidentActivityNote:
+		ev.Type = "incident_note"
+		ev.Description = "Note" + a.byline() + ": " + a.Body
+	case IncidentActivityLink:
+		ev.Type = "incident_link"
+		ev.Description = "Link" + a.byline() + ": " + a.URL
+		if a.Body != "" {
+			ev.Description += " (" + a.Body + ")"
+		}
+	case IncidentActivityStatusChange:
+		ev.Type = "incident_" + string(a.Status)
+		ev.Description = "Incident " + string(a.Status) + a.byline()
+		if a.Status == IncidentStatusAcknowledged {
+			ev.Severity = "warning"
+		}
+	}
+	return ev
+}
+
+func (a *IncidentActivity) byline() string {
+	if a.AuthorEmail == "" {
+		return ""
+	}
+	return " by " + a.AuthorEmail
+}
+
+// PostmortemActionItem is a follow-up task from a postmortem.
+type PostmortemActionItem struct {
+	Description string     `json:"description"`
+	Owner       string     `json:"owner"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Done        bool       `json:"done"`
+}
+
+// IncidentPostmortem is the structured review attached to a resolved
+// incident. There is at most one per incident; saving again replaces it.
+type IncidentPostmortem struct {
+	IncidentID  uuid.UUID
+	RootCause   string
+	Impact      string
+	ActionItems []PostmortemActionItem
+	AuthorID    *uuid.UUID
+	AuthorEmail string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Validate checks the postmortem's fields. Root cause and impact are
+// required and every action item needs a description and an owner.
+func (p *IncidentPostmortem) Validate() error {
+	p.RootCause = strings.TrimSpace(p.RootCause)
+	p.Impact = strings.TrimSpace(p.Impact)
+	if p.RootCause == "" {
+		return fmt.Errorf("%w: root_cause is required", ErrInvalidPostmortem)
+	}
+	if p.Impact == "" {
+		return fmt.Errorf("%w: impact is required", ErrInvalidPostmortem)
+	}
+	if len(p.RootCause) > MaxPostmortemTextLength || len(p.Impact) > MaxPostmortemTextLength {
+		return fmt.Errorf("%w: root_cause and impact must not exceed %d characters", ErrInvalidPostmortem, MaxPostmortemTextLength)
+	}
+	if len(p.ActionItems) > MaxPostmortemActionItems {
+		return fmt.Errorf("%w: at most %d action items are allowed", ErrInvalidPostmortem, MaxPostmortemActionItems)
+	}
+	for i := range p.ActionItems {
+		item := &p.ActionItems[i]
+		item.Description = strings.TrimSpace(item.Description)
+		item.Owner = strings.TrimSpace(item.Owner)
+		if item.Description == "" || item.Owner == "" {
+			return fmt.Errorf("%w: action_items[%d]: description and owner are required", ErrInvalidPostmortem, i)
+		}
+	}
+	return nil
+}
+
+// TimelineEvent describes the postmortem for the investigation timeline.
+func (p *IncidentPostmortem) TimelineEvent() TimelineEvent {
+	desc := "Postmortem written"
+	if p.AuthorEmail != "" {
+		desc += " by " + p.AuthorEmail
+	}
+	return TimelineEvent{
+		Time:        p.CreatedAt,
+		Type:        "incident_postmortem",
+		Description: desc,
+		Severity:    "info",
+	}
+}
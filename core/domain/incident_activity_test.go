@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIncidentNote(t *testing.T) {
+	incidentID, authorID := uuid.New(), uuid.New()
+
+	note, err := NewIncidentNote(incidentID, authorID, IncidentActivityNote, "  restarted the pool  ", "")
+	require.NoError(t, err)
+	assert.Equal(t, "restarted the pool", note.Body)
+	assert.Equal(t, authorID, *note.AuthorID)
+
+	link, err := NewIncidentNote(incidentID, authorID, IncidentActivityLink, "runbook", "https://wiki.example.com/db")
+	require.NoError(t, err)
+	assert.Equal(t, "https://wiki.example.com/db", link.URL)
+
+	tests := []struct {
+		name string
+		kind IncidentActivityKind
+		body string
+		link string
+	}{
+		{"empty note", IncidentActivityNote, "  ", ""},
+		{"note with url", IncidentActivityNote, "see", "https://example.com"},
+		{"note too long", IncidentActivityNote, strings.Repeat("x", MaxIncidentNoteLength+1), ""},
+		{"link without url", IncidentActivityLink, "runbook", ""},
+		{"relative link", IncidentActivityLink, "", "/runbooks/db"},
+		{"non-http link", IncidentActivityLink, "", "javascript:alert(1)"},
+		{"status change", IncidentActivityStatusChange, "resolved", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewIncidentNote(incidentID, authorID, tt.kind, tt.body, tt.link)
+			assert.True(t, errors.Is(err, ErrInvalidIncidentActivity), "got %v", err)
+		})
+	}
+}
+
+func TestIncidentActivity_Edit(t *testing.T) {
+	note, err := NewIncidentNote(uuid.New(), uuid.New(), IncidentActivityNote, "first", "")
+	require.NoError(t, err)
+
+	require.NoError(t, note.Edit("second", ""))
+	assert.Equal(t, "second", note.Body)
+
+	assert.Error(t, note.Edit("", ""))
+	assert.Equal(t, "second", note.Body, "a rejected edit leaves the note unchanged")
+
+	change := NewIncidentStatusChange(uuid.New(), uuid.New(), IncidentStatusResolved)
+	assert.ErrorIs(t, change.Edit("rewritten", ""), ErrIncidentActivityImmutable)
+}
+
+func TestIncidentActivity_TimelineEvent(t *testing.T) {
+	change := NewIncidentStatusChange(uuid.New(), uuid.New(), IncidentStatusAcknowledged)
+	change.AuthorEmail = "oncall@example.com"
+	ev := change.TimelineEvent()
+	assert.Equal(t, "incident_acknowledged", ev.Type)
+	assert.Equal(t, "Incident acknowledged by oncall@example.com", ev.Description)
+	assert.Equal(t, change.CreatedAt, ev.Time)
+
+	link, err := NewIncidentNote(uuid.New(), uuid.New(), IncidentActivityLink, "dashboard", "https://grafana.example.com/d/1")
+	require.NoError(t, err)
+	assert.Equal(t, "Link: https://grafana.example.com/d/1 (dashboard)", link.TimelineEvent().Description)
+}
+
+func TestIncidentPostmortem_Validate(t *testing.T) {
+	due := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	p := &IncidentPostmortem{
+		RootCause: " connection pool exhausted ",
+		Impact:    "checkout failed for 12 minutes",
+		ActionItems: []PostmortemActionItem{
+			{Description: "alert on pool saturation", Owner: "db-team", DueDate: &due},
+		},
+	}
+	require.NoError(t, p.Validate())
+	assert.Equal(t, "connection pool exhausted", p.RootCause)
+
+	tests := []struct {
+		name string
+		edit func(p *IncidentPostmortem)
+	}{
+		{"no root cause", func(p *IncidentPostmortem) { p.RootCause = "" }},
+		{"no impact", func(p *IncidentPostmortem) { p.Impact = " " }},
+		{"action item without owner", func(p *IncidentPostmortem) {
+			p.ActionItems = []PostmortemActionItem{{Description: "add alert"}}
+		}},
+		{"too many action items", func(p *IncidentPostmortem) {
+			p.ActionItems = make([]PostmortemActionItem, MaxPostmortemActionItems+1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := *p
+			tt.edit(&invalid)
+			assert.True(t, errors.Is(invalid.Validate(), ErrInvalidPostmortem))
+		})
+	}
+}
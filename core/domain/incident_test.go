@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +19,7 @@ func TestIncidentStatus_IsValid(t *testing.T) {
 		{IncidentStatusOpen, true},
 		{IncidentStatusAcknowledged, true},
 		{IncidentStatusResolved, true},
+		{IncidentStatusFlapping, true},
 		{IncidentStatus("invalid"), false},
 		{IncidentStatus(""), false},
 	}
@@ -37,6 +40,7 @@ func TestIncidentStatus_IsActive(t *testing.T) {
 		{IncidentStatusOpen, true},
 		{IncidentStatusAcknowledged, true},
 		{IncidentStatusResolved, false},
+		{IncidentStatusFlapping, true},
 	}
 
 	for _, tt := range tests {
@@ -61,9 +65,49 @@ func TestNewIncident(t *testing.T) {
 	assert.Nil(t, incident.AcknowledgedBy)
 	assert.Nil(t, incident.AcknowledgedAt)
 	assert.Equal(t, IncidentStatusOpen, incident.Status)
+	assert.Equal(t, IncidentKindDown, incident.Kind)
 	assert.False(t, incident.CreatedAt.IsZero())
 }
 
+func TestNewDegradedIncident(t *testing.T) {
+	incident := NewDegradedIncident(uuid.New())
+
+	assert.True(t, incident.IsDegraded())
+	assert.Equal(t, IncidentStatusOpen, incident.Status)
+	assert.Equal(t, MonitorStatusDegraded, incident.Kind.MonitorStatus())
+	assert.Equal(t, MonitorStatusDown, IncidentKindDown.MonitorStatus())
+}
+
+func TestIncident_DeriveSeverity(t *testing.T) {
+	m := NewMonitor(uuid.New(), "payments", MonitorTypeHTTP, "https://pay.example.com")
+	m.Severity = IncidentSeverityMajor
+
+	down := NewIncident(m.ID)
+	assert.Equal(t, DefaultIncidentSeverity, down.Severity)
+	down.DeriveSeverity(m)
+	assert.Equal(t, IncidentSeverityMajor, down.Severity)
+
+	degraded := NewDegradedIncident(m.ID)
+	degraded.DeriveSeverity(m)
+	assert.Equal(t, IncidentSeverityMinor, degraded.Severity, "degradation ranks below an outage")
+
+	m.Severity = IncidentSeverityInfo
+	degraded.DeriveSeverity(m)
+	assert.Equal(t, IncidentSeverityInfo, degraded.Severity)
+
+	m.Severity = ""
+	down.DeriveSeverity(m)
+	assert.Equal(t, DefaultIncidentSeverity, down.Severity)
+}
+
+func TestIncident_SetSeverity(t *testing.T) {
+	incident := NewIncident(uuid.New())
+	require.NoError(t, incident.SetSeverity(IncidentSeverityInfo))
+	assert.Equal(t, IncidentSeverityInfo, incident.Severity)
+	assert.ErrorIs(t, incident.SetSeverity("sev1"), ErrInvalidIncidentSeverity)
+	assert.Equal(t, IncidentSeverityInfo, incident.Severity)
+}
+
 func TestIncident_Acknowledge(t *testing.T) {
 	t.Run("acknowledge open incident", func(t *testing.T) {
 		incident := NewIncident(uuid.New())
@@ -178,3 +222,53 @@ func TestIncident_StatusChecks(t *testing.T) {
 	assert.True(t, incident.IsResolved())
 	assert.False(t, incident.IsActive())
 }
+
+func TestNewDeclaredIncident(t *testing.T) {
+	userID, monitorID := uuid.New(), uuid.New()
+
+	inc, err := NewDeclaredIncident(userID, "  Vendor outage ", "DNS provider down", "", []uuid.UUID{monitorID, monitorID})
+	require.NoError(t, err)
+	assert.True(t, inc.IsDeclared())
+	assert.Equal(t, uuid.Nil, inc.MonitorID)
+	assert.Equal(t, "Vendor outage", inc.Title)
+	assert.Equal(t, DefaultIncidentSeverity, inc.Severity)
+	assert.Equal(t, userID, *inc.OwnerID)
+	assert.Equal(t, []uuid.UUID{monitorID}, inc.AffectedMonitorIDs)
+	assert.True(t, inc.DeclaredBy(userID))
+	assert.False(t, inc.DeclaredBy(uuid.New()))
+	assert.False(t, NewIncident(monitorID).DeclaredBy(userID))
+
+	tests := []struct {
+		name     string
+		title    string
+		desc     string
+		severity IncidentSeverity
+		monitors int
+	}{
+		{"missing title", " ", "", "", 0},
+		{"long title", strings.Repeat("x", MaxDeclaredIncidentTitleLength+1), "", "", 0},
+		{"long description", "t", strings.Repeat("x", MaxDeclaredIncidentDescriptionLength+1), "", 0},
+		{"bad severity", "t", "", "sev1", 0},
+		{"too many monitors", "t", "", "", MaxDeclaredIncidentMonitors + 1},
+	}
+	for _, tt := range tests {
+		ids := make([]uuid.UUID, tt.monitors)
+		for i := range ids {
+			ids[i] = uuid.New()
+		}
+		_, err := NewDeclaredIncident(userID, tt.title, tt.desc, tt.severity, ids)
+		assert.True(t, errors.Is(err, ErrInvalidDeclaredIncident), tt.name)
+	}
+}
+
+func TestIncident_DeclaredMonitor(t *testing.T) {
+	inc, err := NewDeclaredIncident(uuid.New(), "Security event", strings.Repeat("é", 400), IncidentSeverityCritical, nil)
+	require.NoError(t, err)
+
+	m := inc.DeclaredMonitor()
+	assert.Equal(t, "Security event", m.Name)
+	assert.Equal(t, IncidentSeverityCritical, m.Severity)
+	assert.Equal(t, MonitorStatusDown, m.Status)
+	assert.Equal(t, 300, len([]rune(m.Target)))
+	assert.True(t, strings.HasSuffix(m.Target, "…"))
+}
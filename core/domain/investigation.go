@@ -22,6 +22,12 @@ type IncidentInvestigation struct {
 	SystemMetrics     []SystemMetricSnapshot `json:"system_metrics"`
 	CertDetails       *CertDetails         `json:"cert_details,omitempty"`
 	Timeline          []TimelineEvent      `json:"timeline"`
+	Locations         []LocationStatus     `json:"locations,omitempty"`
+	// DependencyTree is rooted at the incident's monitor, with upstream
+	// dependencies under Parents and dependents under Children.
+	DependencyTree     *DependencyNode     `json:"dependency_tree,omitempty"`
+	RootCauseMonitorID *uuid.UUID          `json:"root_cause_monitor_id,omitempty"`
+	Postmortem         *IncidentPostmortem `json:"postmortem,omitempty"`
 }
 
 // AgentSummary is a safe-to-serialize subset of Agent for API responses.
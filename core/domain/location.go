@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxMonitorLocations caps how many agents may probe a single monitor.
+const MaxMonitorLocations = 10
+
+// locationStaleIntervals is how many check intervals may pass without a
+// heartbeat before a location's verdict is considered unknown.
+const locationStaleIntervals = 3
+
+// LocationState is the verdict for a monitor as seen from one probing agent.
+type LocationState string
+
+const (
+	LocationStateUp      LocationState = "up"
+	LocationStateDown    LocationState = "down"
+	LocationStateUnknown LocationState = "unknown"
+)
+
+// LocationStatus summarises a monitor's recent checks from one probing agent.
+type LocationStatus struct {
+	AgentID     uuid.UUID     `json:"agent_id"`
+	AgentName   string        `json:"agent_name"`
+	State       LocationState `json:"state"`
+	Checks      int           `json:"checks"`
+	Failures    int           `json:"failures"`
+	LatencyMs   *int          `json:"latency_ms,omitempty"`
+	LastCheckAt *time.Time    `json:"last_check_at,omitempty"`
+	LastError   *string       `json:"last_error,omitempty"`
+}
+
+// SetLocations assigns the set of agents that probe this monitor and the
+// quorum of failing locations needed to declare it down. The owning agent is
+// always part of the set. An empty set reverts to single-agent checks; a
+// quorum of zero means a simple majority.
+func (m *Monitor) SetLocations(agentIDs []uuid.UUID, quorum int) bool {
+	if len(agentIDs) == 0 {
+		m.LocationAgentIDs = nil
+		m.Quorum = 0
+		return true
+	}
+
+	set := []uuid.UUID{m.AgentID}
+	seen := map[uuid.UUID]bool{m.AgentID: true}
+	for _, id := range agentIDs {
+		if !seen[id] {
+			seen[id] = true
+			set = append(set, id)
+		}
+	}
+	if len(set) > MaxMonitorLocations || quorum < 0 || quorum > len(set) {
+		return false
+	}
+
+	m.LocationAgentIDs = set
+	m.Quorum = quorum
+	return true
+}
+
+// IsMultiLocation returns true if more than one agent probes the monitor.
+func (m *Monitor) IsMultiLocation() bool {
+	return len(m.LocationAgentIDs) > 1
+}
+
+// ProbeAgentIDs returns every agent that should run this monitor's check.
+func (m *Monitor) ProbeAgentIDs() []uuid.UUID {
+	if m.IsMultiLocation() {
+		return m.LocationAgentIDs
+	}
+	return []uuid.UUID{m.AgentID}
+}
+
+// EffectiveQuorum returns the number of failing locations that marks the
+// monitor down, defaulting to a simple majority.
+func (m *Monitor) EffectiveQuorum() int {
+	n := len(m.ProbeAgentIDs())
+	if m.Quorum > 0 && m.Quorum <= n {
+		return m.Quorum
+	}
+	return n/2 + 1
+}
+
+// LocationBreakdown groups heartbeats (newest first) by probing agent and
+// derives each location's state as of asOf. A location is down when its
+// last FailureThreshold checks all failed, and unknown when it has not
+// reported within a few check intervals.
+func (m *Monitor) LocationBreakdown(heartbeats []*Heartbeat, asOf time.Time) []LocationStatus {
+	byAgent := heartbeatsByAgent(heartbeats)
+
+	threshold := m.FailureThreshold
+	if threshold < 1 {
+		threshold = DefaultFailureThreshold
+	}
+	stale := time.Duration(m.IntervalSeconds*locationStaleIntervals) * time.Second
+
+	agentIDs := m.ProbeAgentIDs()
+	locations := make([]LocationStatus, 0, len(agentIDs))
+	for _, agentID := range agentIDs {
+		recent := byAgent[agentID]
+		loc := LocationStatus{AgentID: agentID, State: LocationStateUnknown, Checks: len(recent)}
+		for _, hb := range recent {
+			if !hb.IsSuccess() {
+				loc.Failures++
+			}
+		}
+		if len(recent) > 0 {
+			latest := recent[0]
+			lastCheck := latest.Time
+			loc.LastCheckAt = &lastCheck
+			loc.LatencyMs = latest.LatencyMs
+			loc.LastError = latest.ErrorMessage
+			if stale == 0 || asOf.Sub(latest.Time) <= stale {
+				loc.State = locationState(recent, threshold)
+			}
+		}
+		locations = append(locations, loc)
+	}
+	return locations
+}
+
+// locationState returns down when the newest threshold heartbeats all failed.
+func locationState(recent []*Heartbeat, threshold int) LocationState {
+	if len(recent) < threshold {
+		if recent[0].IsSuccess() {
+			return LocationStateUp
+		}
+		return LocationStateUnknown
+	}
+	for _, hb := range recent[:threshold] {
+		if hb.IsSuccess() {
+			return LocationStateUp
+		}
+	}
+	return LocationStateDown
+}
+
+// EvaluateQuorum counts failing locations and reports whether they reach the
+// monitor's quorum.
+func (m *Monitor) EvaluateQuorum(locations []LocationStatus) (failing int, down bool) {
+	for _, loc := range locations {
+		if loc.State == LocationStateDown {
+			failing++
+		}
+	}
+	return failing, failing >= m.EffectiveQuorum()
+}
+
+// LocationsFlapping reports whether a quorum of the monitor's locations is
+// flapping, given each location's recent heartbeats (newest first). Each
+// location is judged on its own checks, so one steadily failing location
+// does not make the interleaved checks of all of them look like flapping.
+func (m *Monitor) LocationsFlapping(heartbeats []*Heartbeat) bool {
+	byAgent := heartbeatsByAgent(heartbeats)
+	flapping := 0
+	for _, agentID := range m.ProbeAgentIDs() {
+		if m.IsFlapping(byAgent[agentID]) {
+			flapping++
+		}
+	}
+	return flapping >= m.EffectiveQuorum()
+}
+
+// LocationsRecovered reports whether a down multi-location monitor has
+// recovered, given each location's recent heartbeats (newest first): the
+// locations whose last RecoveryThreshold checks did not all succeed are
+// below the quorum.
+func (m *Monitor) LocationsRecovered(heartbeats []*Heartbeat) bool {
+	threshold := max(m.RecoveryThreshold, 1)
+	byAgent := heartbeatsByAgent(heartbeats)
+	unrecovered := 0
+	for _, agentID := range m.ProbeAgentIDs() {
+		if !allSucceeded(byAgent[agentID], threshold) {
+			unrecovered++
+		}
+	}
+	return unrecovered < m.EffectiveQuorum()
+}
+
+// allSucceeded reports whether the newest n heartbeats all succeeded.
+func allSucceeded(recent []*Heartbeat, n int) bool {
+	if len(recent) < n {
+		return false
+	}
+	for _, hb := range recent[:n] {
+		if !hb.IsSuccess() {
+			return false
+		}
+	}
+	return true
+}
+
+// heartbeatsByAgent groups heartbeats by probing agent, keeping their order.
+func heartbeatsByAgent(heartbeats []*Heartbeat) map[uuid.UUID][]*Heartbeat {
+	byAgent := make(map[uuid.UUID][]*Heartbeat)
+	for _, hb := range heartbeats {
+		byAgent[hb.AgentID] = append(byAgent[hb.AgentID], hb)
+	}
+	return byAgent
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor_SetLocations(t *testing.T) {
+	owner, a, b := uuid.New(), uuid.New(), uuid.New()
+	monitor := NewMonitor(owner, "test", MonitorTypeHTTP, "https://example.com")
+
+	assert.False(t, monitor.IsMultiLocation())
+	assert.Equal(t, []uuid.UUID{owner}, monitor.ProbeAgentIDs())
+
+	require.True(t, monitor.SetLocations([]uuid.UUID{a, b, a}, 0))
+	assert.Equal(t, []uuid.UUID{owner, a, b}, monitor.LocationAgentIDs, "owner is first and duplicates are dropped")
+	assert.True(t, monitor.IsMultiLocation())
+	assert.Equal(t, 2, monitor.EffectiveQuorum(), "majority of three")
+
+	assert.False(t, monitor.SetLocations([]uuid.UUID{a, b}, 4), "quorum above location count")
+
+	require.True(t, monitor.SetLocations(nil, 0))
+	assert.False(t, monitor.IsMultiLocation())
+}
+
+func TestMonitor_LocationBreakdown(t *testing.T) {
+	owner, a, b := uuid.New(), uuid.New(), uuid.New()
+	monitorID := uuid.New()
+	monitor := NewMonitor(owner, "test", MonitorTypeHTTP, "https://example.com")
+	monitor.FailureThreshold = 2
+	require.True(t, monitor.SetLocations([]uuid.UUID{a, b}, 2))
+
+	now := time.Now()
+	hb := func(agentID uuid.UUID, ok bool, age time.Duration) *Heartbeat {
+		h := NewFailureHeartbeat(monitorID, agentID, HeartbeatStatusDown, "timeout")
+		if ok {
+			h = NewSuccessHeartbeat(monitorID, agentID, 40)
+		}
+		h.Time = now.Add(-age)
+		return h
+	}
+
+	heartbeats := []*Heartbeat{
+		hb(owner, false, 10*time.Second),
+		hb(a, false, 20*time.Second),
+		hb(owner, false, 70*time.Second),
+		hb(a, true, 80*time.Second),
+		hb(b, false, time.Hour),
+		hb(b, false, time.Hour+time.Minute),
+	}
+
+	locations := monitor.LocationBreakdown(heartbeats, now)
+	require.Len(t, locations, 3)
+	assert.Equal(t, LocationStateDown, locations[0].State)
+	assert.Equal(t, 2, locations[0].Failures)
+	assert.Equal(t, LocationStateUp, locations[1].State, "one failure is below the threshold")
+	assert.Equal(t, LocationStateUnknown, locations[2].State, "stale location")
+
+	failing, down := monitor.EvaluateQuorum(locations)
+	assert.Equal(t, 1, failing)
+	assert.False(t, down)
+
+	heartbeats = append([]*Heartbeat{hb(a, false, 5*time.Second)}, heartbeats...)
+	failing, down = monitor.EvaluateQuorum(monitor.LocationBreakdown(heartbeats, now))
+	assert.Equal(t, 2, failing)
+	assert.True(t, down)
+}
+
+func TestMonitor_LocationsFlappingAndRecovered(t *testing.T) {
+	owner, a, b := uuid.New(), uuid.New(), uuid.New()
+	monitorID := uuid.New()
+	monitor := NewMonitor(owner, "test", MonitorTypeHTTP, "https://example.com")
+	require.True(t, monitor.SetLocations([]uuid.UUID{a, b}, 2))
+	monitor.SetFlapDetection(4, 50)
+	monitor.SetRecoveryThreshold(2)
+
+	up := func(agentID uuid.UUID) *Heartbeat { return NewSuccessHeartbeat(monitorID, agentID, 40) }
+	down := func(agentID uuid.UUID) *Heartbeat { return NewFailureHeartbeat(monitorID, agentID, HeartbeatStatusDown, "timeout") }
+
+	// b is steadily down: interleaved with the others it alternates, but on
+	// its own it does not flap.
+	heartbeats := []*Heartbeat{
+		up(owner), down(b), up(a),
+		up(owner), down(b), up(a),
+		up(owner), down(b), up(a),
+		up(owner), down(b), up(a),
+	}
+	assert.False(t, monitor.LocationsFlapping(heartbeats))
+	assert.True(t, monitor.LocationsRecovered(heartbeats), "one unrecovered location is below the quorum")
+
+	heartbeats = []*Heartbeat{
+		up(owner), up(a), down(b),
+		down(owner), down(a), down(b),
+		up(owner), up(a), down(b),
+		down(owner), down(a), down(b),
+	}
+	assert.True(t, monitor.LocationsFlapping(heartbeats))
+	assert.False(t, monitor.LocationsRecovered(heartbeats))
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message template limits.
+const (
+	MaxMessageTemplates       = 100
+	MaxMessageTemplateSubject = 1024
+	MaxMessageTemplateBody    = 8 * 1024
+)
+
+// ErrInvalidMessageTemplate is returned when a message template fails
+// validation or does not render.
+var ErrInvalidMessageTemplate = errors.New("invalid message template")
+
+// TemplateEventAny is the event of a template used for every event that has
+// no template of its own.
+const TemplateEventAny = ""
+
+// ValidTemplateEvent reports whether event can have a message template: an
+// AlertEventKind, DeferredEventMaintenance, NotificationEventDigest or
+// TemplateEventAny.
+func ValidTemplateEvent(event string) bool {
+	switch event {
+	case TemplateEventAny, DeferredEventMaintenance, NotificationEventDigest:
+		return true
+	}
+	return AlertEventKind(event).IsValid()
+}
+
+// TemplatableChannelTypes are the channel types whose messages can be
+// templated. PagerDuty, Opsgenie and Splunk On-Call events have a fixed
+// schema and are not.
+var TemplatableChannelTypes = map[AlertChannelType]bool{
+	AlertChannelSlack:      true,
+	AlertChannelDiscord:    true,
+	AlertChannelTelegram:   true,
+	AlertChannelEmail:      true,
+	AlertChannelWebhook:    true,
+	AlertChannelTeams:      true,
+	AlertChannelMattermost: true,
+}
+
+// MessageTemplate overrides the built-in layout of a notification. A
+// template with a ChannelID applies to that channel only; one without
+// applies to all of the user's channels of ChannelType. For each, a template
+// for the event wins over one for TemplateEventAny.
+//
+// Subject and Body are Go templates. Subject is the Slack, Discord, Teams
+// and Mattermost title and the email subject; Telegram and webhooks ignore
+// it. Body is an HTML
+// template for email and a text template otherwise; for webhooks it is the
+// JSON request body.
+type MessageTemplate struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	ChannelType AlertChannelType
+	ChannelID   *uuid.UUID
+	Event       string
+	Subject     string
+	Body        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewMessageTemplate creates a validated message template.
+func NewMessageTemplate(userID uuid.UUID, channelType AlertChannelType, channelID *uuid.UUID, event, subject, body string) (*MessageTemplate, error) {
+	now := time.Now()
+	t := &MessageTemplate{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ChannelType: channelType,
+		ChannelID:   channelID,
+		Event:       event,
+		Subject:     subject,
+		Body:        body,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Validate checks the template's scope, event and size. Template syntax is
+// checked by the notifiers that render it.
+func (t *MessageTemplate) Validate() error {
+	if !TemplatableChannelTypes[t.ChannelType] {
+		return fmt.Errorf("%w: channel type %q does not support templates", ErrInvalidMessageTemplate, t.ChannelType)
+	}
+	if !ValidTemplateEvent(t.Event) {
+		return fmt.Errorf("%w: unknown event %q", ErrInvalidMessageTemplate, t.Event)
+	}
+	if t.Body == "" {
+		return fmt.Errorf("%w: body is required", ErrInvalidMessageTemplate)
+	}
+	if len(t.Subject) > MaxMessageTemplateSubject {
+		return fmt.Errorf("%w: subject must be at most %d bytes", ErrInvalidMessageTemplate, MaxMessageTemplateSubject)
+	}
+	if len(t.Body) > MaxMessageTemplateBody {
+		return fmt.Errorf("%w: body must be at most %d bytes", ErrInvalidMessageTemplate, MaxMessageTemplateBody)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageTemplate_Validation(t *testing.T) {
+	user := uuid.New()
+	tests := []struct {
+		name        string
+		channelType AlertChannelType
+		event       string
+		subject     string
+		body        string
+		ok          bool
+	}{
+		{"any event", AlertChannelSlack, TemplateEventAny, "", "{{.Title}}", true},
+		{"incident event", AlertChannelEmail, string(AlertEventIncidentOpened), "{{.Title}}", "<p>{{.Title}}</p>", true},
+		{"maintenance", AlertChannelTelegram, DeferredEventMaintenance, "", "{{.Agent.Window}}", true},
+		{"digest", AlertChannelWebhook, NotificationEventDigest, "", "{}", true},
+		{"pagerduty", AlertChannelPagerDuty, TemplateEventAny, "", "{{.Title}}", false},
+		{"unknown event", AlertChannelDiscord, "opened", "", "{{.Title}}", false},
+		{"empty body", AlertChannelDiscord, TemplateEventAny, "{{.Title}}", "", false},
+		{"long subject", AlertChannelEmail, TemplateEventAny, strings.Repeat("x", MaxMessageTemplateSubject+1), "x", false},
+		{"long body", AlertChannelSlack, TemplateEventAny, "", strings.Repeat("x", MaxMessageTemplateBody+1), false},
+	}
+	for _, tt := range tests {
+		tmpl, err := NewMessageTemplate(user, tt.channelType, nil, tt.event, tt.subject, tt.body)
+		if !tt.ok {
+			assert.True(t, errors.Is(err, ErrInvalidMessageTemplate), tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, user, tmpl.UserID, tt.name)
+		assert.Equal(t, tt.event, tmpl.Event, tt.name)
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,24 +11,26 @@ import (
 type MonitorType string
 
 const (
-	MonitorTypePing     MonitorType = "ping"
-	MonitorTypeHTTP     MonitorType = "http"
-	MonitorTypeTCP      MonitorType = "tcp"
-	MonitorTypeDNS      MonitorType = "dns"
-	MonitorTypeTLS      MonitorType = "tls"
-	MonitorTypeDocker   MonitorType = "docker"
-	MonitorTypeDatabase MonitorType = "database"
-	MonitorTypeSystem   MonitorType = "system"
-	MonitorTypeService  MonitorType = "service"
-	MonitorTypePortScan MonitorType = "port_scan"
-	MonitorTypeSNMP     MonitorType = "snmp"
+	MonitorTypePing        MonitorType = "ping"
+	MonitorTypeHTTP        MonitorType = "http"
+	MonitorTypeTCP         MonitorType = "tcp"
+	MonitorTypeDNS         MonitorType = "dns"
+	MonitorTypeTLS         MonitorType = "tls"
+	MonitorTypeDocker      MonitorType = "docker"
+	MonitorTypeDatabase    MonitorType = "database"
+	MonitorTypeSystem      MonitorType = "system"
+	MonitorTypeService     MonitorType = "service"
+	MonitorTypePortScan    MonitorType = "port_scan"
+	MonitorTypeSNMP        MonitorType = "snmp"
+	MonitorTypePush        MonitorType = "push"
+	MonitorTypeTransaction MonitorType = "transaction"
 )
 
 // ValidMonitorTypes lists all valid monitor types.
 var ValidMonitorTypes = []MonitorType{
 	MonitorTypePing, MonitorTypeHTTP, MonitorTypeTCP, MonitorTypeDNS, MonitorTypeTLS,
 	MonitorTypeDocker, MonitorTypeDatabase, MonitorTypeSystem, MonitorTypeService,
-	MonitorTypePortScan, MonitorTypeSNMP,
+	MonitorTypePortScan, MonitorTypeSNMP, MonitorTypePush, MonitorTypeTransaction,
 }
 
 // ValidMonitorTypeStrings returns monitor types as strings (for templates).
@@ -44,7 +47,18 @@ func (t MonitorType) IsValid() bool {
 	switch t {
 	case MonitorTypePing, MonitorTypeHTTP, MonitorTypeTCP, MonitorTypeDNS, MonitorTypeTLS,
 		MonitorTypeDocker, MonitorTypeDatabase, MonitorTypeSystem, MonitorTypeService,
-		MonitorTypePortScan, MonitorTypeSNMP:
+		MonitorTypePortScan, MonitorTypeSNMP, MonitorTypePush, MonitorTypeTransaction:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsHubProbeable returns true if checks of this type can run inside the hub.
+// Only checks against network targets qualify; the rest need local access.
+func (t MonitorType) IsHubProbeable() bool {
+	switch t {
+	case MonitorTypePing, MonitorTypeHTTP, MonitorTypeTCP, MonitorTypeDNS, MonitorTypeTLS, MonitorTypeTransaction:
 		return true
 	default:
 		return false
@@ -90,7 +104,54 @@ type Monitor struct {
 	FailureThreshold  int
 	Metadata          map[string]string
 	SLATargetPercent  *float64
+	// Severity is the default severity of the monitor's incidents.
+	Severity          IncidentSeverity
+	// EscalationPolicyID selects the escalation policy paged while the
+	// monitor's incidents stay unacknowledged. When nil, the policy named by
+	// the EscalationPolicyTag tag is used, if any.
+	EscalationPolicyID *uuid.UUID
 	CreatedAt         time.Time
+
+	// Degraded rules. A monitor that keeps answering checks but breaches
+	// one of these is reported as degraded instead of up. Nil disables the rule.
+	DegradedLatencyMs      *int // latency ceiling in milliseconds
+	DegradedLatencyChecks  int  // consecutive checks above the ceiling
+	DegradedFailurePercent *int // failure percentage within the window
+	DegradedWindow         int  // sliding window size in checks
+
+	// RecoveryThreshold is the number of consecutive successful checks
+	// required to resolve an incident (mirrors FailureThreshold).
+	RecoveryThreshold int
+
+	// Flap detection. A monitor whose state changes in at least
+	// FlapThresholdPercent of its last FlapWindow checks is flapping.
+	// A FlapWindow of zero disables detection.
+	FlapWindow           int
+	FlapThresholdPercent int
+
+	// Multi-location checks. LocationAgentIDs lists every agent probing the
+	// target (including AgentID); when more than one agent is listed,
+	// incidents follow a quorum of failing locations instead of a single agent.
+	LocationAgentIDs []uuid.UUID
+	Quorum           int // failing locations that mark the monitor down; 0 means majority
+
+	// Push monitors. Instead of being checked by an agent, the watched job
+	// pings the hub at PushToken's ingest URL; the hub opens an incident when
+	// no ping arrives within IntervalSeconds + PushGraceSeconds.
+	PushToken        string
+	PushGraceSeconds int
+	LastPingAt       *time.Time
+	PushStartedAt    *time.Time // last start signal; a run is in progress while newer than LastPingAt
+
+	// Assertions are evaluated on the hub against the response an HTTP check
+	// reports. The first one that fails turns the check into a failure.
+	Assertions []Assertion
+
+	// Transaction monitors run an ordered list of HTTP steps instead of a
+	// single check against Target. LastTransactionRun holds the per-step
+	// results of the latest reported run.
+	Transaction        *Transaction
+	LastTransactionRun *TransactionRun
 }
 
 // Default values for monitor configuration.
@@ -104,6 +165,20 @@ const (
 	DefaultFailureThreshold = 3
 	MinFailureThreshold     = 1
 	MaxFailureThreshold     = 20
+
+	DefaultDegradedLatencyChecks = 3
+	MinDegradedLatencyChecks     = 1
+	MaxDegradedLatencyChecks     = 20
+	DefaultDegradedWindow        = 10
+	MinDegradedWindow            = 2
+	MaxDegradedWindow            = 100
+
+	DefaultRecoveryThreshold    = 1
+	MinRecoveryThreshold        = 1
+	MaxRecoveryThreshold        = 20
+	MinFlapWindow               = 4
+	MaxFlapWindow               = 100
+	DefaultFlapThresholdPercent = 50
 )
 
 // NewMonitor creates a new Monitor with default settings.
@@ -120,7 +195,14 @@ func NewMonitor(agentID uuid.UUID, name string, monitorType MonitorType, target
 		Enabled:          true,
 		FailureThreshold: DefaultFailureThreshold,
 		Metadata:         make(map[string]string),
+		Severity:         DefaultIncidentSeverity,
 		CreatedAt:        time.Now(),
+
+		DegradedLatencyChecks: DefaultDegradedLatencyChecks,
+		DegradedWindow:        DefaultDegradedWindow,
+
+		RecoveryThreshold:    DefaultRecoveryThreshold,
+		FlapThresholdPercent: DefaultFlapThresholdPercent,
 	}
 }
 
@@ -142,6 +224,134 @@ func (m *Monitor) SetTimeout(seconds int) bool {
 	return true
 }
 
+// SetDegradedLatency configures the latency rule: the monitor is degraded once
+// thresholdMs is exceeded for checks consecutive heartbeats. A thresholdMs of
+// zero disables the rule.
+func (m *Monitor) SetDegradedLatency(thresholdMs, checks int) bool {
+	if thresholdMs == 0 {
+		m.DegradedLatencyMs = nil
+		return true
+	}
+	if thresholdMs < 0 || checks < MinDegradedLatencyChecks || checks > MaxDegradedLatencyChecks {
+		return false
+	}
+	m.DegradedLatencyMs = &thresholdMs
+	m.DegradedLatencyChecks = checks
+	return true
+}
+
+// SetDegradedFailureRate configures the partial-failure rule: the monitor is
+// degraded once percent of the last window heartbeats failed. A percent of
+// zero disables the rule.
+func (m *Monitor) SetDegradedFailureRate(percent, window int) bool {
+	if percent == 0 {
+		m.DegradedFailurePercent = nil
+		return true
+	}
+	if percent < 1 || percent > 100 || window < MinDegradedWindow || window > MaxDegradedWindow {
+		return false
+	}
+	m.DegradedFailurePercent = &percent
+	m.DegradedWindow = window
+	return true
+}
+
+// HasDegradedRules returns true if at least one degraded rule is configured.
+func (m *Monitor) HasDegradedRules() bool {
+	return m.DegradedLatencyMs != nil || m.DegradedFailurePercent != nil
+}
+
+// DegradedHistorySize returns how many recent heartbeats EvaluateDegraded needs.
+func (m *Monitor) DegradedHistorySize() int {
+	n := 0
+	if m.DegradedLatencyMs != nil {
+		n = m.DegradedLatencyChecks
+	}
+	if m.DegradedFailurePercent != nil && m.DegradedWindow > n {
+		n = m.DegradedWindow
+	}
+	return n
+}
+
+// EvaluateDegraded checks the degraded rules against recent heartbeats
+// (newest first). It returns whether the monitor is degraded and a short
+// human-readable reason naming the rule that tripped.
+func (m *Monitor) EvaluateDegraded(recent []*Heartbeat) (bool, string) {
+	if m.DegradedLatencyMs != nil && m.DegradedLatencyChecks > 0 && len(recent) >= m.DegradedLatencyChecks {
+		slow := true
+		for _, hb := range recent[:m.DegradedLatencyChecks] {
+			if !hb.IsSuccess() || hb.LatencyMs == nil || *hb.LatencyMs <= *m.DegradedLatencyMs {
+				slow = false
+				break
+			}
+		}
+		if slow {
+			return true, fmt.Sprintf("latency above %dms for %d consecutive checks", *m.DegradedLatencyMs, m.DegradedLatencyChecks)
+		}
+	}
+
+	if m.DegradedFailurePercent != nil && m.DegradedWindow > 0 && len(recent) >= m.DegradedWindow {
+		failures := 0
+		for _, hb := range recent[:m.DegradedWindow] {
+			if hb.Status.IsFailure() {
+				failures++
+			}
+		}
+		if failures*100 >= *m.DegradedFailurePercent*m.DegradedWindow {
+			return true, fmt.Sprintf("%d of the last %d checks failed", failures, m.DegradedWindow)
+		}
+	}
+
+	return false, ""
+}
+
+// SetRecoveryThreshold sets the consecutive successes needed to resolve an incident.
+func (m *Monitor) SetRecoveryThreshold(n int) bool {
+	if n < MinRecoveryThreshold || n > MaxRecoveryThreshold {
+		return false
+	}
+	m.RecoveryThreshold = n
+	return true
+}
+
+// SetFlapDetection configures the flap detector. A window of zero disables it.
+func (m *Monitor) SetFlapDetection(window, thresholdPercent int) bool {
+	if window == 0 {
+		m.FlapWindow = 0
+		return true
+	}
+	if window < MinFlapWindow || window > MaxFlapWindow || thresholdPercent < 1 || thresholdPercent > 100 {
+		return false
+	}
+	m.FlapWindow = window
+	m.FlapThresholdPercent = thresholdPercent
+	return true
+}
+
+// StateChangeRate returns the percentage of consecutive heartbeat pairs whose
+// up/down state differs. Fewer than two heartbeats yields zero.
+func StateChangeRate(heartbeats []*Heartbeat) int {
+	if len(heartbeats) < 2 {
+		return 0
+	}
+	changes := 0
+	for i := 1; i < len(heartbeats); i++ {
+		if heartbeats[i].IsSuccess() != heartbeats[i-1].IsSuccess() {
+			changes++
+		}
+	}
+	return changes * 100 / (len(heartbeats) - 1)
+}
+
+// IsFlapping reports whether the monitor is flapping given its recent
+// heartbeats (newest first). A full window is required before flapping is declared.
+func (m *Monitor) IsFlapping(recent []*Heartbeat) bool {
+	if m.FlapWindow == 0 || len(recent) < m.FlapWindow {
+		return false
+	}
+	return StateChangeRate(recent[:m.FlapWindow]) >= m.FlapThresholdPercent
+}
+
 // UpdateStatus updates the monitor status.
 func (m *Monitor) UpdateStatus(status MonitorStatus) {
 	m.Status = status
@@ -173,3 +173,83 @@ func TestMonitor_UpdateStatus(t *testing.T) {
 	monitor.UpdateStatus(MonitorStatusDown)
 	assert.Equal(t, MonitorStatusDown, monitor.Status)
 }
+
+func TestMonitor_SetDegradedLatency(t *testing.T) {
+	monitor := NewMonitor(uuid.New(), "test", MonitorTypeHTTP, "https://example.com")
+
+	assert.False(t, monitor.SetDegradedLatency(-1, 3))
+	assert.False(t, monitor.SetDegradedLatency(500, MaxDegradedLatencyChecks+1))
+	assert.Nil(t, monitor.DegradedLatencyMs)
+
+	require.True(t, monitor.SetDegradedLatency(500, 2))
+	require.NotNil(t, monitor.DegradedLatencyMs)
+	assert.Equal(t, 500, *monitor.DegradedLatencyMs)
+	assert.Equal(t, 2, monitor.DegradedLatencyChecks)
+	assert.True(t, monitor.HasDegradedRules())
+
+	require.True(t, monitor.SetDegradedLatency(0, 0))
+	assert.Nil(t, monitor.DegradedLatencyMs)
+	assert.False(t, monitor.HasDegradedRules())
+}
+
+func TestMonitor_EvaluateDegraded(t *testing.T) {
+	monitorID, agentID := uuid.New(), uuid.New()
+	up := func(ms int) *Heartbeat { return NewSuccessHeartbeat(monitorID, agentID, ms) }
+	down := func() *Heartbeat { return NewFailureHeartbeat(monitorID, agentID, HeartbeatStatusDown, "err") }
+
+	t.Run("no rules", func(t *testing.T) {
+		monitor := NewMonitor(agentID, "test", MonitorTypeHTTP, "https://example.com")
+		degraded, _ := monitor.EvaluateDegraded([]*Heartbeat{up(9000), up(9000), up(9000)})
+		assert.False(t, degraded)
+	})
+
+	t.Run("latency above threshold for K checks", func(t *testing.T) {
+		monitor := NewMonitor(agentID, "test", MonitorTypeHTTP, "https://example.com")
+		require.True(t, monitor.SetDegradedLatency(500, 3))
+
+		degraded, reason := monitor.EvaluateDegraded([]*Heartbeat{up(800), up(900), up(700)})
+		assert.True(t, degraded)
+		assert.Contains(t, reason, "500ms")
+
+		degraded, _ = monitor.EvaluateDegraded([]*Heartbeat{up(800), up(100), up(700)})
+		assert.False(t, degraded, "a fast check breaks the streak")
+
+		degraded, _ = monitor.EvaluateDegraded([]*Heartbeat{up(800), up(900)})
+		assert.False(t, degraded, "not enough history")
+	})
+
+	t.Run("failure percentage in window", func(t *testing.T) {
+		monitor := NewMonitor(agentID, "test", MonitorTypeHTTP, "https://example.com")
+		require.True(t, monitor.SetDegradedFailureRate(50, 4))
+		assert.Equal(t, 4, monitor.DegradedHistorySize())
+
+		degraded, reason := monitor.EvaluateDegraded([]*Heartbeat{down(), up(10), down(), up(10)})
+		assert.True(t, degraded)
+		assert.Equal(t, "2 of the last 4 checks failed", reason)
+
+		degraded, _ = monitor.EvaluateDegraded([]*Heartbeat{down(), up(10), up(10), up(10)})
+		assert.False(t, degraded)
+	})
+}
+
+func TestMonitor_IsFlapping(t *testing.T) {
+	monitorID, agentID := uuid.New(), uuid.New()
+	up := func() *Heartbeat { return NewSuccessHeartbeat(monitorID, agentID, 10) }
+	down := func() *Heartbeat { return NewFailureHeartbeat(monitorID, agentID, HeartbeatStatusDown, "err") }
+
+	monitor := NewMonitor(agentID, "test", MonitorTypeHTTP, "https://example.com")
+	assert.Equal(t, DefaultRecoveryThreshold, monitor.RecoveryThreshold)
+	assert.False(t, monitor.IsFlapping([]*Heartbeat{up(), down(), up(), down()}), "disabled by default")
+
+	assert.False(t, monitor.SetFlapDetection(2, 50), "window below minimum")
+	assert.False(t, monitor.SetFlapDetection(6, 0), "threshold below minimum")
+	require.True(t, monitor.SetFlapDetection(5, 50))
+
+	assert.True(t, monitor.IsFlapping([]*Heartbeat{up(), down(), up(), down(), up()}))
+	assert.True(t, monitor.IsFlapping([]*Heartbeat{up(), down(), up(), up(), up()}))
+	assert.False(t, monitor.IsFlapping([]*Heartbeat{up(), up(), up(), down(), down()}))
+	assert.False(t, monitor.IsFlapping([]*Heartbeat{up(), down(), up(), down()}), "not enough history")
+
+	assert.Equal(t, 100, StateChangeRate([]*Heartbeat{up(), down(), up()}))
+	assert.Equal(t, 0, StateChangeRate([]*Heartbeat{up()}))
+}
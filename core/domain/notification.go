@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Alert channel config keys that throttle a channel's notifications. All
+// are optional; an unset or zero value turns the limit off.
+const (
+	// RateLimitConfigKey caps the messages a channel sends per hour.
+	RateLimitConfigKey = "rate_limit_per_hour"
+	// DedupWindowConfigKey drops an event identical to one sent within the
+	// given number of minutes.
+	DedupWindowConfigKey = "dedup_window_minutes"
+	// DigestIntervalConfigKey batches minor and info incident events into
+	// one summary message every given number of minutes.
+	DigestIntervalConfigKey = "digest_interval_minutes"
+)
+
+// Notification throttling limits.
+const (
+	MaxNotificationRateLimit   = 3600
+	MaxNotificationWindowHours = 24
+	// NotificationDigestLimit caps the events listed in a digest message;
+	// the rest are counted.
+	NotificationDigestLimit = 20
+)
+
+// NotificationPolicy throttles the notifications sent through a channel.
+type NotificationPolicy struct {
+	RateLimit      int // messages per hour; 0 is unlimited
+	DedupWindow    time.Duration
+	DigestInterval time.Duration
+}
+
+// IsZero returns true if the policy throttles nothing.
+func (p NotificationPolicy) IsZero() bool {
+	return p.RateLimit == 0 && p.DedupWindow == 0 && p.DigestInterval == 0
+}
+
+// Validate checks the policy's limits are in range.
+func (p NotificationPolicy) Validate() error {
+	maxWindow := MaxNotificationWindowHours * time.Hour
+	switch {
+	case p.RateLimit < 0 || p.RateLimit > MaxNotificationRateLimit:
+		return fmt.Errorf("rate limit must be between 0 and %d per hour", MaxNotificationRateLimit)
+	case p.DedupWindow < 0 || p.DedupWindow > maxWindow:
+		return fmt.Errorf("dedup window must be between 0 and %d hours", MaxNotificationWindowHours)
+	case p.DigestInterval < 0 || p.DigestInterval > maxWindow:
+		return fmt.Errorf("digest interval must be between 0 and %d hours", MaxNotificationWindowHours)
+	}
+	return nil
+}
+
+// NotificationPolicy returns the throttling set in the channel's config.
+func (ac *AlertChannel) NotificationPolicy() (NotificationPolicy, error) {
+	var p NotificationPolicy
+	parse := func(key string) (int, error) {
+		raw := ac.Config[key]
+		if raw == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, fmt.Errorf("%s must be a whole number", key)
+		}
+		return n, nil
+	}
+
+	var err error
+	if p.RateLimit, err = parse(RateLimitConfigKey); err != nil {
+		return p, err
+	}
+	minutes, err := parse(DedupWindowConfigKey)
+	if err != nil {
+		return p, err
+	}
+	p.DedupWindow = time.Duration(minutes) * time.Minute
+	if minutes, err = parse(DigestIntervalConfigKey); err != nil {
+		return p, err
+	}
+	p.DigestInterval = time.Duration(minutes) * time.Minute
+
+	if err := p.Validate(); err != nil {
+		return p, err
+	}
+	if p.DigestInterval > 0 && ac.Type.IsIncidentManagement() {
+		return p, fmt.Errorf("%s is not supported for %s", DigestIntervalConfigKey, ac.Type)
+	}
+	return p, nil
+}
+
+// NotificationStatus records what became of a notification.
+type NotificationStatus string
+
+const (
+	NotificationStatusSent          NotificationStatus = "sent"
+	NotificationStatusFailed        NotificationStatus = "failed"
+	NotificationStatusRateLimited   NotificationStatus = "rate_limited"
+	NotificationStatusDeduplicated  NotificationStatus = "deduplicated"
+	NotificationStatusDigestPending NotificationStatus = "digest_pending"
+	NotificationStatusDigested      NotificationStatus = "digested"
+	// NotificationStatusQuietHours marks events dropped outside the
+	// channel's delivery schedule.
+	NotificationStatusQuietHours NotificationStatus = "quiet_hours"
+)
+
+// IsSuppressed returns true for notifications dropped by a throttle or a
+// delivery schedule, which the channel's next message reports a count of.
+func (s NotificationStatus) IsSuppressed() bool {
+	return s == NotificationStatusRateLimited || s == NotificationStatusDeduplicated || s == NotificationStatusQuietHours
+}
+
+// NotificationEventDigest is the event of a digest summary message.
+const NotificationEventDigest = "digest"
+
+// NotificationLogEntry records one notification to one channel. Entries of
+// the global notifier have no channel or user.
+type NotificationLogEntry struct {
+	ID          uuid.UUID
+	UserID      *uuid.UUID
+	ChannelID   *uuid.UUID
+	ChannelName string
+	Event       string // an AlertEventKind or NotificationEventDigest
+	Subject     string // monitor or agent name
+	IncidentID  *uuid.UUID
+	Severity    IncidentSeverity
+	DedupKey    string
+	Status      NotificationStatus
+	Error       string
+	// Reported is set on suppressed entries once a message has counted them.
+	Reported bool
+	// DigestDueAt is when a pending entry's digest is sent.
+	DigestDueAt *time.Time
+	CreatedAt   time.Time
+}
+
+// NewNotificationLogEntry creates a log entry for an event to a channel.
+func NewNotificationLogEntry(userID, channelID *uuid.UUID, channelName, event, subject string) *NotificationLogEntry {
+	return &NotificationLogEntry{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ChannelID:   channelID,
+		ChannelName: channelName,
+		Event:       event,
+		Subject:     subject,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// NotificationDigest is the summary of a channel's batched low-severity
+// events.
+type NotificationDigest struct {
+	ChannelName string
+	Entries     []*NotificationLogEntry // oldest first
+	// Suppressed counts notifications rate limited, deduplicated or dropped
+	// outside the delivery schedule since the channel's last message.
+	Suppressed int
+}
+
+// Since returns when the digest's first event happened.
+func (d *NotificationDigest) Since() time.Time {
+	if len(d.Entries) == 0 {
+		return time.Time{}
+	}
+	return d.Entries[0].CreatedAt
+}
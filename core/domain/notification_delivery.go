@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrDeliveryTargetGone is returned when a notification delivery cannot be
+// sent again because its channel no longer exists or is disabled.
+var ErrDeliveryTargetGone = errors.New("notification channel no longer exists or is disabled")
+
+// DeliveryStatus is the state of a notification delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	// DeliveryStatusRetrying marks a failed delivery waiting for its next
+	// attempt.
+	DeliveryStatusRetrying DeliveryStatus = "retrying"
+	// DeliveryStatusDead marks a delivery that failed every attempt. Dead
+	// deliveries are only sent again when redelivered by hand.
+	DeliveryStatusDead DeliveryStatus = "dead"
+)
+
+// IsValid returns true if the status is known.
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryStatusDelivered, DeliveryStatusRetrying, DeliveryStatusDead:
+		return true
+	}
+	return false
+}
+
+// Notification delivery retry limits. A failed delivery is retried after
+// 1, 2, 4 and 8 minutes, then dead-lettered.
+const (
+	MaxDeliveryAttempts   = 5
+	deliveryRetryBase     = time.Minute
+	deliveryRetryMaxDelay = time.Hour
+)
+
+// DeliveryRetryDelay returns how long to wait before retrying a delivery
+// that has failed attempts times.
+func DeliveryRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := deliveryRetryBase
+	for i := 1; i < attempts && delay < deliveryRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, deliveryRetryMaxDelay)
+}
+
+// NotificationDelivery is one event sent to one channel, with its retry
+// state. Deliveries of the global notifiers have no user or channel ID; they
+// are told apart by ChannelName.
+type NotificationDelivery struct {
+	ID          uuid.UUID
+	UserID      *uuid.UUID
+	ChannelID   *uuid.UUID
+	ChannelName string
+	ChannelType string
+	Event       string // an AlertEventKind, DeferredEventMaintenance or NotificationEventDigest
+	Subject     string // monitor or agent name
+	IncidentID  *uuid.UUID
+	// Payload is what the event is sent again from on a retry or
+	// redelivery.
+	Payload       DeferredPayload
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt *time.Time // retrying deliveries only
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewNotificationDelivery creates a delivery of an event to a channel that
+// has not been attempted yet.
+func NewNotificationDelivery(userID, channelID *uuid.UUID, channelName, channelType, event, subject string, payload DeferredPayload) *NotificationDelivery {
+	now := time.Now()
+	return &NotificationDelivery{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ChannelID:   channelID,
+		ChannelName: channelName,
+		ChannelType: channelType,
+		Event:       event,
+		Subject:     subject,
+		Payload:     payload,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// RecordAttempt updates the delivery with the outcome of an attempt made at
+// now. A failed attempt schedules a retry with exponential backoff until
+// MaxDeliveryAttempts, after which the delivery is dead.
+func (d *NotificationDelivery) RecordAttempt(sendErr error, now time.Time) {
+	d.Attempts++
+	d.UpdatedAt = now
+	d.NextAttemptAt = nil
+	if sendErr == nil {
+		d.Status = DeliveryStatusDelivered
+		d.LastError = ""
+		return
+	}
+	d.LastError = sendErr.Error()
+	if d.Attempts >= MaxDeliveryAttempts {
+		d.Status = DeliveryStatusDead
+		return
+	}
+	d.Status = DeliveryStatusRetrying
+	next := now.Add(DeliveryRetryDelay(d.Attempts))
+	d.NextAttemptAt = &next
+}
+
+// DeadLetter marks the delivery dead without another attempt, e.g. when its
+// channel was deleted.
+func (d *NotificationDelivery) DeadLetter(reason string, now time.Time) {
+	d.Status = DeliveryStatusDead
+	d.LastError = reason
+	d.NextAttemptAt = nil
+	d.UpdatedAt = now
+}
+
+// NotificationAttempt records one attempt to send a notification delivery.
+type NotificationAttempt struct {
+	ID         uuid.UUID
+	DeliveryID uuid.UUID
+	Attempt    int // 1 for the first
+	// PayloadHash is the hex SHA-256 of the request body sent; empty when the
+	// notifier failed before sending.
+	PayloadHash string
+	StatusCode  int    // HTTP status; 0 for email or when there was no response
+	Error       string // HTTP, SMTP or transport error
+	LatencyMs   int
+	CreatedAt   time.Time
+}
+
+// NotificationDeliveryFilter selects the deliveries listed to a user.
+type NotificationDeliveryFilter struct {
+	UserID uuid.UUID
+	// IncludeGlobal also lists deliveries of the global notifiers.
+	IncludeGlobal bool
+	ChannelID     *uuid.UUID
+	Status        DeliveryStatus // empty for any
+	Limit         int
+}
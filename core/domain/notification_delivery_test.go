@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, DeliveryRetryDelay(0))
+	assert.Equal(t, time.Minute, DeliveryRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, DeliveryRetryDelay(2))
+	assert.Equal(t, 8*time.Minute, DeliveryRetryDelay(4))
+	assert.Equal(t, time.Hour, DeliveryRetryDelay(20))
+}
+
+func TestNotificationDelivery_RecordAttempt(t *testing.T) {
+	now := time.Now()
+	d := NewNotificationDelivery(nil, nil, "slack", "slack", string(AlertEventIncidentOpened), "api", DeferredPayload{})
+
+	d.RecordAttempt(errors.New("unexpected status code: 500"), now)
+	assert.Equal(t, DeliveryStatusRetrying, d.Status)
+	require.NotNil(t, d.NextAttemptAt)
+	assert.Equal(t, now.Add(time.Minute), *d.NextAttemptAt)
+	assert.Equal(t, "unexpected status code: 500", d.LastError)
+
+	d.RecordAttempt(nil, now)
+	assert.Equal(t, DeliveryStatusDelivered, d.Status)
+	assert.Nil(t, d.NextAttemptAt)
+	assert.Empty(t, d.LastError)
+
+	for d.Attempts < MaxDeliveryAttempts {
+		d.RecordAttempt(errors.New("timeout"), now)
+	}
+	assert.Equal(t, DeliveryStatusDead, d.Status)
+	assert.Nil(t, d.NextAttemptAt)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertChannel_NotificationPolicy(t *testing.T) {
+	ch := &AlertChannel{Type: AlertChannelSlack, Config: map[string]string{
+		RateLimitConfigKey:      "30",
+		DedupWindowConfigKey:    "10",
+		DigestIntervalConfigKey: "15",
+	}}
+	p, err := ch.NotificationPolicy()
+	require.NoError(t, err)
+	assert.Equal(t, NotificationPolicy{RateLimit: 30, DedupWindow: 10 * time.Minute, DigestInterval: 15 * time.Minute}, p)
+
+	p, err = (&AlertChannel{Type: AlertChannelSlack, Config: map[string]string{}}).NotificationPolicy()
+	require.NoError(t, err)
+	assert.True(t, p.IsZero())
+
+	tests := []struct {
+		name   string
+		typ    AlertChannelType
+		config map[string]string
+	}{
+		{"not a number", AlertChannelSlack, map[string]string{RateLimitConfigKey: "lots"}},
+		{"negative rate", AlertChannelSlack, map[string]string{RateLimitConfigKey: "-1"}},
+		{"rate too high", AlertChannelSlack, map[string]string{RateLimitConfigKey: "3601"}},
+		{"window too long", AlertChannelSlack, map[string]string{DedupWindowConfigKey: "1441"}},
+		{"digest on pagerduty", AlertChannelPagerDuty, map[string]string{DigestIntervalConfigKey: "5"}},
+		{"digest on opsgenie", AlertChannelOpsgenie, map[string]string{DigestIntervalConfigKey: "5"}},
+		{"digest on splunk on-call", AlertChannelSplunkOnCall, map[string]string{DigestIntervalConfigKey: "5"}},
+	}
+	for _, tt := range tests {
+		_, err := (&AlertChannel{Type: tt.typ, Config: tt.config}).NotificationPolicy()
+		assert.Error(t, err, tt.name)
+	}
+}
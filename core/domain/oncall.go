@@ -0,0 +1,329 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OnCallScheduleConfigKey is the alert channel config key that makes an
+// email or Telegram channel page whoever is on call for a schedule instead
+// of a fixed recipient.
+const OnCallScheduleConfigKey = "on_call_schedule_id"
+
+// On-call schedule limits.
+const (
+	MaxOnCallLayers       = 5
+	MaxOnCallParticipants = 50
+	MaxOnCallNameLength   = 100
+	MaxOnCallOverrideDays = 90
+)
+
+// Formats of an on-call layer's start date and handoff time.
+const (
+	OnCallDateLayout = "2006-01-02"
+	OnCallTimeLayout = "15:04"
+)
+
+var (
+	// ErrInvalidOnCallSchedule is returned when a schedule fails validation.
+	ErrInvalidOnCallSchedule = errors.New("invalid on-call schedule")
+	// ErrInvalidOnCallOverride is returned when an override fails validation.
+	ErrInvalidOnCallOverride = errors.New("invalid on-call override")
+	// ErrNobodyOnCall is returned when a schedule has nobody on call.
+	ErrNobodyOnCall = errors.New("nobody is on call")
+)
+
+// OnCallRotation is how often a layer hands off to its next participant.
+type OnCallRotation string
+
+const (
+	OnCallRotationDaily  OnCallRotation = "daily"
+	OnCallRotationWeekly OnCallRotation = "weekly"
+)
+
+// IsValid returns true if the rotation is recognized.
+func (r OnCallRotation) IsValid() bool {
+	return r == OnCallRotationDaily || r == OnCallRotationWeekly
+}
+
+// days returns the length of one shift in calendar days.
+func (r OnCallRotation) days() int {
+	if r == OnCallRotationWeekly {
+		return 7
+	}
+	return 1
+}
+
+// OnCallLayer rotates its participants in order. The first participant
+// takes over at HandoffTime on StartDate, in the schedule's timezone, and
+// hands off to the next one every day or week at the same wall-clock time.
+// A layer with an EndDate stops at its handoff time on that date.
+type OnCallLayer struct {
+	Name         string         `json:"name"`
+	Rotation     OnCallRotation `json:"rotation"`
+	StartDate    string         `json:"start_date"`
+	HandoffTime  string         `json:"handoff_time"`
+	EndDate      string         `json:"end_date,omitempty"`
+	Participants []uuid.UUID    `json:"participants"`
+}
+
+// handoffOn returns the layer's handoff instant on the given civil date.
+func (l OnCallLayer) handoffOn(day time.Time, loc *time.Location) time.Time {
+	handoff, _ := time.Parse(OnCallTimeLayout, l.HandoffTime)
+	return time.Date(day.Year(), day.Month(), day.Day(), handoff.Hour(), handoff.Minute(), 0, 0, loc)
+}
+
+// bounds returns when the layer starts and, if it has an end date, ends.
+func (l OnCallLayer) bounds(loc *time.Location) (start, end time.Time) {
+	startDay, _ := time.Parse(OnCallDateLayout, l.StartDate)
+	start = l.handoffOn(startDay, loc)
+	if l.EndDate != "" {
+		endDay, _ := time.Parse(OnCallDateLayout, l.EndDate)
+		end = l.handoffOn(endDay, loc)
+	}
+	return start, end
+}
+
+// shiftAt returns the layer's shift covering t, or false when the layer is
+// not active at t.
+func (l OnCallLayer) shiftAt(t time.Time, loc *time.Location) (OnCallShift, bool) {
+	start, end := l.bounds(loc)
+	if len(l.Participants) == 0 || t.Before(start) || (!end.IsZero() && !t.Before(end)) {
+		return OnCallShift{}, false
+	}
+
+	// The civil date of the handoff that started the current shift.
+	local := t.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if t.Before(l.handoffOn(day, loc)) {
+		day = day.AddDate(0, 0, -1)
+	}
+	startDay, _ := time.Parse(OnCallDateLayout, l.StartDate)
+	length := l.Rotation.days()
+	index := int(day.Sub(startDay).Hours()/24) / length
+
+	shiftDay := startDay.AddDate(0, 0, index*length)
+	shift := OnCallShift{
+		UserID: l.Participants[index%len(l.Participants)],
+		Start:  l.handoffOn(shiftDay, loc),
+		End:    l.handoffOn(shiftDay.AddDate(0, 0, length), loc),
+	}
+	if !end.IsZero() && shift.End.After(end) {
+		shift.End = end
+	}
+	return shift, true
+}
+
+// OnCallSchedule decides who is on call. Later layers take precedence over
+// earlier ones while they are active, and overrides take precedence over
+// every layer.
+type OnCallSchedule struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Timezone  string
+	Layers    []OnCallLayer
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewOnCallSchedule creates a validated on-call schedule. An empty timezone
+// defaults to UTC.
+func NewOnCallSchedule(userID uuid.UUID, name, timezone string, layers []OnCallLayer) (*OnCallSchedule, error) {
+	now := time.Now()
+	s := &OnCallSchedule{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Timezone:  timezone,
+		Layers:    layers,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks the schedule's name, timezone and layers.
+func (s *OnCallSchedule) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidOnCallSchedule)
+	}
+	if len(s.Name) > MaxOnCallNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidOnCallSchedule, MaxOnCallNameLength)
+	}
+	if s.Timezone == "" {
+		s.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidOnCallSchedule, s.Timezone)
+	}
+	if len(s.Layers) == 0 || len(s.Layers) > MaxOnCallLayers {
+		return fmt.Errorf("%w: between 1 and %d layers are required", ErrInvalidOnCallSchedule, MaxOnCallLayers)
+	}
+	for i, l := range s.Layers {
+		if !l.Rotation.IsValid() {
+			return fmt.Errorf("%w: layers[%d]: rotation must be daily or weekly", ErrInvalidOnCallSchedule, i)
+		}
+		start, err := time.Parse(OnCallDateLayout, l.StartDate)
+		if err != nil {
+			return fmt.Errorf("%w: layers[%d]: start_date must be YYYY-MM-DD", ErrInvalidOnCallSchedule, i)
+		}
+		if _, err := time.Parse(OnCallTimeLayout, l.HandoffTime); err != nil {
+			return fmt.Errorf("%w: layers[%d]: handoff_time must be HH:MM", ErrInvalidOnCallSchedule, i)
+		}
+		if l.EndDate != "" {
+			end, err := time.Parse(OnCallDateLayout, l.EndDate)
+			if err != nil {
+				return fmt.Errorf("%w: layers[%d]: end_date must be YYYY-MM-DD", ErrInvalidOnCallSchedule, i)
+			}
+			if !end.After(start) {
+				return fmt.Errorf("%w: layers[%d]: end_date must be after start_date", ErrInvalidOnCallSchedule, i)
+			}
+		}
+		if len(l.Participants) == 0 || len(l.Participants) > MaxOnCallParticipants {
+			return fmt.Errorf("%w: layers[%d]: between 1 and %d participants are required", ErrInvalidOnCallSchedule, i, MaxOnCallParticipants)
+		}
+	}
+	return nil
+}
+
+// Location returns the schedule's timezone, falling back to UTC.
+func (s *OnCallSchedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ShiftAt returns who is on call at t: the most recently created override
+// covering t, else the shift of the last layer active at t. Returns false
+// when nobody is on call.
+func (s *OnCallSchedule) ShiftAt(t time.Time, overrides []*OnCallOverride) (OnCallShift, bool) {
+	var override *OnCallOverride
+	for _, o := range overrides {
+		if o.Covers(t) && (override == nil || o.CreatedAt.After(override.CreatedAt)) {
+			override = o
+		}
+	}
+	if override != nil {
+		return OnCallShift{UserID: override.UserID, Start: override.StartsAt, End: override.EndsAt, Override: true}, true
+	}
+
+	loc := s.Location()
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		if shift, ok := s.Layers[i].shiftAt(t, loc); ok {
+			return shift, true
+		}
+	}
+	return OnCallShift{}, false
+}
+
+// Shifts returns who is on call between from and to, as consecutive shifts
+// clipped to the range. Gaps where nobody is on call are left out.
+func (s *OnCallSchedule) Shifts(from, to time.Time, overrides []*OnCallOverride) []OnCallShift {
+	if !from.Before(to) {
+		return nil
+	}
+
+	// Who is on call can only change at a layer handoff, a layer start or
+	// end, or an override start or end.
+	boundaries := []time.Time{from, to}
+	add := func(t time.Time) {
+		if t.After(from) && t.Before(to) {
+			boundaries = append(boundaries, t)
+		}
+	}
+	for _, o := range overrides {
+		add(o.StartsAt)
+		add(o.EndsAt)
+	}
+	loc := s.Location()
+	for _, l := range s.Layers {
+		start, _ := l.bounds(loc)
+		add(start)
+		t := from
+		if t.Before(start) {
+			t = start
+		}
+		for t.Before(to) {
+			shift, ok := l.shiftAt(t, loc)
+			if !ok {
+				break
+			}
+			add(shift.End)
+			t = shift.End
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+
+	var shifts []OnCallShift
+	for i := 0; i+1 < len(boundaries); i++ {
+		segStart, segEnd := boundaries[i], boundaries[i+1]
+		if !segStart.Before(segEnd) {
+			continue
+		}
+		shift, ok := s.ShiftAt(segStart, overrides)
+		if !ok {
+			continue
+		}
+		if n := len(shifts); n > 0 && shifts[n-1].UserID == shift.UserID &&
+			shifts[n-1].Override == shift.Override && shifts[n-1].End.Equal(segStart) {
+			shifts[n-1].End = segEnd
+			continue
+		}
+		shifts = append(shifts, OnCallShift{UserID: shift.UserID, Start: segStart, End: segEnd, Override: shift.Override})
+	}
+	return shifts
+}
+
+// OnCallShift is a period during which one user is on call.
+type OnCallShift struct {
+	UserID   uuid.UUID
+	Start    time.Time
+	End      time.Time
+	Override bool
+}
+
+// OnCallOverride temporarily puts a user on call for a schedule, e.g. to
+// cover for a participant on leave.
+type OnCallOverride struct {
+	ID         uuid.UUID
+	ScheduleID uuid.UUID
+	UserID     uuid.UUID
+	StartsAt   time.Time
+	EndsAt     time.Time
+	CreatedAt  time.Time
+}
+
+// NewOnCallOverride creates a validated override.
+func NewOnCallOverride(scheduleID, userID uuid.UUID, startsAt, endsAt time.Time) (*OnCallOverride, error) {
+	if !endsAt.After(startsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidOnCallOverride)
+	}
+	if endsAt.Sub(startsAt) > MaxOnCallOverrideDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: overrides cannot exceed %d days", ErrInvalidOnCallOverride, MaxOnCallOverrideDays)
+	}
+	return &OnCallOverride{
+		ID:         uuid.New(),
+		ScheduleID: scheduleID,
+		UserID:     userID,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// Covers returns true if the override is in effect at t.
+func (o *OnCallOverride) Covers(t time.Time) bool {
+	return !t.Before(o.StartsAt) && t.Before(o.EndsAt)
+}
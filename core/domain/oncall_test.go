@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOnCallSchedule(t *testing.T) {
+	participants := []uuid.UUID{uuid.New()}
+	layer := OnCallLayer{Rotation: OnCallRotationWeekly, StartDate: "2026-01-05", HandoffTime: "09:00", Participants: participants}
+
+	s, err := NewOnCallSchedule(uuid.New(), " platform ", "", []OnCallLayer{layer})
+	require.NoError(t, err)
+	assert.Equal(t, "platform", s.Name)
+	assert.Equal(t, "UTC", s.Timezone)
+
+	tests := []struct {
+		name     string
+		timezone string
+		mutate   func(l *OnCallLayer)
+	}{
+		{"unknown timezone", "Mars/Olympus", func(*OnCallLayer) {}},
+		{"bad rotation", "UTC", func(l *OnCallLayer) { l.Rotation = "hourly" }},
+		{"bad start date", "UTC", func(l *OnCallLayer) { l.StartDate = "05/01/2026" }},
+		{"bad handoff", "UTC", func(l *OnCallLayer) { l.HandoffTime = "9am" }},
+		{"end before start", "UTC", func(l *OnCallLayer) { l.EndDate = "2026-01-01" }},
+		{"no participants", "UTC", func(l *OnCallLayer) { l.Participants = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := layer
+			tt.mutate(&l)
+			_, err := NewOnCallSchedule(uuid.New(), "platform", tt.timezone, []OnCallLayer{l})
+			assert.True(t, errors.Is(err, ErrInvalidOnCallSchedule), "got %v", err)
+		})
+	}
+}
+
+func TestOnCallSchedule_ShiftAt_WeeklyInTimezone(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	s := &OnCallSchedule{
+		Timezone: "America/New_York",
+		Layers: []OnCallLayer{{
+			Rotation: OnCallRotationWeekly, StartDate: "2026-01-05", HandoffTime: "09:00",
+			Participants: []uuid.UUID{alice, bob},
+		}},
+	}
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	_, ok := s.ShiftAt(time.Date(2026, 1, 5, 8, 59, 0, 0, ny), nil)
+	assert.False(t, ok, "nobody is on call before the layer starts")
+
+	shift, ok := s.ShiftAt(time.Date(2026, 1, 5, 9, 0, 0, 0, ny), nil)
+	require.True(t, ok)
+	assert.Equal(t, alice, shift.UserID)
+	assert.True(t, shift.End.Equal(time.Date(2026, 1, 12, 9, 0, 0, 0, ny)))
+
+	shift, _ = s.ShiftAt(time.Date(2026, 1, 12, 8, 0, 0, 0, ny), nil)
+	assert.Equal(t, alice, shift.UserID, "handoff happens at 09:00 local")
+	shift, _ = s.ShiftAt(time.Date(2026, 1, 12, 9, 0, 0, 0, ny), nil)
+	assert.Equal(t, bob, shift.UserID)
+	shift, _ = s.ShiftAt(time.Date(2026, 1, 20, 12, 0, 0, 0, ny), nil)
+	assert.Equal(t, alice, shift.UserID)
+
+	// Handoffs stay at 09:00 local across the DST change on 2026-03-08.
+	shift, _ = s.ShiftAt(time.Date(2026, 3, 9, 9, 0, 0, 0, ny), nil)
+	assert.True(t, shift.Start.Equal(time.Date(2026, 3, 9, 9, 0, 0, 0, ny)))
+	assert.Equal(t, 13, shift.Start.UTC().Hour())
+}
+
+func TestOnCallSchedule_ShiftAt_LayersAndOverrides(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	s := &OnCallSchedule{
+		Timezone: "UTC",
+		Layers: []OnCallLayer{
+			{Rotation: OnCallRotationDaily, StartDate: "2026-01-01", HandoffTime: "00:00", Participants: []uuid.UUID{alice}},
+			{Rotation: OnCallRotationDaily, StartDate: "2026-02-01", HandoffTime: "00:00", EndDate: "2026-02-03", Participants: []uuid.UUID{bob}},
+		},
+	}
+
+	shift, _ := s.ShiftAt(time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), nil)
+	assert.Equal(t, alice, shift.UserID)
+	shift, _ = s.ShiftAt(time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC), nil)
+	assert.Equal(t, bob, shift.UserID, "the later layer takes precedence while active")
+	shift, _ = s.ShiftAt(time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), nil)
+	assert.Equal(t, alice, shift.UserID, "the later layer ends at its end date")
+
+	override, err := NewOnCallOverride(s.ID, carol, time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC), time.Date(2026, 1, 15, 14, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	shift, _ = s.ShiftAt(time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), []*OnCallOverride{override})
+	assert.Equal(t, carol, shift.UserID)
+	assert.True(t, shift.Override)
+}
+
+func TestOnCallSchedule_Shifts(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	s := &OnCallSchedule{
+		Timezone: "UTC",
+		Layers: []OnCallLayer{{
+			Rotation: OnCallRotationDaily, StartDate: "2026-01-01", HandoffTime: "09:00",
+			Participants: []uuid.UUID{alice, bob},
+		}},
+	}
+	override, err := NewOnCallOverride(s.ID, carol, time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 2, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC)
+	shifts := s.Shifts(from, to, []*OnCallOverride{override})
+
+	require.Len(t, shifts, 4)
+	assert.Equal(t, alice, shifts[0].UserID)
+	assert.True(t, shifts[0].Start.Equal(time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)), "the gap before the layer starts is left out")
+	assert.Equal(t, bob, shifts[1].UserID)
+	assert.True(t, shifts[1].End.Equal(override.StartsAt))
+	assert.Equal(t, carol, shifts[2].UserID)
+	assert.True(t, shifts[2].Override)
+	assert.Equal(t, bob, shifts[3].UserID)
+	assert.True(t, shifts[3].End.Equal(to))
+}
+
+func TestNewOnCallOverride(t *testing.T) {
+	now := time.Now()
+	_, err := NewOnCallOverride(uuid.New(), uuid.New(), now, now)
+	assert.True(t, errors.Is(err, ErrInvalidOnCallOverride))
+	_, err = NewOnCallOverride(uuid.New(), uuid.New(), now, now.AddDate(0, 0, MaxOnCallOverrideDays+1))
+	assert.True(t, errors.Is(err, ErrInvalidOnCallOverride))
+}
+
+func TestAlertChannel_OnCallValidate(t *testing.T) {
+	scheduleID := uuid.New().String()
+
+	email := NewAlertChannel(uuid.New(), AlertChannelEmail, "on-call", map[string]string{
+		"host": "smtp.example.com", "from": "alerts@example.com", OnCallScheduleConfigKey: scheduleID,
+	})
+	assert.NoError(t, email.Validate(), "on-call email channels need no fixed recipient")
+	assert.True(t, email.IsOnCall())
+
+	slack := NewAlertChannel(uuid.New(), AlertChannelSlack, "on-call", map[string]string{
+		"webhook_url": "https://hooks.slack.com/x", OnCallScheduleConfigKey: scheduleID,
+	})
+	assert.Error(t, slack.Validate())
+
+	telegram := NewAlertChannel(uuid.New(), AlertChannelTelegram, "on-call", map[string]string{
+		"bot_token": "t", OnCallScheduleConfigKey: "not-a-uuid",
+	})
+	assert.Error(t, telegram.Validate())
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// PushSignal is the kind of ping a push monitor receives.
+type PushSignal string
+
+const (
+	PushSignalSuccess PushSignal = "success"
+	PushSignalFail    PushSignal = "fail"
+	PushSignalStart   PushSignal = "start"
+)
+
+// IsValid checks if the signal is a valid PushSignal.
+func (s PushSignal) IsValid() bool {
+	switch s {
+	case PushSignalSuccess, PushSignalFail, PushSignalStart:
+		return true
+	default:
+		return false
+	}
+}
+
+// Push monitor limits. Push monitors accept much longer intervals than
+// agent-pulled checks so that daily and weekly jobs can be watched.
+const (
+	DefaultPushGraceSeconds = 300
+	MaxPushGraceSeconds     = 86400
+	MaxPushIntervalSeconds  = 7 * 86400
+	MaxPushLogExcerpt       = 1000
+)
+
+// GeneratePushToken returns a new unguessable push ingest token.
+// Token format: wd_push_<32 hex chars>.
+func GeneratePushToken() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate push token: %w", err)
+	}
+	return "wd_push_" + hex.EncodeToString(raw), nil
+}
+
+// IsPush returns true if the monitor is pinged by the job it watches rather
+// than checked by an agent.
+func (m *Monitor) IsPush() bool {
+	return m.Type == MonitorTypePush
+}
+
+// EnablePush assigns the ingest token and push defaults. A single failed run
+// is enough to open an incident, so the failure threshold drops to one.
+func (m *Monitor) EnablePush(token string) {
+	m.PushToken = token
+	m.PushGraceSeconds = DefaultPushGraceSeconds
+	m.FailureThreshold = 1
+}
+
+// SetPushSchedule sets how often the job is expected to ping and how late a
+// ping may arrive before the monitor is considered down.
+func (m *Monitor) SetPushSchedule(intervalSeconds, graceSeconds int) bool {
+	if intervalSeconds < MinIntervalSeconds || intervalSeconds > MaxPushIntervalSeconds {
+		return false
+	}
+	if graceSeconds < 0 || graceSeconds > MaxPushGraceSeconds {
+		return false
+	}
+	m.IntervalSeconds = intervalSeconds
+	m.PushGraceSeconds = graceSeconds
+	return true
+}
+
+// PushDeadline returns when the next ping is due at the latest. A job that
+// signalled start must finish within the grace period; otherwise the next
+// ping is due one interval plus grace after the last one (or after creation
+// if it never pinged).
+func (m *Monitor) PushDeadline() time.Time {
+	grace := time.Duration(m.PushGraceSeconds) * time.Second
+	last := m.CreatedAt
+	if m.LastPingAt != nil {
+		last = *m.LastPingAt
+	}
+	deadline := last.Add(time.Duration(m.IntervalSeconds)*time.Second + grace)
+	if m.PushStartedAt != nil && m.PushStartedAt.After(last) {
+		if runDeadline := m.PushStartedAt.Add(grace); runDeadline.Before(deadline) {
+			deadline = runDeadline
+		}
+	}
+	return deadline
+}
+
+// IsPushOverdue reports whether a push monitor missed its deadline at now.
+func (m *Monitor) IsPushOverdue(now time.Time) bool {
+	return m.IsPush() && m.Enabled && now.After(m.PushDeadline())
+}
+
+// TruncatePushLog clips a log excerpt sent with a ping to the stored size.
+func TruncatePushLog(excerpt string) string {
+	if len(excerpt) <= MaxPushLogExcerpt {
+		return excerpt
+	}
+	return excerpt[:MaxPushLogExcerpt]
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePushToken(t *testing.T) {
+	a, err := GeneratePushToken()
+	require.NoError(t, err)
+	b, err := GeneratePushToken()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(a, "wd_push_"))
+	assert.Len(t, a, len("wd_push_")+32)
+	assert.NotEqual(t, a, b)
+}
+
+func TestMonitor_SetPushSchedule(t *testing.T) {
+	monitor := NewMonitor(uuid.New(), "backup", MonitorTypePush, "")
+	monitor.EnablePush("wd_push_test")
+	assert.Equal(t, 1, monitor.FailureThreshold)
+	assert.Equal(t, DefaultPushGraceSeconds, monitor.PushGraceSeconds)
+
+	require.True(t, monitor.SetPushSchedule(86400, 1800), "daily jobs exceed the agent check interval limit")
+	assert.Equal(t, 86400, monitor.IntervalSeconds)
+	assert.Equal(t, 1800, monitor.PushGraceSeconds)
+
+	assert.False(t, monitor.SetPushSchedule(MaxPushIntervalSeconds+1, 0))
+	assert.False(t, monitor.SetPushSchedule(3600, -1))
+	assert.False(t, monitor.SetPushSchedule(3600, MaxPushGraceSeconds+1))
+}
+
+func TestMonitor_PushDeadline(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	monitor := NewMonitor(uuid.New(), "backup", MonitorTypePush, "")
+	monitor.EnablePush("wd_push_test")
+	monitor.CreatedAt = created
+	require.True(t, monitor.SetPushSchedule(3600, 300))
+
+	assert.Equal(t, created.Add(65*time.Minute), monitor.PushDeadline(), "never pinged: counted from creation")
+
+	lastPing := created.Add(2 * time.Hour)
+	monitor.LastPingAt = &lastPing
+	assert.Equal(t, lastPing.Add(65*time.Minute), monitor.PushDeadline())
+	assert.False(t, monitor.IsPushOverdue(lastPing.Add(64*time.Minute)))
+	assert.True(t, monitor.IsPushOverdue(lastPing.Add(66*time.Minute)))
+
+	started := lastPing.Add(30 * time.Minute)
+	monitor.PushStartedAt = &started
+	assert.Equal(t, started.Add(5*time.Minute), monitor.PushDeadline(), "a started run must finish within the grace period")
+
+	stale := lastPing.Add(-time.Minute)
+	monitor.PushStartedAt = &stale
+	assert.Equal(t, lastPing.Add(65*time.Minute), monitor.PushDeadline(), "a start before the last ping is complete")
+
+	monitor.Disable()
+	assert.False(t, monitor.IsPushOverdue(lastPing.Add(2*time.Hour)))
+}
+
+func TestTruncatePushLog(t *testing.T) {
+	assert.Equal(t, "short", TruncatePushLog("short"))
+	assert.Len(t, TruncatePushLog(strings.Repeat("x", MaxPushLogExcerpt+10)), MaxPushLogExcerpt)
+}
@@ -0,0 +1,325 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transaction limits.
+const (
+	MaxTransactionSteps       = 10
+	MaxTransactionExtractions = 10
+	MaxTransactionBodyBytes   = 64 << 10
+)
+
+// Metadata keys used to ship a transaction to agents and to report a run
+// back. The definition travels as JSON in TaskPayload.Metadata; results come
+// back as JSON in HeartbeatPayload.Metadata.
+const (
+	TransactionTaskKey    = "transaction"
+	TransactionVersionKey = "transaction_version"
+	TransactionStepsKey   = "transaction_steps"
+)
+
+var (
+	ErrInvalidTransaction = errors.New("invalid transaction")
+
+	transactionVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	transactionVarRef  = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+)
+
+// ExtractionSource is where a transaction step reads a variable from.
+type ExtractionSource string
+
+const (
+	ExtractJSONPath ExtractionSource = "json_path"
+	ExtractHeader   ExtractionSource = "header"
+	ExtractRegex    ExtractionSource = "regex"
+)
+
+// IsValid checks if the source is a valid ExtractionSource.
+func (s ExtractionSource) IsValid() bool {
+	switch s {
+	case ExtractJSONPath, ExtractHeader, ExtractRegex:
+		return true
+	default:
+		return false
+	}
+}
+
+// Extraction stores part of a step's response in a variable that later steps
+// reference as {{name}}. Expression is a JSON path, a header name, or a regex
+// whose first capture group (or whole match) becomes the value.
+type Extraction struct {
+	Variable   string           `json:"variable"`
+	Source     ExtractionSource `json:"source"`
+	Expression string           `json:"expression"`
+}
+
+// TransactionStep is one HTTP request in a transaction. URL, header values
+// and body may reference variables extracted by earlier steps.
+type TransactionStep struct {
+	Name       string            `json:"name"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Extract    []Extraction      `json:"extract,omitempty"`
+	Assertions []Assertion       `json:"assertions,omitempty"`
+}
+
+// Transaction is the versioned definition of a transaction monitor. The
+// version increases every time the steps change.
+type Transaction struct {
+	Version int               `json:"version"`
+	Steps   []TransactionStep `json:"steps"`
+}
+
+// TransactionVersion is a past definition of a transaction monitor.
+type TransactionVersion struct {
+	MonitorID uuid.UUID
+	Version   int
+	Steps     []TransactionStep
+	CreatedAt time.Time
+}
+
+// TransactionStepStatus is the outcome of one step in a transaction run.
+type TransactionStepStatus string
+
+const (
+	TransactionStepPassed  TransactionStepStatus = "passed"
+	TransactionStepFailed  TransactionStepStatus = "failed"
+	TransactionStepSkipped TransactionStepStatus = "skipped"
+)
+
+// TransactionStepResult is the reported outcome of one step. Steps after a
+// failure are reported as skipped.
+type TransactionStepResult struct {
+	Name       string                `json:"name"`
+	Status     TransactionStepStatus `json:"status"`
+	DurationMs int                   `json:"duration_ms"`
+	StatusCode int                   `json:"status_code,omitempty"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// TransactionRun is the latest reported run of a transaction monitor.
+type TransactionRun struct {
+	Version int                     `json:"version"`
+	At      time.Time               `json:"at"`
+	Steps   []TransactionStepResult `json:"steps"`
+}
+
+// WaterfallStep places a step result on the run's timeline.
+type WaterfallStep struct {
+	TransactionStepResult
+	OffsetMs int `json:"offset_ms"`
+}
+
+// IsTransaction returns true for transaction monitors.
+func (m *Monitor) IsTransaction() bool {
+	return m.Type == MonitorTypeTransaction
+}
+
+// SetTransaction replaces the monitor's steps and bumps the version when they
+// differ from the current definition. The first step's URL becomes the
+// monitor's target so lists show where the flow starts. Returns whether a
+// new version was created.
+func (m *Monitor) SetTransaction(steps []TransactionStep) bool {
+	if m.Transaction != nil && sameSteps(m.Transaction.Steps, steps) {
+		return false
+	}
+	version := 1
+	if m.Transaction != nil {
+		version = m.Transaction.Version + 1
+	}
+	m.Transaction = &Transaction{Version: version, Steps: steps}
+	if len(steps) > 0 {
+		m.Target = steps[0].URL
+	}
+	return true
+}
+
+// sameSteps compares definitions by their JSON encoding, so an empty list and
+// an omitted one are the same step.
+func sameSteps(a, b []TransactionStep) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// TaskMetadata returns the metadata shipped to agents in TaskPayload. For
+// transaction monitors it carries the current definition.
+func (m *Monitor) TaskMetadata() map[string]string {
+	if m.Transaction == nil {
+		return m.Metadata
+	}
+	meta := make(map[string]string, len(m.Metadata)+1)
+	for k, v := range m.Metadata {
+		meta[k] = v
+	}
+	def, err := json.Marshal(m.Transaction)
+	if err == nil {
+		meta[TransactionTaskKey] = string(def)
+	}
+	return meta
+}
+
+// ValidateTransactionSteps checks a transaction definition: step count,
+// methods, URLs, extractions and assertions. Variables must be extracted by
+// an earlier step before they are referenced.
+func ValidateTransactionSteps(steps []TransactionStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("%w: at least one step is required", ErrInvalidTransaction)
+	}
+	if len(steps) > MaxTransactionSteps {
+		return fmt.Errorf("%w: at most %d steps are allowed", ErrInvalidTransaction, MaxTransactionSteps)
+	}
+
+	defined := make(map[string]bool)
+	for i, step := range steps {
+		prefix := fmt.Sprintf("steps[%d]", i)
+		if strings.TrimSpace(step.Name) == "" {
+			return fmt.Errorf("%w: %s: name is required", ErrInvalidTransaction, prefix)
+		}
+		switch step.Method {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead:
+		default:
+			return fmt.Errorf("%w: %s: unsupported method %q", ErrInvalidTransaction, prefix, step.Method)
+		}
+		if len(step.Body) > MaxTransactionBodyBytes {
+			return fmt.Errorf("%w: %s: body exceeds %d bytes", ErrInvalidTransaction, prefix, MaxTransactionBodyBytes)
+		}
+
+		// Check the URL with variables replaced by a placeholder.
+		u, err := url.Parse(transactionVarRef.ReplaceAllString(step.URL, "x"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %s: url must be an absolute http(s) URL", ErrInvalidTransaction, prefix)
+		}
+
+		refs := []string{step.URL, step.Body}
+		for _, v := range step.Headers {
+			refs = append(refs, v)
+		}
+		for _, s := range refs {
+			for _, match := range transactionVarRef.FindAllStringSubmatch(s, -1) {
+				if !defined[match[1]] {
+					return fmt.Errorf("%w: %s: variable %q is not extracted by an earlier step", ErrInvalidTransaction, prefix, match[1])
+				}
+			}
+		}
+
+		if len(step.Extract) > MaxTransactionExtractions {
+			return fmt.Errorf("%w: %s: at most %d extractions are allowed", ErrInvalidTransaction, prefix, MaxTransactionExtractions)
+		}
+		for j, ex := range step.Extract {
+			if err := ex.validate(); err != nil {
+				return fmt.Errorf("%w: %s.extract[%d]: %v", ErrInvalidTransaction, prefix, j, err)
+			}
+		}
+		for _, ex := range step.Extract {
+			defined[ex.Variable] = true
+		}
+
+		if err := ValidateAssertions(step.Assertions); err != nil {
+			return fmt.Errorf("%s: %w", prefix, err)
+		}
+	}
+	return nil
+}
+
+func (e Extraction) validate() error {
+	if !transactionVarName.MatchString(e.Variable) {
+		return fmt.Errorf("variable %q must be a letter or underscore followed by letters, digits or underscores", e.Variable)
+	}
+	if !e.Source.IsValid() {
+		return fmt.Errorf("unknown source %q", e.Source)
+	}
+	if strings.TrimSpace(e.Expression) == "" {
+		return errors.New("expression is required")
+	}
+	switch e.Source {
+	case ExtractJSONPath:
+		if _, err := parseJSONPath(e.Expression); err != nil {
+			return err
+		}
+	case ExtractRegex:
+		if _, err := regexp.Compile(e.Expression); err != nil {
+			return fmt.Errorf("bad regex %q: %v", e.Expression, err)
+		}
+	}
+	return nil
+}
+
+// Extract reads the variable's value from a step's response, reported in the
+// same shape assertions use. Returns false when the value is not present.
+func (e Extraction) Extract(result map[string]string) (string, bool) {
+	switch e.Source {
+	case ExtractJSONPath:
+		return lookupJSONPath(result[ResultBody], e.Expression)
+	case ExtractHeader:
+		v, ok := result[ResultHeaderPrefix+strings.ToLower(e.Expression)]
+		return v, ok
+	case ExtractRegex:
+		re, err := regexp.Compile(e.Expression)
+		if err != nil {
+			return "", false
+		}
+		match := re.FindStringSubmatch(result[ResultBody])
+		switch {
+		case match == nil:
+			return "", false
+		case len(match) > 1:
+			return match[1], true
+		default:
+			return match[0], true
+		}
+	}
+	return "", false
+}
+
+// ExpandVariables replaces {{name}} references with extracted values.
+// Unknown variables are left as-is.
+func ExpandVariables(s string, vars map[string]string) string {
+	return transactionVarRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := transactionVarRef.FindStringSubmatch(ref)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// ParseTransactionRun reads a run reported in heartbeat metadata. Returns
+// false when the metadata carries no step results.
+func ParseTransactionRun(meta map[string]string, at time.Time) (*TransactionRun, bool) {
+	raw, ok := meta[TransactionStepsKey]
+	if !ok {
+		return nil, false
+	}
+	run := &TransactionRun{At: at}
+	if err := json.Unmarshal([]byte(raw), &run.Steps); err != nil {
+		return nil, false
+	}
+	_, _ = fmt.Sscanf(meta[TransactionVersionKey], "%d", &run.Version)
+	return run, true
+}
+
+// Waterfall lays the run's steps out on a timeline. Steps run one after
+// another, so each starts when the previous one finished.
+func (r *TransactionRun) Waterfall() []WaterfallStep {
+	steps := make([]WaterfallStep, len(r.Steps))
+	offset := 0
+	for i, s := range r.Steps {
+		steps[i] = WaterfallStep{TransactionStepResult: s, OffsetMs: offset}
+		offset += s.DurationMs
+	}
+	return steps
+}
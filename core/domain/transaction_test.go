@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loginFlow() []TransactionStep {
+	return []TransactionStep{
+		{
+			Name:    "login",
+			Method:  "POST",
+			URL:     "https://api.example.com/login",
+			Body:    `{"user":"probe"}`,
+			Extract: []Extraction{{Variable: "token", Source: ExtractJSONPath, Expression: "$.token"}},
+		},
+		{
+			Name:       "orders",
+			Method:     "GET",
+			URL:        "https://api.example.com/orders",
+			Headers:    map[string]string{"Authorization": "Bearer {{token}}"},
+			Assertions: []Assertion{{Type: AssertionJSONPath, Property: "$.count", Operator: OpGreaterThan, Target: "0"}},
+		},
+	}
+}
+
+func TestValidateTransactionSteps(t *testing.T) {
+	require.NoError(t, ValidateTransactionSteps(loginFlow()))
+
+	tests := []struct {
+		name   string
+		mutate func(steps []TransactionStep)
+	}{
+		{"missing name", func(s []TransactionStep) { s[0].Name = "" }},
+		{"bad method", func(s []TransactionStep) { s[0].Method = "TRACE" }},
+		{"relative url", func(s []TransactionStep) { s[1].URL = "/orders" }},
+		{"undefined variable", func(s []TransactionStep) { s[1].Headers["Authorization"] = "Bearer {{session}}" }},
+		{"variable used before extraction", func(s []TransactionStep) { s[0].URL = "https://api.example.com/{{token}}" }},
+		{"bad variable name", func(s []TransactionStep) { s[0].Extract[0].Variable = "1token" }},
+		{"bad extraction source", func(s []TransactionStep) { s[0].Extract[0].Source = "cookie" }},
+		{"bad step assertion", func(s []TransactionStep) { s[1].Assertions[0].Operator = "sorta" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := loginFlow()
+			tt.mutate(steps)
+			assert.Error(t, ValidateTransactionSteps(steps))
+		})
+	}
+
+	assert.Error(t, ValidateTransactionSteps(nil))
+	assert.Error(t, ValidateTransactionSteps(make([]TransactionStep, MaxTransactionSteps+1)))
+}
+
+func TestMonitor_SetTransaction_Versions(t *testing.T) {
+	m := NewMonitor(uuid.New(), "checkout", MonitorTypeTransaction, "")
+
+	require.True(t, m.SetTransaction(loginFlow()))
+	assert.Equal(t, 1, m.Transaction.Version)
+	assert.Equal(t, "https://api.example.com/login", m.Target)
+
+	assert.False(t, m.SetTransaction(loginFlow()), "unchanged steps keep the version")
+
+	steps := loginFlow()
+	steps[1].URL = "https://api.example.com/v2/orders"
+	assert.True(t, m.SetTransaction(steps))
+	assert.Equal(t, 2, m.Transaction.Version)
+}
+
+func TestMonitor_TaskMetadata(t *testing.T) {
+	m := NewMonitor(uuid.New(), "checkout", MonitorTypeTransaction, "")
+	m.Metadata["region"] = "eu"
+	m.SetTransaction(loginFlow())
+
+	meta := m.TaskMetadata()
+	assert.Equal(t, "eu", meta["region"])
+	var shipped Transaction
+	require.NoError(t, json.Unmarshal([]byte(meta[TransactionTaskKey]), &shipped))
+	assert.Equal(t, 1, shipped.Version)
+	assert.Len(t, shipped.Steps, 2)
+	assert.NotContains(t, m.Metadata, TransactionTaskKey, "monitor metadata is not modified")
+}
+
+func TestExtraction_Extract(t *testing.T) {
+	result := map[string]string{
+		ResultBody:                    `{"token":"abc123"} session=xyz`,
+		ResultHeaderPrefix + "x-csrf": "csrf-1",
+	}
+	v, ok := Extraction{Variable: "t", Source: ExtractRegex, Expression: `session=(\w+)`}.Extract(result)
+	assert.True(t, ok)
+	assert.Equal(t, "xyz", v)
+
+	v, ok = Extraction{Variable: "t", Source: ExtractHeader, Expression: "X-CSRF"}.Extract(result)
+	assert.True(t, ok)
+	assert.Equal(t, "csrf-1", v)
+
+	_, ok = Extraction{Variable: "t", Source: ExtractJSONPath, Expression: "$.missing"}.Extract(result)
+	assert.False(t, ok)
+}
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{"token": "abc"}
+	assert.Equal(t, "Bearer abc", ExpandVariables("Bearer {{ token }}", vars))
+	assert.Equal(t, "{{other}}", ExpandVariables("{{other}}", vars))
+}
+
+func TestParseTransactionRun_Waterfall(t *testing.T) {
+	at := time.Now()
+	meta := map[string]string{
+		TransactionVersionKey: "3",
+		TransactionStepsKey: `[{"name":"login","status":"passed","duration_ms":120,"status_code":200},` +
+			`{"name":"orders","status":"failed","duration_ms":80,"error":"unexpected status 500"},` +
+			`{"name":"logout","status":"skipped","duration_ms":0}]`,
+	}
+	run, ok := ParseTransactionRun(meta, at)
+	require.True(t, ok)
+	assert.Equal(t, 3, run.Version)
+
+	waterfall := run.Waterfall()
+	require.Len(t, waterfall, 3)
+	assert.Equal(t, 0, waterfall[0].OffsetMs)
+	assert.Equal(t, 120, waterfall[1].OffsetMs)
+	assert.Equal(t, 200, waterfall[2].OffsetMs)
+	assert.Equal(t, TransactionStepFailed, waterfall[1].Status)
+
+	_, ok = ParseTransactionRun(map[string]string{"status_code": "200"}, at)
+	assert.False(t, ok)
+}
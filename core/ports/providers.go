@@ -54,9 +54,12 @@ type AgentManager interface {
 }
 
 // AlertRouter decouples alert routing from channel dispatch.
+// RouteChannels decides which of the event's user's alert channels an
+// event goes to, filling in the routing's Channels.
 type AlertRouter interface {
 	RouteIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error
 	RouteIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error
+	RouteChannels(ctx context.Context, event *domain.AlertEvent) (*domain.AlertRouting, error)
 }
 
 // StorageBackend abstracts database access with tenant-scoped query isolation.
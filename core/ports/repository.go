@@ -64,6 +64,7 @@ type AgentRepository interface {
 	UpdateFingerprint(ctx context.Context, id uuid.UUID, fingerprint map[string]string) error
 	UpdateVersion(ctx context.Context, id uuid.UUID, version string) error
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	GetHubAgents(ctx context.Context) ([]*domain.Agent, error)
 }
 
 // MonitorRepository defines the interface for monitor persistence.
@@ -80,6 +81,21 @@ type MonitorRepository interface {
 	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.MonitorStatus) error
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
 	UpdateMetadata(ctx context.Context, id uuid.UUID, metadata map[string]string) error
+	MergeMetadata(ctx context.Context, id uuid.UUID, metadata map[string]string) error
+	SetLocations(ctx context.Context, monitorID uuid.UUID, agentIDs []uuid.UUID) error
+	GetByPushTokenGlobal(ctx context.Context, token string) (*domain.Monitor, error)
+	RecordPing(ctx context.Context, id uuid.UUID, at time.Time, start bool) error
+	GetOverduePush(ctx context.Context, now time.Time) ([]*domain.Monitor, error)
+	UpdateTransactionRun(ctx context.Context, id uuid.UUID, run *domain.TransactionRun) error
+	GetTransactionVersions(ctx context.Context, monitorID uuid.UUID) ([]*domain.TransactionVersion, error)
+}
+
+// MonitorCache serves monitor reads on the heartbeat path from memory.
+// Returned monitors are shared and must not be modified. Invalidate drops a
+// monitor after it changes.
+type MonitorCache interface {
+	Get(ctx context.Context, id uuid.UUID) (*domain.Monitor, error)
+	Invalidate(id uuid.UUID)
 }
 
 // IncidentRepository defines the interface for incident persistence.
@@ -94,6 +110,12 @@ type IncidentRepository interface {
 	Update(ctx context.Context, incident *domain.Incident) error
 	Acknowledge(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	Resolve(ctx context.Context, id uuid.UUID) error
+	SetFlapping(ctx context.Context, id uuid.UUID, flapping bool) error
+	GetActiveByParentID(ctx context.Context, parentIncidentID uuid.UUID) ([]*domain.Incident, error)
+	SetParent(ctx context.Context, id uuid.UUID, parentIncidentID *uuid.UUID) error
+	SetSeverity(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error
+	SetOwner(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error
+	GetDeclaredAffecting(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error)
 }
 
 // HeartbeatRepository defines the interface for heartbeat persistence.
@@ -102,6 +124,7 @@ type HeartbeatRepository interface {
 	CreateBatch(ctx context.Context, heartbeats []*domain.Heartbeat) error
 	GetByMonitorID(ctx context.Context, monitorID uuid.UUID, limit int) ([]*domain.Heartbeat, error)
 	GetByMonitorIDInRange(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]*domain.Heartbeat, error)
+	GetRecentPerAgent(ctx context.Context, monitorID uuid.UUID, perAgent int) ([]*domain.Heartbeat, error)
 	GetLatestByMonitorID(ctx context.Context, monitorID uuid.UUID) (*domain.Heartbeat, error)
 	GetRecentFailures(ctx context.Context, monitorID uuid.UUID, count int) ([]*domain.Heartbeat, error)
 	GetUptimePercent(ctx context.Context, monitorID uuid.UUID, since time.Time) (float64, error)
@@ -109,6 +132,8 @@ type HeartbeatRepository interface {
 	GetLatencyPercentiles(ctx context.Context, monitorID uuid.UUID, from, to time.Time, bucketInterval string) ([]domain.LatencyPercentilePoint, error)
 	GetLatencyPercentileSummary(ctx context.Context, monitorID uuid.UUID, from, to time.Time) (domain.LatencyTrendSummary, error)
 	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+	DeleteRollupsOlderThan(ctx context.Context, before time.Time) error
+	RefreshRollups(ctx context.Context, from, to time.Time) error
 }
 
 // UsageEventRepository defines the interface for usage event persistence.
@@ -193,6 +218,141 @@ type MaintenanceWindowRepository interface {
 	DeleteExpired(ctx context.Context, before time.Time) error
 }
 
+// DependencyRepository defines the interface for monitor dependency persistence.
+type DependencyRepository interface {
+	Create(ctx context.Context, dep *domain.MonitorDependency) error
+	Delete(ctx context.Context, monitorID, parentID uuid.UUID) error
+	GetParentIDs(ctx context.Context, monitorID uuid.UUID) ([]uuid.UUID, error)
+	GetByTenant(ctx context.Context) ([]*domain.MonitorDependency, error)
+}
+
+// IncidentActivityRepository defines the interface for incident activity
+// log and postmortem persistence. Reads fill in author emails.
+type IncidentActivityRepository interface {
+	Create(ctx context.Context, activity *domain.IncidentActivity) error
+	Update(ctx context.Context, activity *domain.IncidentActivity) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.IncidentActivity, error)
+	GetByIncidentID(ctx context.Context, incidentID uuid.UUID) ([]*domain.IncidentActivity, error)
+	GetPostmortem(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentPostmortem, error)
+	UpsertPostmortem(ctx context.Context, postmortem *domain.IncidentPostmortem) error
+}
+
+// EscalationRepository defines the interface for escalation policy
+// persistence and for tracking the escalations running for open incidents.
+type EscalationRepository interface {
+	CreatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error
+	UpdatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error
+	DeletePolicy(ctx context.Context, id uuid.UUID) error
+	GetPolicyByID(ctx context.Context, id uuid.UUID) (*domain.EscalationPolicy, error)
+	GetPolicyByName(ctx context.Context, userID uuid.UUID, name string) (*domain.EscalationPolicy, error)
+	GetPoliciesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.EscalationPolicy, error)
+
+	CreateEscalation(ctx context.Context, escalation *domain.Escalation) error
+	UpdateEscalation(ctx context.Context, escalation *domain.Escalation) error
+	SetEscalationWorkflow(ctx context.Context, incidentID, workflowID uuid.UUID) error
+	DeleteEscalation(ctx context.Context, incidentID uuid.UUID) error
+	GetEscalation(ctx context.Context, incidentID uuid.UUID) (*domain.Escalation, error)
+	GetDueEscalations(ctx context.Context, now time.Time) ([]*domain.Escalation, error)
+}
+
+// OnCallRepository defines the interface for on-call schedule and override persistence.
+type OnCallRepository interface {
+	CreateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error
+	UpdateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error
+	DeleteSchedule(ctx context.Context, id uuid.UUID) error
+	GetScheduleByID(ctx context.Context, id uuid.UUID) (*domain.OnCallSchedule, error)
+	GetSchedulesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.OnCallSchedule, error)
+
+	CreateOverride(ctx context.Context, override *domain.OnCallOverride) error
+	DeleteOverride(ctx context.Context, id uuid.UUID) error
+	GetOverrideByID(ctx context.Context, id uuid.UUID) (*domain.OnCallOverride, error)
+	GetOverrides(ctx context.Context, scheduleID uuid.UUID, from, to time.Time) ([]*domain.OnCallOverride, error)
+}
+
+// AlertRuleRepository defines the interface for alert routing rule persistence.
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *domain.AlertRule) error
+	Update(ctx context.Context, rule *domain.AlertRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.AlertRule, error)
+}
+
+// NotificationLogRepository defines the interface for the notification log,
+// which throttling reads back and which queues digests. A nil channel ID
+// stands for the global notifier.
+type NotificationLogRepository interface {
+	Create(ctx context.Context, entry *domain.NotificationLogEntry) error
+	CountSent(ctx context.Context, channelID *uuid.UUID, since time.Time) (int, error)
+	HasRecent(ctx context.Context, channelID *uuid.UUID, dedupKey string, since time.Time) (bool, error)
+	CountUnreported(ctx context.Context, channelID *uuid.UUID) (int, error)
+	MarkReported(ctx context.Context, channelID *uuid.UUID, before time.Time) error
+	GetDigestDueAt(ctx context.Context, channelID *uuid.UUID) (*time.Time, error)
+	GetDueDigests(ctx context.Context, now time.Time) ([]*domain.NotificationLogEntry, error)
+	MarkDigested(ctx context.Context, ids []uuid.UUID) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DeferredNotificationRepository defines the interface for the queue of
+// notifications held until their channel's delivery window opens.
+type DeferredNotificationRepository interface {
+	Create(ctx context.Context, n *domain.DeferredNotification) error
+	GetDue(ctx context.Context, now time.Time) ([]*domain.DeferredNotification, error)
+	CountByChannelID(ctx context.Context, channelID uuid.UUID) (int, error)
+	Reschedule(ctx context.Context, id uuid.UUID, deliverAt time.Time, attempts int) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// NotificationDeliveryRepository defines the interface for the notification
+// delivery log: deliveries, their attempts, and the queue of retries.
+type NotificationDeliveryRepository interface {
+	Create(ctx context.Context, d *domain.NotificationDelivery) error
+	Update(ctx context.Context, d *domain.NotificationDelivery) error
+	CreateAttempt(ctx context.Context, a *domain.NotificationAttempt) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.NotificationDelivery, error)
+	GetAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*domain.NotificationAttempt, error)
+	List(ctx context.Context, filter domain.NotificationDeliveryFilter) ([]*domain.NotificationDelivery, error)
+	GetDue(ctx context.Context, now time.Time) ([]*domain.NotificationDelivery, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// MessageTemplateRepository defines the interface for message template
+// persistence. Resolve returns the template a channel uses for an event, or
+// nil when it uses the built-in layout.
+type MessageTemplateRepository interface {
+	Create(ctx context.Context, t *domain.MessageTemplate) error
+	Update(ctx context.Context, t *domain.MessageTemplate) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.MessageTemplate, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.MessageTemplate, error)
+	Resolve(ctx context.Context, channel *domain.AlertChannel, event string) (*domain.MessageTemplate, error)
+}
+
+// AlertGroupRepository defines the interface for alert group persistence
+// and for assigning incidents to groups.
+type AlertGroupRepository interface {
+	Create(ctx context.Context, group *domain.AlertGroup) error
+	Update(ctx context.Context, group *domain.AlertGroup) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Resolve(ctx context.Context, id uuid.UUID) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AlertGroup, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, status domain.AlertGroupStatus) ([]*domain.AlertGroup, error)
+	GetAccepting(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.AlertGroup, error)
+	GetDue(ctx context.Context, createdBefore time.Time) ([]*domain.AlertGroup, error)
+
+	GetIncidents(ctx context.Context, groupID uuid.UUID) ([]*domain.Incident, error)
+	SetIncidentGroup(ctx context.Context, incidentIDs []uuid.UUID, groupID *uuid.UUID) error
+}
+
+// AnomalyRepository defines the interface for latency baseline and anomaly persistence.
+type AnomalyRepository interface {
+	GetBaselines(ctx context.Context, monitorID uuid.UUID) ([]*domain.LatencyBaseline, error)
+	UpsertBaseline(ctx context.Context, baseline *domain.LatencyBaseline) error
+	CreateAnomaly(ctx context.Context, anomaly *domain.LatencyAnomaly) error
+	GetAnomaliesInRange(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]*domain.LatencyAnomaly, error)
+}
+
 // DiscoveryRepository defines the interface for network discovery persistence.
 type DiscoveryRepository interface {
 	CreateScan(ctx context.Context, scan *domain.DiscoveryScan) error
@@ -2,6 +2,9 @@ package ports
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -26,10 +29,12 @@ type MonitorService interface {
 	CreateMonitor(ctx context.Context, userID uuid.UUID, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, metadata map[string]string) (*domain.Monitor, error)
 	GetMonitor(ctx context.Context, id uuid.UUID) (*domain.Monitor, error)
 	GetMonitorsByAgent(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
+	GetEnabledMonitorsByAgent(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
 	ListMonitorsByTags(ctx context.Context, userID uuid.UUID, tags map[string]string) ([]*domain.Monitor, error)
 	UpdateMonitor(ctx context.Context, monitor *domain.Monitor) error
 	DeleteMonitor(ctx context.Context, id uuid.UUID) error
 	ProcessHeartbeat(ctx context.Context, heartbeat *domain.Heartbeat) error
+	EvaluateHeartbeat(ctx context.Context, heartbeat *domain.Heartbeat) error
 	MarkAgentMonitorsDown(ctx context.Context, agentID uuid.UUID) error
 	ResolveAgentMonitors(ctx context.Context, agentID uuid.UUID) error
 }
@@ -44,8 +49,15 @@ type IncidentService interface {
 	AcknowledgeIncident(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	ResolveIncident(ctx context.Context, id uuid.UUID) error
 	CreateIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
+	CreateDegradedIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
+	CreateAnomalyIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID, notify bool) (*domain.Incident, error)
 	CreateIncidentSilently(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
 	ResolveIncidentSilently(ctx context.Context, id uuid.UUID) error
+	SetIncidentFlapping(ctx context.Context, id uuid.UUID, flapping bool) error
+	SetIncidentSeverity(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error
+	DeclareIncident(ctx context.Context, incident *domain.Incident) error
+	SetIncidentOwner(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error
+	GetDeclaredIncidentsAffecting(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error)
 	NotifyAgentOffline(ctx context.Context, agentID uuid.UUID, affectedMonitors int)
 	NotifyAgentOnline(ctx context.Context, agentID uuid.UUID, resolvedIncidents int)
 	NotifyAgentMaintenance(ctx context.Context, agentID uuid.UUID, windowName string)
@@ -56,6 +68,75 @@ type InvestigationService interface {
 	Investigate(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentInvestigation, error)
 }
 
+// IncidentActivityService defines the interface for incident notes, status
+// change entries and postmortems.
+type IncidentActivityService interface {
+	ListActivity(ctx context.Context, incidentID uuid.UUID) ([]*domain.IncidentActivity, error)
+	AddNote(ctx context.Context, note *domain.IncidentActivity) error
+	EditNote(ctx context.Context, incidentID, noteID uuid.UUID, body, link string) (*domain.IncidentActivity, error)
+	DeleteNote(ctx context.Context, incidentID, noteID uuid.UUID) error
+	RecordStatusChange(ctx context.Context, incidentID, authorID uuid.UUID, status domain.IncidentStatus) error
+	GetPostmortem(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentPostmortem, error)
+	SavePostmortem(ctx context.Context, postmortem *domain.IncidentPostmortem, authorID uuid.UUID) error
+}
+
+// EscalationService defines the interface for managing escalation policies
+// and inspecting the escalations running for open incidents.
+type EscalationService interface {
+	ListPolicies(ctx context.Context, userID uuid.UUID) ([]*domain.EscalationPolicy, error)
+	GetPolicy(ctx context.Context, id uuid.UUID) (*domain.EscalationPolicy, error)
+	CreatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error
+	UpdatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error
+	DeletePolicy(ctx context.Context, id uuid.UUID) error
+	GetEscalation(ctx context.Context, incidentID uuid.UUID) (*domain.Escalation, error)
+}
+
+// AlertRuleService defines the interface for managing alert routing rules
+// and trying them against an event.
+type AlertRuleService interface {
+	ListRules(ctx context.Context, userID uuid.UUID) ([]*domain.AlertRule, error)
+	GetRule(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error)
+	CreateRule(ctx context.Context, rule *domain.AlertRule) error
+	UpdateRule(ctx context.Context, rule *domain.AlertRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	DryRun(ctx context.Context, event *domain.AlertEvent) (*domain.AlertRouting, error)
+}
+
+// NotificationDeliveryService defines the interface for the notification
+// delivery log: listing deliveries with their attempts and sending one again.
+type NotificationDeliveryService interface {
+	ListDeliveries(ctx context.Context, filter domain.NotificationDeliveryFilter) ([]*domain.NotificationDelivery, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*domain.NotificationDelivery, []*domain.NotificationAttempt, error)
+	Redeliver(ctx context.Context, delivery *domain.NotificationDelivery) (*domain.NotificationAttempt, error)
+}
+
+// AlertGroupService defines the interface for inspecting alert groups and
+// merging or splitting them by hand.
+type AlertGroupService interface {
+	ListGroups(ctx context.Context, userID uuid.UUID, status domain.AlertGroupStatus) ([]*domain.AlertGroup, error)
+	GetGroup(ctx context.Context, id uuid.UUID) (*domain.AlertGroup, error)
+	GetIncidents(ctx context.Context, groupID uuid.UUID) ([]*domain.Incident, error)
+	Merge(ctx context.Context, target *domain.AlertGroup, sources []*domain.AlertGroup) error
+	Split(ctx context.Context, group *domain.AlertGroup, incidentIDs []uuid.UUID) (*domain.AlertGroup, error)
+}
+
+// OnCallService defines the interface for managing on-call schedules and
+// resolving who is on call.
+type OnCallService interface {
+	ListSchedules(ctx context.Context, userID uuid.UUID) ([]*domain.OnCallSchedule, error)
+	GetSchedule(ctx context.Context, id uuid.UUID) (*domain.OnCallSchedule, error)
+	CreateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error
+	UpdateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error
+	DeleteSchedule(ctx context.Context, id uuid.UUID) error
+	ListOverrides(ctx context.Context, scheduleID uuid.UUID, from, to time.Time) ([]*domain.OnCallOverride, error)
+	GetOverride(ctx context.Context, id uuid.UUID) (*domain.OnCallOverride, error)
+	CreateOverride(ctx context.Context, override *domain.OnCallOverride) error
+	DeleteOverride(ctx context.Context, id uuid.UUID) error
+	WhoIsOnCall(ctx context.Context, scheduleID uuid.UUID, at time.Time) (*domain.OnCallShift, error)
+	Shifts(ctx context.Context, schedule *domain.OnCallSchedule, from, to time.Time) ([]domain.OnCallShift, error)
+	ResolveChannel(ctx context.Context, channel *domain.AlertChannel) (*domain.AlertChannel, error)
+}
+
 // AuditService defines the interface for security audit logging.
 type AuditService interface {
 	LogEvent(ctx context.Context, userID *uuid.UUID, action domain.AuditAction, ipAddress string, metadata map[string]string)
@@ -73,6 +154,46 @@ type Notifier interface {
 	NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error
 	NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error
 	NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error
+	NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error
+}
+
+// deliveryReceiptKey is the context key for a notifier's DeliveryReceipt.
+type deliveryReceiptKey struct{}
+
+// DeliveryReceipt is filled in by a notifier with the request it sent, for
+// the notification delivery log. Its methods do nothing on a nil receipt.
+type DeliveryReceipt struct {
+	PayloadHash string // hex SHA-256 of the request body
+	StatusCode  int    // HTTP status; 0 when there was no HTTP response
+}
+
+// SetPayload records the request body.
+func (r *DeliveryReceipt) SetPayload(body []byte) {
+	if r == nil {
+		return
+	}
+	sum := sha256.Sum256(body)
+	r.PayloadHash = hex.EncodeToString(sum[:])
+}
+
+// SetStatus records the HTTP response status.
+func (r *DeliveryReceipt) SetStatus(code int) {
+	if r == nil {
+		return
+	}
+	r.StatusCode = code
+}
+
+// WithDeliveryReceipt returns a context that notifiers record a receipt in.
+func WithDeliveryReceipt(ctx context.Context) (context.Context, *DeliveryReceipt) {
+	r := &DeliveryReceipt{}
+	return context.WithValue(ctx, deliveryReceiptKey{}, r), r
+}
+
+// DeliveryReceiptFromContext returns the context's receipt, or nil.
+func DeliveryReceiptFromContext(ctx context.Context) *DeliveryReceipt {
+	r, _ := ctx.Value(deliveryReceiptKey{}).(*DeliveryReceipt)
+	return r
 }
 
 // NotifierFactory creates a Notifier from an AlertChannel configuration.
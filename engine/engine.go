@@ -85,8 +85,17 @@ type Engine struct {
 	agentAuthSvc       ports.AgentAuthService
 	auditSvc           ports.AuditService
 	mwRepo             ports.MaintenanceWindowRepository
+	systemSettingsRepo ports.SystemSettingsRepository
 	traceRetentionSvc  *services.TraceRetention
 	logRetentionSvc    *services.LogRetention
+	pushSvc            *services.PushService
+	escalationSvc      *services.EscalationService
+	alertGroupSvc      *services.AlertGroupService
+	throttle           *services.NotificationThrottle
+	deliveryScheduler  *services.DeliveryScheduler
+	deliveryLog        *services.DeliveryLog
+	anomalySvc         *services.AnomalyService
+	ingestSvc          *services.HeartbeatIngestService
 
 	// Maintenance window background processing hooks.
 	mwExpiredHooks    []MaintenanceExpiredHook
@@ -195,19 +204,70 @@ func New(ctx context.Context) (*Engine, error) {
 	spanRepo := repository.NewSpanRepository(db)
 	logRecordRepo := repository.NewLogRecordRepository(db)
 	systemSettingsRepo := repository.NewSystemSettingsRepository(db)
-
-	// Notifiers
-	notifier := buildNotifier(cfg.Notify, logger)
+	dependencyRepo := repository.NewDependencyRepository(db)
+	anomalyRepo := repository.NewAnomalyRepository(db)
+	incidentActivityRepo := repository.NewIncidentActivityRepository(db)
+	escalationRepo := repository.NewEscalationRepository(db)
+	onCallRepo := repository.NewOnCallRepository(db)
+	alertGroupRepo := repository.NewAlertGroupRepository(db)
+	alertRuleRepo := repository.NewAlertRuleRepository(db)
+	notificationLogRepo := repository.NewNotificationLogRepository(db)
+	deferredNotificationRepo := repository.NewDeferredNotificationRepository(db)
+	messageTemplateRepo := repository.NewMessageTemplateRepository(db)
+	notificationDeliveryRepo := repository.NewNotificationDeliveryRepository(db)
 
 	// Services
 	auditSvc := services.NewAuditService(auditLogRepo, logger)
 	authSvc := services.NewAuthService(userRepo, agentRepo, usageEventRepo, hasher, encryptor, logger)
-	notifierFactory := notify.NewChannelNotifierFactory()
-	incidentSvc := services.NewIncidentService(incidentRepo, monitorRepo, agentRepo, heartbeatRepo, alertChannelRepo, notifier, notifierFactory, db, logger)
+	onCallSvc := services.NewOnCallService(onCallRepo, userRepo, alertChannelRepo, logger)
+	// Links in notifications point at the hub's public URL.
+	publicURL := cfg.Server.PublicURL
+	if publicURL == "" && len(cfg.Server.AllowedOrigins) > 0 {
+		publicURL = cfg.Server.AllowedOrigins[0]
+	}
+	notify.SetPublicURL(publicURL)
+	channelFactory := notify.NewChannelNotifierFactory()
+	channelFactory.SetTemplateRepo(messageTemplateRepo)
+	notifierFactory := services.NewOnCallNotifierFactory(channelFactory, onCallSvc)
+	// Every delivery is recorded; failed ones are retried, then dead-lettered.
+	deliveryLog := services.NewDeliveryLog(notificationDeliveryRepo, alertChannelRepo, notifierFactory, logger)
+
+	// Notifiers
+	notifier := buildNotifier(cfg.Notify, deliveryLog, logger)
+
+	// Incident and agent alerts go through the notification throttle;
+	// escalations page every level as configured.
+	throttle := services.NewNotificationThrottle(notificationLogRepo, alertChannelRepo, deliveryLog, notifier, domain.NotificationPolicy{
+		RateLimit:      cfg.Notify.RateLimitPerHour,
+		DedupWindow:    cfg.Notify.DedupWindow,
+		DigestInterval: cfg.Notify.DigestInterval,
+	}, logger)
+	// Channels with a delivery schedule hold or drop events outside it.
+	deliveryScheduler := services.NewDeliveryScheduler(deferredNotificationRepo, notificationLogRepo, alertChannelRepo, throttle, logger)
+	incidentSvc := services.NewIncidentService(incidentRepo, monitorRepo, agentRepo, heartbeatRepo, alertChannelRepo, throttle.Global(), deliveryScheduler, db, logger)
 	monitorSvc := services.NewMonitorService(monitorRepo, heartbeatRepo, incidentRepo, incidentSvc, userRepo, usageEventRepo, logger)
 	investigationSvc := services.NewInvestigationService(incidentRepo, monitorRepo, agentRepo, heartbeatRepo, certDetailsRepo, logger)
+	incidentSvc.SetDependencyRepo(dependencyRepo)
+	investigationSvc.SetDependencyRepo(dependencyRepo)
+	investigationSvc.SetAnomalyRepo(anomalyRepo)
+	investigationSvc.SetActivityRepo(incidentActivityRepo)
+	incidentActivitySvc := services.NewIncidentActivityService(incidentActivityRepo, incidentRepo)
+	escalationSvc := services.NewEscalationService(escalationRepo, agentRepo, alertChannelRepo, userRepo, logger)
+	escalationSvc.SetOnCallService(onCallSvc)
+	incidentSvc.SetEscalator(escalationSvc)
+	alertGroupSvc := services.NewAlertGroupService(alertGroupRepo, monitorRepo, agentRepo, cfg.Feature.AlertGroupWindow, logger)
+	alertGroupSvc.SetDependencyRepo(dependencyRepo)
+	alertGroupSvc.SetAnnouncer(incidentSvc)
+	if cfg.Feature.AlertGrouping {
+		incidentSvc.SetGrouper(alertGroupSvc)
+	}
 	traceRetentionSvc := services.NewTraceRetention(spanRepo, systemSettingsRepo, logger)
 	logRetentionSvc := services.NewLogRetention(logRecordRepo, systemSettingsRepo, logger)
+	pushSvc := services.NewPushService(monitorRepo, heartbeatRepo, monitorSvc, incidentSvc, db, logger)
+	anomalySvc := services.NewAnomalyService(
+		monitorRepo, incidentRepo, anomalyRepo, services.NewLatencyTrendService(heartbeatRepo),
+		incidentSvc, db, cfg.Feature.AnomalySigma, domain.AnomalyAction(cfg.Feature.AnomalyAction), logger,
+	)
 
 	// Module registry with defaults
 	reg := registry.New(logger)
@@ -215,7 +275,9 @@ func New(ctx context.Context) (*Engine, error) {
 		AuthService:    authSvc,
 		AgentAuth:      authSvc,
 		AgentRepo:      agentRepo,
-		Notifier:       notifier,
+		Notifier:       throttle.Global(),
+		AlertRuleRepo:  alertRuleRepo,
+		ChannelRepo:    alertChannelRepo,
 		AuditService:   auditSvc,
 		StatusPageRepo: statusPageRepo,
 		DB:             db,
@@ -224,16 +286,25 @@ func New(ctx context.Context) (*Engine, error) {
 		Logger:         logger,
 	})
 
+	// Alert routing rules pick the channels per-user notifications go to
+	alertRouter := reg.AlertRouter()
+	incidentSvc.SetAlertRouter(alertRouter)
+	alertRuleSvc := services.NewAlertRuleService(alertRuleRepo, alertChannelRepo, agentRepo, alertRouter)
+
 	// WebSocket hub (created before workflow wiring so discovery handlers can reference it)
 	hub := realtime.NewHub(logger)
 
 	// Wire workflow engine for durable alert dispatch + discovery
 	if wfEngine := reg.WorkflowEngine(); wfEngine != nil {
 		workflows.RegisterAlertHandlers(
-			wfEngine, notifier, notifierFactory,
+			wfEngine, throttle.Global(), deliveryScheduler, alertRouter,
 			agentRepo, heartbeatRepo, alertChannelRepo, incidentRepo, monitorRepo, logger,
 		)
 		incidentSvc.SetWorkflowEngine(wfEngine)
+		workflows.RegisterEscalationHandlers(
+			wfEngine, escalationRepo, incidentRepo, monitorRepo, alertChannelRepo, deliveryLog, onCallSvc, logger,
+		)
+		escalationSvc.SetWorkflowEngine(wfEngine)
 		logger.Info("durable alert dispatch enabled")
 	}
 
@@ -327,6 +398,16 @@ func New(ctx context.Context) (*Engine, error) {
 		LogRecordRepo:    logRecordRepo,
 		CertDetailsRepo:       certDetailsRepo,
 		MaintenanceWindowRepo: mwRepo,
+		DependencyRepo:        dependencyRepo,
+		IncidentActivityService: incidentActivitySvc,
+		EscalationService:     escalationSvc,
+		OnCallService:         onCallSvc,
+		DeferredNotificationRepo: deferredNotificationRepo,
+		MessageTemplateRepo:   messageTemplateRepo,
+		NotificationDeliveryService: deliveryLog,
+		AlertGroupService:     alertGroupSvc,
+		AlertRuleService:      alertRuleSvc,
+		PushService:           pushSvc,
 		Hub:                   hub,
 		Hasher:           hasher,
 		AuditService:     auditSvc,
@@ -348,6 +429,11 @@ func New(ctx context.Context) (*Engine, error) {
 
 	// Wire investigation service into the API handler
 	router.APIV1Handler().SetInvestigationService(investigationSvc)
+	router.APIV1Handler().SetIncidentActivityService(incidentActivitySvc)
+	router.APIV1Handler().SetEscalationService(escalationSvc)
+	router.APIV1Handler().SetConfigService(services.NewConfigService(
+		agentRepo, monitorRepo, alertChannelRepo, statusPageRepo, mwRepo, monitorSvc, authSvc, db, logger,
+	))
 
 	// Wire discovery service
 	discoveryRepo := repository.NewDiscoveryRepository(db)
@@ -410,6 +496,25 @@ func New(ctx context.Context) (*Engine, error) {
 	// Wire Prometheus heartbeat latency observer
 	router.WSHandler().SetHeartbeatTimer(prom.ObserveHeartbeat)
 
+	// Monitors read while processing heartbeats are served from memory.
+	monitorCache := repository.NewMonitorCache(monitorRepo, cfg.Ingest.MonitorCacheTTL)
+	monitorSvc.SetMonitorCache(monitorCache)
+	router.WSHandler().SetMonitorCache(monitorCache)
+
+	// Buffered heartbeat ingestion — agent heartbeats are queued per tenant
+	// and stored in batches instead of on each connection goroutine.
+	var ingestSvc *services.HeartbeatIngestService
+	if cfg.Ingest.Buffered {
+		ingestSvc = services.NewHeartbeatIngestService(heartbeatRepo, agentRepo, monitorSvc, db, repository.WithTenantID, services.HeartbeatIngestConfig{
+			QueueSize:     cfg.Ingest.QueueSize,
+			BatchSize:     cfg.Ingest.BatchSize,
+			FlushInterval: cfg.Ingest.FlushInterval,
+		}, logger)
+		ingestSvc.SetObserver(prom)
+		prom.SetHeartbeatQueueDepthFunc(ingestSvc.QueueDepth)
+		router.WSHandler().SetIngestService(ingestSvc)
+	}
+
 	// Wire metrics history for in-app dashboard
 	router.SystemAPIHandler().SetMetricsHistory(prom.History())
 
@@ -436,8 +541,17 @@ func New(ctx context.Context) (*Engine, error) {
 		agentAuthSvc:       authSvc,
 		auditSvc:           auditSvc,
 		mwRepo:             mwRepo,
+		systemSettingsRepo: systemSettingsRepo,
 		traceRetentionSvc:  traceRetentionSvc,
 		logRetentionSvc:    logRetentionSvc,
+		pushSvc:            pushSvc,
+		escalationSvc:      escalationSvc,
+		alertGroupSvc:      alertGroupSvc,
+		throttle:           throttle,
+		deliveryScheduler:  deliveryScheduler,
+		deliveryLog:        deliveryLog,
+		ingestSvc:          ingestSvc,
+		anomalySvc:         anomalySvc,
 
 		telemetryShutdown: telemetryShutdown,
 	}, nil
@@ -553,8 +667,8 @@ func (e *Engine) OnMaintenanceExpired(hook MaintenanceExpiredHook) {
 }
 
 // SetMaintenanceTenantProvider sets the provider for listing tenant IDs.
-// When set, the background maintenance ticker iterates over all tenants
-// instead of only the "default" tenant. EE sets this from the tenants table.
+// When set, the background maintenance, push deadline, anomaly and hub prober jobs
+// iterate over all tenants instead of only the "default" tenant. EE sets this from the tenants table.
 func (e *Engine) SetMaintenanceTenantProvider(p MaintenanceTenantProvider) {
 	e.mwTenantProvider = p
 }
@@ -577,6 +691,11 @@ func (e *Engine) Init(ctx context.Context) error {
 
 	e.router.RegisterRoutes()
 
+	// Heartbeat ingest flush workers.
+	if e.ingestSvc != nil {
+		e.ingestSvc.Start(ctx)
+	}
+
 	// Background maintenance window processor (60s tick).
 	if e.mwRepo != nil {
 		go e.runMaintenanceTicker(ctx)
@@ -590,9 +709,256 @@ func (e *Engine) Init(ctx context.Context) error {
 	// log records according to system_settings.log_retention_days.
 	e.logRetentionSvc.Start(ctx)
 
+	// Background heartbeat retention worker (hourly tick) — prunes raw
+	// heartbeats and their rollups according to system_settings and
+	// backfills the rollups from the raw rows that are kept.
+	services.NewHeartbeatRetention(e.heartbeatRepo, e.systemSettingsRepo, e.tenantIDs, repository.WithTenantID, e.logger).Start(ctx)
+
+	// Background push monitor deadline checks (30s tick). Runs on the hub so
+	// missed pings are caught even while the owning agent is offline.
+	go e.runPushTicker(ctx)
+
+	// Background escalation checks (30s tick) — resumes escalation
+	// workflows whose level delay has passed without an acknowledgement.
+	go e.runEscalationTicker(ctx)
+
+	// Background alert group checks (5s tick) — announces alert groups
+	// whose correlation window has ended.
+	if e.cfg.Feature.AlertGrouping {
+		go e.runAlertGroupTicker(ctx)
+	}
+
+	// Background notification digests (1m tick) — sends digests of batched
+	// low-severity events whose interval has passed and prunes the
+	// notification log hourly.
+	go e.runNotificationTicker(ctx)
+
+	// Background latency anomaly detection (5m tick) — learns each monitor's
+	// seasonal baseline and flags latency that deviates from it.
+	if e.cfg.Feature.AnomalyDetection {
+		go e.runAnomalyTicker(ctx)
+	}
+
+	// Hub prober — runs the http/tcp/dns/tls/ping checks of virtual hub
+	// agents in-process and feeds results through the agent heartbeat path.
+	if e.cfg.Feature.HubProber {
+		newHubProber(e.agentRepo, e.monitorRepo, e.db, e.router.WSHandler().ProcessHeartbeatPayload, e.tenantIDs, e.logger).Start(ctx)
+	}
+
 	return nil
 }
 
+// tenantIDs lists the tenants background jobs iterate over: all tenants when
+// a provider is set, otherwise only "default".
+func (e *Engine) tenantIDs(ctx context.Context) []string {
+	if e.mwTenantProvider != nil {
+		return e.mwTenantProvider(ctx)
+	}
+	return []string{"default"}
+}
+
+// runPushTicker opens incidents for push monitors that missed their deadline
+// every 30 seconds.
+func (e *Engine) runPushTicker(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.processPushDeadlines(ctx, now)
+		}
+	}
+}
+
+// processPushDeadlines checks push monitor deadlines for every tenant.
+func (e *Engine) processPushDeadlines(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.pushSvc.CheckOverdue(tCtx, now); err != nil {
+			e.logger.Error("push: failed to check deadlines",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// runEscalationTicker resumes due escalations every 30 seconds.
+func (e *Engine) runEscalationTicker(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.processEscalations(ctx, now)
+		}
+	}
+}
+
+// processEscalations resumes due escalations for every tenant.
+func (e *Engine) processEscalations(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.escalationSvc.ResumeDue(tCtx, now); err != nil {
+			e.logger.Error("escalation: failed to resume due escalations",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// runAlertGroupTicker announces due alert groups every 5 seconds.
+func (e *Engine) runAlertGroupTicker(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.processAlertGroups(ctx, now)
+		}
+	}
+}
+
+// processAlertGroups announces due alert groups for every tenant.
+func (e *Engine) processAlertGroups(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.alertGroupSvc.ProcessDue(tCtx, now); err != nil {
+			e.logger.Error("alert group: failed to announce due groups",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// runNotificationTicker sends due notification digests, deferred
+// notifications and delivery retries every minute and prunes the
+// notification log every hour.
+func (e *Engine) runNotificationTicker(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	lastPrune := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.processNotificationDigests(ctx, now)
+			e.processDeferredNotifications(ctx, now)
+			e.processNotificationRetries(ctx, now)
+			if now.Sub(lastPrune) >= time.Hour {
+				e.pruneNotificationLog(ctx, now)
+				lastPrune = now
+			}
+		}
+	}
+}
+
+// processNotificationDigests sends due notification digests for every tenant.
+func (e *Engine) processNotificationDigests(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.throttle.FlushDigests(tCtx, now); err != nil {
+			e.logger.Error("notification: failed to send digests",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// processDeferredNotifications sends notifications whose channel's delivery
+// window has opened, for every tenant.
+func (e *Engine) processDeferredNotifications(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.deliveryScheduler.DeliverDue(tCtx, now); err != nil {
+			e.logger.Error("notification: failed to send deferred notifications",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// processNotificationRetries retries failed notification deliveries that are
+// due, for every tenant.
+func (e *Engine) processNotificationRetries(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.deliveryLog.RetryDue(tCtx, now); err != nil {
+			e.logger.Error("notification: failed to retry deliveries",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// notificationLogRetention is how long notification log entries and
+// deliveries are kept.
+const notificationLogRetention = 30 * 24 * time.Hour
+
+// pruneNotificationLog deletes old notification log entries and deliveries
+// for every tenant.
+func (e *Engine) pruneNotificationLog(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.throttle.Prune(tCtx, now.Add(-notificationLogRetention)); err != nil {
+			e.logger.Error("notification: failed to prune log",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+		if _, err := e.deliveryLog.Prune(tCtx, now.Add(-notificationLogRetention)); err != nil {
+			e.logger.Error("notification: failed to prune deliveries",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// runAnomalyTicker runs latency anomaly detection every 5 minutes.
+func (e *Engine) runAnomalyTicker(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.processAnomalies(ctx, now)
+		}
+	}
+}
+
+// processAnomalies runs latency anomaly detection for every tenant.
+func (e *Engine) processAnomalies(ctx context.Context, now time.Time) {
+	for _, tenantID := range e.tenantIDs(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		if _, err := e.anomalySvc.Run(tCtx, now); err != nil {
+			e.logger.Error("anomaly: failed to run detection",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
 // runMaintenanceTicker processes expired maintenance windows every 60 seconds.
 func (e *Engine) runMaintenanceTicker(ctx context.Context) {
 	ticker := time.NewTicker(60 * time.Second)
@@ -718,6 +1084,11 @@ func (e *Engine) Shutdown(ctx context.Context) error {
 	e.hub.Stop()
 	e.router.Stop()
 
+	// Store the heartbeats still queued before the pool closes.
+	if e.ingestSvc != nil {
+		e.ingestSvc.Stop()
+	}
+
 	if err := e.reg.ShutdownAll(ctx); err != nil {
 		e.logger.Error("module shutdown error", slog.String("error", err.Error()))
 	}
@@ -737,46 +1108,47 @@ func (e *Engine) Shutdown(ctx context.Context) error {
 }
 
 // buildNotifier creates the appropriate notifier based on configuration.
-func buildNotifier(cfg config.NotifyConfig, logger *slog.Logger) notify.Notifier {
+// Each notifier's deliveries are recorded in the delivery log.
+func buildNotifier(cfg config.NotifyConfig, deliveries *services.DeliveryLog, logger *slog.Logger) notify.Notifier {
 	notify.SetBrandName(cfg.BrandName)
 
 	multi := notify.NewMultiNotifier()
 	count := 0
 
 	if cfg.SlackWebhookURL != "" {
-		multi.AddNotifier(notify.NewSlackNotifier(cfg.SlackWebhookURL))
+		multi.AddNotifier(deliveries.WrapGlobal("slack", notify.NewSlackNotifier(cfg.SlackWebhookURL)))
 		logger.Info("slack notifier enabled")
 		count++
 	}
 	if cfg.DiscordWebhookURL != "" {
-		multi.AddNotifier(notify.NewDiscordNotifier(cfg.DiscordWebhookURL))
+		multi.AddNotifier(deliveries.WrapGlobal("discord", notify.NewDiscordNotifier(cfg.DiscordWebhookURL)))
 		logger.Info("discord notifier enabled")
 		count++
 	}
 	if cfg.WebhookURL != "" {
-		multi.AddNotifier(notify.NewWebhookNotifier(cfg.WebhookURL, ""))
+		multi.AddNotifier(deliveries.WrapGlobal("webhook", notify.NewWebhookNotifier(cfg.WebhookURL, "")))
 		logger.Info("webhook notifier enabled")
 		count++
 	}
 	if cfg.SMTPHost != "" && cfg.SMTPFrom != "" && cfg.SMTPTo != "" {
-		multi.AddNotifier(notify.NewEmailNotifier(notify.EmailConfig{
+		multi.AddNotifier(deliveries.WrapGlobal("email", notify.NewEmailNotifier(notify.EmailConfig{
 			Host:     cfg.SMTPHost,
 			Port:     cfg.SMTPPort,
 			Username: cfg.SMTPUsername,
 			Password: cfg.SMTPPassword,
 			From:     cfg.SMTPFrom,
 			To:       cfg.SMTPTo,
-		}))
+		})))
 		logger.Info("email notifier enabled")
 		count++
 	}
 	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != "" {
-		multi.AddNotifier(notify.NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatID))
+		multi.AddNotifier(deliveries.WrapGlobal("telegram", notify.NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatID)))
 		logger.Info("telegram notifier enabled")
 		count++
 	}
 	if cfg.PagerDutyRoutingKey != "" {
-		multi.AddNotifier(notify.NewPagerDutyNotifier(cfg.PagerDutyRoutingKey))
+		multi.AddNotifier(deliveries.WrapGlobal("pagerduty", notify.NewPagerDutyNotifier(cfg.PagerDutyRoutingKey)))
 		logger.Info("pagerduty notifier enabled")
 		count++
 	}
@@ -0,0 +1,285 @@
+package engine
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sylvester-francis/watchdog-proto/protocol"
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+// hubMaxBodyBytes caps how much of an HTTP response body is kept for
+// assertions.
+const hubMaxBodyBytes = 64 << 10
+
+// runHubCheck executes a monitor's check in-process and reports the result in
+// the same shape an agent would send it. ctx bounds the whole check.
+func runHubCheck(ctx context.Context, m *domain.Monitor) *protocol.HeartbeatPayload {
+	payload := &protocol.HeartbeatPayload{MonitorID: m.ID.String()}
+	start := time.Now()
+
+	var err error
+	switch m.Type {
+	case domain.MonitorTypeHTTP:
+		err = hubCheckHTTP(ctx, m, payload)
+	case domain.MonitorTypeTCP:
+		err = hubCheckTCP(ctx, m.Target)
+	case domain.MonitorTypeDNS:
+		err = hubCheckDNS(ctx, m.Target, payload)
+	case domain.MonitorTypeTLS:
+		err = hubCheckTLS(ctx, m.Target, payload)
+	case domain.MonitorTypePing:
+		err = hubCheckPing(ctx, m.Target)
+	case domain.MonitorTypeTransaction:
+		err = hubCheckTransaction(ctx, m, payload)
+	default:
+		err = fmt.Errorf("monitor type %s cannot run on the hub", m.Type)
+	}
+
+	payload.LatencyMs = int(time.Since(start).Milliseconds())
+	switch {
+	case err == nil:
+		payload.Status = string(domain.HeartbeatStatusUp)
+	case errors.Is(err, context.DeadlineExceeded) || isTimeout(err):
+		payload.Status = string(domain.HeartbeatStatusTimeout)
+		payload.ErrorMessage = "check timed out"
+	default:
+		payload.Status = string(domain.HeartbeatStatusDown)
+		payload.ErrorMessage = err.Error()
+	}
+	return payload
+}
+
+// hubCheckHTTP requests the target and compares the status code with the
+// expected_status metadata, accepting any 2xx/3xx response when it is unset.
+func hubCheckHTTP(ctx context.Context, m *domain.Monitor, payload *protocol.HeartbeatPayload) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.Target, nil)
+	if err != nil {
+		return fmt.Errorf("invalid target: %w", err)
+	}
+	req.Header.Set("User-Agent", "WatchDog-Hub/1.0")
+
+	resp, err := hubHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	payload.Metadata, err = readHTTPResult(resp, len(m.Assertions) > 0)
+	if err != nil {
+		return err
+	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		days := certExpiryDays(resp.TLS.PeerCertificates[0])
+		payload.CertExpiryDays = &days
+		payload.CertIssuer = resp.TLS.PeerCertificates[0].Issuer.CommonName
+	}
+
+	if raw := m.Metadata["expected_status"]; raw != "" {
+		expected, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid expected_status %q", raw)
+		}
+		if resp.StatusCode != expected {
+			return fmt.Errorf("unexpected status %d, expected %d", resp.StatusCode, expected)
+		}
+		return nil
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// readHTTPResult reports a response in the shape assertions and transaction
+// extractions read: status code and size, plus the body excerpt and headers
+// when withContent is set. It reads the start of the body and drains the
+// rest, counting it so the size is exact and the connection can be reused.
+func readHTTPResult(resp *http.Response, withContent bool) (map[string]string, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, hubMaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	size := int64(len(body))
+	rest, _ := io.Copy(io.Discard, resp.Body)
+	size += rest
+
+	result := map[string]string{
+		domain.ResultStatusCode:   strconv.Itoa(resp.StatusCode),
+		domain.ResultResponseSize: strconv.FormatInt(size, 10),
+	}
+	if withContent {
+		result[domain.ResultBody] = string(body)
+		for name, values := range resp.Header {
+			result[domain.ResultHeaderPrefix+strings.ToLower(name)] = strings.Join(values, ", ")
+		}
+	}
+	return result, nil
+}
+
+// hubCheckTCP opens and closes a TCP connection to host:port.
+func hubCheckTCP(ctx context.Context, target string) error {
+	conn, err := hubDialer.DialContext(ctx, "tcp", target)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// hubCheckDNS resolves the target hostname and reports the addresses found.
+func hubCheckDNS(ctx context.Context, target string, payload *protocol.HeartbeatPayload) error {
+	addrs, err := net.DefaultResolver.LookupHost(ctx, target)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("no addresses for %s", target)
+	}
+	payload.Metadata = map[string]string{"resolved": strings.Join(addrs, ",")}
+	return nil
+}
+
+// hubCheckTLS performs a verified TLS handshake with the target (port 443 by
+// default) and reports the leaf certificate the way agents do, so cert
+// details are stored for hub-probed monitors as well.
+func hubCheckTLS(ctx context.Context, target string, payload *protocol.HeartbeatPayload) error {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		host, port = target, "443"
+	}
+
+	d := &tls.Dialer{NetDialer: hubDialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return errors.New("no peer certificate")
+	}
+	leaf := certs[0]
+	days := certExpiryDays(leaf)
+	payload.CertExpiryDays = &days
+	payload.CertIssuer = leaf.Issuer.CommonName
+	payload.Metadata = map[string]string{
+		"cert_algorithm":   leaf.PublicKeyAlgorithm.String(),
+		"cert_key_size":    strconv.Itoa(certKeySize(leaf)),
+		"cert_sans":        strings.Join(leaf.DNSNames, ","),
+		"cert_serial":      leaf.SerialNumber.String(),
+		"cert_chain_valid": "true",
+	}
+
+	if time.Now().After(leaf.NotAfter) {
+		return fmt.Errorf("certificate expired %s", leaf.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// hubCheckPing sends an ICMP echo request. Raw sockets need CAP_NET_RAW;
+// without it the check falls back to a TCP connect on port 443 then 80,
+// where a refused connection still proves the host is reachable.
+func hubCheckPing(ctx context.Context, target string) error {
+	err := icmpEcho(ctx, target)
+	if err == nil || !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+
+	for _, port := range []string{"443", "80"} {
+		conn, err := hubDialer.DialContext(ctx, "tcp", net.JoinHostPort(target, port))
+		if err == nil {
+			return conn.Close()
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return nil
+		}
+		if errors.Is(err, errHubDestinationBlocked) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("host %s unreachable", target)
+}
+
+// icmpEcho sends one ICMPv4 echo request and waits for the matching reply.
+func icmpEcho(ctx context.Context, target string) error {
+	conn, err := hubDialer.DialContext(ctx, "ip4:icmp", target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	id := uint16(os.Getpid() & 0xffff)
+	msg := []byte{8, 0, 0, 0, byte(id >> 8), byte(id), 0, 1, 'w', 'a', 't', 'c', 'h', 'd', 'o', 'g'}
+	binary.BigEndian.PutUint16(msg[2:], icmpChecksum(msg))
+	if _, err := conn.Write(msg); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 512)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return err
+		}
+		// Raw sockets see every ICMP packet; wait for our echo reply.
+		if n >= 8 && buf[0] == 0 && binary.BigEndian.Uint16(buf[4:]) == id {
+			return nil
+		}
+	}
+}
+
+// icmpChecksum computes the RFC 1071 internet checksum.
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	return ^uint16(sum)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func certExpiryDays(cert *x509.Certificate) int {
+	return int(time.Until(cert.NotAfter).Hours() / 24)
+}
+
+func certKeySize(cert *x509.Certificate) int {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return key.Curve.Params().BitSize
+	default:
+		return 256
+	}
+}
@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+func hubCheck(t *testing.T, m *domain.Monitor) (status, errMsg string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	payload := runHubCheck(ctx, m)
+	require.Equal(t, m.ID.String(), payload.MonitorID)
+	return payload.Status, payload.ErrorMessage
+}
+
+// allowHubLoopback lets hub checks reach the test's loopback servers.
+func allowHubLoopback(t *testing.T) {
+	t.Helper()
+	allowed := hubAddrAllowed
+	hubAddrAllowed = func(addr netip.Addr) bool { return addr.Unmap().IsLoopback() || allowed(addr) }
+	t.Cleanup(func() { hubAddrAllowed = allowed })
+}
+
+func TestRunHubCheck_HTTP(t *testing.T) {
+	allowHubLoopback(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ok := domain.NewMonitor(uuid.New(), "ok", domain.MonitorTypeHTTP, srv.URL)
+	status, _ := hubCheck(t, ok)
+	assert.Equal(t, "up", status)
+
+	broken := domain.NewMonitor(uuid.New(), "broken", domain.MonitorTypeHTTP, srv.URL+"/broken")
+	status, msg := hubCheck(t, broken)
+	assert.Equal(t, "down", status)
+	assert.Contains(t, msg, "500")
+
+	broken.Metadata["expected_status"] = "500"
+	status, _ = hubCheck(t, broken)
+	assert.Equal(t, "up", status, "expected_status overrides the 2xx/3xx default")
+}
+
+func TestRunHubCheck_HTTPReportsResponseForAssertions(t *testing.T) {
+	allowHubLoopback(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	m := domain.NewMonitor(uuid.New(), "api", domain.MonitorTypeHTTP, srv.URL)
+	payload := runHubCheck(context.Background(), m)
+	assert.Equal(t, "15", payload.Metadata[domain.ResultResponseSize])
+	assert.NotContains(t, payload.Metadata, domain.ResultBody, "body is only kept when assertions need it")
+
+	m.Assertions = []domain.Assertion{{Type: domain.AssertionJSONPath, Property: "$.status", Operator: domain.OpEquals, Target: "ok"}}
+	payload = runHubCheck(context.Background(), m)
+	assert.Equal(t, `{"status":"ok"}`, payload.Metadata[domain.ResultBody])
+	assert.Equal(t, "application/json", payload.Metadata[domain.ResultHeaderPrefix+"content-type"])
+	assert.Empty(t, m.FailedAssertion(payload.Metadata))
+}
+
+func TestRunHubCheck_TCP(t *testing.T) {
+	allowHubLoopback(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	m := domain.NewMonitor(uuid.New(), "tcp", domain.MonitorTypeTCP, addr)
+	status, _ := hubCheck(t, m)
+	assert.Equal(t, "up", status)
+
+	require.NoError(t, ln.Close())
+	status, msg := hubCheck(t, m)
+	assert.Equal(t, "down", status)
+	assert.NotEmpty(t, msg)
+}
+
+func TestRunHubCheck_BlocksInternalDestinations(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	for _, m := range []*domain.Monitor{
+		domain.NewMonitor(uuid.New(), "loopback", domain.MonitorTypeTCP, ln.Addr().String()),
+		domain.NewMonitor(uuid.New(), "private", domain.MonitorTypeTCP, "10.0.0.1:22"),
+		domain.NewMonitor(uuid.New(), "mapped", domain.MonitorTypeTCP, "[::ffff:127.0.0.1]:5432"),
+		domain.NewMonitor(uuid.New(), "localhost", domain.MonitorTypeHTTP, "http://localhost:"+strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)),
+		domain.NewMonitor(uuid.New(), "metadata", domain.MonitorTypeHTTP, "http://169.254.169.254/latest/meta-data/"),
+		domain.NewMonitor(uuid.New(), "ping", domain.MonitorTypePing, "127.0.0.1"),
+	} {
+		status, msg := hubCheck(t, m)
+		assert.Equal(t, "down", status, m.Name)
+		assert.Contains(t, msg, "not allowed", m.Name)
+	}
+}
+
+func TestHubAddrAllowed(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fd00:ec2::254":    false,
+		"::ffff:127.0.0.1": false,
+	} {
+		assert.Equal(t, want, hubAddrAllowed(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestRunHubCheck_UnsupportedType(t *testing.T) {
+	m := domain.NewMonitor(uuid.New(), "docker", domain.MonitorTypeDocker, "nginx")
+	status, msg := hubCheck(t, m)
+	assert.Equal(t, "down", status)
+	assert.Contains(t, msg, "cannot run on the hub")
+}
+
+func TestICMPChecksum(t *testing.T) {
+	msg := []byte{8, 0, 0, 0, 0x12, 0x34, 0, 1}
+	sum := icmpChecksum(msg)
+	msg[2], msg[3] = byte(sum>>8), byte(sum)
+	assert.Equal(t, uint16(0), icmpChecksum(msg), "a packet with its checksum filled in sums to zero")
+}
+
+func TestSameCheck(t *testing.T) {
+	a := domain.NewMonitor(uuid.New(), "a", domain.MonitorTypeHTTP, "https://example.com")
+	b := *a
+	b.Metadata = map[string]string{}
+	b.Name = "renamed"
+	assert.True(t, sameCheck(a, &b), "renames do not restart the check")
+
+	b.Metadata["expected_status"] = "204"
+	assert.False(t, sameCheck(a, &b))
+}
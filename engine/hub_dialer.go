@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errHubDestinationBlocked is returned when a hub check would connect to an
+// address inside the hub's own network.
+var errHubDestinationBlocked = errors.New("destination address is not allowed")
+
+// hubBlockedPrefixes are ranges outside the netip classifications that hub
+// checks must not reach either.
+var hubBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // reserved, incl. broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 of any IPv4 address
+}
+
+// hubAddrAllowed reports whether hub checks may connect to addr. Hub agents
+// are created by tenants, so their checks only reach public addresses:
+// never loopback, private, link-local (which includes cloud metadata at
+// 169.254.169.254) or other special-purpose ranges. Tests swap it to reach
+// their loopback servers.
+var hubAddrAllowed = func(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range hubBlockedPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// hubDialControl rejects a connection once its address is resolved, just
+// before the socket connects, so every address a name resolves to and every
+// redirect hop is checked.
+func hubDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		// Raw IP sockets (ping) carry no port.
+		host = address
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errHubDestinationBlocked, address)
+	}
+	if !hubAddrAllowed(addr) {
+		return fmt.Errorf("%w: %s", errHubDestinationBlocked, addr)
+	}
+	return nil
+}
+
+// hubDialer is the dialer every hub check connects through.
+var hubDialer = &net.Dialer{
+	Timeout: 30 * time.Second,
+	Control: hubDialControl,
+}
+
+// hubHTTPClient is shared by hub HTTP checks and transaction steps. Per-check
+// timeouts come from the request context. It ignores proxy settings, which
+// would hide the destination from hubDialer, and refuses redirects to
+// literal addresses hub checks may not reach before dialing them; redirects
+// to names are checked by hubDialer once resolved.
+var hubHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext:           hubDialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if addr, err := netip.ParseAddr(req.URL.Hostname()); err == nil && !hubAddrAllowed(addr) {
+			return fmt.Errorf("redirect to %s: %w", req.URL.Host, errHubDestinationBlocked)
+		}
+		return nil
+	},
+}
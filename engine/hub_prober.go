@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog-proto/protocol"
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/repository"
+)
+
+// hubProberSyncInterval is how often the hub prober reloads hub agents and
+// their monitors. Monitor changes are picked up within one interval.
+const hubProberSyncInterval = 30 * time.Second
+
+// hubReportFunc delivers a check result as if the agent had sent it.
+type hubReportFunc func(ctx context.Context, agentID uuid.UUID, payload *protocol.HeartbeatPayload)
+
+// hubProber runs the checks of virtual hub agents in-process. Hub agents are
+// ordinary rows in the agents table, so monitors, maintenance windows and SLA
+// reports treat them like any other agent; only the transport differs.
+type hubProber struct {
+	agentRepo   ports.AgentRepository
+	monitorRepo ports.MonitorRepository
+	transactor  ports.Transactor
+	report      hubReportFunc
+	tenants     func(ctx context.Context) []string
+	logger      *slog.Logger
+
+	mu    sync.Mutex
+	tasks map[hubTaskKey]*hubTask
+}
+
+// hubTaskKey identifies one monitor probed by one hub agent. A hub agent may
+// be one location of a multi-location monitor.
+type hubTaskKey struct {
+	agentID   uuid.UUID
+	monitorID uuid.UUID
+}
+
+type hubTask struct {
+	tenantID string
+	monitor  *domain.Monitor
+	cancel   context.CancelFunc
+}
+
+func newHubProber(
+	agentRepo ports.AgentRepository,
+	monitorRepo ports.MonitorRepository,
+	transactor ports.Transactor,
+	report hubReportFunc,
+	tenants func(ctx context.Context) []string,
+	logger *slog.Logger,
+) *hubProber {
+	return &hubProber{
+		agentRepo:   agentRepo,
+		monitorRepo: monitorRepo,
+		transactor:  transactor,
+		report:      report,
+		tenants:     tenants,
+		logger:      logger,
+		tasks:       make(map[hubTaskKey]*hubTask),
+	}
+}
+
+// Start syncs immediately and then on every hubProberSyncInterval until ctx
+// is cancelled, which also stops every running check.
+func (p *hubProber) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(hubProberSyncInterval)
+		defer ticker.Stop()
+
+		p.sync(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				p.stopAll()
+				return
+			case <-ticker.C:
+				p.sync(ctx)
+			}
+		}
+	}()
+}
+
+// sync loads every hub agent's enabled monitors across tenants, starts checks
+// for new or changed monitors and stops checks for removed ones.
+func (p *hubProber) sync(ctx context.Context) {
+	type desiredTask struct {
+		tenantID string
+		monitor  *domain.Monitor
+	}
+	desired := make(map[hubTaskKey]desiredTask)
+
+	for _, tenantID := range p.tenants(ctx) {
+		tCtx := repository.WithTenantID(ctx, tenantID)
+		err := p.transactor.WithTransaction(tCtx, func(txCtx context.Context) error {
+			agents, err := p.agentRepo.GetHubAgents(txCtx)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, agent := range agents {
+				if agent.Status != domain.AgentStatusOnline {
+					if err := p.agentRepo.UpdateStatus(txCtx, agent.ID, domain.AgentStatusOnline); err != nil {
+						return err
+					}
+				}
+				if err := p.agentRepo.UpdateLastSeen(txCtx, agent.ID, now); err != nil {
+					return err
+				}
+
+				monitors, err := p.monitorRepo.GetEnabledByAgentID(txCtx, agent.ID)
+				if err != nil {
+					return fmt.Errorf("monitors for hub agent %s: %w", agent.ID, err)
+				}
+				for _, m := range monitors {
+					if m.Type.IsHubProbeable() {
+						desired[hubTaskKey{agentID: agent.ID, monitorID: m.ID}] = desiredTask{tenantID: tenantID, monitor: m}
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			p.logger.Error("hub prober: failed to load hub agents",
+				slog.String("tenant_id", tenantID),
+				slog.String("error", err.Error()),
+			)
+			// Keep this tenant's checks running rather than dropping them.
+			p.mu.Lock()
+			for key, task := range p.tasks {
+				if task.tenantID == tenantID {
+					desired[key] = desiredTask{tenantID: tenantID, monitor: task.monitor}
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, task := range p.tasks {
+		if want, ok := desired[key]; !ok || !sameCheck(task.monitor, want.monitor) {
+			task.cancel()
+			delete(p.tasks, key)
+		}
+	}
+	for key, want := range desired {
+		if _, running := p.tasks[key]; running {
+			continue
+		}
+		taskCtx, cancel := context.WithCancel(repository.WithTenantID(ctx, want.tenantID))
+		p.tasks[key] = &hubTask{tenantID: want.tenantID, monitor: want.monitor, cancel: cancel}
+		go p.run(taskCtx, key.agentID, want.monitor)
+	}
+}
+
+// run checks the monitor every interval until ctx is cancelled.
+func (p *hubProber) run(ctx context.Context, agentID uuid.UUID, m *domain.Monitor) {
+	interval := time.Duration(m.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Duration(domain.DefaultIntervalSeconds) * time.Second
+	}
+	timeout := time.Duration(m.TimeoutSeconds) * time.Second
+	if timeout <= 0 || timeout > interval {
+		timeout = interval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		payload := runHubCheck(checkCtx, m)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		p.report(ctx, agentID, payload)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *hubProber) stopAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, task := range p.tasks {
+		task.cancel()
+		delete(p.tasks, key)
+	}
+}
+
+// sameCheck reports whether two versions of a monitor run the same check.
+func sameCheck(a, b *domain.Monitor) bool {
+	return a.Type == b.Type && a.Target == b.Target &&
+		a.IntervalSeconds == b.IntervalSeconds && a.TimeoutSeconds == b.TimeoutSeconds &&
+		maps.Equal(a.Metadata, b.Metadata) && slices.Equal(a.Assertions, b.Assertions) &&
+		transactionVersion(a) == transactionVersion(b)
+}
+
+func transactionVersion(m *domain.Monitor) int {
+	if m.Transaction == nil {
+		return 0
+	}
+	return m.Transaction.Version
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sylvester-francis/watchdog-proto/protocol"
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+// hubCheckTransaction runs a transaction monitor's steps in order, passing
+// extracted variables on to later steps, and reports per-step results the
+// way agents do. The first failing step fails the run and the remaining
+// steps are skipped.
+func hubCheckTransaction(ctx context.Context, m *domain.Monitor, payload *protocol.HeartbeatPayload) error {
+	if m.Transaction == nil || len(m.Transaction.Steps) == 0 {
+		return errors.New("transaction has no steps")
+	}
+
+	vars := make(map[string]string)
+	results := make([]domain.TransactionStepResult, len(m.Transaction.Steps))
+	var runErr error
+	for i, step := range m.Transaction.Steps {
+		results[i] = domain.TransactionStepResult{Name: step.Name, Status: domain.TransactionStepSkipped}
+		if runErr != nil {
+			continue
+		}
+
+		start := time.Now()
+		code, err := runTransactionStep(ctx, step, vars)
+		results[i].DurationMs = int(time.Since(start).Milliseconds())
+		results[i].StatusCode = code
+		if err != nil {
+			results[i].Status = domain.TransactionStepFailed
+			results[i].Error = err.Error()
+			runErr = fmt.Errorf("step %d (%s): %w", i+1, step.Name, err)
+			continue
+		}
+		results[i].Status = domain.TransactionStepPassed
+	}
+
+	steps, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	payload.Metadata = map[string]string{
+		domain.TransactionVersionKey: strconv.Itoa(m.Transaction.Version),
+		domain.TransactionStepsKey:   string(steps),
+	}
+	return runErr
+}
+
+// runTransactionStep sends one step's request with variables expanded,
+// checks its assertions (or, without any, that the status is below 400) and
+// stores its extractions in vars. Returns the response status code. Steps
+// go through hubHTTPClient, so neither a step nor a variable expanded into
+// its URL can reach the hub's internal network.
+func runTransactionStep(ctx context.Context, step domain.TransactionStep, vars map[string]string) (int, error) {
+	var body io.Reader
+	if step.Body != "" {
+		body = strings.NewReader(domain.ExpandVariables(step.Body, vars))
+	}
+	req, err := http.NewRequestWithContext(ctx, step.Method, domain.ExpandVariables(step.URL, vars), body)
+	if err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("User-Agent", "WatchDog-Hub/1.0")
+	for name, value := range step.Headers {
+		req.Header.Set(name, domain.ExpandVariables(value, vars))
+	}
+
+	resp, err := hubHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	result, err := readHTTPResult(resp, true)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if len(step.Assertions) > 0 {
+		if msg := domain.FirstFailedAssertion(step.Assertions, result); msg != "" {
+			return resp.StatusCode, errors.New(msg)
+		}
+	} else if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	for _, ex := range step.Extract {
+		value, ok := ex.Extract(result)
+		if !ok {
+			return resp.StatusCode, fmt.Errorf("could not extract %q (%s %s)", ex.Variable, ex.Source, ex.Expression)
+		}
+		vars[ex.Variable] = value
+	}
+	return resp.StatusCode, nil
+}
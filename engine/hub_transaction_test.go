@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+func TestHubCheckTransaction(t *testing.T) {
+	allowHubLoopback(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			_, _ = w.Write([]byte(`{"token":"t0k"}`))
+		case "/orders":
+			if r.Header.Get("Authorization") != "Bearer t0k" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"count":2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	m := domain.NewMonitor(uuid.New(), "checkout", domain.MonitorTypeTransaction, "")
+	m.SetTransaction([]domain.TransactionStep{
+		{
+			Name: "login", Method: http.MethodPost, URL: srv.URL + "/login",
+			Extract: []domain.Extraction{{Variable: "token", Source: domain.ExtractJSONPath, Expression: "$.token"}},
+		},
+		{
+			Name: "orders", Method: http.MethodGet, URL: srv.URL + "/orders",
+			Headers:    map[string]string{"Authorization": "Bearer {{token}}"},
+			Assertions: []domain.Assertion{{Type: domain.AssertionJSONPath, Property: "$.count", Operator: domain.OpGreaterThan, Target: "0"}},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	payload := runHubCheck(ctx, m)
+	assert.Equal(t, "up", payload.Status)
+	run, ok := domain.ParseTransactionRun(payload.Metadata, time.Now())
+	require.True(t, ok)
+	assert.Equal(t, 1, run.Version)
+	require.Len(t, run.Steps, 2)
+	assert.Equal(t, domain.TransactionStepPassed, run.Steps[1].Status)
+	assert.Equal(t, 200, run.Steps[1].StatusCode)
+
+	// A failing step fails the run and skips the rest.
+	steps := m.Transaction.Steps
+	steps = append([]domain.TransactionStep{{Name: "health", Method: http.MethodGet, URL: srv.URL + "/missing"}}, steps...)
+	m.SetTransaction(steps)
+	payload = runHubCheck(ctx, m)
+	assert.Equal(t, "down", payload.Status)
+	assert.Contains(t, payload.ErrorMessage, "step 1 (health)")
+	run, ok = domain.ParseTransactionRun(payload.Metadata, time.Now())
+	require.True(t, ok)
+	assert.Equal(t, 2, run.Version)
+	assert.Equal(t, domain.TransactionStepFailed, run.Steps[0].Status)
+	assert.Equal(t, domain.TransactionStepSkipped, run.Steps[2].Status)
+}
+
+func TestHubCheckTransaction_BlocksInternalDestinations(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, url := range []string{srv.URL + "/admin", "http://169.254.169.254/latest/meta-data/"} {
+		m := domain.NewMonitor(uuid.New(), "internal", domain.MonitorTypeTransaction, "")
+		m.SetTransaction([]domain.TransactionStep{{Name: "read", Method: http.MethodGet, URL: url}})
+
+		payload := runHubCheck(ctx, m)
+		assert.Equal(t, "down", payload.Status, url)
+		assert.Contains(t, payload.ErrorMessage, "not allowed", url)
+		run, ok := domain.ParseTransactionRun(payload.Metadata, time.Now())
+		require.True(t, ok)
+		assert.Equal(t, domain.TransactionStepFailed, run.Steps[0].Status)
+	}
+	assert.Zero(t, hits, "a step aimed at 127.0.0.1 must not connect")
+
+	// Every redirect hop is checked too.
+	allowHubLoopback(t)
+	m := domain.NewMonitor(uuid.New(), "redirect", domain.MonitorTypeTransaction, "")
+	m.SetTransaction([]domain.TransactionStep{{Name: "read", Method: http.MethodGet, URL: srv.URL + "/admin"}})
+	payload := runHubCheck(ctx, m)
+	assert.Equal(t, "down", payload.Status)
+	assert.Contains(t, payload.ErrorMessage, "not allowed")
+	assert.Equal(t, 1, hits)
+}
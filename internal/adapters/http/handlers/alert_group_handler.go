@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+)
+
+// AlertGroupHandler serves endpoints for listing alert groups and merging
+// or splitting them by hand.
+type AlertGroupHandler struct {
+	groupSvc    ports.AlertGroupService
+	monitorRepo ports.MonitorRepository
+	auditSvc    ports.AuditService
+}
+
+// NewAlertGroupHandler creates a new AlertGroupHandler.
+func NewAlertGroupHandler(groupSvc ports.AlertGroupService, monitorRepo ports.MonitorRepository, auditSvc ports.AuditService) *AlertGroupHandler {
+	return &AlertGroupHandler{groupSvc: groupSvc, monitorRepo: monitorRepo, auditSvc: auditSvc}
+}
+
+type alertGroupResponse struct {
+	ID             string  `json:"id"`
+	Reason         string  `json:"reason"`
+	Title          string  `json:"title"`
+	Status         string  `json:"status"`
+	LeadIncidentID *string `json:"lead_incident_id"`
+	IncidentCount  int     `json:"incident_count"`
+	CreatedAt      string  `json:"created_at"`
+	LastIncidentAt string  `json:"last_incident_at"`
+	NotifiedAt     *string `json:"notified_at"`
+	ResolvedAt     *string `json:"resolved_at"`
+	// Incidents lists the group's members; only set for a single group.
+	Incidents []incidentResponse `json:"incidents,omitempty"`
+}
+
+type alertGroupMergeRequest struct {
+	GroupIDs []string `json:"group_ids"`
+}
+
+type alertGroupSplitRequest struct {
+	IncidentIDs []string `json:"incident_ids"`
+}
+
+func toAlertGroupResponse(g *domain.AlertGroup) alertGroupResponse {
+	resp := alertGroupResponse{
+		ID:             g.ID.String(),
+		Reason:         string(g.Reason),
+		Title:          g.Title,
+		Status:         string(g.Status),
+		IncidentCount:  g.IncidentCount,
+		CreatedAt:      g.CreatedAt.Format(time.RFC3339),
+		LastIncidentAt: g.LastIncidentAt.Format(time.RFC3339),
+	}
+	if g.LeadIncidentID != nil {
+		id := g.LeadIncidentID.String()
+		resp.LeadIncidentID = &id
+	}
+	if g.NotifiedAt != nil {
+		t := g.NotifiedAt.Format(time.RFC3339)
+		resp.NotifiedAt = &t
+	}
+	if g.ResolvedAt != nil {
+		t := g.ResolvedAt.Format(time.RFC3339)
+		resp.ResolvedAt = &t
+	}
+	return resp
+}
+
+// group resolves the :id path parameter to a group owned by the user,
+// writing the error response when it cannot.
+func (h *AlertGroupHandler) group(c echo.Context, userID uuid.UUID) (*domain.AlertGroup, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errJSON(c, http.StatusBadRequest, "invalid alert group ID")
+	}
+	return h.ownedGroup(c, id, userID)
+}
+
+func (h *AlertGroupHandler) ownedGroup(c echo.Context, id, userID uuid.UUID) (*domain.AlertGroup, error) {
+	group, err := h.groupSvc.GetGroup(c.Request().Context(), id)
+	if err != nil {
+		return nil, errJSON(c, http.StatusInternalServerError, "failed to fetch alert group")
+	}
+	if group == nil || group.UserID != userID {
+		return nil, errJSON(c, http.StatusNotFound, "alert group not found: "+id.String())
+	}
+	return group, nil
+}
+
+// withIncidents returns the group's response with its member incidents.
+func (h *AlertGroupHandler) withIncidents(c echo.Context, group *domain.AlertGroup) (alertGroupResponse, error) {
+	ctx := c.Request().Context()
+	incidents, err := h.groupSvc.GetIncidents(ctx, group.ID)
+	if err != nil {
+		return alertGroupResponse{}, err
+	}
+
+	resp := toAlertGroupResponse(group)
+	resp.IncidentCount = len(incidents)
+	resp.Incidents = make([]incidentResponse, 0, len(incidents))
+	names := make(map[uuid.UUID]string)
+	for _, i := range incidents {
+		name, ok := names[i.MonitorID]
+		if !ok {
+			if m, err := h.monitorRepo.GetByID(ctx, i.MonitorID); err == nil && m != nil {
+				name = m.Name
+			}
+			names[i.MonitorID] = name
+		}
+		resp.Incidents = append(resp.Incidents, toIncidentResponse(i, name))
+	}
+	return resp, nil
+}
+
+// List returns the authenticated user's alert groups, newest first.
+// GET /api/v1/alert-groups?status=open|resolved
+func (h *AlertGroupHandler) List(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	status := domain.AlertGroupStatus(c.QueryParam("status"))
+	if status != "" && !status.IsValid() {
+		return errJSON(c, http.StatusBadRequest, "status must be open or resolved")
+	}
+
+	groups, err := h.groupSvc.ListGroups(c.Request().Context(), userID, status)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch alert groups")
+	}
+
+	result := make([]alertGroupResponse, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, toAlertGroupResponse(g))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// Get returns an alert group with its incidents.
+// GET /api/v1/alert-groups/:id
+func (h *AlertGroupHandler) Get(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	group, err := h.group(c, userID)
+	if group == nil {
+		return err
+	}
+
+	resp, err := h.withIncidents(c, group)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch alert group incidents")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": resp})
+}
+
+// Merge moves the incidents of other open groups into this one and deletes
+// them.
+// POST /api/v1/alert-groups/:id/merge
+func (h *AlertGroupHandler) Merge(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	target, err := h.group(c, userID)
+	if target == nil {
+		return err
+	}
+
+	var req alertGroupMergeRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	sources := make([]*domain.AlertGroup, 0, len(req.GroupIDs))
+	for _, raw := range req.GroupIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "invalid alert group ID: "+raw)
+		}
+		source, err := h.ownedGroup(c, id, userID)
+		if source == nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+
+	if err := h.groupSvc.Merge(ctx, target, sources); err != nil {
+		return alertGroupError(c, err, "failed to merge alert groups")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditAlertGroupMerged, c.RealIP(), map[string]string{
+			"alert_group_id": target.ID.String(), "merged": strings.Join(req.GroupIDs, ","),
+		})
+	}
+
+	resp, err := h.withIncidents(c, target)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch alert group incidents")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": resp})
+}
+
+// Split moves the given incidents out of this group into a new one.
+// POST /api/v1/alert-groups/:id/split
+func (h *AlertGroupHandler) Split(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	group, err := h.group(c, userID)
+	if group == nil {
+		return err
+	}
+
+	var req alertGroupSplitRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	ids := make([]uuid.UUID, 0, len(req.IncidentIDs))
+	for _, raw := range req.IncidentIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "invalid incident ID: "+raw)
+		}
+		ids = append(ids, id)
+	}
+
+	split, err := h.groupSvc.Split(ctx, group, ids)
+	if err != nil {
+		return alertGroupError(c, err, "failed to split alert group")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditAlertGroupSplit, c.RealIP(), map[string]string{
+			"alert_group_id": group.ID.String(), "new_alert_group_id": split.ID.String(),
+		})
+	}
+
+	resp, err := h.withIncidents(c, split)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch alert group incidents")
+	}
+	return c.JSON(http.StatusCreated, map[string]any{"data": resp})
+}
+
+// alertGroupError maps AlertGroupService errors to responses.
+func alertGroupError(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, domain.ErrInvalidAlertGroupChange) {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+	return errJSON(c, http.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+)
+
+// AlertRuleHandler serves CRUD endpoints for alert routing rules and a
+// dry-run that shows where an incident or agent event would be sent.
+type AlertRuleHandler struct {
+	ruleSvc     ports.AlertRuleService
+	incidentSvc ports.IncidentService
+	monitorRepo ports.MonitorRepository
+	agentRepo   ports.AgentRepository
+	auditSvc    ports.AuditService
+}
+
+// NewAlertRuleHandler creates a new AlertRuleHandler.
+func NewAlertRuleHandler(ruleSvc ports.AlertRuleService, incidentSvc ports.IncidentService, monitorRepo ports.MonitorRepository, agentRepo ports.AgentRepository, auditSvc ports.AuditService) *AlertRuleHandler {
+	return &AlertRuleHandler{ruleSvc: ruleSvc, incidentSvc: incidentSvc, monitorRepo: monitorRepo, agentRepo: agentRepo, auditSvc: auditSvc}
+}
+
+type alertRuleResponse struct {
+	ID         string                `json:"id"`
+	Name       string                `json:"name"`
+	Position   int                   `json:"position"`
+	Enabled    bool                  `json:"enabled"`
+	Match      domain.AlertRuleMatch `json:"match"`
+	Action     string                `json:"action"`
+	ChannelIDs []string              `json:"channel_ids"`
+	CreatedAt  string                `json:"created_at"`
+	UpdatedAt  string                `json:"updated_at"`
+}
+
+type alertRuleRequest struct {
+	Name       string                `json:"name"`
+	Position   int                   `json:"position"`
+	Enabled    *bool                 `json:"enabled"`
+	Match      domain.AlertRuleMatch `json:"match"`
+	Action     string                `json:"action"`
+	ChannelIDs []string              `json:"channel_ids"`
+}
+
+type alertRuleDryRunRequest struct {
+	IncidentID string `json:"incident_id"`
+	AgentID    string `json:"agent_id"`
+	Event      string `json:"event"`
+	At         string `json:"at"`
+}
+
+type alertRuleHitResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+type alertRoutedChannelResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type alertRuleDryRunResponse struct {
+	Event        string                       `json:"event"`
+	MatchedRules []alertRuleHitResponse       `json:"matched_rules"`
+	Suppressed   bool                         `json:"suppressed"`
+	DefaultRoute bool                         `json:"default_route"`
+	Channels     []alertRoutedChannelResponse `json:"channels"`
+}
+
+func toAlertRuleResponse(r *domain.AlertRule) alertRuleResponse {
+	channelIDs := make([]string, len(r.ChannelIDs))
+	for i, id := range r.ChannelIDs {
+		channelIDs[i] = id.String()
+	}
+	return alertRuleResponse{
+		ID:         r.ID.String(),
+		Name:       r.Name,
+		Position:   r.Position,
+		Enabled:    r.Enabled,
+		Match:      r.Match,
+		Action:     string(r.Action),
+		ChannelIDs: channelIDs,
+		CreatedAt:  r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// parseChannelIDs converts request channel IDs, returning a message for the
+// first malformed one.
+func parseChannelIDs(raw []string) ([]uuid.UUID, string) {
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, "invalid channel ID: " + s
+		}
+		ids = append(ids, id)
+	}
+	return ids, ""
+}
+
+// rule resolves the :id path parameter to a rule owned by the user, writing
+// the error response when it cannot.
+func (h *AlertRuleHandler) rule(c echo.Context, userID uuid.UUID) (*domain.AlertRule, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errJSON(c, http.StatusBadRequest, "invalid rule ID")
+	}
+	rule, err := h.ruleSvc.GetRule(c.Request().Context(), id)
+	if err != nil {
+		return nil, errJSON(c, http.StatusInternalServerError, "failed to fetch alert rule")
+	}
+	if rule == nil || rule.UserID != userID {
+		return nil, errJSON(c, http.StatusNotFound, "alert rule not found")
+	}
+	return rule, nil
+}
+
+// List returns the authenticated user's alert rules in evaluation order.
+// GET /api/v1/alert-rules
+func (h *AlertRuleHandler) List(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	rules, err := h.ruleSvc.ListRules(c.Request().Context(), userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch alert rules")
+	}
+
+	result := make([]alertRuleResponse, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, toAlertRuleResponse(r))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// Get returns a single alert rule.
+// GET /api/v1/alert-rules/:id
+func (h *AlertRuleHandler) Get(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	rule, err := h.rule(c, userID)
+	if rule == nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": toAlertRuleResponse(rule)})
+}
+
+// Create creates a new alert rule.
+// POST /api/v1/alert-rules
+func (h *AlertRuleHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req alertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	channelIDs, msg := parseChannelIDs(req.ChannelIDs)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	rule, err := domain.NewAlertRule(userID, req.Name, req.Position, req.Match, domain.AlertRuleAction(req.Action), channelIDs)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := h.ruleSvc.CreateRule(ctx, rule); err != nil {
+		return alertRuleError(c, err, "failed to create alert rule")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditAlertRuleCreated, c.RealIP(), map[string]string{
+			"rule_id": rule.ID.String(), "name": rule.Name, "action": string(rule.Action),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": toAlertRuleResponse(rule)})
+}
+
+// Update replaces an alert rule's name, position, conditions, action and
+// channels.
+// PUT /api/v1/alert-rules/:id
+func (h *AlertRuleHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	rule, err := h.rule(c, userID)
+	if rule == nil {
+		return err
+	}
+
+	var req alertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	channelIDs, msg := parseChannelIDs(req.ChannelIDs)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	rule.Name = req.Name
+	rule.Position = req.Position
+	rule.Match = req.Match
+	rule.Action = domain.AlertRuleAction(req.Action)
+	rule.ChannelIDs = channelIDs
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := h.ruleSvc.UpdateRule(ctx, rule); err != nil {
+		return alertRuleError(c, err, "failed to update alert rule")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditAlertRuleUpdated, c.RealIP(), map[string]string{
+			"rule_id": rule.ID.String(), "name": rule.Name, "action": string(rule.Action),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toAlertRuleResponse(rule)})
+}
+
+// Delete removes an alert rule.
+// DELETE /api/v1/alert-rules/:id
+func (h *AlertRuleHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	rule, err := h.rule(c, userID)
+	if rule == nil {
+		return err
+	}
+
+	if err := h.ruleSvc.DeleteRule(ctx, rule.ID); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to delete alert rule")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditAlertRuleDeleted, c.RealIP(), map[string]string{
+			"rule_id": rule.ID.String(), "name": rule.Name,
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DryRun shows which rules an incident or agent event matches and which
+// channels it would be sent to, without sending anything. The event defaults
+// to incident_opened for an incident and agent_offline for an agent; at
+// defaults to now.
+// POST /api/v1/alert-rules/dry-run
+func (h *AlertRuleHandler) DryRun(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req alertRuleDryRunRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if (req.IncidentID == "") == (req.AgentID == "") {
+		return errJSON(c, http.StatusBadRequest, "exactly one of incident_id or agent_id is required")
+	}
+	at := time.Now()
+	if req.At != "" {
+		t, err := time.Parse(time.RFC3339, req.At)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "at must be an RFC 3339 timestamp")
+		}
+		at = t
+	}
+
+	var event *domain.AlertEvent
+	if req.IncidentID != "" {
+		kind := domain.AlertEventKind(req.Event)
+		if kind == "" {
+			kind = domain.AlertEventIncidentOpened
+		}
+		if kind != domain.AlertEventIncidentOpened && kind != domain.AlertEventIncidentResolved {
+			return errJSON(c, http.StatusBadRequest, "event must be incident_opened or incident_resolved for an incident")
+		}
+		incidentID, err := uuid.Parse(req.IncidentID)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "invalid incident ID")
+		}
+		incident, err := verifyIncidentOwnership(ctx, h.incidentSvc, h.monitorRepo, h.agentRepo, incidentID, userID)
+		if err != nil {
+			return errJSON(c, http.StatusInternalServerError, "failed to fetch incident")
+		}
+		if incident == nil {
+			return errJSON(c, http.StatusNotFound, "incident not found")
+		}
+		var monitor *domain.Monitor
+		if incident.IsDeclared() {
+			monitor = incident.DeclaredMonitor()
+		} else if monitor, err = h.monitorRepo.GetByID(ctx, incident.MonitorID); err != nil || monitor == nil {
+			return errJSON(c, http.StatusInternalServerError, "failed to fetch monitor")
+		}
+		event = domain.NewIncidentAlertEvent(userID, incident, monitor, kind == domain.AlertEventIncidentOpened)
+	} else {
+		kind := domain.AlertEventKind(req.Event)
+		if kind == "" {
+			kind = domain.AlertEventAgentOffline
+		}
+		if kind != domain.AlertEventAgentOffline && kind != domain.AlertEventAgentOnline {
+			return errJSON(c, http.StatusBadRequest, "event must be agent_offline or agent_online for an agent")
+		}
+		agentID, err := uuid.Parse(req.AgentID)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "invalid agent ID")
+		}
+		agent, err := h.agentRepo.GetByID(ctx, agentID)
+		if err != nil {
+			return errJSON(c, http.StatusInternalServerError, "failed to fetch agent")
+		}
+		if agent == nil || agent.UserID != userID {
+			return errJSON(c, http.StatusNotFound, "agent not found")
+		}
+		event = domain.NewAgentAlertEvent(userID, agent.ID, kind == domain.AlertEventAgentOnline)
+	}
+	event.At = at
+
+	routing, err := h.ruleSvc.DryRun(ctx, event)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to evaluate alert rules")
+	}
+
+	resp := alertRuleDryRunResponse{
+		Event:        string(event.Kind),
+		MatchedRules: make([]alertRuleHitResponse, 0, len(routing.Hits)),
+		Suppressed:   routing.Suppressed,
+		DefaultRoute: routing.Default,
+		Channels:     make([]alertRoutedChannelResponse, 0, len(routing.Channels)),
+	}
+	for _, hit := range routing.Hits {
+		resp.MatchedRules = append(resp.MatchedRules, alertRuleHitResponse{ID: hit.RuleID.String(), Name: hit.Name, Action: string(hit.Action)})
+	}
+	for _, ch := range routing.Channels {
+		resp.Channels = append(resp.Channels, alertRoutedChannelResponse{ID: ch.ID.String(), Name: ch.Name, Type: string(ch.Type)})
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": resp})
+}
+
+// alertRuleError maps AlertRuleService errors to responses.
+func alertRuleError(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, domain.ErrInvalidAlertRule) {
+		return errJSON(c, http.StatusBadRequest, errors.Unwrap(err).Error())
+	}
+	return errJSON(c, http.StatusInternalServerError, fallback)
+}
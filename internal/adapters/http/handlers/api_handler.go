@@ -218,7 +218,7 @@ func (h *APIHandler) DashboardStats(c echo.Context) error {
 	activeIncidents := 0
 	if err == nil {
 		for _, inc := range allIncidents {
-			if _, ok := userMonitorIDs[inc.MonitorID]; ok {
+			if _, ok := userMonitorIDs[inc.MonitorID]; ok || inc.DeclaredBy(userID) {
 				activeIncidents++
 			}
 		}
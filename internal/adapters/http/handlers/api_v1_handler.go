@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,7 +34,10 @@ type APIV1Handler struct {
 	hub              *realtime.Hub
 	auditSvc         ports.AuditService
 	investigationSvc ports.InvestigationService
+	activitySvc      ports.IncidentActivityService
+	escalationSvc    ports.EscalationService
 	updateSvc        *services.UpdateService
+	configSvc        *services.ConfigService
 }
 
 // NewAPIV1Handler creates a new APIV1Handler.
@@ -60,25 +66,330 @@ func NewAPIV1Handler(
 }
 
 type monitorResponse struct {
-	ID               string            `json:"id"`
-	AgentID          string            `json:"agent_id"`
-	AgentName        string            `json:"agent_name"`
-	Name             string            `json:"name"`
-	Type             string            `json:"type"`
-	Target           string            `json:"target"`
-	Status           string            `json:"status"`
-	Enabled          bool              `json:"enabled"`
-	Interval         int               `json:"interval_seconds"`
-	Timeout          int               `json:"timeout_seconds"`
-	FailureThreshold int               `json:"failure_threshold"`
-	Metadata         map[string]string `json:"metadata,omitempty"`
-	SLATargetPercent *float64          `json:"sla_target_percent,omitempty"`
+	ID                string            `json:"id"`
+	AgentID           string            `json:"agent_id"`
+	AgentName         string            `json:"agent_name"`
+	Name              string            `json:"name"`
+	Type              string            `json:"type"`
+	Target            string            `json:"target"`
+	Status            string            `json:"status"`
+	Enabled           bool              `json:"enabled"`
+	Interval          int               `json:"interval_seconds"`
+	Timeout           int               `json:"timeout_seconds"`
+	FailureThreshold  int               `json:"failure_threshold"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	SLATargetPercent  *float64          `json:"sla_target_percent,omitempty"`
+	Degraded          *degradedRulesDTO `json:"degraded,omitempty"`
+	RecoveryThreshold int               `json:"recovery_threshold"`
+	FlapDetection     *flapDetectionDTO `json:"flap_detection,omitempty"`
+	Locations         *locationsDTO     `json:"locations,omitempty"`
+	Push              *pushDTO          `json:"push,omitempty"`
+	Assertions        assertionsDTO     `json:"assertions,omitempty"`
+	Transaction       *transactionDTO   `json:"transaction,omitempty"`
+	Severity          string            `json:"severity"`
+	EscalationPolicyID *string          `json:"escalation_policy_id"`
+}
+
+// transactionDTO is the JSON shape of a transaction monitor's definition.
+// Only steps is read from requests; the hub assigns versions. LastRun is only
+// populated on single-monitor reads.
+type transactionDTO struct {
+	Version int                      `json:"version,omitempty"`
+	Steps   []domain.TransactionStep `json:"steps"`
+	LastRun *transactionRunDTO       `json:"last_run,omitempty"`
+}
+
+// transactionRunDTO renders the latest run of a transaction monitor as a
+// per-step waterfall.
+type transactionRunDTO struct {
+	Version    int                    `json:"version"`
+	At         time.Time              `json:"at"`
+	DurationMs int                    `json:"duration_ms"`
+	Waterfall  []domain.WaterfallStep `json:"waterfall"`
+}
+
+// assertionsDTO is the JSON shape of an HTTP monitor's assertions, shared by
+// responses and create/update requests.
+type assertionsDTO []domain.Assertion
+
+// pushDTO is the JSON shape of a push monitor: where its job pings and how
+// late a ping may be. Only grace_seconds is read from requests.
+type pushDTO struct {
+	URL          string     `json:"url,omitempty"`
+	GraceSeconds *int       `json:"grace_seconds,omitempty"`
+	LastPingAt   *time.Time `json:"last_ping_at,omitempty"`
+	NextDueAt    *time.Time `json:"next_due_at,omitempty"`
+}
+
+// locationsDTO is the JSON shape of a multi-location monitor: the agents that
+// probe it and the number of failing locations that mark it down (0 = majority).
+// Breakdown is only populated on single-monitor reads.
+type locationsDTO struct {
+	AgentIDs  []string                `json:"agent_ids"`
+	Quorum    int                     `json:"quorum"`
+	Breakdown []domain.LocationStatus `json:"breakdown,omitempty"`
+}
+
+// flapDetectionDTO is the JSON shape of a monitor's flap detector settings.
+// A window of 0 disables detection.
+type flapDetectionDTO struct {
+	Window           int `json:"window"`
+	ThresholdPercent int `json:"threshold_percent"`
+}
+
+// degradedRulesDTO is the JSON shape of a monitor's degraded rules, shared by
+// responses and create/update requests. Request fields left nil are unchanged;
+// a latency_ms or failure_percent of 0 disables that rule.
+type degradedRulesDTO struct {
+	LatencyMs      *int `json:"latency_ms,omitempty"`
+	LatencyChecks  *int `json:"latency_checks,omitempty"`
+	FailurePercent *int `json:"failure_percent,omitempty"`
+	Window         *int `json:"window,omitempty"`
+}
+
+// toMonitorResponse converts a domain Monitor into its JSON DTO.
+func toMonitorResponse(m *domain.Monitor, agentName string) monitorResponse {
+	resp := monitorResponse{
+		ID:                m.ID.String(),
+		AgentID:           m.AgentID.String(),
+		AgentName:         agentName,
+		Name:              m.Name,
+		Type:              string(m.Type),
+		Target:            m.Target,
+		Status:            string(m.Status),
+		Enabled:           m.Enabled,
+		Interval:          m.IntervalSeconds,
+		Timeout:           m.TimeoutSeconds,
+		FailureThreshold:  m.FailureThreshold,
+		Metadata:          m.Metadata,
+		SLATargetPercent:  m.SLATargetPercent,
+		RecoveryThreshold: m.RecoveryThreshold,
+		Assertions:        m.Assertions,
+		Severity:          string(m.Severity),
+	}
+	if m.EscalationPolicyID != nil {
+		id := m.EscalationPolicyID.String()
+		resp.EscalationPolicyID = &id
+	}
+	if m.IsMultiLocation() {
+		ids := make([]string, len(m.LocationAgentIDs))
+		for i, id := range m.LocationAgentIDs {
+			ids[i] = id.String()
+		}
+		resp.Locations = &locationsDTO{AgentIDs: ids, Quorum: m.EffectiveQuorum()}
+	}
+	if m.IsPush() {
+		grace, due := m.PushGraceSeconds, m.PushDeadline()
+		resp.Push = &pushDTO{
+			URL:          "/api/v1/push/" + m.PushToken,
+			GraceSeconds: &grace,
+			LastPingAt:   m.LastPingAt,
+			NextDueAt:    &due,
+		}
+	}
+	if m.Transaction != nil {
+		resp.Transaction = &transactionDTO{Version: m.Transaction.Version, Steps: m.Transaction.Steps}
+	}
+	if m.FlapWindow > 0 {
+		resp.FlapDetection = &flapDetectionDTO{Window: m.FlapWindow, ThresholdPercent: m.FlapThresholdPercent}
+	}
+	if m.HasDegradedRules() {
+		checks, window := m.DegradedLatencyChecks, m.DegradedWindow
+		resp.Degraded = &degradedRulesDTO{
+			LatencyMs:      m.DegradedLatencyMs,
+			LatencyChecks:  &checks,
+			FailurePercent: m.DegradedFailurePercent,
+			Window:         &window,
+		}
+	}
+	return resp
+}
+
+// applyDegradedRules validates and applies requested degraded rules to a monitor.
+// Returns a client-facing error message, or "" on success.
+func applyDegradedRules(m *domain.Monitor, req *degradedRulesDTO) string {
+	if req == nil {
+		return ""
+	}
+	if req.LatencyMs != nil || req.LatencyChecks != nil {
+		threshold, checks := 0, m.DegradedLatencyChecks
+		if m.DegradedLatencyMs != nil {
+			threshold = *m.DegradedLatencyMs
+		}
+		if req.LatencyMs != nil {
+			threshold = *req.LatencyMs
+		}
+		if req.LatencyChecks != nil {
+			checks = *req.LatencyChecks
+		}
+		if !m.SetDegradedLatency(threshold, checks) {
+			return fmt.Sprintf("degraded.latency_ms must be positive and degraded.latency_checks between %d and %d", domain.MinDegradedLatencyChecks, domain.MaxDegradedLatencyChecks)
+		}
+	}
+	if req.FailurePercent != nil || req.Window != nil {
+		percent, window := 0, m.DegradedWindow
+		if m.DegradedFailurePercent != nil {
+			percent = *m.DegradedFailurePercent
+		}
+		if req.FailurePercent != nil {
+			percent = *req.FailurePercent
+		}
+		if req.Window != nil {
+			window = *req.Window
+		}
+		if !m.SetDegradedFailureRate(percent, window) {
+			return fmt.Sprintf("degraded.failure_percent must be between 1 and 100 and degraded.window between %d and %d", domain.MinDegradedWindow, domain.MaxDegradedWindow)
+		}
+	}
+	return ""
+}
+
+// applyRecoveryRules validates and applies the requested recovery threshold and
+// flap detector settings. Returns a client-facing error message, or "" on success.
+func applyRecoveryRules(m *domain.Monitor, recoveryThreshold *int, flap *flapDetectionDTO) string {
+	if recoveryThreshold != nil && !m.SetRecoveryThreshold(*recoveryThreshold) {
+		return fmt.Sprintf("recovery_threshold must be between %d and %d", domain.MinRecoveryThreshold, domain.MaxRecoveryThreshold)
+	}
+	if flap != nil {
+		percent := flap.ThresholdPercent
+		if percent == 0 {
+			percent = domain.DefaultFlapThresholdPercent
+		}
+		if !m.SetFlapDetection(flap.Window, percent) {
+			return fmt.Sprintf("flap_detection.window must be 0 or between %d and %d and flap_detection.threshold_percent between 1 and 100", domain.MinFlapWindow, domain.MaxFlapWindow)
+		}
+	}
+	return ""
+}
+
+// validateAssertions checks requested assertions before they are applied.
+// Only HTTP monitors report the response values assertions run against.
+// Returns a client-facing error message, or "" on success.
+func validateAssertions(t domain.MonitorType, assertions assertionsDTO) string {
+	if len(assertions) == 0 {
+		return ""
+	}
+	if t != domain.MonitorTypeHTTP {
+		return "assertions are only supported on http monitors"
+	}
+	if err := domain.ValidateAssertions(assertions); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// applyPushSchedule validates and applies the interval and grace period of a
+// push monitor. Push monitors accept intervals of up to a week. Returns a
+// client-facing error message, or "" on success.
+func applyPushSchedule(m *domain.Monitor, interval *int, req *pushDTO) string {
+	if interval == nil && (req == nil || req.GraceSeconds == nil) {
+		return ""
+	}
+	seconds, grace := m.IntervalSeconds, m.PushGraceSeconds
+	if interval != nil {
+		seconds = *interval
+	}
+	if req != nil && req.GraceSeconds != nil {
+		grace = *req.GraceSeconds
+	}
+	if !m.SetPushSchedule(seconds, grace) {
+		return fmt.Sprintf("interval_seconds must be between %d and %d and push.grace_seconds between 0 and %d", domain.MinIntervalSeconds, domain.MaxPushIntervalSeconds, domain.MaxPushGraceSeconds)
+	}
+	return ""
+}
+
+// validateTransaction checks a requested transaction definition against the
+// monitor type. Returns a client-facing error message, or "" on success.
+func validateTransaction(t domain.MonitorType, req *transactionDTO) string {
+	if t != domain.MonitorTypeTransaction {
+		if req != nil {
+			return "transaction is only supported on transaction monitors"
+		}
+		return ""
+	}
+	if req == nil {
+		return "transaction monitors require transaction.steps"
+	}
+	if err := domain.ValidateTransactionSteps(req.Steps); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// errHubMonitorType is returned when a monitor that needs local access is
+// assigned to a hub agent.
+const errHubMonitorType = "hub agents only run http, tcp, dns, tls, ping and transaction monitors"
+
+// applyLocations validates and applies a requested probe set to a monitor.
+// Every agent must belong to userID. Returns a client-facing error message,
+// or "" on success.
+func (h *APIV1Handler) applyLocations(ctx context.Context, m *domain.Monitor, req *locationsDTO, userID uuid.UUID) string {
+	if req == nil {
+		return ""
+	}
+	agentIDs := make([]uuid.UUID, 0, len(req.AgentIDs))
+	for _, raw := range req.AgentIDs {
+		agentID, err := uuid.Parse(raw)
+		if err != nil {
+			return "invalid agent id in locations.agent_ids"
+		}
+		if agentID != m.AgentID {
+			agent, err := h.agentRepo.GetByID(ctx, agentID)
+			if err != nil || agent == nil || agent.UserID != userID {
+				return "agent not found or not owned by you"
+			}
+			if agent.Hub && !m.Type.IsHubProbeable() {
+				return errHubMonitorType
+			}
+		}
+		agentIDs = append(agentIDs, agentID)
+	}
+	if !m.SetLocations(agentIDs, req.Quorum) {
+		return fmt.Sprintf("locations allow at most %d agents and quorum must be between 0 and the number of locations", domain.MaxMonitorLocations)
+	}
+	return ""
+}
+
+// locationBreakdown returns the per-location state of a multi-location monitor
+// with agent names resolved.
+func (h *APIV1Handler) locationBreakdown(ctx context.Context, m *domain.Monitor) []domain.LocationStatus {
+	recent, err := h.heartbeatRepo.GetRecentPerAgent(ctx, m.ID, 20)
+	if err != nil {
+		return nil
+	}
+	locations := m.LocationBreakdown(recent, time.Now())
+	for i := range locations {
+		if agent, err := h.agentRepo.GetByID(ctx, locations[i].AgentID); err == nil && agent != nil {
+			locations[i].AgentName = agent.Name
+		}
+	}
+	return locations
+}
+
+// dispatchTask sends the monitor's task to every probing agent, or cancels
+// it everywhere when the monitor is disabled.
+func (h *APIV1Handler) dispatchTask(m *domain.Monitor) {
+	// Push monitors are pinged by their jobs; no agent runs them.
+	if m.IsPush() {
+		return
+	}
+	msg := protocol.NewTaskCancelMessage(m.ID.String())
+	if m.Enabled {
+		msg = protocol.NewTaskMessageWithMetadata(
+			m.ID.String(), string(m.Type),
+			m.Target, m.IntervalSeconds, m.TimeoutSeconds, m.TaskMetadata(),
+		)
+	}
+	for _, agentID := range m.ProbeAgentIDs() {
+		h.hub.SendToAgent(agentID, msg)
+	}
 }
 
 type agentResponse struct {
 	ID         string  `json:"id"`
 	Name       string  `json:"name"`
 	Status     string  `json:"status"`
+	Hub        bool    `json:"hub"`
 	LastSeenAt *string `json:"last_seen_at"`
 }
 
@@ -91,6 +402,64 @@ type incidentResponse struct {
 	ResolvedAt     *string `json:"resolved_at"`
 	AcknowledgedAt *string `json:"acknowledged_at"`
 	TTRSeconds     *int    `json:"ttr_seconds"`
+	Kind           string  `json:"kind"`
+	Severity       string  `json:"severity"`
+	// ParentIncidentID is set when the incident is suppressed under an
+	// upstream dependency's incident.
+	ParentIncidentID *string `json:"parent_incident_id,omitempty"`
+	// AlertGroupID is set when the incident was correlated into an alert group.
+	AlertGroupID *string `json:"alert_group_id,omitempty"`
+	// The fields below are set on declared incidents, which have no monitor.
+	Title              string   `json:"title,omitempty"`
+	Description        string   `json:"description,omitempty"`
+	OwnerID            *string  `json:"owner_id,omitempty"`
+	AffectedMonitorIDs []string `json:"affected_monitor_ids,omitempty"`
+}
+
+// toIncidentResponse converts an incident. Declared incidents report their
+// title as the monitor name and no monitor ID.
+func toIncidentResponse(i *domain.Incident, monitorName string) incidentResponse {
+	resp := incidentResponse{
+		ID:          i.ID.String(),
+		MonitorID:   i.MonitorID.String(),
+		MonitorName: monitorName,
+		Status:      string(i.Status),
+		StartedAt:   i.StartedAt.Format(time.RFC3339),
+		TTRSeconds:  i.TTRSeconds,
+		Kind:        string(i.Kind),
+		Severity:    string(i.Severity),
+	}
+	if i.ResolvedAt != nil {
+		t := i.ResolvedAt.Format(time.RFC3339)
+		resp.ResolvedAt = &t
+	}
+	if i.ParentIncidentID != nil {
+		p := i.ParentIncidentID.String()
+		resp.ParentIncidentID = &p
+	}
+	if i.AlertGroupID != nil {
+		g := i.AlertGroupID.String()
+		resp.AlertGroupID = &g
+	}
+	if i.AcknowledgedAt != nil {
+		t := i.AcknowledgedAt.Format(time.RFC3339)
+		resp.AcknowledgedAt = &t
+	}
+	if i.IsDeclared() {
+		resp.MonitorID = ""
+		resp.MonitorName = i.Title
+		resp.Title = i.Title
+		resp.Description = i.Description
+		if i.OwnerID != nil {
+			o := i.OwnerID.String()
+			resp.OwnerID = &o
+		}
+		resp.AffectedMonitorIDs = make([]string, 0, len(i.AffectedMonitorIDs))
+		for _, id := range i.AffectedMonitorIDs {
+			resp.AffectedMonitorIDs = append(resp.AffectedMonitorIDs, id.String())
+		}
+	}
+	return resp
 }
 
 // ListMonitors returns all monitors for the authenticated user.
@@ -133,21 +502,7 @@ func (h *APIV1Handler) ListMonitors(c echo.Context) error {
 
 	monitors := make([]monitorResponse, 0, len(sourceMonitors))
 	for _, m := range sourceMonitors {
-		monitors = append(monitors, monitorResponse{
-			ID:               m.ID.String(),
-			AgentID:          m.AgentID.String(),
-			AgentName:        agentNames[m.AgentID],
-			Name:             m.Name,
-			Type:             string(m.Type),
-			Target:           m.Target,
-			Status:           string(m.Status),
-			Enabled:          m.Enabled,
-			Interval:         m.IntervalSeconds,
-			Timeout:          m.TimeoutSeconds,
-			FailureThreshold: m.FailureThreshold,
-			Metadata:         m.Metadata,
-			SLATargetPercent: m.SLATargetPercent,
-		})
+		monitors = append(monitors, toMonitorResponse(m, agentNames[m.AgentID]))
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{
@@ -212,22 +567,23 @@ func (h *APIV1Handler) GetMonitor(c echo.Context) error {
 		}
 	}
 
+	resp := toMonitorResponse(monitor, agent.Name)
+	resp.Metadata = meta
+	if resp.Locations != nil {
+		resp.Locations.Breakdown = h.locationBreakdown(ctx, monitor)
+	}
+	if resp.Transaction != nil && monitor.LastTransactionRun != nil {
+		run := monitor.LastTransactionRun
+		waterfall := run.Waterfall()
+		duration := 0
+		if n := len(waterfall); n > 0 {
+			duration = waterfall[n-1].OffsetMs + waterfall[n-1].DurationMs
+		}
+		resp.Transaction.LastRun = &transactionRunDTO{Version: run.Version, At: run.At, DurationMs: duration, Waterfall: waterfall}
+	}
+
 	return c.JSON(http.StatusOK, map[string]any{
-		"data": monitorResponse{
-			ID:               monitor.ID.String(),
-			AgentID:          monitor.AgentID.String(),
-			AgentName:        agent.Name,
-			Name:             monitor.Name,
-			Type:             string(monitor.Type),
-			Target:           monitor.Target,
-			Status:           string(monitor.Status),
-			Enabled:          monitor.Enabled,
-			Interval:         monitor.IntervalSeconds,
-			Timeout:          monitor.TimeoutSeconds,
-			FailureThreshold: monitor.FailureThreshold,
-			SLATargetPercent: monitor.SLATargetPercent,
-			Metadata:         meta,
-		},
+		"data": resp,
 		"heartbeats": map[string]any{
 			"latencies":   latencies,
 			"uptime_up":   up,
@@ -257,6 +613,7 @@ func (h *APIV1Handler) ListAgents(c echo.Context) error {
 			ID:     a.ID.String(),
 			Name:   a.Name,
 			Status: string(a.Status),
+			Hub:    a.Hub,
 		}
 		if a.LastSeenAt != nil {
 			t := a.LastSeenAt.Format(time.RFC3339)
@@ -276,6 +633,9 @@ func (h *APIV1Handler) ListAgents(c echo.Context) error {
 
 // ListIncidents returns all incidents for the authenticated user.
 // GET /api/v1/incidents?status=open|acknowledged|resolved|all
+//
+// Optional query: `severity=critical,major` keeps only incidents with one of
+// the listed severities.
 func (h *APIV1Handler) ListIncidents(c echo.Context) error {
 	ctx := c.Request().Context()
 	userID, ok := middleware.GetUserID(c)
@@ -283,9 +643,13 @@ func (h *APIV1Handler) ListIncidents(c echo.Context) error {
 		return errJSON(c, http.StatusUnauthorized, "unauthorized")
 	}
 
+	severities, err := parseSeverityFilter(c.QueryParam("severity"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
 	status := c.QueryParam("status")
 	var rawIncidents []*domain.Incident
-	var err error
 
 	switch status {
 	case "resolved":
@@ -308,8 +672,8 @@ func (h *APIV1Handler) ListIncidents(c echo.Context) error {
 		return errJSON(c, http.StatusInternalServerError, "failed to fetch incidents")
 	}
 
-	// Filter incidents to only those belonging to the user's monitors.
-	// Build a monitor name map while iterating.
+	// Filter incidents to only those belonging to the user's monitors, or
+	// declared by the user. Build a monitor name map while iterating.
 	agents, err := h.agentRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return errJSON(c, http.StatusInternalServerError, "failed to fetch incidents")
@@ -327,26 +691,13 @@ func (h *APIV1Handler) ListIncidents(c echo.Context) error {
 
 	var result []incidentResponse
 	for _, i := range rawIncidents {
-		if _, owns := monitorNames[i.MonitorID]; !owns {
+		if _, owns := monitorNames[i.MonitorID]; !owns && !i.DeclaredBy(userID) {
 			continue
 		}
-		resp := incidentResponse{
-			ID:          i.ID.String(),
-			MonitorID:   i.MonitorID.String(),
-			MonitorName: monitorNames[i.MonitorID],
-			Status:      string(i.Status),
-			StartedAt:   i.StartedAt.Format(time.RFC3339),
-			TTRSeconds:  i.TTRSeconds,
-		}
-		if i.ResolvedAt != nil {
-			t := i.ResolvedAt.Format(time.RFC3339)
-			resp.ResolvedAt = &t
-		}
-		if i.AcknowledgedAt != nil {
-			t := i.AcknowledgedAt.Format(time.RFC3339)
-			resp.AcknowledgedAt = &t
+		if severities != nil && !severities[i.Severity] {
+			continue
 		}
-		result = append(result, resp)
+		result = append(result, toIncidentResponse(i, monitorNames[i.MonitorID]))
 	}
 
 	if result == nil {
@@ -358,18 +709,44 @@ func (h *APIV1Handler) ListIncidents(c echo.Context) error {
 	})
 }
 
+// parseSeverityFilter parses a comma-separated severity list. An empty list
+// returns a nil set, meaning no filter.
+func parseSeverityFilter(raw string) (map[domain.IncidentSeverity]bool, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	set := make(map[domain.IncidentSeverity]bool)
+	for _, part := range strings.Split(raw, ",") {
+		severity := domain.IncidentSeverity(strings.ToLower(strings.TrimSpace(part)))
+		if !severity.IsValid() {
+			return nil, domain.ErrInvalidIncidentSeverity
+		}
+		set[severity] = true
+	}
+	return set, nil
+}
+
 // --- CRUD endpoints ---
 
 type createMonitorRequest struct {
-	AgentID          string            `json:"agent_id"`
-	Name             string            `json:"name"`
-	Type             string            `json:"type"`
-	Target           string            `json:"target"`
-	Interval         int               `json:"interval_seconds"`
-	Timeout          int               `json:"timeout_seconds"`
-	FailureThreshold *int              `json:"failure_threshold,omitempty"`
-	Metadata         map[string]string `json:"metadata,omitempty"`
-	SLATargetPercent *float64          `json:"sla_target_percent,omitempty"`
+	AgentID           string            `json:"agent_id"`
+	Name              string            `json:"name"`
+	Type              string            `json:"type"`
+	Target            string            `json:"target"`
+	Interval          int               `json:"interval_seconds"`
+	Timeout           int               `json:"timeout_seconds"`
+	FailureThreshold  *int              `json:"failure_threshold,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	SLATargetPercent  *float64          `json:"sla_target_percent,omitempty"`
+	Degraded          *degradedRulesDTO `json:"degraded,omitempty"`
+	RecoveryThreshold *int              `json:"recovery_threshold,omitempty"`
+	FlapDetection     *flapDetectionDTO `json:"flap_detection,omitempty"`
+	Locations         *locationsDTO     `json:"locations,omitempty"`
+	Push              *pushDTO          `json:"push,omitempty"`
+	Assertions        assertionsDTO     `json:"assertions,omitempty"`
+	Transaction       *transactionDTO   `json:"transaction,omitempty"`
+	Severity          string            `json:"severity,omitempty"`
+	EscalationPolicyID string           `json:"escalation_policy_id,omitempty"`
 }
 
 // CreateMonitor creates a new monitor.
@@ -386,13 +763,31 @@ func (h *APIV1Handler) CreateMonitor(c echo.Context) error {
 		return errJSON(c, http.StatusBadRequest, "invalid request body")
 	}
 
-	if req.Name == "" || req.Type == "" || req.Target == "" || req.AgentID == "" {
+	// Push monitors have no target: their job pings the hub instead.
+	// Transaction monitors start at their first step's URL.
+	isPush := domain.MonitorType(req.Type) == domain.MonitorTypePush
+	if domain.MonitorType(req.Type) == domain.MonitorTypeTransaction && req.Transaction != nil && len(req.Transaction.Steps) > 0 {
+		req.Target = req.Transaction.Steps[0].URL
+	}
+	if req.Name == "" || req.Type == "" || (req.Target == "" && !isPush) || req.AgentID == "" {
 		return errJSON(c, http.StatusBadRequest, "name, type, target, and agent_id are required")
 	}
 
 	if !domain.MonitorType(req.Type).IsValid() {
 		return errJSON(c, http.StatusBadRequest, fmt.Sprintf("invalid monitor type: %s", req.Type))
 	}
+	if isPush && req.Locations != nil {
+		return errJSON(c, http.StatusBadRequest, "push monitors cannot have locations")
+	}
+	if msg := validateAssertions(domain.MonitorType(req.Type), req.Assertions); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	if msg := validateTransaction(domain.MonitorType(req.Type), req.Transaction); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	if req.Severity != "" && !domain.IncidentSeverity(req.Severity).IsValid() {
+		return errJSON(c, http.StatusBadRequest, domain.ErrInvalidIncidentSeverity.Error())
+	}
 
 	agentID, err := uuid.Parse(req.AgentID)
 	if err != nil {
@@ -404,6 +799,9 @@ func (h *APIV1Handler) CreateMonitor(c echo.Context) error {
 	if err != nil || agent == nil || agent.UserID != userID {
 		return errJSON(c, http.StatusNotFound, "agent not found")
 	}
+	if agent.Hub && !domain.MonitorType(req.Type).IsHubProbeable() && !isPush {
+		return errJSON(c, http.StatusBadRequest, errHubMonitorType)
+	}
 
 	monitor, err := h.monitorSvc.CreateMonitor(ctx, userID, agentID, req.Name, domain.MonitorType(req.Type), req.Target, req.Metadata)
 	if err != nil {
@@ -414,7 +812,15 @@ func (h *APIV1Handler) CreateMonitor(c echo.Context) error {
 	}
 
 	// Apply optional interval/timeout/failure_threshold
-	if req.Interval > 0 {
+	if monitor.IsPush() {
+		var interval *int
+		if req.Interval > 0 {
+			interval = &req.Interval
+		}
+		if msg := applyPushSchedule(monitor, interval, req.Push); msg != "" {
+			return errJSON(c, http.StatusBadRequest, msg)
+		}
+	} else if req.Interval > 0 {
 		monitor.SetInterval(req.Interval)
 	}
 	if req.Timeout > 0 {
@@ -434,18 +840,39 @@ func (h *APIV1Handler) CreateMonitor(c echo.Context) error {
 		}
 		monitor.SLATargetPercent = req.SLATargetPercent
 	}
-	if req.Interval > 0 || req.Timeout > 0 || req.FailureThreshold != nil || req.SLATargetPercent != nil {
+	if msg := applyDegradedRules(monitor, req.Degraded); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	if msg := applyRecoveryRules(monitor, req.RecoveryThreshold, req.FlapDetection); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	if msg := h.applyLocations(ctx, monitor, req.Locations, userID); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	monitor.Assertions = req.Assertions
+	if req.Transaction != nil {
+		monitor.SetTransaction(req.Transaction.Steps)
+	}
+	if req.Severity != "" {
+		monitor.Severity = domain.IncidentSeverity(req.Severity)
+	}
+	if req.EscalationPolicyID != "" {
+		policyID, msg := h.resolveEscalationPolicy(ctx, req.EscalationPolicyID, userID)
+		if msg != "" {
+			return errJSON(c, http.StatusBadRequest, msg)
+		}
+		monitor.EscalationPolicyID = policyID
+	}
+	if req.Interval > 0 || req.Timeout > 0 || req.FailureThreshold != nil || req.SLATargetPercent != nil || req.Degraded != nil ||
+		req.RecoveryThreshold != nil || req.FlapDetection != nil || req.Locations != nil || req.Push != nil || len(req.Assertions) > 0 ||
+		req.Transaction != nil || req.Severity != "" || req.EscalationPolicyID != "" {
 		if err := h.monitorSvc.UpdateMonitor(ctx, monitor); err != nil {
 			return errJSON(c, http.StatusInternalServerError, "monitor created but failed to apply settings")
 		}
 	}
 
-	// Notify agent if connected
-	taskMsg := protocol.NewTaskMessageWithMetadata(
-		monitor.ID.String(), string(monitor.Type),
-		monitor.Target, monitor.IntervalSeconds, monitor.TimeoutSeconds, monitor.Metadata,
-	)
-	h.hub.SendToAgent(monitor.AgentID, taskMsg)
+	// Notify agents if connected
+	h.dispatchTask(monitor)
 
 	// H-011: audit monitor creation.
 	if h.auditSvc != nil {
@@ -455,32 +882,28 @@ func (h *APIV1Handler) CreateMonitor(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusCreated, map[string]any{
-		"data": monitorResponse{
-			ID:               monitor.ID.String(),
-			AgentID:          monitor.AgentID.String(),
-			AgentName:        agent.Name,
-			Name:             monitor.Name,
-			Type:             string(monitor.Type),
-			Target:           monitor.Target,
-			Status:           string(monitor.Status),
-			Enabled:          monitor.Enabled,
-			Interval:         monitor.IntervalSeconds,
-			Timeout:          monitor.TimeoutSeconds,
-			FailureThreshold: monitor.FailureThreshold,
-			SLATargetPercent: monitor.SLATargetPercent,
-		},
+		"data": toMonitorResponse(monitor, agent.Name),
 	})
 }
 
 type updateMonitorRequest struct {
-	Name             *string  `json:"name"`
-	Target           *string  `json:"target"`
-	Interval         *int     `json:"interval_seconds"`
-	Timeout          *int     `json:"timeout_seconds"`
-	FailureThreshold *int     `json:"failure_threshold"`
-	Enabled          *bool    `json:"enabled"`
-	SLATargetPercent *float64 `json:"sla_target_percent"`
-	AgentID          *string  `json:"agent_id"`
+	Name              *string           `json:"name"`
+	Target            *string           `json:"target"`
+	Interval          *int              `json:"interval_seconds"`
+	Timeout           *int              `json:"timeout_seconds"`
+	FailureThreshold  *int              `json:"failure_threshold"`
+	Enabled           *bool             `json:"enabled"`
+	SLATargetPercent  *float64          `json:"sla_target_percent"`
+	AgentID           *string           `json:"agent_id"`
+	Degraded          *degradedRulesDTO `json:"degraded"`
+	RecoveryThreshold *int              `json:"recovery_threshold"`
+	FlapDetection     *flapDetectionDTO `json:"flap_detection"`
+	Locations         *locationsDTO     `json:"locations"`
+	Push              *pushDTO          `json:"push"`
+	Assertions        *assertionsDTO    `json:"assertions"`
+	Transaction       *transactionDTO   `json:"transaction"`
+	Severity          *string           `json:"severity"`
+	EscalationPolicyID *string          `json:"escalation_policy_id"`
 }
 
 // UpdateMonitor updates an existing monitor.
@@ -519,7 +942,14 @@ func (h *APIV1Handler) UpdateMonitor(c echo.Context) error {
 	if req.Target != nil {
 		monitor.Target = *req.Target
 	}
-	if req.Interval != nil {
+	if monitor.IsPush() {
+		if req.Locations != nil {
+			return errJSON(c, http.StatusBadRequest, "push monitors cannot have locations")
+		}
+		if msg := applyPushSchedule(monitor, req.Interval, req.Push); msg != "" {
+			return errJSON(c, http.StatusBadRequest, msg)
+		}
+	} else if req.Interval != nil {
 		monitor.SetInterval(*req.Interval)
 	}
 	if req.Timeout != nil {
@@ -546,6 +976,40 @@ func (h *APIV1Handler) UpdateMonitor(c echo.Context) error {
 		}
 		monitor.SLATargetPercent = req.SLATargetPercent
 	}
+	if msg := applyDegradedRules(monitor, req.Degraded); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	if msg := applyRecoveryRules(monitor, req.RecoveryThreshold, req.FlapDetection); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	if req.Assertions != nil {
+		if msg := validateAssertions(monitor.Type, *req.Assertions); msg != "" {
+			return errJSON(c, http.StatusBadRequest, msg)
+		}
+		monitor.Assertions = *req.Assertions
+	}
+	if req.Transaction != nil {
+		if msg := validateTransaction(monitor.Type, req.Transaction); msg != "" {
+			return errJSON(c, http.StatusBadRequest, msg)
+		}
+		monitor.SetTransaction(req.Transaction.Steps)
+	}
+	if req.Severity != nil {
+		severity := domain.IncidentSeverity(*req.Severity)
+		if !severity.IsValid() {
+			return errJSON(c, http.StatusBadRequest, domain.ErrInvalidIncidentSeverity.Error())
+		}
+		monitor.Severity = severity
+	}
+	if req.EscalationPolicyID != nil {
+		// An empty ID detaches the policy.
+		policyID, msg := h.resolveEscalationPolicy(ctx, *req.EscalationPolicyID, userID)
+		if msg != "" {
+			return errJSON(c, http.StatusBadRequest, msg)
+		}
+		monitor.EscalationPolicyID = policyID
+	}
+	oldProbes := append([]uuid.UUID(nil), monitor.ProbeAgentIDs()...)
 	oldAgentID := monitor.AgentID
 	if req.AgentID != nil {
 		newAgentID, err := uuid.Parse(*req.AgentID)
@@ -558,9 +1022,21 @@ func (h *APIV1Handler) UpdateMonitor(c echo.Context) error {
 			if err != nil || newAgent == nil || newAgent.UserID != userID {
 				return errJSON(c, http.StatusBadRequest, "agent not found or not owned by you")
 			}
+			if newAgent.Hub && !monitor.Type.IsHubProbeable() && !monitor.IsPush() {
+				return errJSON(c, http.StatusBadRequest, errHubMonitorType)
+			}
 			monitor.AgentID = newAgentID
+			if monitor.IsMultiLocation() && req.Locations == nil {
+				// Keep the probe set, now anchored on the new owning agent.
+				if !monitor.SetLocations(monitor.LocationAgentIDs, monitor.Quorum) {
+					return errJSON(c, http.StatusBadRequest, fmt.Sprintf("locations allow at most %d agents", domain.MaxMonitorLocations))
+				}
+			}
 		}
 	}
+	if msg := h.applyLocations(ctx, monitor, req.Locations, userID); msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
 
 	if err := h.monitorSvc.UpdateMonitor(ctx, monitor); err != nil {
 		return errJSON(c, http.StatusInternalServerError, "failed to update monitor")
@@ -573,22 +1049,21 @@ func (h *APIV1Handler) UpdateMonitor(c echo.Context) error {
 		})
 	}
 
-	// If agent changed, cancel the monitor on the old agent
-	if oldAgentID != monitor.AgentID {
-		h.hub.SendToAgent(oldAgentID, protocol.NewTaskCancelMessage(monitor.ID.String()))
+	// Cancel the monitor on agents that no longer probe it (reassignment or
+	// a shrunk location set)
+	probes := make(map[uuid.UUID]bool)
+	for _, agentID := range monitor.ProbeAgentIDs() {
+		probes[agentID] = true
 	}
-
-	// Notify the (possibly new) agent of the task
-	if monitor.Enabled {
-		taskMsg := protocol.NewTaskMessageWithMetadata(
-			monitor.ID.String(), string(monitor.Type),
-			monitor.Target, monitor.IntervalSeconds, monitor.TimeoutSeconds, monitor.Metadata,
-		)
-		h.hub.SendToAgent(monitor.AgentID, taskMsg)
-	} else {
-		h.hub.SendToAgent(monitor.AgentID, protocol.NewTaskCancelMessage(monitor.ID.String()))
+	for _, agentID := range oldProbes {
+		if !probes[agentID] {
+			h.hub.SendToAgent(agentID, protocol.NewTaskCancelMessage(monitor.ID.String()))
+		}
 	}
 
+	// Notify the (possibly new) agents of the task
+	h.dispatchTask(monitor)
+
 	// Resolve agent name — use current (possibly reassigned) agent.
 	agentName := agent.Name
 	if monitor.AgentID != agent.ID {
@@ -598,20 +1073,7 @@ func (h *APIV1Handler) UpdateMonitor(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{
-		"data": monitorResponse{
-			ID:               monitor.ID.String(),
-			AgentID:          monitor.AgentID.String(),
-			AgentName:        agentName,
-			Name:             monitor.Name,
-			Type:             string(monitor.Type),
-			Target:           monitor.Target,
-			Status:           string(monitor.Status),
-			Enabled:          monitor.Enabled,
-			Interval:         monitor.IntervalSeconds,
-			Timeout:          monitor.TimeoutSeconds,
-			FailureThreshold: monitor.FailureThreshold,
-			SLATargetPercent: monitor.SLATargetPercent,
-		},
+		"data": toMonitorResponse(monitor, agentName),
 	})
 }
 
@@ -651,8 +1113,10 @@ func (h *APIV1Handler) DeleteMonitor(c echo.Context) error {
 		})
 	}
 
-	// Notify agent to stop the task
-	h.hub.SendToAgent(monitor.AgentID, protocol.NewTaskCancelMessage(monitorID.String()))
+	// Notify agents to stop the task
+	for _, agentID := range monitor.ProbeAgentIDs() {
+		h.hub.SendToAgent(agentID, protocol.NewTaskCancelMessage(monitorID.String()))
+	}
 
 	return c.NoContent(http.StatusNoContent)
 }
@@ -660,6 +1124,7 @@ func (h *APIV1Handler) DeleteMonitor(c echo.Context) error {
 type createAgentRequest struct {
 	Name          string `json:"name"`
 	ExpiresInDays *int   `json:"expires_in_days,omitempty"` // H-023: override default key expiry
+	Hub           bool   `json:"hub,omitempty"`             // virtual agent probed by the hub itself
 }
 
 // CreateAgent creates a new agent and returns its API key.
@@ -688,8 +1153,18 @@ func (h *APIV1Handler) CreateAgent(c echo.Context) error {
 		return errJSON(c, http.StatusBadRequest, "failed to create agent")
 	}
 
-	// H-023: allow client to override the default key expiry.
-	if req.ExpiresInDays != nil {
+	// Hub agents never connect, so their API key is neither returned nor
+	// accepted and does not expire.
+	if req.Hub {
+		agent.Hub = true
+		agent.APIKeyExpiresAt = nil
+		agent.MarkOnline()
+		if err := h.agentRepo.Update(ctx, agent); err != nil {
+			return errJSON(c, http.StatusInternalServerError, "failed to create hub agent")
+		}
+		apiKey = ""
+	} else if req.ExpiresInDays != nil {
+		// H-023: allow client to override the default key expiry.
 		days := *req.ExpiresInDays
 		if days <= 0 {
 			// Zero or negative means "never expires".
@@ -711,13 +1186,15 @@ func (h *APIV1Handler) CreateAgent(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusCreated, map[string]any{
-		"data": map[string]string{
-			"id":      agent.ID.String(),
-			"name":    agent.Name,
-			"api_key": apiKey,
-		},
-	})
+	data := map[string]any{
+		"id":   agent.ID.String(),
+		"name": agent.Name,
+		"hub":  agent.Hub,
+	}
+	if apiKey != "" {
+		data["api_key"] = apiKey
+	}
+	return c.JSON(http.StatusCreated, map[string]any{"data": data})
 }
 
 // DeleteAgent deletes an agent.
@@ -778,6 +1255,7 @@ func (h *APIV1Handler) AcknowledgeIncident(c echo.Context) error {
 	if err := h.incidentSvc.AcknowledgeIncident(ctx, incidentID, userID); err != nil {
 		return errJSON(c, http.StatusInternalServerError, "failed to acknowledge incident")
 	}
+	h.recordStatusChange(ctx, incidentID, userID, domain.IncidentStatusAcknowledged)
 
 	if h.auditSvc != nil {
 		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentAcked, c.RealIP(), map[string]string{
@@ -813,6 +1291,7 @@ func (h *APIV1Handler) ResolveIncident(c echo.Context) error {
 	if err := h.incidentSvc.ResolveIncident(ctx, incidentID); err != nil {
 		return errJSON(c, http.StatusInternalServerError, "failed to resolve incident")
 	}
+	h.recordStatusChange(ctx, incidentID, userID, domain.IncidentStatusResolved)
 
 	if h.auditSvc != nil {
 		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentResolved, c.RealIP(), map[string]string{
@@ -823,6 +1302,57 @@ func (h *APIV1Handler) ResolveIncident(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "resolved"})
 }
 
+type updateIncidentRequest struct {
+	Severity *string `json:"severity"`
+}
+
+// UpdateIncident overrides an incident's severity.
+// PATCH /api/v1/incidents/:id
+func (h *APIV1Handler) UpdateIncident(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	incidentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid incident ID")
+	}
+
+	var req updateIncidentRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Severity == nil {
+		return errJSON(c, http.StatusBadRequest, "severity is required")
+	}
+	severity := domain.IncidentSeverity(*req.Severity)
+	if !severity.IsValid() {
+		return errJSON(c, http.StatusBadRequest, domain.ErrInvalidIncidentSeverity.Error())
+	}
+
+	incident, err := verifyIncidentOwnership(ctx, h.incidentSvc, h.monitorRepo, h.agentRepo, incidentID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to update incident")
+	}
+	if incident == nil {
+		return errJSON(c, http.StatusNotFound, "incident not found")
+	}
+
+	if err := h.incidentSvc.SetIncidentSeverity(ctx, incidentID, severity); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to update incident")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentUpdated, c.RealIP(), map[string]string{
+			"incident_id": incidentID.String(), "severity": string(severity),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "updated", "severity": string(severity)})
+}
+
 // DashboardStats returns summary statistics.
 // GET /api/v1/dashboard/stats
 func (h *APIV1Handler) DashboardStats(c echo.Context) error {
@@ -842,6 +1372,7 @@ func (h *APIV1Handler) DashboardStats(c echo.Context) error {
 	totalMonitors := 0
 	monitorsUp := 0
 	monitorsDown := 0
+	monitorsDegraded := 0
 	userMonitorIDs := make(map[uuid.UUID]struct{})
 
 	for _, a := range agents {
@@ -860,6 +1391,8 @@ func (h *APIV1Handler) DashboardStats(c echo.Context) error {
 				monitorsUp++
 			case domain.MonitorStatusDown:
 				monitorsDown++
+			case domain.MonitorStatusDegraded:
+				monitorsDegraded++
 			}
 		}
 	}
@@ -868,18 +1401,19 @@ func (h *APIV1Handler) DashboardStats(c echo.Context) error {
 	allIncidents, _ := h.incidentSvc.GetActiveIncidents(ctx)
 	activeIncidents := 0
 	for _, inc := range allIncidents {
-		if _, ok := userMonitorIDs[inc.MonitorID]; ok {
+		if _, ok := userMonitorIDs[inc.MonitorID]; ok || inc.DeclaredBy(userID) {
 			activeIncidents++
 		}
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{
-		"total_monitors":   totalMonitors,
-		"monitors_up":      monitorsUp,
-		"monitors_down":    monitorsDown,
-		"active_incidents": activeIncidents,
-		"total_agents":     totalAgents,
-		"online_agents":    onlineAgents,
+		"total_monitors":    totalMonitors,
+		"monitors_up":       monitorsUp,
+		"monitors_down":     monitorsDown,
+		"monitors_degraded": monitorsDegraded,
+		"active_incidents":  activeIncidents,
+		"total_agents":      totalAgents,
+		"online_agents":     onlineAgents,
 	})
 }
 
@@ -944,6 +1478,55 @@ func (h *APIV1Handler) GetMonitorCertificate(c echo.Context) error {
 	})
 }
 
+type transactionVersionResponse struct {
+	Version   int                      `json:"version"`
+	Steps     []domain.TransactionStep `json:"steps"`
+	CreatedAt string                   `json:"created_at"`
+}
+
+// ListTransactionVersions returns every definition a transaction monitor has
+// had, newest first.
+// GET /api/v1/monitors/:id/transaction/versions
+func (h *APIV1Handler) ListTransactionVersions(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	monitorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid monitor ID")
+	}
+
+	monitor, err := verifyMonitorOwnership(ctx, h.monitorRepo, h.agentRepo, monitorID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch monitor")
+	}
+	if monitor == nil {
+		return errJSON(c, http.StatusNotFound, "monitor not found")
+	}
+	if !monitor.IsTransaction() {
+		return errJSON(c, http.StatusBadRequest, "monitor is not a transaction monitor")
+	}
+
+	versions, err := h.monitorRepo.GetTransactionVersions(ctx, monitorID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch transaction versions")
+	}
+
+	result := make([]transactionVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, transactionVersionResponse{
+			Version:   v.Version,
+			Steps:     v.Steps,
+			CreatedAt: v.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
 // GetExpiringCertificates lists monitors with certs expiring within N days.
 // GET /api/v1/certificates/expiring?days=30
 func (h *APIV1Handler) GetExpiringCertificates(c echo.Context) error {
@@ -1058,6 +1641,52 @@ func (h *APIV1Handler) SetInvestigationService(svc ports.InvestigationService) {
 	h.investigationSvc = svc
 }
 
+// SetIncidentActivityService enables status change entries in the incident
+// activity log and the postmortem in investigations.
+func (h *APIV1Handler) SetIncidentActivityService(svc ports.IncidentActivityService) {
+	h.activitySvc = svc
+}
+
+// SetEscalationService enables referencing escalation policies from monitors.
+func (h *APIV1Handler) SetEscalationService(svc ports.EscalationService) {
+	h.escalationSvc = svc
+}
+
+// resolveEscalationPolicy parses an escalation policy ID and checks that the
+// policy belongs to the user, returning a message when it does not. An empty
+// ID resolves to no policy.
+func (h *APIV1Handler) resolveEscalationPolicy(ctx context.Context, raw string, userID uuid.UUID) (*uuid.UUID, string) {
+	if raw == "" {
+		return nil, ""
+	}
+	if h.escalationSvc == nil {
+		return nil, "escalation policies are not available"
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, "invalid escalation_policy_id"
+	}
+	policy, err := h.escalationSvc.GetPolicy(ctx, id)
+	if err != nil || policy == nil || policy.UserID != userID {
+		return nil, "escalation policy not found"
+	}
+	return &id, ""
+}
+
+// recordStatusChange logs a user's status change in the incident activity
+// log. The change itself already succeeded, so failures are only logged.
+func (h *APIV1Handler) recordStatusChange(ctx context.Context, incidentID, userID uuid.UUID, status domain.IncidentStatus) {
+	if h.activitySvc == nil {
+		return
+	}
+	if err := h.activitySvc.RecordStatusChange(ctx, incidentID, userID, status); err != nil {
+		slog.Error("failed to record incident status change",
+			slog.String("incident_id", incidentID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
 // SetUpdateService sets the update service for agent auto-update.
 func (h *APIV1Handler) SetUpdateService(svc *services.UpdateService) {
 	h.updateSvc = svc
@@ -1085,6 +1714,9 @@ func (h *APIV1Handler) GetIncidentInvestigation(c echo.Context) error {
 	if incident == nil {
 		return errJSON(c, http.StatusNotFound, "incident not found")
 	}
+	if incident.IsDeclared() {
+		return errJSON(c, http.StatusNotFound, "declared incidents have no monitor to investigate")
+	}
 
 	if h.investigationSvc == nil {
 		return errJSON(c, http.StatusNotImplemented, "investigation service not available")
@@ -1107,6 +1739,7 @@ func (h *APIV1Handler) GetIncidentInvestigation(c echo.Context) error {
 			Status:     string(inc.Status),
 			StartedAt:  inc.StartedAt.Format(time.RFC3339),
 			TTRSeconds: inc.TTRSeconds,
+			Kind:       string(inc.Kind),
 		}
 		if inc.ResolvedAt != nil {
 			t := inc.ResolvedAt.Format(time.RFC3339)
@@ -1137,14 +1770,18 @@ func (h *APIV1Handler) GetIncidentInvestigation(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, map[string]any{
 		"data": map[string]any{
-			"agent_summary":      investigation.AgentSummary,
-			"recurrence_pattern": investigation.RecurrencePattern,
-			"mttr_seconds":       investigation.MTTRSeconds,
-			"sibling_monitors":   investigation.SiblingMonitors,
-			"previous_incidents": prevIncidents,
-			"system_metrics":     investigation.SystemMetrics,
-			"cert_details":       certDetails,
-			"timeline":           investigation.Timeline,
+			"agent_summary":         investigation.AgentSummary,
+			"recurrence_pattern":    investigation.RecurrencePattern,
+			"mttr_seconds":          investigation.MTTRSeconds,
+			"sibling_monitors":      investigation.SiblingMonitors,
+			"previous_incidents":    prevIncidents,
+			"system_metrics":        investigation.SystemMetrics,
+			"cert_details":          certDetails,
+			"timeline":              investigation.Timeline,
+			"locations":             investigation.Locations,
+			"dependency_tree":       investigation.DependencyTree,
+			"root_cause_monitor_id": investigation.RootCauseMonitorID,
+			"postmortem":            toPostmortemResponse(investigation.Postmortem),
 		},
 	})
 }
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sylvester-francis/watchdog-proto/protocol"
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+)
+
+// maxConfigBytes caps the size of a config spec accepted by plan and apply.
+const maxConfigBytes = 1 << 20
+
+// SetConfigService sets the config-as-code service (wired after construction).
+func (h *APIV1Handler) SetConfigService(svc *services.ConfigService) {
+	h.configSvc = svc
+}
+
+// ExportConfig returns the user's setup as a declarative spec.
+// GET /api/v1/config/export
+//
+// Optional query: format=yaml returns the spec as a YAML document instead
+// of JSON.
+func (h *APIV1Handler) ExportConfig(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	if h.configSvc == nil {
+		return errJSON(c, http.StatusNotImplemented, "config service not available")
+	}
+
+	spec, err := h.configSvc.Export(c.Request().Context(), userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to export config")
+	}
+
+	if c.QueryParam("format") == "yaml" {
+		doc, err := encodeConfigYAML(spec)
+		if err != nil {
+			return errJSON(c, http.StatusInternalServerError, "failed to export config")
+		}
+		return c.Blob(http.StatusOK, "application/yaml", doc)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": spec})
+}
+
+// PlanConfig returns the changes applying a spec would make.
+// POST /api/v1/config/plan
+//
+// The body is the spec as JSON, or as YAML with a YAML Content-Type.
+func (h *APIV1Handler) PlanConfig(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	if h.configSvc == nil {
+		return errJSON(c, http.StatusNotImplemented, "config service not available")
+	}
+
+	spec, err := readConfigSpec(c)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	plan, err := h.configSvc.Plan(c.Request().Context(), userID, spec)
+	if err != nil {
+		return configError(c, err, "failed to plan config")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": plan})
+}
+
+// ApplyConfig brings the user's setup in line with a spec in one transaction
+// and returns the changes made. API keys of created agents are returned once.
+// POST /api/v1/config/apply
+func (h *APIV1Handler) ApplyConfig(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	if h.configSvc == nil {
+		return errJSON(c, http.StatusNotImplemented, "config service not available")
+	}
+
+	spec, err := readConfigSpec(c)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.configSvc.Apply(ctx, userID, spec)
+	if err != nil {
+		return configError(c, err, "failed to apply config")
+	}
+
+	for monitorID, agentIDs := range result.Cancel {
+		for _, agentID := range agentIDs {
+			h.hub.SendToAgent(agentID, protocol.NewTaskCancelMessage(monitorID.String()))
+		}
+	}
+	for _, monitor := range result.Dispatch {
+		h.dispatchTask(monitor)
+	}
+
+	if h.auditSvc != nil && len(result.Changes) > 0 {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditConfigApplied, c.RealIP(), map[string]string{
+			"changes": strconv.Itoa(len(result.Changes)),
+		})
+	}
+
+	data := map[string]any{"changes": result.Changes}
+	if len(result.AgentKeys) > 0 {
+		data["agent_keys"] = result.AgentKeys
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": data})
+}
+
+// configError maps a plan or apply failure to a response.
+func configError(c echo.Context, err error, fallback string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidConfig):
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrMonitorLimitReached):
+		return errJSONCode(c, http.StatusForbidden, CodeLimitReached, domain.ErrMonitorLimitReached.Error())
+	case errors.Is(err, domain.ErrAgentLimitReached):
+		return errJSONCode(c, http.StatusForbidden, CodeLimitReached, domain.ErrAgentLimitReached.Error())
+	default:
+		return errJSON(c, http.StatusInternalServerError, fallback)
+	}
+}
+
+// readConfigSpec decodes the request body as a spec. YAML is converted to
+// JSON first so both formats share the spec's JSON field names. Unknown
+// fields are rejected to catch typos.
+func readConfigSpec(c echo.Context) (*domain.ConfigSpec, error) {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxConfigBytes+1))
+	if err != nil {
+		return nil, errors.New("invalid request body")
+	}
+	if len(body) > maxConfigBytes {
+		return nil, fmt.Errorf("config exceeds %d bytes", maxConfigBytes)
+	}
+
+	if strings.Contains(c.Request().Header.Get(echo.HeaderContentType), "yaml") {
+		var doc any
+		if err := yaml.Unmarshal(body, &doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %v", err)
+		}
+		if body, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %v", err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	var spec domain.ConfigSpec
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+	return &spec, nil
+}
+
+// encodeConfigYAML renders a spec as YAML. The JSON encoding is parsed as
+// YAML (JSON is a subset) so fields keep their declared order, then printed
+// in block style.
+func encodeConfigYAML(spec *domain.ConfigSpec) ([]byte, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	clearYAMLStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func clearYAMLStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		clearYAMLStyle(child)
+	}
+}
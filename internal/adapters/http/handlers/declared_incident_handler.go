@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+	"github.com/sylvester-francis/watchdog/internal/adapters/repository"
+)
+
+// DeclaredIncidentHandler serves endpoints for incidents declared by an
+// operator rather than opened by a monitor. Acknowledging, resolving and
+// notes go through the regular incident endpoints.
+type DeclaredIncidentHandler struct {
+	incidentSvc ports.IncidentService
+	monitorRepo ports.MonitorRepository
+	agentRepo   ports.AgentRepository
+	userRepo    ports.UserRepository
+	auditSvc    ports.AuditService
+}
+
+// NewDeclaredIncidentHandler creates a new DeclaredIncidentHandler.
+func NewDeclaredIncidentHandler(incidentSvc ports.IncidentService, monitorRepo ports.MonitorRepository, agentRepo ports.AgentRepository, userRepo ports.UserRepository, auditSvc ports.AuditService) *DeclaredIncidentHandler {
+	return &DeclaredIncidentHandler{incidentSvc: incidentSvc, monitorRepo: monitorRepo, agentRepo: agentRepo, userRepo: userRepo, auditSvc: auditSvc}
+}
+
+type declareIncidentRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Severity    string   `json:"severity"`
+	OwnerID     *string  `json:"owner_id"`
+	MonitorIDs  []string `json:"monitor_ids"`
+}
+
+type incidentOwnerRequest struct {
+	OwnerID *string `json:"owner_id"`
+}
+
+// Declare opens an incident not tied to a monitor, such as a vendor outage
+// or a security event.
+// POST /api/v1/incidents
+func (h *DeclaredIncidentHandler) Declare(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req declareIncidentRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	monitorIDs := make([]uuid.UUID, 0, len(req.MonitorIDs))
+	for _, raw := range req.MonitorIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "invalid monitor ID: "+raw)
+		}
+		monitorIDs = append(monitorIDs, id)
+	}
+
+	incident, err := domain.NewDeclaredIncident(userID, req.Title, req.Description, domain.IncidentSeverity(req.Severity), monitorIDs)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Affected monitors are checked after validation, which caps how many
+	// there are.
+	for _, id := range incident.AffectedMonitorIDs {
+		monitor, err := verifyMonitorOwnership(ctx, h.monitorRepo, h.agentRepo, id, userID)
+		if err != nil {
+			return errJSON(c, http.StatusInternalServerError, "failed to verify monitor")
+		}
+		if monitor == nil {
+			return errJSON(c, http.StatusNotFound, "monitor not found: "+id.String())
+		}
+	}
+
+	if req.OwnerID != nil {
+		ownerID, err := h.owner(ctx, *req.OwnerID)
+		if err != nil {
+			return ownerError(c, err)
+		}
+		incident.OwnerID = ownerID
+	}
+
+	if err := h.incidentSvc.DeclareIncident(ctx, incident); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to declare incident")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentDeclared, c.RealIP(), map[string]string{
+			"incident_id": incident.ID.String(), "title": incident.Title, "severity": string(incident.Severity),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": toIncidentResponse(incident, "")})
+}
+
+// SetOwner hands a declared incident to another user, or clears its owner
+// when owner_id is null.
+// PUT /api/v1/incidents/:id/owner
+func (h *DeclaredIncidentHandler) SetOwner(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	incidentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid incident ID")
+	}
+
+	var req incidentOwnerRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	incident, err := verifyIncidentOwnership(ctx, h.incidentSvc, h.monitorRepo, h.agentRepo, incidentID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to update incident owner")
+	}
+	if incident == nil {
+		return errJSON(c, http.StatusNotFound, "incident not found")
+	}
+	if !incident.IsDeclared() {
+		return errJSON(c, http.StatusBadRequest, "only declared incidents have an owner")
+	}
+
+	var ownerID *uuid.UUID
+	if req.OwnerID != nil {
+		if ownerID, err = h.owner(ctx, *req.OwnerID); err != nil {
+			return ownerError(c, err)
+		}
+	}
+
+	if err := h.incidentSvc.SetIncidentOwner(ctx, incidentID, ownerID); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to update incident owner")
+	}
+	incident.OwnerID = ownerID
+
+	if h.auditSvc != nil {
+		owner := ""
+		if ownerID != nil {
+			owner = ownerID.String()
+		}
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentOwnerChanged, c.RealIP(), map[string]string{
+			"incident_id": incidentID.String(), "owner_id": owner,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toIncidentResponse(incident, "")})
+}
+
+var errOwnerNotFound = errors.New("owner not found")
+
+// owner parses an owner ID and checks the user exists in the caller's
+// tenant: the owner is notified with the incident's title and description.
+func (h *DeclaredIncidentHandler) owner(ctx context.Context, raw string) (*uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, errOwnerNotFound
+	}
+	user, err := h.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.TenantID != repository.TenantIDFromContext(ctx) {
+		return nil, errOwnerNotFound
+	}
+	return &id, nil
+}
+
+// ownerError maps owner lookup errors to responses.
+func ownerError(c echo.Context, err error) error {
+	if errors.Is(err, errOwnerNotFound) {
+		return errJSON(c, http.StatusBadRequest, "owner_id must be an existing user")
+	}
+	return errJSON(c, http.StatusInternalServerError, "failed to look up owner")
+}
@@ -0,0 +1,83 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/handlers"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+	"github.com/sylvester-francis/watchdog/internal/adapters/repository"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+// ownerRequest builds an authenticated request in the acme tenant.
+func ownerRequest(method, body string, userID uuid.UUID) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/api/v1/incidents", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(repository.WithTenantID(req.Context(), "acme"))
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.UserIDKey, userID.String())
+	return c, rec
+}
+
+func TestDeclaredIncidentHandler_ForeignTenantOwner(t *testing.T) {
+	userID, colleague, outsider := uuid.New(), uuid.New(), uuid.New()
+	userRepo := &mocks.MockUserRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.User, error) {
+			tenantID := "acme"
+			if id == outsider {
+				tenantID = "globex"
+			}
+			return &domain.User{ID: id, TenantID: tenantID}, nil
+		},
+	}
+	declared, _ := domain.NewDeclaredIncident(userID, "Payments vendor outage", "", "", nil)
+	var owners []*uuid.UUID
+	incidentSvc := &mocks.MockIncidentService{
+		DeclareIncidentFn: func(_ context.Context, incident *domain.Incident) error {
+			owners = append(owners, incident.OwnerID)
+			return nil
+		},
+		GetIncidentFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return declared, nil
+		},
+		SetIncidentOwnerFn: func(_ context.Context, _ uuid.UUID, ownerID *uuid.UUID) error {
+			owners = append(owners, ownerID)
+			return nil
+		},
+	}
+	h := handlers.NewDeclaredIncidentHandler(incidentSvc, &mocks.MockMonitorRepository{}, &mocks.MockAgentRepository{}, userRepo, nil)
+
+	declare := func(ownerID uuid.UUID) int {
+		c, rec := ownerRequest(http.MethodPost, `{"title":"Payments vendor outage","owner_id":"`+ownerID.String()+`"}`, userID)
+		require.NoError(t, h.Declare(c))
+		return rec.Code
+	}
+	setOwner := func(ownerID uuid.UUID) int {
+		c, rec := ownerRequest(http.MethodPut, `{"owner_id":"`+ownerID.String()+`"}`, userID)
+		c.SetParamNames("id")
+		c.SetParamValues(declared.ID.String())
+		require.NoError(t, h.SetOwner(c))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, declare(outsider), "an owner from another tenant is rejected")
+	assert.Equal(t, http.StatusBadRequest, setOwner(outsider), "an owner from another tenant is rejected")
+	assert.Empty(t, owners, "nothing is saved for a foreign owner")
+
+	assert.Equal(t, http.StatusCreated, declare(colleague))
+	assert.Equal(t, http.StatusOK, setOwner(colleague))
+	require.Len(t, owners, 2)
+	assert.Equal(t, colleague, *owners[0])
+	assert.Equal(t, colleague, *owners[1])
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+)
+
+// DependencyHandler serves endpoints for monitor dependency edges.
+type DependencyHandler struct {
+	depRepo     ports.DependencyRepository
+	monitorRepo ports.MonitorRepository
+	agentRepo   ports.AgentRepository
+	auditSvc    ports.AuditService
+}
+
+// NewDependencyHandler creates a new DependencyHandler.
+func NewDependencyHandler(depRepo ports.DependencyRepository, monitorRepo ports.MonitorRepository, agentRepo ports.AgentRepository, auditSvc ports.AuditService) *DependencyHandler {
+	return &DependencyHandler{depRepo: depRepo, monitorRepo: monitorRepo, agentRepo: agentRepo, auditSvc: auditSvc}
+}
+
+type dependencyResponse struct {
+	MonitorID string `json:"monitor_id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+}
+
+type createDependencyRequest struct {
+	ParentID string `json:"parent_id"`
+}
+
+// List returns the monitors a monitor depends on and the monitors that depend on it.
+// GET /api/v1/monitors/:id/dependencies
+func (h *DependencyHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	monitorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid monitor ID")
+	}
+
+	monitor, err := verifyMonitorOwnership(ctx, h.monitorRepo, h.agentRepo, monitorID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch monitor")
+	}
+	if monitor == nil {
+		return errJSON(c, http.StatusNotFound, "monitor not found")
+	}
+
+	edges, err := h.depRepo.GetByTenant(ctx)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch dependencies")
+	}
+
+	parents := make([]dependencyResponse, 0)
+	children := make([]dependencyResponse, 0)
+	for _, e := range edges {
+		switch monitorID {
+		case e.MonitorID:
+			if resp, ok := h.toResponse(c, e.ParentID, e.CreatedAt, userID); ok {
+				parents = append(parents, resp)
+			}
+		case e.ParentID:
+			if resp, ok := h.toResponse(c, e.MonitorID, e.CreatedAt, userID); ok {
+				children = append(children, resp)
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"parents":  parents,
+			"children": children,
+		},
+	})
+}
+
+// Create adds a dependency: the monitor in the path depends on parent_id.
+// POST /api/v1/monitors/:id/dependencies
+func (h *DependencyHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	monitorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid monitor ID")
+	}
+
+	var req createDependencyRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	parentID, err := uuid.Parse(req.ParentID)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid parent_id")
+	}
+
+	monitor, err := verifyMonitorOwnership(ctx, h.monitorRepo, h.agentRepo, monitorID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch monitor")
+	}
+	if monitor == nil {
+		return errJSON(c, http.StatusNotFound, "monitor not found")
+	}
+
+	parent, err := verifyMonitorOwnership(ctx, h.monitorRepo, h.agentRepo, parentID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch monitor")
+	}
+	if parent == nil {
+		return errJSON(c, http.StatusNotFound, "parent monitor not found")
+	}
+
+	dep, err := domain.NewMonitorDependency(monitorID, parentID)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	edges, err := h.depRepo.GetByTenant(ctx)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch dependencies")
+	}
+	if domain.WouldCreateCycle(edges, monitorID, parentID) {
+		return errJSON(c, http.StatusConflict, domain.ErrDependencyCycle.Error())
+	}
+
+	if err := h.depRepo.Create(ctx, dep); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to create dependency")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditDependencyCreated, c.RealIP(), map[string]string{
+			"monitor_id": monitorID.String(),
+			"parent_id":  parentID.String(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": dependencyResponse{
+		MonitorID: parent.ID.String(),
+		Name:      parent.Name,
+		Status:    string(parent.Status),
+		CreatedAt: dep.CreatedAt.Format(time.RFC3339),
+	}})
+}
+
+// Delete removes a dependency edge.
+// DELETE /api/v1/monitors/:id/dependencies/:parentId
+func (h *DependencyHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	monitorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid monitor ID")
+	}
+	parentID, err := uuid.Parse(c.Param("parentId"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid parent ID")
+	}
+
+	monitor, err := verifyMonitorOwnership(ctx, h.monitorRepo, h.agentRepo, monitorID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch monitor")
+	}
+	if monitor == nil {
+		return errJSON(c, http.StatusNotFound, "monitor not found")
+	}
+
+	if err := h.depRepo.Delete(ctx, monitorID, parentID); err != nil {
+		return errJSON(c, http.StatusNotFound, "dependency not found")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditDependencyDeleted, c.RealIP(), map[string]string{
+			"monitor_id": monitorID.String(),
+			"parent_id":  parentID.String(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// toResponse describes the monitor at the other end of an edge, skipping
+// monitors the user does not own.
+func (h *DependencyHandler) toResponse(c echo.Context, monitorID uuid.UUID, createdAt time.Time, userID uuid.UUID) (dependencyResponse, bool) {
+	m, err := verifyMonitorOwnership(c.Request().Context(), h.monitorRepo, h.agentRepo, monitorID, userID)
+	if err != nil || m == nil {
+		return dependencyResponse{}, false
+	}
+	return dependencyResponse{
+		MonitorID: m.ID.String(),
+		Name:      m.Name,
+		Status:    string(m.Status),
+		CreatedAt: createdAt.Format(time.RFC3339),
+	}, true
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+)
+
+// EscalationHandler serves CRUD endpoints for escalation policies and the
+// escalation state of incidents.
+type EscalationHandler struct {
+	escalationSvc ports.EscalationService
+	incidentSvc   ports.IncidentService
+	monitorRepo   ports.MonitorRepository
+	agentRepo     ports.AgentRepository
+	auditSvc      ports.AuditService
+}
+
+// NewEscalationHandler creates a new EscalationHandler.
+func NewEscalationHandler(escalationSvc ports.EscalationService, incidentSvc ports.IncidentService, monitorRepo ports.MonitorRepository, agentRepo ports.AgentRepository, auditSvc ports.AuditService) *EscalationHandler {
+	return &EscalationHandler{escalationSvc: escalationSvc, incidentSvc: incidentSvc, monitorRepo: monitorRepo, agentRepo: agentRepo, auditSvc: auditSvc}
+}
+
+type escalationLevelDTO struct {
+	DelayMinutes int      `json:"delay_minutes"`
+	ChannelIDs   []string `json:"channel_ids"`
+	UserIDs      []string `json:"user_ids"`
+	ScheduleIDs  []string `json:"schedule_ids"`
+}
+
+type escalationPolicyResponse struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	Levels    []escalationLevelDTO `json:"levels"`
+	Repeat    int                  `json:"repeat"`
+	CreatedAt string               `json:"created_at"`
+	UpdatedAt string               `json:"updated_at"`
+}
+
+type escalationPolicyRequest struct {
+	Name   string               `json:"name"`
+	Levels []escalationLevelDTO `json:"levels"`
+	Repeat int                  `json:"repeat"`
+}
+
+type escalationResponse struct {
+	IncidentID  string  `json:"incident_id"`
+	PolicyID    string  `json:"policy_id"`
+	WorkflowID  string  `json:"workflow_id,omitempty"`
+	LevelsPaged int     `json:"levels_paged"`
+	TotalLevels int     `json:"total_levels"`
+	NextPageAt  *string `json:"next_page_at"`
+	StartedAt   string  `json:"started_at"`
+}
+
+func toEscalationLevelDTOs(levels []domain.EscalationLevel) []escalationLevelDTO {
+	out := make([]escalationLevelDTO, len(levels))
+	for i, l := range levels {
+		dto := escalationLevelDTO{
+			DelayMinutes: l.DelayMinutes,
+			ChannelIDs:   make([]string, len(l.ChannelIDs)),
+			UserIDs:      make([]string, len(l.UserIDs)),
+			ScheduleIDs:  make([]string, len(l.ScheduleIDs)),
+		}
+		for j, id := range l.ChannelIDs {
+			dto.ChannelIDs[j] = id.String()
+		}
+		for j, id := range l.UserIDs {
+			dto.UserIDs[j] = id.String()
+		}
+		for j, id := range l.ScheduleIDs {
+			dto.ScheduleIDs[j] = id.String()
+		}
+		out[i] = dto
+	}
+	return out
+}
+
+// parseEscalationLevels converts request levels, returning a message for
+// the first malformed ID.
+func parseEscalationLevels(dtos []escalationLevelDTO) ([]domain.EscalationLevel, string) {
+	levels := make([]domain.EscalationLevel, len(dtos))
+	for i, dto := range dtos {
+		level := domain.EscalationLevel{DelayMinutes: dto.DelayMinutes}
+		for _, raw := range dto.ChannelIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, "invalid channel ID: " + raw
+			}
+			level.ChannelIDs = append(level.ChannelIDs, id)
+		}
+		for _, raw := range dto.UserIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, "invalid user ID: " + raw
+			}
+			level.UserIDs = append(level.UserIDs, id)
+		}
+		for _, raw := range dto.ScheduleIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, "invalid schedule ID: " + raw
+			}
+			level.ScheduleIDs = append(level.ScheduleIDs, id)
+		}
+		levels[i] = level
+	}
+	return levels, ""
+}
+
+func toEscalationPolicyResponse(p *domain.EscalationPolicy) escalationPolicyResponse {
+	return escalationPolicyResponse{
+		ID:        p.ID.String(),
+		Name:      p.Name,
+		Levels:    toEscalationLevelDTOs(p.Levels),
+		Repeat:    p.Repeat,
+		CreatedAt: p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// policy resolves the :id path parameter to a policy owned by the user,
+// writing the error response when it cannot.
+func (h *EscalationHandler) policy(c echo.Context, userID uuid.UUID) (*domain.EscalationPolicy, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errJSON(c, http.StatusBadRequest, "invalid policy ID")
+	}
+	policy, err := h.escalationSvc.GetPolicy(c.Request().Context(), id)
+	if err != nil {
+		return nil, errJSON(c, http.StatusInternalServerError, "failed to fetch escalation policy")
+	}
+	if policy == nil || policy.UserID != userID {
+		return nil, errJSON(c, http.StatusNotFound, "escalation policy not found")
+	}
+	return policy, nil
+}
+
+// List returns the authenticated user's escalation policies.
+// GET /api/v1/escalation-policies
+func (h *EscalationHandler) List(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	policies, err := h.escalationSvc.ListPolicies(c.Request().Context(), userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch escalation policies")
+	}
+
+	result := make([]escalationPolicyResponse, 0, len(policies))
+	for _, p := range policies {
+		result = append(result, toEscalationPolicyResponse(p))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// Get returns a single escalation policy.
+// GET /api/v1/escalation-policies/:id
+func (h *EscalationHandler) Get(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	policy, err := h.policy(c, userID)
+	if policy == nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": toEscalationPolicyResponse(policy)})
+}
+
+// Create creates a new escalation policy.
+// POST /api/v1/escalation-policies
+func (h *EscalationHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req escalationPolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	levels, msg := parseEscalationLevels(req.Levels)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	policy, err := domain.NewEscalationPolicy(userID, req.Name, levels, req.Repeat)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.escalationSvc.CreatePolicy(ctx, policy); err != nil {
+		return policyError(c, err, "failed to create escalation policy")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditEscalationPolicyCreated, c.RealIP(), map[string]string{
+			"policy_id": policy.ID.String(), "name": policy.Name,
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": toEscalationPolicyResponse(policy)})
+}
+
+// Update replaces an escalation policy's name, levels and repeat count.
+// Escalations already running keep the levels they started with.
+// PUT /api/v1/escalation-policies/:id
+func (h *EscalationHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	policy, err := h.policy(c, userID)
+	if policy == nil {
+		return err
+	}
+
+	var req escalationPolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	levels, msg := parseEscalationLevels(req.Levels)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	policy.Name = req.Name
+	policy.Levels = levels
+	policy.Repeat = req.Repeat
+
+	if err := h.escalationSvc.UpdatePolicy(ctx, policy); err != nil {
+		return policyError(c, err, "failed to update escalation policy")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditEscalationPolicyUpdated, c.RealIP(), map[string]string{
+			"policy_id": policy.ID.String(), "name": policy.Name,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toEscalationPolicyResponse(policy)})
+}
+
+// Delete removes an escalation policy.
+// DELETE /api/v1/escalation-policies/:id
+func (h *EscalationHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	policy, err := h.policy(c, userID)
+	if policy == nil {
+		return err
+	}
+
+	if err := h.escalationSvc.DeletePolicy(ctx, policy.ID); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to delete escalation policy")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditEscalationPolicyDeleted, c.RealIP(), map[string]string{
+			"policy_id": policy.ID.String(), "name": policy.Name,
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetIncidentEscalation returns the escalation running for an incident.
+// GET /api/v1/incidents/:id/escalation
+func (h *EscalationHandler) GetIncidentEscalation(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	incidentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid incident ID")
+	}
+	incident, err := verifyIncidentOwnership(ctx, h.incidentSvc, h.monitorRepo, h.agentRepo, incidentID, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch incident")
+	}
+	if incident == nil {
+		return errJSON(c, http.StatusNotFound, "incident not found")
+	}
+
+	esc, err := h.escalationSvc.GetEscalation(ctx, incident.ID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch escalation")
+	}
+	if esc == nil {
+		return errJSON(c, http.StatusNotFound, "no escalation is running for this incident")
+	}
+
+	resp := escalationResponse{
+		IncidentID:  esc.IncidentID.String(),
+		PolicyID:    esc.PolicyID.String(),
+		LevelsPaged: esc.Step,
+		TotalLevels: len(esc.Steps),
+		StartedAt:   esc.CreatedAt.Format(time.RFC3339),
+	}
+	if esc.WorkflowID != uuid.Nil {
+		resp.WorkflowID = esc.WorkflowID.String()
+	}
+	if esc.ResumeAt != nil {
+		t := esc.ResumeAt.Format(time.RFC3339)
+		resp.NextPageAt = &t
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": resp})
+}
+
+// policyError maps EscalationService errors to responses.
+func policyError(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, domain.ErrInvalidEscalationPolicy) {
+		return errJSON(c, http.StatusBadRequest, errors.Unwrap(err).Error())
+	}
+	return errJSON(c, http.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+)
+
+// IncidentActivityHandler serves endpoints for incident notes and postmortems.
+type IncidentActivityHandler struct {
+	activitySvc ports.IncidentActivityService
+	incidentSvc ports.IncidentService
+	monitorRepo ports.MonitorRepository
+	agentRepo   ports.AgentRepository
+	auditSvc    ports.AuditService
+}
+
+// NewIncidentActivityHandler creates a new IncidentActivityHandler.
+func NewIncidentActivityHandler(activitySvc ports.IncidentActivityService, incidentSvc ports.IncidentService, monitorRepo ports.MonitorRepository, agentRepo ports.AgentRepository, auditSvc ports.AuditService) *IncidentActivityHandler {
+	return &IncidentActivityHandler{activitySvc: activitySvc, incidentSvc: incidentSvc, monitorRepo: monitorRepo, agentRepo: agentRepo, auditSvc: auditSvc}
+}
+
+type incidentActivityResponse struct {
+	ID          string  `json:"id"`
+	Kind        string  `json:"kind"`
+	AuthorID    *string `json:"author_id"`
+	AuthorEmail string  `json:"author_email,omitempty"`
+	Body        string  `json:"body,omitempty"`
+	URL         string  `json:"url,omitempty"`
+	Status      string  `json:"status,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type incidentNoteRequest struct {
+	Kind string `json:"kind"`
+	Body string `json:"body"`
+	URL  string `json:"url"`
+}
+
+type postmortemResponse struct {
+	IncidentID  string                        `json:"incident_id"`
+	RootCause   string                        `json:"root_cause"`
+	Impact      string                        `json:"impact"`
+	ActionItems []domain.PostmortemActionItem `json:"action_items"`
+	AuthorID    *string                       `json:"author_id"`
+	AuthorEmail string                        `json:"author_email,omitempty"`
+	CreatedAt   string                        `json:"created_at"`
+	UpdatedAt   string                        `json:"updated_at"`
+}
+
+type postmortemRequest struct {
+	RootCause   string                        `json:"root_cause"`
+	Impact      string                        `json:"impact"`
+	ActionItems []domain.PostmortemActionItem `json:"action_items"`
+}
+
+func toIncidentActivityResponse(a *domain.IncidentActivity) incidentActivityResponse {
+	resp := incidentActivityResponse{
+		ID:          a.ID.String(),
+		Kind:        string(a.Kind),
+		AuthorEmail: a.AuthorEmail,
+		Body:        a.Body,
+		URL:         a.URL,
+		Status:      string(a.Status),
+		CreatedAt:   a.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   a.UpdatedAt.Format(time.RFC3339),
+	}
+	if a.AuthorID != nil {
+		id := a.AuthorID.String()
+		resp.AuthorID = &id
+	}
+	return resp
+}
+
+func toPostmortemResponse(p *domain.IncidentPostmortem) *postmortemResponse {
+	if p == nil {
+		return nil
+	}
+	resp := &postmortemResponse{
+		IncidentID:  p.IncidentID.String(),
+		RootCause:   p.RootCause,
+		Impact:      p.Impact,
+		ActionItems: p.ActionItems,
+		AuthorEmail: p.AuthorEmail,
+		CreatedAt:   p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   p.UpdatedAt.Format(time.RFC3339),
+	}
+	if resp.ActionItems == nil {
+		resp.ActionItems = []domain.PostmortemActionItem{}
+	}
+	if p.AuthorID != nil {
+		id := p.AuthorID.String()
+		resp.AuthorID = &id
+	}
+	return resp
+}
+
+// incident resolves the :id path parameter to an incident owned by the user,
+// writing the error response when it cannot.
+func (h *IncidentActivityHandler) incident(c echo.Context, userID uuid.UUID) (*domain.Incident, error) {
+	incidentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errJSON(c, http.StatusBadRequest, "invalid incident ID")
+	}
+	incident, err := verifyIncidentOwnership(c.Request().Context(), h.incidentSvc, h.monitorRepo, h.agentRepo, incidentID, userID)
+	if err != nil {
+		return nil, errJSON(c, http.StatusInternalServerError, "failed to fetch incident")
+	}
+	if incident == nil {
+		return nil, errJSON(c, http.StatusNotFound, "incident not found")
+	}
+	return incident, nil
+}
+
+// ListNotes returns the incident's activity log: notes, links and status changes.
+// GET /api/v1/incidents/:id/notes
+func (h *IncidentActivityHandler) ListNotes(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	incident, err := h.incident(c, userID)
+	if incident == nil {
+		return err
+	}
+
+	activity, err := h.activitySvc.ListActivity(c.Request().Context(), incident.ID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch notes")
+	}
+
+	result := make([]incidentActivityResponse, 0, len(activity))
+	for _, a := range activity {
+		result = append(result, toIncidentActivityResponse(a))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// CreateNote adds a note or link to the incident. kind defaults to note.
+// POST /api/v1/incidents/:id/notes
+func (h *IncidentActivityHandler) CreateNote(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	incident, err := h.incident(c, userID)
+	if incident == nil {
+		return err
+	}
+
+	var req incidentNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	kind := domain.IncidentActivityKind(req.Kind)
+	if kind == "" {
+		kind = domain.IncidentActivityNote
+	}
+	note, err := domain.NewIncidentNote(incident.ID, userID, kind, req.Body, req.URL)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.activitySvc.AddNote(ctx, note); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to create note")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentNoteAdded, c.RealIP(), map[string]string{
+			"incident_id": incident.ID.String(), "note_id": note.ID.String(), "kind": string(note.Kind),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": toIncidentActivityResponse(note)})
+}
+
+// UpdateNote edits a note or link. Status changes cannot be edited.
+// PUT /api/v1/incidents/:id/notes/:noteId
+func (h *IncidentActivityHandler) UpdateNote(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid note ID")
+	}
+	incident, err := h.incident(c, userID)
+	if incident == nil {
+		return err
+	}
+
+	var req incidentNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	note, err := h.activitySvc.EditNote(ctx, incident.ID, noteID, req.Body, req.URL)
+	if err != nil {
+		return noteError(c, err, "failed to update note")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentNoteUpdated, c.RealIP(), map[string]string{
+			"incident_id": incident.ID.String(), "note_id": note.ID.String(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toIncidentActivityResponse(note)})
+}
+
+// DeleteNote removes a note or link. Status changes cannot be deleted.
+// DELETE /api/v1/incidents/:id/notes/:noteId
+func (h *IncidentActivityHandler) DeleteNote(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid note ID")
+	}
+	incident, err := h.incident(c, userID)
+	if incident == nil {
+		return err
+	}
+
+	if err := h.activitySvc.DeleteNote(ctx, incident.ID, noteID); err != nil {
+		return noteError(c, err, "failed to delete note")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentNoteDeleted, c.RealIP(), map[string]string{
+			"incident_id": incident.ID.String(), "note_id": noteID.String(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetPostmortem returns the incident's postmortem.
+// GET /api/v1/incidents/:id/postmortem
+func (h *IncidentActivityHandler) GetPostmortem(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	incident, err := h.incident(c, userID)
+	if incident == nil {
+		return err
+	}
+
+	postmortem, err := h.activitySvc.GetPostmortem(c.Request().Context(), incident.ID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch postmortem")
+	}
+	if postmortem == nil {
+		return errJSON(c, http.StatusNotFound, "postmortem not found")
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toPostmortemResponse(postmortem)})
+}
+
+// SavePostmortem creates or replaces the postmortem of a resolved incident.
+// PUT /api/v1/incidents/:id/postmortem
+func (h *IncidentActivityHandler) SavePostmortem(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	incident, err := h.incident(c, userID)
+	if incident == nil {
+		return err
+	}
+
+	var req postmortemRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	postmortem := &domain.IncidentPostmortem{
+		IncidentID:  incident.ID,
+		RootCause:   req.RootCause,
+		Impact:      req.Impact,
+		ActionItems: req.ActionItems,
+	}
+	if err := h.activitySvc.SavePostmortem(ctx, postmortem, userID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPostmortemUnresolved):
+			return errJSON(c, http.StatusConflict, domain.ErrPostmortemUnresolved.Error())
+		case errors.Is(err, domain.ErrInvalidPostmortem):
+			return errJSON(c, http.StatusBadRequest, errors.Unwrap(err).Error())
+		}
+		return errJSON(c, http.StatusInternalServerError, "failed to save postmortem")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditIncidentPostmortemSaved, c.RealIP(), map[string]string{
+			"incident_id": incident.ID.String(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toPostmortemResponse(postmortem)})
+}
+
+// noteError maps IncidentActivityService errors to responses.
+func noteError(c echo.Context, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrIncidentActivityNotFound):
+		return errJSON(c, http.StatusNotFound, "note not found")
+	case errors.Is(err, domain.ErrIncidentActivityImmutable):
+		return errJSON(c, http.StatusConflict, domain.ErrIncidentActivityImmutable.Error())
+	case errors.Is(err, domain.ErrInvalidIncidentActivity):
+		return errJSON(c, http.StatusBadRequest, errors.Unwrap(err).Error())
+	}
+	return errJSON(c, http.StatusInternalServerError, fallback)
+}
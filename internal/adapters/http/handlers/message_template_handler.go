@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+	"github.com/sylvester-francis/watchdog/internal/adapters/notify"
+)
+
+// MessageTemplateHandler serves CRUD endpoints for notification message
+// templates and a preview that renders a template against a sample event.
+type MessageTemplateHandler struct {
+	templateRepo ports.MessageTemplateRepository
+	channelRepo  ports.AlertChannelRepository
+	auditSvc     ports.AuditService
+}
+
+// NewMessageTemplateHandler creates a new MessageTemplateHandler.
+func NewMessageTemplateHandler(templateRepo ports.MessageTemplateRepository, channelRepo ports.AlertChannelRepository, auditSvc ports.AuditService) *MessageTemplateHandler {
+	return &MessageTemplateHandler{templateRepo: templateRepo, channelRepo: channelRepo, auditSvc: auditSvc}
+}
+
+type messageTemplateResponse struct {
+	ID          string  `json:"id"`
+	ChannelType string  `json:"channel_type"`
+	ChannelID   *string `json:"channel_id"`
+	Event       string  `json:"event"`
+	Subject     string  `json:"subject"`
+	Body        string  `json:"body"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type messageTemplateRequest struct {
+	ChannelType string `json:"channel_type"`
+	ChannelID   string `json:"channel_id"`
+	Event       string `json:"event"`
+	Subject     string `json:"subject"`
+	Body        string `json:"body"`
+}
+
+type messageTemplatePreviewRequest struct {
+	messageTemplateRequest
+	// SampleEvent picks the sample rendered for a template that applies to
+	// any event. Defaults to the template's event, or incident_opened.
+	SampleEvent string `json:"sample_event"`
+}
+
+type messageTemplatePreviewResponse struct {
+	Event   string `json:"event"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+func toMessageTemplateResponse(t *domain.MessageTemplate) messageTemplateResponse {
+	resp := messageTemplateResponse{
+		ID:          t.ID.String(),
+		ChannelType: string(t.ChannelType),
+		Event:       t.Event,
+		Subject:     t.Subject,
+		Body:        t.Body,
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   t.UpdatedAt.Format(time.RFC3339),
+	}
+	if t.ChannelID != nil {
+		id := t.ChannelID.String()
+		resp.ChannelID = &id
+	}
+	return resp
+}
+
+// scope resolves a request's channel_id and channel_type. A channel's
+// template takes the channel's type. When ok is false the error response has
+// been written.
+func (h *MessageTemplateHandler) scope(c echo.Context, userID uuid.UUID, req messageTemplateRequest) (channelType domain.AlertChannelType, channelID *uuid.UUID, ok bool, err error) {
+	if req.ChannelID == "" {
+		return domain.AlertChannelType(req.ChannelType), nil, true, nil
+	}
+	id, err := uuid.Parse(req.ChannelID)
+	if err != nil {
+		return "", nil, false, errJSON(c, http.StatusBadRequest, "invalid channel ID")
+	}
+	channel, err := h.channelRepo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return "", nil, false, errJSON(c, http.StatusInternalServerError, "failed to fetch channel")
+	}
+	if channel == nil || channel.UserID != userID {
+		return "", nil, false, errJSON(c, http.StatusNotFound, "channel not found")
+	}
+	if req.ChannelType != "" && domain.AlertChannelType(req.ChannelType) != channel.Type {
+		return "", nil, false, errJSON(c, http.StatusBadRequest, "channel_type does not match the channel")
+	}
+	return channel.Type, &id, true, nil
+}
+
+// template resolves the :id path parameter to a template owned by the user,
+// writing the error response when it cannot.
+func (h *MessageTemplateHandler) template(c echo.Context, userID uuid.UUID) (*domain.MessageTemplate, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errJSON(c, http.StatusBadRequest, "invalid template ID")
+	}
+	t, err := h.templateRepo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, errJSON(c, http.StatusInternalServerError, "failed to fetch message template")
+	}
+	if t == nil || t.UserID != userID {
+		return nil, errJSON(c, http.StatusNotFound, "message template not found")
+	}
+	return t, nil
+}
+
+// List returns the authenticated user's message templates.
+// GET /api/v1/message-templates
+func (h *MessageTemplateHandler) List(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	templates, err := h.templateRepo.GetByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch message templates")
+	}
+
+	result := make([]messageTemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, toMessageTemplateResponse(t))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// Create creates a message template for a channel type, or for one channel
+// when channel_id is set. The template must render a sample of every event
+// it applies to.
+// POST /api/v1/message-templates
+func (h *MessageTemplateHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req messageTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	channelType, channelID, ok, err := h.scope(c, userID, req)
+	if !ok {
+		return err
+	}
+	t, err := domain.NewMessageTemplate(userID, channelType, channelID, req.Event, req.Subject, req.Body)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := notify.ValidateTemplate(t); err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	existing, err := h.templateRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch message templates")
+	}
+	if len(existing) >= domain.MaxMessageTemplates {
+		return errJSON(c, http.StatusBadRequest, "message template limit reached")
+	}
+	for _, e := range existing {
+		if e.ChannelType == t.ChannelType && e.Event == t.Event && sameChannel(e.ChannelID, t.ChannelID) {
+			return errJSON(c, http.StatusConflict, "a template for this channel and event already exists")
+		}
+	}
+
+	if err := h.templateRepo.Create(ctx, t); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to create message template")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditMessageTemplateCreated, c.RealIP(), messageTemplateAuditMeta(t))
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": toMessageTemplateResponse(t)})
+}
+
+// Update replaces a message template's subject and body. Its channel and
+// event cannot change.
+// PUT /api/v1/message-templates/:id
+func (h *MessageTemplateHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	t, err := h.template(c, userID)
+	if t == nil {
+		return err
+	}
+
+	var req messageTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	t.Subject = req.Subject
+	t.Body = req.Body
+	t.UpdatedAt = time.Now()
+	if err := notify.ValidateTemplate(t); err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.templateRepo.Update(ctx, t); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to update message template")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditMessageTemplateUpdated, c.RealIP(), messageTemplateAuditMeta(t))
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toMessageTemplateResponse(t)})
+}
+
+// Delete removes a message template; its channels go back to the next
+// matching template or the built-in layout.
+// DELETE /api/v1/message-templates/:id
+func (h *MessageTemplateHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	t, err := h.template(c, userID)
+	if t == nil {
+		return err
+	}
+
+	if err := h.templateRepo.Delete(ctx, t.ID); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to delete message template")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditMessageTemplateDeleted, c.RealIP(), messageTemplateAuditMeta(t))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Preview renders an unsaved template against a sample event, returning
+// the message it would send or why it is invalid. Nothing is sent.
+// POST /api/v1/message-templates/preview
+func (h *MessageTemplateHandler) Preview(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req messageTemplatePreviewRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	channelType, channelID, ok, err := h.scope(c, userID, req.messageTemplateRequest)
+	if !ok {
+		return err
+	}
+	t := &domain.MessageTemplate{
+		UserID:      userID,
+		ChannelType: channelType,
+		ChannelID:   channelID,
+		Event:       req.Event,
+		Subject:     req.Subject,
+		Body:        req.Body,
+	}
+	if err := t.Validate(); err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	event := req.SampleEvent
+	if event == "" {
+		event = t.Event
+	}
+	if event == domain.TemplateEventAny {
+		event = string(domain.AlertEventIncidentOpened)
+	}
+	if !domain.ValidTemplateEvent(event) || (t.Event != domain.TemplateEventAny && event != t.Event) {
+		return errJSON(c, http.StatusBadRequest, "sample_event must be an event the template applies to")
+	}
+
+	msg, err := notify.RenderTemplate(t, notify.SampleTemplateData(event))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, fmt.Errorf("%w: %v", domain.ErrInvalidMessageTemplate, err).Error())
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": messageTemplatePreviewResponse{
+		Event:   event,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+	}})
+}
+
+func sameChannel(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func messageTemplateAuditMeta(t *domain.MessageTemplate) map[string]string {
+	meta := map[string]string{
+		"template_id":  t.ID.String(),
+		"channel_type": string(t.ChannelType),
+		"event":        t.Event,
+	}
+	if t.ChannelID != nil {
+		meta["channel_id"] = t.ChannelID.String()
+	}
+	return meta
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+)
+
+// NotificationDeliveryHandler serves the notification delivery log: every
+// attempt to send an alert, failed deliveries waiting for a retry, the
+// dead-letter list, and redelivery.
+type NotificationDeliveryHandler struct {
+	deliverySvc ports.NotificationDeliveryService
+	userRepo    ports.UserRepository
+	auditSvc    ports.AuditService
+}
+
+// NewNotificationDeliveryHandler creates a new NotificationDeliveryHandler.
+func NewNotificationDeliveryHandler(deliverySvc ports.NotificationDeliveryService, userRepo ports.UserRepository, auditSvc ports.AuditService) *NotificationDeliveryHandler {
+	return &NotificationDeliveryHandler{deliverySvc: deliverySvc, userRepo: userRepo, auditSvc: auditSvc}
+}
+
+type notificationDeliveryResponse struct {
+	ID            string                        `json:"id"`
+	ChannelID     *string                       `json:"channel_id"`
+	ChannelName   string                        `json:"channel_name"`
+	ChannelType   string                        `json:"channel_type"`
+	Event         string                        `json:"event"`
+	Subject       string                        `json:"subject"`
+	IncidentID    *string                       `json:"incident_id"`
+	Status        string                        `json:"status"`
+	Attempts      int                           `json:"attempts"`
+	NextAttemptAt *string                       `json:"next_attempt_at"`
+	LastError     string                        `json:"last_error,omitempty"`
+	CreatedAt     string                        `json:"created_at"`
+	UpdatedAt     string                        `json:"updated_at"`
+	History       []notificationAttemptResponse `json:"history,omitempty"`
+}
+
+type notificationAttemptResponse struct {
+	Attempt     int    `json:"attempt"`
+	PayloadHash string `json:"payload_hash"`
+	StatusCode  int    `json:"status_code"`
+	Error       string `json:"error,omitempty"`
+	LatencyMs   int    `json:"latency_ms"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func toNotificationDeliveryResponse(d *domain.NotificationDelivery) notificationDeliveryResponse {
+	resp := notificationDeliveryResponse{
+		ID:          d.ID.String(),
+		ChannelName: d.ChannelName,
+		ChannelType: d.ChannelType,
+		Event:       d.Event,
+		Subject:     d.Subject,
+		Status:      string(d.Status),
+		Attempts:    d.Attempts,
+		LastError:   d.LastError,
+		CreatedAt:   d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   d.UpdatedAt.Format(time.RFC3339),
+	}
+	if d.ChannelID != nil {
+		id := d.ChannelID.String()
+		resp.ChannelID = &id
+	}
+	if d.IncidentID != nil {
+		id := d.IncidentID.String()
+		resp.IncidentID = &id
+	}
+	if d.NextAttemptAt != nil {
+		at := d.NextAttemptAt.Format(time.RFC3339)
+		resp.NextAttemptAt = &at
+	}
+	return resp
+}
+
+func toNotificationAttemptResponse(a *domain.NotificationAttempt) notificationAttemptResponse {
+	return notificationAttemptResponse{
+		Attempt:     a.Attempt,
+		PayloadHash: a.PayloadHash,
+		StatusCode:  a.StatusCode,
+		Error:       a.Error,
+		LatencyMs:   a.LatencyMs,
+		CreatedAt:   a.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// isAdmin reports whether the user may see the global notifiers' deliveries.
+func (h *NotificationDeliveryHandler) isAdmin(c echo.Context, userID uuid.UUID) bool {
+	user, err := h.userRepo.GetByID(c.Request().Context(), userID)
+	return err == nil && user != nil && user.IsAdmin
+}
+
+// delivery resolves the :id path parameter to a delivery the user can see,
+// with its attempts, writing the error response when it cannot. Deliveries
+// of the global notifiers are visible to admins.
+func (h *NotificationDeliveryHandler) delivery(c echo.Context, userID uuid.UUID) (*domain.NotificationDelivery, []*domain.NotificationAttempt, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, nil, errJSON(c, http.StatusBadRequest, "invalid notification ID")
+	}
+	d, attempts, err := h.deliverySvc.GetDelivery(c.Request().Context(), id)
+	if err != nil {
+		return nil, nil, errJSON(c, http.StatusInternalServerError, "failed to fetch notification")
+	}
+	if d == nil {
+		return nil, nil, errJSON(c, http.StatusNotFound, "notification not found")
+	}
+	if d.UserID != nil && *d.UserID == userID {
+		return d, attempts, nil
+	}
+	if d.UserID == nil && h.isAdmin(c, userID) {
+		return d, attempts, nil
+	}
+	return nil, nil, errJSON(c, http.StatusNotFound, "notification not found")
+}
+
+// List returns the user's notification deliveries, newest first, and for
+// admins those of the global notifiers. Filter with ?status=delivered,
+// retrying or dead (the dead-letter list), ?channel_id= and ?limit= (max 100).
+// GET /api/v1/notifications
+func (h *NotificationDeliveryHandler) List(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	filter := domain.NotificationDeliveryFilter{
+		UserID: userID,
+		Status: domain.DeliveryStatus(c.QueryParam("status")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return errJSON(c, http.StatusBadRequest, "status must be delivered, retrying or dead")
+	}
+	if v := c.QueryParam("channel_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "invalid channel ID")
+		}
+		filter.ChannelID = &id
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return errJSON(c, http.StatusBadRequest, "invalid limit")
+		}
+		filter.Limit = limit
+	}
+	// Global deliveries have no channel, so a channel filter excludes them.
+	filter.IncludeGlobal = filter.ChannelID == nil && h.isAdmin(c, userID)
+
+	deliveries, err := h.deliverySvc.ListDeliveries(c.Request().Context(), filter)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch notifications")
+	}
+
+	result := make([]notificationDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, toNotificationDeliveryResponse(d))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// Get returns a notification delivery with every attempt made to send it.
+// GET /api/v1/notifications/:id
+func (h *NotificationDeliveryHandler) Get(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	d, attempts, err := h.delivery(c, userID)
+	if d == nil {
+		return err
+	}
+
+	resp := toNotificationDeliveryResponse(d)
+	resp.History = make([]notificationAttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		resp.History = append(resp.History, toNotificationAttemptResponse(a))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": resp})
+}
+
+// Redeliver sends a notification again now, whatever its status, and
+// returns the delivery with the new attempt. A failed attempt is reported in
+// the response rather than as an error.
+// POST /api/v1/notifications/:id/redeliver
+func (h *NotificationDeliveryHandler) Redeliver(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	d, _, err := h.delivery(c, userID)
+	if d == nil {
+		return err
+	}
+
+	attempt, err := h.deliverySvc.Redeliver(ctx, d)
+	if err != nil {
+		if errors.Is(err, domain.ErrDeliveryTargetGone) {
+			return errJSON(c, http.StatusConflict, domain.ErrDeliveryTargetGone.Error())
+		}
+		return errJSON(c, http.StatusInternalServerError, "failed to redeliver notification")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditNotificationRedelivered, c.RealIP(), map[string]string{
+			"notification_id": d.ID.String(),
+			"channel":         d.ChannelName,
+			"event":           d.Event,
+			"status":          string(d.Status),
+		})
+	}
+
+	resp := toNotificationDeliveryResponse(d)
+	resp.History = []notificationAttemptResponse{toNotificationAttemptResponse(attempt)}
+	return c.JSON(http.StatusOK, map[string]any{"data": resp})
+}
@@ -0,0 +1,628 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/http/middleware"
+)
+
+// Windows of the on-call shift and iCal endpoints.
+const (
+	defaultOnCallShiftDays = 14
+	maxOnCallShiftDays     = 90
+	onCallICalPastDays     = 7
+)
+
+// OnCallHandler serves CRUD endpoints for on-call schedules and overrides,
+// who is on call, and the iCal export.
+type OnCallHandler struct {
+	onCallSvc ports.OnCallService
+	userRepo  ports.UserRepository
+	auditSvc  ports.AuditService
+}
+
+// NewOnCallHandler creates a new OnCallHandler.
+func NewOnCallHandler(onCallSvc ports.OnCallService, userRepo ports.UserRepository, auditSvc ports.AuditService) *OnCallHandler {
+	return &OnCallHandler{onCallSvc: onCallSvc, userRepo: userRepo, auditSvc: auditSvc}
+}
+
+type onCallLayerDTO struct {
+	Name         string   `json:"name"`
+	Rotation     string   `json:"rotation"`
+	StartDate    string   `json:"start_date"`
+	HandoffTime  string   `json:"handoff_time"`
+	EndDate      string   `json:"end_date,omitempty"`
+	Participants []string `json:"participants"`
+}
+
+type onCallScheduleResponse struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Timezone  string           `json:"timezone"`
+	Layers    []onCallLayerDTO `json:"layers"`
+	CreatedAt string           `json:"created_at"`
+	UpdatedAt string           `json:"updated_at"`
+}
+
+type onCallScheduleRequest struct {
+	Name     string           `json:"name"`
+	Timezone string           `json:"timezone"`
+	Layers   []onCallLayerDTO `json:"layers"`
+}
+
+type onCallOverrideResponse struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type onCallOverrideRequest struct {
+	UserID   string `json:"user_id"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+type onCallShiftResponse struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Override bool   `json:"override"`
+}
+
+func toOnCallScheduleResponse(s *domain.OnCallSchedule) onCallScheduleResponse {
+	layers := make([]onCallLayerDTO, len(s.Layers))
+	for i, l := range s.Layers {
+		participants := make([]string, len(l.Participants))
+		for j, id := range l.Participants {
+			participants[j] = id.String()
+		}
+		layers[i] = onCallLayerDTO{
+			Name:         l.Name,
+			Rotation:     string(l.Rotation),
+			StartDate:    l.StartDate,
+			HandoffTime:  l.HandoffTime,
+			EndDate:      l.EndDate,
+			Participants: participants,
+		}
+	}
+	return onCallScheduleResponse{
+		ID:        s.ID.String(),
+		Name:      s.Name,
+		Timezone:  s.Timezone,
+		Layers:    layers,
+		CreatedAt: s.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: s.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toOnCallOverrideResponse(o *domain.OnCallOverride) onCallOverrideResponse {
+	return onCallOverrideResponse{
+		ID:        o.ID.String(),
+		UserID:    o.UserID.String(),
+		StartsAt:  o.StartsAt.Format(time.RFC3339),
+		EndsAt:    o.EndsAt.Format(time.RFC3339),
+		CreatedAt: o.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// parseOnCallLayers converts request layers, returning a message for the
+// first malformed participant ID.
+func parseOnCallLayers(dtos []onCallLayerDTO) ([]domain.OnCallLayer, string) {
+	layers := make([]domain.OnCallLayer, len(dtos))
+	for i, dto := range dtos {
+		layer := domain.OnCallLayer{
+			Name:        strings.TrimSpace(dto.Name),
+			Rotation:    domain.OnCallRotation(dto.Rotation),
+			StartDate:   dto.StartDate,
+			HandoffTime: dto.HandoffTime,
+			EndDate:     dto.EndDate,
+		}
+		for _, raw := range dto.Participants {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, "invalid participant ID: " + raw
+			}
+			layer.Participants = append(layer.Participants, id)
+		}
+		layers[i] = layer
+	}
+	return layers, ""
+}
+
+// schedule resolves the :id path parameter to a schedule owned by the user,
+// writing the error response when it cannot.
+func (h *OnCallHandler) schedule(c echo.Context, userID uuid.UUID) (*domain.OnCallSchedule, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errJSON(c, http.StatusBadRequest, "invalid schedule ID")
+	}
+	schedule, err := h.onCallSvc.GetSchedule(c.Request().Context(), id)
+	if err != nil {
+		return nil, errJSON(c, http.StatusInternalServerError, "failed to fetch on-call schedule")
+	}
+	if schedule == nil || schedule.UserID != userID {
+		return nil, errJSON(c, http.StatusNotFound, "on-call schedule not found")
+	}
+	return schedule, nil
+}
+
+// shiftResponses renders shifts with the names of the users on call.
+func (h *OnCallHandler) shiftResponses(ctx context.Context, shifts []domain.OnCallShift) []onCallShiftResponse {
+	users := h.users(ctx, shifts)
+	result := make([]onCallShiftResponse, 0, len(shifts))
+	for _, s := range shifts {
+		resp := onCallShiftResponse{
+			UserID:   s.UserID.String(),
+			Start:    s.Start.Format(time.RFC3339),
+			End:      s.End.Format(time.RFC3339),
+			Override: s.Override,
+		}
+		if u := users[s.UserID]; u != nil {
+			resp.Username = u.Username
+			resp.Email = u.Email
+		}
+		result = append(result, resp)
+	}
+	return result
+}
+
+// users looks up the users on call in shifts. Users that cannot be found
+// map to nil.
+func (h *OnCallHandler) users(ctx context.Context, shifts []domain.OnCallShift) map[uuid.UUID]*domain.User {
+	users := make(map[uuid.UUID]*domain.User)
+	for _, s := range shifts {
+		if _, ok := users[s.UserID]; ok {
+			continue
+		}
+		u, err := h.userRepo.GetByID(ctx, s.UserID)
+		if err != nil {
+			u = nil
+		}
+		users[s.UserID] = u
+	}
+	return users
+}
+
+// shiftWindow parses the from/to query parameters, defaulting to the next
+// two weeks.
+func shiftWindow(c echo.Context) (time.Time, time.Time, string) {
+	from := time.Now()
+	if raw := c.QueryParam("from"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, "from must be an RFC3339 timestamp"
+		}
+		from = t
+	}
+	to := from.AddDate(0, 0, defaultOnCallShiftDays)
+	if raw := c.QueryParam("to"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, "to must be an RFC3339 timestamp"
+		}
+		to = t
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, "to must be after from"
+	}
+	if to.Sub(from) > maxOnCallShiftDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Sprintf("the window cannot exceed %d days", maxOnCallShiftDays)
+	}
+	return from, to, ""
+}
+
+// List returns the authenticated user's on-call schedules.
+// GET /api/v1/oncall-schedules
+func (h *OnCallHandler) List(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	schedules, err := h.onCallSvc.ListSchedules(c.Request().Context(), userID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch on-call schedules")
+	}
+
+	result := make([]onCallScheduleResponse, 0, len(schedules))
+	for _, s := range schedules {
+		result = append(result, toOnCallScheduleResponse(s))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// Get returns a single on-call schedule.
+// GET /api/v1/oncall-schedules/:id
+func (h *OnCallHandler) Get(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": toOnCallScheduleResponse(schedule)})
+}
+
+// Create creates a new on-call schedule.
+// POST /api/v1/oncall-schedules
+func (h *OnCallHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req onCallScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	layers, msg := parseOnCallLayers(req.Layers)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	schedule, err := domain.NewOnCallSchedule(userID, req.Name, req.Timezone, layers)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.onCallSvc.CreateSchedule(ctx, schedule); err != nil {
+		return onCallError(c, err, "failed to create on-call schedule")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditOnCallScheduleCreated, c.RealIP(), map[string]string{
+			"schedule_id": schedule.ID.String(), "name": schedule.Name,
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": toOnCallScheduleResponse(schedule)})
+}
+
+// Update replaces an on-call schedule's name, timezone and layers.
+// PUT /api/v1/oncall-schedules/:id
+func (h *OnCallHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+
+	var req onCallScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	layers, msg := parseOnCallLayers(req.Layers)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+	schedule.Name = req.Name
+	schedule.Timezone = req.Timezone
+	schedule.Layers = layers
+
+	if err := h.onCallSvc.UpdateSchedule(ctx, schedule); err != nil {
+		return onCallError(c, err, "failed to update on-call schedule")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditOnCallScheduleUpdated, c.RealIP(), map[string]string{
+			"schedule_id": schedule.ID.String(), "name": schedule.Name,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": toOnCallScheduleResponse(schedule)})
+}
+
+// Delete removes an on-call schedule and its overrides.
+// DELETE /api/v1/oncall-schedules/:id
+func (h *OnCallHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+
+	if err := h.onCallSvc.DeleteSchedule(ctx, schedule.ID); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to delete on-call schedule")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditOnCallScheduleDeleted, c.RealIP(), map[string]string{
+			"schedule_id": schedule.ID.String(), "name": schedule.Name,
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// WhoIsOnCall returns who is on call for a schedule at a point in time.
+// Optional query: `at` (RFC3339, default now).
+// GET /api/v1/oncall-schedules/:id/oncall
+func (h *OnCallHandler) WhoIsOnCall(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+
+	at := time.Now()
+	if raw := c.QueryParam("at"); raw != "" {
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "at must be an RFC3339 timestamp")
+		}
+	}
+
+	shift, err := h.onCallSvc.WhoIsOnCall(ctx, schedule.ID, at)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to resolve on-call user")
+	}
+	if shift == nil {
+		return errJSON(c, http.StatusNotFound, domain.ErrNobodyOnCall.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": h.shiftResponses(ctx, []domain.OnCallShift{*shift})[0]})
+}
+
+// ListShifts returns who is on call over a window.
+// Optional query: `from`, `to` (RFC3339, default the next 14 days, at most 90 days).
+// GET /api/v1/oncall-schedules/:id/shifts
+func (h *OnCallHandler) ListShifts(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+	from, to, msg := shiftWindow(c)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+
+	shifts, err := h.onCallSvc.Shifts(ctx, schedule, from, to)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to compute shifts")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": h.shiftResponses(ctx, shifts)})
+}
+
+// ListOverrides returns a schedule's overrides over a window.
+// Optional query: `from`, `to` (RFC3339, default the next 14 days, at most 90 days).
+// GET /api/v1/oncall-schedules/:id/overrides
+func (h *OnCallHandler) ListOverrides(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+	from, to, msg := shiftWindow(c)
+	if msg != "" {
+		return errJSON(c, http.StatusBadRequest, msg)
+	}
+
+	overrides, err := h.onCallSvc.ListOverrides(ctx, schedule.ID, from, to)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch overrides")
+	}
+	result := make([]onCallOverrideResponse, 0, len(overrides))
+	for _, o := range overrides {
+		result = append(result, toOnCallOverrideResponse(o))
+	}
+	return c.JSON(http.StatusOK, map[string]any{"data": result})
+}
+
+// CreateOverride puts a user on call for a schedule for a period.
+// POST /api/v1/oncall-schedules/:id/overrides
+func (h *OnCallHandler) CreateOverride(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+
+	var req onCallOverrideRequest
+	if err := c.Bind(&req); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	overrideUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid user_id")
+	}
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "starts_at must be an RFC3339 timestamp")
+	}
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "ends_at must be an RFC3339 timestamp")
+	}
+	override, err := domain.NewOnCallOverride(schedule.ID, overrideUserID, startsAt, endsAt)
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.onCallSvc.CreateOverride(ctx, override); err != nil {
+		return onCallError(c, err, "failed to create override")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditOnCallOverrideCreated, c.RealIP(), map[string]string{
+			"schedule_id": schedule.ID.String(), "override_id": override.ID.String(), "user_id": override.UserID.String(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"data": toOnCallOverrideResponse(override)})
+}
+
+// DeleteOverride removes an override.
+// DELETE /api/v1/oncall-schedules/:id/overrides/:overrideId
+func (h *OnCallHandler) DeleteOverride(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+	overrideID, err := uuid.Parse(c.Param("overrideId"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid override ID")
+	}
+
+	override, err := h.onCallSvc.GetOverride(ctx, overrideID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to fetch override")
+	}
+	if override == nil || override.ScheduleID != schedule.ID {
+		return errJSON(c, http.StatusNotFound, "override not found")
+	}
+	if err := h.onCallSvc.DeleteOverride(ctx, override.ID); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to delete override")
+	}
+
+	if h.auditSvc != nil {
+		h.auditSvc.LogEvent(ctx, &userID, domain.AuditOnCallOverrideDeleted, c.RealIP(), map[string]string{
+			"schedule_id": schedule.ID.String(), "override_id": override.ID.String(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ExportICal returns the schedule's shifts as an iCalendar feed, from a
+// week ago up to `days` ahead (default 14, at most 90).
+// GET /api/v1/oncall-schedules/:id/ical
+func (h *OnCallHandler) ExportICal(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+	schedule, err := h.schedule(c, userID)
+	if schedule == nil {
+		return err
+	}
+
+	days := defaultOnCallShiftDays
+	if raw := c.QueryParam("days"); raw != "" {
+		days, err = strconv.Atoi(raw)
+		if err != nil || days < 1 || days > maxOnCallShiftDays {
+			return errJSON(c, http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", maxOnCallShiftDays))
+		}
+	}
+
+	now := time.Now()
+	shifts, err := h.onCallSvc.Shifts(ctx, schedule, now.AddDate(0, 0, -onCallICalPastDays), now.AddDate(0, 0, days))
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to compute shifts")
+	}
+
+	names := make(map[uuid.UUID]string)
+	for id, u := range h.users(ctx, shifts) {
+		if u != nil {
+			names[id] = u.Username
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="oncall-%s.ics"`, schedule.ID))
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(encodeOnCallICal(schedule, shifts, names, now)))
+}
+
+// encodeOnCallICal renders shifts as an RFC 5545 calendar with one event
+// per shift. names maps user IDs to display names; IDs are used otherwise.
+func encodeOnCallICal(schedule *domain.OnCallSchedule, shifts []domain.OnCallShift, names map[uuid.UUID]string, now time.Time) string {
+	const stamp = "20060102T150405Z"
+
+	var b strings.Builder
+	line := func(s string) {
+		// Fold lines longer than 75 octets without splitting a character.
+		for len(s) > 75 {
+			cut := 75
+			for !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+			b.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		b.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//WatchDog//On-call//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + icalEscape(schedule.Name))
+	for _, s := range shifts {
+		name := names[s.UserID]
+		if name == "" {
+			name = s.UserID.String()
+		}
+		summary := "On call: " + name
+		if s.Override {
+			summary += " (override)"
+		}
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s-%d@watchdog", schedule.ID, s.Start.Unix()))
+		line("DTSTAMP:" + now.UTC().Format(stamp))
+		line("DTSTART:" + s.Start.UTC().Format(stamp))
+		line("DTEND:" + s.End.UTC().Format(stamp))
+		line("SUMMARY:" + icalEscape(summary))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// icalEscape escapes an iCalendar TEXT value.
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// onCallError maps OnCallService errors to responses.
+func onCallError(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, domain.ErrInvalidOnCallSchedule) || errors.Is(err, domain.ErrInvalidOnCallOverride) {
+		return errJSON(c, http.StatusBadRequest, errors.Unwrap(err).Error())
+	}
+	return errJSON(c, http.StatusInternalServerError, fallback)
+}
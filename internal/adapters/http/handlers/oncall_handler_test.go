@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+func TestEncodeOnCallICal(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	schedule := &domain.OnCallSchedule{ID: uuid.New(), Name: "Primary, EU; " + strings.Repeat("é", 40)}
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	shifts := []domain.OnCallShift{
+		{UserID: alice, Start: start, End: start.Add(24 * time.Hour)},
+		{UserID: bob, Start: start.Add(24 * time.Hour), End: start.Add(30 * time.Hour), Override: true},
+	}
+	names := map[uuid.UUID]string{alice: "Alice"}
+
+	out := encodeOnCallICal(schedule, shifts, names, start)
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT\r\n"))
+	assert.Contains(t, out, "DTSTART:20260302T090000Z\r\n")
+	assert.Contains(t, out, "SUMMARY:On call: Alice\r\n")
+	assert.Contains(t, out, "SUMMARY:On call: "+bob.String()+" (override)\r\n")
+	assert.Contains(t, out, `X-WR-CALNAME:Primary\, EU\; é`)
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, utf8.ValidString(line), "folding must not split characters: %q", line)
+	}
+}
@@ -40,6 +40,7 @@ func verifyMonitorOwnership(
 }
 
 // verifyIncidentOwnership checks the incident -> monitor -> agent -> user ownership chain.
+// Declared incidents have no monitor and belong to the user who declared them.
 // Returns the incident if it exists and belongs to the user.
 // Returns nil, nil when the resource doesn't exist or doesn't belong to the user (caller should 404).
 // Returns nil, err on actual DB errors (caller should 500).
@@ -58,6 +59,12 @@ func verifyIncidentOwnership(
 	if incident == nil {
 		return nil, nil
 	}
+	if incident.IsDeclared() {
+		if !incident.DeclaredBy(userID) {
+			return nil, nil
+		}
+		return incident, nil
+	}
 
 	monitor, err := verifyMonitorOwnership(ctx, monitorRepo, agentRepo, incident.MonitorID, userID)
 	if err != nil {
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/adapters/repository"
+)
+
+type pushService interface {
+	RecordPing(ctx context.Context, monitor *domain.Monitor, signal domain.PushSignal, durationMs *int, logExcerpt string) error
+}
+
+// PushHandler serves the ingest endpoint that jobs watched by push monitors
+// ping. It is unauthenticated: the unguessable token in the URL identifies
+// the monitor and only allows recording pings for it.
+type PushHandler struct {
+	svc         pushService
+	monitorRepo ports.MonitorRepository
+	agentRepo   ports.AgentRepository
+}
+
+// NewPushHandler creates a new PushHandler.
+func NewPushHandler(svc pushService, monitorRepo ports.MonitorRepository, agentRepo ports.AgentRepository) *PushHandler {
+	return &PushHandler{svc: svc, monitorRepo: monitorRepo, agentRepo: agentRepo}
+}
+
+type pushRequest struct {
+	Signal     string `json:"signal"`
+	DurationMs *int   `json:"duration_ms"`
+	Log        string `json:"log"`
+}
+
+// Ping records a success, fail or start signal for a push monitor. The signal
+// is taken from the path, then the body, and defaults to success, so a bare
+// POST works for simple cron jobs.
+// POST /api/v1/push/:token
+// POST /api/v1/push/:token/:signal
+func (h *PushHandler) Ping(c echo.Context) error {
+	var req pushRequest
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			return errJSON(c, http.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	signal := domain.PushSignalSuccess
+	if s := c.Param("signal"); s != "" {
+		signal = domain.PushSignal(s)
+	} else if req.Signal != "" {
+		signal = domain.PushSignal(req.Signal)
+	}
+	if !signal.IsValid() {
+		return errJSON(c, http.StatusBadRequest, "signal must be one of: success, fail, start")
+	}
+	if req.DurationMs != nil && *req.DurationMs < 0 {
+		return errJSON(c, http.StatusBadRequest, "duration_ms must not be negative")
+	}
+
+	ctx := c.Request().Context()
+	monitor, err := h.monitorRepo.GetByPushTokenGlobal(ctx, c.Param("token"))
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to record ping")
+	}
+	if monitor == nil || !monitor.Enabled {
+		return errJSON(c, http.StatusNotFound, "push monitor not found")
+	}
+
+	agent, err := h.agentRepo.GetByIDGlobal(ctx, monitor.AgentID)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to record ping")
+	}
+	if agent == nil {
+		return errJSON(c, http.StatusNotFound, "push monitor not found")
+	}
+	ctx = repository.WithTenantID(ctx, agent.TenantID)
+
+	if err := h.svc.RecordPing(ctx, monitor, signal, req.DurationMs, req.Log); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to record ping")
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"data": map[string]string{"status": "ok"}})
+}
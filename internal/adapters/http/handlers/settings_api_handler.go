@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -23,6 +24,10 @@ type SettingsAPIHandler struct {
 	userRepo    ports.UserRepository
 	auditSvc    ports.AuditService
 	hasher      *crypto.PasswordHasher
+	onCallSvc   ports.OnCallService // optional: channels that page an on-call schedule
+
+	deferredRepo ports.DeferredNotificationRepository // optional: pending count in channel tests
+	templateRepo ports.MessageTemplateRepository      // optional: channel tests render message templates
 }
 
 // NewSettingsAPIHandler creates a new SettingsAPIHandler.
@@ -42,6 +47,24 @@ func NewSettingsAPIHandler(
 	}
 }
 
+// SetOnCallService enables email and Telegram channels that page whoever is
+// on call for a schedule.
+func (h *SettingsAPIHandler) SetOnCallService(svc ports.OnCallService) {
+	h.onCallSvc = svc
+}
+
+// SetDeferredNotificationRepo lets channel tests report how many
+// notifications a channel is holding until its delivery window opens.
+func (h *SettingsAPIHandler) SetDeferredNotificationRepo(repo ports.DeferredNotificationRepository) {
+	h.deferredRepo = repo
+}
+
+// SetMessageTemplateRepo makes channel tests render the channel's message
+// templates, as real notifications do.
+func (h *SettingsAPIHandler) SetMessageTemplateRepo(repo ports.MessageTemplateRepository) {
+	h.templateRepo = repo
+}
+
 // --- Response DTOs ---
 
 type tokenResponse struct {
@@ -63,6 +86,8 @@ type channelResponse struct {
 	Enabled   bool              `json:"enabled"`
 	CreatedAt string            `json:"created_at"`
 	UpdatedAt string            `json:"updated_at"`
+
+	DeliverySchedule *domain.DeliverySchedule `json:"delivery_schedule"`
 }
 
 func toTokenResponse(t *domain.APIToken) tokenResponse {
@@ -104,6 +129,8 @@ func toChannelResponse(ch *domain.AlertChannel) channelResponse {
 		Enabled:   ch.Enabled,
 		CreatedAt: ch.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: ch.UpdatedAt.Format(time.RFC3339),
+
+		DeliverySchedule: ch.DeliverySchedule,
 	}
 }
 
@@ -334,6 +361,8 @@ func (h *SettingsAPIHandler) CreateChannel(c echo.Context) error {
 		Type   string            `json:"type"`
 		Name   string            `json:"name"`
 		Config map[string]string `json:"config"`
+
+		DeliverySchedule *domain.DeliverySchedule `json:"delivery_schedule"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return errJSON(c, http.StatusBadRequest, "invalid request body")
@@ -354,9 +383,20 @@ func (h *SettingsAPIHandler) CreateChannel(c echo.Context) error {
 	}
 
 	channel := domain.NewAlertChannel(userID, channelType, name, req.Config)
+	channel.DeliverySchedule = req.DeliverySchedule
 	if err := channel.Validate(); err != nil {
 		return errJSON(c, http.StatusBadRequest, err.Error())
 	}
+	if channel.IsOnCall() {
+		if h.onCallSvc == nil {
+			return errJSON(c, http.StatusBadRequest, "on-call schedules are not available")
+		}
+		scheduleID, _ := channel.OnCallScheduleID()
+		schedule, err := h.onCallSvc.GetSchedule(c.Request().Context(), scheduleID)
+		if err != nil || schedule == nil || schedule.UserID != userID {
+			return errJSON(c, http.StatusBadRequest, "on-call schedule not found")
+		}
+	}
 
 	if err := h.channelRepo.Create(c.Request().Context(), channel); err != nil {
 		return errJSON(c, http.StatusInternalServerError, "failed to save alert channel")
@@ -440,8 +480,110 @@ func (h *SettingsAPIHandler) ToggleChannel(c echo.Context) error {
 	})
 }
 
+// SetDeliverySchedule sets when an alert channel delivers notifications.
+// PUT /api/v1/alert-channels/:id/delivery-schedule
+func (h *SettingsAPIHandler) SetDeliverySchedule(c echo.Context) error {
+	var schedule domain.DeliverySchedule
+	if err := c.Bind(&schedule); err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := schedule.Validate(); err != nil {
+		return errJSON(c, http.StatusBadRequest, err.Error())
+	}
+	return h.updateDeliverySchedule(c, &schedule)
+}
+
+// ClearDeliverySchedule makes an alert channel deliver at any time again.
+// DELETE /api/v1/alert-channels/:id/delivery-schedule
+func (h *SettingsAPIHandler) ClearDeliverySchedule(c echo.Context) error {
+	return h.updateDeliverySchedule(c, nil)
+}
+
+func (h *SettingsAPIHandler) updateDeliverySchedule(c echo.Context, schedule *domain.DeliverySchedule) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return errJSON(c, http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, "invalid channel ID")
+	}
+
+	channel, err := h.channelRepo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to get channel")
+	}
+	if channel == nil || channel.UserID != userID {
+		return errJSON(c, http.StatusNotFound, "channel not found")
+	}
+
+	channel.DeliverySchedule = schedule
+	if err := h.channelRepo.Update(c.Request().Context(), channel); err != nil {
+		return errJSON(c, http.StatusInternalServerError, "failed to update channel")
+	}
+
+	if h.auditSvc != nil {
+		outside := "always"
+		if schedule != nil {
+			outside = string(schedule.Outside)
+		}
+		h.auditSvc.LogEvent(c.Request().Context(), &userID, domain.AuditChannelUpdated, c.RealIP(), map[string]string{
+			"channel_id": id.String(), "name": channel.Name, "delivery_schedule": outside,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"data": toChannelResponse(channel),
+	})
+}
+
+// deliveryPreview describes what a channel's delivery schedule does with an
+// event, as reported by TestChannel.
+type deliveryPreview struct {
+	Scheduled bool    `json:"scheduled"`
+	Open      bool    `json:"open"`
+	Severity  string  `json:"severity"`
+	Decision  string  `json:"decision"`
+	DeliverAt *string `json:"deliver_at,omitempty"`
+	NextOpen  *string `json:"next_open,omitempty"`
+	Deferred  int     `json:"deferred"`
+}
+
+// previewDelivery applies the channel's delivery schedule to an event of the
+// given severity at t.
+func (h *SettingsAPIHandler) previewDelivery(ctx context.Context, channel *domain.AlertChannel, severity domain.IncidentSeverity, t time.Time) deliveryPreview {
+	preview := deliveryPreview{Open: true, Severity: string(severity), Decision: string(domain.DeliveryNow)}
+	schedule := channel.DeliverySchedule
+	if schedule == nil {
+		return preview
+	}
+
+	decision, deliverAt := schedule.Decide(t, severity == domain.IncidentSeverityCritical)
+	preview.Scheduled = true
+	preview.Open = schedule.IsOpen(t)
+	preview.Decision = string(decision)
+	if decision == domain.DeliveryDefer {
+		s := deliverAt.Format(time.RFC3339)
+		preview.DeliverAt = &s
+	}
+	if !preview.Open {
+		s := schedule.NextOpen(t).Format(time.RFC3339)
+		preview.NextOpen = &s
+	}
+	if h.deferredRepo != nil {
+		if n, err := h.deferredRepo.CountByChannelID(ctx, channel.ID); err == nil {
+			preview.Deferred = n
+		}
+	}
+	return preview
+}
+
 // TestChannel sends a test notification through the specified alert channel.
-// POST /api/v1/alert-channels/:id/test
+// The test is sent whatever the channel's delivery schedule; the response
+// reports what the schedule would do with an event of the given severity
+// (default critical) at the given time (default now).
+// POST /api/v1/alert-channels/:id/test?severity=minor&at=2026-01-01T03:00:00Z
 func (h *SettingsAPIHandler) TestChannel(c echo.Context) error {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
@@ -461,10 +603,38 @@ func (h *SettingsAPIHandler) TestChannel(c echo.Context) error {
 		return errJSON(c, http.StatusNotFound, "channel not found")
 	}
 
+	severity := domain.DefaultIncidentSeverity
+	if v := c.QueryParam("severity"); v != "" {
+		severity = domain.IncidentSeverity(v)
+		if !severity.IsValid() {
+			return errJSON(c, http.StatusBadRequest, "invalid severity")
+		}
+	}
+	at := time.Now()
+	if v := c.QueryParam("at"); v != "" {
+		if at, err = time.Parse(time.RFC3339, v); err != nil {
+			return errJSON(c, http.StatusBadRequest, "at must be an RFC 3339 time")
+		}
+	}
+	delivery := h.previewDelivery(c.Request().Context(), channel, severity, at)
+
+	if channel.IsOnCall() && h.onCallSvc != nil {
+		channel, err = h.onCallSvc.ResolveChannel(c.Request().Context(), channel)
+		if errors.Is(err, domain.ErrNobodyOnCall) {
+			return errJSON(c, http.StatusBadRequest, "nobody is on call for this channel's schedule")
+		}
+		if err != nil {
+			return errJSON(c, http.StatusBadRequest, "failed to resolve the on-call recipient")
+		}
+	}
+
 	notifier, err := notify.BuildFromChannel(channel)
 	if err != nil {
 		return errJSON(c, http.StatusBadRequest, "invalid channel configuration")
 	}
+	if h.templateRepo != nil {
+		notify.ApplyTemplates(notifier, notify.ChannelTemplates(h.templateRepo, channel))
+	}
 
 	testMonitor := &domain.Monitor{
 		ID:     uuid.New(),
@@ -476,6 +646,7 @@ func (h *SettingsAPIHandler) TestChannel(c echo.Context) error {
 		ID:        uuid.New(),
 		StartedAt: time.Now(),
 		Status:    domain.IncidentStatusOpen,
+		Severity:  severity,
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
@@ -485,7 +656,7 @@ func (h *SettingsAPIHandler) TestChannel(c echo.Context) error {
 		return errJSON(c, http.StatusBadGateway, "Test failed. Check your configuration.")
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	return c.JSON(http.StatusOK, map[string]any{"status": "ok", "delivery": delivery})
 }
 
 // --- Profile Endpoints ---
@@ -53,6 +53,15 @@ type AgentStatusEvent struct {
 	LastSeenAt *string `json:"lastSeenAt,omitempty"`
 }
 
+// MonitorStatusEvent represents a monitor status change event
+// (pending, up, down, or degraded).
+type MonitorStatusEvent struct {
+	ID      string `json:"id"`
+	AgentID string `json:"agentId"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+}
+
 // IncidentEvent represents an incident event.
 type IncidentEvent struct {
 	ID         string  `json:"id"`
@@ -123,6 +132,7 @@ func (h *SSEHandler) pollUpdates(ctx context.Context, userID uuid.UUID, events c
 
 	// Track last known state
 	lastAgentStates := make(map[string]string)
+	lastMonitorStates := make(map[uuid.UUID]string)
 	lastIncidentCount := 0
 
 	for {
@@ -156,23 +166,39 @@ func (h *SSEHandler) pollUpdates(ctx context.Context, userID uuid.UUID, events c
 				}
 			}
 
+			// Build user's monitor ID set from agents fetched above and
+			// emit monitor status changes (including degraded transitions)
+			userMonitorIDs := make(map[uuid.UUID]struct{})
+			for _, agent := range agents {
+				monitors, err := h.monitorRepo.GetByAgentID(ctx, agent.ID)
+				if err != nil {
+					continue
+				}
+				for _, m := range monitors {
+					userMonitorIDs[m.ID] = struct{}{}
+
+					currentStatus := string(m.Status)
+					if lastStatus, exists := lastMonitorStates[m.ID]; !exists || lastStatus != currentStatus {
+						lastMonitorStates[m.ID] = currentStatus
+
+						data, _ := json.Marshal(MonitorStatusEvent{
+							ID:      m.ID.String(),
+							AgentID: m.AgentID.String(),
+							Name:    m.Name,
+							Status:  currentStatus,
+						})
+						events <- fmt.Sprintf("event: monitor-status\ndata: %s\n\n", data)
+					}
+				}
+			}
+
 			// Check for new incidents (filtered by user's monitors)
 			incidents, err := h.incidentSvc.GetActiveIncidents(ctx)
 			if err == nil {
-				// Build user's monitor ID set from agents fetched above
-				userMonitorIDs := make(map[uuid.UUID]struct{})
-				for _, agent := range agents {
-					monitors, err := h.monitorRepo.GetByAgentID(ctx, agent.ID)
-					if err == nil {
-						for _, m := range monitors {
-							userMonitorIDs[m.ID] = struct{}{}
-						}
-					}
-				}
 				// Count only the user's incidents
 				userIncidentCount := 0
 				for _, inc := range incidents {
-					if _, ok := userMonitorIDs[inc.MonitorID]; ok {
+					if _, ok := userMonitorIDs[inc.MonitorID]; ok || inc.DeclaredBy(userID) {
 						userIncidentCount++
 					}
 				}
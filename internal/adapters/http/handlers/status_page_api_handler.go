@@ -328,6 +328,8 @@ type publicIncidentResponse struct {
 	ResolvedAt      *string `json:"resolved_at"`
 	DurationSeconds int     `json:"duration_seconds"`
 	Status          string  `json:"status"`
+	Kind            string  `json:"kind"`
+	Severity        string  `json:"severity"`
 	IsActive        bool    `json:"is_active"`
 }
 
@@ -462,12 +464,40 @@ func (h *StatusPageAPIHandler) PublicView(c echo.Context) error {
 					ResolvedAt:      resolvedAt,
 					DurationSeconds: int(inc.Duration().Seconds()),
 					Status:          string(inc.Status),
+					Kind:            string(inc.Kind),
+					Severity:        string(inc.Severity),
 					IsActive:        inc.IsActive(),
 				})
 			}
 		}
 	}
 
+	// Declared incidents affecting the page's monitors show under their
+	// title; their description stays private.
+	declared, err := h.incidentSvc.GetDeclaredIncidentsAffecting(ctx, monitorIDs, thirtyDaysAgo)
+	if err == nil {
+		for _, inc := range declared {
+			var resolvedAt *string
+			if inc.ResolvedAt != nil {
+				s := inc.ResolvedAt.Format(time.RFC3339)
+				resolvedAt = &s
+			}
+			if inc.IsActive() {
+				allUp = false
+			}
+			incidents = append(incidents, publicIncidentResponse{
+				MonitorName:     inc.Title,
+				StartedAt:       inc.StartedAt.Format(time.RFC3339),
+				ResolvedAt:      resolvedAt,
+				DurationSeconds: int(inc.Duration().Seconds()),
+				Status:          string(inc.Status),
+				Kind:            string(inc.Kind),
+				Severity:        string(inc.Severity),
+				IsActive:        inc.IsActive(),
+			})
+		}
+	}
+
 	// Sort incidents: active first, then by StartedAt DESC
 	sort.Slice(incidents, func(i, j int) bool {
 		if incidents[i].IsActive != incidents[j].IsActive {
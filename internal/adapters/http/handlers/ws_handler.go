@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -26,6 +27,14 @@ import (
 // maxWSConnsPerIP limits concurrent WebSocket connections per IP (H-005).
 const maxWSConnsPerIP = 10
 
+// backpressureInterval is the minimum time between backpressure signals sent
+// to one agent while its tenant's ingest queue is full.
+const backpressureInterval = 5 * time.Second
+
+// ErrCodeBackpressure is the error message code telling an agent the hub
+// dropped its heartbeat because the ingest queue is full.
+const ErrCodeBackpressure = "backpressure"
+
 // HeartbeatHook is called after each heartbeat is processed.
 // Extensions can use this for post-processing.
 type HeartbeatHook func(ctx context.Context, agentID, monitorID uuid.UUID, payload *protocol.HeartbeatPayload)
@@ -49,13 +58,40 @@ type WSHandler struct {
 	heartbeatTimer  func(time.Duration) // optional: records heartbeat processing latency
 	updateSvc       *services.UpdateService
 	discoveryHook   func(ctx context.Context, payload *protocol.DiscoveryResultPayload)
+	ingestSvc       *services.HeartbeatIngestService // optional: buffers heartbeats for batch storage
+	monitorCache    ports.MonitorCache               // optional: serves monitor reads on the heartbeat path
+
+	backpressureMu   sync.Mutex
+	lastBackpressure map[uuid.UUID]time.Time
 }
 
 // SetHeartbeatTimer sets a function to record heartbeat processing latency.
+// With an ingest service the latency runs from queueing to processing.
 func (h *WSHandler) SetHeartbeatTimer(fn func(time.Duration)) {
 	h.heartbeatTimer = fn
 }
 
+// SetIngestService routes heartbeats through the buffered ingest pipeline
+// instead of storing them on the connection goroutine.
+func (h *WSHandler) SetIngestService(svc *services.HeartbeatIngestService) {
+	h.ingestSvc = svc
+	svc.SetResultHook(h.processIngested)
+}
+
+// processIngested runs processResult for a heartbeat whose batch was stored
+// and records its latency since it was queued.
+func (h *WSHandler) processIngested(ctx context.Context, item services.IngestItem) {
+	h.processResult(ctx, item)
+	if h.heartbeatTimer != nil {
+		h.heartbeatTimer(time.Since(item.QueuedAt))
+	}
+}
+
+// SetMonitorCache sets the cache monitor reads on the heartbeat path go through.
+func (h *WSHandler) SetMonitorCache(cache ports.MonitorCache) {
+	h.monitorCache = cache
+}
+
 // SetUpdateService sets the update service for auto-update checks.
 func (h *WSHandler) SetUpdateService(svc *services.UpdateService) {
 	h.updateSvc = svc
@@ -92,6 +128,8 @@ func NewWSHandler(
 		logger:          logger,
 		allowedOrigins:  allowedOrigins,
 		connCount:       make(map[string]int),
+
+		lastBackpressure: make(map[uuid.UUID]time.Time),
 	}
 }
 
@@ -323,124 +361,7 @@ func (h *WSHandler) HandleConnection(c echo.Context) error {
 
 	// Wire heartbeat processing: agent heartbeats -> MonitorService.ProcessHeartbeat
 	client.SetHeartbeatCallback(func(agentID uuid.UUID, payload *protocol.HeartbeatPayload) {
-		hbStart := time.Now()
-		defer func() {
-			if h.heartbeatTimer != nil {
-				h.heartbeatTimer(time.Since(hbStart))
-			}
-		}()
-
-		monitorID, err := uuid.Parse(payload.MonitorID)
-		if err != nil {
-			h.logger.Warn("invalid monitor ID in heartbeat", slog.String("monitor_id", payload.MonitorID))
-			return
-		}
-
-		var heartbeat *domain.Heartbeat
-		status := domain.HeartbeatStatus(payload.Status)
-		if status.IsSuccess() {
-			heartbeat = domain.NewSuccessHeartbeat(monitorID, agentID, payload.LatencyMs)
-			// Don't record latency for non-network checks (system metrics, docker)
-			if payload.LatencyMs == 0 {
-				heartbeat.LatencyMs = nil
-			}
-			// Preserve ErrorMessage for system monitors (contains metric reading e.g. "cpu usage 23.5%")
-			if payload.ErrorMessage != "" {
-				heartbeat.ErrorMessage = &payload.ErrorMessage
-			}
-		} else {
-			heartbeat = domain.NewFailureHeartbeat(monitorID, agentID, status, payload.ErrorMessage)
-		}
-
-		// Thread TLS certificate data from agent payload
-		heartbeat.CertExpiryDays = payload.CertExpiryDays
-		if payload.CertIssuer != "" {
-			heartbeat.CertIssuer = &payload.CertIssuer
-		}
-
-		if err := h.monitorSvc.ProcessHeartbeat(ctx, heartbeat); err != nil {
-			h.logger.Error("failed to process heartbeat",
-				slog.String("monitor_id", payload.MonitorID),
-				slog.String("error", err.Error()),
-			)
-		}
-
-		// Upsert extended cert details if agent sent cert metadata
-		if h.certDetailsRepo != nil && payload.Metadata["cert_algorithm"] != "" {
-			keySize, _ := strconv.Atoi(payload.Metadata["cert_key_size"])
-			var sans []string
-			if s := payload.Metadata["cert_sans"]; s != "" {
-				sans = strings.Split(s, ",")
-			}
-			cd := &domain.CertDetails{
-				MonitorID:    monitorID,
-				ExpiryDays:   payload.CertExpiryDays,
-				Issuer:       payload.CertIssuer,
-				SANs:         sans,
-				Algorithm:    payload.Metadata["cert_algorithm"],
-				KeySize:      keySize,
-				SerialNumber: payload.Metadata["cert_serial"],
-				ChainValid:   payload.Metadata["cert_chain_valid"] == "true",
-			}
-			if err := h.certDetailsRepo.Upsert(ctx, cd); err != nil {
-				h.logger.Error("failed to upsert cert details",
-					slog.String("monitor_id", payload.MonitorID),
-					slog.String("error", err.Error()),
-				)
-			}
-		}
-
-		// Persist port scan results to monitor metadata (merge, preserving config keys)
-		if payload.Metadata["open_ports"] != "" || payload.Metadata["scanned_count"] != "" {
-			if h.monitorRepo != nil {
-				existing, err := h.monitorRepo.GetByID(ctx, monitorID)
-				if err == nil && existing != nil {
-					merged := make(map[string]string)
-					for k, v := range existing.Metadata {
-						merged[k] = v
-					}
-					for k, v := range payload.Metadata {
-						merged[k] = v
-					}
-					if err := h.monitorRepo.UpdateMetadata(ctx, monitorID, merged); err != nil {
-						h.logger.Error("failed to update port scan metadata",
-							slog.String("monitor_id", payload.MonitorID),
-							slog.String("error", err.Error()),
-						)
-					}
-				}
-			}
-		}
-
-		// Persist SNMP results to monitor metadata (merge, preserving config keys)
-		if payload.Metadata["snmp_value"] != "" || payload.Metadata["snmp_results"] != "" {
-			if h.monitorRepo != nil {
-				existing, err := h.monitorRepo.GetByID(ctx, monitorID)
-				if err == nil && existing != nil {
-					merged := make(map[string]string)
-					for k, v := range existing.Metadata {
-						merged[k] = v
-					}
-					for k, v := range payload.Metadata {
-						merged[k] = v
-					}
-					if err := h.monitorRepo.UpdateMetadata(ctx, monitorID, merged); err != nil {
-						h.logger.Error("failed to update SNMP metadata",
-							slog.String("monitor_id", payload.MonitorID),
-							slog.String("error", err.Error()),
-						)
-					}
-				}
-			}
-		}
-
-		// Invoke heartbeat hooks (port scan storage, service change detection, etc.)
-		for _, hook := range h.heartbeatHooks {
-			hook(ctx, agentID, monitorID, payload)
-		}
-
-		// Update agent last seen
-		_ = h.agentRepo.UpdateLastSeen(ctx, agentID, time.Now())
+		h.ProcessHeartbeatPayload(ctx, agentID, payload)
 	})
 
 	// Wire discovery result processing
@@ -500,9 +421,213 @@ func (h *WSHandler) HandleConnection(c echo.Context) error {
 	return nil
 }
 
-// sendTasks sends all enabled monitor tasks to the newly connected agent.
+// lookupMonitor reads a monitor on the heartbeat path, from the cache when
+// one is set. The returned monitor must not be modified.
+func (h *WSHandler) lookupMonitor(ctx context.Context, monitorID uuid.UUID) (*domain.Monitor, error) {
+	if h.monitorCache != nil {
+		return h.monitorCache.Get(ctx, monitorID)
+	}
+	return h.monitorRepo.GetByID(ctx, monitorID)
+}
+
+// failedAssertion evaluates the monitor's assertions against the HTTP result
+// reported in a successful heartbeat and returns the first failure, if any.
+func (h *WSHandler) failedAssertion(ctx context.Context, monitorID uuid.UUID, payload *protocol.HeartbeatPayload) string {
+	if h.monitorRepo == nil || payload.Metadata[domain.ResultStatusCode] == "" {
+		return ""
+	}
+	monitor, err := h.lookupMonitor(ctx, monitorID)
+	if err != nil || monitor == nil || len(monitor.Assertions) == 0 {
+		return ""
+	}
+	return monitor.FailedAssertion(payload.Metadata)
+}
+
+// ProcessHeartbeatPayload handles a check result reported by an agent. ctx
+// must carry the agent's tenant. Hub agents feed their in-process results
+// through here too.
+//
+// With an ingest service the heartbeat is queued for batch storage and
+// processed by processResult after its batch is flushed; if the queue is full
+// it is dropped and the agent told to back off. Otherwise, or once the ingest
+// service has stopped, it is stored, run
+// through MonitorService.ProcessHeartbeat and processResult, and the agent's
+// last-seen time updated, all before returning.
+func (h *WSHandler) ProcessHeartbeatPayload(ctx context.Context, agentID uuid.UUID, payload *protocol.HeartbeatPayload) {
+	monitorID, err := uuid.Parse(payload.MonitorID)
+	if err != nil {
+		h.logger.Warn("invalid monitor ID in heartbeat", slog.String("monitor_id", payload.MonitorID))
+		return
+	}
+
+	if h.ingestSvc != nil {
+		// A heartbeat for a monitor that no longer exists would fail the
+		// whole batch insert.
+		if h.monitorRepo != nil {
+			if monitor, err := h.lookupMonitor(ctx, monitorID); err == nil && monitor == nil {
+				h.logger.Debug("dropping heartbeat for unknown monitor", slog.String("monitor_id", payload.MonitorID))
+				return
+			}
+		}
+		item := services.IngestItem{AgentID: agentID, Heartbeat: h.buildHeartbeat(ctx, monitorID, agentID, payload), Payload: payload}
+		err := h.ingestSvc.Enqueue(repository.TenantIDFromContext(ctx), item)
+		if errors.Is(err, services.ErrIngestQueueFull) {
+			h.signalBackpressure(agentID)
+		}
+		// Once the ingest service has stopped nothing drains its queues, so
+		// the heartbeat is processed directly rather than lost.
+		if !errors.Is(err, services.ErrIngestStopped) {
+			return
+		}
+	}
+
+	hbStart := time.Now()
+	defer func() {
+		if h.heartbeatTimer != nil {
+			h.heartbeatTimer(time.Since(hbStart))
+		}
+	}()
+
+	heartbeat := h.buildHeartbeat(ctx, monitorID, agentID, payload)
+	if err := h.monitorSvc.ProcessHeartbeat(ctx, heartbeat); err != nil {
+		h.logger.Error("failed to process heartbeat",
+			slog.String("monitor_id", payload.MonitorID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	h.processResult(ctx, services.IngestItem{AgentID: agentID, Heartbeat: heartbeat, Payload: payload})
+
+	// Update agent last seen
+	_ = h.agentRepo.UpdateLastSeen(ctx, agentID, time.Now())
+}
+
+// buildHeartbeat turns an agent's report into a heartbeat. A successful HTTP
+// check that fails one of the monitor's assertions is recorded as down.
+func (h *WSHandler) buildHeartbeat(ctx context.Context, monitorID, agentID uuid.UUID, payload *protocol.HeartbeatPayload) *domain.Heartbeat {
+	var heartbeat *domain.Heartbeat
+	status := domain.HeartbeatStatus(payload.Status)
+	if status.IsSuccess() {
+		if msg := h.failedAssertion(ctx, monitorID, payload); msg != "" {
+			status = domain.HeartbeatStatusDown
+			payload.ErrorMessage = msg
+		}
+	}
+	if status.IsSuccess() {
+		heartbeat = domain.NewSuccessHeartbeat(monitorID, agentID, payload.LatencyMs)
+		// Don't record latency for non-network checks (system metrics, docker)
+		if payload.LatencyMs == 0 {
+			heartbeat.LatencyMs = nil
+		}
+		// Preserve ErrorMessage for system monitors (contains metric reading e.g. "cpu usage 23.5%")
+		if payload.ErrorMessage != "" {
+			heartbeat.ErrorMessage = &payload.ErrorMessage
+		}
+	} else {
+		heartbeat = domain.NewFailureHeartbeat(monitorID, agentID, status, payload.ErrorMessage)
+	}
+
+	// Thread TLS certificate data from agent payload
+	heartbeat.CertExpiryDays = payload.CertExpiryDays
+	if payload.CertIssuer != "" {
+		heartbeat.CertIssuer = &payload.CertIssuer
+	}
+	return heartbeat
+}
+
+// signalBackpressure tells an agent its heartbeats are being dropped, at most
+// once per backpressureInterval.
+func (h *WSHandler) signalBackpressure(agentID uuid.UUID) {
+	now := time.Now()
+	h.backpressureMu.Lock()
+	if now.Sub(h.lastBackpressure[agentID]) < backpressureInterval {
+		h.backpressureMu.Unlock()
+		return
+	}
+	h.lastBackpressure[agentID] = now
+	h.backpressureMu.Unlock()
+
+	h.logger.Warn("heartbeat ingest queue full, dropping heartbeats",
+		slog.String("agent_id", agentID.String()),
+	)
+	h.hub.SendToAgent(agentID, protocol.NewErrorMessage(ErrCodeBackpressure, "hub is overloaded, heartbeats are being dropped; reduce check frequency or retry later"))
+}
+
+// processResult stores what a heartbeat reported beyond its status:
+// certificate details, transaction step results and port scan and SNMP
+// metadata. It then runs the heartbeat hooks.
+func (h *WSHandler) processResult(ctx context.Context, item services.IngestItem) {
+	agentID, monitorID, payload := item.AgentID, item.Heartbeat.MonitorID, item.Payload
+
+	// Upsert extended cert details if agent sent cert metadata
+	if h.certDetailsRepo != nil && payload.Metadata["cert_algorithm"] != "" {
+		keySize, _ := strconv.Atoi(payload.Metadata["cert_key_size"])
+		var sans []string
+		if s := payload.Metadata["cert_sans"]; s != "" {
+			sans = strings.Split(s, ",")
+		}
+		cd := &domain.CertDetails{
+			MonitorID:    monitorID,
+			ExpiryDays:   payload.CertExpiryDays,
+			Issuer:       payload.CertIssuer,
+			SANs:         sans,
+			Algorithm:    payload.Metadata["cert_algorithm"],
+			KeySize:      keySize,
+			SerialNumber: payload.Metadata["cert_serial"],
+			ChainValid:   payload.Metadata["cert_chain_valid"] == "true",
+		}
+		if err := h.certDetailsRepo.Upsert(ctx, cd); err != nil {
+			h.logger.Error("failed to upsert cert details",
+				slog.String("monitor_id", payload.MonitorID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	// Keep the latest per-step results of transaction monitors for the waterfall
+	if run, ok := domain.ParseTransactionRun(payload.Metadata, item.Heartbeat.Time); ok && h.monitorRepo != nil {
+		if err := h.monitorRepo.UpdateTransactionRun(ctx, monitorID, run); err != nil {
+			h.logger.Error("failed to store transaction run",
+				slog.String("monitor_id", payload.MonitorID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	// Persist port scan results to monitor metadata (merge, preserving config keys)
+	if payload.Metadata["open_ports"] != "" || payload.Metadata["scanned_count"] != "" {
+		if h.monitorRepo != nil {
+			if err := h.monitorRepo.MergeMetadata(ctx, monitorID, payload.Metadata); err != nil {
+				h.logger.Error("failed to update port scan metadata",
+					slog.String("monitor_id", payload.MonitorID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+
+	// Persist SNMP results to monitor metadata (merge, preserving config keys)
+	if payload.Metadata["snmp_value"] != "" || payload.Metadata["snmp_results"] != "" {
+		if h.monitorRepo != nil {
+			if err := h.monitorRepo.MergeMetadata(ctx, monitorID, payload.Metadata); err != nil {
+				h.logger.Error("failed to update SNMP metadata",
+					slog.String("monitor_id", payload.MonitorID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+
+	// Invoke heartbeat hooks (port scan storage, service change detection, etc.)
+	for _, hook := range h.heartbeatHooks {
+		hook(ctx, agentID, monitorID, payload)
+	}
+}
+
+// sendTasks sends all enabled monitor tasks to the newly connected agent,
+// including multi-location monitors it probes on behalf of another agent.
 func (h *WSHandler) sendTasks(ctx context.Context, client *realtime.Client, agentID uuid.UUID) {
-	monitors, err := h.monitorSvc.GetMonitorsByAgent(ctx, agentID)
+	monitors, err := h.monitorSvc.GetEnabledMonitorsByAgent(ctx, agentID)
 	if err != nil {
 		h.logger.Error("failed to get monitors for task distribution",
 			slog.String("agent_id", agentID.String()),
@@ -522,7 +647,7 @@ func (h *WSHandler) sendTasks(ctx context.Context, client *realtime.Client, agen
 			monitor.Target,
 			monitor.IntervalSeconds,
 			monitor.TimeoutSeconds,
-			monitor.Metadata,
+			monitor.TaskMetadata(),
 		)
 		client.Send(taskMsg)
 	}
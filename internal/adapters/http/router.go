@@ -44,6 +44,16 @@ type Dependencies struct {
 	LogRecordRepo    ports.LogRecordRepository
 	CertDetailsRepo        ports.CertDetailsRepository
 	MaintenanceWindowRepo  ports.MaintenanceWindowRepository
+	DependencyRepo         ports.DependencyRepository
+	IncidentActivityService ports.IncidentActivityService
+	EscalationService      ports.EscalationService
+	OnCallService          ports.OnCallService
+	DeferredNotificationRepo ports.DeferredNotificationRepository // optional: pending count in channel tests
+	MessageTemplateRepo    ports.MessageTemplateRepository // optional: notification message templates
+	NotificationDeliveryService ports.NotificationDeliveryService // optional: notification delivery log
+	AlertGroupService      ports.AlertGroupService
+	AlertRuleService       ports.AlertRuleService
+	PushService            *services.PushService
 	Hub                    *realtime.Hub
 	Hasher           *crypto.PasswordHasher
 	AuditService     ports.AuditService
@@ -76,6 +86,16 @@ type Router struct {
 	statusPageAPIHandler *handlers.StatusPageAPIHandler
 	systemAPIHandler     *handlers.SystemAPIHandler
 	maintenanceHandler   *handlers.MaintenanceHandler
+	dependencyHandler    *handlers.DependencyHandler
+	incidentActivityHandler *handlers.IncidentActivityHandler
+	declaredIncidentHandler *handlers.DeclaredIncidentHandler
+	escalationHandler    *handlers.EscalationHandler
+	onCallHandler        *handlers.OnCallHandler
+	alertGroupHandler    *handlers.AlertGroupHandler
+	alertRuleHandler     *handlers.AlertRuleHandler
+	messageTemplateHandler *handlers.MessageTemplateHandler
+	notificationDeliveryHandler *handlers.NotificationDeliveryHandler
+	pushHandler          *handlers.PushHandler
 	discoveryHandler     *handlers.DiscoveryHandler
 	tracesHandler        *handlers.TracesHandler
 	tracesAPIHandler     *handlers.TracesAPIHandler
@@ -191,6 +211,47 @@ func NewRouter(e *echo.Echo, deps Dependencies) (*Router, error) {
 		r.maintenanceHandler = handlers.NewMaintenanceHandler(deps.MaintenanceWindowRepo, deps.AgentRepo, deps.AuditService)
 	}
 
+	if deps.DependencyRepo != nil {
+		r.dependencyHandler = handlers.NewDependencyHandler(deps.DependencyRepo, deps.MonitorRepo, deps.AgentRepo, deps.AuditService)
+	}
+
+	r.declaredIncidentHandler = handlers.NewDeclaredIncidentHandler(deps.IncidentService, deps.MonitorRepo, deps.AgentRepo, deps.UserRepo, deps.AuditService)
+
+	if deps.IncidentActivityService != nil {
+		r.incidentActivityHandler = handlers.NewIncidentActivityHandler(deps.IncidentActivityService, deps.IncidentService, deps.MonitorRepo, deps.AgentRepo, deps.AuditService)
+	}
+
+	if deps.EscalationService != nil {
+		r.escalationHandler = handlers.NewEscalationHandler(deps.EscalationService, deps.IncidentService, deps.MonitorRepo, deps.AgentRepo, deps.AuditService)
+	}
+
+	if deps.OnCallService != nil {
+		r.onCallHandler = handlers.NewOnCallHandler(deps.OnCallService, deps.UserRepo, deps.AuditService)
+		r.settingsAPIHandler.SetOnCallService(deps.OnCallService)
+	}
+	if deps.DeferredNotificationRepo != nil {
+		r.settingsAPIHandler.SetDeferredNotificationRepo(deps.DeferredNotificationRepo)
+	}
+	if deps.MessageTemplateRepo != nil {
+		r.messageTemplateHandler = handlers.NewMessageTemplateHandler(deps.MessageTemplateRepo, deps.AlertChannelRepo, deps.AuditService)
+		r.settingsAPIHandler.SetMessageTemplateRepo(deps.MessageTemplateRepo)
+	}
+	if deps.NotificationDeliveryService != nil {
+		r.notificationDeliveryHandler = handlers.NewNotificationDeliveryHandler(deps.NotificationDeliveryService, deps.UserRepo, deps.AuditService)
+	}
+
+	if deps.AlertGroupService != nil {
+		r.alertGroupHandler = handlers.NewAlertGroupHandler(deps.AlertGroupService, deps.MonitorRepo, deps.AuditService)
+	}
+
+	if deps.AlertRuleService != nil {
+		r.alertRuleHandler = handlers.NewAlertRuleHandler(deps.AlertRuleService, deps.IncidentService, deps.MonitorRepo, deps.AgentRepo, deps.AuditService)
+	}
+
+	if deps.PushService != nil {
+		r.pushHandler = handlers.NewPushHandler(deps.PushService, deps.MonitorRepo, deps.AgentRepo)
+	}
+
 	if deps.SpanRepo != nil {
 		r.tracesHandler = handlers.NewTracesHandler(deps.SpanRepo, logger)
 		r.tracesAPIHandler = handlers.NewTracesAPIHandler(deps.SpanRepo, logger)
@@ -317,6 +378,12 @@ func (r *Router) RegisterRoutes() {
 	// Public status page API (no auth required)
 	v1Public.GET("/public/status/:username/:slug", r.statusPageAPIHandler.PublicView)
 
+	// Push monitor ingest (no auth — the token in the URL identifies the monitor)
+	if r.pushHandler != nil {
+		v1Public.POST("/push/:token", r.pushHandler.Ping)
+		v1Public.POST("/push/:token/:signal", r.pushHandler.Ping)
+	}
+
 	// OTLP HTTP receivers (/v1/*). Bearer-token auth with the
 	// telemetry_ingest scope; no session cookie path. tenantMW resolves
 	// the token's user to a tenant_id so the receivers can stamp every
@@ -368,6 +435,7 @@ func (r *Router) RegisterRoutes() {
 	v1.PUT("/monitors/:id", r.apiV1Handler.UpdateMonitor)
 	v1.DELETE("/monitors/:id", r.apiV1Handler.DeleteMonitor)
 	v1.GET("/monitors/:id/certificate", r.apiV1Handler.GetMonitorCertificate)
+	v1.GET("/monitors/:id/transaction/versions", r.apiV1Handler.ListTransactionVersions)
 	v1.GET("/monitors/:id/sla", r.apiV1Handler.GetMonitorSLA)
 	v1.GET("/monitors/:id/latency-trend", r.latencyTrendHandler.GetLatencyTrend)
 	v1.GET("/certificates/expiring", r.apiV1Handler.GetExpiringCertificates)
@@ -395,9 +463,75 @@ func (r *Router) RegisterRoutes() {
 
 	// Incidents
 	v1.GET("/incidents", r.apiV1Handler.ListIncidents)
+	v1.POST("/incidents", r.declaredIncidentHandler.Declare)
+	v1.PUT("/incidents/:id/owner", r.declaredIncidentHandler.SetOwner)
 	v1.GET("/incidents/:id/investigation", r.apiV1Handler.GetIncidentInvestigation)
 	v1.POST("/incidents/:id/acknowledge", r.apiV1Handler.AcknowledgeIncident)
 	v1.POST("/incidents/:id/resolve", r.apiV1Handler.ResolveIncident)
+	v1.PATCH("/incidents/:id", r.apiV1Handler.UpdateIncident)
+	if r.incidentActivityHandler != nil {
+		v1.GET("/incidents/:id/notes", r.incidentActivityHandler.ListNotes)
+		v1.POST("/incidents/:id/notes", r.incidentActivityHandler.CreateNote)
+		v1.PUT("/incidents/:id/notes/:noteId", r.incidentActivityHandler.UpdateNote)
+		v1.DELETE("/incidents/:id/notes/:noteId", r.incidentActivityHandler.DeleteNote)
+		v1.GET("/incidents/:id/postmortem", r.incidentActivityHandler.GetPostmortem)
+		v1.PUT("/incidents/:id/postmortem", r.incidentActivityHandler.SavePostmortem)
+	}
+	if r.escalationHandler != nil {
+		v1.GET("/incidents/:id/escalation", r.escalationHandler.GetIncidentEscalation)
+
+		// Escalation policies
+		v1.GET("/escalation-policies", r.escalationHandler.List)
+		v1.POST("/escalation-policies", r.escalationHandler.Create)
+		v1.GET("/escalation-policies/:id", r.escalationHandler.Get)
+		v1.PUT("/escalation-policies/:id", r.escalationHandler.Update)
+		v1.DELETE("/escalation-policies/:id", r.escalationHandler.Delete)
+	}
+
+	// On-call schedules
+	if r.onCallHandler != nil {
+		v1.GET("/oncall-schedules", r.onCallHandler.List)
+		v1.POST("/oncall-schedules", r.onCallHandler.Create)
+		v1.GET("/oncall-schedules/:id", r.onCallHandler.Get)
+		v1.PUT("/oncall-schedules/:id", r.onCallHandler.Update)
+		v1.DELETE("/oncall-schedules/:id", r.onCallHandler.Delete)
+		v1.GET("/oncall-schedules/:id/oncall", r.onCallHandler.WhoIsOnCall)
+		v1.GET("/oncall-schedules/:id/shifts", r.onCallHandler.ListShifts)
+		v1.GET("/oncall-schedules/:id/ical", r.onCallHandler.ExportICal)
+		v1.GET("/oncall-schedules/:id/overrides", r.onCallHandler.ListOverrides)
+		v1.POST("/oncall-schedules/:id/overrides", r.onCallHandler.CreateOverride)
+		v1.DELETE("/oncall-schedules/:id/overrides/:overrideId", r.onCallHandler.DeleteOverride)
+	}
+
+	// Alert groups
+	if r.alertGroupHandler != nil {
+		v1.GET("/alert-groups", r.alertGroupHandler.List)
+		v1.GET("/alert-groups/:id", r.alertGroupHandler.Get)
+		v1.POST("/alert-groups/:id/merge", r.alertGroupHandler.Merge)
+		v1.POST("/alert-groups/:id/split", r.alertGroupHandler.Split)
+	}
+
+	// Alert routing rules
+	if r.alertRuleHandler != nil {
+		v1.GET("/alert-rules", r.alertRuleHandler.List)
+		v1.POST("/alert-rules", r.alertRuleHandler.Create)
+		v1.POST("/alert-rules/dry-run", r.alertRuleHandler.DryRun)
+		v1.GET("/alert-rules/:id", r.alertRuleHandler.Get)
+		v1.PUT("/alert-rules/:id", r.alertRuleHandler.Update)
+		v1.DELETE("/alert-rules/:id", r.alertRuleHandler.Delete)
+	}
+	if r.messageTemplateHandler != nil {
+		v1.GET("/message-templates", r.messageTemplateHandler.List)
+		v1.POST("/message-templates", r.messageTemplateHandler.Create)
+		v1.POST("/message-templates/preview", r.messageTemplateHandler.Preview)
+		v1.PUT("/message-templates/:id", r.messageTemplateHandler.Update)
+		v1.DELETE("/message-templates/:id", r.messageTemplateHandler.Delete)
+	}
+	if r.notificationDeliveryHandler != nil {
+		v1.GET("/notifications", r.notificationDeliveryHandler.List)
+		v1.GET("/notifications/:id", r.notificationDeliveryHandler.Get)
+		v1.POST("/notifications/:id/redeliver", r.notificationDeliveryHandler.Redeliver)
+	}
 
 	// Dashboard
 	v1.GET("/dashboard/stats", r.apiV1Handler.DashboardStats)
@@ -415,6 +549,8 @@ func (r *Router) RegisterRoutes() {
 	v1.DELETE("/alert-channels/:id", r.settingsAPIHandler.DeleteChannel)
 	v1.POST("/alert-channels/:id/toggle", r.settingsAPIHandler.ToggleChannel)
 	v1.POST("/alert-channels/:id/test", r.settingsAPIHandler.TestChannel)
+	v1.PUT("/alert-channels/:id/delivery-schedule", r.settingsAPIHandler.SetDeliverySchedule)
+	v1.DELETE("/alert-channels/:id/delivery-schedule", r.settingsAPIHandler.ClearDeliverySchedule)
 
 	// Settings: profile
 	v1.PATCH("/users/me", r.settingsAPIHandler.UpdateProfile)
@@ -434,6 +570,13 @@ func (r *Router) RegisterRoutes() {
 		v1.DELETE("/maintenance-windows/:id", r.maintenanceHandler.Delete)
 	}
 
+	// Monitor dependencies
+	if r.dependencyHandler != nil {
+		v1.GET("/monitors/:id/dependencies", r.dependencyHandler.List)
+		v1.POST("/monitors/:id/dependencies", r.dependencyHandler.Create)
+		v1.DELETE("/monitors/:id/dependencies/:parentId", r.dependencyHandler.Delete)
+	}
+
 	// Discovery
 	if r.discoveryHandler != nil {
 		v1.POST("/discovery", r.discoveryHandler.StartScan)
@@ -441,6 +584,11 @@ func (r *Router) RegisterRoutes() {
 		v1.GET("/discovery/:id", r.discoveryHandler.GetScan)
 	}
 
+	// Config as code
+	v1.GET("/config/export", r.apiV1Handler.ExportConfig)
+	v1.POST("/config/plan", r.apiV1Handler.PlanConfig)
+	v1.POST("/config/apply", r.apiV1Handler.ApplyConfig)
+
 	// Audit logs (user-scoped)
 	v1.GET("/audit-logs", r.systemAPIHandler.GetAuditLogs)
 
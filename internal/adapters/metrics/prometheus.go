@@ -16,7 +16,7 @@ import (
 	"github.com/sylvester-francis/watchdog/internal/core/realtime"
 )
 
-// Metrics holds OpenTelemetry meter handles for the 5 key metrics.
+// Metrics holds OpenTelemetry meter handles for the key hub metrics.
 //
 // Backwards compatibility: metric names, label names, and histogram bucket
 // boundaries match the previous Prometheus-native definitions exactly,
@@ -25,8 +25,14 @@ import (
 // bridges these meters back to the existing /metrics endpoint while an
 // optional OTLP push reader sends them to a configured OTel collector.
 type Metrics struct {
-	httpDuration     metric.Float64Histogram
-	heartbeatLatency metric.Float64Histogram
+	httpDuration       metric.Float64Histogram
+	heartbeatLatency   metric.Float64Histogram
+	heartbeatBatchSize metric.Int64Histogram
+	heartbeatsDropped  metric.Int64Counter
+
+	// Reports the heartbeats waiting in the ingest queue, set via
+	// SetHeartbeatQueueDepthFunc and read by the observer callback.
+	heartbeatQueueDepth atomic.Pointer[func() int]
 
 	// Incident counts for the watchdog_incidents_active gauge. Updated
 	// synchronously via SetIncidents and read by the ObservableGauge
@@ -53,7 +59,7 @@ func New(meter metric.Meter, hub *realtime.Hub, pool *pgxpool.Pool) (*Metrics, e
 
 	heartbeatLatency, err := meter.Float64Histogram(
 		"watchdog_heartbeat_processing_seconds",
-		metric.WithDescription("Time to process a heartbeat (validate + store + incident check)."),
+		metric.WithDescription("Time to process a heartbeat (queue + store + incident check)."),
 		metric.WithUnit("s"),
 		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1),
 	)
@@ -61,10 +67,29 @@ func New(meter metric.Meter, hub *realtime.Hub, pool *pgxpool.Pool) (*Metrics, e
 		return nil, fmt.Errorf("create heartbeat latency histogram: %w", err)
 	}
 
+	heartbeatBatchSize, err := meter.Int64Histogram(
+		"watchdog_heartbeat_batch_size",
+		metric.WithDescription("Heartbeats stored per ingest batch."),
+		metric.WithExplicitBucketBoundaries(1, 10, 50, 100, 250, 500, 1000, 2500),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create heartbeat batch size histogram: %w", err)
+	}
+
+	heartbeatsDropped, err := meter.Int64Counter(
+		"watchdog_heartbeats_dropped",
+		metric.WithDescription("Heartbeats dropped by the ingest pipeline, by reason."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create heartbeats dropped counter: %w", err)
+	}
+
 	m := &Metrics{
-		httpDuration:     httpDuration,
-		heartbeatLatency: heartbeatLatency,
-		history:          NewMetricsHistory(),
+		httpDuration:       httpDuration,
+		heartbeatLatency:   heartbeatLatency,
+		heartbeatBatchSize: heartbeatBatchSize,
+		heartbeatsDropped:  heartbeatsDropped,
+		history:            NewMetricsHistory(),
 	}
 
 	wsConnections, err := meter.Int64ObservableGauge(
@@ -91,6 +116,14 @@ func New(meter metric.Meter, hub *realtime.Hub, pool *pgxpool.Pool) (*Metrics, e
 		return nil, fmt.Errorf("create incidents active gauge: %w", err)
 	}
 
+	heartbeatQueueDepth, err := meter.Int64ObservableGauge(
+		"watchdog_heartbeat_queue_depth",
+		metric.WithDescription("Number of heartbeats waiting in the ingest queue."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create heartbeat queue depth gauge: %w", err)
+	}
+
 	openAttr := metric.WithAttributes(attribute.String("status", "open"))
 	ackAttr := metric.WithAttributes(attribute.String("status", "acknowledged"))
 
@@ -99,8 +132,11 @@ func New(meter metric.Meter, hub *realtime.Hub, pool *pgxpool.Pool) (*Metrics, e
 		o.ObserveInt64(dbPoolActive, int64(pool.Stat().AcquiredConns()))
 		o.ObserveInt64(incidentsActive, m.openIncidents.Load(), openAttr)
 		o.ObserveInt64(incidentsActive, m.acknowledgedIncidents.Load(), ackAttr)
+		if depth := m.heartbeatQueueDepth.Load(); depth != nil {
+			o.ObserveInt64(heartbeatQueueDepth, int64((*depth)()))
+		}
 		return nil
-	}, wsConnections, dbPoolActive, incidentsActive); err != nil {
+	}, wsConnections, dbPoolActive, incidentsActive, heartbeatQueueDepth); err != nil {
 		return nil, fmt.Errorf("register meter callback: %w", err)
 	}
 
@@ -148,6 +184,23 @@ func (m *Metrics) ObserveHeartbeat(d time.Duration) {
 	m.heartbeatLatency.Record(context.Background(), d.Seconds())
 }
 
+// ObserveHeartbeatBatch records the size of a stored heartbeat batch.
+func (m *Metrics) ObserveHeartbeatBatch(size int) {
+	m.heartbeatBatchSize.Record(context.Background(), int64(size))
+}
+
+// AddDroppedHeartbeats counts heartbeats the ingest pipeline dropped.
+func (m *Metrics) AddDroppedHeartbeats(reason string, n int) {
+	m.heartbeatsDropped.Add(context.Background(), int64(n), metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// SetHeartbeatQueueDepthFunc sets the function reporting the number of
+// heartbeats waiting in the ingest queue. It is read by the periodic observer
+// callback and surfaced through the watchdog_heartbeat_queue_depth gauge.
+func (m *Metrics) SetHeartbeatQueueDepthFunc(depth func() int) {
+	m.heartbeatQueueDepth.Store(&depth)
+}
+
 // SetIncidents sets the active incident counts by status. The values are
 // surfaced through the watchdog_incidents_active gauge by the periodic
 // observer callback registered in New.
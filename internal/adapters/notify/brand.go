@@ -1,5 +1,7 @@
 package notify
 
+import "strings"
+
 // BrandName is the brand label used in notification footers and subjects.
 // Override via NOTIFICATION_BRAND environment variable.
 var BrandName = "WatchDog Monitoring"
@@ -10,3 +12,12 @@ func SetBrandName(name string) {
 		BrandName = name
 	}
 }
+
+// PublicURL is the hub's externally-reachable base URL, used for links in
+// notifications and message templates. Empty leaves the links out.
+var PublicURL string
+
+// SetPublicURL sets the base URL of links in message templates.
+func SetPublicURL(url string) {
+	PublicURL = strings.TrimRight(url, "/")
+}
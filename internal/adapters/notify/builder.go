@@ -10,16 +10,30 @@ import (
 
 // ChannelNotifierFactory implements ports.NotifierFactory by building
 // notifiers from AlertChannel configurations.
-type ChannelNotifierFactory struct{}
+type ChannelNotifierFactory struct {
+	templates ports.MessageTemplateRepository
+}
 
 // NewChannelNotifierFactory creates a new ChannelNotifierFactory.
 func NewChannelNotifierFactory() *ChannelNotifierFactory {
 	return &ChannelNotifierFactory{}
 }
 
+// SetTemplateRepo makes built notifiers render the user's message templates.
+func (f *ChannelNotifierFactory) SetTemplateRepo(repo ports.MessageTemplateRepository) {
+	f.templates = repo
+}
+
 // BuildFromChannel creates a Notifier from an AlertChannel's type and config.
 func (f *ChannelNotifierFactory) BuildFromChannel(channel *domain.AlertChannel) (ports.Notifier, error) {
-	return BuildFromChannel(channel)
+	n, err := BuildFromChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+	if f.templates != nil {
+		ApplyTemplates(n, ChannelTemplates(f.templates, channel))
+	}
+	return n, nil
 }
 
 // BuildFromChannel creates a Notifier from an AlertChannel's type and config.
@@ -39,6 +53,21 @@ func BuildFromChannel(channel *domain.AlertChannel) (Notifier, error) {
 		}
 		return NewSlackNotifier(url), nil
 
+	case domain.AlertChannelTeams:
+		url := channel.Config["webhook_url"]
+		if url == "" {
+			return nil, fmt.Errorf("teams: webhook_url is required")
+		}
+		return NewTeamsNotifier(url), nil
+
+	case domain.AlertChannelMattermost:
+		url := channel.Config["webhook_url"]
+		if url == "" {
+			return nil, fmt.Errorf("mattermost: webhook_url is required")
+		}
+		channelName := channel.Config["channel"] // optional; empty posts to the webhook's channel
+		return NewMattermostNotifier(url, channelName), nil
+
 	case domain.AlertChannelWebhook:
 		url := channel.Config["url"]
 		if url == "" {
@@ -79,6 +108,22 @@ func BuildFromChannel(channel *domain.AlertChannel) (Notifier, error) {
 		}
 		return NewPagerDutyNotifier(key), nil
 
+	case domain.AlertChannelOpsgenie:
+		key := channel.Config["api_key"]
+		if key == "" {
+			return nil, fmt.Errorf("opsgenie: api_key is required")
+		}
+		region := channel.Config["region"] // optional; empty means the US API
+		return NewOpsgenieNotifier(key, region), nil
+
+	case domain.AlertChannelSplunkOnCall:
+		key := channel.Config["api_key"]
+		routingKey := channel.Config["routing_key"]
+		if key == "" || routingKey == "" {
+			return nil, fmt.Errorf("splunk_oncall: api_key and routing_key are required")
+		}
+		return NewSplunkOnCallNotifier(key, routingKey), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported channel type: %s", channel.Type)
 	}
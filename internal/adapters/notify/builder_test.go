@@ -61,3 +61,45 @@ func TestBuildFromChannel_Webhook_MissingURL(t *testing.T) {
 	_, err := notify.BuildFromChannel(ch)
 	assert.Error(t, err, "missing url should fail")
 }
+
+func TestBuildFromChannel_TeamsAndMattermost(t *testing.T) {
+	teams, err := notify.BuildFromChannel(&domain.AlertChannel{
+		Type:   domain.AlertChannelTeams,
+		Name:   "Ops",
+		Config: map[string]string{"webhook_url": "https://example.webhook.office.com/x"},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &notify.TeamsNotifier{}, teams)
+
+	mattermost, err := notify.BuildFromChannel(&domain.AlertChannel{
+		Type:   domain.AlertChannelMattermost,
+		Name:   "Ops",
+		Config: map[string]string{"webhook_url": "https://mattermost.example.com/hooks/x", "channel": "alerts"},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &notify.MattermostNotifier{}, mattermost)
+
+	_, err = notify.BuildFromChannel(&domain.AlertChannel{Type: domain.AlertChannelMattermost, Name: "Ops", Config: map[string]string{}})
+	assert.Error(t, err)
+}
+
+func TestBuildFromChannel_OpsgenieAndSplunkOnCall(t *testing.T) {
+	opsgenie, err := notify.BuildFromChannel(&domain.AlertChannel{
+		Type:   domain.AlertChannelOpsgenie,
+		Name:   "Ops",
+		Config: map[string]string{"api_key": "key", "region": "eu"},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &notify.OpsgenieNotifier{}, opsgenie)
+
+	splunk, err := notify.BuildFromChannel(&domain.AlertChannel{
+		Type:   domain.AlertChannelSplunkOnCall,
+		Name:   "Ops",
+		Config: map[string]string{"api_key": "key", "routing_key": "database"},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &notify.SplunkOnCallNotifier{}, splunk)
+
+	_, err = notify.BuildFromChannel(&domain.AlertChannel{Type: domain.AlertChannelSplunkOnCall, Name: "Ops", Config: map[string]string{"api_key": "key"}})
+	assert.Error(t, err)
+}
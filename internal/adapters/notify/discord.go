@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
 )
 
 // Discord embed colors.
@@ -16,10 +18,12 @@ const (
 	colorRed    = 0xFF0000 // Incident opened
 	colorGreen  = 0x00FF00 // Incident resolved
 	colorYellow = 0xFFAA00 // Maintenance / warning
+	colorBlue   = 0x3B82F6 // Digest
 )
 
 // DiscordNotifier sends notifications to a Discord webhook.
 type DiscordNotifier struct {
+	templated
 	webhookURL string
 	httpClient *http.Client
 }
@@ -34,10 +38,19 @@ func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
 
 // NotifyIncidentOpened sends a notification when an incident is opened.
 func (d *DiscordNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	color := colorRed
+	if incident.IsDegraded() {
+		color = colorYellow
+	}
+	if msg := d.render(ctx, incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)); msg != nil {
+		return d.sendMessage(ctx, msg, color)
+	}
+
 	fields := []discordField{
 		{Name: "Monitor", Value: monitor.Name, Inline: true},
 		{Name: "Type", Value: string(monitor.Type), Inline: true},
 		{Name: "Target", Value: monitor.Target, Inline: false},
+		{Name: "Severity", Value: string(incidentSeverity(incident)), Inline: true},
 	}
 
 	if ac := incident.AlertContext; ac != nil {
@@ -52,6 +65,13 @@ func (d *DiscordNotifier) NotifyIncidentOpened(ctx context.Context, incident *do
 		}
 	}
 
+	if group := groupSummary(incident); group != "" {
+		fields = append(fields, discordField{Name: "Grouped", Value: group, Inline: false})
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		fields = append(fields, discordField{Name: "Suppressed", Value: suppressed, Inline: false})
+	}
+
 	fields = append(fields, discordField{
 		Name:   "Started",
 		Value:  fmt.Sprintf("<t:%d:f>", incident.StartedAt.Unix()),
@@ -60,8 +80,8 @@ func (d *DiscordNotifier) NotifyIncidentOpened(ctx context.Context, incident *do
 
 	embed := discordEmbed{
 		Title:       fmt.Sprintf("🚨 Incident Opened: %s", monitor.Name),
-		Description: fmt.Sprintf("Monitor **%s** is DOWN", monitor.Name),
-		Color:       colorRed,
+		Description: fmt.Sprintf("Monitor **%s** is %s", monitor.Name, incidentState(incident)),
+		Color:       color,
 		Fields:      fields,
 		Timestamp:   incident.StartedAt.Format(time.RFC3339),
 		Footer: discordFooter{
@@ -74,6 +94,10 @@ func (d *DiscordNotifier) NotifyIncidentOpened(ctx context.Context, incident *do
 
 // NotifyIncidentResolved sends a notification when an incident is resolved.
 func (d *DiscordNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := d.render(ctx, incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)); msg != nil {
+		return d.sendMessage(ctx, msg, colorGreen)
+	}
+
 	fields := []discordField{
 		{Name: "Monitor", Value: monitor.Name, Inline: true},
 		{Name: "Type", Value: string(monitor.Type), Inline: true},
@@ -86,6 +110,13 @@ func (d *DiscordNotifier) NotifyIncidentResolved(ctx context.Context, incident *
 		}
 	}
 
+	if group := groupSummary(incident); group != "" {
+		fields = append(fields, discordField{Name: "Grouped", Value: group, Inline: false})
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		fields = append(fields, discordField{Name: "Suppressed", Value: suppressed, Inline: false})
+	}
+
 	fields = append(fields, discordField{Name: "Duration", Value: formatDuration(incident.Duration()), Inline: true})
 
 	embed := discordEmbed{
@@ -107,6 +138,11 @@ func (d *DiscordNotifier) NotifyIncidentResolved(ctx context.Context, incident *
 
 // NotifyAgentOffline sends a notification when an agent goes offline.
 func (d *DiscordNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOffline), agent, TemplateAgent{AffectedMonitors: affectedMonitors})
+	if msg := d.render(ctx, data); msg != nil {
+		return d.sendMessage(ctx, msg, colorRed)
+	}
+
 	embed := discordEmbed{
 		Title:       fmt.Sprintf("⚠️ Agent Offline: %s", agent.Name),
 		Description: fmt.Sprintf("Agent **%s** has disconnected", agent.Name),
@@ -126,6 +162,11 @@ func (d *DiscordNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.
 
 // NotifyAgentOnline sends a notification when an agent comes back online.
 func (d *DiscordNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOnline), agent, TemplateAgent{ResolvedIncidents: resolvedIncidents})
+	if msg := d.render(ctx, data); msg != nil {
+		return d.sendMessage(ctx, msg, colorGreen)
+	}
+
 	embed := discordEmbed{
 		Title:       fmt.Sprintf("✅ Agent Online: %s", agent.Name),
 		Description: fmt.Sprintf("Agent **%s** has reconnected", agent.Name),
@@ -145,6 +186,10 @@ func (d *DiscordNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.A
 
 // NotifyAgentMaintenance sends a notification when an agent enters maintenance mode.
 func (d *DiscordNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	if msg := d.render(ctx, agentTemplateData(domain.DeferredEventMaintenance, agent, TemplateAgent{Window: windowName})); msg != nil {
+		return d.sendMessage(ctx, msg, colorYellow)
+	}
+
 	embed := discordEmbed{
 		Title:       fmt.Sprintf("🔧 Maintenance Mode: %s", agent.Name),
 		Description: fmt.Sprintf("Agent **%s** entered maintenance mode", agent.Name),
@@ -162,6 +207,44 @@ func (d *DiscordNotifier) NotifyAgentMaintenance(ctx context.Context, agent *dom
 	return d.sendWebhook(ctx, embed)
 }
 
+// NotifyDigest sends a summary of batched low-severity events.
+func (d *DiscordNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if msg := d.render(ctx, digestTemplateData(digest)); msg != nil {
+		return d.sendMessage(ctx, msg, colorBlue)
+	}
+
+	var fields []discordField
+	if suppressed := suppressedSummary(digest.Suppressed); suppressed != "" {
+		fields = append(fields, discordField{Name: "Suppressed", Value: suppressed, Inline: false})
+	}
+
+	embed := discordEmbed{
+		Title:       "📋 " + digestTitle(digest),
+		Description: strings.Join(digestLines(digest), "\n"),
+		Color:       colorBlue,
+		Fields:      fields,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: discordFooter{
+			Text: BrandName,
+		},
+	}
+
+	return d.sendWebhook(ctx, embed)
+}
+
+// sendMessage sends a message rendered from a template.
+func (d *DiscordNotifier) sendMessage(ctx context.Context, msg *Message, color int) error {
+	return d.sendWebhook(ctx, discordEmbed{
+		Title:       msg.Subject,
+		Description: msg.Body,
+		Color:       color,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: discordFooter{
+			Text: BrandName,
+		},
+	})
+}
+
 // sendWebhook sends a webhook message to Discord.
 func (d *DiscordNotifier) sendWebhook(ctx context.Context, embed discordEmbed) error {
 	payload := discordWebhookPayload{
@@ -180,11 +263,15 @@ func (d *DiscordNotifier) sendWebhook(ctx context.Context, embed discordEmbed) e
 
 	req.Header.Set("Content-Type", "application/json")
 
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
 	resp, err := d.httpClient.Do(req)
 	if err != nil {
 		return &NotifierError{Notifier: "discord", Err: fmt.Errorf("send request: %w", err)}
 	}
 	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return &NotifierError{Notifier: "discord", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"net/smtp"
+	"strings"
 	"time"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
 )
 
 // EmailNotifier sends notifications via SMTP email.
 type EmailNotifier struct {
+	templated
 	host     string
 	port     int
 	username string
@@ -42,10 +45,15 @@ func NewEmailNotifier(cfg EmailConfig) *EmailNotifier {
 }
 
 // NotifyIncidentOpened sends an email when an incident is opened.
-func (e *EmailNotifier) NotifyIncidentOpened(_ context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
-	subject := fmt.Sprintf("[%s] Incident Opened: %s is DOWN", BrandName, monitor.Name)
+func (e *EmailNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := e.render(ctx, incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)); msg != nil {
+		return e.sendMessage(ctx, msg)
+	}
 
-	extra := ""
+	state := incidentState(incident)
+	subject := fmt.Sprintf("[%s] Incident Opened: %s is %s", BrandName, monitor.Name, state)
+
+	extra := fmt.Sprintf("Severity: %s\n", incidentSeverity(incident))
 	if ac := incident.AlertContext; ac != nil {
 		if ac.ErrorMessage != "" {
 			extra += fmt.Sprintf("Error: %s\n", ac.ErrorMessage)
@@ -57,23 +65,34 @@ func (e *EmailNotifier) NotifyIncidentOpened(_ context.Context, incident *domain
 			extra += fmt.Sprintf("Interval: %s\n", formatInterval(ac.Interval))
 		}
 	}
+	if group := groupSummary(incident); group != "" {
+		extra += fmt.Sprintf("Grouped: %s\n", group)
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		extra += fmt.Sprintf("Suppressed: %s\n", suppressed)
+	}
 
 	body := fmt.Sprintf(
-		"Monitor: %s\nType: %s\nTarget: %s\n%sStarted: %s\n\nMonitor %s is currently DOWN.\n\n— %s",
+		"Monitor: %s\nType: %s\nTarget: %s\n%sStarted: %s\n\nMonitor %s is currently %s.\n\n— %s",
 		monitor.Name,
 		string(monitor.Type),
 		monitor.Target,
 		extra,
 		incident.StartedAt.Format(time.RFC3339),
 		monitor.Name,
+		state,
 		BrandName,
 	)
 
-	return e.send(subject, body)
+	return e.send(ctx, subject, body)
 }
 
 // NotifyIncidentResolved sends an email when an incident is resolved.
-func (e *EmailNotifier) NotifyIncidentResolved(_ context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+func (e *EmailNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := e.render(ctx, incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)); msg != nil {
+		return e.sendMessage(ctx, msg)
+	}
+
 	subject := fmt.Sprintf("[%s] Incident Resolved: %s is UP", BrandName, monitor.Name)
 
 	extra := ""
@@ -82,6 +101,12 @@ func (e *EmailNotifier) NotifyIncidentResolved(_ context.Context, incident *doma
 			extra += fmt.Sprintf("Agent: %s\n", ac.AgentName)
 		}
 	}
+	if group := groupSummary(incident); group != "" {
+		extra += fmt.Sprintf("Grouped: %s\n", group)
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		extra += fmt.Sprintf("Suppressed: %s\n", suppressed)
+	}
 
 	body := fmt.Sprintf(
 		"Monitor: %s\nType: %s\nTarget: %s\n%sStarted: %s\nDuration: %s\n\nMonitor %s is back UP.\n\n— %s",
@@ -95,11 +120,16 @@ func (e *EmailNotifier) NotifyIncidentResolved(_ context.Context, incident *doma
 		BrandName,
 	)
 
-	return e.send(subject, body)
+	return e.send(ctx, subject, body)
 }
 
 // NotifyAgentOffline sends an email when an agent goes offline.
-func (e *EmailNotifier) NotifyAgentOffline(_ context.Context, agent *domain.Agent, affectedMonitors int) error {
+func (e *EmailNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOffline), agent, TemplateAgent{AffectedMonitors: affectedMonitors})
+	if msg := e.render(ctx, data); msg != nil {
+		return e.sendMessage(ctx, msg)
+	}
+
 	subject := fmt.Sprintf("[%s] Agent Offline: %s", BrandName, agent.Name)
 	body := fmt.Sprintf(
 		"Agent: %s\nStatus: Offline\nAffected Monitors: %d\n\nAgent %s has disconnected.\n\n— %s",
@@ -109,11 +139,16 @@ func (e *EmailNotifier) NotifyAgentOffline(_ context.Context, agent *domain.Agen
 		BrandName,
 	)
 
-	return e.send(subject, body)
+	return e.send(ctx, subject, body)
 }
 
 // NotifyAgentOnline sends an email when an agent comes back online.
-func (e *EmailNotifier) NotifyAgentOnline(_ context.Context, agent *domain.Agent, resolvedIncidents int) error {
+func (e *EmailNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOnline), agent, TemplateAgent{ResolvedIncidents: resolvedIncidents})
+	if msg := e.render(ctx, data); msg != nil {
+		return e.sendMessage(ctx, msg)
+	}
+
 	subject := fmt.Sprintf("[%s] Agent Online: %s", BrandName, agent.Name)
 	body := fmt.Sprintf(
 		"Agent: %s\nStatus: Online\nResolved Incidents: %d\n\nAgent %s has reconnected.\n\n— %s",
@@ -123,11 +158,15 @@ func (e *EmailNotifier) NotifyAgentOnline(_ context.Context, agent *domain.Agent
 		BrandName,
 	)
 
-	return e.send(subject, body)
+	return e.send(ctx, subject, body)
 }
 
 // NotifyAgentMaintenance sends an email when an agent enters maintenance mode.
-func (e *EmailNotifier) NotifyAgentMaintenance(_ context.Context, agent *domain.Agent, windowName string) error {
+func (e *EmailNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	if msg := e.render(ctx, agentTemplateData(domain.DeferredEventMaintenance, agent, TemplateAgent{Window: windowName})); msg != nil {
+		return e.sendMessage(ctx, msg)
+	}
+
 	subject := fmt.Sprintf("[%s] Maintenance Mode: %s", BrandName, agent.Name)
 	body := fmt.Sprintf(
 		"Agent: %s\nStatus: Maintenance Mode\nWindow: %s\n\nAgent %s entered maintenance mode. Alerts are suppressed until the window expires.\n\n— %s",
@@ -137,18 +176,52 @@ func (e *EmailNotifier) NotifyAgentMaintenance(_ context.Context, agent *domain.
 		BrandName,
 	)
 
-	return e.send(subject, body)
+	return e.send(ctx, subject, body)
+}
+
+// NotifyDigest sends an email summarising batched low-severity events.
+func (e *EmailNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if msg := e.render(ctx, digestTemplateData(digest)); msg != nil {
+		return e.sendMessage(ctx, msg)
+	}
+
+	subject := fmt.Sprintf("[%s] %s", BrandName, digestTitle(digest))
+
+	extra := ""
+	if suppressed := suppressedSummary(digest.Suppressed); suppressed != "" {
+		extra = fmt.Sprintf("\n%s.\n", suppressed)
+	}
+
+	body := fmt.Sprintf(
+		"Events since %s:\n\n%s\n%s\n— %s",
+		digest.Since().Format(time.RFC3339),
+		strings.Join(digestLines(digest), "\n"),
+		extra,
+		BrandName,
+	)
+
+	return e.send(ctx, subject, body)
+}
+
+// sendMessage sends a message rendered from a template; its body is HTML.
+func (e *EmailNotifier) sendMessage(ctx context.Context, msg *Message) error {
+	return e.sendMail(ctx, msg.Subject, "text/html", msg.Body)
+}
+
+func (e *EmailNotifier) send(ctx context.Context, subject, body string) error {
+	return e.sendMail(ctx, subject, "text/plain", body)
 }
 
-func (e *EmailNotifier) send(subject, body string) error {
+func (e *EmailNotifier) sendMail(ctx context.Context, subject, contentType, body string) error {
 	msg := fmt.Sprintf(
-		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
-		e.from, e.to, subject, body,
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: %s; charset=UTF-8\r\n\r\n%s",
+		e.from, e.to, subject, contentType, body,
 	)
 
 	addr := fmt.Sprintf("%s:%d", e.host, e.port)
 	auth := smtp.PlainAuth("", e.username, e.password, e.host)
 
+	ports.DeliveryReceiptFromContext(ctx).SetPayload([]byte(msg))
 	if err := smtp.SendMail(addr, auth, e.from, []string{e.to}, []byte(msg)); err != nil {
 		return &NotifierError{Notifier: "email", Err: fmt.Errorf("send mail: %w", err)}
 	}
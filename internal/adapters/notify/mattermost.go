@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// MattermostNotifier sends notifications to a Mattermost incoming webhook as
+// message attachments.
+type MattermostNotifier struct {
+	templated
+	webhookURL string
+	channel    string
+	httpClient *http.Client
+}
+
+// NewMattermostNotifier creates a new Mattermost notifier. channel overrides
+// the webhook's default channel; empty keeps it.
+func NewMattermostNotifier(webhookURL, channel string) *MattermostNotifier {
+	return &MattermostNotifier{
+		webhookURL: webhookURL,
+		channel:    channel,
+		httpClient: NewHTTPClient(10 * time.Second),
+	}
+}
+
+// NotifyIncidentOpened sends a notification when an incident is opened.
+func (m *MattermostNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	color := "#FF0000"
+	if incident.IsDegraded() {
+		color = "#FFAA00"
+	}
+	if msg := m.render(ctx, incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)); msg != nil {
+		return m.sendMessage(ctx, msg, color)
+	}
+
+	title := fmt.Sprintf("Incident Opened: %s", monitor.Name)
+	return m.send(ctx, mattermostAttachment{
+		Fallback:  title,
+		Color:     color,
+		Title:     title,
+		TitleLink: monitorLink(monitor),
+		Text:      fmt.Sprintf("Monitor **%s** is %s", monitor.Name, incidentState(incident)),
+		Fields:    incidentFields(incident, monitor),
+		Footer:    BrandName,
+		Ts:        incident.StartedAt.Unix(),
+	})
+}
+
+// NotifyIncidentResolved sends a notification when an incident is resolved.
+func (m *MattermostNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := m.render(ctx, incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)); msg != nil {
+		return m.sendMessage(ctx, msg, "#00FF00")
+	}
+
+	fields := incidentFields(incident, monitor)
+	fields = append(fields, slackField{
+		Title: "Duration",
+		Value: formatDuration(incident.Duration()),
+		Short: true,
+	})
+
+	var ts int64
+	if incident.ResolvedAt != nil {
+		ts = incident.ResolvedAt.Unix()
+	}
+
+	title := fmt.Sprintf("Incident Resolved: %s", monitor.Name)
+	return m.send(ctx, mattermostAttachment{
+		Fallback:  title,
+		Color:     "#00FF00",
+		Title:     title,
+		TitleLink: monitorLink(monitor),
+		Text:      fmt.Sprintf("Monitor **%s** is UP", monitor.Name),
+		Fields:    fields,
+		Footer:    BrandName,
+		Ts:        ts,
+	})
+}
+
+// NotifyAgentOffline sends a notification when an agent goes offline.
+func (m *MattermostNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOffline), agent, TemplateAgent{AffectedMonitors: affectedMonitors})
+	if msg := m.render(ctx, data); msg != nil {
+		return m.sendMessage(ctx, msg, "#FF0000")
+	}
+
+	title := fmt.Sprintf("Agent Offline: %s", agent.Name)
+	return m.send(ctx, mattermostAttachment{
+		Fallback: title,
+		Color:    "#FF0000",
+		Title:    title,
+		Text:     fmt.Sprintf("Agent **%s** has disconnected", agent.Name),
+		Fields: []slackField{
+			{Title: "Agent", Value: agent.Name, Short: true},
+			{Title: "Affected Monitors", Value: fmt.Sprintf("%d", affectedMonitors), Short: true},
+		},
+		Footer: BrandName,
+		Ts:     time.Now().Unix(),
+	})
+}
+
+// NotifyAgentOnline sends a notification when an agent comes back online.
+func (m *MattermostNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOnline), agent, TemplateAgent{ResolvedIncidents: resolvedIncidents})
+	if msg := m.render(ctx, data); msg != nil {
+		return m.sendMessage(ctx, msg, "#00FF00")
+	}
+
+	title := fmt.Sprintf("Agent Online: %s", agent.Name)
+	return m.send(ctx, mattermostAttachment{
+		Fallback: title,
+		Color:    "#00FF00",
+		Title:    title,
+		Text:     fmt.Sprintf("Agent **%s** has reconnected", agent.Name),
+		Fields: []slackField{
+			{Title: "Agent", Value: agent.Name, Short: true},
+			{Title: "Resolved Incidents", Value: fmt.Sprintf("%d", resolvedIncidents), Short: true},
+		},
+		Footer: BrandName,
+		Ts:     time.Now().Unix(),
+	})
+}
+
+// NotifyAgentMaintenance sends a notification when an agent enters maintenance mode.
+func (m *MattermostNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	if msg := m.render(ctx, agentTemplateData(domain.DeferredEventMaintenance, agent, TemplateAgent{Window: windowName})); msg != nil {
+		return m.sendMessage(ctx, msg, "#FFAA00")
+	}
+
+	title := fmt.Sprintf("Maintenance Mode: %s", agent.Name)
+	return m.send(ctx, mattermostAttachment{
+		Fallback: title,
+		Color:    "#FFAA00",
+		Title:    title,
+		Text:     fmt.Sprintf("Agent **%s** entered maintenance mode", agent.Name),
+		Fields: []slackField{
+			{Title: "Agent", Value: agent.Name, Short: true},
+			{Title: "Window", Value: windowName, Short: true},
+		},
+		Footer: BrandName,
+		Ts:     time.Now().Unix(),
+	})
+}
+
+// NotifyDigest sends a summary of batched low-severity events.
+func (m *MattermostNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if msg := m.render(ctx, digestTemplateData(digest)); msg != nil {
+		return m.sendMessage(ctx, msg, "#3B82F6")
+	}
+
+	var fields []slackField
+	if suppressed := suppressedSummary(digest.Suppressed); suppressed != "" {
+		fields = append(fields, slackField{Title: "Suppressed", Value: suppressed, Short: false})
+	}
+
+	title := digestTitle(digest)
+	return m.send(ctx, mattermostAttachment{
+		Fallback: title,
+		Color:    "#3B82F6",
+		Title:    title,
+		Text:     strings.Join(digestLines(digest), "\n"),
+		Fields:   fields,
+		Footer:   BrandName,
+		Ts:       time.Now().Unix(),
+	})
+}
+
+// sendMessage sends a message rendered from a template.
+func (m *MattermostNotifier) sendMessage(ctx context.Context, msg *Message, color string) error {
+	return m.send(ctx, mattermostAttachment{
+		Fallback: msg.Subject,
+		Color:    color,
+		Title:    msg.Subject,
+		Text:     msg.Body,
+		Footer:   BrandName,
+		Ts:       time.Now().Unix(),
+	})
+}
+
+func (m *MattermostNotifier) send(ctx context.Context, attachment mattermostAttachment) error {
+	payload := mattermostPayload{
+		Channel:     m.channel,
+		Attachments: []mattermostAttachment{attachment},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &NotifierError{Notifier: "mattermost", Err: fmt.Errorf("marshal payload: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return &NotifierError{Notifier: "mattermost", Err: fmt.Errorf("create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return &NotifierError{Notifier: "mattermost", Err: fmt.Errorf("send request: %w", err)}
+	}
+	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &NotifierError{Notifier: "mattermost", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+
+	return nil
+}
+
+// Mattermost webhook payload structures. Attachments follow Slack's, with a
+// fallback shown in push notifications.
+type mattermostPayload struct {
+	Channel     string                 `json:"channel,omitempty"`
+	Attachments []mattermostAttachment `json:"attachments"`
+}
+
+type mattermostAttachment struct {
+	Fallback  string       `json:"fallback"`
+	Color     string       `json:"color"`
+	Title     string       `json:"title"`
+	TitleLink string       `json:"title_link,omitempty"`
+	Text      string       `json:"text"`
+	Fields    []slackField `json:"fields,omitempty"`
+	Footer    string       `json:"footer,omitempty"`
+	Ts        int64        `json:"ts,omitempty"`
+}
@@ -0,0 +1,128 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/adapters/notify"
+)
+
+type mattermostPayload struct {
+	Channel     string `json:"channel"`
+	Attachments []struct {
+		Fallback  string `json:"fallback"`
+		Color     string `json:"color"`
+		Title     string `json:"title"`
+		TitleLink string `json:"title_link"`
+		Text      string `json:"text"`
+		Fields    []struct {
+			Title string `json:"title"`
+			Value string `json:"value"`
+		} `json:"fields"`
+		Footer string `json:"footer"`
+	} `json:"attachments"`
+}
+
+// mattermostServer records every payload posted to it.
+func mattermostServer(t *testing.T, payloads *[]mattermostPayload) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload mattermostPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.Len(t, payload.Attachments, 1)
+		*payloads = append(*payloads, payload)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMattermostNotifier_AllEvents(t *testing.T) {
+	var payloads []mattermostPayload
+	server := mattermostServer(t, &payloads)
+	notifier := notify.NewMattermostNotifier(server.URL, "")
+	ctx := context.Background()
+
+	incident := testIncident()
+	incident.AlertContext = &domain.AlertContext{ErrorMessage: "connection refused"}
+	resolved := testIncident()
+	resolvedAt := time.Now()
+	resolved.ResolvedAt = &resolvedAt
+	agent := &domain.Agent{ID: uuid.New(), Name: "edge-fra-1"}
+	digest := &domain.NotificationDigest{
+		ChannelName: "ops",
+		Entries: []*domain.NotificationLogEntry{
+			{Event: string(domain.AlertEventIncidentResolved), Subject: "blog", CreatedAt: time.Now()},
+			{Event: string(domain.AlertEventIncidentOpened), Subject: "docs", CreatedAt: time.Now()},
+		},
+		Suppressed: 4,
+	}
+
+	require.NoError(t, notifier.NotifyIncidentOpened(ctx, incident, testMonitor()))
+	require.NoError(t, notifier.NotifyIncidentResolved(ctx, resolved, testMonitor()))
+	require.NoError(t, notifier.NotifyAgentOffline(ctx, agent, 3))
+	require.NoError(t, notifier.NotifyAgentOnline(ctx, agent, 2))
+	require.NoError(t, notifier.NotifyAgentMaintenance(ctx, agent, "Patch Tuesday"))
+	require.NoError(t, notifier.NotifyDigest(ctx, digest))
+
+	titles := []string{
+		"Incident Opened: Test Monitor",
+		"Incident Resolved: Test Monitor",
+		"Agent Offline: edge-fra-1",
+		"Agent Online: edge-fra-1",
+		"Maintenance Mode: edge-fra-1",
+		"Digest: 2 low-severity events",
+	}
+	require.Len(t, payloads, len(titles))
+	for i, p := range payloads {
+		a := p.Attachments[0]
+		assert.Empty(t, p.Channel, "the webhook's channel is kept")
+		assert.Equal(t, titles[i], a.Title)
+		assert.Equal(t, titles[i], a.Fallback)
+		assert.Equal(t, notify.BrandName, a.Footer)
+	}
+
+	opened := payloads[0].Attachments[0]
+	assert.Equal(t, "#FF0000", opened.Color)
+	assert.Equal(t, "Monitor **Test Monitor** is DOWN", opened.Text, "Mattermost bolds with double asterisks")
+	assert.Empty(t, opened.TitleLink)
+	assert.Contains(t, payloads[2].Attachments[0].Text, "has disconnected")
+	assert.Equal(t, "Patch Tuesday", payloads[4].Attachments[0].Fields[1].Value)
+	assert.Contains(t, payloads[5].Attachments[0].Text, "resolved: blog")
+	assert.Equal(t, "Suppressed", payloads[5].Attachments[0].Fields[0].Title)
+}
+
+func TestMattermostNotifier_ChannelOverride(t *testing.T) {
+	var payloads []mattermostPayload
+	server := mattermostServer(t, &payloads)
+
+	notifier := notify.NewMattermostNotifier(server.URL, "alerts")
+	require.NoError(t, notifier.NotifyAgentOnline(context.Background(), &domain.Agent{ID: uuid.New(), Name: "edge"}, 0))
+	assert.Equal(t, "alerts", payloads[0].Channel)
+}
+
+func TestMattermostNotifier_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := notify.NewMattermostNotifier(server.URL, "").NotifyIncidentResolved(context.Background(), testIncident(), testMonitor())
+
+	assert.Error(t, err)
+	assert.True(t, notify.IsNotifierError(err))
+}
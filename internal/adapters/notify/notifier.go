@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
 )
@@ -15,6 +18,7 @@ type Notifier interface {
 	NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error
 	NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error
 	NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error
+	NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error
 }
 
 // MultiNotifier sends notifications to multiple notifiers.
@@ -94,6 +98,17 @@ func (m *MultiNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domai
 	return combineErrors(errs)
 }
 
+// NotifyDigest sends digest notifications to all notifiers.
+func (m *MultiNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	var errs []error
+	for _, n := range m.notifiers {
+		if err := n.NotifyDigest(ctx, digest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return combineErrors(errs)
+}
+
 // NoOpNotifier is a notifier that does nothing.
 // Useful as a default or for testing.
 type NoOpNotifier struct{}
@@ -128,6 +143,11 @@ func (n *NoOpNotifier) NotifyAgentMaintenance(_ context.Context, _ *domain.Agent
 	return nil
 }
 
+// NotifyDigest does nothing.
+func (n *NoOpNotifier) NotifyDigest(_ context.Context, _ *domain.NotificationDigest) error {
+	return nil
+}
+
 // combineErrors combines multiple errors into a single error.
 func combineErrors(errs []error) error {
 	if len(errs) == 0 {
@@ -148,6 +168,110 @@ func combineErrors(errs []error) error {
 	return combined
 }
 
+// incidentState returns the upper-case monitor state an opened incident reports.
+func incidentState(incident *domain.Incident) string {
+	if incident.IsDeclared() {
+		return "DECLARED"
+	}
+	if incident.IsDegraded() {
+		return "DEGRADED"
+	}
+	return "DOWN"
+}
+
+// incidentSeverity returns the severity an alert reports: the one captured in
+// the alert context at dispatch time, else the incident's own.
+func incidentSeverity(incident *domain.Incident) domain.IncidentSeverity {
+	if ac := incident.AlertContext; ac != nil && ac.Severity.IsValid() {
+		return ac.Severity
+	}
+	if incident.Severity.IsValid() {
+		return incident.Severity
+	}
+	return domain.DefaultIncidentSeverity
+}
+
+// groupSummary describes the alert group an alert announces, or returns ""
+// for alerts about a single incident.
+func groupSummary(incident *domain.Incident) string {
+	ac := incident.AlertContext
+	if ac == nil || ac.Group == nil {
+		return ""
+	}
+	g := ac.Group
+	text := fmt.Sprintf("%d incidents (%s): %s", g.Total, g.Title, strings.Join(g.Monitors, ", "))
+	if more := g.Total - len(g.Monitors); more > 0 {
+		text += fmt.Sprintf(" and %d more", more)
+	}
+	return text
+}
+
+// suppressedSummary reports the notifications throttled since the channel's
+// last message, or returns "" when none were.
+func suppressedSummary(suppressed int) string {
+	if suppressed <= 0 {
+		return ""
+	}
+	if suppressed == 1 {
+		return "1 notification suppressed since the last message"
+	}
+	return fmt.Sprintf("%d notifications suppressed since the last message", suppressed)
+}
+
+// incidentSuppressed returns the suppressed count an incident alert carries.
+func incidentSuppressed(incident *domain.Incident) int {
+	if incident.AlertContext == nil {
+		return 0
+	}
+	return incident.AlertContext.Suppressed
+}
+
+// monitorLink returns the URL of a monitor's page in the hub, or "" when
+// PUBLIC_URL is not set.
+func monitorLink(monitor *domain.Monitor) string {
+	if PublicURL == "" || monitor.ID == uuid.Nil {
+		return ""
+	}
+	return PublicURL + "/monitors/" + monitor.ID.String()
+}
+
+// digestTitle returns the headline of a digest message.
+func digestTitle(digest *domain.NotificationDigest) string {
+	if len(digest.Entries) == 1 {
+		return "Digest: 1 low-severity event"
+	}
+	return fmt.Sprintf("Digest: %d low-severity events", len(digest.Entries))
+}
+
+// digestLines describes a digest's events one per line, listing at most
+// domain.NotificationDigestLimit of them.
+func digestLines(digest *domain.NotificationDigest) []string {
+	lines := make([]string, 0, min(len(digest.Entries), domain.NotificationDigestLimit)+1)
+	for i, e := range digest.Entries {
+		if i == domain.NotificationDigestLimit {
+			lines = append(lines, fmt.Sprintf("and %d more", len(digest.Entries)-i))
+			break
+		}
+		line := fmt.Sprintf("%s %s: %s", e.CreatedAt.UTC().Format("15:04"), digestEvent(e.Event), e.Subject)
+		if e.Severity != "" {
+			line += fmt.Sprintf(" (%s)", e.Severity)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// digestEvent returns a readable name for a logged event.
+func digestEvent(event string) string {
+	switch domain.AlertEventKind(event) {
+	case domain.AlertEventIncidentOpened:
+		return "opened"
+	case domain.AlertEventIncidentResolved:
+		return "resolved"
+	}
+	return event
+}
+
 // formatInterval returns a human-readable check interval string.
 func formatInterval(seconds int) string {
 	if seconds < 60 {
@@ -178,3 +178,7 @@ func (s *stubNotifier) NotifyAgentOnline(_ context.Context, _ *domain.Agent, _ i
 func (s *stubNotifier) NotifyAgentMaintenance(_ context.Context, _ *domain.Agent, _ string) error {
 	return nil
 }
+
+func (s *stubNotifier) NotifyDigest(_ context.Context, _ *domain.NotificationDigest) error {
+	return nil
+}
@@ -0,0 +1,252 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Opsgenie Alert API base URLs by account region.
+const (
+	opsgenieDefaultAPIURL = "https://api.opsgenie.com"
+	opsgenieEUAPIURL      = "https://api.eu.opsgenie.com"
+)
+
+// Opsgenie Alert API field limits.
+const (
+	opsgenieMaxMessage = 130
+	opsgenieMaxTags    = 20
+	opsgenieMaxTag     = 50
+)
+
+// OpsgenieNotifier opens and closes alerts via the Opsgenie Alert API. Each
+// incident is one alert, deduplicated by an alias of the incident ID, and is
+// closed when the incident resolves.
+type OpsgenieNotifier struct {
+	apiKey     string
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewOpsgenieNotifier creates a new Opsgenie notifier for an account in
+// region "us" or "eu"; empty is "us".
+func NewOpsgenieNotifier(apiKey, region string) *OpsgenieNotifier {
+	apiURL := opsgenieDefaultAPIURL
+	if region == domain.OpsgenieRegionEU {
+		apiURL = opsgenieEUAPIURL
+	}
+	return &OpsgenieNotifier{
+		apiKey:     apiKey,
+		apiURL:     apiURL,
+		httpClient: NewHTTPClient(10 * time.Second),
+	}
+}
+
+// SetAPIURL overrides the Opsgenie API base URL (useful for testing).
+func (o *OpsgenieNotifier) SetAPIURL(url string) {
+	o.apiURL = url
+}
+
+// SetHTTPClient overrides the HTTP client (useful for testing).
+func (o *OpsgenieNotifier) SetHTTPClient(client *http.Client) {
+	o.httpClient = client
+}
+
+// NotifyIncidentOpened creates an Opsgenie alert for the incident.
+func (o *OpsgenieNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	details := map[string]string{
+		"monitor_name": monitor.Name,
+		"monitor_type": string(monitor.Type),
+		"target":       monitor.Target,
+		"severity":     string(incidentSeverity(incident)),
+	}
+
+	if ac := incident.AlertContext; ac != nil {
+		if ac.ErrorMessage != "" {
+			details["error_message"] = ac.ErrorMessage
+		}
+		if ac.AgentName != "" {
+			details["agent_name"] = ac.AgentName
+		}
+		if ac.Interval > 0 {
+			details["interval"] = formatInterval(ac.Interval)
+		}
+	}
+	if group := groupSummary(incident); group != "" {
+		details["alert_group"] = group
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		details["suppressed"] = suppressed
+	}
+	if link := monitorLink(monitor); link != "" {
+		details["monitor_url"] = link
+	}
+
+	alert := opsgenieAlert{
+		Message:     truncateRunes(fmt.Sprintf("Monitor %s is %s (%s)", monitor.Name, incidentState(incident), monitor.Target), opsgenieMaxMessage),
+		Alias:       incident.ID.String(),
+		Description: incident.Description,
+		Tags:        opsgenieTags(monitor),
+		Details:     details,
+		Entity:      monitor.Name,
+		Source:      BrandName,
+		Priority:    opsgeniePriority(incidentSeverity(incident)),
+	}
+
+	return o.create(ctx, alert)
+}
+
+// NotifyIncidentResolved closes the incident's Opsgenie alert.
+func (o *OpsgenieNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	note := fmt.Sprintf("Monitor %s is UP after %s", monitor.Name, formatDuration(incident.Duration()))
+	return o.close(ctx, incident.ID.String(), note)
+}
+
+// NotifyAgentOffline creates an Opsgenie alert when an agent goes offline.
+func (o *OpsgenieNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	alert := opsgenieAlert{
+		Message: truncateRunes(fmt.Sprintf("Agent %s is offline (%d monitors affected)", agent.Name, affectedMonitors), opsgenieMaxMessage),
+		Alias:   fmt.Sprintf("agent-offline-%s", agent.ID.String()),
+		Tags:    []string{"watchdog", "agent"},
+		Details: map[string]string{
+			"agent_name":        agent.Name,
+			"agent_id":          agent.ID.String(),
+			"affected_monitors": fmt.Sprintf("%d", affectedMonitors),
+		},
+		Entity:   agent.Name,
+		Source:   BrandName,
+		Priority: "P3",
+	}
+
+	return o.create(ctx, alert)
+}
+
+// NotifyAgentOnline closes the agent's offline alert.
+func (o *OpsgenieNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	note := fmt.Sprintf("Agent %s is back online (%d incidents resolved)", agent.Name, resolvedIncidents)
+	return o.close(ctx, fmt.Sprintf("agent-offline-%s", agent.ID.String()), note)
+}
+
+// NotifyAgentMaintenance does nothing: maintenance is planned, and an alert
+// for it would stay open with nothing to close it.
+func (o *OpsgenieNotifier) NotifyAgentMaintenance(_ context.Context, _ *domain.Agent, _ string) error {
+	return nil
+}
+
+// NotifyDigest does nothing: digests are not supported for Opsgenie, which
+// alerts on every event it receives.
+func (o *OpsgenieNotifier) NotifyDigest(_ context.Context, _ *domain.NotificationDigest) error {
+	return nil
+}
+
+// opsgeniePriority maps an incident severity onto Opsgenie's P1-P5.
+func opsgeniePriority(severity domain.IncidentSeverity) string {
+	switch severity {
+	case domain.IncidentSeverityMajor:
+		return "P2"
+	case domain.IncidentSeverityMinor:
+		return "P3"
+	case domain.IncidentSeverityInfo:
+		return "P5"
+	default:
+		return "P1"
+	}
+}
+
+// opsgenieTags tags an alert with the monitor's type and its metadata as
+// key:value pairs, within Opsgenie's limits.
+func opsgenieTags(monitor *domain.Monitor) []string {
+	keys := make([]string, 0, len(monitor.Metadata))
+	for k := range monitor.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := []string{"watchdog", truncateRunes("monitor_type:"+string(monitor.Type), opsgenieMaxTag)}
+	for _, k := range keys {
+		if len(tags) == opsgenieMaxTags {
+			break
+		}
+		tag := k
+		if v := monitor.Metadata[k]; v != "" {
+			tag += ":" + v
+		}
+		tags = append(tags, truncateRunes(tag, opsgenieMaxTag))
+	}
+	return tags
+}
+
+// truncateRunes shortens s to at most n runes.
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+func (o *OpsgenieNotifier) create(ctx context.Context, alert opsgenieAlert) error {
+	return o.post(ctx, o.apiURL+"/v2/alerts", alert)
+}
+
+func (o *OpsgenieNotifier) close(ctx context.Context, alias, note string) error {
+	endpoint := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", o.apiURL, url.PathEscape(alias))
+	return o.post(ctx, endpoint, opsgenieClose{Source: BrandName, Note: note})
+}
+
+// post sends a request to the Alert API, which processes it asynchronously
+// and answers 202 Accepted.
+func (o *OpsgenieNotifier) post(ctx context.Context, endpoint string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &NotifierError{Notifier: "opsgenie", Err: fmt.Errorf("marshal payload: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return &NotifierError{Notifier: "opsgenie", Err: fmt.Errorf("create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+o.apiKey)
+
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return &NotifierError{Notifier: "opsgenie", Err: fmt.Errorf("send request: %w", err)}
+	}
+	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &NotifierError{Notifier: "opsgenie", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+
+	return nil
+}
+
+// Opsgenie Alert API request structures.
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
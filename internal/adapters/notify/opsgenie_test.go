@@ -0,0 +1,177 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/adapters/notify"
+)
+
+// opsgenieRequest records one request to the Opsgenie Alert API.
+type opsgenieRequest struct {
+	Path          string
+	Query         string
+	Authorization string
+	Body          struct {
+		Message  string            `json:"message"`
+		Alias    string            `json:"alias"`
+		Tags     []string          `json:"tags"`
+		Details  map[string]string `json:"details"`
+		Entity   string            `json:"entity"`
+		Source   string            `json:"source"`
+		Priority string            `json:"priority"`
+		Note     string            `json:"note"`
+	}
+}
+
+func newOpsgenieServer(t *testing.T, received *[]opsgenieRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		req := opsgenieRequest{Path: r.URL.Path, Query: r.URL.RawQuery, Authorization: r.Header.Get("Authorization")}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req.Body))
+		*received = append(*received, req)
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"result":"Request will be processed","requestId":"x"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpsgenieNotifier_IncidentLifecycle(t *testing.T) {
+	var received []opsgenieRequest
+	server := newOpsgenieServer(t, &received)
+
+	notifier := notify.NewOpsgenieNotifier("test-api-key", "")
+	notifier.SetAPIURL(server.URL)
+
+	incident := domain.NewIncident(uuid.New())
+	incident.AlertContext = &domain.AlertContext{Severity: domain.IncidentSeverityMajor, ErrorMessage: "connection refused"}
+	monitor := domain.NewMonitor(uuid.New(), "API Server", domain.MonitorTypeHTTP, "https://api.example.com/health")
+	monitor.Metadata = map[string]string{"team": "payments", "env": "prod"}
+
+	require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), incident, monitor))
+	require.NoError(t, notifier.NotifyIncidentResolved(context.Background(), incident, monitor))
+	require.Len(t, received, 2)
+
+	created := received[0]
+	assert.Equal(t, "/v2/alerts", created.Path)
+	assert.Equal(t, "GenieKey test-api-key", created.Authorization)
+	assert.Equal(t, incident.ID.String(), created.Body.Alias)
+	assert.Equal(t, "P2", created.Body.Priority)
+	assert.Equal(t, notify.BrandName, created.Body.Source)
+	assert.Equal(t, "API Server", created.Body.Entity)
+	assert.Contains(t, created.Body.Message, "API Server")
+	assert.Equal(t, []string{"watchdog", "monitor_type:http", "env:prod", "team:payments"}, created.Body.Tags)
+	assert.Equal(t, "connection refused", created.Body.Details["error_message"])
+	assert.Equal(t, "https://api.example.com/health", created.Body.Details["target"])
+
+	closed := received[1]
+	assert.Equal(t, "/v2/alerts/"+incident.ID.String()+"/close", closed.Path)
+	assert.Equal(t, "identifierType=alias", closed.Query)
+	assert.Equal(t, "GenieKey test-api-key", closed.Authorization)
+	assert.Contains(t, closed.Body.Note, "API Server")
+}
+
+func TestOpsgenieNotifier_Priority(t *testing.T) {
+	tests := []struct {
+		severity domain.IncidentSeverity
+		want     string
+	}{
+		{domain.IncidentSeverityCritical, "P1"},
+		{domain.IncidentSeverityMajor, "P2"},
+		{domain.IncidentSeverityMinor, "P3"},
+		{domain.IncidentSeverityInfo, "P5"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.severity), func(t *testing.T) {
+			var received []opsgenieRequest
+			server := newOpsgenieServer(t, &received)
+
+			notifier := notify.NewOpsgenieNotifier("test-api-key", "")
+			notifier.SetAPIURL(server.URL)
+
+			incident := domain.NewIncident(uuid.New())
+			incident.AlertContext = &domain.AlertContext{Severity: tt.severity}
+
+			require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), incident, testMonitor()))
+			require.Len(t, received, 1)
+			assert.Equal(t, tt.want, received[0].Body.Priority)
+		})
+	}
+}
+
+func TestOpsgenieNotifier_TagLimits(t *testing.T) {
+	var received []opsgenieRequest
+	server := newOpsgenieServer(t, &received)
+
+	notifier := notify.NewOpsgenieNotifier("test-api-key", "")
+	notifier.SetAPIURL(server.URL)
+
+	monitor := testMonitor()
+	monitor.Metadata = map[string]string{"owner": strings.Repeat("x", 80)}
+	for i := 0; i < 30; i++ {
+		monitor.Metadata[fmt.Sprintf("key%02d", i)] = "v"
+	}
+
+	require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), testIncident(), monitor))
+	require.Len(t, received, 1)
+	assert.Len(t, received[0].Body.Tags, 20)
+	for _, tag := range received[0].Body.Tags {
+		assert.LessOrEqual(t, len(tag), 50)
+	}
+}
+
+func TestOpsgenieNotifier_AgentOfflineOnline(t *testing.T) {
+	var received []opsgenieRequest
+	server := newOpsgenieServer(t, &received)
+
+	notifier := notify.NewOpsgenieNotifier("test-api-key", "")
+	notifier.SetAPIURL(server.URL)
+
+	agent := &domain.Agent{ID: uuid.New(), Name: "edge-1"}
+	require.NoError(t, notifier.NotifyAgentOffline(context.Background(), agent, 3))
+	require.NoError(t, notifier.NotifyAgentOnline(context.Background(), agent, 2))
+	require.NoError(t, notifier.NotifyAgentMaintenance(context.Background(), agent, "patching"))
+	require.Len(t, received, 2, "maintenance opens no alert")
+
+	alias := "agent-offline-" + agent.ID.String()
+	assert.Equal(t, "/v2/alerts", received[0].Path)
+	assert.Equal(t, alias, received[0].Body.Alias)
+	assert.Equal(t, "P3", received[0].Body.Priority)
+	assert.Equal(t, "3", received[0].Body.Details["affected_monitors"])
+
+	assert.Equal(t, "/v2/alerts/"+alias+"/close", received[1].Path)
+	assert.Equal(t, "identifierType=alias", received[1].Query)
+}
+
+func TestOpsgenieNotifier_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Key format is not valid!"}`))
+	}))
+	defer server.Close()
+
+	notifier := notify.NewOpsgenieNotifier("invalid-key", "")
+	notifier.SetAPIURL(server.URL)
+
+	err := notifier.NotifyIncidentOpened(context.Background(), testIncident(), testMonitor())
+
+	require.Error(t, err)
+	assert.True(t, notify.IsNotifierError(err), "expected NotifierError, got: %T", err)
+	assert.Contains(t, err.Error(), "opsgenie")
+	assert.Contains(t, err.Error(), "401")
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
 )
 
 const pagerdutyDefaultEventsURL = "https://events.pagerduty.com/v2/enqueue"
@@ -58,15 +59,21 @@ func (p *PagerDutyNotifier) NotifyIncidentOpened(ctx context.Context, incident *
 			details["interval"] = formatInterval(ac.Interval)
 		}
 	}
+	if group := groupSummary(incident); group != "" {
+		details["alert_group"] = group
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		details["suppressed"] = suppressed
+	}
 
 	payload := pagerdutyEvent{
 		RoutingKey:  p.routingKey,
 		EventAction: "trigger",
 		DedupKey:    incident.ID.String(),
 		Payload: pagerdutyPayload{
-			Summary:       fmt.Sprintf("Monitor %s is DOWN (%s)", monitor.Name, monitor.Target),
+			Summary:       fmt.Sprintf("Monitor %s is %s (%s)", monitor.Name, incidentState(incident), monitor.Target),
 			Source:        BrandName,
-			Severity:      "critical",
+			Severity:      pagerdutySeverity(incidentSeverity(incident)),
 			Timestamp:     incident.StartedAt.Format(time.RFC3339),
 			CustomDetails: details,
 		},
@@ -87,6 +94,12 @@ func (p *PagerDutyNotifier) NotifyIncidentResolved(ctx context.Context, incident
 			details["agent_name"] = ac.AgentName
 		}
 	}
+	if group := groupSummary(incident); group != "" {
+		details["alert_group"] = group
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		details["suppressed"] = suppressed
+	}
 
 	payload := pagerdutyEvent{
 		RoutingKey:  p.routingKey,
@@ -170,6 +183,27 @@ func (p *PagerDutyNotifier) NotifyAgentMaintenance(ctx context.Context, agent *d
 	return p.send(ctx, payload)
 }
 
+// NotifyDigest does nothing: digests are not supported for PagerDuty, which
+// pages on every event it receives.
+func (p *PagerDutyNotifier) NotifyDigest(_ context.Context, _ *domain.NotificationDigest) error {
+	return nil
+}
+
+// pagerdutySeverity maps an incident severity onto the Events API v2
+// severity levels.
+func pagerdutySeverity(severity domain.IncidentSeverity) string {
+	switch severity {
+	case domain.IncidentSeverityMajor:
+		return "error"
+	case domain.IncidentSeverityMinor:
+		return "warning"
+	case domain.IncidentSeverityInfo:
+		return "info"
+	default:
+		return "critical"
+	}
+}
+
 func (p *PagerDutyNotifier) send(ctx context.Context, event pagerdutyEvent) error {
 	body, err := json.Marshal(event)
 	if err != nil {
@@ -182,11 +216,15 @@ func (p *PagerDutyNotifier) send(ctx context.Context, event pagerdutyEvent) erro
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
 		return &NotifierError{Notifier: "pagerduty", Err: fmt.Errorf("send request: %w", err)}
 	}
 	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return &NotifierError{Notifier: "pagerduty", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
@@ -73,6 +73,38 @@ func TestPagerDutyNotifier_TriggerEvent(t *testing.T) {
 	assert.Equal(t, "https://api.example.com/health", received.Payload.CustomDetails["target"])
 }
 
+func TestPagerDutyNotifier_TriggerSeverity(t *testing.T) {
+	tests := []struct {
+		severity domain.IncidentSeverity
+		want     string
+	}{
+		{domain.IncidentSeverityCritical, "critical"},
+		{domain.IncidentSeverityMajor, "error"},
+		{domain.IncidentSeverityMinor, "warning"},
+		{domain.IncidentSeverityInfo, "info"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.severity), func(t *testing.T) {
+			var received pagerdutyEventPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer server.Close()
+
+			notifier := notify.NewPagerDutyNotifier("test-routing-key")
+			notifier.SetEventsURL(server.URL)
+
+			incident := domain.NewIncident(uuid.New())
+			incident.AlertContext = &domain.AlertContext{Severity: tt.severity}
+			monitor := domain.NewMonitor(uuid.New(), "API Server", domain.MonitorTypeHTTP, "https://api.example.com/health")
+
+			require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), incident, monitor))
+			assert.Equal(t, tt.want, received.Payload.Severity)
+		})
+	}
+}
+
 func TestPagerDutyNotifier_ResolveEvent(t *testing.T) {
 	var received pagerdutyEventPayload
 
@@ -6,13 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
 )
 
 // SlackNotifier sends notifications to a Slack webhook.
 type SlackNotifier struct {
+	templated
 	webhookURL string
 	httpClient *http.Client
 }
@@ -27,12 +30,20 @@ func NewSlackNotifier(webhookURL string) *SlackNotifier {
 
 // NotifyIncidentOpened sends a notification when an incident is opened.
 func (s *SlackNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	color := "#FF0000"
+	if incident.IsDegraded() {
+		color = "#FFAA00"
+	}
+	if msg := s.render(ctx, incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)); msg != nil {
+		return s.sendMessage(ctx, msg, color)
+	}
+
 	payload := slackPayload{
 		Attachments: []slackAttachment{
 			{
-				Color:  "#FF0000",
+				Color:  color,
 				Title:  fmt.Sprintf("Incident Opened: %s", monitor.Name),
-				Text:   fmt.Sprintf("Monitor *%s* is DOWN", monitor.Name),
+				Text:   fmt.Sprintf("Monitor *%s* is %s", monitor.Name, incidentState(incident)),
 				Fields: incidentFields(incident, monitor),
 				Footer: BrandName,
 				Ts:     incident.StartedAt.Unix(),
@@ -45,6 +56,10 @@ func (s *SlackNotifier) NotifyIncidentOpened(ctx context.Context, incident *doma
 
 // NotifyIncidentResolved sends a notification when an incident is resolved.
 func (s *SlackNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := s.render(ctx, incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)); msg != nil {
+		return s.sendMessage(ctx, msg, "#00FF00")
+	}
+
 	fields := incidentFields(incident, monitor)
 	fields = append(fields, slackField{
 		Title: "Duration",
@@ -75,6 +90,11 @@ func (s *SlackNotifier) NotifyIncidentResolved(ctx context.Context, incident *do
 
 // NotifyAgentOffline sends a notification when an agent goes offline.
 func (s *SlackNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOffline), agent, TemplateAgent{AffectedMonitors: affectedMonitors})
+	if msg := s.render(ctx, data); msg != nil {
+		return s.sendMessage(ctx, msg, "#FF0000")
+	}
+
 	payload := slackPayload{
 		Attachments: []slackAttachment{
 			{
@@ -96,6 +116,11 @@ func (s *SlackNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Ag
 
 // NotifyAgentOnline sends a notification when an agent comes back online.
 func (s *SlackNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOnline), agent, TemplateAgent{ResolvedIncidents: resolvedIncidents})
+	if msg := s.render(ctx, data); msg != nil {
+		return s.sendMessage(ctx, msg, "#00FF00")
+	}
+
 	payload := slackPayload{
 		Attachments: []slackAttachment{
 			{
@@ -117,6 +142,10 @@ func (s *SlackNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Age
 
 // NotifyAgentMaintenance sends a notification when an agent enters maintenance mode.
 func (s *SlackNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	if msg := s.render(ctx, agentTemplateData(domain.DeferredEventMaintenance, agent, TemplateAgent{Window: windowName})); msg != nil {
+		return s.sendMessage(ctx, msg, "#FFAA00")
+	}
+
 	payload := slackPayload{
 		Attachments: []slackAttachment{
 			{
@@ -136,6 +165,48 @@ func (s *SlackNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domai
 	return s.send(ctx, payload)
 }
 
+// NotifyDigest sends a summary of batched low-severity events.
+func (s *SlackNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if msg := s.render(ctx, digestTemplateData(digest)); msg != nil {
+		return s.sendMessage(ctx, msg, "#3B82F6")
+	}
+
+	var fields []slackField
+	if suppressed := suppressedSummary(digest.Suppressed); suppressed != "" {
+		fields = append(fields, slackField{Title: "Suppressed", Value: suppressed, Short: false})
+	}
+
+	payload := slackPayload{
+		Attachments: []slackAttachment{
+			{
+				Color:  "#3B82F6",
+				Title:  digestTitle(digest),
+				Text:   strings.Join(digestLines(digest), "\n"),
+				Fields: fields,
+				Footer: BrandName,
+				Ts:     time.Now().Unix(),
+			},
+		},
+	}
+
+	return s.send(ctx, payload)
+}
+
+// sendMessage sends a message rendered from a template.
+func (s *SlackNotifier) sendMessage(ctx context.Context, msg *Message, color string) error {
+	return s.send(ctx, slackPayload{
+		Attachments: []slackAttachment{
+			{
+				Color:  color,
+				Title:  msg.Subject,
+				Text:   msg.Body,
+				Footer: BrandName,
+				Ts:     time.Now().Unix(),
+			},
+		},
+	})
+}
+
 func (s *SlackNotifier) send(ctx context.Context, payload slackPayload) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -148,11 +219,15 @@ func (s *SlackNotifier) send(ctx context.Context, payload slackPayload) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		return &NotifierError{Notifier: "slack", Err: fmt.Errorf("send request: %w", err)}
 	}
 	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return &NotifierError{Notifier: "slack", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
@@ -166,6 +241,7 @@ func incidentFields(incident *domain.Incident, monitor *domain.Monitor) []slackF
 		{Title: "Monitor", Value: monitor.Name, Short: true},
 		{Title: "Type", Value: string(monitor.Type), Short: true},
 		{Title: "Target", Value: monitor.Target, Short: false},
+		{Title: "Severity", Value: string(incidentSeverity(incident)), Short: true},
 	}
 
 	if ac := incident.AlertContext; ac != nil {
@@ -180,6 +256,13 @@ func incidentFields(incident *domain.Incident, monitor *domain.Monitor) []slackF
 		}
 	}
 
+	if group := groupSummary(incident); group != "" {
+		fields = append(fields, slackField{Title: "Grouped", Value: group, Short: false})
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		fields = append(fields, slackField{Title: "Suppressed", Value: suppressed, Short: false})
+	}
+
 	fields = append(fields, slackField{Title: "Started At", Value: incident.StartedAt.Format(time.RFC3339), Short: true})
 	return fields
 }
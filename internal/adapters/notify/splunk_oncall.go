@@ -0,0 +1,211 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+const splunkOnCallDefaultRESTURL = "https://alert.victorops.com/integrations/generic/20131114/alert"
+
+// Splunk On-Call message types.
+const (
+	splunkOnCallCritical = "CRITICAL"
+	splunkOnCallWarning  = "WARNING"
+	splunkOnCallInfo     = "INFO"
+	splunkOnCallRecovery = "RECOVERY"
+)
+
+// SplunkOnCallNotifier sends notifications to a Splunk On-Call (formerly
+// VictorOps) REST endpoint. Each incident is one entity_id, so its alerts
+// open and recover the same Splunk On-Call incident.
+type SplunkOnCallNotifier struct {
+	apiKey     string
+	routingKey string
+	restURL    string
+	httpClient *http.Client
+}
+
+// NewSplunkOnCallNotifier creates a new Splunk On-Call notifier for the REST
+// integration's API key and a routing key.
+func NewSplunkOnCallNotifier(apiKey, routingKey string) *SplunkOnCallNotifier {
+	return &SplunkOnCallNotifier{
+		apiKey:     apiKey,
+		routingKey: routingKey,
+		restURL:    splunkOnCallDefaultRESTURL,
+		httpClient: NewHTTPClient(10 * time.Second),
+	}
+}
+
+// SetRESTURL overrides the Splunk On-Call REST endpoint, without the API and
+// routing keys (useful for testing).
+func (s *SplunkOnCallNotifier) SetRESTURL(url string) {
+	s.restURL = url
+}
+
+// SetHTTPClient overrides the HTTP client (useful for testing).
+func (s *SplunkOnCallNotifier) SetHTTPClient(client *http.Client) {
+	s.httpClient = client
+}
+
+// NotifyIncidentOpened sends a CRITICAL, or for minor and info incidents a
+// WARNING or INFO, alert for the incident.
+func (s *SplunkOnCallNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	alert := splunkOnCallAlert{
+		"message_type":        splunkOnCallMessageType(incidentSeverity(incident)),
+		"entity_id":           incident.ID.String(),
+		"entity_display_name": fmt.Sprintf("Monitor %s is %s", monitor.Name, incidentState(incident)),
+		"state_message":       fmt.Sprintf("Monitor %s is %s (%s)", monitor.Name, incidentState(incident), monitor.Target),
+		"state_start_time":    incident.StartedAt.Unix(),
+		"monitor_name":        monitor.Name,
+		"monitor_type":        string(monitor.Type),
+		"target":              monitor.Target,
+		"severity":            string(incidentSeverity(incident)),
+	}
+
+	if ac := incident.AlertContext; ac != nil {
+		if ac.ErrorMessage != "" {
+			alert["error_message"] = ac.ErrorMessage
+		}
+		if ac.AgentName != "" {
+			alert["agent_name"] = ac.AgentName
+		}
+		if ac.Interval > 0 {
+			alert["interval"] = formatInterval(ac.Interval)
+		}
+	}
+	if group := groupSummary(incident); group != "" {
+		alert["alert_group"] = group
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		alert["suppressed"] = suppressed
+	}
+	if link := monitorLink(monitor); link != "" {
+		alert["monitor_url"] = link
+	}
+
+	return s.send(ctx, alert)
+}
+
+// NotifyIncidentResolved sends a RECOVERY alert for the incident.
+func (s *SplunkOnCallNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	alert := splunkOnCallAlert{
+		"message_type":        splunkOnCallRecovery,
+		"entity_id":           incident.ID.String(),
+		"entity_display_name": fmt.Sprintf("Monitor %s is UP", monitor.Name),
+		"state_message":       fmt.Sprintf("Monitor %s is UP (%s)", monitor.Name, monitor.Target),
+		"monitor_name":        monitor.Name,
+		"duration":            formatDuration(incident.Duration()),
+	}
+
+	return s.send(ctx, alert)
+}
+
+// NotifyAgentOffline sends a WARNING alert when an agent goes offline.
+func (s *SplunkOnCallNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	alert := splunkOnCallAlert{
+		"message_type":        splunkOnCallWarning,
+		"entity_id":           fmt.Sprintf("agent-offline-%s", agent.ID.String()),
+		"entity_display_name": fmt.Sprintf("Agent %s is offline", agent.Name),
+		"state_message":       fmt.Sprintf("Agent %s is offline (%d monitors affected)", agent.Name, affectedMonitors),
+		"agent_name":          agent.Name,
+		"agent_id":            agent.ID.String(),
+		"affected_monitors":   affectedMonitors,
+	}
+
+	return s.send(ctx, alert)
+}
+
+// NotifyAgentOnline sends a RECOVERY alert for the agent's offline alert.
+func (s *SplunkOnCallNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	alert := splunkOnCallAlert{
+		"message_type":        splunkOnCallRecovery,
+		"entity_id":           fmt.Sprintf("agent-offline-%s", agent.ID.String()),
+		"entity_display_name": fmt.Sprintf("Agent %s is back online", agent.Name),
+		"state_message":       fmt.Sprintf("Agent %s is back online (%d incidents resolved)", agent.Name, resolvedIncidents),
+		"agent_name":          agent.Name,
+		"agent_id":            agent.ID.String(),
+		"resolved_incidents":  resolvedIncidents,
+	}
+
+	return s.send(ctx, alert)
+}
+
+// NotifyAgentMaintenance sends an INFO alert, which shows in the timeline
+// without opening an incident, when an agent enters maintenance mode.
+func (s *SplunkOnCallNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	alert := splunkOnCallAlert{
+		"message_type":        splunkOnCallInfo,
+		"entity_id":           fmt.Sprintf("agent-maintenance-%s", agent.ID.String()),
+		"entity_display_name": fmt.Sprintf("Agent %s entered maintenance mode", agent.Name),
+		"state_message":       fmt.Sprintf("Agent %s entered maintenance mode (window: %s)", agent.Name, windowName),
+		"agent_name":          agent.Name,
+		"agent_id":            agent.ID.String(),
+		"window_name":         windowName,
+	}
+
+	return s.send(ctx, alert)
+}
+
+// NotifyDigest does nothing: digests are not supported for Splunk On-Call,
+// which pages on every event it receives.
+func (s *SplunkOnCallNotifier) NotifyDigest(_ context.Context, _ *domain.NotificationDigest) error {
+	return nil
+}
+
+// splunkOnCallMessageType maps an incident severity onto the message type
+// that opens it: INFO opens no incident.
+func splunkOnCallMessageType(severity domain.IncidentSeverity) string {
+	switch severity {
+	case domain.IncidentSeverityMinor:
+		return splunkOnCallWarning
+	case domain.IncidentSeverityInfo:
+		return splunkOnCallInfo
+	default:
+		return splunkOnCallCritical
+	}
+}
+
+func (s *SplunkOnCallNotifier) send(ctx context.Context, alert splunkOnCallAlert) error {
+	alert["monitoring_tool"] = BrandName
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return &NotifierError{Notifier: "splunk_oncall", Err: fmt.Errorf("marshal payload: %w", err)}
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/%s", s.restURL, url.PathEscape(s.apiKey), url.PathEscape(s.routingKey))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return &NotifierError{Notifier: "splunk_oncall", Err: fmt.Errorf("create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return &NotifierError{Notifier: "splunk_oncall", Err: fmt.Errorf("send request: %w", err)}
+	}
+	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &NotifierError{Notifier: "splunk_oncall", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+
+	return nil
+}
+
+// splunkOnCallAlert is a REST endpoint alert: the fields Splunk On-Call
+// reads, such as message_type and entity_id, and any others, which are shown
+// as alert details.
+type splunkOnCallAlert map[string]any
@@ -0,0 +1,133 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/adapters/notify"
+)
+
+// splunkOnCallRequest records one request to the Splunk On-Call REST endpoint.
+type splunkOnCallRequest struct {
+	Path string
+	Body map[string]any
+}
+
+func newSplunkOnCallServer(t *testing.T, received *[]splunkOnCallRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		req := splunkOnCallRequest{Path: r.URL.Path}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req.Body))
+		*received = append(*received, req)
+
+		_, _ = w.Write([]byte(`{"result":"success","entity_id":"x"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSplunkOnCallNotifier_IncidentLifecycle(t *testing.T) {
+	var received []splunkOnCallRequest
+	server := newSplunkOnCallServer(t, &received)
+
+	notifier := notify.NewSplunkOnCallNotifier("test-api-key", "database")
+	notifier.SetRESTURL(server.URL + "/alert")
+
+	incident := domain.NewIncident(uuid.New())
+	monitor := domain.NewMonitor(uuid.New(), "API Server", domain.MonitorTypeHTTP, "https://api.example.com/health")
+
+	require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), incident, monitor))
+	require.NoError(t, notifier.NotifyIncidentResolved(context.Background(), incident, monitor))
+	require.Len(t, received, 2)
+
+	opened := received[0]
+	assert.Equal(t, "/alert/test-api-key/database", opened.Path)
+	assert.Equal(t, "CRITICAL", opened.Body["message_type"])
+	assert.Equal(t, incident.ID.String(), opened.Body["entity_id"])
+	assert.Equal(t, notify.BrandName, opened.Body["monitoring_tool"])
+	assert.Contains(t, opened.Body["state_message"], "API Server")
+	assert.Equal(t, "https://api.example.com/health", opened.Body["target"])
+	assert.EqualValues(t, incident.StartedAt.Unix(), opened.Body["state_start_time"])
+
+	resolved := received[1]
+	assert.Equal(t, "RECOVERY", resolved.Body["message_type"])
+	assert.Equal(t, incident.ID.String(), resolved.Body["entity_id"])
+}
+
+func TestSplunkOnCallNotifier_MessageType(t *testing.T) {
+	tests := []struct {
+		severity domain.IncidentSeverity
+		want     string
+	}{
+		{domain.IncidentSeverityCritical, "CRITICAL"},
+		{domain.IncidentSeverityMajor, "CRITICAL"},
+		{domain.IncidentSeverityMinor, "WARNING"},
+		{domain.IncidentSeverityInfo, "INFO"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.severity), func(t *testing.T) {
+			var received []splunkOnCallRequest
+			server := newSplunkOnCallServer(t, &received)
+
+			notifier := notify.NewSplunkOnCallNotifier("test-api-key", "database")
+			notifier.SetRESTURL(server.URL)
+
+			incident := domain.NewIncident(uuid.New())
+			incident.AlertContext = &domain.AlertContext{Severity: tt.severity}
+
+			require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), incident, testMonitor()))
+			require.Len(t, received, 1)
+			assert.Equal(t, tt.want, received[0].Body["message_type"])
+		})
+	}
+}
+
+func TestSplunkOnCallNotifier_AgentEvents(t *testing.T) {
+	var received []splunkOnCallRequest
+	server := newSplunkOnCallServer(t, &received)
+
+	notifier := notify.NewSplunkOnCallNotifier("test-api-key", "database")
+	notifier.SetRESTURL(server.URL)
+
+	agent := &domain.Agent{ID: uuid.New(), Name: "edge-1"}
+	require.NoError(t, notifier.NotifyAgentOffline(context.Background(), agent, 3))
+	require.NoError(t, notifier.NotifyAgentOnline(context.Background(), agent, 2))
+	require.NoError(t, notifier.NotifyAgentMaintenance(context.Background(), agent, "patching"))
+	require.Len(t, received, 3)
+
+	entityID := "agent-offline-" + agent.ID.String()
+	assert.Equal(t, "WARNING", received[0].Body["message_type"])
+	assert.Equal(t, entityID, received[0].Body["entity_id"])
+	assert.Equal(t, "RECOVERY", received[1].Body["message_type"])
+	assert.Equal(t, entityID, received[1].Body["entity_id"])
+	assert.Equal(t, "INFO", received[2].Body["message_type"])
+	assert.Equal(t, "agent-maintenance-"+agent.ID.String(), received[2].Body["entity_id"])
+}
+
+func TestSplunkOnCallNotifier_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewSplunkOnCallNotifier("invalid-key", "database")
+	notifier.SetRESTURL(server.URL)
+
+	err := notifier.NotifyIncidentOpened(context.Background(), testIncident(), testMonitor())
+
+	require.Error(t, err)
+	assert.True(t, notify.IsNotifierError(err), "expected NotifierError, got: %T", err)
+	assert.Contains(t, err.Error(), "splunk_oncall")
+	assert.Contains(t, err.Error(), "404")
+}
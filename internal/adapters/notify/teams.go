@@ -0,0 +1,285 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Adaptive Card text colors.
+const (
+	teamsAttention = "attention" // Incident opened
+	teamsGood      = "good"      // Incident resolved
+	teamsWarning   = "warning"   // Maintenance / warning
+	teamsAccent    = "accent"    // Digest
+)
+
+// TeamsNotifier sends notifications to a Microsoft Teams incoming webhook,
+// either a Workflows webhook or a legacy connector, as Adaptive Cards.
+type TeamsNotifier struct {
+	templated
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewTeamsNotifier creates a new Microsoft Teams notifier.
+func NewTeamsNotifier(webhookURL string) *TeamsNotifier {
+	return &TeamsNotifier{
+		webhookURL: webhookURL,
+		httpClient: NewHTTPClient(10 * time.Second),
+	}
+}
+
+// NotifyIncidentOpened sends a notification when an incident is opened.
+func (t *TeamsNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	color := teamsAttention
+	if incident.IsDegraded() {
+		color = teamsWarning
+	}
+	if msg := t.render(ctx, incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)); msg != nil {
+		return t.sendMessage(ctx, msg, color)
+	}
+
+	card := newTeamsCard(color,
+		fmt.Sprintf("Incident Opened: %s", monitor.Name),
+		fmt.Sprintf("Monitor **%s** is %s", monitor.Name, incidentState(incident)),
+		teamsFacts(incidentFields(incident, monitor)),
+		incident.StartedAt,
+	)
+	card.addLink("View Monitor", monitorLink(monitor))
+
+	return t.send(ctx, card)
+}
+
+// NotifyIncidentResolved sends a notification when an incident is resolved.
+func (t *TeamsNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := t.render(ctx, incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)); msg != nil {
+		return t.sendMessage(ctx, msg, teamsGood)
+	}
+
+	facts := teamsFacts(incidentFields(incident, monitor))
+	facts = append(facts, teamsFact{Title: "Duration", Value: formatDuration(incident.Duration())})
+
+	resolvedAt := time.Now()
+	if incident.ResolvedAt != nil {
+		resolvedAt = *incident.ResolvedAt
+	}
+
+	card := newTeamsCard(teamsGood,
+		fmt.Sprintf("Incident Resolved: %s", monitor.Name),
+		fmt.Sprintf("Monitor **%s** is UP", monitor.Name),
+		facts,
+		resolvedAt,
+	)
+	card.addLink("View Monitor", monitorLink(monitor))
+
+	return t.send(ctx, card)
+}
+
+// NotifyAgentOffline sends a notification when an agent goes offline.
+func (t *TeamsNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOffline), agent, TemplateAgent{AffectedMonitors: affectedMonitors})
+	if msg := t.render(ctx, data); msg != nil {
+		return t.sendMessage(ctx, msg, teamsAttention)
+	}
+
+	return t.send(ctx, newTeamsCard(teamsAttention,
+		fmt.Sprintf("Agent Offline: %s", agent.Name),
+		fmt.Sprintf("Agent **%s** has disconnected", agent.Name),
+		[]teamsFact{
+			{Title: "Agent", Value: agent.Name},
+			{Title: "Affected Monitors", Value: fmt.Sprintf("%d", affectedMonitors)},
+		},
+		time.Now(),
+	))
+}
+
+// NotifyAgentOnline sends a notification when an agent comes back online.
+func (t *TeamsNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOnline), agent, TemplateAgent{ResolvedIncidents: resolvedIncidents})
+	if msg := t.render(ctx, data); msg != nil {
+		return t.sendMessage(ctx, msg, teamsGood)
+	}
+
+	return t.send(ctx, newTeamsCard(teamsGood,
+		fmt.Sprintf("Agent Online: %s", agent.Name),
+		fmt.Sprintf("Agent **%s** has reconnected", agent.Name),
+		[]teamsFact{
+			{Title: "Agent", Value: agent.Name},
+			{Title: "Resolved Incidents", Value: fmt.Sprintf("%d", resolvedIncidents)},
+		},
+		time.Now(),
+	))
+}
+
+// NotifyAgentMaintenance sends a notification when an agent enters maintenance mode.
+func (t *TeamsNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	if msg := t.render(ctx, agentTemplateData(domain.DeferredEventMaintenance, agent, TemplateAgent{Window: windowName})); msg != nil {
+		return t.sendMessage(ctx, msg, teamsWarning)
+	}
+
+	return t.send(ctx, newTeamsCard(teamsWarning,
+		fmt.Sprintf("Maintenance Mode: %s", agent.Name),
+		fmt.Sprintf("Agent **%s** entered maintenance mode", agent.Name),
+		[]teamsFact{
+			{Title: "Agent", Value: agent.Name},
+			{Title: "Window", Value: windowName},
+		},
+		time.Now(),
+	))
+}
+
+// NotifyDigest sends a summary of batched low-severity events.
+func (t *TeamsNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if msg := t.render(ctx, digestTemplateData(digest)); msg != nil {
+		return t.sendMessage(ctx, msg, teamsAccent)
+	}
+
+	var facts []teamsFact
+	if suppressed := suppressedSummary(digest.Suppressed); suppressed != "" {
+		facts = append(facts, teamsFact{Title: "Suppressed", Value: suppressed})
+	}
+
+	// A TextBlock needs a blank line between paragraphs to break lines.
+	return t.send(ctx, newTeamsCard(teamsAccent, digestTitle(digest), strings.Join(digestLines(digest), "\n\n"), facts, time.Now()))
+}
+
+// sendMessage sends a message rendered from a template.
+func (t *TeamsNotifier) sendMessage(ctx context.Context, msg *Message, color string) error {
+	return t.send(ctx, newTeamsCard(color, msg.Subject, msg.Body, nil, time.Now()))
+}
+
+func (t *TeamsNotifier) send(ctx context.Context, card teamsCard) error {
+	payload := teamsPayload{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &NotifierError{Notifier: "teams", Err: fmt.Errorf("marshal payload: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return &NotifierError{Notifier: "teams", Err: fmt.Errorf("create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return &NotifierError{Notifier: "teams", Err: fmt.Errorf("send request: %w", err)}
+	}
+	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
+
+	// Workflows webhooks answer 202 Accepted, connectors 200 OK.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &NotifierError{Notifier: "teams", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+
+	return nil
+}
+
+// newTeamsCard lays out an Adaptive Card: a colored title, the text, the
+// facts and a footer with the brand and time.
+func newTeamsCard(color, title, text string, facts []teamsFact, at time.Time) teamsCard {
+	body := []teamsElement{
+		{Type: "TextBlock", Text: title, Size: "Large", Weight: "Bolder", Color: color, Wrap: true},
+		{Type: "TextBlock", Text: text, Wrap: true},
+	}
+	if len(facts) > 0 {
+		body = append(body, teamsElement{Type: "FactSet", Facts: facts})
+	}
+	body = append(body, teamsElement{
+		Type:     "TextBlock",
+		Text:     fmt.Sprintf("%s · %s", BrandName, at.UTC().Format("2006-01-02 15:04 UTC")),
+		Size:     "Small",
+		IsSubtle: true,
+		Wrap:     true,
+	})
+
+	return teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+		MSTeams: &teamsCardOptions{Width: "Full"},
+	}
+}
+
+// addLink adds a button opening url; an empty url adds nothing.
+func (c *teamsCard) addLink(title, url string) {
+	if url == "" {
+		return
+	}
+	c.Actions = append(c.Actions, teamsAction{Type: "Action.OpenUrl", Title: title, URL: url})
+}
+
+// teamsFacts shows the fields of a Slack attachment as Adaptive Card facts.
+func teamsFacts(fields []slackField) []teamsFact {
+	facts := make([]teamsFact, 0, len(fields))
+	for _, f := range fields {
+		facts = append(facts, teamsFact{Title: f.Title, Value: f.Value})
+	}
+	return facts
+}
+
+// Teams webhook payload structures.
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string            `json:"$schema"`
+	Type    string            `json:"type"`
+	Version string            `json:"version"`
+	Body    []teamsElement    `json:"body"`
+	Actions []teamsAction     `json:"actions,omitempty"`
+	MSTeams *teamsCardOptions `json:"msteams,omitempty"`
+}
+
+// teamsElement is a TextBlock or a FactSet.
+type teamsElement struct {
+	Type     string      `json:"type"`
+	Text     string      `json:"text,omitempty"`
+	Size     string      `json:"size,omitempty"`
+	Weight   string      `json:"weight,omitempty"`
+	Color    string      `json:"color,omitempty"`
+	IsSubtle bool        `json:"isSubtle,omitempty"`
+	Wrap     bool        `json:"wrap,omitempty"`
+	Facts    []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type teamsCardOptions struct {
+	Width string `json:"width"`
+}
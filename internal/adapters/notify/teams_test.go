@@ -0,0 +1,151 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/adapters/notify"
+)
+
+type teamsCard struct {
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	Body    []struct {
+		Type  string `json:"type"`
+		Text  string `json:"text"`
+		Color string `json:"color"`
+		Facts []struct {
+			Title string `json:"title"`
+			Value string `json:"value"`
+		} `json:"facts"`
+	} `json:"body"`
+	Actions []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"actions"`
+}
+
+// teamsServer records the Adaptive Card of every message posted to it.
+func teamsServer(t *testing.T, cards *[]teamsCard) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload struct {
+			Type        string `json:"type"`
+			Attachments []struct {
+				ContentType string    `json:"contentType"`
+				Content     teamsCard `json:"content"`
+			} `json:"attachments"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "message", payload.Type)
+		require.Len(t, payload.Attachments, 1)
+		assert.Equal(t, "application/vnd.microsoft.card.adaptive", payload.Attachments[0].ContentType)
+		*cards = append(*cards, payload.Attachments[0].Content)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTeamsNotifier_AllEvents(t *testing.T) {
+	var cards []teamsCard
+	server := teamsServer(t, &cards)
+	notifier := notify.NewTeamsNotifier(server.URL)
+	ctx := context.Background()
+
+	incident := testIncident()
+	incident.AlertContext = &domain.AlertContext{ErrorMessage: "connection refused", AgentName: "edge"}
+	resolved := testIncident()
+	resolvedAt := time.Now()
+	resolved.ResolvedAt = &resolvedAt
+	agent := &domain.Agent{ID: uuid.New(), Name: "edge-fra-1"}
+	digest := &domain.NotificationDigest{
+		ChannelName: "ops",
+		Entries: []*domain.NotificationLogEntry{
+			{Event: string(domain.AlertEventIncidentOpened), Subject: "blog", Severity: domain.IncidentSeverityMinor, CreatedAt: time.Now()},
+		},
+	}
+
+	require.NoError(t, notifier.NotifyIncidentOpened(ctx, incident, testMonitor()))
+	require.NoError(t, notifier.NotifyIncidentResolved(ctx, resolved, testMonitor()))
+	require.NoError(t, notifier.NotifyAgentOffline(ctx, agent, 3))
+	require.NoError(t, notifier.NotifyAgentOnline(ctx, agent, 2))
+	require.NoError(t, notifier.NotifyAgentMaintenance(ctx, agent, "Patch Tuesday"))
+	require.NoError(t, notifier.NotifyDigest(ctx, digest))
+
+	titles := []string{
+		"Incident Opened: Test Monitor",
+		"Incident Resolved: Test Monitor",
+		"Agent Offline: edge-fra-1",
+		"Agent Online: edge-fra-1",
+		"Maintenance Mode: edge-fra-1",
+		"Digest: 1 low-severity event",
+	}
+	colors := []string{"attention", "good", "attention", "good", "warning", "accent"}
+	require.Len(t, cards, len(titles))
+	for i, card := range cards {
+		assert.Equal(t, "AdaptiveCard", card.Type)
+		assert.Equal(t, titles[i], card.Body[0].Text)
+		assert.Equal(t, colors[i], card.Body[0].Color, titles[i])
+	}
+
+	facts := map[string]string{}
+	for _, f := range cards[0].Body[2].Facts {
+		facts[f.Title] = f.Value
+	}
+	assert.Equal(t, "connection refused", facts["Error"])
+	assert.Equal(t, "edge", facts["Agent"])
+	assert.Contains(t, cards[1].Body[2].Facts[len(cards[1].Body[2].Facts)-1].Title, "Duration")
+	assert.Contains(t, cards[5].Body[1].Text, "blog (minor)")
+}
+
+func TestTeamsNotifier_MonitorLink(t *testing.T) {
+	notify.SetPublicURL("https://watchdog.example.com/")
+	defer notify.SetPublicURL("")
+
+	var cards []teamsCard
+	server := teamsServer(t, &cards)
+	monitor := testMonitor()
+
+	require.NoError(t, notify.NewTeamsNotifier(server.URL).NotifyIncidentOpened(context.Background(), testIncident(), monitor))
+	require.Len(t, cards[0].Actions, 1)
+	assert.Equal(t, "Action.OpenUrl", cards[0].Actions[0].Type)
+	assert.Equal(t, "https://watchdog.example.com/monitors/"+monitor.ID.String(), cards[0].Actions[0].URL)
+}
+
+func TestTeamsNotifier_RendersTemplate(t *testing.T) {
+	var cards []teamsCard
+	server := teamsServer(t, &cards)
+
+	notifier := notify.NewTeamsNotifier(server.URL)
+	notifier.SetTemplates(staticTemplates(messageTemplate(domain.AlertChannelTeams, "", "Heads up: {{.Title}}", "{{.Agent.Name}} went away")))
+
+	require.NoError(t, notifier.NotifyAgentOffline(context.Background(), &domain.Agent{ID: uuid.New(), Name: "edge"}, 1))
+	assert.Equal(t, "Heads up: Agent Offline: edge", cards[0].Body[0].Text)
+	assert.Equal(t, "edge went away", cards[0].Body[1].Text)
+}
+
+func TestTeamsNotifier_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := notify.NewTeamsNotifier(server.URL).NotifyIncidentOpened(context.Background(), testIncident(), testMonitor())
+
+	assert.Error(t, err)
+	assert.True(t, notify.IsNotifierError(err))
+}
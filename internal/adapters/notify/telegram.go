@@ -6,15 +6,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
 )
 
 const telegramDefaultBaseURL = "https://api.telegram.org"
 
 // TelegramNotifier sends notifications via Telegram Bot API.
 type TelegramNotifier struct {
+	templated
 	botToken   string
 	chatID     string
 	baseURL    string
@@ -43,7 +46,11 @@ func (t *TelegramNotifier) SetHTTPClient(client *http.Client) {
 
 // NotifyIncidentOpened sends a Telegram message when an incident is opened.
 func (t *TelegramNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
-	extra := ""
+	if msg := t.render(ctx, incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)); msg != nil {
+		return t.send(ctx, msg.Body)
+	}
+
+	extra := fmt.Sprintf("*Severity:* %s\n", incidentSeverity(incident))
 	if ac := incident.AlertContext; ac != nil {
 		if ac.ErrorMessage != "" {
 			extra += fmt.Sprintf("*Error:* %s\n", escapeMarkdown(ac.ErrorMessage))
@@ -55,9 +62,21 @@ func (t *TelegramNotifier) NotifyIncidentOpened(ctx context.Context, incident *d
 			extra += fmt.Sprintf("*Interval:* %s\n", formatInterval(ac.Interval))
 		}
 	}
+	if group := groupSummary(incident); group != "" {
+		extra += fmt.Sprintf("*Grouped:* %s\n", escapeMarkdown(group))
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		extra += fmt.Sprintf("*Suppressed:* %s\n", suppressed)
+	}
+
+	icon := "🔴"
+	if incident.IsDegraded() {
+		icon = "🟠"
+	}
 
 	text := fmt.Sprintf(
-		"🔴 *Incident Opened*\n\n*Monitor:* %s\n*Type:* %s\n*Target:* `%s`\n%s*Started:* %s\n\n— %s",
+		"%s *Incident Opened*\n\n*Monitor:* %s\n*Type:* %s\n*Target:* `%s`\n%s*Started:* %s\n\n— %s",
+		icon,
 		escapeMarkdown(monitor.Name),
 		string(monitor.Type),
 		monitor.Target,
@@ -71,12 +90,22 @@ func (t *TelegramNotifier) NotifyIncidentOpened(ctx context.Context, incident *d
 
 // NotifyIncidentResolved sends a Telegram message when an incident is resolved.
 func (t *TelegramNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := t.render(ctx, incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)); msg != nil {
+		return t.send(ctx, msg.Body)
+	}
+
 	extra := ""
 	if ac := incident.AlertContext; ac != nil {
 		if ac.AgentName != "" {
 			extra += fmt.Sprintf("*Agent:* %s\n", escapeMarkdown(ac.AgentName))
 		}
 	}
+	if group := groupSummary(incident); group != "" {
+		extra += fmt.Sprintf("*Grouped:* %s\n", escapeMarkdown(group))
+	}
+	if suppressed := suppressedSummary(incidentSuppressed(incident)); suppressed != "" {
+		extra += fmt.Sprintf("*Suppressed:* %s\n", suppressed)
+	}
 
 	text := fmt.Sprintf(
 		"🟢 *Incident Resolved*\n\n*Monitor:* %s\n*Type:* %s\n*Target:* `%s`\n%s*Duration:* %s\n\n— %s",
@@ -93,6 +122,11 @@ func (t *TelegramNotifier) NotifyIncidentResolved(ctx context.Context, incident
 
 // NotifyAgentOffline sends a Telegram message when an agent goes offline.
 func (t *TelegramNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOffline), agent, TemplateAgent{AffectedMonitors: affectedMonitors})
+	if msg := t.render(ctx, data); msg != nil {
+		return t.send(ctx, msg.Body)
+	}
+
 	text := fmt.Sprintf(
 		"🔴 *Agent Offline*\n\n*Agent:* %s\n*Affected Monitors:* %d\n\nAgent %s has disconnected.\n\n— %s",
 		escapeMarkdown(agent.Name),
@@ -106,6 +140,11 @@ func (t *TelegramNotifier) NotifyAgentOffline(ctx context.Context, agent *domain
 
 // NotifyAgentOnline sends a Telegram message when an agent comes back online.
 func (t *TelegramNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOnline), agent, TemplateAgent{ResolvedIncidents: resolvedIncidents})
+	if msg := t.render(ctx, data); msg != nil {
+		return t.send(ctx, msg.Body)
+	}
+
 	text := fmt.Sprintf(
 		"🟢 *Agent Online*\n\n*Agent:* %s\n*Resolved Incidents:* %d\n\nAgent %s has reconnected.\n\n— %s",
 		escapeMarkdown(agent.Name),
@@ -119,6 +158,10 @@ func (t *TelegramNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.
 
 // NotifyAgentMaintenance sends a Telegram message when an agent enters maintenance mode.
 func (t *TelegramNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	if msg := t.render(ctx, agentTemplateData(domain.DeferredEventMaintenance, agent, TemplateAgent{Window: windowName})); msg != nil {
+		return t.send(ctx, msg.Body)
+	}
+
 	text := fmt.Sprintf(
 		"🔧 *Maintenance Mode*\n\n*Agent:* %s\n*Window:* %s\n\nAgent %s entered maintenance mode. Alerts are suppressed.\n\n— %s",
 		escapeMarkdown(agent.Name),
@@ -130,6 +173,33 @@ func (t *TelegramNotifier) NotifyAgentMaintenance(ctx context.Context, agent *do
 	return t.send(ctx, text)
 }
 
+// NotifyDigest sends a Telegram message summarising batched low-severity events.
+func (t *TelegramNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if msg := t.render(ctx, digestTemplateData(digest)); msg != nil {
+		return t.send(ctx, msg.Body)
+	}
+
+	lines := digestLines(digest)
+	for i, line := range lines {
+		lines[i] = "• " + escapeMarkdown(line)
+	}
+
+	extra := ""
+	if suppressed := suppressedSummary(digest.Suppressed); suppressed != "" {
+		extra = fmt.Sprintf("*Suppressed:* %s\n\n", suppressed)
+	}
+
+	text := fmt.Sprintf(
+		"📋 *%s*\n\n%s\n\n%s— %s",
+		digestTitle(digest),
+		strings.Join(lines, "\n"),
+		extra,
+		escapeMarkdown(BrandName),
+	)
+
+	return t.send(ctx, text)
+}
+
 func (t *TelegramNotifier) send(ctx context.Context, text string) error {
 	url := fmt.Sprintf("%s/bot%s/sendMessage", t.baseURL, t.botToken)
 
@@ -150,11 +220,15 @@ func (t *TelegramNotifier) send(ctx context.Context, text string) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
 		return &NotifierError{Notifier: "telegram", Err: fmt.Errorf("send request: %w", err)}
 	}
 	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return &NotifierError{Notifier: "telegram", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
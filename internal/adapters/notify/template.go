@@ -0,0 +1,519 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log/slog"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Limits that sandbox message templates. Templates only see TemplateData,
+// which holds plain values, and templateFuncs, none of which has side
+// effects. They cannot define or call other templates, so cannot recurse.
+const (
+	maxTemplateOutput = 16 * 1024
+	templateTimeout   = time.Second
+)
+
+// TemplateData is what message templates are executed with. Pointer fields
+// are nil for events they do not apply to; guard them with {{with}}.
+type TemplateData struct {
+	// Event is incident_opened, incident_resolved, agent_offline,
+	// agent_online, agent_maintenance or digest.
+	Event string
+	// Title is the built-in headline, e.g. "Incident Opened: api".
+	Title string
+	// Brand is the NOTIFICATION_BRAND label.
+	Brand string
+	// Time is when the event happened.
+	Time     time.Time
+	Incident *TemplateIncident // incident events
+	Monitor  *TemplateMonitor  // incident events
+	Alert    *TemplateAlert    // incident events, when dispatched with context
+	Agent    *TemplateAgent    // agent events
+	Digest   *TemplateDigest   // digest
+	// Tags are the monitor's tags.
+	Tags map[string]string
+	// Links are empty when PUBLIC_URL is not set.
+	Links TemplateLinks
+	// Suppressed counts the channel's notifications dropped since its last
+	// message.
+	Suppressed int
+}
+
+// TemplateIncident describes the incident of an incident event.
+type TemplateIncident struct {
+	ID          string
+	Status      string // open, acknowledged or resolved
+	Kind        string // down, degraded, anomaly or declared
+	Severity    string // critical, major, minor or info
+	State       string // DOWN, DEGRADED or DECLARED
+	Title       string // declared incidents only
+	Description string // declared incidents only
+	StartedAt   time.Time
+	ResolvedAt  *time.Time
+	Duration    string // e.g. "4m 12s"
+}
+
+// TemplateMonitor describes the monitor of an incident event.
+type TemplateMonitor struct {
+	ID     string
+	Name   string
+	Type   string
+	Target string
+}
+
+// TemplateAlert is the context an incident alert was dispatched with.
+type TemplateAlert struct {
+	Error     string
+	AgentName string
+	LatencyMs int    // 0 when unknown
+	Interval  string // e.g. "Every 30s"
+	Threshold int
+	Group     *TemplateGroup // set when the alert announces an alert group
+}
+
+// TemplateGroup describes the alert group an alert announces.
+type TemplateGroup struct {
+	Title    string
+	Total    int
+	Monitors []string
+}
+
+// TemplateAgent describes the agent of an agent event.
+type TemplateAgent struct {
+	ID                string
+	Name              string
+	AffectedMonitors  int    // agent_offline
+	ResolvedIncidents int    // agent_online
+	Window            string // agent_maintenance: the maintenance window's name
+}
+
+// TemplateDigest describes a digest of batched low-severity events.
+type TemplateDigest struct {
+	Since  time.Time
+	Events []TemplateDigestEvent
+}
+
+// TemplateDigestEvent is one event of a digest.
+type TemplateDigestEvent struct {
+	Time     time.Time
+	Event    string
+	Subject  string
+	Severity string
+}
+
+// TemplateLinks point at the hub's web UI.
+type TemplateLinks struct {
+	Dashboard string
+	Incidents string
+	Monitor   string // incident events
+}
+
+// templateFuncs are the functions message templates can call.
+var templateFuncs = map[string]any{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join": func(sep string, items []string) string {
+		return strings.Join(items, sep)
+	},
+	"truncate": func(n int, s string) string {
+		if n < 0 || len([]rune(s)) <= n {
+			return s
+		}
+		return string([]rune(s)[:n]) + "…"
+	},
+	"default": func(def, s string) string {
+		if s == "" {
+			return def
+		}
+		return s
+	},
+	// md escapes Telegram Markdown.
+	"md": escapeMarkdown,
+	// json encodes a value as JSON, for webhook bodies.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Message is a rendered message template.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// TemplateLookup returns the message template a channel uses for an event,
+// or nil for the built-in layout.
+type TemplateLookup func(ctx context.Context, event string) (*domain.MessageTemplate, error)
+
+// ChannelTemplates returns the TemplateLookup of a channel's templates.
+func ChannelTemplates(repo ports.MessageTemplateRepository, channel *domain.AlertChannel) TemplateLookup {
+	return func(ctx context.Context, event string) (*domain.MessageTemplate, error) {
+		return repo.Resolve(ctx, channel, event)
+	}
+}
+
+// ApplyTemplates makes n render the templates lookup returns, if its channel
+// type supports templates.
+func ApplyTemplates(n Notifier, lookup TemplateLookup) {
+	if t, ok := n.(interface{ SetTemplates(TemplateLookup) }); ok {
+		t.SetTemplates(lookup)
+	}
+}
+
+// templated is embedded by notifiers whose layout message templates can
+// override.
+type templated struct {
+	lookup TemplateLookup
+}
+
+// SetTemplates makes the notifier render the templates lookup returns.
+func (t *templated) SetTemplates(lookup TemplateLookup) {
+	t.lookup = lookup
+}
+
+// render returns the message the channel's template renders for data, or
+// nil to use the built-in layout: when there is no template or it fails.
+func (t *templated) render(ctx context.Context, data *TemplateData) *Message {
+	if t.lookup == nil {
+		return nil
+	}
+	tmpl, err := t.lookup(ctx, data.Event)
+	if err != nil {
+		slog.Warn("notify: failed to look up message template", slog.String("event", data.Event), slog.String("error", err.Error()))
+		return nil
+	}
+	if tmpl == nil {
+		return nil
+	}
+	msg, err := RenderTemplate(tmpl, data)
+	if err != nil {
+		slog.Warn("notify: message template failed, using built-in layout",
+			slog.String("template_id", tmpl.ID.String()),
+			slog.String("event", data.Event),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	return msg
+}
+
+// RenderTemplate renders a message template. Email bodies are HTML
+// templates; webhook bodies must render valid JSON. Without a subject
+// template the subject is the built-in title.
+func RenderTemplate(t *domain.MessageTemplate, data *TemplateData) (*Message, error) {
+	body, err := parseTemplate("body", t.Body, t.ChannelType == domain.AlertChannelEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &Message{Subject: data.Title}
+	if t.Subject != "" {
+		subject, err := parseTemplate("subject", t.Subject, false)
+		if err != nil {
+			return nil, err
+		}
+		if msg.Subject, err = executeTemplate(subject, data); err != nil {
+			return nil, err
+		}
+		msg.Subject = strings.Join(strings.Fields(msg.Subject), " ") // subjects are one line
+	}
+	if msg.Body, err = executeTemplate(body, data); err != nil {
+		return nil, err
+	}
+	if t.ChannelType == domain.AlertChannelWebhook && !json.Valid([]byte(msg.Body)) {
+		return nil, errors.New("body: webhook body must be valid JSON")
+	}
+	return msg, nil
+}
+
+// ValidateTemplate checks that a template renders a sample of every event it
+// applies to.
+func ValidateTemplate(t *domain.MessageTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	events := []string{t.Event}
+	if t.Event == domain.TemplateEventAny {
+		events = templateEvents
+	}
+	for _, event := range events {
+		if _, err := RenderTemplate(t, SampleTemplateData(event)); err != nil {
+			return fmt.Errorf("%w: %s: %v", domain.ErrInvalidMessageTemplate, event, err)
+		}
+	}
+	return nil
+}
+
+// executable is a parsed text or HTML template.
+type executable interface {
+	Execute(w io.Writer, data any) error
+}
+
+func parseTemplate(name, src string, html bool) (executable, error) {
+	var tree *parse.Tree
+	var tmpl executable
+	if html {
+		t, err := htmltemplate.New(name).Funcs(templateFuncs).Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		tree, tmpl = t.Tree, t
+	} else {
+		t, err := texttemplate.New(name).Funcs(templateFuncs).Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		tree, tmpl = t.Tree, t
+	}
+	if tree != nil && callsTemplate(tree.Root) {
+		return nil, fmt.Errorf("%s: template and block actions are not allowed", name)
+	}
+	return tmpl, nil
+}
+
+// callsTemplate reports whether a parse tree invokes another template.
+func callsTemplate(node parse.Node) bool {
+	switch n := node.(type) {
+	case *parse.TemplateNode:
+		return true
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if callsTemplate(child) {
+				return true
+			}
+		}
+	case *parse.IfNode:
+		return callsTemplate(n.List) || callsTemplate(n.ElseList)
+	case *parse.RangeNode:
+		return callsTemplate(n.List) || callsTemplate(n.ElseList)
+	case *parse.WithNode:
+		return callsTemplate(n.List) || callsTemplate(n.ElseList)
+	}
+	return false
+}
+
+// executeTemplate runs a template, failing when its output exceeds
+// maxTemplateOutput or it runs longer than templateTimeout.
+func executeTemplate(t executable, data *TemplateData) (string, error) {
+	type result struct {
+		out string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		w := &limitedBuffer{max: maxTemplateOutput}
+		err := t.Execute(w, data)
+		done <- result{out: w.String(), err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.out, r.err
+	case <-time.After(templateTimeout):
+		return "", fmt.Errorf("rendering took longer than %s", templateTimeout)
+	}
+}
+
+// limitedBuffer is a buffer that fails writes beyond max bytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		return 0, fmt.Errorf("output exceeds %d bytes", b.max)
+	}
+	return b.Buffer.Write(p)
+}
+
+// templateEvents are the events notifiers render templates for.
+var templateEvents = []string{
+	string(domain.AlertEventIncidentOpened),
+	string(domain.AlertEventIncidentResolved),
+	string(domain.AlertEventAgentOffline),
+	string(domain.AlertEventAgentOnline),
+	domain.DeferredEventMaintenance,
+	domain.NotificationEventDigest,
+}
+
+func templateLinks() TemplateLinks {
+	if PublicURL == "" {
+		return TemplateLinks{}
+	}
+	return TemplateLinks{Dashboard: PublicURL + "/dashboard", Incidents: PublicURL + "/incidents"}
+}
+
+// incidentTemplateData returns the template data of an incident event.
+func incidentTemplateData(kind domain.AlertEventKind, incident *domain.Incident, monitor *domain.Monitor) *TemplateData {
+	data := &TemplateData{
+		Event: string(kind),
+		Brand: BrandName,
+		Time:  incident.StartedAt,
+		Incident: &TemplateIncident{
+			ID:          incident.ID.String(),
+			Status:      string(incident.Status),
+			Kind:        string(incident.Kind),
+			Severity:    string(incidentSeverity(incident)),
+			State:       incidentState(incident),
+			Title:       incident.Title,
+			Description: incident.Description,
+			StartedAt:   incident.StartedAt,
+			ResolvedAt:  incident.ResolvedAt,
+			Duration:    formatDuration(incident.Duration()),
+		},
+		Monitor: &TemplateMonitor{
+			ID:     monitor.ID.String(),
+			Name:   monitor.Name,
+			Type:   string(monitor.Type),
+			Target: monitor.Target,
+		},
+		Tags:       monitor.Metadata,
+		Links:      templateLinks(),
+		Suppressed: incidentSuppressed(incident),
+	}
+	if kind == domain.AlertEventIncidentResolved {
+		data.Title = fmt.Sprintf("Incident Resolved: %s", monitor.Name)
+		if incident.ResolvedAt != nil {
+			data.Time = *incident.ResolvedAt
+		}
+	} else {
+		data.Title = fmt.Sprintf("Incident Opened: %s", monitor.Name)
+	}
+	data.Links.Monitor = monitorLink(monitor)
+
+	if ac := incident.AlertContext; ac != nil {
+		data.Alert = &TemplateAlert{
+			Error:     ac.ErrorMessage,
+			AgentName: ac.AgentName,
+			Threshold: ac.Threshold,
+		}
+		if ac.LastLatencyMs != nil {
+			data.Alert.LatencyMs = *ac.LastLatencyMs
+		}
+		if ac.Interval > 0 {
+			data.Alert.Interval = formatInterval(ac.Interval)
+		}
+		if g := ac.Group; g != nil {
+			data.Alert.Group = &TemplateGroup{Title: g.Title, Total: g.Total, Monitors: g.Monitors}
+		}
+	}
+	return data
+}
+
+// agentTemplateData returns the template data of an agent event.
+func agentTemplateData(event string, agent *domain.Agent, a TemplateAgent) *TemplateData {
+	a.ID = agent.ID.String()
+	a.Name = agent.Name
+	data := &TemplateData{
+		Event: event,
+		Brand: BrandName,
+		Time:  time.Now(),
+		Agent: &a,
+		Links: templateLinks(),
+	}
+	switch domain.AlertEventKind(event) {
+	case domain.AlertEventAgentOffline:
+		data.Title = fmt.Sprintf("Agent Offline: %s", agent.Name)
+	case domain.AlertEventAgentOnline:
+		data.Title = fmt.Sprintf("Agent Online: %s", agent.Name)
+	default:
+		data.Title = fmt.Sprintf("Maintenance Mode: %s", agent.Name)
+	}
+	return data
+}
+
+// digestTemplateData returns the template data of a digest.
+func digestTemplateData(digest *domain.NotificationDigest) *TemplateData {
+	d := &TemplateDigest{Since: digest.Since(), Events: make([]TemplateDigestEvent, 0, len(digest.Entries))}
+	for _, e := range digest.Entries {
+		d.Events = append(d.Events, TemplateDigestEvent{
+			Time:     e.CreatedAt,
+			Event:    digestEvent(e.Event),
+			Subject:  e.Subject,
+			Severity: string(e.Severity),
+		})
+	}
+	return &TemplateData{
+		Event:      domain.NotificationEventDigest,
+		Title:      digestTitle(digest),
+		Brand:      BrandName,
+		Time:       time.Now(),
+		Digest:     d,
+		Links:      templateLinks(),
+		Suppressed: digest.Suppressed,
+	}
+}
+
+// SampleTemplateData returns the data of a sample event, for previews and
+// validation. An unknown event gets an opened incident.
+func SampleTemplateData(event string) *TemplateData {
+	now := time.Now().Truncate(time.Second)
+	agent := &domain.Agent{ID: uuid.New(), Name: "edge-fra-1"}
+
+	switch event {
+	case string(domain.AlertEventAgentOffline):
+		return agentTemplateData(event, agent, TemplateAgent{AffectedMonitors: 4})
+	case string(domain.AlertEventAgentOnline):
+		return agentTemplateData(event, agent, TemplateAgent{ResolvedIncidents: 4})
+	case domain.DeferredEventMaintenance:
+		return agentTemplateData(event, agent, TemplateAgent{Window: "Kernel upgrade"})
+	case domain.NotificationEventDigest:
+		incidentID := uuid.New()
+		return digestTemplateData(&domain.NotificationDigest{
+			ChannelName: "ops",
+			Entries: []*domain.NotificationLogEntry{
+				{Event: string(domain.AlertEventIncidentOpened), Subject: "blog", IncidentID: &incidentID, Severity: domain.IncidentSeverityMinor, CreatedAt: now.Add(-20 * time.Minute)},
+				{Event: string(domain.AlertEventIncidentResolved), Subject: "blog", IncidentID: &incidentID, Severity: domain.IncidentSeverityMinor, CreatedAt: now.Add(-5 * time.Minute)},
+			},
+			Suppressed: 2,
+		})
+	}
+
+	latency := 5000
+	monitor := &domain.Monitor{
+		ID:       uuid.New(),
+		Name:     "api",
+		Type:     domain.MonitorTypeHTTP,
+		Target:   "https://api.example.com/health",
+		Metadata: map[string]string{"team": "payments", "env": "prod"},
+	}
+	incident := domain.NewIncident(monitor.ID)
+	incident.StartedAt = now.Add(-12 * time.Minute)
+	incident.Kind = domain.IncidentKindDown
+	incident.Severity = domain.IncidentSeverityCritical
+	incident.AlertContext = &domain.AlertContext{
+		ErrorMessage:  "connection refused",
+		LastLatencyMs: &latency,
+		AgentName:     agent.Name,
+		Interval:      30,
+		Threshold:     3,
+		Severity:      domain.IncidentSeverityCritical,
+	}
+	if event == string(domain.AlertEventIncidentResolved) {
+		incident.Status = domain.IncidentStatusResolved
+		incident.ResolvedAt = &now
+		return incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)
+	}
+	return incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)
+}
@@ -0,0 +1,161 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/adapters/notify"
+)
+
+func messageTemplate(channelType domain.AlertChannelType, event, subject, body string) *domain.MessageTemplate {
+	return &domain.MessageTemplate{ID: uuid.New(), ChannelType: channelType, Event: event, Subject: subject, Body: body}
+}
+
+func staticTemplates(t *domain.MessageTemplate) notify.TemplateLookup {
+	return func(_ context.Context, event string) (*domain.MessageTemplate, error) {
+		if t.Event != domain.TemplateEventAny && t.Event != event {
+			return nil, nil
+		}
+		return t, nil
+	}
+}
+
+func TestRenderTemplate_IncidentOpened(t *testing.T) {
+	tmpl := messageTemplate(domain.AlertChannelSlack, "",
+		"{{.Brand}}: {{.Monitor.Name | upper}}\n",
+		`{{.Incident.Severity}} on {{.Monitor.Target}}{{with .Alert}} ({{.Error}}){{end}} team={{index .Tags "team"}}`)
+
+	msg, err := notify.RenderTemplate(tmpl, notify.SampleTemplateData(string(domain.AlertEventIncidentOpened)))
+	require.NoError(t, err)
+	assert.Equal(t, "WatchDog Monitoring: API", msg.Subject, "subjects are one line")
+	assert.Contains(t, msg.Body, "critical on https://api.example.com/health")
+	assert.Contains(t, msg.Body, "team=payments")
+}
+
+func TestRenderTemplate_SubjectDefaultsToTitle(t *testing.T) {
+	tmpl := messageTemplate(domain.AlertChannelDiscord, "", "", "{{.Agent.Name}}")
+
+	msg, err := notify.RenderTemplate(tmpl, notify.SampleTemplateData(string(domain.AlertEventAgentOffline)))
+	require.NoError(t, err)
+	assert.Equal(t, "Agent Offline: edge-fra-1", msg.Subject)
+	assert.Equal(t, "edge-fra-1", msg.Body)
+}
+
+func TestRenderTemplate_EmailEscapesHTML(t *testing.T) {
+	data := notify.SampleTemplateData(string(domain.AlertEventIncidentOpened))
+	data.Monitor.Name = "<script>"
+	tmpl := messageTemplate(domain.AlertChannelEmail, "", "", "<p>{{.Monitor.Name}}</p>")
+
+	msg, err := notify.RenderTemplate(tmpl, data)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>&lt;script&gt;</p>", msg.Body)
+}
+
+func TestRenderTemplate_WebhookBodyMustBeJSON(t *testing.T) {
+	data := notify.SampleTemplateData(string(domain.AlertEventIncidentOpened))
+
+	_, err := notify.RenderTemplate(messageTemplate(domain.AlertChannelWebhook, "", "", `{"monitor": {{.Monitor.Name}}}`), data)
+	assert.Error(t, err)
+
+	msg, err := notify.RenderTemplate(messageTemplate(domain.AlertChannelWebhook, "", "", `{"monitor": {{json .Monitor.Name}}}`), data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"monitor": "api"}`, msg.Body)
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		body  string
+		ok    bool
+	}{
+		{"plain text", "", "{{.Title}}", true},
+		{"guarded fields for any event", "", "{{with .Incident}}{{.Severity}}{{end}}{{with .Agent}}{{.Name}}{{end}}", true},
+		{"incident fields for any event", "", "{{.Incident.Severity}}", false},
+		{"incident fields for incidents", string(domain.AlertEventIncidentResolved), "{{.Incident.Duration}}", true},
+		{"syntax error", "", "{{.Title", false},
+		{"unknown field", "", "{{.Nope}}", false},
+		{"unknown function", "", "{{exec .Title}}", false},
+		{"template action", "", `{{define "x"}}hi{{end}}{{template "x"}}`, false},
+		{"nested block", "", `{{if .Title}}{{block "x" .}}hi{{end}}{{end}}`, false},
+	}
+	for _, tt := range tests {
+		err := notify.ValidateTemplate(messageTemplate(domain.AlertChannelTelegram, tt.event, "", tt.body))
+		if tt.ok {
+			assert.NoError(t, err, tt.name)
+		} else {
+			assert.True(t, errors.Is(err, domain.ErrInvalidMessageTemplate), tt.name)
+		}
+	}
+}
+
+func TestValidateTemplate_OutputLimit(t *testing.T) {
+	// Two events squared: 4 copies of a 5000 byte line.
+	body := `{{range .Digest.Events}}{{range $.Digest.Events}}` + strings.Repeat("x", 5000) + `{{end}}{{end}}`
+	err := notify.ValidateTemplate(messageTemplate(domain.AlertChannelTelegram, domain.NotificationEventDigest, "", body))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "output exceeds")
+}
+
+func TestSlackNotifier_RendersTemplate(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewSlackNotifier(server.URL)
+	notifier.SetTemplates(staticTemplates(messageTemplate(domain.AlertChannelSlack, string(domain.AlertEventIncidentOpened),
+		"Down: {{.Monitor.Name}}", "{{.Monitor.Target}} is {{.Incident.State}}")))
+
+	require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), testIncident(), testMonitor()))
+	attachment := payload["attachments"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Down: Test Monitor", attachment["title"])
+	assert.Equal(t, "https://example.com is DOWN", attachment["text"])
+}
+
+func TestSlackNotifier_FallsBackWhenTemplateFails(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewSlackNotifier(server.URL)
+	// Valid for incidents, but agent events have no incident.
+	notifier.SetTemplates(staticTemplates(messageTemplate(domain.AlertChannelSlack, "", "", "{{.Incident.Severity}}")))
+
+	require.NoError(t, notifier.NotifyAgentOffline(context.Background(), &domain.Agent{ID: uuid.New(), Name: "edge"}, 2))
+	assert.Contains(t, body, "Agent Offline", "the built-in layout is sent")
+}
+
+func TestWebhookNotifier_RendersTemplate(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(server.URL, "")
+	notify.ApplyTemplates(notifier, staticTemplates(messageTemplate(domain.AlertChannelWebhook, "",
+		"", `{"text": {{json .Title}}, "event": "{{.Event}}"}`)))
+
+	require.NoError(t, notifier.NotifyIncidentResolved(context.Background(), testIncident(), testMonitor()))
+	assert.JSONEq(t, `{"text": "Incident Resolved: Test Monitor", "event": "incident_resolved"}`, body)
+}
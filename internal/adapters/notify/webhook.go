@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
 )
 
 // WebhookNotifier sends notifications to a generic webhook URL.
@@ -18,6 +19,7 @@ import (
 // headers for integrity verification and replay protection.
 // See docs/webhooks.md for the verification recipe.
 type WebhookNotifier struct {
+	templated
 	url           string
 	signingSecret string
 	httpClient    *http.Client
@@ -35,6 +37,10 @@ func NewWebhookNotifier(url, signingSecret string) *WebhookNotifier {
 
 // NotifyIncidentOpened sends a notification when an incident is opened.
 func (w *WebhookNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := w.render(ctx, incidentTemplateData(domain.AlertEventIncidentOpened, incident, monitor)); msg != nil {
+		return w.post(ctx, []byte(msg.Body))
+	}
+
 	payload := webhookPayload{
 		Event:     "incident.opened",
 		Timestamp: incident.StartedAt,
@@ -42,6 +48,8 @@ func (w *WebhookNotifier) NotifyIncidentOpened(ctx context.Context, incident *do
 			ID:        incident.ID.String(),
 			MonitorID: incident.MonitorID.String(),
 			Status:    string(incident.Status),
+			Kind:      string(incident.Kind),
+			Severity:  string(incidentSeverity(incident)),
 			StartedAt: incident.StartedAt,
 		},
 		Monitor: webhookMonitor{
@@ -58,6 +66,10 @@ func (w *WebhookNotifier) NotifyIncidentOpened(ctx context.Context, incident *do
 
 // NotifyIncidentResolved sends a notification when an incident is resolved.
 func (w *WebhookNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if msg := w.render(ctx, incidentTemplateData(domain.AlertEventIncidentResolved, incident, monitor)); msg != nil {
+		return w.post(ctx, []byte(msg.Body))
+	}
+
 	payload := webhookPayload{
 		Event:     "incident.resolved",
 		Timestamp: time.Now(),
@@ -65,6 +77,8 @@ func (w *WebhookNotifier) NotifyIncidentResolved(ctx context.Context, incident *
 			ID:         incident.ID.String(),
 			MonitorID:  incident.MonitorID.String(),
 			Status:     string(incident.Status),
+			Kind:       string(incident.Kind),
+			Severity:   string(incidentSeverity(incident)),
 			StartedAt:  incident.StartedAt,
 			ResolvedAt: incident.ResolvedAt,
 		},
@@ -82,6 +96,11 @@ func (w *WebhookNotifier) NotifyIncidentResolved(ctx context.Context, incident *
 
 // NotifyAgentOffline sends a notification when an agent goes offline.
 func (w *WebhookNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOffline), agent, TemplateAgent{AffectedMonitors: affectedMonitors})
+	if msg := w.render(ctx, data); msg != nil {
+		return w.post(ctx, []byte(msg.Body))
+	}
+
 	payload := webhookAgentPayload{
 		Event:            "agent.offline",
 		Timestamp:        time.Now(),
@@ -94,6 +113,11 @@ func (w *WebhookNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.
 
 // NotifyAgentOnline sends a notification when an agent comes back online.
 func (w *WebhookNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	data := agentTemplateData(string(domain.AlertEventAgentOnline), agent, TemplateAgent{ResolvedIncidents: resolvedIncidents})
+	if msg := w.render(ctx, data); msg != nil {
+		return w.post(ctx, []byte(msg.Body))
+	}
+
 	payload := webhookAgentPayload{
 		Event:             "agent.online",
 		Timestamp:         time.Now(),
@@ -106,6 +130,10 @@ func (w *WebhookNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.A
 
 // NotifyAgentMaintenance sends a notification when an agent enters maintenance mode.
 func (w *WebhookNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	if msg := w.render(ctx, agentTemplateData(domain.DeferredEventMaintenance, agent, TemplateAgent{Window: windowName})); msg != nil {
+		return w.post(ctx, []byte(msg.Body))
+	}
+
 	payload := webhookAgentPayload{
 		Event:      "agent.maintenance",
 		Timestamp:  time.Now(),
@@ -116,6 +144,39 @@ func (w *WebhookNotifier) NotifyAgentMaintenance(ctx context.Context, agent *dom
 	return w.sendAgent(ctx, payload)
 }
 
+// NotifyDigest sends a summary of batched low-severity events.
+func (w *WebhookNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if msg := w.render(ctx, digestTemplateData(digest)); msg != nil {
+		return w.post(ctx, []byte(msg.Body))
+	}
+
+	payload := webhookDigestPayload{
+		Event:      "notification.digest",
+		Timestamp:  time.Now(),
+		Since:      digest.Since(),
+		Suppressed: digest.Suppressed,
+		Events:     make([]webhookDigestEvent, 0, len(digest.Entries)),
+	}
+	for _, e := range digest.Entries {
+		event := webhookDigestEvent{
+			Event:     e.Event,
+			Subject:   e.Subject,
+			Severity:  string(e.Severity),
+			Timestamp: e.CreatedAt,
+		}
+		if e.IncidentID != nil {
+			event.IncidentID = e.IncidentID.String()
+		}
+		payload.Events = append(payload.Events, event)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &NotifierError{Notifier: "webhook", Err: fmt.Errorf("marshal payload: %w", err)}
+	}
+	return w.post(ctx, body)
+}
+
 func (w *WebhookNotifier) sendAgent(ctx context.Context, payload webhookAgentPayload) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -150,11 +211,15 @@ func (w *WebhookNotifier) post(ctx context.Context, body []byte) error {
 		req.Header.Set("X-Watchdog-Nonce", nonce)
 	}
 
+	receipt := ports.DeliveryReceiptFromContext(ctx)
+	receipt.SetPayload(body)
+
 	resp, err := w.httpClient.Do(req)
 	if err != nil {
 		return &NotifierError{Notifier: "webhook", Err: fmt.Errorf("send request: %w", err)}
 	}
 	defer resp.Body.Close()
+	receipt.SetStatus(resp.StatusCode)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return &NotifierError{Notifier: "webhook", Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
@@ -174,6 +239,8 @@ type webhookIncident struct {
 	ID         string     `json:"id"`
 	MonitorID  string     `json:"monitor_id"`
 	Status     string     `json:"status"`
+	Kind       string     `json:"kind,omitempty"`
+	Severity   string     `json:"severity"`
 	StartedAt  time.Time  `json:"started_at"`
 	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
 }
@@ -186,10 +253,19 @@ type webhookMonitor struct {
 }
 
 type webhookAlertContext struct {
-	ErrorMessage string `json:"error_message,omitempty"`
-	AgentName    string `json:"agent_name,omitempty"`
-	Interval     string `json:"interval,omitempty"`
-	Threshold    int    `json:"threshold,omitempty"`
+	ErrorMessage string             `json:"error_message,omitempty"`
+	AgentName    string             `json:"agent_name,omitempty"`
+	Interval     string             `json:"interval,omitempty"`
+	Threshold    int                `json:"threshold,omitempty"`
+	AlertGroup   *webhookAlertGroup `json:"alert_group,omitempty"`
+	Suppressed   int                `json:"suppressed,omitempty"`
+}
+
+type webhookAlertGroup struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Total    int      `json:"total"`
+	Monitors []string `json:"monitors"`
 }
 
 func buildWebhookContext(incident *domain.Incident) *webhookAlertContext {
@@ -201,10 +277,14 @@ func buildWebhookContext(incident *domain.Incident) *webhookAlertContext {
 		ErrorMessage: ac.ErrorMessage,
 		AgentName:    ac.AgentName,
 		Threshold:    ac.Threshold,
+		Suppressed:   ac.Suppressed,
 	}
 	if ac.Interval > 0 {
 		wctx.Interval = formatInterval(ac.Interval)
 	}
+	if g := ac.Group; g != nil {
+		wctx.AlertGroup = &webhookAlertGroup{ID: g.ID.String(), Title: g.Title, Total: g.Total, Monitors: g.Monitors}
+	}
 	return wctx
 }
 
@@ -217,3 +297,19 @@ type webhookAgentPayload struct {
 	ResolvedIncidents int       `json:"resolved_incidents,omitempty"`
 	WindowName        string    `json:"window_name,omitempty"`
 }
+
+type webhookDigestPayload struct {
+	Event      string               `json:"event"`
+	Timestamp  time.Time            `json:"timestamp"`
+	Since      time.Time            `json:"since"`
+	Suppressed int                  `json:"suppressed,omitempty"`
+	Events     []webhookDigestEvent `json:"events"`
+}
+
+type webhookDigestEvent struct {
+	Event      string    `json:"event"`
+	Subject    string    `json:"subject"`
+	IncidentID string    `json:"incident_id,omitempty"`
+	Severity   string    `json:"severity,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
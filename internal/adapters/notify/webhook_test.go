@@ -241,3 +241,27 @@ func TestWebhookNotifier_Signed_EachRequestHasFreshNonce(t *testing.T) {
 	assert.NotEqual(t, nonces[1], nonces[2])
 	assert.NotEqual(t, nonces[0], nonces[2])
 }
+
+func TestWebhookNotifier_Digest(t *testing.T) {
+	var receivedPayload map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&receivedPayload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	entry := domain.NewNotificationLogEntry(nil, nil, "global", string(domain.AlertEventIncidentOpened), "blog")
+	entry.Severity = domain.IncidentSeverityMinor
+	digest := &domain.NotificationDigest{Entries: []*domain.NotificationLogEntry{entry}, Suppressed: 4}
+
+	err := notify.NewWebhookNotifier(server.URL, "").NotifyDigest(context.Background(), digest)
+
+	require.NoError(t, err)
+	assert.Equal(t, "notification.digest", receivedPayload["event"])
+	assert.Equal(t, float64(4), receivedPayload["suppressed"])
+	events := receivedPayload["events"].([]any)
+	require.Len(t, events, 1)
+	assert.Equal(t, "blog", events[0].(map[string]any)["subject"])
+	assert.Equal(t, "minor", events[0].(map[string]any)["severity"])
+}
@@ -29,8 +29,8 @@ func (r *AgentRepository) Create(ctx context.Context, agent *domain.Agent) error
 	tenantID := TenantIDFromContext(ctx)
 
 	query := `
-		INSERT INTO agents (id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, created_at, tenant_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		INSERT INTO agents (id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, created_at, tenant_id, is_hub)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	_, err := q.Exec(ctx, query,
 		agent.ID,
@@ -42,6 +42,7 @@ func (r *AgentRepository) Create(ctx context.Context, agent *domain.Agent) error
 		agent.Status,
 		agent.CreatedAt,
 		tenantID,
+		agent.Hub,
 	)
 	if err != nil {
 		return fmt.Errorf("agentRepo.Create: %w", err)
@@ -56,7 +57,7 @@ func (r *AgentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Ag
 	tenantID := TenantIDFromContext(ctx)
 
 	query := `
-		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, created_at
+		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, is_hub, created_at
 		FROM agents
 		WHERE id = $1 AND tenant_id = $2`
 
@@ -72,6 +73,7 @@ func (r *AgentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Ag
 		&agent.Status,
 		&fingerprintJSON,
 		&agent.FingerprintVerifiedAt,
+		&agent.Hub,
 		&agent.CreatedAt,
 	)
 	if err != nil {
@@ -93,7 +95,7 @@ func (r *AgentRepository) GetByIDGlobal(ctx context.Context, id uuid.UUID) (*dom
 	q := r.db.Querier(ctx)
 
 	query := `
-		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, tenant_id, created_at
+		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, is_hub, tenant_id, created_at
 		FROM agents
 		WHERE id = $1`
 
@@ -109,6 +111,7 @@ func (r *AgentRepository) GetByIDGlobal(ctx context.Context, id uuid.UUID) (*dom
 		&agent.Status,
 		&fingerprintJSON,
 		&agent.FingerprintVerifiedAt,
+		&agent.Hub,
 		&agent.TenantID,
 		&agent.CreatedAt,
 	)
@@ -132,7 +135,7 @@ func (r *AgentRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 
 	// H-020: hard limit prevents unbounded result sets.
 	query := `
-		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, created_at
+		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, is_hub, created_at
 		FROM agents
 		WHERE user_id = $1 AND tenant_id = $2
 		ORDER BY created_at DESC
@@ -158,6 +161,7 @@ func (r *AgentRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 			&agent.Status,
 			&fingerprintJSON,
 			&agent.FingerprintVerifiedAt,
+			&agent.Hub,
 			&agent.CreatedAt,
 		)
 		if err != nil {
@@ -183,7 +187,7 @@ func (r *AgentRepository) GetAllInTenant(ctx context.Context) ([]*domain.Agent,
 
 	// H-020: hard limit prevents unbounded result sets.
 	query := `
-		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, created_at
+		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, is_hub, created_at
 		FROM agents
 		WHERE tenant_id = $1
 		ORDER BY created_at DESC
@@ -209,6 +213,7 @@ func (r *AgentRepository) GetAllInTenant(ctx context.Context) ([]*domain.Agent,
 			&agent.Status,
 			&fingerprintJSON,
 			&agent.FingerprintVerifiedAt,
+			&agent.Hub,
 			&agent.CreatedAt,
 		)
 		if err != nil {
@@ -234,7 +239,7 @@ func (r *AgentRepository) Update(ctx context.Context, agent *domain.Agent) error
 
 	query := `
 		UPDATE agents
-		SET name = $2, api_key_encrypted = $3, api_key_expires_at = $4, last_seen_at = $5, status = $6
+		SET name = $2, api_key_encrypted = $3, api_key_expires_at = $4, last_seen_at = $5, status = $6, is_hub = $8
 		WHERE id = $1 AND tenant_id = $7`
 
 	result, err := q.Exec(ctx, query,
@@ -245,6 +250,7 @@ func (r *AgentRepository) Update(ctx context.Context, agent *domain.Agent) error
 		agent.LastSeenAt,
 		agent.Status,
 		tenantID,
+		agent.Hub,
 	)
 	if err != nil {
 		return fmt.Errorf("agentRepo.Update(%s): %w", agent.ID, err)
@@ -372,3 +378,55 @@ func (r *AgentRepository) UpdateLastSeen(ctx context.Context, id uuid.UUID, last
 
 	return nil
 }
+
+// GetHubAgents retrieves all virtual hub agents in the current tenant.
+func (r *AgentRepository) GetHubAgents(ctx context.Context) ([]*domain.Agent, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	// H-020: hard limit prevents unbounded result sets.
+	query := `
+		SELECT id, user_id, name, api_key_encrypted, api_key_expires_at, last_seen_at, status, fingerprint, fingerprint_verified_at, is_hub, created_at
+		FROM agents
+		WHERE tenant_id = $1 AND is_hub = true
+		ORDER BY created_at
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("agentRepo.GetHubAgents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []*domain.Agent
+	for rows.Next() {
+		agent := &domain.Agent{TenantID: tenantID}
+		var fingerprintJSON []byte
+		err := rows.Scan(
+			&agent.ID,
+			&agent.UserID,
+			&agent.Name,
+			&agent.APIKeyEncrypted,
+			&agent.APIKeyExpiresAt,
+			&agent.LastSeenAt,
+			&agent.Status,
+			&fingerprintJSON,
+			&agent.FingerprintVerifiedAt,
+			&agent.Hub,
+			&agent.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("agentRepo.GetHubAgents: scan: %w", err)
+		}
+		if fingerprintJSON != nil {
+			_ = json.Unmarshal(fingerprintJSON, &agent.Fingerprint)
+		}
+		agents = append(agents, agent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("agentRepo.GetHubAgents: rows: %w", err)
+	}
+
+	return agents, nil
+}
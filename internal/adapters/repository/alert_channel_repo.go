@@ -33,10 +33,14 @@ func (r *AlertChannelRepository) Create(ctx context.Context, channel *domain.Ale
 	if err != nil {
 		return fmt.Errorf("alertChannelRepo.Create: encrypt: %w", err)
 	}
+	schedule, err := marshalDeliverySchedule(channel.DeliverySchedule)
+	if err != nil {
+		return fmt.Errorf("alertChannelRepo.Create: %w", err)
+	}
 
 	query := `
-		INSERT INTO alert_channels (id, user_id, type, name, config_encrypted, enabled, created_at, updated_at, tenant_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		INSERT INTO alert_channels (id, user_id, type, name, config_encrypted, enabled, delivery_schedule, created_at, updated_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	_, err = q.Exec(ctx, query,
 		channel.ID,
@@ -45,6 +49,7 @@ func (r *AlertChannelRepository) Create(ctx context.Context, channel *domain.Ale
 		channel.Name,
 		encrypted,
 		channel.Enabled,
+		schedule,
 		channel.CreatedAt,
 		channel.UpdatedAt,
 		tenantID,
@@ -62,7 +67,7 @@ func (r *AlertChannelRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 	tenantID := TenantIDFromContext(ctx)
 
 	query := `
-		SELECT id, user_id, type, name, config_encrypted, enabled, created_at, updated_at
+		SELECT id, user_id, type, name, config_encrypted, enabled, delivery_schedule, created_at, updated_at
 		FROM alert_channels
 		WHERE id = $1 AND tenant_id = $2`
 
@@ -84,7 +89,7 @@ func (r *AlertChannelRepository) GetByUserID(ctx context.Context, userID uuid.UU
 
 	// H-020: hard limit prevents unbounded result sets.
 	query := `
-		SELECT id, user_id, type, name, config_encrypted, enabled, created_at, updated_at
+		SELECT id, user_id, type, name, config_encrypted, enabled, delivery_schedule, created_at, updated_at
 		FROM alert_channels
 		WHERE user_id = $1 AND tenant_id = $2
 		ORDER BY created_at DESC
@@ -115,7 +120,7 @@ func (r *AlertChannelRepository) GetEnabledByUserID(ctx context.Context, userID
 
 	// H-020: hard limit prevents unbounded result sets.
 	query := `
-		SELECT id, user_id, type, name, config_encrypted, enabled, created_at, updated_at
+		SELECT id, user_id, type, name, config_encrypted, enabled, delivery_schedule, created_at, updated_at
 		FROM alert_channels
 		WHERE user_id = $1 AND tenant_id = $2 AND enabled = true
 		ORDER BY created_at DESC
@@ -139,7 +144,8 @@ func (r *AlertChannelRepository) GetEnabledByUserID(ctx context.Context, userID
 	return channels, rows.Err()
 }
 
-// Update updates an alert channel's name, enabled status, and config.
+// Update updates an alert channel's name, enabled status, config and
+// delivery schedule.
 func (r *AlertChannelRepository) Update(ctx context.Context, channel *domain.AlertChannel) error {
 	q := r.db.Querier(ctx)
 	tenantID := TenantIDFromContext(ctx)
@@ -148,13 +154,17 @@ func (r *AlertChannelRepository) Update(ctx context.Context, channel *domain.Ale
 	if err != nil {
 		return fmt.Errorf("alertChannelRepo.Update: encrypt: %w", err)
 	}
+	schedule, err := marshalDeliverySchedule(channel.DeliverySchedule)
+	if err != nil {
+		return fmt.Errorf("alertChannelRepo.Update: %w", err)
+	}
 
 	query := `
 		UPDATE alert_channels
-		SET name = $1, config_encrypted = $2, enabled = $3, updated_at = NOW()
-		WHERE id = $4 AND tenant_id = $5`
+		SET name = $1, config_encrypted = $2, enabled = $3, delivery_schedule = $4, updated_at = NOW()
+		WHERE id = $5 AND tenant_id = $6`
 
-	result, err := q.Exec(ctx, query, channel.Name, encrypted, channel.Enabled, channel.ID, tenantID)
+	result, err := q.Exec(ctx, query, channel.Name, encrypted, channel.Enabled, schedule, channel.ID, tenantID)
 	if err != nil {
 		return fmt.Errorf("alertChannelRepo.Update: %w", err)
 	}
@@ -203,15 +213,40 @@ func (r *AlertChannelRepository) decryptConfig(encrypted []byte) (map[string]str
 	return config, nil
 }
 
+// marshalDeliverySchedule encodes a channel's delivery schedule, or returns
+// nil (SQL NULL) when it has none.
+func marshalDeliverySchedule(schedule *domain.DeliverySchedule) ([]byte, error) {
+	if schedule == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("marshal delivery schedule: %w", err)
+	}
+	return data, nil
+}
+
+// unmarshalDeliverySchedule decodes a channel's delivery schedule.
+func unmarshalDeliverySchedule(data []byte) (*domain.DeliverySchedule, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var schedule domain.DeliverySchedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("unmarshal delivery schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
 // scanChannel scans a single row into an AlertChannel.
 func (r *AlertChannelRepository) scanChannel(row pgx.Row) (*domain.AlertChannel, error) {
 	var ch domain.AlertChannel
 	var channelType string
-	var encrypted []byte
+	var encrypted, schedule []byte
 
 	if err := row.Scan(
 		&ch.ID, &ch.UserID, &channelType, &ch.Name,
-		&encrypted, &ch.Enabled, &ch.CreatedAt, &ch.UpdatedAt,
+		&encrypted, &ch.Enabled, &schedule, &ch.CreatedAt, &ch.UpdatedAt,
 	); err != nil {
 		return nil, err
 	}
@@ -224,6 +259,10 @@ func (r *AlertChannelRepository) scanChannel(row pgx.Row) (*domain.AlertChannel,
 	}
 	ch.Config = config
 
+	if ch.DeliverySchedule, err = unmarshalDeliverySchedule(schedule); err != nil {
+		return nil, err
+	}
+
 	return &ch, nil
 }
 
@@ -231,11 +270,11 @@ func (r *AlertChannelRepository) scanChannel(row pgx.Row) (*domain.AlertChannel,
 func (r *AlertChannelRepository) scanChannelRow(rows pgx.Rows) (*domain.AlertChannel, error) {
 	var ch domain.AlertChannel
 	var channelType string
-	var encrypted []byte
+	var encrypted, schedule []byte
 
 	if err := rows.Scan(
 		&ch.ID, &ch.UserID, &channelType, &ch.Name,
-		&encrypted, &ch.Enabled, &ch.CreatedAt, &ch.UpdatedAt,
+		&encrypted, &ch.Enabled, &schedule, &ch.CreatedAt, &ch.UpdatedAt,
 	); err != nil {
 		return nil, err
 	}
@@ -248,5 +287,9 @@ func (r *AlertChannelRepository) scanChannelRow(rows pgx.Rows) (*domain.AlertCha
 	}
 	ch.Config = config
 
+	if ch.DeliverySchedule, err = unmarshalDeliverySchedule(schedule); err != nil {
+		return nil, err
+	}
+
 	return &ch, nil
 }
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const alertGroupColumns = "g.id, g.user_id, g.reason, g.title, g.keys, g.lead_incident_id, g.status, g.created_at, g.last_incident_at, g.notified_at, g.resolved_at"
+
+// alertGroupCount counts a group's incidents alongside its columns.
+const alertGroupCount = "(SELECT COUNT(*) FROM incidents i WHERE i.alert_group_id = g.id)"
+
+// AlertGroupRepository implements ports.AlertGroupRepository using PostgreSQL.
+type AlertGroupRepository struct {
+	db *DB
+}
+
+// NewAlertGroupRepository creates a new AlertGroupRepository.
+func NewAlertGroupRepository(db *DB) *AlertGroupRepository {
+	return &AlertGroupRepository{db: db}
+}
+
+func scanAlertGroup(row pgx.Row) (*domain.AlertGroup, error) {
+	g := &domain.AlertGroup{}
+	err := row.Scan(&g.ID, &g.UserID, &g.Reason, &g.Title, &g.Keys, &g.LeadIncidentID, &g.Status,
+		&g.CreatedAt, &g.LastIncidentAt, &g.NotifiedAt, &g.ResolvedAt, &g.IncidentCount)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Create inserts a new alert group.
+func (r *AlertGroupRepository) Create(ctx context.Context, g *domain.AlertGroup) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO alert_groups (id, user_id, reason, title, keys, lead_incident_id, status, tenant_id, created_at, last_incident_at, notified_at, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := q.Exec(ctx, query, g.ID, g.UserID, g.Reason, g.Title, alertGroupKeys(g), g.LeadIncidentID, g.Status,
+		tenantID, g.CreatedAt, g.LastIncidentAt, g.NotifiedAt, g.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("alertGroupRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores a group's reason, title, keys, lead incident and timestamps.
+func (r *AlertGroupRepository) Update(ctx context.Context, g *domain.AlertGroup) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE alert_groups
+		SET reason = $1, title = $2, keys = $3, lead_incident_id = $4, last_incident_at = $5, notified_at = $6
+		WHERE id = $7 AND tenant_id = $8`
+
+	result, err := q.Exec(ctx, query, g.Reason, g.Title, alertGroupKeys(g), g.LeadIncidentID, g.LastIncidentAt, g.NotifiedAt, g.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("alertGroupRepo.Update(%s): %w", g.ID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("alertGroupRepo.Update(%s): group not found", g.ID)
+	}
+
+	return nil
+}
+
+// Delete removes an alert group. Its incidents are detached from it.
+func (r *AlertGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM alert_groups WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("alertGroupRepo.Delete(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("alertGroupRepo.Delete(%s): group not found", id)
+	}
+
+	return nil
+}
+
+// Resolve marks an open group resolved. Returns false when the group was
+// already resolved, so only one caller announces its recovery.
+func (r *AlertGroupRepository) Resolve(ctx context.Context, id uuid.UUID) (bool, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE alert_groups
+		SET status = 'resolved', resolved_at = NOW()
+		WHERE id = $1 AND tenant_id = $2 AND status = 'open'`
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("alertGroupRepo.Resolve(%s): %w", id, err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetByID retrieves an alert group. Returns nil when it does not exist.
+func (r *AlertGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AlertGroup, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + alertGroupColumns + `, ` + alertGroupCount + ` FROM alert_groups g WHERE g.id = $1 AND g.tenant_id = $2`
+
+	g, err := scanAlertGroup(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("alertGroupRepo.GetByID(%s): %w", id, err)
+	}
+
+	return g, nil
+}
+
+// GetByUserID returns a user's alert groups, newest first. An empty status
+// returns groups in any status.
+func (r *AlertGroupRepository) GetByUserID(ctx context.Context, userID uuid.UUID, status domain.AlertGroupStatus) ([]*domain.AlertGroup, error) {
+	query := `
+		SELECT ` + alertGroupColumns + `, ` + alertGroupCount + `
+		FROM alert_groups g
+		WHERE g.user_id = $1 AND g.tenant_id = $2 AND ($3 = '' OR g.status = $3)
+		ORDER BY g.created_at DESC
+		LIMIT 100`
+
+	return r.list(ctx, "GetByUserID", query, userID, TenantIDFromContext(ctx), string(status))
+}
+
+// GetAccepting returns a user's open groups whose latest incident opened at
+// or after since, oldest first.
+func (r *AlertGroupRepository) GetAccepting(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.AlertGroup, error) {
+	query := `
+		SELECT ` + alertGroupColumns + `, 0
+		FROM alert_groups g
+		WHERE g.user_id = $1 AND g.tenant_id = $2 AND g.status = 'open' AND g.last_incident_at >= $3
+		ORDER BY g.created_at
+		LIMIT 100`
+
+	return r.list(ctx, "GetAccepting", query, userID, TenantIDFromContext(ctx), since)
+}
+
+// GetDue returns pending groups created at or before createdBefore.
+func (r *AlertGroupRepository) GetDue(ctx context.Context, createdBefore time.Time) ([]*domain.AlertGroup, error) {
+	query := `
+		SELECT ` + alertGroupColumns + `, 0
+		FROM alert_groups g
+		WHERE g.tenant_id = $1 AND g.status = 'open' AND g.notified_at IS NULL AND g.created_at <= $2
+		ORDER BY g.created_at
+		LIMIT 500`
+
+	return r.list(ctx, "GetDue", query, TenantIDFromContext(ctx), createdBefore)
+}
+
+func (r *AlertGroupRepository) list(ctx context.Context, method, query string, args ...any) ([]*domain.AlertGroup, error) {
+	q := r.db.Querier(ctx)
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("alertGroupRepo.%s: %w", method, err)
+	}
+	defer rows.Close()
+
+	var groups []*domain.AlertGroup
+	for rows.Next() {
+		g, err := scanAlertGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("alertGroupRepo.%s: scan: %w", method, err)
+		}
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("alertGroupRepo.%s: rows: %w", method, err)
+	}
+
+	return groups, nil
+}
+
+// GetIncidents returns a group's incidents ordered by start.
+func (r *AlertGroupRepository) GetIncidents(ctx context.Context, groupID uuid.UUID) ([]*domain.Incident, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE alert_group_id = $1 AND tenant_id = $2
+		ORDER BY started_at
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, groupID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("alertGroupRepo.GetIncidents(%s): %w", groupID, err)
+	}
+	defer rows.Close()
+
+	return scanIncidents(rows)
+}
+
+// SetIncidentGroup moves incidents into a group, or out of any group when
+// groupID is nil.
+func (r *AlertGroupRepository) SetIncidentGroup(ctx context.Context, incidentIDs []uuid.UUID, groupID *uuid.UUID) error {
+	return r.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		q := r.db.Querier(txCtx)
+		tenantID := TenantIDFromContext(txCtx)
+
+		for _, id := range incidentIDs {
+			result, err := q.Exec(txCtx,
+				`UPDATE incidents SET alert_group_id = $3 WHERE id = $1 AND tenant_id = $2`,
+				id, tenantID, groupID)
+			if err != nil {
+				return fmt.Errorf("alertGroupRepo.SetIncidentGroup(%s): %w", id, err)
+			}
+			if result.RowsAffected() == 0 {
+				return fmt.Errorf("alertGroupRepo.SetIncidentGroup(%s): incident not found", id)
+			}
+		}
+		return nil
+	})
+}
+
+// alertGroupKeys stores groups without keys as an empty array.
+func alertGroupKeys(g *domain.AlertGroup) []string {
+	if g.Keys == nil {
+		return []string{}
+	}
+	return g.Keys
+}
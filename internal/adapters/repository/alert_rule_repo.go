@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const alertRuleColumns = "id, user_id, name, position, enabled, conditions, action, channel_ids, created_at, updated_at"
+
+// AlertRuleRepository implements ports.AlertRuleRepository using PostgreSQL.
+type AlertRuleRepository struct {
+	db *DB
+}
+
+// NewAlertRuleRepository creates a new AlertRuleRepository.
+func NewAlertRuleRepository(db *DB) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+func scanAlertRule(row pgx.Row) (*domain.AlertRule, error) {
+	r := &domain.AlertRule{}
+	var conditions, channelIDs []byte
+	if err := row.Scan(&r.ID, &r.UserID, &r.Name, &r.Position, &r.Enabled, &conditions, &r.Action, &channelIDs, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(conditions, &r.Match); err != nil {
+		return nil, fmt.Errorf("decode conditions: %w", err)
+	}
+	if err := json.Unmarshal(channelIDs, &r.ChannelIDs); err != nil {
+		return nil, fmt.Errorf("decode channel_ids: %w", err)
+	}
+	return r, nil
+}
+
+// encodeAlertRule returns the JSON columns of a rule.
+func encodeAlertRule(rule *domain.AlertRule) (conditions, channelIDs []byte, err error) {
+	if conditions, err = json.Marshal(rule.Match); err != nil {
+		return nil, nil, fmt.Errorf("encode conditions: %w", err)
+	}
+	ids := rule.ChannelIDs
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	if channelIDs, err = json.Marshal(ids); err != nil {
+		return nil, nil, fmt.Errorf("encode channel_ids: %w", err)
+	}
+	return conditions, channelIDs, nil
+}
+
+// Create inserts a new alert rule.
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	conditions, channelIDs, err := encodeAlertRule(rule)
+	if err != nil {
+		return fmt.Errorf("alertRuleRepo.Create: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_rules (id, user_id, name, position, enabled, conditions, action, channel_ids, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = q.Exec(ctx, query, rule.ID, rule.UserID, rule.Name, rule.Position, rule.Enabled, conditions, rule.Action, channelIDs, tenantID, rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("alertRuleRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores a rule's name, position, state, conditions, action and channels.
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	conditions, channelIDs, err := encodeAlertRule(rule)
+	if err != nil {
+		return fmt.Errorf("alertRuleRepo.Update(%s): %w", rule.ID, err)
+	}
+
+	query := `
+		UPDATE alert_rules
+		SET name = $1, position = $2, enabled = $3, conditions = $4, action = $5, channel_ids = $6, updated_at = $7
+		WHERE id = $8 AND tenant_id = $9`
+
+	result, err := q.Exec(ctx, query, rule.Name, rule.Position, rule.Enabled, conditions, rule.Action, channelIDs, rule.UpdatedAt, rule.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("alertRuleRepo.Update(%s): %w", rule.ID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("alertRuleRepo.Update(%s): rule not found", rule.ID)
+	}
+
+	return nil
+}
+
+// Delete removes an alert rule.
+func (r *AlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM alert_rules WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("alertRuleRepo.Delete(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("alertRuleRepo.Delete(%s): rule not found", id)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an alert rule. Returns nil when it does not exist.
+func (r *AlertRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1 AND tenant_id = $2`
+
+	rule, err := scanAlertRule(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("alertRuleRepo.GetByID(%s): %w", id, err)
+	}
+
+	return rule, nil
+}
+
+// GetByUserID returns a user's alert rules in evaluation order.
+func (r *AlertRuleRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.AlertRule, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + alertRuleColumns + `
+		FROM alert_rules
+		WHERE user_id = $1 AND tenant_id = $2
+		ORDER BY position, created_at
+		LIMIT 100`
+
+	rows, err := q.Query(ctx, query, userID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("alertRuleRepo.GetByUserID: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*domain.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("alertRuleRepo.GetByUserID: scan: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("alertRuleRepo.GetByUserID: rows: %w", err)
+	}
+
+	return rules, nil
+}
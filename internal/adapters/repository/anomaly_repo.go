@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+// AnomalyRepository implements ports.AnomalyRepository using PostgreSQL.
+type AnomalyRepository struct {
+	db *DB
+}
+
+// NewAnomalyRepository creates a new AnomalyRepository.
+func NewAnomalyRepository(db *DB) *AnomalyRepository {
+	return &AnomalyRepository{db: db}
+}
+
+// GetBaselines returns every learned baseline slot of a monitor.
+func (r *AnomalyRepository) GetBaselines(ctx context.Context, monitorID uuid.UUID) ([]*domain.LatencyBaseline, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT monitor_id, hour_of_week, p50_mean, p50_var, p95_mean, p95_var, samples, last_bucket
+		FROM latency_baselines
+		WHERE monitor_id = $1 AND tenant_id = $2
+		ORDER BY hour_of_week
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, monitorID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("anomalyRepo.GetBaselines(%s): %w", monitorID, err)
+	}
+	defer rows.Close()
+
+	var baselines []*domain.LatencyBaseline
+	for rows.Next() {
+		b := &domain.LatencyBaseline{}
+		if err := rows.Scan(&b.MonitorID, &b.HourOfWeek, &b.P50Mean, &b.P50Var, &b.P95Mean, &b.P95Var, &b.Samples, &b.LastBucket); err != nil {
+			return nil, fmt.Errorf("anomalyRepo.GetBaselines(%s): scan: %w", monitorID, err)
+		}
+		baselines = append(baselines, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("anomalyRepo.GetBaselines(%s): rows: %w", monitorID, err)
+	}
+
+	return baselines, nil
+}
+
+// UpsertBaseline stores a baseline slot, replacing the previous values.
+func (r *AnomalyRepository) UpsertBaseline(ctx context.Context, b *domain.LatencyBaseline) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO latency_baselines (monitor_id, hour_of_week, p50_mean, p50_var, p95_mean, p95_var, samples, last_bucket, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (monitor_id, hour_of_week) DO UPDATE SET
+			p50_mean = EXCLUDED.p50_mean,
+			p50_var = EXCLUDED.p50_var,
+			p95_mean = EXCLUDED.p95_mean,
+			p95_var = EXCLUDED.p95_var,
+			samples = EXCLUDED.samples,
+			last_bucket = EXCLUDED.last_bucket`
+
+	_, err := q.Exec(ctx, query,
+		b.MonitorID, b.HourOfWeek, b.P50Mean, b.P50Var, b.P95Mean, b.P95Var, b.Samples, b.LastBucket, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("anomalyRepo.UpsertBaseline(%s): %w", b.MonitorID, err)
+	}
+
+	return nil
+}
+
+// CreateAnomaly records a detection.
+func (r *AnomalyRepository) CreateAnomaly(ctx context.Context, a *domain.LatencyAnomaly) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO latency_anomalies (id, monitor_id, detected_at, hour_of_week, observed_p50, observed_p95, baseline_p95, sigma, incident_id, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := q.Exec(ctx, query,
+		a.ID, a.MonitorID, a.DetectedAt, a.HourOfWeek, a.ObservedP50, a.ObservedP95, a.BaselineP95, a.Sigma, a.IncidentID, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("anomalyRepo.CreateAnomaly(%s): %w", a.MonitorID, err)
+	}
+
+	return nil
+}
+
+// GetAnomaliesInRange returns a monitor's detections in [from, to], oldest first.
+func (r *AnomalyRepository) GetAnomaliesInRange(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]*domain.LatencyAnomaly, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT id, monitor_id, detected_at, hour_of_week, observed_p50, observed_p95, baseline_p95, sigma, incident_id
+		FROM latency_anomalies
+		WHERE monitor_id = $1 AND tenant_id = $2 AND detected_at BETWEEN $3 AND $4
+		ORDER BY detected_at
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, monitorID, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("anomalyRepo.GetAnomaliesInRange(%s): %w", monitorID, err)
+	}
+	defer rows.Close()
+
+	var anomalies []*domain.LatencyAnomaly
+	for rows.Next() {
+		a, err := scanAnomaly(rows)
+		if err != nil {
+			return nil, fmt.Errorf("anomalyRepo.GetAnomaliesInRange(%s): scan: %w", monitorID, err)
+		}
+		anomalies = append(anomalies, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("anomalyRepo.GetAnomaliesInRange(%s): rows: %w", monitorID, err)
+	}
+
+	return anomalies, nil
+}
+
+func scanAnomaly(row pgx.Row) (*domain.LatencyAnomaly, error) {
+	a := &domain.LatencyAnomaly{}
+	err := row.Scan(&a.ID, &a.MonitorID, &a.DetectedAt, &a.HourOfWeek, &a.ObservedP50, &a.ObservedP95, &a.BaselineP95, &a.Sigma, &a.IncidentID)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+// DeferredNotificationRepository implements ports.DeferredNotificationRepository using PostgreSQL.
+type DeferredNotificationRepository struct {
+	db *DB
+}
+
+// NewDeferredNotificationRepository creates a new DeferredNotificationRepository.
+func NewDeferredNotificationRepository(db *DB) *DeferredNotificationRepository {
+	return &DeferredNotificationRepository{db: db}
+}
+
+// Create queues a deferred notification.
+func (r *DeferredNotificationRepository) Create(ctx context.Context, n *domain.DeferredNotification) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	payload, err := json.Marshal(n.Payload)
+	if err != nil {
+		return fmt.Errorf("deferredNotificationRepo.Create: marshal payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO deferred_notifications (id, channel_id, event, payload, deliver_at, attempts, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = q.Exec(ctx, query, n.ID, n.ChannelID, n.Event, payload, n.DeliverAt, n.Attempts, tenantID, n.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("deferredNotificationRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// GetDue returns the notifications due by now, oldest first.
+func (r *DeferredNotificationRepository) GetDue(ctx context.Context, now time.Time) ([]*domain.DeferredNotification, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT id, channel_id, event, payload, deliver_at, attempts, created_at
+		FROM deferred_notifications
+		WHERE tenant_id = $1 AND deliver_at <= $2
+		ORDER BY created_at
+		LIMIT 500`
+
+	rows, err := q.Query(ctx, query, tenantID, now)
+	if err != nil {
+		return nil, fmt.Errorf("deferredNotificationRepo.GetDue: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*domain.DeferredNotification
+	for rows.Next() {
+		n := &domain.DeferredNotification{}
+		var payload []byte
+		if err := rows.Scan(&n.ID, &n.ChannelID, &n.Event, &payload, &n.DeliverAt, &n.Attempts, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("deferredNotificationRepo.GetDue: scan: %w", err)
+		}
+		if err := json.Unmarshal(payload, &n.Payload); err != nil {
+			return nil, fmt.Errorf("deferredNotificationRepo.GetDue: unmarshal payload: %w", err)
+		}
+		due = append(due, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("deferredNotificationRepo.GetDue: rows: %w", err)
+	}
+
+	return due, nil
+}
+
+// CountByChannelID returns how many notifications a channel has deferred.
+func (r *DeferredNotificationRepository) CountByChannelID(ctx context.Context, channelID uuid.UUID) (int, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	var n int
+	err := q.QueryRow(ctx, `SELECT COUNT(*) FROM deferred_notifications WHERE channel_id = $1 AND tenant_id = $2`, channelID, tenantID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("deferredNotificationRepo.CountByChannelID: %w", err)
+	}
+	return n, nil
+}
+
+// Reschedule moves a notification whose delivery failed to a later time.
+func (r *DeferredNotificationRepository) Reschedule(ctx context.Context, id uuid.UUID, deliverAt time.Time, attempts int) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `UPDATE deferred_notifications SET deliver_at = $1, attempts = $2 WHERE id = $3 AND tenant_id = $4`
+
+	result, err := q.Exec(ctx, query, deliverAt, attempts, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("deferredNotificationRepo.Reschedule: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("deferredNotificationRepo.Reschedule: notification not found")
+	}
+	return nil
+}
+
+// Delete removes a notification from the queue.
+func (r *DeferredNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	if _, err := q.Exec(ctx, `DELETE FROM deferred_notifications WHERE id = $1 AND tenant_id = $2`, id, tenantID); err != nil {
+		return fmt.Errorf("deferredNotificationRepo.Delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+// DependencyRepository implements ports.DependencyRepository using PostgreSQL.
+type DependencyRepository struct {
+	db *DB
+}
+
+// NewDependencyRepository creates a new DependencyRepository.
+func NewDependencyRepository(db *DB) *DependencyRepository {
+	return &DependencyRepository{db: db}
+}
+
+// Create inserts a dependency edge. Adding an existing edge is a no-op.
+func (r *DependencyRepository) Create(ctx context.Context, dep *domain.MonitorDependency) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO monitor_dependencies (monitor_id, parent_id, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (monitor_id, parent_id) DO NOTHING`
+
+	_, err := q.Exec(ctx, query, dep.MonitorID, dep.ParentID, tenantID, dep.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("dependencyRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a dependency edge.
+func (r *DependencyRepository) Delete(ctx context.Context, monitorID, parentID uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM monitor_dependencies WHERE monitor_id = $1 AND parent_id = $2 AND tenant_id = $3`
+
+	result, err := q.Exec(ctx, query, monitorID, parentID, tenantID)
+	if err != nil {
+		return fmt.Errorf("dependencyRepo.Delete(%s): %w", monitorID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("dependencyRepo.Delete(%s): dependency not found", monitorID)
+	}
+
+	return nil
+}
+
+// GetParentIDs returns the monitors the given monitor depends on.
+func (r *DependencyRepository) GetParentIDs(ctx context.Context, monitorID uuid.UUID) ([]uuid.UUID, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT parent_id
+		FROM monitor_dependencies
+		WHERE monitor_id = $1 AND tenant_id = $2
+		ORDER BY created_at
+		LIMIT 100`
+
+	rows, err := q.Query(ctx, query, monitorID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("dependencyRepo.GetParentIDs(%s): %w", monitorID, err)
+	}
+	defer rows.Close()
+
+	var parentIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("dependencyRepo.GetParentIDs(%s): scan: %w", monitorID, err)
+		}
+		parentIDs = append(parentIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("dependencyRepo.GetParentIDs(%s): rows: %w", monitorID, err)
+	}
+
+	return parentIDs, nil
+}
+
+// GetByTenant returns every dependency edge for the current tenant.
+func (r *DependencyRepository) GetByTenant(ctx context.Context) ([]*domain.MonitorDependency, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT monitor_id, parent_id, created_at
+		FROM monitor_dependencies
+		WHERE tenant_id = $1
+		ORDER BY created_at
+		LIMIT 10000`
+
+	rows, err := q.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("dependencyRepo.GetByTenant: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []*domain.MonitorDependency
+	for rows.Next() {
+		dep := &domain.MonitorDependency{}
+		if err := rows.Scan(&dep.MonitorID, &dep.ParentID, &dep.CreatedAt); err != nil {
+			return nil, fmt.Errorf("dependencyRepo.GetByTenant: scan: %w", err)
+		}
+		deps = append(deps, dep)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("dependencyRepo.GetByTenant: rows: %w", err)
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const (
+	escalationPolicyColumns = "id, user_id, name, levels, repeat, created_at, updated_at"
+	escalationColumns       = "incident_id, policy_id, workflow_id, steps, step, resume_at, created_at"
+)
+
+// EscalationRepository implements ports.EscalationRepository using PostgreSQL.
+type EscalationRepository struct {
+	db *DB
+}
+
+// NewEscalationRepository creates a new EscalationRepository.
+func NewEscalationRepository(db *DB) *EscalationRepository {
+	return &EscalationRepository{db: db}
+}
+
+func scanEscalationPolicy(row pgx.Row) (*domain.EscalationPolicy, error) {
+	p := &domain.EscalationPolicy{}
+	var levels []byte
+	if err := row.Scan(&p.ID, &p.UserID, &p.Name, &levels, &p.Repeat, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(levels, &p.Levels); err != nil {
+		return nil, fmt.Errorf("decode levels: %w", err)
+	}
+	return p, nil
+}
+
+func scanEscalation(row pgx.Row) (*domain.Escalation, error) {
+	e := &domain.Escalation{}
+	var workflowID *uuid.UUID
+	var steps []byte
+	if err := row.Scan(&e.IncidentID, &e.PolicyID, &workflowID, &steps, &e.Step, &e.ResumeAt, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	if workflowID != nil {
+		e.WorkflowID = *workflowID
+	}
+	if err := json.Unmarshal(steps, &e.Steps); err != nil {
+		return nil, fmt.Errorf("decode steps: %w", err)
+	}
+	return e, nil
+}
+
+// CreatePolicy inserts a new escalation policy.
+func (r *EscalationRepository) CreatePolicy(ctx context.Context, p *domain.EscalationPolicy) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	levels, err := json.Marshal(p.Levels)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.CreatePolicy: encode levels: %w", err)
+	}
+
+	query := `
+		INSERT INTO escalation_policies (id, user_id, name, levels, repeat, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = q.Exec(ctx, query, p.ID, p.UserID, p.Name, levels, p.Repeat, tenantID, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.CreatePolicy: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePolicy stores a policy's name, levels and repeat count.
+func (r *EscalationRepository) UpdatePolicy(ctx context.Context, p *domain.EscalationPolicy) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	levels, err := json.Marshal(p.Levels)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.UpdatePolicy(%s): encode levels: %w", p.ID, err)
+	}
+
+	query := `
+		UPDATE escalation_policies
+		SET name = $1, levels = $2, repeat = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6`
+
+	result, err := q.Exec(ctx, query, p.Name, levels, p.Repeat, p.UpdatedAt, p.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.UpdatePolicy(%s): %w", p.ID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("escalationRepo.UpdatePolicy(%s): policy not found", p.ID)
+	}
+
+	return nil
+}
+
+// DeletePolicy removes an escalation policy. Monitors referencing it fall
+// back to their tags.
+func (r *EscalationRepository) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM escalation_policies WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.DeletePolicy(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("escalationRepo.DeletePolicy(%s): policy not found", id)
+	}
+
+	return nil
+}
+
+// GetPolicyByID retrieves an escalation policy. Returns nil when it does not exist.
+func (r *EscalationRepository) GetPolicyByID(ctx context.Context, id uuid.UUID) (*domain.EscalationPolicy, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + escalationPolicyColumns + ` FROM escalation_policies WHERE id = $1 AND tenant_id = $2`
+
+	p, err := scanEscalationPolicy(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("escalationRepo.GetPolicyByID(%s): %w", id, err)
+	}
+
+	return p, nil
+}
+
+// GetPolicyByName retrieves one of a user's policies by name. Returns nil
+// when the user has no policy with that name.
+func (r *EscalationRepository) GetPolicyByName(ctx context.Context, userID uuid.UUID, name string) (*domain.EscalationPolicy, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + escalationPolicyColumns + ` FROM escalation_policies WHERE user_id = $1 AND name = $2 AND tenant_id = $3`
+
+	p, err := scanEscalationPolicy(q.QueryRow(ctx, query, userID, name, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("escalationRepo.GetPolicyByName(%s): %w", name, err)
+	}
+
+	return p, nil
+}
+
+// GetPoliciesByUserID returns a user's escalation policies ordered by name.
+func (r *EscalationRepository) GetPoliciesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.EscalationPolicy, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + escalationPolicyColumns + `
+		FROM escalation_policies
+		WHERE user_id = $1 AND tenant_id = $2
+		ORDER BY name
+		LIMIT 100`
+
+	rows, err := q.Query(ctx, query, userID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("escalationRepo.GetPoliciesByUserID: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*domain.EscalationPolicy
+	for rows.Next() {
+		p, err := scanEscalationPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("escalationRepo.GetPoliciesByUserID: scan: %w", err)
+		}
+		policies = append(policies, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("escalationRepo.GetPoliciesByUserID: rows: %w", err)
+	}
+
+	return policies, nil
+}
+
+// CreateEscalation starts tracking an escalation for an incident.
+func (r *EscalationRepository) CreateEscalation(ctx context.Context, e *domain.Escalation) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	steps, err := json.Marshal(e.Steps)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.CreateEscalation: encode steps: %w", err)
+	}
+
+	query := `
+		INSERT INTO incident_escalations (incident_id, policy_id, workflow_id, steps, step, resume_at, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = q.Exec(ctx, query, e.IncidentID, e.PolicyID, nullableWorkflowID(e.WorkflowID), steps, e.Step, e.ResumeAt, tenantID, e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.CreateEscalation: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateEscalation stores an escalation's progress and resume time.
+func (r *EscalationRepository) UpdateEscalation(ctx context.Context, e *domain.Escalation) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE incident_escalations
+		SET step = $1, resume_at = $2
+		WHERE incident_id = $3 AND tenant_id = $4`
+
+	result, err := q.Exec(ctx, query, e.Step, e.ResumeAt, e.IncidentID, tenantID)
+	if err != nil {
+		return fmt.Errorf("escalationRepo.UpdateEscalation(%s): %w", e.IncidentID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("escalationRepo.UpdateEscalation(%s): escalation not found", e.IncidentID)
+	}
+
+	return nil
+}
+
+// SetEscalationWorkflow records the workflow running an incident's
+// escalation. It only touches workflow_id so it cannot undo progress the
+// workflow already made.
+func (r *EscalationRepository) SetEscalationWorkflow(ctx context.Context, incidentID, workflowID uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `UPDATE incident_escalations SET workflow_id = $1 WHERE incident_id = $2 AND tenant_id = $3`
+
+	if _, err := q.Exec(ctx, query, workflowID, incidentID, tenantID); err != nil {
+		return fmt.Errorf("escalationRepo.SetEscalationWorkflow(%s): %w", incidentID, err)
+	}
+
+	return nil
+}
+
+// DeleteEscalation stops tracking an incident's escalation. Deleting an
+// escalation that does not exist is not an error.
+func (r *EscalationRepository) DeleteEscalation(ctx context.Context, incidentID uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM incident_escalations WHERE incident_id = $1 AND tenant_id = $2`
+
+	if _, err := q.Exec(ctx, query, incidentID, tenantID); err != nil {
+		return fmt.Errorf("escalationRepo.DeleteEscalation(%s): %w", incidentID, err)
+	}
+
+	return nil
+}
+
+// GetEscalation retrieves an incident's escalation. Returns nil when none is running.
+func (r *EscalationRepository) GetEscalation(ctx context.Context, incidentID uuid.UUID) (*domain.Escalation, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + escalationColumns + ` FROM incident_escalations WHERE incident_id = $1 AND tenant_id = $2`
+
+	e, err := scanEscalation(q.QueryRow(ctx, query, incidentID, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("escalationRepo.GetEscalation(%s): %w", incidentID, err)
+	}
+
+	return e, nil
+}
+
+// GetDueEscalations returns escalations waiting on a delay that ended at or before now.
+func (r *EscalationRepository) GetDueEscalations(ctx context.Context, now time.Time) ([]*domain.Escalation, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + escalationColumns + `
+		FROM incident_escalations
+		WHERE tenant_id = $1 AND resume_at IS NOT NULL AND resume_at <= $2
+		ORDER BY resume_at
+		LIMIT 500`
+
+	rows, err := q.Query(ctx, query, tenantID, now)
+	if err != nil {
+		return nil, fmt.Errorf("escalationRepo.GetDueEscalations: %w", err)
+	}
+	defer rows.Close()
+
+	var escalations []*domain.Escalation
+	for rows.Next() {
+		e, err := scanEscalation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("escalationRepo.GetDueEscalations: scan: %w", err)
+		}
+		escalations = append(escalations, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("escalationRepo.GetDueEscalations: rows: %w", err)
+	}
+
+	return escalations, nil
+}
+
+// nullableWorkflowID stores the zero UUID as NULL: the escalation row is
+// written before its workflow is submitted.
+func nullableWorkflowID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
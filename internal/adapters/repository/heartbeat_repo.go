@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -132,6 +133,39 @@ func (r *HeartbeatRepository) GetByMonitorIDInRange(ctx context.Context, monitor
 	return scanHeartbeats(rows, monitorID)
 }
 
+// GetRecentPerAgent retrieves up to perAgent of the most recent heartbeats
+// from each agent probing a monitor, newest first. Only the last day is
+// scanned, which bounds the query on large hypertables.
+func (r *HeartbeatRepository) GetRecentPerAgent(ctx context.Context, monitorID uuid.UUID, perAgent int) ([]*domain.Heartbeat, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	// H-020: clamp caller-supplied limit to a safe maximum.
+	const maxPerAgent = 100
+	if perAgent <= 0 || perAgent > maxPerAgent {
+		perAgent = maxPerAgent
+	}
+
+	query := `
+		SELECT time, monitor_id, agent_id, status, latency_ms, error_message, cert_expiry_days, cert_issuer
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY agent_id ORDER BY time DESC) AS rn
+			FROM heartbeats
+			WHERE monitor_id = $1 AND tenant_id = $2 AND time > NOW() - INTERVAL '1 day'
+		) recent
+		WHERE rn <= $3
+		ORDER BY time DESC
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, monitorID, tenantID, perAgent)
+	if err != nil {
+		return nil, fmt.Errorf("heartbeatRepo.GetRecentPerAgent(%s): %w", monitorID, err)
+	}
+	defer rows.Close()
+
+	return scanHeartbeats(rows, monitorID)
+}
+
 // GetLatestByMonitorID retrieves the most recent heartbeat for a monitor.
 func (r *HeartbeatRepository) GetLatestByMonitorID(ctx context.Context, monitorID uuid.UUID) (*domain.Heartbeat, error) {
 	q := r.db.Querier(ctx)
@@ -189,10 +223,12 @@ func (r *HeartbeatRepository) GetRecentFailures(ctx context.Context, monitorID u
 }
 
 // GetUptimePercent calculates the uptime percentage for a monitor since the given time.
+// Long ranges read the coarsest heartbeat rollup that still fits the range.
 func (r *HeartbeatRepository) GetUptimePercent(ctx context.Context, monitorID uuid.UUID, since time.Time) (float64, error) {
 	q := r.db.Querier(ctx)
 	tenantID := TenantIDFromContext(ctx)
 
+	rollup := domain.HeartbeatRollupFor(time.Since(since), 0)
 	query := `
 		SELECT COALESCE(
 			COUNT(*) FILTER (WHERE status IN ('up')) * 100.0 / NULLIF(COUNT(*), 0),
@@ -200,9 +236,18 @@ func (r *HeartbeatRepository) GetUptimePercent(ctx context.Context, monitorID uu
 		)
 		FROM heartbeats
 		WHERE monitor_id = $1 AND tenant_id = $2 AND time >= $3`
+	if rollup != domain.HeartbeatRollupRaw {
+		query = fmt.Sprintf(`
+		SELECT COALESCE(
+			SUM(up_count) * 100.0 / NULLIF(SUM(up_count + down_count), 0),
+			100.0
+		)
+		FROM %s
+		WHERE monitor_id = $1 AND tenant_id = $2 AND bucket >= $3`, rollup)
+	}
 
 	var pct float64
-	err := q.QueryRow(ctx, query, monitorID, tenantID, since).Scan(&pct)
+	err := q.QueryRow(ctx, query, monitorID, tenantID, rollup.Start(since)).Scan(&pct)
 	if err != nil {
 		return 0, fmt.Errorf("heartbeatRepo.GetUptimePercent(%s): %w", monitorID, err)
 	}
@@ -211,18 +256,33 @@ func (r *HeartbeatRepository) GetUptimePercent(ctx context.Context, monitorID uu
 }
 
 // GetLatencyHistory returns aggregated latency data points using TimescaleDB time_bucket.
+// Long ranges re-bucket the coarsest heartbeat rollup that divides bucketInterval;
+// the rollup averages are weighted by their sample counts.
 func (r *HeartbeatRepository) GetLatencyHistory(ctx context.Context, monitorID uuid.UUID, since time.Time, bucketInterval string) ([]domain.LatencyPoint, error) {
 	q := r.db.Querier(ctx)
 	tenantID := TenantIDFromContext(ctx)
 
+	rollup := domain.HeartbeatRollupRaw
+	if bucket, ok := parseBucketInterval(bucketInterval); ok {
+		rollup = domain.HeartbeatRollupFor(time.Since(since), bucket)
+	}
 	query := `
 		SELECT time_bucket($3::interval, time) AS bucket,
 			AVG(latency_ms)::INT, MIN(latency_ms), MAX(latency_ms)
 		FROM heartbeats
 		WHERE monitor_id = $1 AND tenant_id = $2 AND time >= $4 AND latency_ms IS NOT NULL
 		GROUP BY bucket ORDER BY bucket`
+	if rollup != domain.HeartbeatRollupRaw {
+		query = fmt.Sprintf(`
+		SELECT time_bucket($3::interval, bucket) AS b,
+			(SUM(latency_avg * latency_count) / SUM(latency_count))::INT,
+			MIN(latency_min), MAX(latency_max)
+		FROM %s
+		WHERE monitor_id = $1 AND tenant_id = $2 AND bucket >= $4 AND latency_count > 0
+		GROUP BY b ORDER BY b`, rollup)
+	}
 
-	rows, err := q.Query(ctx, query, monitorID, tenantID, bucketInterval, since)
+	rows, err := q.Query(ctx, query, monitorID, tenantID, bucketInterval, rollup.Start(since))
 	if err != nil {
 		return nil, fmt.Errorf("heartbeatRepo.GetLatencyHistory(%s): %w", monitorID, err)
 	}
@@ -323,6 +383,53 @@ func (r *HeartbeatRepository) DeleteOlderThan(ctx context.Context, before time.T
 	return result.RowsAffected(), nil
 }
 
+// DeleteRollupsOlderThan drops heartbeat rollup chunks older than the
+// specified time, for every tenant.
+func (r *HeartbeatRepository) DeleteRollupsOlderThan(ctx context.Context, before time.Time) error {
+	q := r.db.Querier(ctx)
+	for _, rollup := range domain.HeartbeatRollups {
+		if _, err := q.Exec(ctx, `SELECT drop_chunks($1::regclass, older_than => $2::timestamptz)`, string(rollup), before); err != nil {
+			return fmt.Errorf("heartbeatRepo.DeleteRollupsOlderThan(%s): %w", rollup, err)
+		}
+	}
+	return nil
+}
+
+// RefreshRollups materializes every heartbeat rollup between from and to.
+// Only buckets invalidated since the last refresh are recomputed, so calling
+// it repeatedly is cheap. It must not run inside a transaction.
+func (r *HeartbeatRepository) RefreshRollups(ctx context.Context, from, to time.Time) error {
+	q := r.db.Querier(ctx)
+	for _, rollup := range domain.HeartbeatRollups {
+		if _, err := q.Exec(ctx, `CALL refresh_continuous_aggregate($1::regclass, $2::timestamptz, $3::timestamptz)`, string(rollup), from, to); err != nil {
+			return fmt.Errorf("heartbeatRepo.RefreshRollups(%s): %w", rollup, err)
+		}
+	}
+	return nil
+}
+
+// parseBucketInterval parses the "<n> <unit>" interval strings passed to
+// time_bucket, such as "15 minutes" or "1 day".
+func parseBucketInterval(interval string) (time.Duration, bool) {
+	var n int
+	var unit string
+	if _, err := fmt.Sscanf(interval, "%d %s", &n, &unit); err != nil || n <= 0 {
+		return 0, false
+	}
+	units := map[string]time.Duration{
+		"second": time.Second,
+		"minute": time.Minute,
+		"hour":   time.Hour,
+		"day":    24 * time.Hour,
+		"week":   7 * 24 * time.Hour,
+	}
+	d, ok := units[strings.TrimSuffix(unit, "s")]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * d, true
+}
+
 // scanHeartbeats is a helper function to scan rows into heartbeats slice.
 func scanHeartbeats(rows pgx.Rows, contextID uuid.UUID) ([]*domain.Heartbeat, error) {
 	var heartbeats []*domain.Heartbeat
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const incidentActivityColumns = "a.id, a.incident_id, a.kind, a.author_id, COALESCE(u.email, ''), a.body, a.url, a.status, a.created_at, a.updated_at"
+
+// IncidentActivityRepository implements ports.IncidentActivityRepository using PostgreSQL.
+type IncidentActivityRepository struct {
+	db *DB
+}
+
+// NewIncidentActivityRepository creates a new IncidentActivityRepository.
+func NewIncidentActivityRepository(db *DB) *IncidentActivityRepository {
+	return &IncidentActivityRepository{db: db}
+}
+
+func scanIncidentActivity(row pgx.Row) (*domain.IncidentActivity, error) {
+	a := &domain.IncidentActivity{}
+	var status string
+	if err := row.Scan(&a.ID, &a.IncidentID, &a.Kind, &a.AuthorID, &a.AuthorEmail, &a.Body, &a.URL, &status, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	a.Status = domain.IncidentStatus(status)
+	return a, nil
+}
+
+// Create inserts an activity log entry.
+func (r *IncidentActivityRepository) Create(ctx context.Context, a *domain.IncidentActivity) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO incident_activity (id, incident_id, kind, author_id, body, url, status, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := q.Exec(ctx, query,
+		a.ID, a.IncidentID, a.Kind, a.AuthorID, a.Body, a.URL, string(a.Status), tenantID, a.CreatedAt, a.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("incidentActivityRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores the edited body and URL of an entry.
+func (r *IncidentActivityRepository) Update(ctx context.Context, a *domain.IncidentActivity) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE incident_activity
+		SET body = $1, url = $2, updated_at = $3
+		WHERE id = $4 AND tenant_id = $5`
+
+	result, err := q.Exec(ctx, query, a.Body, a.URL, a.UpdatedAt, a.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("incidentActivityRepo.Update(%s): %w", a.ID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("incidentActivityRepo.Update(%s): activity not found", a.ID)
+	}
+
+	return nil
+}
+
+// Delete removes an activity log entry.
+func (r *IncidentActivityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM incident_activity WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("incidentActivityRepo.Delete(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("incidentActivityRepo.Delete(%s): activity not found", id)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an activity log entry. Returns nil when it does not exist.
+func (r *IncidentActivityRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.IncidentActivity, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + incidentActivityColumns + `
+		FROM incident_activity a
+		LEFT JOIN users u ON u.id = a.author_id
+		WHERE a.id = $1 AND a.tenant_id = $2`
+
+	a, err := scanIncidentActivity(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("incidentActivityRepo.GetByID(%s): %w", id, err)
+	}
+
+	return a, nil
+}
+
+// GetByIncidentID returns an incident's activity log, oldest first.
+func (r *IncidentActivityRepository) GetByIncidentID(ctx context.Context, incidentID uuid.UUID) ([]*domain.IncidentActivity, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + incidentActivityColumns + `
+		FROM incident_activity a
+		LEFT JOIN users u ON u.id = a.author_id
+		WHERE a.incident_id = $1 AND a.tenant_id = $2
+		ORDER BY a.created_at
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, incidentID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentActivityRepo.GetByIncidentID(%s): %w", incidentID, err)
+	}
+	defer rows.Close()
+
+	var activity []*domain.IncidentActivity
+	for rows.Next() {
+		a, err := scanIncidentActivity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("incidentActivityRepo.GetByIncidentID(%s): scan: %w", incidentID, err)
+		}
+		activity = append(activity, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("incidentActivityRepo.GetByIncidentID(%s): rows: %w", incidentID, err)
+	}
+
+	return activity, nil
+}
+
+// GetPostmortem returns an incident's postmortem, or nil when none was written.
+func (r *IncidentActivityRepository) GetPostmortem(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentPostmortem, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT p.incident_id, p.root_cause, p.impact, p.action_items, p.author_id, COALESCE(u.email, ''), p.created_at, p.updated_at
+		FROM incident_postmortems p
+		LEFT JOIN users u ON u.id = p.author_id
+		WHERE p.incident_id = $1 AND p.tenant_id = $2`
+
+	p := &domain.IncidentPostmortem{}
+	var actionItems []byte
+	err := q.QueryRow(ctx, query, incidentID, tenantID).Scan(
+		&p.IncidentID, &p.RootCause, &p.Impact, &actionItems, &p.AuthorID, &p.AuthorEmail, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("incidentActivityRepo.GetPostmortem(%s): %w", incidentID, err)
+	}
+	if len(actionItems) > 0 {
+		if err := json.Unmarshal(actionItems, &p.ActionItems); err != nil {
+			return nil, fmt.Errorf("incidentActivityRepo.GetPostmortem(%s): decode action items: %w", incidentID, err)
+		}
+	}
+
+	return p, nil
+}
+
+// UpsertPostmortem stores an incident's postmortem, replacing the previous
+// one. The original author and creation time are kept.
+func (r *IncidentActivityRepository) UpsertPostmortem(ctx context.Context, p *domain.IncidentPostmortem) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	actionItems, err := json.Marshal(p.ActionItems)
+	if err != nil {
+		return fmt.Errorf("incidentActivityRepo.UpsertPostmortem(%s): encode action items: %w", p.IncidentID, err)
+	}
+
+	query := `
+		INSERT INTO incident_postmortems (incident_id, root_cause, impact, action_items, author_id, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (incident_id) DO UPDATE SET
+			root_cause = EXCLUDED.root_cause,
+			impact = EXCLUDED.impact,
+			action_items = EXCLUDED.action_items,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = q.Exec(ctx, query,
+		p.IncidentID, p.RootCause, p.Impact, actionItems, p.AuthorID, tenantID, p.CreatedAt, p.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("incidentActivityRepo.UpsertPostmortem(%s): %w", p.IncidentID, err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -12,6 +13,8 @@ import (
 	"github.com/sylvester-francis/watchdog/core/domain"
 )
 
+const incidentColumns = "id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at, kind, parent_incident_id, severity, alert_group_id, user_id, title, description, owner_id, affected_monitor_ids"
+
 // IncidentRepository implements ports.IncidentRepository using PostgreSQL.
 type IncidentRepository struct {
 	db *DB
@@ -27,13 +30,19 @@ func (r *IncidentRepository) Create(ctx context.Context, incident *domain.Incide
 	q := r.db.Querier(ctx)
 	tenantID := TenantIDFromContext(ctx)
 
+	affected, err := json.Marshal(incidentAffectedMonitors(incident))
+	if err != nil {
+		return fmt.Errorf("incidentRepo.Create: encode affected monitors: %w", err)
+	}
+
 	query := `
-		INSERT INTO incidents (id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at, tenant_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+		INSERT INTO incidents (id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at, tenant_id, kind, parent_incident_id, severity,
+			user_id, title, description, owner_id, affected_monitor_ids)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
 
-	_, err := q.Exec(ctx, query,
+	_, err = q.Exec(ctx, query,
 		incident.ID,
-		incident.MonitorID,
+		incidentMonitorID(incident),
 		incident.StartedAt,
 		incident.ResolvedAt,
 		incident.TTRSeconds,
@@ -42,6 +51,14 @@ func (r *IncidentRepository) Create(ctx context.Context, incident *domain.Incide
 		incident.Status,
 		incident.CreatedAt,
 		tenantID,
+		incidentKind(incident),
+		incident.ParentIncidentID,
+		incidentSeverity(incident),
+		incident.UserID,
+		incident.Title,
+		incident.Description,
+		incident.OwnerID,
+		affected,
 	)
 	if err != nil {
 		return fmt.Errorf("incidentRepo.Create: %w", err)
@@ -56,22 +73,11 @@ func (r *IncidentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	tenantID := TenantIDFromContext(ctx)
 
 	query := `
-		SELECT id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
 		WHERE id = $1 AND tenant_id = $2`
 
-	incident := &domain.Incident{}
-	err := q.QueryRow(ctx, query, id, tenantID).Scan(
-		&incident.ID,
-		&incident.MonitorID,
-		&incident.StartedAt,
-		&incident.ResolvedAt,
-		&incident.TTRSeconds,
-		&incident.AcknowledgedBy,
-		&incident.AcknowledgedAt,
-		&incident.Status,
-		&incident.CreatedAt,
-	)
+	incident, err := scanIncident(q.QueryRow(ctx, query, id, tenantID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -89,7 +95,7 @@ func (r *IncidentRepository) GetByMonitorID(ctx context.Context, monitorID uuid.
 
 	// H-020: hard limit prevents unbounded result sets.
 	query := `
-		SELECT id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
 		WHERE monitor_id = $1 AND tenant_id = $2
 		ORDER BY created_at DESC
@@ -111,23 +117,12 @@ func (r *IncidentRepository) GetActiveByMonitorID(ctx context.Context, monitorID
 	tenantID := TenantIDFromContext(ctx)
 
 	query := `
-		SELECT id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
-		WHERE monitor_id = $1 AND tenant_id = $2 AND status IN ('open', 'acknowledged')
+		WHERE monitor_id = $1 AND tenant_id = $2 AND status IN ('open', 'acknowledged', 'flapping')
 		LIMIT 1`
 
-	incident := &domain.Incident{}
-	err := q.QueryRow(ctx, query, monitorID, tenantID).Scan(
-		&incident.ID,
-		&incident.MonitorID,
-		&incident.StartedAt,
-		&incident.ResolvedAt,
-		&incident.TTRSeconds,
-		&incident.AcknowledgedBy,
-		&incident.AcknowledgedAt,
-		&incident.Status,
-		&incident.CreatedAt,
-	)
+	incident, err := scanIncident(q.QueryRow(ctx, query, monitorID, tenantID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -145,9 +140,9 @@ func (r *IncidentRepository) GetActiveIncidents(ctx context.Context) ([]*domain.
 
 	// H-020: hard limit prevents unbounded result sets.
 	query := `
-		SELECT id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
-		WHERE tenant_id = $1 AND status IN ('open', 'acknowledged')
+		WHERE tenant_id = $1 AND status IN ('open', 'acknowledged', 'flapping')
 		ORDER BY created_at DESC
 		LIMIT 1000`
 
@@ -166,7 +161,7 @@ func (r *IncidentRepository) GetResolvedIncidents(ctx context.Context) ([]*domai
 	tenantID := TenantIDFromContext(ctx)
 
 	query := `
-		SELECT id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
 		WHERE tenant_id = $1 AND status = 'resolved'
 		ORDER BY resolved_at DESC
@@ -187,7 +182,7 @@ func (r *IncidentRepository) GetAllIncidents(ctx context.Context) ([]*domain.Inc
 	tenantID := TenantIDFromContext(ctx)
 
 	query := `
-		SELECT id, monitor_id, started_at, resolved_at, ttr_seconds, acknowledged_by, acknowledged_at, status, created_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
 		WHERE tenant_id = $1
 		ORDER BY created_at DESC
@@ -232,7 +227,7 @@ func (r *IncidentRepository) Update(ctx context.Context, incident *domain.Incide
 	return nil
 }
 
-// Acknowledge marks an incident as acknowledged by a user.
+// Acknowledge marks an open or flapping incident as acknowledged by a user.
 func (r *IncidentRepository) Acknowledge(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	q := r.db.Querier(ctx)
 	tenantID := TenantIDFromContext(ctx)
@@ -241,7 +236,7 @@ func (r *IncidentRepository) Acknowledge(ctx context.Context, id uuid.UUID, user
 	query := `
 		UPDATE incidents
 		SET status = 'acknowledged', acknowledged_by = $2, acknowledged_at = $3
-		WHERE id = $1 AND tenant_id = $4 AND status = 'open'`
+		WHERE id = $1 AND tenant_id = $4 AND status IN ('open', 'flapping')`
 
 	result, err := q.Exec(ctx, query, id, userID, now, tenantID)
 	if err != nil {
@@ -249,7 +244,7 @@ func (r *IncidentRepository) Acknowledge(ctx context.Context, id uuid.UUID, user
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("incidentRepo.Acknowledge(%s): incident not found or not open", id)
+		return fmt.Errorf("incidentRepo.Acknowledge(%s): incident not found or not open or flapping", id)
 	}
 
 	return nil
@@ -266,7 +261,7 @@ func (r *IncidentRepository) Resolve(ctx context.Context, id uuid.UUID) error {
 		SET status = 'resolved',
 		    resolved_at = NOW(),
 		    ttr_seconds = EXTRACT(EPOCH FROM (NOW() - started_at))::INT
-		WHERE id = $1 AND tenant_id = $2 AND status IN ('open', 'acknowledged')`
+		WHERE id = $1 AND tenant_id = $2 AND status IN ('open', 'acknowledged', 'flapping')`
 
 	result, err := q.Exec(ctx, query, id, tenantID)
 	if err != nil {
@@ -280,22 +275,226 @@ func (r *IncidentRepository) Resolve(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// SetFlapping moves an active incident into the flapping state, or returns a
+// flapping incident to open (or acknowledged, if it was acknowledged before).
+func (r *IncidentRepository) SetFlapping(ctx context.Context, id uuid.UUID, flapping bool) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE incidents
+		SET status = 'flapping'
+		WHERE id = $1 AND tenant_id = $2 AND status IN ('open', 'acknowledged')`
+	if !flapping {
+		query = `
+		UPDATE incidents
+		SET status = CASE WHEN acknowledged_at IS NOT NULL THEN 'acknowledged' ELSE 'open' END
+		WHERE id = $1 AND tenant_id = $2 AND status = 'flapping'`
+	}
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("incidentRepo.SetFlapping(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("incidentRepo.SetFlapping(%s): incident not found or not active", id)
+	}
+
+	return nil
+}
+
+// GetActiveByParentID retrieves active incidents suppressed under the given
+// parent incident.
+func (r *IncidentRepository) GetActiveByParentID(ctx context.Context, parentIncidentID uuid.UUID) ([]*domain.Incident, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE parent_incident_id = $1 AND tenant_id = $2 AND status IN ('open', 'acknowledged', 'flapping')
+		ORDER BY started_at
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, parentIncidentID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentRepo.GetActiveByParentID(%s): %w", parentIncidentID, err)
+	}
+	defer rows.Close()
+
+	return scanIncidents(rows)
+}
+
+// SetParent re-attaches an incident to a parent incident, or detaches it so
+// it stands on its own when parentIncidentID is nil.
+func (r *IncidentRepository) SetParent(ctx context.Context, id uuid.UUID, parentIncidentID *uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE incidents
+		SET parent_incident_id = $3
+		WHERE id = $1 AND tenant_id = $2`
+
+	if _, err := q.Exec(ctx, query, id, tenantID, parentIncidentID); err != nil {
+		return fmt.Errorf("incidentRepo.SetParent(%s): %w", id, err)
+	}
+
+	return nil
+}
+
+// SetSeverity overrides an incident's severity.
+func (r *IncidentRepository) SetSeverity(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE incidents
+		SET severity = $3
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID, severity)
+	if err != nil {
+		return fmt.Errorf("incidentRepo.SetSeverity(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("incidentRepo.SetSeverity(%s): incident not found", id)
+	}
+
+	return nil
+}
+
+// SetOwner assigns the user responsible for an incident.
+func (r *IncidentRepository) SetOwner(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE incidents
+		SET owner_id = $3
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID, ownerID)
+	if err != nil {
+		return fmt.Errorf("incidentRepo.SetOwner(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("incidentRepo.SetOwner(%s): incident not found", id)
+	}
+
+	return nil
+}
+
+// GetDeclaredAffecting retrieves declared incidents that affect any of the
+// given monitors and are still active or started at or after since, most
+// recent first.
+func (r *IncidentRepository) GetDeclaredAffecting(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error) {
+	if len(monitorIDs) == 0 {
+		return nil, nil
+	}
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	ids := make([]string, len(monitorIDs))
+	for i, id := range monitorIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE tenant_id = $1 AND kind = 'declared' AND (status <> 'resolved' OR started_at >= $2) AND affected_monitor_ids ?| $3
+		ORDER BY started_at DESC
+		LIMIT 100`
+
+	rows, err := q.Query(ctx, query, tenantID, since, ids)
+	if err != nil {
+		return nil, fmt.Errorf("incidentRepo.GetDeclaredAffecting: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIncidents(rows)
+}
+
+// incidentMonitorID stores declared incidents, which have no monitor, with
+// a NULL monitor_id.
+func incidentMonitorID(incident *domain.Incident) *uuid.UUID {
+	if incident.MonitorID == uuid.Nil {
+		return nil
+	}
+	return &incident.MonitorID
+}
+
+// incidentAffectedMonitors stores incidents without affected monitors as
+// an empty array.
+func incidentAffectedMonitors(incident *domain.Incident) []uuid.UUID {
+	if incident.AffectedMonitorIDs == nil {
+		return []uuid.UUID{}
+	}
+	return incident.AffectedMonitorIDs
+}
+
+// incidentKind defaults incidents built without a constructor to outages.
+func incidentKind(incident *domain.Incident) domain.IncidentKind {
+	if incident.Kind == "" {
+		return domain.IncidentKindDown
+	}
+	return incident.Kind
+}
+
+// incidentSeverity defaults incidents built without a constructor to the
+// default severity.
+func incidentSeverity(incident *domain.Incident) domain.IncidentSeverity {
+	if !incident.Severity.IsValid() {
+		return domain.DefaultIncidentSeverity
+	}
+	return incident.Severity
+}
+
+func scanIncident(scanner interface{ Scan(dest ...any) error }) (*domain.Incident, error) {
+	incident := &domain.Incident{}
+	var monitorID *uuid.UUID
+	var affected []byte
+	err := scanner.Scan(
+		&incident.ID,
+		&monitorID,
+		&incident.StartedAt,
+		&incident.ResolvedAt,
+		&incident.TTRSeconds,
+		&incident.AcknowledgedBy,
+		&incident.AcknowledgedAt,
+		&incident.Status,
+		&incident.CreatedAt,
+		&incident.Kind,
+		&incident.ParentIncidentID,
+		&incident.Severity,
+		&incident.AlertGroupID,
+		&incident.UserID,
+		&incident.Title,
+		&incident.Description,
+		&incident.OwnerID,
+		&affected,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if monitorID != nil {
+		incident.MonitorID = *monitorID
+	}
+	if err := json.Unmarshal(affected, &incident.AffectedMonitorIDs); err != nil {
+		return nil, fmt.Errorf("decode affected monitors: %w", err)
+	}
+	return incident, nil
+}
+
 // scanIncidents is a helper function to scan rows into incidents slice.
 func scanIncidents(rows pgx.Rows) ([]*domain.Incident, error) {
 	var incidents []*domain.Incident
 	for rows.Next() {
-		incident := &domain.Incident{}
-		err := rows.Scan(
-			&incident.ID,
-			&incident.MonitorID,
-			&incident.StartedAt,
-			&incident.ResolvedAt,
-			&incident.TTRSeconds,
-			&incident.AcknowledgedBy,
-			&incident.AcknowledgedAt,
-			&incident.Status,
-			&incident.CreatedAt,
-		)
+		incident, err := scanIncident(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan: %w", err)
 		}
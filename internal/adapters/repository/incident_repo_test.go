@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+// statusGuard matches an UPDATE's guard on the current status.
+var statusGuard = regexp.MustCompile(`status (?:= '(\w+)'|IN \(([^)]*)\))`)
+
+// incidentWithStatus is a transaction holding one incident in status: an
+// UPDATE affects it only if its status guard admits that status.
+func incidentWithStatus(status domain.IncidentStatus) *fakeTx {
+	return &fakeTx{exec: func(sql string, _ ...any) (pgconn.CommandTag, error) {
+		where := sql[strings.Index(sql, "WHERE"):]
+		m := statusGuard.FindStringSubmatch(where)
+		admitted := []string{m[1]}
+		if m[1] == "" {
+			admitted = strings.Split(strings.NewReplacer("'", "", " ", "").Replace(m[2]), ",")
+		}
+		if slices.Contains(admitted, string(status)) {
+			return pgconn.NewCommandTag("UPDATE 1"), nil
+		}
+		return pgconn.NewCommandTag("UPDATE 0"), nil
+	}}
+}
+
+func TestIncidentRepository_Acknowledge(t *testing.T) {
+	repo := NewIncidentRepository(&DB{})
+	ack := func(status domain.IncidentStatus) error {
+		ctx := context.WithValue(context.Background(), txKey{}, pgx.Tx(incidentWithStatus(status)))
+		return repo.Acknowledge(ctx, uuid.New(), uuid.New())
+	}
+
+	assert.NoError(t, ack(domain.IncidentStatusOpen))
+	assert.NoError(t, ack(domain.IncidentStatusFlapping), "a flapping incident can be acknowledged")
+	assert.Error(t, ack(domain.IncidentStatusAcknowledged))
+	assert.Error(t, ack(domain.IncidentStatusResolved))
+}
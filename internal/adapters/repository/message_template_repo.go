@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const messageTemplateColumns = "id, user_id, channel_type, channel_id, event, subject, body, created_at, updated_at"
+
+// MessageTemplateRepository implements ports.MessageTemplateRepository using PostgreSQL.
+type MessageTemplateRepository struct {
+	db *DB
+}
+
+// NewMessageTemplateRepository creates a new MessageTemplateRepository.
+func NewMessageTemplateRepository(db *DB) *MessageTemplateRepository {
+	return &MessageTemplateRepository{db: db}
+}
+
+func scanMessageTemplate(row pgx.Row) (*domain.MessageTemplate, error) {
+	t := &domain.MessageTemplate{}
+	if err := row.Scan(&t.ID, &t.UserID, &t.ChannelType, &t.ChannelID, &t.Event, &t.Subject, &t.Body, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Create inserts a new message template.
+func (r *MessageTemplateRepository) Create(ctx context.Context, t *domain.MessageTemplate) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO message_templates (id, user_id, channel_type, channel_id, event, subject, body, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := q.Exec(ctx, query, t.ID, t.UserID, t.ChannelType, t.ChannelID, t.Event, t.Subject, t.Body, tenantID, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("messageTemplateRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores a template's subject and body.
+func (r *MessageTemplateRepository) Update(ctx context.Context, t *domain.MessageTemplate) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE message_templates
+		SET subject = $1, body = $2, updated_at = $3
+		WHERE id = $4 AND tenant_id = $5`
+
+	result, err := q.Exec(ctx, query, t.Subject, t.Body, t.UpdatedAt, t.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("messageTemplateRepo.Update(%s): %w", t.ID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("messageTemplateRepo.Update(%s): template not found", t.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a message template.
+func (r *MessageTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	result, err := q.Exec(ctx, `DELETE FROM message_templates WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("messageTemplateRepo.Delete(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("messageTemplateRepo.Delete(%s): template not found", id)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a message template. Returns nil when it does not exist.
+func (r *MessageTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MessageTemplate, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + messageTemplateColumns + ` FROM message_templates WHERE id = $1 AND tenant_id = $2`
+
+	t, err := scanMessageTemplate(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("messageTemplateRepo.GetByID(%s): %w", id, err)
+	}
+
+	return t, nil
+}
+
+// GetByUserID returns a user's message templates, channel type templates
+// before channel templates.
+func (r *MessageTemplateRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.MessageTemplate, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + messageTemplateColumns + `
+		FROM message_templates
+		WHERE user_id = $1 AND tenant_id = $2
+		ORDER BY channel_type, channel_id NULLS FIRST, event
+		LIMIT 100`
+
+	rows, err := q.Query(ctx, query, userID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("messageTemplateRepo.GetByUserID: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*domain.MessageTemplate
+	for rows.Next() {
+		t, err := scanMessageTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("messageTemplateRepo.GetByUserID: scan: %w", err)
+		}
+		templates = append(templates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("messageTemplateRepo.GetByUserID: rows: %w", err)
+	}
+
+	return templates, nil
+}
+
+// Resolve returns the template a channel uses for an event: the channel's
+// own before its type's, and one for the event before one for any event.
+// Returns nil when the channel uses the built-in layout.
+func (r *MessageTemplateRepository) Resolve(ctx context.Context, channel *domain.AlertChannel, event string) (*domain.MessageTemplate, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + messageTemplateColumns + `
+		FROM message_templates
+		WHERE user_id = $1 AND tenant_id = $2 AND channel_type = $3
+		  AND (channel_id = $4 OR channel_id IS NULL)
+		  AND (event = $5 OR event = '')
+		ORDER BY channel_id IS NULL, event = ''
+		LIMIT 1`
+
+	t, err := scanMessageTemplate(q.QueryRow(ctx, query, channel.UserID, tenantID, channel.Type, channel.ID, event))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("messageTemplateRepo.Resolve(%s): %w", channel.ID, err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// MonitorCache implements ports.MonitorCache with a TTL map in front of a
+// MonitorRepository. Entries remember the tenant that loaded them and are
+// only served to that tenant, so RLS scoping is preserved. Missing monitors
+// are cached too, so agents still running a deleted monitor's task do not
+// cause a lookup per heartbeat.
+type MonitorCache struct {
+	repo ports.MonitorRepository
+	ttl  time.Duration
+
+	mu        sync.RWMutex
+	entries   map[uuid.UUID]monitorCacheEntry
+	lastPrune time.Time
+}
+
+type monitorCacheEntry struct {
+	tenantID string
+	monitor  *domain.Monitor // nil when the monitor does not exist
+	expires  time.Time
+}
+
+// NewMonitorCache creates a MonitorCache whose entries expire after ttl.
+func NewMonitorCache(repo ports.MonitorRepository, ttl time.Duration) *MonitorCache {
+	return &MonitorCache{
+		repo:      repo,
+		ttl:       ttl,
+		entries:   make(map[uuid.UUID]monitorCacheEntry),
+		lastPrune: time.Now(),
+	}
+}
+
+// Get returns the monitor with the given ID, or nil if it does not exist in
+// the context's tenant.
+func (c *MonitorCache) Get(ctx context.Context, id uuid.UUID) (*domain.Monitor, error) {
+	tenantID := TenantIDFromContext(ctx)
+	now := time.Now()
+
+	c.mu.RLock()
+	entry, ok := c.entries[id]
+	c.mu.RUnlock()
+	if ok && entry.tenantID == tenantID && now.Before(entry.expires) {
+		return entry.monitor, nil
+	}
+
+	monitor, err := c.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[id] = monitorCacheEntry{tenantID: tenantID, monitor: monitor, expires: now.Add(c.ttl)}
+	if now.Sub(c.lastPrune) >= c.ttl {
+		for key, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.lastPrune = now
+	}
+	return monitor, nil
+}
+
+// Invalidate drops the cached monitor so the next Get reloads it.
+func (c *MonitorCache) Invalidate(id uuid.UUID) {
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -12,7 +13,8 @@ import (
 	"github.com/sylvester-francis/watchdog/core/domain"
 )
 
-const monitorColumns = "id, agent_id, name, type, target, interval_seconds, timeout_seconds, status, enabled, failure_threshold, metadata, sla_target_percent, created_at"
+const monitorColumns = "id, agent_id, name, type, target, interval_seconds, timeout_seconds, status, enabled, failure_threshold, metadata, sla_target_percent, created_at, degraded_latency_ms, degraded_latency_checks, degraded_failure_percent, degraded_window, recovery_threshold, flap_window, flap_threshold_percent, quorum, push_token, push_grace_seconds, last_ping_at, push_started_at, assertions, transaction, transaction_last_run, severity, escalation_policy_id, " +
+	"ARRAY(SELECT ma.agent_id FROM monitor_agents ma WHERE ma.monitor_id = monitors.id ORDER BY ma.sort_order)"
 
 // MonitorRepository implements ports.MonitorRepository using PostgreSQL.
 type MonitorRepository struct {
@@ -27,17 +29,37 @@ func NewMonitorRepository(db *DB) *MonitorRepository {
 func scanMonitor(scanner interface{ Scan(dest ...any) error }) (*domain.Monitor, error) {
 	m := &domain.Monitor{}
 	var metadataBytes []byte
+	var pushToken *string
+	var assertionsBytes, transactionBytes, lastRunBytes []byte
 	err := scanner.Scan(
 		&m.ID, &m.AgentID, &m.Name, &m.Type, &m.Target,
 		&m.IntervalSeconds, &m.TimeoutSeconds, &m.Status, &m.Enabled, &m.FailureThreshold, &metadataBytes, &m.SLATargetPercent, &m.CreatedAt,
+		&m.DegradedLatencyMs, &m.DegradedLatencyChecks, &m.DegradedFailurePercent, &m.DegradedWindow,
+		&m.RecoveryThreshold, &m.FlapWindow, &m.FlapThresholdPercent, &m.Quorum,
+		&pushToken, &m.PushGraceSeconds, &m.LastPingAt, &m.PushStartedAt, &assertionsBytes,
+		&transactionBytes, &lastRunBytes, &m.Severity, &m.EscalationPolicyID, &m.LocationAgentIDs,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if pushToken != nil {
+		m.PushToken = *pushToken
+	}
 	m.Metadata = make(map[string]string)
 	if len(metadataBytes) > 0 {
 		_ = json.Unmarshal(metadataBytes, &m.Metadata)
 	}
+	if len(assertionsBytes) > 0 {
+		_ = json.Unmarshal(assertionsBytes, &m.Assertions)
+	}
+	if len(transactionBytes) > 0 {
+		m.Transaction = &domain.Transaction{}
+		_ = json.Unmarshal(transactionBytes, m.Transaction)
+	}
+	if len(lastRunBytes) > 0 {
+		m.LastTransactionRun = &domain.TransactionRun{}
+		_ = json.Unmarshal(lastRunBytes, m.LastTransactionRun)
+	}
 	return m, nil
 }
 
@@ -54,6 +76,102 @@ func scanMonitors(rows pgx.Rows) ([]*domain.Monitor, error) {
 	return monitors, rows.Err()
 }
 
+// degradedLatencyChecks returns the stored check count, falling back to the
+// default for monitors built without NewMonitor.
+func degradedLatencyChecks(m *domain.Monitor) int {
+	if m.DegradedLatencyChecks < domain.MinDegradedLatencyChecks {
+		return domain.DefaultDegradedLatencyChecks
+	}
+	return m.DegradedLatencyChecks
+}
+
+// degradedWindow returns the stored window size, falling back to the default
+// for monitors built without NewMonitor.
+func degradedWindow(m *domain.Monitor) int {
+	if m.DegradedWindow < domain.MinDegradedWindow {
+		return domain.DefaultDegradedWindow
+	}
+	return m.DegradedWindow
+}
+
+// pushToken stores an empty token as NULL so the unique index only covers
+// push monitors.
+func pushToken(m *domain.Monitor) *string {
+	if m.PushToken == "" {
+		return nil
+	}
+	return &m.PushToken
+}
+
+// assertionsJSON encodes the monitor's assertions, storing NULL when it has
+// none.
+func assertionsJSON(m *domain.Monitor) []byte {
+	if len(m.Assertions) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(m.Assertions)
+	return b
+}
+
+// transactionJSON encodes a transaction monitor's current definition,
+// storing NULL for other monitors.
+func transactionJSON(m *domain.Monitor) []byte {
+	if m.Transaction == nil {
+		return nil
+	}
+	b, _ := json.Marshal(m.Transaction)
+	return b
+}
+
+// saveTransactionVersion records the monitor's current transaction definition
+// in its version history. Versions are immutable, so an already stored
+// version is left alone.
+func saveTransactionVersion(ctx context.Context, q Querier, m *domain.Monitor, tenantID string) error {
+	if m.Transaction == nil {
+		return nil
+	}
+	steps, err := json.Marshal(m.Transaction.Steps)
+	if err != nil {
+		return fmt.Errorf("marshal transaction steps: %w", err)
+	}
+	_, err = q.Exec(ctx,
+		`INSERT INTO monitor_transaction_versions (monitor_id, version, steps, tenant_id)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (monitor_id, version) DO NOTHING`,
+		m.ID, m.Transaction.Version, steps, tenantID)
+	if err != nil {
+		return fmt.Errorf("save transaction version %d: %w", m.Transaction.Version, err)
+	}
+	return nil
+}
+
+// monitorSeverity returns the stored severity, falling back to the default
+// for monitors built without NewMonitor.
+func monitorSeverity(m *domain.Monitor) domain.IncidentSeverity {
+	if !m.Severity.IsValid() {
+		return domain.DefaultIncidentSeverity
+	}
+	return m.Severity
+}
+
+// recoveryThreshold returns the stored recovery threshold, falling back to
+// the default for monitors built without NewMonitor.
+func recoveryThreshold(m *domain.Monitor) int {
+	if m.RecoveryThreshold < domain.MinRecoveryThreshold {
+		return domain.DefaultRecoveryThreshold
+	}
+	return m.RecoveryThreshold
+}
+
+// flapThresholdPercent returns the stored flap threshold, falling back to
+// the default for monitors built without NewMonitor.
+func flapThresholdPercent(m *domain.Monitor) int {
+	if m.FlapThresholdPercent < 1 {
+		return domain.DefaultFlapThresholdPercent
+	}
+	return m.FlapThresholdPercent
+}
+
 // Create inserts a new monitor into the database.
 func (r *MonitorRepository) Create(ctx context.Context, monitor *domain.Monitor) error {
 	q := r.db.Querier(ctx)
@@ -65,17 +183,25 @@ func (r *MonitorRepository) Create(ctx context.Context, monitor *domain.Monitor)
 	}
 
 	query := `
-		INSERT INTO monitors (id, agent_id, name, type, target, interval_seconds, timeout_seconds, status, enabled, failure_threshold, metadata, sla_target_percent, created_at, tenant_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+		INSERT INTO monitors (id, agent_id, name, type, target, interval_seconds, timeout_seconds, status, enabled, failure_threshold, metadata, sla_target_percent, created_at, tenant_id,
+			degraded_latency_ms, degraded_latency_checks, degraded_failure_percent, degraded_window,
+			recovery_threshold, flap_window, flap_threshold_percent, quorum, push_token, push_grace_seconds, assertions, transaction, severity, escalation_policy_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)`
 
 	_, err = q.Exec(ctx, query,
 		monitor.ID, monitor.AgentID, monitor.Name, monitor.Type, monitor.Target,
 		monitor.IntervalSeconds, monitor.TimeoutSeconds, monitor.Status, monitor.Enabled, monitor.FailureThreshold, metadataJSON, monitor.SLATargetPercent, monitor.CreatedAt,
 		tenantID,
+		monitor.DegradedLatencyMs, degradedLatencyChecks(monitor), monitor.DegradedFailurePercent, degradedWindow(monitor),
+		recoveryThreshold(monitor), monitor.FlapWindow, flapThresholdPercent(monitor), monitor.Quorum,
+		pushToken(monitor), monitor.PushGraceSeconds, assertionsJSON(monitor), transactionJSON(monitor), monitorSeverity(monitor), monitor.EscalationPolicyID,
 	)
 	if err != nil {
 		return fmt.Errorf("monitorRepo.Create: %w", err)
 	}
+	if err := saveTransactionVersion(ctx, q, monitor, tenantID); err != nil {
+		return fmt.Errorf("monitorRepo.Create: %w", err)
+	}
 
 	return nil
 }
@@ -119,13 +245,18 @@ func (r *MonitorRepository) GetByAgentID(ctx context.Context, agentID uuid.UUID)
 	return monitors, nil
 }
 
-// GetEnabledByAgentID retrieves all enabled monitors for an agent.
+// GetEnabledByAgentID retrieves all enabled monitors an agent should run: the
+// monitors it owns plus any multi-location monitors it is a probe for. Push
+// monitors are never run by agents.
 func (r *MonitorRepository) GetEnabledByAgentID(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error) {
 	q := r.db.Querier(ctx)
 	tenantID := TenantIDFromContext(ctx)
 
 	// H-020: hard limit prevents unbounded result sets.
-	query := `SELECT ` + monitorColumns + ` FROM monitors WHERE agent_id = $1 AND tenant_id = $2 AND enabled = true ORDER BY created_at DESC LIMIT 1000`
+	query := `SELECT ` + monitorColumns + ` FROM monitors
+		WHERE tenant_id = $2 AND enabled = true AND type <> 'push'
+		  AND (agent_id = $1 OR id IN (SELECT monitor_id FROM monitor_agents WHERE agent_id = $1))
+		ORDER BY created_at DESC LIMIT 1000`
 
 	rows, err := q.Query(ctx, query, agentID, tenantID)
 	if err != nil {
@@ -243,13 +374,19 @@ func (r *MonitorRepository) Update(ctx context.Context, monitor *domain.Monitor)
 
 	query := `
 		UPDATE monitors
-		SET name = $2, type = $3, target = $4, interval_seconds = $5, timeout_seconds = $6, status = $7, enabled = $8, failure_threshold = $9, metadata = $10, sla_target_percent = $11, agent_id = $12
+		SET name = $2, type = $3, target = $4, interval_seconds = $5, timeout_seconds = $6, status = $7, enabled = $8, failure_threshold = $9, metadata = $10, sla_target_percent = $11, agent_id = $12,
+		    degraded_latency_ms = $14, degraded_latency_checks = $15, degraded_failure_percent = $16, degraded_window = $17,
+		    recovery_threshold = $18, flap_window = $19, flap_threshold_percent = $20, quorum = $21,
+		    push_token = $22, push_grace_seconds = $23, assertions = $24, transaction = $25, severity = $26, escalation_policy_id = $27
 		WHERE id = $1 AND tenant_id = $13`
 
 	result, err := q.Exec(ctx, query,
 		monitor.ID, monitor.Name, monitor.Type, monitor.Target,
 		monitor.IntervalSeconds, monitor.TimeoutSeconds, monitor.Status, monitor.Enabled, monitor.FailureThreshold, metadataJSON, monitor.SLATargetPercent, monitor.AgentID,
 		tenantID,
+		monitor.DegradedLatencyMs, degradedLatencyChecks(monitor), monitor.DegradedFailurePercent, degradedWindow(monitor),
+		recoveryThreshold(monitor), monitor.FlapWindow, flapThresholdPercent(monitor), monitor.Quorum,
+		pushToken(monitor), monitor.PushGraceSeconds, assertionsJSON(monitor), transactionJSON(monitor), monitorSeverity(monitor), monitor.EscalationPolicyID,
 	)
 	if err != nil {
 		return fmt.Errorf("monitorRepo.Update(%s): %w", monitor.ID, err)
@@ -258,6 +395,9 @@ func (r *MonitorRepository) Update(ctx context.Context, monitor *domain.Monitor)
 	if result.RowsAffected() == 0 {
 		return fmt.Errorf("monitorRepo.Update(%s): monitor not found", monitor.ID)
 	}
+	if err := saveTransactionVersion(ctx, q, monitor, tenantID); err != nil {
+		return fmt.Errorf("monitorRepo.Update(%s): %w", monitor.ID, err)
+	}
 
 	return nil
 }
@@ -321,6 +461,31 @@ func (r *MonitorRepository) UpdateMetadata(ctx context.Context, id uuid.UUID, me
 	return nil
 }
 
+// MergeMetadata merges keys into a monitor's metadata in place, overwriting
+// existing values and keeping the other keys.
+func (r *MonitorRepository) MergeMetadata(ctx context.Context, id uuid.UUID, metadata map[string]string) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("monitorRepo.MergeMetadata(%s): marshal: %w", id, err)
+	}
+
+	query := `UPDATE monitors SET metadata = metadata || $2::jsonb WHERE id = $1 AND tenant_id = $3`
+
+	result, err := q.Exec(ctx, query, id, metadataJSON, tenantID)
+	if err != nil {
+		return fmt.Errorf("monitorRepo.MergeMetadata(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("monitorRepo.MergeMetadata(%s): monitor not found", id)
+	}
+
+	return nil
+}
+
 // UpdateStatus updates only the status of a monitor.
 func (r *MonitorRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.MonitorStatus) error {
 	q := r.db.Querier(ctx)
@@ -339,3 +504,173 @@ func (r *MonitorRepository) UpdateStatus(ctx context.Context, id uuid.UUID, stat
 
 	return nil
 }
+
+// GetByPushTokenGlobal retrieves a push monitor by its ingest token without
+// tenant scoping. Used by the unauthenticated push endpoint, which has no
+// tenant context until the monitor is found.
+func (r *MonitorRepository) GetByPushTokenGlobal(ctx context.Context, token string) (*domain.Monitor, error) {
+	q := r.db.Querier(ctx)
+
+	query := `SELECT ` + monitorColumns + ` FROM monitors WHERE push_token = $1 AND type = 'push'`
+
+	monitor, err := scanMonitor(q.QueryRow(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("monitorRepo.GetByPushTokenGlobal: %w", err)
+	}
+
+	return monitor, nil
+}
+
+// RecordPing stores when a push monitor last pinged. A start signal only
+// marks the beginning of a run; any other signal completes it.
+func (r *MonitorRepository) RecordPing(ctx context.Context, id uuid.UUID, at time.Time, start bool) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `UPDATE monitors SET last_ping_at = $2 WHERE id = $1 AND tenant_id = $3`
+	if start {
+		query = `UPDATE monitors SET push_started_at = $2 WHERE id = $1 AND tenant_id = $3`
+	}
+
+	result, err := q.Exec(ctx, query, id, at, tenantID)
+	if err != nil {
+		return fmt.Errorf("monitorRepo.RecordPing(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("monitorRepo.RecordPing(%s): monitor not found", id)
+	}
+
+	return nil
+}
+
+// GetOverduePush retrieves enabled push monitors in the current tenant that
+// missed their deadline as of now and are not already down. The deadline
+// mirrors domain.Monitor.PushDeadline.
+func (r *MonitorRepository) GetOverduePush(ctx context.Context, now time.Time) ([]*domain.Monitor, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	// H-020: hard limit prevents unbounded result sets.
+	query := `SELECT ` + monitorColumns + ` FROM monitors
+		WHERE tenant_id = $1 AND type = 'push' AND enabled = true AND status <> 'down'
+		  AND (
+		    COALESCE(last_ping_at, created_at) + make_interval(secs => interval_seconds + push_grace_seconds) < $2
+		    OR (push_started_at > COALESCE(last_ping_at, created_at)
+		        AND push_started_at + make_interval(secs => push_grace_seconds) < $2)
+		  )
+		ORDER BY created_at LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, tenantID, now)
+	if err != nil {
+		return nil, fmt.Errorf("monitorRepo.GetOverduePush: %w", err)
+	}
+
+	monitors, err := scanMonitors(rows)
+	if err != nil {
+		return nil, fmt.Errorf("monitorRepo.GetOverduePush: %w", err)
+	}
+
+	return monitors, nil
+}
+
+// SetLocations replaces the set of probing agents for a monitor.
+// Defense-in-depth: every agent must belong to the same user and tenant as
+// the monitor's owning agent.
+func (r *MonitorRepository) SetLocations(ctx context.Context, monitorID uuid.UUID, agentIDs []uuid.UUID) error {
+	return r.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		q := r.db.Querier(txCtx)
+		tenantID := TenantIDFromContext(txCtx)
+
+		_, err := q.Exec(txCtx,
+			`DELETE FROM monitor_agents ma
+			 USING monitors m
+			 WHERE ma.monitor_id = m.id
+			   AND ma.monitor_id = $1
+			   AND m.tenant_id = $2`,
+			monitorID, tenantID)
+		if err != nil {
+			return fmt.Errorf("monitorRepo.SetLocations(%s): clear agents: %w", monitorID, err)
+		}
+
+		for i, agentID := range agentIDs {
+			result, err := q.Exec(txCtx,
+				`INSERT INTO monitor_agents (monitor_id, agent_id, sort_order)
+				 SELECT m.id, a.id, $3
+				 FROM monitors m
+				 JOIN agents owner ON owner.id = m.agent_id
+				 JOIN agents a ON a.id = $2
+				 WHERE m.id = $1
+				   AND a.user_id = owner.user_id
+				   AND m.tenant_id = $4
+				   AND a.tenant_id = $4`,
+				monitorID, agentID, i, tenantID)
+			if err != nil {
+				return fmt.Errorf("monitorRepo.SetLocations(%s): insert agent %s: %w", monitorID, agentID, err)
+			}
+			if result.RowsAffected() == 0 {
+				return fmt.Errorf("monitorRepo.SetLocations(%s): agent %s not owned by monitor owner", monitorID, agentID)
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpdateTransactionRun stores the latest reported run of a transaction monitor.
+func (r *MonitorRepository) UpdateTransactionRun(ctx context.Context, id uuid.UUID, run *domain.TransactionRun) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	runJSON, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("monitorRepo.UpdateTransactionRun(%s): marshal: %w", id, err)
+	}
+
+	result, err := q.Exec(ctx, `UPDATE monitors SET transaction_last_run = $2 WHERE id = $1 AND tenant_id = $3`, id, runJSON, tenantID)
+	if err != nil {
+		return fmt.Errorf("monitorRepo.UpdateTransactionRun(%s): %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("monitorRepo.UpdateTransactionRun(%s): monitor not found", id)
+	}
+
+	return nil
+}
+
+// GetTransactionVersions returns a transaction monitor's definitions, newest
+// first.
+func (r *MonitorRepository) GetTransactionVersions(ctx context.Context, monitorID uuid.UUID) ([]*domain.TransactionVersion, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	rows, err := q.Query(ctx,
+		`SELECT monitor_id, version, steps, created_at
+		 FROM monitor_transaction_versions
+		 WHERE monitor_id = $1 AND tenant_id = $2
+		 ORDER BY version DESC
+		 LIMIT 1000`,
+		monitorID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("monitorRepo.GetTransactionVersions(%s): %w", monitorID, err)
+	}
+	defer rows.Close()
+
+	var versions []*domain.TransactionVersion
+	for rows.Next() {
+		v := &domain.TransactionVersion{}
+		var steps []byte
+		if err := rows.Scan(&v.MonitorID, &v.Version, &steps, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("monitorRepo.GetTransactionVersions(%s): scan: %w", monitorID, err)
+		}
+		if err := json.Unmarshal(steps, &v.Steps); err != nil {
+			return nil, fmt.Errorf("monitorRepo.GetTransactionVersions(%s): unmarshal: %w", monitorID, err)
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const notificationDeliveryColumns = `id, user_id, channel_id, channel_name, channel_type, event, subject, incident_id,
+	payload, status, attempts, next_attempt_at, last_error, created_at, updated_at`
+
+// NotificationDeliveryRepository implements ports.NotificationDeliveryRepository using PostgreSQL.
+type NotificationDeliveryRepository struct {
+	db *DB
+}
+
+// NewNotificationDeliveryRepository creates a new NotificationDeliveryRepository.
+func NewNotificationDeliveryRepository(db *DB) *NotificationDeliveryRepository {
+	return &NotificationDeliveryRepository{db: db}
+}
+
+func scanNotificationDelivery(row pgx.Row) (*domain.NotificationDelivery, error) {
+	d := &domain.NotificationDelivery{}
+	var payload []byte
+	if err := row.Scan(&d.ID, &d.UserID, &d.ChannelID, &d.ChannelName, &d.ChannelType, &d.Event, &d.Subject, &d.IncidentID,
+		&payload, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(payload, &d.Payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+	return d, nil
+}
+
+// Create inserts a notification delivery.
+func (r *NotificationDeliveryRepository) Create(ctx context.Context, d *domain.NotificationDelivery) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	payload, err := json.Marshal(d.Payload)
+	if err != nil {
+		return fmt.Errorf("notificationDeliveryRepo.Create: marshal payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO notification_deliveries (id, user_id, channel_id, channel_name, channel_type, event, subject, incident_id,
+			payload, status, attempts, next_attempt_at, last_error, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err = q.Exec(ctx, query, d.ID, d.UserID, d.ChannelID, d.ChannelName, d.ChannelType, d.Event, d.Subject, d.IncidentID,
+		payload, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, tenantID, d.CreatedAt, d.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("notificationDeliveryRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores a delivery's status, attempt count and next attempt.
+func (r *NotificationDeliveryRepository) Update(ctx context.Context, d *domain.NotificationDelivery) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE notification_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, updated_at = $5
+		WHERE id = $6 AND tenant_id = $7`
+
+	result, err := q.Exec(ctx, query, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.UpdatedAt, d.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("notificationDeliveryRepo.Update(%s): %w", d.ID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("notificationDeliveryRepo.Update(%s): delivery not found", d.ID)
+	}
+
+	return nil
+}
+
+// CreateAttempt records an attempt to send a delivery.
+func (r *NotificationDeliveryRepository) CreateAttempt(ctx context.Context, a *domain.NotificationAttempt) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO notification_attempts (id, delivery_id, attempt, payload_hash, status_code, error, latency_ms, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := q.Exec(ctx, query, a.ID, a.DeliveryID, a.Attempt, a.PayloadHash, a.StatusCode, a.Error, a.LatencyMs, tenantID, a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("notificationDeliveryRepo.CreateAttempt(%s): %w", a.DeliveryID, err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a delivery. Returns nil when it does not exist.
+func (r *NotificationDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.NotificationDelivery, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + notificationDeliveryColumns + ` FROM notification_deliveries WHERE id = $1 AND tenant_id = $2`
+
+	d, err := scanNotificationDelivery(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("notificationDeliveryRepo.GetByID(%s): %w", id, err)
+	}
+
+	return d, nil
+}
+
+// GetAttempts returns a delivery's attempts, first to last.
+func (r *NotificationDeliveryRepository) GetAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*domain.NotificationAttempt, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT id, delivery_id, attempt, payload_hash, status_code, error, latency_ms, created_at
+		FROM notification_attempts
+		WHERE delivery_id = $1 AND tenant_id = $2
+		ORDER BY attempt, created_at
+		LIMIT 100`
+
+	rows, err := q.Query(ctx, query, deliveryID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("notificationDeliveryRepo.GetAttempts(%s): %w", deliveryID, err)
+	}
+	defer rows.Close()
+
+	var attempts []*domain.NotificationAttempt
+	for rows.Next() {
+		a := &domain.NotificationAttempt{}
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.Attempt, &a.PayloadHash, &a.StatusCode, &a.Error, &a.LatencyMs, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("notificationDeliveryRepo.GetAttempts(%s): scan: %w", deliveryID, err)
+		}
+		attempts = append(attempts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("notificationDeliveryRepo.GetAttempts(%s): rows: %w", deliveryID, err)
+	}
+
+	return attempts, nil
+}
+
+// List returns the deliveries a filter selects, newest first.
+func (r *NotificationDeliveryRepository) List(ctx context.Context, filter domain.NotificationDeliveryFilter) ([]*domain.NotificationDelivery, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	query := `
+		SELECT ` + notificationDeliveryColumns + `
+		FROM notification_deliveries
+		WHERE tenant_id = $1 AND (user_id = $2 OR ($3 AND user_id IS NULL))
+		  AND ($4::uuid IS NULL OR channel_id = $4)
+		  AND ($5 = '' OR status = $5)
+		ORDER BY created_at DESC
+		LIMIT $6`
+
+	rows, err := q.Query(ctx, query, tenantID, filter.UserID, filter.IncludeGlobal, filter.ChannelID, string(filter.Status), limit)
+	if err != nil {
+		return nil, fmt.Errorf("notificationDeliveryRepo.List: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.NotificationDelivery
+	for rows.Next() {
+		d, err := scanNotificationDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("notificationDeliveryRepo.List: scan: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("notificationDeliveryRepo.List: rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// GetDue returns the retrying deliveries whose next attempt is due by now,
+// oldest first.
+func (r *NotificationDeliveryRepository) GetDue(ctx context.Context, now time.Time) ([]*domain.NotificationDelivery, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + notificationDeliveryColumns + `
+		FROM notification_deliveries
+		WHERE tenant_id = $1 AND status = 'retrying' AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT 500`
+
+	rows, err := q.Query(ctx, query, tenantID, now)
+	if err != nil {
+		return nil, fmt.Errorf("notificationDeliveryRepo.GetDue: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*domain.NotificationDelivery
+	for rows.Next() {
+		d, err := scanNotificationDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("notificationDeliveryRepo.GetDue: scan: %w", err)
+		}
+		due = append(due, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("notificationDeliveryRepo.GetDue: rows: %w", err)
+	}
+
+	return due, nil
+}
+
+// DeleteBefore deletes deliveries, and their attempts, created before the
+// given time that are no longer being retried.
+func (r *NotificationDeliveryRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	result, err := q.Exec(ctx,
+		`DELETE FROM notification_deliveries WHERE tenant_id = $1 AND created_at < $2 AND status <> 'retrying'`,
+		tenantID, before)
+	if err != nil {
+		return 0, fmt.Errorf("notificationDeliveryRepo.DeleteBefore: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
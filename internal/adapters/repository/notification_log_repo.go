@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const notificationLogColumns = "id, user_id, channel_id, channel_name, event, subject, incident_id, severity, dedup_key, status, error, reported, digest_due_at, created_at"
+
+// NotificationLogRepository implements ports.NotificationLogRepository using PostgreSQL.
+type NotificationLogRepository struct {
+	db *DB
+}
+
+// NewNotificationLogRepository creates a new NotificationLogRepository.
+func NewNotificationLogRepository(db *DB) *NotificationLogRepository {
+	return &NotificationLogRepository{db: db}
+}
+
+func scanNotificationLogEntry(row pgx.Row) (*domain.NotificationLogEntry, error) {
+	e := &domain.NotificationLogEntry{}
+	err := row.Scan(&e.ID, &e.UserID, &e.ChannelID, &e.ChannelName, &e.Event, &e.Subject, &e.IncidentID,
+		&e.Severity, &e.DedupKey, &e.Status, &e.Error, &e.Reported, &e.DigestDueAt, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Create inserts a log entry.
+func (r *NotificationLogRepository) Create(ctx context.Context, entry *domain.NotificationLogEntry) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO notification_log (id, user_id, channel_id, channel_name, event, subject, incident_id, severity,
+			dedup_key, status, error, reported, digest_due_at, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err := q.Exec(ctx, query, entry.ID, entry.UserID, entry.ChannelID, entry.ChannelName, entry.Event, entry.Subject,
+		entry.IncidentID, entry.Severity, entry.DedupKey, entry.Status, entry.Error, entry.Reported, entry.DigestDueAt,
+		tenantID, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("notificationLogRepo.Create: %w", err)
+	}
+
+	return nil
+}
+
+// CountSent returns how many messages a channel has sent since a time.
+// Digest summaries count as one message each.
+func (r *NotificationLogRepository) CountSent(ctx context.Context, channelID *uuid.UUID, since time.Time) (int, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT COUNT(*) FROM notification_log
+		WHERE tenant_id = $1 AND channel_id IS NOT DISTINCT FROM $2 AND status = 'sent' AND created_at >= $3`
+
+	var n int
+	if err := q.QueryRow(ctx, query, tenantID, channelID, since).Scan(&n); err != nil {
+		return 0, fmt.Errorf("notificationLogRepo.CountSent: %w", err)
+	}
+	return n, nil
+}
+
+// HasRecent returns true if an event with the dedup key was sent, or queued
+// for a digest, through the channel since a time.
+func (r *NotificationLogRepository) HasRecent(ctx context.Context, channelID *uuid.UUID, dedupKey string, since time.Time) (bool, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM notification_log
+			WHERE tenant_id = $1 AND channel_id IS NOT DISTINCT FROM $2 AND dedup_key = $3
+				AND status IN ('sent', 'digest_pending', 'digested') AND created_at >= $4
+		)`
+
+	var exists bool
+	if err := q.QueryRow(ctx, query, tenantID, channelID, dedupKey, since).Scan(&exists); err != nil {
+		return false, fmt.Errorf("notificationLogRepo.HasRecent: %w", err)
+	}
+	return exists, nil
+}
+
+// CountUnreported returns how many of a channel's notifications were
+// suppressed and not yet counted in a message.
+func (r *NotificationLogRepository) CountUnreported(ctx context.Context, channelID *uuid.UUID) (int, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT COUNT(*) FROM notification_log
+		WHERE tenant_id = $1 AND channel_id IS NOT DISTINCT FROM $2
+			AND status IN ('rate_limited', 'deduplicated', 'quiet_hours') AND NOT reported`
+
+	var n int
+	if err := q.QueryRow(ctx, query, tenantID, channelID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("notificationLogRepo.CountUnreported: %w", err)
+	}
+	return n, nil
+}
+
+// MarkReported marks a channel's suppressed notifications up to a time as
+// counted in a message.
+func (r *NotificationLogRepository) MarkReported(ctx context.Context, channelID *uuid.UUID, before time.Time) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE notification_log SET reported = TRUE
+		WHERE tenant_id = $1 AND channel_id IS NOT DISTINCT FROM $2
+			AND status IN ('rate_limited', 'deduplicated', 'quiet_hours') AND NOT reported AND created_at <= $3`
+
+	if _, err := q.Exec(ctx, query, tenantID, channelID, before); err != nil {
+		return fmt.Errorf("notificationLogRepo.MarkReported: %w", err)
+	}
+	return nil
+}
+
+// GetDigestDueAt returns when the channel's pending digest is sent, or nil
+// when it has none.
+func (r *NotificationLogRepository) GetDigestDueAt(ctx context.Context, channelID *uuid.UUID) (*time.Time, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT MIN(digest_due_at) FROM notification_log
+		WHERE tenant_id = $1 AND channel_id IS NOT DISTINCT FROM $2 AND status = 'digest_pending'`
+
+	var due *time.Time
+	if err := q.QueryRow(ctx, query, tenantID, channelID).Scan(&due); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("notificationLogRepo.GetDigestDueAt: %w", err)
+	}
+	return due, nil
+}
+
+// GetDueDigests returns the pending entries of every digest due by now,
+// oldest first.
+func (r *NotificationLogRepository) GetDueDigests(ctx context.Context, now time.Time) ([]*domain.NotificationLogEntry, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + notificationLogColumns + `
+		FROM notification_log
+		WHERE tenant_id = $1 AND status = 'digest_pending' AND digest_due_at <= $2
+		ORDER BY created_at
+		LIMIT 1000`
+
+	rows, err := q.Query(ctx, query, tenantID, now)
+	if err != nil {
+		return nil, fmt.Errorf("notificationLogRepo.GetDueDigests: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.NotificationLogEntry
+	for rows.Next() {
+		e, err := scanNotificationLogEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("notificationLogRepo.GetDueDigests: scan: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("notificationLogRepo.GetDueDigests: rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkDigested marks pending entries as sent in a digest.
+func (r *NotificationLogRepository) MarkDigested(ctx context.Context, ids []uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		UPDATE notification_log SET status = 'digested'
+		WHERE tenant_id = $1 AND id = ANY($2) AND status = 'digest_pending'`
+
+	if _, err := q.Exec(ctx, query, tenantID, ids); err != nil {
+		return fmt.Errorf("notificationLogRepo.MarkDigested: %w", err)
+	}
+	return nil
+}
+
+// DeleteBefore removes entries older than a time, keeping pending digests.
+func (r *NotificationLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM notification_log WHERE tenant_id = $1 AND created_at < $2 AND status <> 'digest_pending'`
+
+	result, err := q.Exec(ctx, query, tenantID, before)
+	if err != nil {
+		return 0, fmt.Errorf("notificationLogRepo.DeleteBefore: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+)
+
+const (
+	onCallScheduleColumns = "id, user_id, name, timezone, layers, created_at, updated_at"
+	onCallOverrideColumns = "id, schedule_id, user_id, starts_at, ends_at, created_at"
+)
+
+// OnCallRepository implements ports.OnCallRepository using PostgreSQL.
+type OnCallRepository struct {
+	db *DB
+}
+
+// NewOnCallRepository creates a new OnCallRepository.
+func NewOnCallRepository(db *DB) *OnCallRepository {
+	return &OnCallRepository{db: db}
+}
+
+func scanOnCallSchedule(row pgx.Row) (*domain.OnCallSchedule, error) {
+	s := &domain.OnCallSchedule{}
+	var layers []byte
+	if err := row.Scan(&s.ID, &s.UserID, &s.Name, &s.Timezone, &layers, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(layers, &s.Layers); err != nil {
+		return nil, fmt.Errorf("decode layers: %w", err)
+	}
+	return s, nil
+}
+
+func scanOnCallOverride(row pgx.Row) (*domain.OnCallOverride, error) {
+	o := &domain.OnCallOverride{}
+	if err := row.Scan(&o.ID, &o.ScheduleID, &o.UserID, &o.StartsAt, &o.EndsAt, &o.CreatedAt); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// CreateSchedule inserts a new on-call schedule.
+func (r *OnCallRepository) CreateSchedule(ctx context.Context, s *domain.OnCallSchedule) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	layers, err := json.Marshal(s.Layers)
+	if err != nil {
+		return fmt.Errorf("onCallRepo.CreateSchedule: encode layers: %w", err)
+	}
+
+	query := `
+		INSERT INTO oncall_schedules (id, user_id, name, timezone, layers, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = q.Exec(ctx, query, s.ID, s.UserID, s.Name, s.Timezone, layers, tenantID, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("onCallRepo.CreateSchedule: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSchedule stores a schedule's name, timezone and layers.
+func (r *OnCallRepository) UpdateSchedule(ctx context.Context, s *domain.OnCallSchedule) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	layers, err := json.Marshal(s.Layers)
+	if err != nil {
+		return fmt.Errorf("onCallRepo.UpdateSchedule(%s): encode layers: %w", s.ID, err)
+	}
+
+	query := `
+		UPDATE oncall_schedules
+		SET name = $1, timezone = $2, layers = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6`
+
+	result, err := q.Exec(ctx, query, s.Name, s.Timezone, layers, s.UpdatedAt, s.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("onCallRepo.UpdateSchedule(%s): %w", s.ID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("onCallRepo.UpdateSchedule(%s): schedule not found", s.ID)
+	}
+
+	return nil
+}
+
+// DeleteSchedule removes an on-call schedule and its overrides.
+func (r *OnCallRepository) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM oncall_schedules WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("onCallRepo.DeleteSchedule(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("onCallRepo.DeleteSchedule(%s): schedule not found", id)
+	}
+
+	return nil
+}
+
+// GetScheduleByID retrieves an on-call schedule. Returns nil when it does not exist.
+func (r *OnCallRepository) GetScheduleByID(ctx context.Context, id uuid.UUID) (*domain.OnCallSchedule, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + onCallScheduleColumns + ` FROM oncall_schedules WHERE id = $1 AND tenant_id = $2`
+
+	s, err := scanOnCallSchedule(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("onCallRepo.GetScheduleByID(%s): %w", id, err)
+	}
+
+	return s, nil
+}
+
+// GetSchedulesByUserID returns a user's on-call schedules ordered by name.
+func (r *OnCallRepository) GetSchedulesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.OnCallSchedule, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + onCallScheduleColumns + `
+		FROM oncall_schedules
+		WHERE user_id = $1 AND tenant_id = $2
+		ORDER BY name
+		LIMIT 100`
+
+	rows, err := q.Query(ctx, query, userID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("onCallRepo.GetSchedulesByUserID: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*domain.OnCallSchedule
+	for rows.Next() {
+		s, err := scanOnCallSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("onCallRepo.GetSchedulesByUserID: scan: %w", err)
+		}
+		schedules = append(schedules, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("onCallRepo.GetSchedulesByUserID: rows: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// CreateOverride inserts a new on-call override.
+func (r *OnCallRepository) CreateOverride(ctx context.Context, o *domain.OnCallOverride) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		INSERT INTO oncall_overrides (id, schedule_id, user_id, starts_at, ends_at, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := q.Exec(ctx, query, o.ID, o.ScheduleID, o.UserID, o.StartsAt, o.EndsAt, tenantID, o.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("onCallRepo.CreateOverride: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteOverride removes an on-call override.
+func (r *OnCallRepository) DeleteOverride(ctx context.Context, id uuid.UUID) error {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `DELETE FROM oncall_overrides WHERE id = $1 AND tenant_id = $2`
+
+	result, err := q.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("onCallRepo.DeleteOverride(%s): %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("onCallRepo.DeleteOverride(%s): override not found", id)
+	}
+
+	return nil
+}
+
+// GetOverrideByID retrieves an on-call override. Returns nil when it does not exist.
+func (r *OnCallRepository) GetOverrideByID(ctx context.Context, id uuid.UUID) (*domain.OnCallOverride, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `SELECT ` + onCallOverrideColumns + ` FROM oncall_overrides WHERE id = $1 AND tenant_id = $2`
+
+	o, err := scanOnCallOverride(q.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("onCallRepo.GetOverrideByID(%s): %w", id, err)
+	}
+
+	return o, nil
+}
+
+// GetOverrides returns a schedule's overrides that overlap [from, to),
+// ordered by start.
+func (r *OnCallRepository) GetOverrides(ctx context.Context, scheduleID uuid.UUID, from, to time.Time) ([]*domain.OnCallOverride, error) {
+	q := r.db.Querier(ctx)
+	tenantID := TenantIDFromContext(ctx)
+
+	query := `
+		SELECT ` + onCallOverrideColumns + `
+		FROM oncall_overrides
+		WHERE schedule_id = $1 AND tenant_id = $2 AND starts_at < $4 AND ends_at > $3
+		ORDER BY starts_at
+		LIMIT 500`
+
+	rows, err := q.Query(ctx, query, scheduleID, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("onCallRepo.GetOverrides(%s): %w", scheduleID, err)
+	}
+	defer rows.Close()
+
+	var overrides []*domain.OnCallOverride
+	for rows.Next() {
+		o, err := scanOnCallOverride(rows)
+		if err != nil {
+			return nil, fmt.Errorf("onCallRepo.GetOverrides(%s): scan: %w", scheduleID, err)
+		}
+		overrides = append(overrides, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("onCallRepo.GetOverrides(%s): rows: %w", scheduleID, err)
+	}
+
+	return overrides, nil
+}
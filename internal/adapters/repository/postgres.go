@@ -111,7 +111,19 @@ func (db *DB) Stats() *pgxpool.Stat {
 // If fn returns an error, the transaction is rolled back.
 // If fn succeeds, the transaction is committed.
 // Sets the RLS tenant context (app.tenant_id) for the transaction.
+//
+// Called within another transaction, fn runs in a savepoint of it instead,
+// so it sees the outer transaction's uncommitted writes and its own writes
+// commit or roll back with the outer transaction.
 func (db *DB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if outer, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		tx, err := outer.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("begin savepoint: %w", err)
+		}
+		return runTransaction(ctx, tx, fn)
+	}
+
 	tx, err := db.Pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -125,6 +137,12 @@ func (db *DB) WithTransaction(ctx context.Context, fn func(ctx context.Context)
 		return fmt.Errorf("set tenant context: %w", err)
 	}
 
+	return runTransaction(ctx, tx, fn)
+}
+
+// runTransaction executes fn with tx in its context, then commits tx, or
+// rolls it back if fn fails.
+func runTransaction(ctx context.Context, tx pgx.Tx, fn func(ctx context.Context) error) error {
 	// Inject transaction into context
 	txCtx := context.WithValue(ctx, txKey{}, tx)
 
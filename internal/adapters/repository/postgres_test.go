@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTx records how a transaction ends; Begin opens a savepoint of it.
+// Exec runs exec, if set.
+type fakeTx struct {
+	pgx.Tx
+	exec       func(sql string, args ...any) (pgconn.CommandTag, error)
+	savepoints []*fakeTx
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return tx.exec(sql, args...)
+}
+
+func (tx *fakeTx) Begin(_ context.Context) (pgx.Tx, error) {
+	sp := &fakeTx{}
+	tx.savepoints = append(tx.savepoints, sp)
+	return sp, nil
+}
+
+func (tx *fakeTx) Commit(_ context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback(_ context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+func TestDB_WithTransaction_Nested(t *testing.T) {
+	// No pool: a nested transaction must not begin one of its own.
+	db := &DB{}
+	outer := &fakeTx{}
+	ctx := context.WithValue(context.Background(), txKey{}, pgx.Tx(outer))
+
+	err := db.WithTransaction(ctx, func(txCtx context.Context) error {
+		require.Len(t, outer.savepoints, 1)
+		assert.Same(t, outer.savepoints[0], db.Querier(txCtx), "runs in a savepoint of the outer transaction")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, outer.savepoints[0].committed)
+	assert.False(t, outer.committed, "the outer transaction commits on its own")
+
+	failed := errors.New("not owned")
+	err = db.WithTransaction(ctx, func(context.Context) error { return failed })
+	assert.ErrorIs(t, err, failed)
+	require.Len(t, outer.savepoints, 2)
+	assert.True(t, outer.savepoints[1].rolledBack)
+	assert.False(t, outer.rolledBack, "only the savepoint is rolled back")
+}
@@ -14,6 +14,7 @@ type Config struct {
 	Crypto    CryptoConfig
 	Notify    NotifyConfig
 	Feature   FeatureConfig
+	Ingest    IngestConfig
 	Telemetry TelemetryConfig
 }
 
@@ -41,6 +42,37 @@ type TelemetryConfig struct {
 type FeatureConfig struct {
 	DurableAlerts         bool   `envconfig:"WATCHDOG_DURABLE_ALERTS" default:"false"`
 	AgentUpdateManifestURL string `envconfig:"AGENT_UPDATE_MANIFEST_URL"`
+
+	// Hub prober: runs hub agents' checks from the hub itself. Opt-in, since
+	// any tenant's hub agent then connects out from the hub's network; checks
+	// never reach private, loopback or link-local addresses.
+	HubProber bool `envconfig:"WATCHDOG_HUB_PROBER" default:"false"`
+
+	// Latency anomaly detection: sigma is how far p95 may deviate from the
+	// learned baseline; action is "incident" (alert) or "degraded" (silent).
+	// Opt-in, so upgrading does not start alerting on latency.
+	AnomalyDetection bool    `envconfig:"WATCHDOG_ANOMALY_DETECTION" default:"false"`
+	AnomalySigma     float64 `envconfig:"WATCHDOG_ANOMALY_SIGMA" default:"3"`
+	AnomalyAction    string  `envconfig:"WATCHDOG_ANOMALY_ACTION" default:"incident"`
+
+	// Alert grouping: incidents that share an agent, dependency, subnet or
+	// tag and open within the window of each other are announced together.
+	// Off by default: grouping holds every new incident for the window.
+	AlertGrouping    bool          `envconfig:"WATCHDOG_ALERT_GROUPING" default:"false"`
+	AlertGroupWindow time.Duration `envconfig:"WATCHDOG_ALERT_GROUP_WINDOW" default:"30s"`
+}
+
+// IngestConfig sizes the buffered heartbeat ingest pipeline. Each tenant
+// gets a queue of QueueSize heartbeats, flushed to the database in batches of
+// up to BatchSize every FlushInterval. Monitors read while processing
+// heartbeats are cached for MonitorCacheTTL. Set WATCHDOG_INGEST_BUFFERED=false
+// to store every heartbeat on the agent's connection instead.
+type IngestConfig struct {
+	Buffered        bool          `envconfig:"WATCHDOG_INGEST_BUFFERED" default:"true"`
+	QueueSize       int           `envconfig:"WATCHDOG_INGEST_QUEUE_SIZE" default:"10000"`
+	BatchSize       int           `envconfig:"WATCHDOG_INGEST_BATCH_SIZE" default:"500"`
+	FlushInterval   time.Duration `envconfig:"WATCHDOG_INGEST_FLUSH_INTERVAL" default:"1s"`
+	MonitorCacheTTL time.Duration `envconfig:"WATCHDOG_MONITOR_CACHE_TTL" default:"30s"`
 }
 
 // NotifyConfig holds notification configuration.
@@ -66,6 +98,12 @@ type NotifyConfig struct {
 
 	// PagerDuty
 	PagerDutyRoutingKey string `envconfig:"PAGERDUTY_ROUTING_KEY"`
+
+	// Throttling of the notifiers above; alert channels set their own in
+	// their config. Zero turns a limit off.
+	RateLimitPerHour int           `envconfig:"NOTIFICATION_RATE_LIMIT_PER_HOUR"`
+	DedupWindow      time.Duration `envconfig:"NOTIFICATION_DEDUP_WINDOW"`
+	DigestInterval   time.Duration `envconfig:"NOTIFICATION_DIGEST_INTERVAL"`
 }
 
 // ServerConfig holds HTTP server configuration.
@@ -128,5 +166,31 @@ func (c *Config) validate() error {
 		return fmt.Errorf("SESSION_SECRET must be at least 32 bytes")
 	}
 
+	if c.Feature.AnomalySigma <= 0 {
+		return fmt.Errorf("WATCHDOG_ANOMALY_SIGMA must be greater than 0")
+	}
+
+	switch c.Feature.AnomalyAction {
+	case "incident", "degraded":
+	default:
+		return fmt.Errorf("WATCHDOG_ANOMALY_ACTION must be incident or degraded")
+	}
+
+	if c.Ingest.QueueSize < 1 || c.Ingest.BatchSize < 1 {
+		return fmt.Errorf("WATCHDOG_INGEST_QUEUE_SIZE and WATCHDOG_INGEST_BATCH_SIZE must be at least 1")
+	}
+
+	if c.Ingest.FlushInterval <= 0 || c.Ingest.MonitorCacheTTL <= 0 {
+		return fmt.Errorf("WATCHDOG_INGEST_FLUSH_INTERVAL and WATCHDOG_MONITOR_CACHE_TTL must be greater than 0")
+	}
+
+	if c.Feature.AlertGroupWindow <= 0 {
+		return fmt.Errorf("WATCHDOG_ALERT_GROUP_WINDOW must be greater than 0")
+	}
+
+	if c.Notify.RateLimitPerHour < 0 || c.Notify.DedupWindow < 0 || c.Notify.DigestInterval < 0 {
+		return fmt.Errorf("NOTIFICATION_RATE_LIMIT_PER_HOUR, NOTIFICATION_DEDUP_WINDOW and NOTIFICATION_DIGEST_INTERVAL must not be negative")
+	}
+
 	return nil
 }
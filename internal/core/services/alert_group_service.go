@@ -0,0 +1,464 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// IncidentAnnouncer sends an incident's notifications. Implemented by
+// *IncidentService.
+type IncidentAnnouncer interface {
+	AnnounceIncident(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor, opened bool)
+}
+
+// AlertGroupService correlates incidents opened close together into alert
+// groups. A new incident is held in a pending group for one window; when
+// the window ends, a group of one is announced as a plain incident and a
+// larger group by one notification for its lead incident. The group's
+// recovery is announced once all of its incidents have resolved.
+type AlertGroupService struct {
+	groupRepo      ports.AlertGroupRepository
+	monitorRepo    ports.MonitorRepository
+	agentRepo      ports.AgentRepository
+	dependencyRepo ports.DependencyRepository // optional: correlate by shared dependency
+	announcer      IncidentAnnouncer
+	window         time.Duration
+	logger         *slog.Logger
+
+	// mu serializes grouping so incidents opened together cannot start
+	// separate groups.
+	mu sync.Mutex
+}
+
+// NewAlertGroupService creates a new AlertGroupService. A window of zero or
+// less uses domain.DefaultAlertGroupWindow.
+func NewAlertGroupService(
+	groupRepo ports.AlertGroupRepository,
+	monitorRepo ports.MonitorRepository,
+	agentRepo ports.AgentRepository,
+	window time.Duration,
+	logger *slog.Logger,
+) *AlertGroupService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if window <= 0 {
+		window = domain.DefaultAlertGroupWindow
+	}
+	return &AlertGroupService{
+		groupRepo:   groupRepo,
+		monitorRepo: monitorRepo,
+		agentRepo:   agentRepo,
+		window:      window,
+		logger:      logger,
+	}
+}
+
+// SetDependencyRepo correlates incidents on monitors that share a dependency.
+func (s *AlertGroupService) SetDependencyRepo(repo ports.DependencyRepository) {
+	s.dependencyRepo = repo
+}
+
+// SetAnnouncer sets where group notifications are sent. Must be called
+// before incidents are grouped.
+func (s *AlertGroupService) SetAnnouncer(announcer IncidentAnnouncer) {
+	s.announcer = announcer
+}
+
+// GroupIncident adds a newly opened incident to the alert group it
+// correlates with, or starts a pending group for it. Returns false when
+// grouping failed, in which case the caller announces the incident itself.
+func (s *AlertGroupService) GroupIncident(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) bool {
+	agent, err := s.agentRepo.GetByID(ctx, monitor.AgentID)
+	if err != nil || agent == nil {
+		s.logger.Error("alert group: failed to get agent", "agent_id", monitor.AgentID, "error", err)
+		return false
+	}
+	var parentIDs []uuid.UUID
+	if s.dependencyRepo != nil {
+		if parentIDs, err = s.dependencyRepo.GetParentIDs(ctx, monitor.ID); err != nil {
+			s.logger.Warn("alert group: failed to get monitor dependencies", "monitor_id", monitor.ID, "error", err)
+		}
+	}
+	keys := domain.CorrelationKeys(monitor, parentIDs)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := incident.StartedAt
+	groups, err := s.groupRepo.GetAccepting(ctx, agent.UserID, at.Add(-s.window))
+	if err != nil {
+		s.logger.Error("alert group: failed to get open groups", "user_id", agent.UserID, "error", err)
+		return false
+	}
+
+	var group *domain.AlertGroup
+	for _, g := range groups {
+		if !g.Accepts(at, s.window) {
+			continue
+		}
+		if key, ok := g.Match(keys); ok {
+			group = g
+			if g.Reason == "" {
+				g.Reason, g.Title = s.describe(ctx, key)
+			}
+			g.Add(keys, at)
+			if err := s.groupRepo.Update(ctx, g); err != nil {
+				s.logger.Error("alert group: failed to update group", "group_id", g.ID, "error", err)
+				return false
+			}
+			break
+		}
+	}
+	if group == nil {
+		group = domain.NewAlertGroup(agent.UserID, keys, at)
+		if err := s.groupRepo.Create(ctx, group); err != nil {
+			s.logger.Error("alert group: failed to create group", "incident_id", incident.ID, "error", err)
+			return false
+		}
+	}
+
+	if err := s.groupRepo.SetIncidentGroup(ctx, []uuid.UUID{incident.ID}, &group.ID); err != nil {
+		s.logger.Error("alert group: failed to add incident", "incident_id", incident.ID, "group_id", group.ID, "error", err)
+		return false
+	}
+	incident.AlertGroupID = &group.ID
+
+	s.logger.Info("incident added to alert group",
+		"incident_id", incident.ID,
+		"group_id", group.ID,
+		"reason", group.Reason,
+		"pending", group.IsPending(),
+	)
+	return true
+}
+
+// IncidentResolved settles the group of a resolved incident. Returns true
+// when the group speaks for the incident, which then sends no resolved
+// notification of its own.
+func (s *AlertGroupService) IncidentResolved(ctx context.Context, incident *domain.Incident) bool {
+	if incident.AlertGroupID == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group, err := s.groupRepo.GetByID(ctx, *incident.AlertGroupID)
+	if err != nil {
+		s.logger.Error("alert group: failed to get group", "group_id", *incident.AlertGroupID, "error", err)
+		return false
+	}
+	if group == nil {
+		return false
+	}
+	// The group decides when it is announced; until then, a recovery
+	// within the window is never announced at all.
+	if group.IsPending() {
+		return true
+	}
+	s.settle(ctx, group)
+	return true
+}
+
+// ProcessDue announces pending groups whose window has ended. Returns how
+// many groups were processed.
+func (s *AlertGroupService) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups, err := s.groupRepo.GetDue(ctx, now.Add(-s.window))
+	if err != nil {
+		return 0, fmt.Errorf("alertGroupService.ProcessDue: %w", err)
+	}
+	for _, group := range groups {
+		if err := s.flush(ctx, group, now); err != nil {
+			s.logger.Error("alert group: failed to announce group", "group_id", group.ID, "error", err)
+		}
+	}
+	return len(groups), nil
+}
+
+// flush announces a pending group whose window has ended.
+func (s *AlertGroupService) flush(ctx context.Context, group *domain.AlertGroup, now time.Time) error {
+	incidents, err := s.groupRepo.GetIncidents(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+
+	// A lone incident is announced as itself and leaves no group behind.
+	if len(incidents) <= 1 {
+		if err := s.groupRepo.Delete(ctx, group.ID); err != nil {
+			return err
+		}
+		if len(incidents) == 1 && incidents[0].IsActive() {
+			incidents[0].AlertGroupID = nil
+			s.announce(ctx, incidents[0], true)
+		}
+		return nil
+	}
+
+	group.NotifiedAt = &now
+	lead := firstActive(incidents)
+	if lead == nil {
+		// Everything recovered within the window: nothing to announce.
+		if err := s.groupRepo.Update(ctx, group); err != nil {
+			return err
+		}
+		_, err := s.groupRepo.Resolve(ctx, group.ID)
+		return err
+	}
+	group.LeadIncidentID = &lead.ID
+	if err := s.groupRepo.Update(ctx, group); err != nil {
+		return err
+	}
+	s.announceGroup(ctx, group, incidents, lead, true)
+	return nil
+}
+
+// settle resolves an announced group once none of its incidents is active
+// and announces its recovery.
+func (s *AlertGroupService) settle(ctx context.Context, group *domain.AlertGroup) {
+	if !group.IsOpen() || group.IsPending() {
+		return
+	}
+	incidents, err := s.groupRepo.GetIncidents(ctx, group.ID)
+	if err != nil {
+		s.logger.Error("alert group: failed to get incidents", "group_id", group.ID, "error", err)
+		return
+	}
+	if len(incidents) == 0 || firstActive(incidents) != nil {
+		return
+	}
+	resolved, err := s.groupRepo.Resolve(ctx, group.ID)
+	if err != nil {
+		s.logger.Error("alert group: failed to resolve group", "group_id", group.ID, "error", err)
+		return
+	}
+	if !resolved {
+		return
+	}
+
+	lead := incidents[0]
+	if group.LeadIncidentID != nil {
+		for _, inc := range incidents {
+			if inc.ID == *group.LeadIncidentID {
+				lead = inc
+			}
+		}
+	}
+	s.announceGroup(ctx, group, incidents, lead, false)
+}
+
+// announceGroup sends the lead incident's notification on behalf of the
+// group, summarizing the group's other incidents.
+func (s *AlertGroupService) announceGroup(ctx context.Context, group *domain.AlertGroup, incidents []*domain.Incident, lead *domain.Incident, opened bool) {
+	summary := &domain.AlertGroupSummary{ID: group.ID, Title: group.Title, Total: len(incidents)}
+	for _, inc := range incidents {
+		if len(summary.Monitors) == domain.MaxAlertGroupSummaryMonitors {
+			break
+		}
+		if m, err := s.monitorRepo.GetByID(ctx, inc.MonitorID); err == nil && m != nil {
+			summary.Monitors = append(summary.Monitors, m.Name)
+		}
+	}
+	lead.AlertContext = &domain.AlertContext{Group: summary}
+	s.announce(ctx, lead, opened)
+}
+
+// announce sends an incident's notifications.
+func (s *AlertGroupService) announce(ctx context.Context, incident *domain.Incident, opened bool) {
+	if s.announcer == nil {
+		return
+	}
+	monitor, err := s.monitorRepo.GetByID(ctx, incident.MonitorID)
+	if err != nil || monitor == nil {
+		s.logger.Error("alert group: failed to get monitor", "monitor_id", incident.MonitorID, "error", err)
+		return
+	}
+	s.announcer.AnnounceIncident(ctx, incident, monitor, opened)
+}
+
+// describe returns the reason and title of a group formed on a key.
+func (s *AlertGroupService) describe(ctx context.Context, key string) (domain.AlertGroupReason, string) {
+	reason, value := domain.CorrelationKeyReason(key)
+	name := value
+	switch reason {
+	case domain.AlertGroupReasonAgent:
+		if id, err := uuid.Parse(value); err == nil {
+			if agent, err := s.agentRepo.GetByID(ctx, id); err == nil && agent != nil {
+				name = agent.Name
+			}
+		}
+	case domain.AlertGroupReasonDependency:
+		if id, err := uuid.Parse(value); err == nil {
+			if m, err := s.monitorRepo.GetByID(ctx, id); err == nil && m != nil {
+				name = m.Name
+			}
+		}
+	}
+	return reason, string(reason) + " " + name
+}
+
+// ListGroups returns a user's alert groups. An empty status lists all.
+func (s *AlertGroupService) ListGroups(ctx context.Context, userID uuid.UUID, status domain.AlertGroupStatus) ([]*domain.AlertGroup, error) {
+	groups, err := s.groupRepo.GetByUserID(ctx, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("alertGroupService.ListGroups: %w", err)
+	}
+	return groups, nil
+}
+
+// GetGroup returns an alert group, or nil when it does not exist.
+func (s *AlertGroupService) GetGroup(ctx context.Context, id uuid.UUID) (*domain.AlertGroup, error) {
+	group, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("alertGroupService.GetGroup: %w", err)
+	}
+	return group, nil
+}
+
+// GetIncidents returns a group's incidents.
+func (s *AlertGroupService) GetIncidents(ctx context.Context, groupID uuid.UUID) ([]*domain.Incident, error) {
+	incidents, err := s.groupRepo.GetIncidents(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("alertGroupService.GetIncidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// Merge moves the incidents of the source groups into the target group and
+// deletes the sources. If any of the groups was announced, so is the result.
+func (s *AlertGroupService) Merge(ctx context.Context, target *domain.AlertGroup, sources []*domain.AlertGroup) error {
+	if len(sources) == 0 {
+		return fmt.Errorf("%w: no groups to merge", domain.ErrInvalidAlertGroupChange)
+	}
+	for _, g := range append([]*domain.AlertGroup{target}, sources...) {
+		if !g.IsOpen() {
+			return fmt.Errorf("%w: group %s is resolved", domain.ErrInvalidAlertGroupChange, g.ID)
+		}
+	}
+	for _, source := range sources {
+		if source.ID == target.ID {
+			return fmt.Errorf("%w: cannot merge a group into itself", domain.ErrInvalidAlertGroupChange)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, source := range sources {
+		incidents, err := s.groupRepo.GetIncidents(ctx, source.ID)
+		if err != nil {
+			return fmt.Errorf("alertGroupService.Merge: %w", err)
+		}
+		ids := make([]uuid.UUID, len(incidents))
+		for i, inc := range incidents {
+			ids[i] = inc.ID
+		}
+		if err := s.groupRepo.SetIncidentGroup(ctx, ids, &target.ID); err != nil {
+			return fmt.Errorf("alertGroupService.Merge: %w", err)
+		}
+		if err := s.groupRepo.Delete(ctx, source.ID); err != nil {
+			return fmt.Errorf("alertGroupService.Merge: %w", err)
+		}
+
+		target.Add(source.Keys, source.LastIncidentAt)
+		if target.NotifiedAt == nil && source.NotifiedAt != nil {
+			target.NotifiedAt, target.LeadIncidentID = source.NotifiedAt, source.LeadIncidentID
+		}
+	}
+	if target.Reason == "" {
+		target.Reason, target.Title = domain.AlertGroupReasonManual, "merged incidents"
+	}
+	if err := s.groupRepo.Update(ctx, target); err != nil {
+		return fmt.Errorf("alertGroupService.Merge: %w", err)
+	}
+	s.settle(ctx, target)
+	return nil
+}
+
+// Split moves the given incidents out of a group into a new one, which
+// takes no new incidents. At least one incident must stay behind.
+func (s *AlertGroupService) Split(ctx context.Context, group *domain.AlertGroup, incidentIDs []uuid.UUID) (*domain.AlertGroup, error) {
+	if !group.IsOpen() {
+		return nil, fmt.Errorf("%w: group is resolved", domain.ErrInvalidAlertGroupChange)
+	}
+	if len(incidentIDs) == 0 {
+		return nil, fmt.Errorf("%w: no incidents to split off", domain.ErrInvalidAlertGroupChange)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incidents, err := s.groupRepo.GetIncidents(ctx, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("alertGroupService.Split: %w", err)
+	}
+	requested := make(map[uuid.UUID]bool, len(incidentIDs))
+	for _, id := range incidentIDs {
+		requested[id] = true
+	}
+	var moved, kept []*domain.Incident
+	for _, inc := range incidents {
+		if requested[inc.ID] {
+			moved = append(moved, inc)
+		} else {
+			kept = append(kept, inc)
+		}
+	}
+	if len(moved) != len(requested) {
+		return nil, fmt.Errorf("%w: incidents must belong to the group", domain.ErrInvalidAlertGroupChange)
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("%w: at least one incident must stay in the group", domain.ErrInvalidAlertGroupChange)
+	}
+
+	split := domain.NewAlertGroup(group.UserID, nil, group.CreatedAt)
+	split.Reason, split.Title = domain.AlertGroupReasonManual, "split from "+group.Title
+	split.LastIncidentAt = group.LastIncidentAt
+	split.NotifiedAt = group.NotifiedAt
+	if split.NotifiedAt != nil {
+		split.LeadIncidentID = &moved[0].ID
+	}
+	if err := s.groupRepo.Create(ctx, split); err != nil {
+		return nil, fmt.Errorf("alertGroupService.Split: %w", err)
+	}
+	ids := make([]uuid.UUID, len(moved))
+	for i, inc := range moved {
+		ids[i] = inc.ID
+	}
+	if err := s.groupRepo.SetIncidentGroup(ctx, ids, &split.ID); err != nil {
+		return nil, fmt.Errorf("alertGroupService.Split: %w", err)
+	}
+
+	if group.LeadIncidentID != nil && slices.Contains(ids, *group.LeadIncidentID) {
+		group.LeadIncidentID = &kept[0].ID
+		if err := s.groupRepo.Update(ctx, group); err != nil {
+			return nil, fmt.Errorf("alertGroupService.Split: %w", err)
+		}
+	}
+	s.settle(ctx, group)
+	s.settle(ctx, split)
+	split.IncidentCount = len(moved)
+	return split, nil
+}
+
+// firstActive returns the earliest active incident, or nil.
+func firstActive(incidents []*domain.Incident) *domain.Incident {
+	for _, inc := range incidents {
+		if inc.IsActive() {
+			return inc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+type announcement struct {
+	incident *domain.Incident
+	opened   bool
+}
+
+type recordingAnnouncer struct {
+	sent []announcement
+}
+
+func (a *recordingAnnouncer) AnnounceIncident(_ context.Context, incident *domain.Incident, _ *domain.Monitor, opened bool) {
+	a.sent = append(a.sent, announcement{incident: incident, opened: opened})
+}
+
+// alertGroupStore keeps alert groups and their incidents in memory behind
+// a MockAlertGroupRepository.
+type alertGroupStore struct {
+	groups    map[uuid.UUID]*domain.AlertGroup
+	incidents []*domain.Incident
+}
+
+func (s *alertGroupStore) repo() *mocks.MockAlertGroupRepository {
+	return &mocks.MockAlertGroupRepository{
+		CreateFn: func(_ context.Context, g *domain.AlertGroup) error {
+			s.groups[g.ID] = g
+			return nil
+		},
+		DeleteFn: func(_ context.Context, id uuid.UUID) error {
+			delete(s.groups, id)
+			for _, inc := range s.incidents {
+				if inc.AlertGroupID != nil && *inc.AlertGroupID == id {
+					inc.AlertGroupID = nil
+				}
+			}
+			return nil
+		},
+		ResolveFn: func(_ context.Context, id uuid.UUID) (bool, error) {
+			g := s.groups[id]
+			if g == nil || !g.IsOpen() {
+				return false, nil
+			}
+			g.Status = domain.AlertGroupStatusResolved
+			return true, nil
+		},
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.AlertGroup, error) {
+			return s.groups[id], nil
+		},
+		GetAcceptingFn: func(_ context.Context, _ uuid.UUID, since time.Time) ([]*domain.AlertGroup, error) {
+			var out []*domain.AlertGroup
+			for _, g := range s.groups {
+				if g.IsOpen() && !g.LastIncidentAt.Before(since) {
+					out = append(out, g)
+				}
+			}
+			return out, nil
+		},
+		GetDueFn: func(_ context.Context, createdBefore time.Time) ([]*domain.AlertGroup, error) {
+			var out []*domain.AlertGroup
+			for _, g := range s.groups {
+				if g.IsPending() && !g.CreatedAt.After(createdBefore) {
+					out = append(out, g)
+				}
+			}
+			return out, nil
+		},
+		GetIncidentsFn: func(_ context.Context, groupID uuid.UUID) ([]*domain.Incident, error) {
+			var out []*domain.Incident
+			for _, inc := range s.incidents {
+				if inc.AlertGroupID != nil && *inc.AlertGroupID == groupID {
+					out = append(out, inc)
+				}
+			}
+			return out, nil
+		},
+		SetIncidentGroupFn: func(_ context.Context, ids []uuid.UUID, groupID *uuid.UUID) error {
+			for _, id := range ids {
+				for _, inc := range s.incidents {
+					if inc.ID == id {
+						inc.AlertGroupID = groupID
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type alertGroupFixture struct {
+	svc       *services.AlertGroupService
+	store     *alertGroupStore
+	announcer *recordingAnnouncer
+	agent     *domain.Agent
+	monitors  map[uuid.UUID]*domain.Monitor
+}
+
+func newAlertGroupFixture() *alertGroupFixture {
+	f := &alertGroupFixture{
+		store:     &alertGroupStore{groups: map[uuid.UUID]*domain.AlertGroup{}},
+		announcer: &recordingAnnouncer{},
+		agent:     &domain.Agent{ID: uuid.New(), UserID: uuid.New(), Name: "edge-1"},
+		monitors:  map[uuid.UUID]*domain.Monitor{},
+	}
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			return f.monitors[id], nil
+		},
+	}
+	agentRepo := &mocks.MockAgentRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Agent, error) {
+			if id == f.agent.ID {
+				return f.agent, nil
+			}
+			return nil, nil
+		},
+	}
+	f.svc = services.NewAlertGroupService(f.store.repo(), monitorRepo, agentRepo, 30*time.Second, slog.Default())
+	f.svc.SetAnnouncer(f.announcer)
+	return f
+}
+
+// open creates an incident on a new monitor of the fixture's agent and
+// groups it.
+func (f *alertGroupFixture) open(t *testing.T, name string, at time.Time) *domain.Incident {
+	t.Helper()
+	m := &domain.Monitor{ID: uuid.New(), AgentID: f.agent.ID, Name: name, Target: name + ".internal"}
+	f.monitors[m.ID] = m
+	inc := domain.NewIncident(m.ID)
+	inc.StartedAt = at
+	f.store.incidents = append(f.store.incidents, inc)
+	require.True(t, f.svc.GroupIncident(context.Background(), inc, m))
+	return inc
+}
+
+func resolve(inc *domain.Incident) {
+	inc.Status = domain.IncidentStatusResolved
+}
+
+func TestAlertGroupService_GroupsIncidentsUnderOneNotification(t *testing.T) {
+	f := newAlertGroupFixture()
+	ctx := context.Background()
+	start := time.Now()
+
+	a := f.open(t, "api", start)
+	b := f.open(t, "db", start.Add(5*time.Second))
+	c := f.open(t, "cache", start.Add(10*time.Second))
+	require.Len(t, f.store.groups, 1)
+	assert.Equal(t, *a.AlertGroupID, *b.AlertGroupID)
+	assert.Equal(t, *a.AlertGroupID, *c.AlertGroupID)
+
+	// Nothing is announced until the window ends.
+	n, err := f.svc.ProcessDue(ctx, start.Add(10*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, f.announcer.sent)
+
+	n, err = f.svc.ProcessDue(ctx, start.Add(31*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, f.announcer.sent, 1)
+	sent := f.announcer.sent[0]
+	assert.True(t, sent.opened)
+	assert.Equal(t, a.ID, sent.incident.ID)
+	require.NotNil(t, sent.incident.AlertContext)
+	require.NotNil(t, sent.incident.AlertContext.Group)
+	assert.Equal(t, 3, sent.incident.AlertContext.Group.Total)
+	assert.Equal(t, []string{"api", "db", "cache"}, sent.incident.AlertContext.Group.Monitors)
+	assert.Equal(t, "agent edge-1", sent.incident.AlertContext.Group.Title)
+
+	group := f.store.groups[*a.AlertGroupID]
+	assert.Equal(t, domain.AlertGroupReasonAgent, group.Reason)
+	assert.False(t, group.IsPending())
+}
+
+func TestAlertGroupService_SingleIncidentAnnouncedAlone(t *testing.T) {
+	f := newAlertGroupFixture()
+	start := time.Now()
+
+	a := f.open(t, "api", start)
+	_, err := f.svc.ProcessDue(context.Background(), start.Add(time.Minute))
+	require.NoError(t, err)
+
+	require.Len(t, f.announcer.sent, 1)
+	assert.Equal(t, a.ID, f.announcer.sent[0].incident.ID)
+	assert.Nil(t, f.announcer.sent[0].incident.AlertContext)
+	assert.Nil(t, a.AlertGroupID)
+	assert.Empty(t, f.store.groups)
+}
+
+func TestAlertGroupService_ResolvesWhenAllIncidentsRecover(t *testing.T) {
+	f := newAlertGroupFixture()
+	ctx := context.Background()
+	start := time.Now()
+
+	a := f.open(t, "api", start)
+	b := f.open(t, "db", start.Add(time.Second))
+	_, err := f.svc.ProcessDue(ctx, start.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, f.announcer.sent, 1)
+
+	resolve(b)
+	assert.True(t, f.svc.IncidentResolved(ctx, b))
+	assert.Len(t, f.announcer.sent, 1, "group recovery waits for every incident")
+
+	resolve(a)
+	assert.True(t, f.svc.IncidentResolved(ctx, a))
+	require.Len(t, f.announcer.sent, 2)
+	assert.False(t, f.announcer.sent[1].opened)
+	assert.Equal(t, a.ID, f.announcer.sent[1].incident.ID)
+	assert.Equal(t, domain.AlertGroupStatusResolved, f.store.groups[*a.AlertGroupID].Status)
+}
+
+func TestAlertGroupService_RecoveredWithinWindowIsSilent(t *testing.T) {
+	f := newAlertGroupFixture()
+	ctx := context.Background()
+	start := time.Now()
+
+	a := f.open(t, "api", start)
+	b := f.open(t, "db", start.Add(time.Second))
+	resolve(a)
+	resolve(b)
+	assert.True(t, f.svc.IncidentResolved(ctx, a))
+	assert.True(t, f.svc.IncidentResolved(ctx, b))
+
+	_, err := f.svc.ProcessDue(ctx, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, f.announcer.sent)
+	assert.Equal(t, domain.AlertGroupStatusResolved, f.store.groups[*a.AlertGroupID].Status)
+}
+
+func TestAlertGroupService_UngroupedIncidentNotHandled(t *testing.T) {
+	f := newAlertGroupFixture()
+	assert.False(t, f.svc.IncidentResolved(context.Background(), domain.NewIncident(uuid.New())))
+}
+
+func TestAlertGroupService_MergeAndSplit(t *testing.T) {
+	f := newAlertGroupFixture()
+	ctx := context.Background()
+	start := time.Now()
+
+	a := f.open(t, "api", start)
+	b := f.open(t, "db", start.Add(time.Second))
+	// A second agent's incident starts a separate group.
+	f.agent = &domain.Agent{ID: uuid.New(), UserID: f.agent.UserID, Name: "edge-2"}
+	c := f.open(t, "cache", start.Add(2*time.Second))
+	require.Len(t, f.store.groups, 2)
+	target, source := f.store.groups[*a.AlertGroupID], f.store.groups[*c.AlertGroupID]
+
+	err := f.svc.Merge(ctx, target, []*domain.AlertGroup{target})
+	assert.True(t, errors.Is(err, domain.ErrInvalidAlertGroupChange))
+
+	require.NoError(t, f.svc.Merge(ctx, target, []*domain.AlertGroup{source}))
+	assert.Len(t, f.store.groups, 1)
+	assert.Equal(t, target.ID, *c.AlertGroupID)
+
+	_, err = f.svc.Split(ctx, target, []uuid.UUID{a.ID, b.ID, c.ID})
+	assert.True(t, errors.Is(err, domain.ErrInvalidAlertGroupChange), "one incident must stay")
+	_, err = f.svc.Split(ctx, target, []uuid.UUID{uuid.New()})
+	assert.True(t, errors.Is(err, domain.ErrInvalidAlertGroupChange), "incident outside the group")
+
+	split, err := f.svc.Split(ctx, target, []uuid.UUID{c.ID, c.ID})
+	require.NoError(t, err)
+	assert.Equal(t, domain.AlertGroupReasonManual, split.Reason)
+	assert.Equal(t, 1, split.IncidentCount)
+	assert.Equal(t, split.ID, *c.AlertGroupID)
+	assert.Equal(t, target.ID, *b.AlertGroupID)
+
+	err = f.svc.Merge(ctx, split, []*domain.AlertGroup{{ID: uuid.New(), Status: domain.AlertGroupStatusResolved}})
+	assert.True(t, errors.Is(err, domain.ErrInvalidAlertGroupChange))
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// AlertRuleService manages the rules that route a user's alerts to their
+// channels. Rules are evaluated by the alert router; DryRun asks it where an
+// event would go without sending anything.
+type AlertRuleService struct {
+	ruleRepo         ports.AlertRuleRepository
+	alertChannelRepo ports.AlertChannelRepository
+	agentRepo        ports.AgentRepository
+	router           ports.AlertRouter
+}
+
+// NewAlertRuleService creates a new AlertRuleService.
+func NewAlertRuleService(
+	ruleRepo ports.AlertRuleRepository,
+	alertChannelRepo ports.AlertChannelRepository,
+	agentRepo ports.AgentRepository,
+	router ports.AlertRouter,
+) *AlertRuleService {
+	return &AlertRuleService{
+		ruleRepo:         ruleRepo,
+		alertChannelRepo: alertChannelRepo,
+		agentRepo:        agentRepo,
+		router:           router,
+	}
+}
+
+// ListRules returns a user's alert rules in evaluation order.
+func (s *AlertRuleService) ListRules(ctx context.Context, userID uuid.UUID) ([]*domain.AlertRule, error) {
+	rules, err := s.ruleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("alertRuleService.ListRules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRule returns an alert rule, or nil when it does not exist.
+func (s *AlertRuleService) GetRule(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("alertRuleService.GetRule: %w", err)
+	}
+	return rule, nil
+}
+
+// CreateRule validates and stores a new alert rule.
+func (s *AlertRuleService) CreateRule(ctx context.Context, rule *domain.AlertRule) error {
+	existing, err := s.ruleRepo.GetByUserID(ctx, rule.UserID)
+	if err != nil {
+		return fmt.Errorf("alertRuleService.CreateRule: %w", err)
+	}
+	if len(existing) >= domain.MaxAlertRules {
+		err := fmt.Errorf("%w: at most %d rules are allowed", domain.ErrInvalidAlertRule, domain.MaxAlertRules)
+		return fmt.Errorf("alertRuleService.CreateRule: %w", err)
+	}
+	if err := s.validate(ctx, rule); err != nil {
+		return fmt.Errorf("alertRuleService.CreateRule: %w", err)
+	}
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return fmt.Errorf("alertRuleService.CreateRule: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule validates and stores an edited alert rule.
+func (s *AlertRuleService) UpdateRule(ctx context.Context, rule *domain.AlertRule) error {
+	if err := s.validate(ctx, rule); err != nil {
+		return fmt.Errorf("alertRuleService.UpdateRule: %w", err)
+	}
+	rule.UpdatedAt = time.Now()
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return fmt.Errorf("alertRuleService.UpdateRule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule removes an alert rule.
+func (s *AlertRuleService) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("alertRuleService.DeleteRule: %w", err)
+	}
+	return nil
+}
+
+// DryRun returns where an event would be routed with the user's current
+// rules, without notifying anyone.
+func (s *AlertRuleService) DryRun(ctx context.Context, event *domain.AlertEvent) (*domain.AlertRouting, error) {
+	routing, err := s.router.RouteChannels(ctx, event)
+	if err != nil {
+		return nil, fmt.Errorf("alertRuleService.DryRun: %w", err)
+	}
+	return routing, nil
+}
+
+// validate checks the rule and that every channel and agent it names
+// belongs to its owner.
+func (s *AlertRuleService) validate(ctx context.Context, rule *domain.AlertRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	for _, id := range rule.ChannelIDs {
+		ch, err := s.alertChannelRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ch == nil || ch.UserID != rule.UserID {
+			return fmt.Errorf("%w: alert channel %s not found", domain.ErrInvalidAlertRule, id)
+		}
+	}
+	for _, id := range rule.Match.AgentIDs {
+		agent, err := s.agentRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if agent == nil || agent.UserID != rule.UserID {
+			return fmt.Errorf("%w: agent %s not found", domain.ErrInvalidAlertRule, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+// stubAlertRouter routes every event with a user's rules and channels held
+// in memory.
+type stubAlertRouter struct {
+	rules    []*domain.AlertRule
+	channels []*domain.AlertChannel
+	events   []*domain.AlertEvent
+}
+
+func (r *stubAlertRouter) RouteIncidentOpened(context.Context, *domain.Incident, *domain.Monitor) error {
+	return nil
+}
+
+func (r *stubAlertRouter) RouteIncidentResolved(context.Context, *domain.Incident, *domain.Monitor) error {
+	return nil
+}
+
+func (r *stubAlertRouter) RouteChannels(_ context.Context, event *domain.AlertEvent) (*domain.AlertRouting, error) {
+	r.events = append(r.events, event)
+	routing := domain.RouteAlert(r.rules, event)
+	if !routing.Suppressed {
+		routing.Channels = routing.Select(r.channels)
+	}
+	return routing, nil
+}
+
+func TestAlertRuleService_CreateRule_ChecksOwnership(t *testing.T) {
+	userID := uuid.New()
+	own := &domain.AlertChannel{ID: uuid.New(), UserID: userID}
+	other := &domain.AlertChannel{ID: uuid.New(), UserID: uuid.New()}
+	agent := &domain.Agent{ID: uuid.New(), UserID: uuid.New()}
+
+	created := 0
+	ruleRepo := &mocks.MockAlertRuleRepository{
+		CreateFn: func(_ context.Context, _ *domain.AlertRule) error {
+			created++
+			return nil
+		},
+	}
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.AlertChannel, error) {
+			for _, ch := range []*domain.AlertChannel{own, other} {
+				if ch.ID == id {
+					return ch, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+	agentRepo := &mocks.MockAgentRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Agent, error) {
+			if id == agent.ID {
+				return agent, nil
+			}
+			return nil, nil
+		},
+	}
+	svc := services.NewAlertRuleService(ruleRepo, channelRepo, agentRepo, &stubAlertRouter{})
+	ctx := context.Background()
+
+	rule, err := domain.NewAlertRule(userID, "to slack", 0, domain.AlertRuleMatch{}, domain.AlertRuleActionRoute, []uuid.UUID{own.ID})
+	require.NoError(t, err)
+	require.NoError(t, svc.CreateRule(ctx, rule))
+
+	rule, err = domain.NewAlertRule(userID, "someone else's", 0, domain.AlertRuleMatch{}, domain.AlertRuleActionRoute, []uuid.UUID{other.ID})
+	require.NoError(t, err)
+	err = svc.CreateRule(ctx, rule)
+	assert.True(t, errors.Is(err, domain.ErrInvalidAlertRule))
+	assert.Contains(t, err.Error(), "alert channel")
+
+	match := domain.AlertRuleMatch{AgentIDs: []uuid.UUID{agent.ID}}
+	rule, err = domain.NewAlertRule(userID, "foreign agent", 0, match, domain.AlertRuleActionSuppress, nil)
+	require.NoError(t, err)
+	err = svc.CreateRule(ctx, rule)
+	assert.True(t, errors.Is(err, domain.ErrInvalidAlertRule))
+	assert.Contains(t, err.Error(), "agent")
+
+	assert.Equal(t, 1, created)
+}
+
+func TestAlertRuleService_CreateRule_Limit(t *testing.T) {
+	ruleRepo := &mocks.MockAlertRuleRepository{
+		GetByUserIDFn: func(_ context.Context, _ uuid.UUID) ([]*domain.AlertRule, error) {
+			return make([]*domain.AlertRule, domain.MaxAlertRules), nil
+		},
+		CreateFn: func(_ context.Context, _ *domain.AlertRule) error {
+			t.Fatal("rule over the limit stored")
+			return nil
+		},
+	}
+	svc := services.NewAlertRuleService(ruleRepo, &mocks.MockAlertChannelRepository{}, &mocks.MockAgentRepository{}, &stubAlertRouter{})
+
+	rule, err := domain.NewAlertRule(uuid.New(), "one too many", 0, domain.AlertRuleMatch{}, domain.AlertRuleActionSuppress, nil)
+	require.NoError(t, err)
+	err = svc.CreateRule(context.Background(), rule)
+	assert.True(t, errors.Is(err, domain.ErrInvalidAlertRule))
+}
+
+func TestNotifyAgentOffline_RoutedByAlertRules(t *testing.T) {
+	agent := &domain.Agent{ID: uuid.New(), UserID: uuid.New(), Name: "edge-1"}
+	slack := &domain.AlertChannel{ID: uuid.New(), UserID: agent.UserID, Type: domain.AlertChannelSlack, Enabled: true}
+	email := &domain.AlertChannel{ID: uuid.New(), UserID: agent.UserID, Type: domain.AlertChannelEmail, Enabled: true}
+
+	offline, err := domain.NewAlertRule(agent.UserID, "agents to email", 0,
+		domain.AlertRuleMatch{Events: []domain.AlertEventKind{domain.AlertEventAgentOffline}},
+		domain.AlertRuleActionRoute, []uuid.UUID{email.ID})
+	require.NoError(t, err)
+	online, err := domain.NewAlertRule(agent.UserID, "quiet recoveries", 1,
+		domain.AlertRuleMatch{Events: []domain.AlertEventKind{domain.AlertEventAgentOnline}},
+		domain.AlertRuleActionSuppress, nil)
+	require.NoError(t, err)
+	router := &stubAlertRouter{rules: []*domain.AlertRule{offline, online}, channels: []*domain.AlertChannel{slack, email}}
+
+	var sentTo []domain.AlertChannelType
+	factory := &mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(ch *domain.AlertChannel) (ports.Notifier, error) {
+			return &mocks.MockNotifier{
+				NotifyAgentOfflineFn: func(_ context.Context, _ *domain.Agent, _ int) error {
+					sentTo = append(sentTo, ch.Type)
+					return nil
+				},
+				NotifyAgentOnlineFn: func(_ context.Context, _ *domain.Agent, _ int) error {
+					sentTo = append(sentTo, ch.Type)
+					return nil
+				},
+			}, nil
+		},
+	}
+	agentRepo := &mocks.MockAgentRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Agent, error) {
+			return agent, nil
+		},
+	}
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetEnabledByUserIDFn: func(_ context.Context, _ uuid.UUID) ([]*domain.AlertChannel, error) {
+			t.Fatal("channels are picked by the alert router")
+			return nil, nil
+		},
+	}
+	svc := services.NewIncidentService(&mocks.MockIncidentRepository{}, &mocks.MockMonitorRepository{}, agentRepo, &mocks.MockHeartbeatRepository{}, channelRepo, &mocks.MockNotifier{}, factory, &mocks.MockTransactor{}, slog.Default())
+	svc.SetAlertRouter(router)
+
+	svc.NotifyAgentOffline(context.Background(), agent.ID, 3)
+	assert.Equal(t, []domain.AlertChannelType{domain.AlertChannelEmail}, sentTo)
+
+	svc.NotifyAgentOnline(context.Background(), agent.ID, 3)
+	assert.Equal(t, []domain.AlertChannelType{domain.AlertChannelEmail}, sentTo, "recovery suppressed")
+
+	require.Len(t, router.events, 2)
+	assert.Equal(t, agent.ID, *router.events[0].AgentID)
+}
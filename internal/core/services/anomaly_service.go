@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Anomaly detection tuning.
+const (
+	// AnomalyWindow is how much recent latency each detection run looks at.
+	AnomalyWindow = 15 * time.Minute
+	// anomalyMinSamples is the fewest successful checks a window or hour
+	// needs before it is learned from or judged.
+	anomalyMinSamples = 3
+	// Hour-of-week slots see one observation a week, so they weigh each one
+	// heavily; the all-hours slot sees one an hour and moves slowly.
+	slotAlpha     = 0.3
+	allHoursAlpha = 0.05
+)
+
+// AnomalyService learns a seasonal latency baseline per monitor and raises
+// anomaly incidents when recent p95 latency deviates from it by more than the
+// configured number of standard deviations. It runs on the hub on a ticker.
+type AnomalyService struct {
+	monitorRepo  ports.MonitorRepository
+	incidentRepo ports.IncidentRepository
+	anomalyRepo  ports.AnomalyRepository
+	trends       *LatencyTrendService
+	incidentSvc  ports.IncidentService
+	transactor   ports.Transactor
+	sigma        float64
+	action       domain.AnomalyAction
+	logger       *slog.Logger
+}
+
+// NewAnomalyService creates a new AnomalyService.
+func NewAnomalyService(
+	monitorRepo ports.MonitorRepository,
+	incidentRepo ports.IncidentRepository,
+	anomalyRepo ports.AnomalyRepository,
+	trends *LatencyTrendService,
+	incidentSvc ports.IncidentService,
+	transactor ports.Transactor,
+	sigma float64,
+	action domain.AnomalyAction,
+	logger *slog.Logger,
+) *AnomalyService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AnomalyService{
+		monitorRepo:  monitorRepo,
+		incidentRepo: incidentRepo,
+		anomalyRepo:  anomalyRepo,
+		trends:       trends,
+		incidentSvc:  incidentSvc,
+		transactor:   transactor,
+		sigma:        sigma,
+		action:       action,
+		logger:       logger,
+	}
+}
+
+// Run learns from the last completed hour and checks the recent window of
+// every enabled monitor in the context's tenant. Returns the number of new
+// anomalies raised.
+func (s *AnomalyService) Run(ctx context.Context, now time.Time) (int, error) {
+	var monitors []*domain.Monitor
+	err := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		monitors, err = s.monitorRepo.GetAllInTenant(txCtx)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("anomalyService.Run: %w", err)
+	}
+
+	raised := 0
+	for _, monitor := range monitors {
+		if !monitor.Enabled {
+			continue
+		}
+		detected, err := s.checkMonitor(ctx, monitor.ID, now)
+		if err != nil {
+			s.logger.Error("failed to check latency anomaly",
+				"monitor_id", monitor.ID,
+				"error", err,
+			)
+			continue
+		}
+		if detected {
+			raised++
+		}
+	}
+	return raised, nil
+}
+
+// checkMonitor updates one monitor's baseline and judges its recent latency.
+func (s *AnomalyService) checkMonitor(ctx context.Context, monitorID uuid.UUID, now time.Time) (bool, error) {
+	stored, err := s.anomalyRepo.GetBaselines(ctx, monitorID)
+	if err != nil {
+		return false, err
+	}
+	baselines := make(map[int]*domain.LatencyBaseline, len(stored))
+	for _, b := range stored {
+		baselines[b.HourOfWeek] = b
+	}
+
+	active, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitorID)
+	if err != nil {
+		return false, fmt.Errorf("check active incident: %w", err)
+	}
+
+	// Hours spent in an incident would teach the baseline that an outage or
+	// slowdown is normal, so they are not learned from.
+	if active == nil {
+		if err := s.learn(ctx, monitorID, baselines, now); err != nil {
+			return false, err
+		}
+	}
+
+	recent, err := s.trends.GetSummary(ctx, monitorID, now.Add(-AnomalyWindow), now)
+	if err != nil {
+		return false, err
+	}
+	if recent.SampleCount < anomalyMinSamples {
+		return false, nil
+	}
+	baseline := baselineFor(baselines, now)
+	if baseline == nil {
+		return false, nil
+	}
+	sigma := baseline.Sigma(float64(recent.P95))
+
+	if sigma < s.sigma {
+		if active != nil && active.IsAnomaly() {
+			return false, s.resolve(ctx, active)
+		}
+		return false, nil
+	}
+	if active != nil {
+		return false, nil
+	}
+
+	incident, err := s.incidentSvc.CreateAnomalyIncidentIfNeeded(ctx, monitorID, s.action == domain.AnomalyActionIncident)
+	if err != nil {
+		return false, err
+	}
+	anomaly := domain.NewLatencyAnomaly(monitorID, now, recent, baseline)
+	anomaly.IncidentID = &incident.ID
+	if err := s.anomalyRepo.CreateAnomaly(ctx, anomaly); err != nil {
+		return false, err
+	}
+	s.logger.Info("latency anomaly detected",
+		"monitor_id", monitorID,
+		"incident_id", incident.ID,
+		"p95_ms", recent.P95,
+		"baseline_p95_ms", anomaly.BaselineP95,
+		"sigma", anomaly.Sigma,
+	)
+	return true, nil
+}
+
+// learn folds the last completed hour into its hour-of-week slot and the
+// all-hours slot, unless it has already been learned.
+func (s *AnomalyService) learn(ctx context.Context, monitorID uuid.UUID, baselines map[int]*domain.LatencyBaseline, now time.Time) error {
+	hour := now.Truncate(time.Hour).Add(-time.Hour)
+	slot := baselineSlot(baselines, monitorID, domain.HourOfWeek(hour))
+	all := baselineSlot(baselines, monitorID, domain.BaselineAllHours)
+	if !slot.LastBucket.Before(hour) && !all.LastBucket.Before(hour) {
+		return nil
+	}
+
+	summary, err := s.trends.GetSummary(ctx, monitorID, hour, hour.Add(time.Hour))
+	if err != nil {
+		return err
+	}
+	if summary.SampleCount < anomalyMinSamples {
+		return nil
+	}
+
+	for _, b := range []struct {
+		baseline *domain.LatencyBaseline
+		alpha    float64
+	}{{slot, slotAlpha}, {all, allHoursAlpha}} {
+		if !b.baseline.LastBucket.Before(hour) {
+			continue
+		}
+		b.baseline.Observe(float64(summary.P50), float64(summary.P95), b.alpha)
+		b.baseline.LastBucket = hour
+		if err := s.anomalyRepo.UpsertBaseline(ctx, b.baseline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve closes an anomaly incident once latency is back within baseline,
+// notifying only when its opening was announced.
+func (s *AnomalyService) resolve(ctx context.Context, incident *domain.Incident) error {
+	var err error
+	if s.action == domain.AnomalyActionIncident {
+		err = s.incidentSvc.ResolveIncident(ctx, incident.ID)
+	} else {
+		err = s.incidentSvc.ResolveIncidentSilently(ctx, incident.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("resolve anomaly incident: %w", err)
+	}
+	s.logger.Info("latency anomaly resolved",
+		"monitor_id", incident.MonitorID,
+		"incident_id", incident.ID,
+	)
+	return nil
+}
+
+// baselineSlot returns the stored slot or a new empty one, registering it.
+func baselineSlot(baselines map[int]*domain.LatencyBaseline, monitorID uuid.UUID, hourOfWeek int) *domain.LatencyBaseline {
+	b, ok := baselines[hourOfWeek]
+	if !ok {
+		b = domain.NewLatencyBaseline(monitorID, hourOfWeek)
+		baselines[hourOfWeek] = b
+	}
+	return b
+}
+
+// baselineFor picks the baseline to judge latency at t against: its
+// hour-of-week slot once warm, otherwise the all-hours slot. Returns nil
+// while neither has seen enough hours.
+func baselineFor(baselines map[int]*domain.LatencyBaseline, t time.Time) *domain.LatencyBaseline {
+	if b := baselines[domain.HourOfWeek(t)]; b != nil && b.IsWarm() {
+		return b
+	}
+	if b := baselines[domain.BaselineAllHours]; b != nil && b.IsWarm() {
+		return b
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+// anomalyNow is a Tuesday, 10:07 UTC.
+var anomalyNow = time.Date(2026, 10, 13, 10, 7, 0, 0, time.UTC)
+
+func newAnomalyService(
+	monitor *domain.Monitor,
+	incidentRepo *mocks.MockIncidentRepository,
+	anomalyRepo *mocks.MockAnomalyRepository,
+	heartbeatRepo *mocks.MockHeartbeatRepository,
+	incidentSvc *mocks.MockIncidentService,
+	action domain.AnomalyAction,
+) *services.AnomalyService {
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetAllInTenantFn: func(_ context.Context) ([]*domain.Monitor, error) {
+			return []*domain.Monitor{monitor}, nil
+		},
+	}
+	return services.NewAnomalyService(
+		monitorRepo, incidentRepo, anomalyRepo, services.NewLatencyTrendService(heartbeatRepo),
+		incidentSvc, &mocks.MockTransactor{}, 3, action, slog.Default(),
+	)
+}
+
+// warmBaselines returns a learned baseline for the current hour slot with a
+// p95 of 100ms ± 10ms, already up to date with the last completed hour.
+func warmBaselines(monitorID uuid.UUID) []*domain.LatencyBaseline {
+	lastHour := anomalyNow.Truncate(time.Hour).Add(-time.Hour)
+	return []*domain.LatencyBaseline{
+		{MonitorID: monitorID, HourOfWeek: domain.HourOfWeek(anomalyNow), P50Mean: 50, P95Mean: 100, P95Var: 100, Samples: 5, LastBucket: lastHour},
+		{MonitorID: monitorID, HourOfWeek: domain.BaselineAllHours, P50Mean: 50, P95Mean: 100, P95Var: 100, Samples: 50, LastBucket: lastHour},
+	}
+}
+
+func recentLatency(p95 int) *mocks.MockHeartbeatRepository {
+	return &mocks.MockHeartbeatRepository{
+		GetLatencyPercentileSummaryFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) (domain.LatencyTrendSummary, error) {
+			return domain.LatencyTrendSummary{P50: p95 / 2, P95: p95, SampleCount: 30}, nil
+		},
+	}
+}
+
+func TestAnomalyService_Run_OpensIncidentOnDeviation(t *testing.T) {
+	monitor := domain.NewMonitor(uuid.New(), "api", domain.MonitorTypeHTTP, "https://example.com")
+	anomalyRepo := &mocks.MockAnomalyRepository{
+		GetBaselinesFn: func(_ context.Context, _ uuid.UUID) ([]*domain.LatencyBaseline, error) {
+			return warmBaselines(monitor.ID), nil
+		},
+	}
+	var recorded *domain.LatencyAnomaly
+	anomalyRepo.CreateAnomalyFn = func(_ context.Context, a *domain.LatencyAnomaly) error {
+		recorded = a
+		return nil
+	}
+
+	incident := domain.NewAnomalyIncident(monitor.ID)
+	var notified bool
+	incidentSvc := &mocks.MockIncidentService{
+		CreateAnomalyIncidentIfNeededFn: func(_ context.Context, id uuid.UUID, notify bool) (*domain.Incident, error) {
+			assert.Equal(t, monitor.ID, id)
+			notified = notify
+			return incident, nil
+		},
+	}
+
+	svc := newAnomalyService(monitor, &mocks.MockIncidentRepository{}, anomalyRepo, recentLatency(180), incidentSvc, domain.AnomalyActionIncident)
+	raised, err := svc.Run(context.Background(), anomalyNow)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, raised)
+	assert.True(t, notified)
+	require.NotNil(t, recorded)
+	assert.Equal(t, 180, recorded.ObservedP95)
+	assert.Equal(t, 100, recorded.BaselineP95)
+	assert.InDelta(t, 8.0, recorded.Sigma, 1e-9)
+	require.NotNil(t, recorded.IncidentID)
+	assert.Equal(t, incident.ID, *recorded.IncidentID)
+}
+
+func TestAnomalyService_Run_DegradedActionIsSilent(t *testing.T) {
+	monitor := domain.NewMonitor(uuid.New(), "api", domain.MonitorTypeHTTP, "https://example.com")
+	anomalyRepo := &mocks.MockAnomalyRepository{
+		GetBaselinesFn: func(_ context.Context, _ uuid.UUID) ([]*domain.LatencyBaseline, error) {
+			return warmBaselines(monitor.ID), nil
+		},
+	}
+	notified := true
+	incidentSvc := &mocks.MockIncidentService{
+		CreateAnomalyIncidentIfNeededFn: func(_ context.Context, id uuid.UUID, notify bool) (*domain.Incident, error) {
+			notified = notify
+			return domain.NewAnomalyIncident(id), nil
+		},
+	}
+
+	svc := newAnomalyService(monitor, &mocks.MockIncidentRepository{}, anomalyRepo, recentLatency(180), incidentSvc, domain.AnomalyActionDegraded)
+	_, err := svc.Run(context.Background(), anomalyNow)
+	require.NoError(t, err)
+	assert.False(t, notified)
+}
+
+func TestAnomalyService_Run_ResolvesWhenBackToBaseline(t *testing.T) {
+	monitor := domain.NewMonitor(uuid.New(), "api", domain.MonitorTypeHTTP, "https://example.com")
+	active := domain.NewAnomalyIncident(monitor.ID)
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return active, nil
+		},
+	}
+	anomalyRepo := &mocks.MockAnomalyRepository{
+		GetBaselinesFn: func(_ context.Context, _ uuid.UUID) ([]*domain.LatencyBaseline, error) {
+			return warmBaselines(monitor.ID), nil
+		},
+	}
+	var resolved uuid.UUID
+	incidentSvc := &mocks.MockIncidentService{
+		ResolveIncidentFn: func(_ context.Context, id uuid.UUID) error {
+			resolved = id
+			return nil
+		},
+		CreateAnomalyIncidentIfNeededFn: func(_ context.Context, _ uuid.UUID, _ bool) (*domain.Incident, error) {
+			t.Fatal("no new incident while latency is normal")
+			return nil, nil
+		},
+	}
+
+	svc := newAnomalyService(monitor, incidentRepo, anomalyRepo, recentLatency(110), incidentSvc, domain.AnomalyActionIncident)
+	raised, err := svc.Run(context.Background(), anomalyNow)
+	require.NoError(t, err)
+
+	assert.Zero(t, raised)
+	assert.Equal(t, active.ID, resolved)
+}
+
+func TestAnomalyService_Run_LearnsLastHourWhileCold(t *testing.T) {
+	monitor := domain.NewMonitor(uuid.New(), "api", domain.MonitorTypeHTTP, "https://example.com")
+	var upserted []*domain.LatencyBaseline
+	anomalyRepo := &mocks.MockAnomalyRepository{
+		UpsertBaselineFn: func(_ context.Context, b *domain.LatencyBaseline) error {
+			upserted = append(upserted, b)
+			return nil
+		},
+	}
+	lastHour := anomalyNow.Truncate(time.Hour).Add(-time.Hour)
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		GetLatencyPercentileSummaryFn: func(_ context.Context, _ uuid.UUID, from, to time.Time) (domain.LatencyTrendSummary, error) {
+			if from.Equal(lastHour) {
+				assert.Equal(t, lastHour.Add(time.Hour), to)
+				return domain.LatencyTrendSummary{P50: 40, P95: 90, SampleCount: 120}, nil
+			}
+			return domain.LatencyTrendSummary{P50: 400, P95: 900, SampleCount: 30}, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		CreateAnomalyIncidentIfNeededFn: func(_ context.Context, _ uuid.UUID, _ bool) (*domain.Incident, error) {
+			t.Fatal("a cold baseline must not raise anomalies")
+			return nil, nil
+		},
+	}
+
+	svc := newAnomalyService(monitor, &mocks.MockIncidentRepository{}, anomalyRepo, heartbeatRepo, incidentSvc, domain.AnomalyActionIncident)
+	raised, err := svc.Run(context.Background(), anomalyNow)
+	require.NoError(t, err)
+
+	assert.Zero(t, raised)
+	require.Len(t, upserted, 2)
+	assert.Equal(t, domain.HourOfWeek(lastHour), upserted[0].HourOfWeek)
+	assert.Equal(t, domain.BaselineAllHours, upserted[1].HourOfWeek)
+	for _, b := range upserted {
+		assert.Equal(t, 90.0, b.P95Mean)
+		assert.Equal(t, 1, b.Samples)
+		assert.Equal(t, lastHour, b.LastBucket)
+	}
+}
@@ -122,6 +122,10 @@ func (s *AuthService) ValidateAPIKey(ctx context.Context, apiKey string) (*domai
 	if agent == nil {
 		return nil, ErrInvalidAPIKey
 	}
+	// Hub agents run inside the hub and never connect over WebSocket.
+	if agent.Hub {
+		return nil, ErrInvalidAPIKey
+	}
 
 	decryptedKey, err := s.encryptor.DecryptString(agent.APIKeyEncrypted)
 	if err != nil {
@@ -198,6 +198,25 @@ func TestValidateAPIKey_AgentNotFound(t *testing.T) {
 	assert.ErrorIs(t, err, services.ErrInvalidAPIKey)
 }
 
+func TestValidateAPIKey_HubAgentRejected(t *testing.T) {
+	encryptor, _ := crypto.NewEncryptor(testEncryptionKey)
+	secret := "deadbeef1234567890abcdef12345678"
+	encrypted, _ := encryptor.EncryptString(secret)
+	agentID := uuid.New()
+
+	agentRepo := &mocks.MockAgentRepository{
+		GetByIDGlobalFn: func(_ context.Context, _ uuid.UUID) (*domain.Agent, error) {
+			return &domain.Agent{ID: agentID, APIKeyEncrypted: encrypted, Hub: true}, nil
+		},
+	}
+	svc := newTestAuthService(&mocks.MockUserRepository{}, agentRepo)
+
+	agent, err := svc.ValidateAPIKey(context.Background(), agentID.String()+":"+secret)
+
+	assert.Nil(t, agent)
+	assert.ErrorIs(t, err, services.ErrInvalidAPIKey)
+}
+
 func TestValidateAPIKey_WrongSecret(t *testing.T) {
 	encryptor, _ := crypto.NewEncryptor(testEncryptionKey)
 	encrypted, _ := encryptor.EncryptString("correctsecret")
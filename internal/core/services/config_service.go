@@ -0,0 +1,674 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// ConfigService exports a user's monitoring setup as a declarative spec and
+// reconciles the stored setup with one. Plans are computed and applied inside
+// a single transaction, so an apply either makes every change or none.
+type ConfigService struct {
+	agentRepo   ports.AgentRepository
+	monitorRepo ports.MonitorRepository
+	channelRepo ports.AlertChannelRepository
+	pageRepo    ports.StatusPageRepository
+	windowRepo  ports.MaintenanceWindowRepository
+	monitorSvc  ports.MonitorService
+	agentAuth   ports.AgentAuthService
+	transactor  ports.Transactor
+	logger      *slog.Logger
+}
+
+// NewConfigService creates a new ConfigService.
+func NewConfigService(
+	agentRepo ports.AgentRepository,
+	monitorRepo ports.MonitorRepository,
+	channelRepo ports.AlertChannelRepository,
+	pageRepo ports.StatusPageRepository,
+	windowRepo ports.MaintenanceWindowRepository,
+	monitorSvc ports.MonitorService,
+	agentAuth ports.AgentAuthService,
+	transactor ports.Transactor,
+	logger *slog.Logger,
+) *ConfigService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ConfigService{
+		agentRepo:   agentRepo,
+		monitorRepo: monitorRepo,
+		channelRepo: channelRepo,
+		pageRepo:    pageRepo,
+		windowRepo:  windowRepo,
+		monitorSvc:  monitorSvc,
+		agentAuth:   agentAuth,
+		transactor:  transactor,
+		logger:      logger,
+	}
+}
+
+// ConfigApplyResult reports what Apply changed. AgentKeys holds the API keys
+// of created agents, shown once like on agent creation. Dispatch lists the
+// created and updated monitors whose tasks must be sent to agents; Cancel maps
+// monitor IDs to the agents that must stop running them.
+type ConfigApplyResult struct {
+	Changes   []domain.ConfigChange
+	AgentKeys map[string]string
+	Dispatch  []*domain.Monitor
+	Cancel    map[uuid.UUID][]uuid.UUID
+}
+
+// configState is a user's stored setup indexed by spec name.
+type configState struct {
+	agents     map[string]*domain.Agent
+	agentNames map[uuid.UUID]string
+	monitors   map[string]*domain.Monitor
+	channels   map[string]*domain.AlertChannel
+	pages      map[string]*domain.StatusPage
+	windows    map[string]*domain.MaintenanceWindow
+	// duplicates names a stored resource of each kind that shares its name
+	// with another, which makes the kind impossible to reconcile.
+	duplicates map[domain.ConfigResource]string
+}
+
+// configOp is one planned change and the function that makes it.
+type configOp struct {
+	change domain.ConfigChange
+	apply  func(ctx context.Context, result *ConfigApplyResult) error
+}
+
+// Export describes the user's stored setup as a spec.
+func (s *ConfigService) Export(ctx context.Context, userID uuid.UUID) (*domain.ConfigSpec, error) {
+	var st *configState
+	err := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		st, err = s.load(txCtx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configService.Export: %w", err)
+	}
+
+	spec := &domain.ConfigSpec{
+		Agents:             []domain.AgentSpec{},
+		Monitors:           []domain.MonitorSpec{},
+		AlertChannels:      []domain.AlertChannelSpec{},
+		StatusPages:        []domain.StatusPageSpec{},
+		MaintenanceWindows: []domain.MaintenanceWindowSpec{},
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.agents)) {
+		spec.Agents = append(spec.Agents, domain.AgentSpec{Name: name, Hub: st.agents[name].Hub})
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.monitors)) {
+		spec.Monitors = append(spec.Monitors, domain.MonitorSpecFrom(st.monitors[name], st.agentNames))
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.channels)) {
+		spec.AlertChannels = append(spec.AlertChannels, domain.AlertChannelSpecFrom(st.channels[name]))
+	}
+	monitorNames := st.monitorNames()
+	for _, slug := range slices.Sorted(maps.Keys(st.pages)) {
+		spec.StatusPages = append(spec.StatusPages, domain.StatusPageSpecFrom(st.pages[slug], monitorNames))
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.windows)) {
+		spec.MaintenanceWindows = append(spec.MaintenanceWindows, domain.MaintenanceWindowSpecFrom(st.windows[name], st.agentNames))
+	}
+	return spec, nil
+}
+
+// Plan computes the changes applying spec would make, without making them.
+func (s *ConfigService) Plan(ctx context.Context, userID uuid.UUID, spec *domain.ConfigSpec) (*domain.ConfigPlan, error) {
+	var ops []configOp
+	err := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+		st, err := s.load(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		ops, err = s.plan(userID, st, spec)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configService.Plan: %w", err)
+	}
+
+	plan := &domain.ConfigPlan{Changes: []domain.ConfigChange{}}
+	for _, op := range ops {
+		plan.Changes = append(plan.Changes, op.change)
+	}
+	return plan, nil
+}
+
+// Apply brings the stored setup in line with spec in one transaction.
+func (s *ConfigService) Apply(ctx context.Context, userID uuid.UUID, spec *domain.ConfigSpec) (*ConfigApplyResult, error) {
+	var result *ConfigApplyResult
+	err := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Start over if the transaction is retried.
+		result = &ConfigApplyResult{
+			Changes:   []domain.ConfigChange{},
+			AgentKeys: make(map[string]string),
+			Cancel:    make(map[uuid.UUID][]uuid.UUID),
+		}
+		st, err := s.load(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		ops, err := s.plan(userID, st, spec)
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if err := op.apply(txCtx, result); err != nil {
+				return fmt.Errorf("%s %s %q: %w", op.change.Action, op.change.Resource, op.change.Name, err)
+			}
+			result.Changes = append(result.Changes, op.change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configService.Apply: %w", err)
+	}
+
+	s.logger.Info("config applied",
+		"user_id", userID,
+		"changes", len(result.Changes),
+	)
+	return result, nil
+}
+
+// load reads the user's stored setup.
+func (s *ConfigService) load(ctx context.Context, userID uuid.UUID) (*configState, error) {
+	st := &configState{
+		agents:     make(map[string]*domain.Agent),
+		agentNames: make(map[uuid.UUID]string),
+		monitors:   make(map[string]*domain.Monitor),
+		channels:   make(map[string]*domain.AlertChannel),
+		pages:      make(map[string]*domain.StatusPage),
+		windows:    make(map[string]*domain.MaintenanceWindow),
+		duplicates: make(map[domain.ConfigResource]string),
+	}
+	agents, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load agents: %w", err)
+	}
+	st.indexDuplicates(domain.ConfigResourceAgent, indexByName(st.agents, agents, func(a *domain.Agent) string { return a.Name }))
+	for _, a := range agents {
+		st.agentNames[a.ID] = a.Name
+	}
+
+	monitors, err := s.monitorRepo.GetByUserIDWithTags(ctx, userID, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("load monitors: %w", err)
+	}
+	st.indexDuplicates(domain.ConfigResourceMonitor, indexByName(st.monitors, monitors, func(m *domain.Monitor) string { return m.Name }))
+
+	channels, err := s.channelRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load alert channels: %w", err)
+	}
+	st.indexDuplicates(domain.ConfigResourceAlertChannel, indexByName(st.channels, channels, func(ch *domain.AlertChannel) string { return ch.Name }))
+
+	pages, err := s.pageRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load status pages: %w", err)
+	}
+	for _, p := range pages {
+		p.MonitorIDs, err = s.pageRepo.GetMonitorIDs(ctx, p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("load status page monitors: %w", err)
+		}
+	}
+	st.indexDuplicates(domain.ConfigResourceStatusPage, indexByName(st.pages, pages, func(p *domain.StatusPage) string { return p.Slug }))
+
+	windows, err := s.windowRepo.GetByTenant(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load maintenance windows: %w", err)
+	}
+	windows = slices.DeleteFunc(windows, func(w *domain.MaintenanceWindow) bool { return w.UserID != userID })
+	st.indexDuplicates(domain.ConfigResourceMaintenanceWindow, indexByName(st.windows, windows, func(w *domain.MaintenanceWindow) string { return w.Name }))
+	return st, nil
+}
+
+func (st *configState) indexDuplicates(resource domain.ConfigResource, name string) {
+	if name != "" {
+		st.duplicates[resource] = name
+	}
+}
+
+// indexByName adds items to index under their names and returns a name two
+// of them share, or "" when names are unique.
+func indexByName[T any](index map[string]T, items []T, name func(T) string) string {
+	duplicate := ""
+	for _, item := range items {
+		n := name(item)
+		if _, ok := index[n]; ok && duplicate == "" {
+			duplicate = n
+		}
+		index[n] = item
+	}
+	return duplicate
+}
+
+// monitorNames maps the IDs of the indexed monitors to their names.
+func (st *configState) monitorNames() map[uuid.UUID]string {
+	names := make(map[uuid.UUID]string, len(st.monitors))
+	for name, m := range st.monitors {
+		names[m.ID] = name
+	}
+	return names
+}
+
+// monitorIDs maps the names of the indexed monitors to their IDs.
+func (st *configState) monitorIDs() map[string]uuid.UUID {
+	ids := make(map[string]uuid.UUID, len(st.monitors))
+	for name, m := range st.monitors {
+		ids[name] = m.ID
+	}
+	return ids
+}
+
+// plan validates spec against the stored setup and returns the changes in
+// the order they must be made. Resources a change creates are added to st as
+// placeholders so later sections can reference them; their apply functions
+// fill the placeholders in with the stored resources.
+func (s *ConfigService) plan(userID uuid.UUID, st *configState, spec *domain.ConfigSpec) ([]configOp, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	managed := map[domain.ConfigResource]bool{
+		domain.ConfigResourceAgent:             spec.Agents != nil || spec.Monitors != nil || spec.MaintenanceWindows != nil,
+		domain.ConfigResourceMonitor:           spec.Monitors != nil,
+		domain.ConfigResourceAlertChannel:      spec.AlertChannels != nil,
+		domain.ConfigResourceStatusPage:        spec.StatusPages != nil,
+		domain.ConfigResourceMaintenanceWindow: spec.MaintenanceWindows != nil,
+	}
+	for resource, name := range st.duplicates {
+		if managed[resource] {
+			return nil, fmt.Errorf("%w: more than one %s is named %q; rename one before applying", domain.ErrInvalidConfig, resource, name)
+		}
+	}
+
+	ops := s.planAgents(userID, st, spec.Agents)
+	if spec.Monitors != nil {
+		monitorOps, err := s.planMonitors(userID, st, spec.Monitors)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, monitorOps...)
+	}
+	if spec.AlertChannels != nil {
+		channelOps, err := s.planChannels(userID, st, spec.AlertChannels)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, channelOps...)
+	}
+	if spec.StatusPages != nil {
+		pageOps, err := s.planPages(userID, st, spec.StatusPages)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, pageOps...)
+	}
+	if spec.MaintenanceWindows != nil {
+		windowOps, err := s.planWindows(userID, st, spec.MaintenanceWindows)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, windowOps...)
+	}
+	return ops, nil
+}
+
+// planAgents creates declared agents that do not exist yet. Agents are never
+// updated or deleted: deleting one would take its monitors with it.
+func (s *ConfigService) planAgents(userID uuid.UUID, st *configState, specs []domain.AgentSpec) []configOp {
+	var ops []configOp
+	for _, as := range specs {
+		if _, ok := st.agents[as.Name]; ok {
+			continue
+		}
+		agent := domain.NewAgent(userID, as.Name, nil)
+		agent.Hub = as.Hub
+		st.agents[as.Name] = agent
+		st.agentNames[agent.ID] = as.Name
+
+		ops = append(ops, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionCreate, Resource: domain.ConfigResourceAgent, Name: as.Name},
+			apply: func(ctx context.Context, result *ConfigApplyResult) error {
+				created, apiKey, err := s.agentAuth.CreateAgent(ctx, userID.String(), as.Name)
+				if err != nil {
+					return err
+				}
+				// Hub agents never connect, so they get no usable key.
+				if as.Hub {
+					created.Hub = true
+					created.APIKeyExpiresAt = nil
+					created.MarkOnline()
+					if err := s.agentRepo.Update(ctx, created); err != nil {
+						return err
+					}
+				} else {
+					result.AgentKeys[as.Name] = apiKey
+				}
+				*agent = *created
+				st.agentNames[created.ID] = as.Name
+				return nil
+			},
+		})
+	}
+	return ops
+}
+
+// planMonitors deletes undeclared monitors, then updates and creates declared
+// ones. Changing a monitor's type replaces it.
+func (s *ConfigService) planMonitors(userID uuid.UUID, st *configState, specs []domain.MonitorSpec) ([]configOp, error) {
+	var deletes, updates, creates []configOp
+	deleteOp := func(m *domain.Monitor) configOp {
+		return configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionDelete, Resource: domain.ConfigResourceMonitor, Name: m.Name},
+			apply: func(ctx context.Context, result *ConfigApplyResult) error {
+				if err := s.monitorSvc.DeleteMonitor(ctx, m.ID); err != nil {
+					return err
+				}
+				result.Cancel[m.ID] = m.ProbeAgentIDs()
+				return nil
+			},
+		}
+	}
+
+	declared := make(map[string]bool, len(specs))
+	for _, ms := range specs {
+		declared[ms.Name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.monitors)) {
+		if !declared[name] {
+			deletes = append(deletes, deleteOp(st.monitors[name]))
+			delete(st.monitors, name)
+		}
+	}
+
+	for _, ms := range specs {
+		existing := st.monitors[ms.Name]
+		if existing != nil && existing.Type != ms.Type {
+			deletes = append(deletes, deleteOp(existing))
+			existing = nil
+		}
+
+		if existing == nil {
+			monitor := domain.NewMonitor(uuid.Nil, ms.Name, ms.Type, ms.Target)
+			if monitor.IsPush() {
+				monitor.EnablePush("")
+			}
+			if err := ms.ApplyTo(monitor, st.agents); err != nil {
+				return nil, err
+			}
+			st.monitors[ms.Name] = monitor
+
+			creates = append(creates, configOp{
+				change: domain.ConfigChange{Action: domain.ConfigActionCreate, Resource: domain.ConfigResourceMonitor, Name: ms.Name},
+				apply: func(ctx context.Context, result *ConfigApplyResult) error {
+					agent := st.agents[ms.Agent]
+					created, err := s.monitorSvc.CreateMonitor(ctx, userID, agent.ID, ms.Name, ms.Type, ms.Target, nil)
+					if err != nil {
+						return err
+					}
+					if err := ms.ApplyTo(created, st.agents); err != nil {
+						return err
+					}
+					if err := s.monitorSvc.UpdateMonitor(ctx, created); err != nil {
+						return err
+					}
+					*monitor = *created
+					result.Dispatch = append(result.Dispatch, monitor)
+					return nil
+				},
+			})
+			continue
+		}
+
+		desired := *existing
+		if err := ms.ApplyTo(&desired, st.agents); err != nil {
+			return nil, err
+		}
+		fields := domain.ChangedFields(
+			domain.MonitorSpecFrom(existing, st.agentNames),
+			domain.MonitorSpecFrom(&desired, st.agentNames),
+		)
+		if len(fields) == 0 {
+			continue
+		}
+		updates = append(updates, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionUpdate, Resource: domain.ConfigResourceMonitor, Name: ms.Name, Fields: fields},
+			apply: func(ctx context.Context, result *ConfigApplyResult) error {
+				oldProbes := existing.ProbeAgentIDs()
+				if err := ms.ApplyTo(existing, st.agents); err != nil {
+					return err
+				}
+				if err := s.monitorSvc.UpdateMonitor(ctx, existing); err != nil {
+					return err
+				}
+				// Cancel the monitor on agents that no longer probe it.
+				for _, agentID := range oldProbes {
+					if !slices.Contains(existing.ProbeAgentIDs(), agentID) {
+						result.Cancel[existing.ID] = append(result.Cancel[existing.ID], agentID)
+					}
+				}
+				result.Dispatch = append(result.Dispatch, existing)
+				return nil
+			},
+		})
+	}
+
+	return slices.Concat(deletes, updates, creates), nil
+}
+
+// planChannels deletes undeclared alert channels, then updates and creates
+// declared ones.
+func (s *ConfigService) planChannels(userID uuid.UUID, st *configState, specs []domain.AlertChannelSpec) ([]configOp, error) {
+	var ops []configOp
+	declared := make(map[string]bool, len(specs))
+	for _, cs := range specs {
+		declared[cs.Name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.channels)) {
+		if declared[name] {
+			continue
+		}
+		ch := st.channels[name]
+		ops = append(ops, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionDelete, Resource: domain.ConfigResourceAlertChannel, Name: name},
+			apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+				return s.channelRepo.Delete(ctx, ch.ID)
+			},
+		})
+	}
+
+	for _, cs := range specs {
+		existing := st.channels[cs.Name]
+		if existing == nil {
+			ch := domain.NewAlertChannel(userID, cs.Type, cs.Name, nil)
+			if err := cs.ApplyTo(ch); err != nil {
+				return nil, err
+			}
+			ops = append(ops, configOp{
+				change: domain.ConfigChange{Action: domain.ConfigActionCreate, Resource: domain.ConfigResourceAlertChannel, Name: cs.Name},
+				apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+					return s.channelRepo.Create(ctx, ch)
+				},
+			})
+			continue
+		}
+
+		desired := *existing
+		if err := cs.ApplyTo(&desired); err != nil {
+			return nil, err
+		}
+		fields := domain.ChangedFields(domain.AlertChannelSpecFrom(existing), domain.AlertChannelSpecFrom(&desired))
+		// A changed password is redacted on both sides.
+		if !maps.Equal(existing.Config, desired.Config) && !slices.Contains(fields, "config") {
+			fields = append(fields, "config")
+			slices.Sort(fields)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		ops = append(ops, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionUpdate, Resource: domain.ConfigResourceAlertChannel, Name: cs.Name, Fields: fields},
+			apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+				return s.channelRepo.Update(ctx, &desired)
+			},
+		})
+	}
+	return ops, nil
+}
+
+// planPages deletes undeclared status pages, then updates and creates
+// declared ones. Pages are planned after monitors so they can show monitors
+// created by the same spec.
+func (s *ConfigService) planPages(userID uuid.UUID, st *configState, specs []domain.StatusPageSpec) ([]configOp, error) {
+	var ops []configOp
+	declared := make(map[string]bool, len(specs))
+	for _, ps := range specs {
+		declared[ps.Slug] = true
+	}
+	for _, slug := range slices.Sorted(maps.Keys(st.pages)) {
+		if declared[slug] {
+			continue
+		}
+		page := st.pages[slug]
+		ops = append(ops, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionDelete, Resource: domain.ConfigResourceStatusPage, Name: slug},
+			apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+				return s.pageRepo.Delete(ctx, page.ID)
+			},
+		})
+	}
+
+	monitorIDs, monitorNames := st.monitorIDs(), st.monitorNames()
+	for _, ps := range specs {
+		existing := st.pages[ps.Slug]
+		if existing == nil {
+			page := domain.NewStatusPage(userID, ps.Name, ps.Slug)
+			if err := ps.ApplyTo(page, monitorIDs); err != nil {
+				return nil, err
+			}
+			ops = append(ops, configOp{
+				change: domain.ConfigChange{Action: domain.ConfigActionCreate, Resource: domain.ConfigResourceStatusPage, Name: ps.Slug},
+				apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+					// Monitors created earlier in the apply now have their IDs.
+					if err := ps.ApplyTo(page, st.monitorIDs()); err != nil {
+						return err
+					}
+					if err := s.pageRepo.Create(ctx, page); err != nil {
+						return err
+					}
+					return s.pageRepo.SetMonitors(ctx, page.ID, page.MonitorIDs)
+				},
+			})
+			continue
+		}
+
+		desired := *existing
+		if err := ps.ApplyTo(&desired, monitorIDs); err != nil {
+			return nil, err
+		}
+		fields := domain.ChangedFields(
+			domain.StatusPageSpecFrom(existing, monitorNames),
+			domain.StatusPageSpecFrom(&desired, monitorNames),
+		)
+		// A replaced monitor keeps its name but not its ID.
+		if !slices.Equal(existing.MonitorIDs, desired.MonitorIDs) && !slices.Contains(fields, "monitors") {
+			fields = append(fields, "monitors")
+			slices.Sort(fields)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		ops = append(ops, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionUpdate, Resource: domain.ConfigResourceStatusPage, Name: ps.Slug, Fields: fields},
+			apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+				if err := ps.ApplyTo(existing, st.monitorIDs()); err != nil {
+					return err
+				}
+				if err := s.pageRepo.Update(ctx, existing); err != nil {
+					return err
+				}
+				return s.pageRepo.SetMonitors(ctx, existing.ID, existing.MonitorIDs)
+			},
+		})
+	}
+	return ops, nil
+}
+
+// planWindows deletes undeclared maintenance windows, then updates and
+// creates declared ones.
+func (s *ConfigService) planWindows(userID uuid.UUID, st *configState, specs []domain.MaintenanceWindowSpec) ([]configOp, error) {
+	var ops []configOp
+	declared := make(map[string]bool, len(specs))
+	for _, ws := range specs {
+		declared[ws.Name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.windows)) {
+		if declared[name] {
+			continue
+		}
+		window := st.windows[name]
+		ops = append(ops, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionDelete, Resource: domain.ConfigResourceMaintenanceWindow, Name: name},
+			apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+				return s.windowRepo.Delete(ctx, window.ID)
+			},
+		})
+	}
+
+	for _, ws := range specs {
+		existing := st.windows[ws.Name]
+		if existing == nil {
+			window := domain.NewMaintenanceWindow(uuid.Nil, userID, ws.Name, ws.StartsAt, ws.EndsAt)
+			if err := ws.ApplyTo(window, st.agents); err != nil {
+				return nil, err
+			}
+			ops = append(ops, configOp{
+				change: domain.ConfigChange{Action: domain.ConfigActionCreate, Resource: domain.ConfigResourceMaintenanceWindow, Name: ws.Name},
+				apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+					// Agents created earlier in the apply now have their IDs.
+					if err := ws.ApplyTo(window, st.agents); err != nil {
+						return err
+					}
+					return s.windowRepo.Create(ctx, window)
+				},
+			})
+			continue
+		}
+
+		desired := *existing
+		if err := ws.ApplyTo(&desired, st.agents); err != nil {
+			return nil, err
+		}
+		fields := domain.ChangedFields(
+			domain.MaintenanceWindowSpecFrom(existing, st.agentNames),
+			domain.MaintenanceWindowSpecFrom(&desired, st.agentNames),
+		)
+		if len(fields) == 0 {
+			continue
+		}
+		ops = append(ops, configOp{
+			change: domain.ConfigChange{Action: domain.ConfigActionUpdate, Resource: domain.ConfigResourceMaintenanceWindow, Name: ws.Name, Fields: fields},
+			apply: func(ctx context.Context, _ *ConfigApplyResult) error {
+				if err := ws.ApplyTo(existing, st.agents); err != nil {
+					return err
+				}
+				return s.windowRepo.Update(ctx, existing)
+			},
+		})
+	}
+	return ops, nil
+}
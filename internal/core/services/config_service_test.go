@@ -0,0 +1,246 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+type configFixture struct {
+	userID     uuid.UUID
+	agent      *domain.Agent
+	monitors   []*domain.Monitor
+	agentRepo  *mocks.MockAgentRepository
+	agentAuth  *mocks.MockAgentAuthService
+	monitorSvc *mocks.MockMonitorService
+	pageRepo   *mocks.MockStatusPageRepository
+	transactor *mocks.MockTransactor
+}
+
+func newConfigFixture(monitors ...*domain.Monitor) *configFixture {
+	userID := uuid.New()
+	agent := domain.NewAgent(userID, "edge", nil)
+	for _, m := range monitors {
+		m.AgentID = agent.ID
+	}
+	f := &configFixture{
+		userID:     userID,
+		agent:      agent,
+		monitors:   monitors,
+		agentAuth:  &mocks.MockAgentAuthService{},
+		monitorSvc: &mocks.MockMonitorService{},
+		pageRepo:   &mocks.MockStatusPageRepository{},
+		transactor: &mocks.MockTransactor{},
+	}
+	f.agentRepo = &mocks.MockAgentRepository{
+		GetByUserIDFn: func(_ context.Context, _ uuid.UUID) ([]*domain.Agent, error) {
+			return []*domain.Agent{agent}, nil
+		},
+	}
+	return f
+}
+
+func (f *configFixture) service() *services.ConfigService {
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByUserIDWithTagsFn: func(_ context.Context, _ uuid.UUID, _ map[string]string) ([]*domain.Monitor, error) {
+			return f.monitors, nil
+		},
+	}
+	return services.NewConfigService(
+		f.agentRepo, monitorRepo, &mocks.MockAlertChannelRepository{}, f.pageRepo,
+		&mocks.MockMaintenanceWindowRepository{}, f.monitorSvc, f.agentAuth, f.transactor, slog.Default(),
+	)
+}
+
+func TestConfigService_Export(t *testing.T) {
+	f := newConfigFixture(domain.NewMonitor(uuid.Nil, "api", domain.MonitorTypeHTTP, "https://example.com"))
+
+	spec, err := f.service().Export(context.Background(), f.userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.AgentSpec{{Name: "edge"}}, spec.Agents)
+	require.Len(t, spec.Monitors, 1)
+	assert.Equal(t, "edge", spec.Monitors[0].Agent)
+	assert.NotNil(t, spec.AlertChannels, "unmanaged sections export as empty lists")
+}
+
+func TestConfigService_Plan(t *testing.T) {
+	api := domain.NewMonitor(uuid.Nil, "api", domain.MonitorTypeHTTP, "https://example.com")
+	old := domain.NewMonitor(uuid.Nil, "old", domain.MonitorTypeTCP, "db:5432")
+	f := newConfigFixture(api, old)
+
+	spec := &domain.ConfigSpec{Monitors: []domain.MonitorSpec{
+		{Name: "api", Agent: "edge", Type: domain.MonitorTypeHTTP, Target: "https://example.com", IntervalSeconds: 60},
+		{Name: "web", Agent: "edge", Type: domain.MonitorTypeHTTP, Target: "https://www.example.com"},
+	}}
+	plan, err := f.service().Plan(context.Background(), f.userID, spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.ConfigChange{
+		{Action: domain.ConfigActionDelete, Resource: domain.ConfigResourceMonitor, Name: "old"},
+		{Action: domain.ConfigActionUpdate, Resource: domain.ConfigResourceMonitor, Name: "api", Fields: []string{"interval_seconds"}},
+		{Action: domain.ConfigActionCreate, Resource: domain.ConfigResourceMonitor, Name: "web"},
+	}, plan.Changes)
+	assert.Equal(t, domain.DefaultIntervalSeconds, api.IntervalSeconds, "planning changes nothing")
+}
+
+func TestConfigService_Apply(t *testing.T) {
+	api := domain.NewMonitor(uuid.Nil, "api", domain.MonitorTypeHTTP, "https://example.com")
+	old := domain.NewMonitor(uuid.Nil, "old", domain.MonitorTypeTCP, "db:5432")
+	f := newConfigFixture(api, old)
+
+	var deleted []uuid.UUID
+	var updated []string
+	f.monitorSvc.DeleteMonitorFn = func(_ context.Context, id uuid.UUID) error {
+		deleted = append(deleted, id)
+		return nil
+	}
+	f.monitorSvc.UpdateMonitorFn = func(_ context.Context, m *domain.Monitor) error {
+		updated = append(updated, m.Name)
+		return nil
+	}
+	f.monitorSvc.CreateMonitorFn = func(_ context.Context, userID, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, _ map[string]string) (*domain.Monitor, error) {
+		assert.Equal(t, f.agent.ID, agentID)
+		return domain.NewMonitor(agentID, name, monitorType, target), nil
+	}
+
+	spec := &domain.ConfigSpec{Monitors: []domain.MonitorSpec{
+		{Name: "api", Agent: "edge", Type: domain.MonitorTypeHTTP, Target: "https://example.com", IntervalSeconds: 60},
+		{Name: "web", Agent: "edge", Type: domain.MonitorTypeHTTP, Target: "https://www.example.com", TimeoutSeconds: 5},
+	}}
+	result, err := f.service().Apply(context.Background(), f.userID, spec)
+	require.NoError(t, err)
+
+	assert.Len(t, result.Changes, 3)
+	assert.Equal(t, []uuid.UUID{old.ID}, deleted)
+	assert.Equal(t, []uuid.UUID{f.agent.ID}, result.Cancel[old.ID])
+	assert.Equal(t, []string{"api", "web"}, updated, "created monitors get their full settings")
+	assert.Equal(t, 60, api.IntervalSeconds)
+
+	require.Len(t, result.Dispatch, 2)
+	assert.Equal(t, "web", result.Dispatch[1].Name)
+	assert.Equal(t, 5, result.Dispatch[1].TimeoutSeconds)
+}
+
+func TestConfigService_Apply_CreatesAgents(t *testing.T) {
+	f := newConfigFixture()
+	f.agentAuth.CreateAgentFn = func(_ context.Context, userID string, name string) (*domain.Agent, string, error) {
+		return domain.NewAgent(f.userID, name, nil), "wd_key_" + name, nil
+	}
+	var hubUpdated bool
+	f.agentRepo.UpdateFn = func(_ context.Context, a *domain.Agent) error {
+		hubUpdated = a.Hub && a.Name == "hub"
+		return nil
+	}
+	var createdOn uuid.UUID
+	f.monitorSvc.CreateMonitorFn = func(_ context.Context, _, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, _ map[string]string) (*domain.Monitor, error) {
+		createdOn = agentID
+		return domain.NewMonitor(agentID, name, monitorType, target), nil
+	}
+
+	spec := &domain.ConfigSpec{
+		Agents: []domain.AgentSpec{{Name: "edge"}, {Name: "eu"}, {Name: "hub", Hub: true}},
+		Monitors: []domain.MonitorSpec{
+			{Name: "api", Agent: "eu", Type: domain.MonitorTypeHTTP, Target: "https://example.com"},
+		},
+	}
+	result, err := f.service().Apply(context.Background(), f.userID, spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"eu": "wd_key_eu"}, result.AgentKeys, "hub agents get no key")
+	assert.True(t, hubUpdated)
+	require.Len(t, result.Dispatch, 1)
+	assert.Equal(t, result.Dispatch[0].AgentID, createdOn, "monitors run on the agent created in the same apply")
+}
+
+func TestConfigService_Apply_WrapsOpError(t *testing.T) {
+	f := newConfigFixture()
+	f.monitorSvc.CreateMonitorFn = func(context.Context, uuid.UUID, uuid.UUID, string, domain.MonitorType, string, map[string]string) (*domain.Monitor, error) {
+		return nil, domain.ErrMonitorLimitReached
+	}
+
+	spec := &domain.ConfigSpec{Monitors: []domain.MonitorSpec{
+		{Name: "api", Agent: "edge", Type: domain.MonitorTypeHTTP, Target: "https://example.com"},
+	}}
+	_, err := f.service().Apply(context.Background(), f.userID, spec)
+	assert.True(t, errors.Is(err, domain.ErrMonitorLimitReached))
+}
+
+func TestConfigService_Plan_DuplicateStoredNames(t *testing.T) {
+	f := newConfigFixture(
+		domain.NewMonitor(uuid.Nil, "api", domain.MonitorTypeHTTP, "https://a.example.com"),
+		domain.NewMonitor(uuid.Nil, "api", domain.MonitorTypeHTTP, "https://b.example.com"),
+	)
+	svc := f.service()
+
+	_, err := svc.Plan(context.Background(), f.userID, &domain.ConfigSpec{Monitors: []domain.MonitorSpec{}})
+	assert.True(t, errors.Is(err, domain.ErrInvalidConfig))
+
+	_, err = svc.Plan(context.Background(), f.userID, &domain.ConfigSpec{AlertChannels: []domain.AlertChannelSpec{}})
+	assert.NoError(t, err, "unmanaged sections may hold duplicates")
+}
+
+// applyTxKey marks a context as inside the apply's transaction.
+type applyTxKey struct{}
+
+func TestConfigService_Apply_LocationsAndPagesInOneTransaction(t *testing.T) {
+	f := newConfigFixture()
+	eu := domain.NewAgent(f.userID, "eu", nil)
+	f.agentRepo.GetByUserIDFn = func(_ context.Context, _ uuid.UUID) ([]*domain.Agent, error) {
+		return []*domain.Agent{f.agent, eu}, nil
+	}
+
+	transactions := 0
+	f.transactor.WithTransactionFn = func(ctx context.Context, fn func(ctx context.Context) error) error {
+		transactions++
+		return fn(context.WithValue(ctx, applyTxKey{}, transactions))
+	}
+	inTx := func(ctx context.Context) bool { return ctx.Value(applyTxKey{}) == 1 }
+
+	created := make(map[uuid.UUID]bool)
+	f.monitorSvc.CreateMonitorFn = func(ctx context.Context, _, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, _ map[string]string) (*domain.Monitor, error) {
+		assert.True(t, inTx(ctx))
+		m := domain.NewMonitor(agentID, name, monitorType, target)
+		created[m.ID] = true
+		return m, nil
+	}
+	var locations []uuid.UUID
+	f.monitorSvc.UpdateMonitorFn = func(ctx context.Context, m *domain.Monitor) error {
+		assert.True(t, inTx(ctx), "locations are set in the apply's transaction")
+		locations = m.LocationAgentIDs
+		return nil
+	}
+	var pageMonitors []uuid.UUID
+	f.pageRepo.SetMonitorsFn = func(ctx context.Context, _ uuid.UUID, monitorIDs []uuid.UUID) error {
+		assert.True(t, inTx(ctx), "page monitors are set in the apply's transaction")
+		for _, id := range monitorIDs {
+			assert.True(t, created[id], "page monitor was created in the same apply")
+		}
+		pageMonitors = monitorIDs
+		return nil
+	}
+
+	spec := &domain.ConfigSpec{
+		Monitors: []domain.MonitorSpec{{
+			Name: "api", Agent: "edge", Type: domain.MonitorTypeHTTP, Target: "https://example.com",
+			Locations: &domain.LocationsSpec{Agents: []string{"edge", "eu"}, Quorum: 2},
+		}},
+		StatusPages: []domain.StatusPageSpec{{Slug: "status", Name: "Status", Monitors: []string{"api"}}},
+	}
+	result, err := f.service().Apply(context.Background(), f.userID, spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, transactions)
+	assert.Len(t, result.Changes, 2)
+	assert.ElementsMatch(t, []uuid.UUID{f.agent.ID, eu.ID}, locations)
+	assert.Len(t, pageMonitors, 1)
+}
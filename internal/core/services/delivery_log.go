@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// DeliveryLog wraps a NotifierFactory and the global notifiers so every
+// notification sent through them is recorded with each attempt's payload
+// hash, HTTP status or error, and latency. A failed delivery is retried in
+// the background with exponential backoff and dead-lettered after
+// domain.MaxDeliveryAttempts; any delivery can be sent again by hand.
+//
+// Once a failed delivery is queued for retry the notifier reports success,
+// so callers do not retry it a second time.
+type DeliveryLog struct {
+	repo        ports.NotificationDeliveryRepository
+	channelRepo ports.AlertChannelRepository
+	factory     ports.NotifierFactory
+	logger      *slog.Logger
+
+	mu      sync.RWMutex
+	globals map[string]ports.Notifier // global notifiers by name
+}
+
+// NewDeliveryLog creates a new DeliveryLog.
+func NewDeliveryLog(
+	repo ports.NotificationDeliveryRepository,
+	channelRepo ports.AlertChannelRepository,
+	factory ports.NotifierFactory,
+	logger *slog.Logger,
+) *DeliveryLog {
+	return &DeliveryLog{
+		repo:        repo,
+		channelRepo: channelRepo,
+		factory:     factory,
+		logger:      logger,
+		globals:     make(map[string]ports.Notifier),
+	}
+}
+
+// BuildFromChannel creates a Notifier for the channel whose deliveries are
+// recorded and retried.
+func (l *DeliveryLog) BuildFromChannel(channel *domain.AlertChannel) (ports.Notifier, error) {
+	notifier, err := l.factory.BuildFromChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+	return &loggedNotifier{
+		log:         l,
+		next:        notifier,
+		userID:      &channel.UserID,
+		channelID:   &channel.ID,
+		channelName: channel.Name,
+		channelType: string(channel.Type),
+	}, nil
+}
+
+// WrapGlobal records and retries the deliveries of a global notifier. name
+// is its channel type, e.g. "slack", and must be unique.
+func (l *DeliveryLog) WrapGlobal(name string, notifier ports.Notifier) ports.Notifier {
+	l.mu.Lock()
+	l.globals[name] = notifier
+	l.mu.Unlock()
+	return &loggedNotifier{log: l, next: notifier, channelName: name, channelType: name}
+}
+
+// ListDeliveries returns the deliveries a filter selects, newest first.
+func (l *DeliveryLog) ListDeliveries(ctx context.Context, filter domain.NotificationDeliveryFilter) ([]*domain.NotificationDelivery, error) {
+	deliveries, err := l.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("deliveryLog.ListDeliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns a delivery and its attempts, or nil when it does not
+// exist.
+func (l *DeliveryLog) GetDelivery(ctx context.Context, id uuid.UUID) (*domain.NotificationDelivery, []*domain.NotificationAttempt, error) {
+	d, err := l.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deliveryLog.GetDelivery: %w", err)
+	}
+	if d == nil {
+		return nil, nil, nil
+	}
+	attempts, err := l.repo.GetAttempts(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deliveryLog.GetDelivery: %w", err)
+	}
+	return d, attempts, nil
+}
+
+// Redeliver sends a delivery again now, whatever its status, and returns
+// the attempt. A failed attempt is retried like any other unless the
+// delivery has used up its attempts. Returns domain.ErrDeliveryTargetGone
+// when the delivery's channel no longer exists or is disabled.
+func (l *DeliveryLog) Redeliver(ctx context.Context, d *domain.NotificationDelivery) (*domain.NotificationAttempt, error) {
+	notifier, _, err := l.target(ctx, d)
+	if err != nil {
+		return nil, fmt.Errorf("deliveryLog.Redeliver(%s): %w", d.ID, err)
+	}
+
+	a, _ := l.attempt(ctx, d, func(ctx context.Context) error {
+		return sendPayload(ctx, notifier, d.Event, d.Payload)
+	})
+	if err := l.repo.Update(ctx, d); err != nil {
+		return nil, fmt.Errorf("deliveryLog.Redeliver(%s): %w", d.ID, err)
+	}
+	l.createAttempt(ctx, a)
+	return a, nil
+}
+
+// RetryDue retries the failed deliveries due by now and returns how many
+// were delivered.
+func (l *DeliveryLog) RetryDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := l.repo.GetDue(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("deliveryLog.RetryDue: %w", err)
+	}
+
+	delivered := 0
+	for _, d := range due {
+		if l.retry(ctx, d, now) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// Prune deletes deliveries created before the given time that are no longer
+// being retried.
+func (l *DeliveryLog) Prune(ctx context.Context, before time.Time) (int64, error) {
+	n, err := l.repo.DeleteBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("deliveryLog.Prune: %w", err)
+	}
+	return n, nil
+}
+
+// retry makes the next attempt of a failed delivery.
+func (l *DeliveryLog) retry(ctx context.Context, d *domain.NotificationDelivery, now time.Time) bool {
+	logger := l.logger.With(
+		slog.String("delivery_id", d.ID.String()),
+		slog.String("channel", d.ChannelName),
+		slog.String("event", d.Event),
+	)
+
+	notifier, channel, err := l.target(ctx, d)
+	if errors.Is(err, domain.ErrDeliveryTargetGone) {
+		d.DeadLetter(err.Error(), now)
+		l.update(ctx, d)
+		return false
+	}
+	if err != nil {
+		logger.Error("delivery log: failed to build notifier", slog.String("error", err.Error()))
+		return false
+	}
+	// Retries wait for a closed delivery window to open.
+	if channel != nil && channel.DeliverySchedule != nil && !channel.DeliverySchedule.IsOpen(now) {
+		next := channel.DeliverySchedule.NextOpen(now)
+		d.NextAttemptAt = &next
+		l.update(ctx, d)
+		return false
+	}
+
+	a, sendErr := l.attempt(ctx, d, func(ctx context.Context) error {
+		return sendPayload(ctx, notifier, d.Event, d.Payload)
+	})
+	l.update(ctx, d)
+	l.createAttempt(ctx, a)
+
+	switch d.Status {
+	case domain.DeliveryStatusDead:
+		logger.Error("delivery log: notification dead-lettered",
+			slog.Int("attempts", d.Attempts),
+			slog.String("error", sendErr.Error()),
+		)
+	case domain.DeliveryStatusRetrying:
+		logger.Warn("delivery log: retry failed",
+			slog.Int("attempts", d.Attempts),
+			slog.Time("next_attempt_at", *d.NextAttemptAt),
+			slog.String("error", sendErr.Error()),
+		)
+	}
+	return sendErr == nil
+}
+
+// target returns the notifier a delivery is sent again through and, for a
+// channel delivery, its channel.
+func (l *DeliveryLog) target(ctx context.Context, d *domain.NotificationDelivery) (ports.Notifier, *domain.AlertChannel, error) {
+	if d.ChannelID == nil {
+		l.mu.RLock()
+		notifier, ok := l.globals[d.ChannelName]
+		l.mu.RUnlock()
+		if !ok {
+			return nil, nil, domain.ErrDeliveryTargetGone
+		}
+		return notifier, nil, nil
+	}
+
+	channel, err := l.channelRepo.GetByID(ctx, *d.ChannelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if channel == nil || !channel.Enabled {
+		return nil, nil, domain.ErrDeliveryTargetGone
+	}
+	notifier, err := l.factory.BuildFromChannel(channel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return notifier, channel, nil
+}
+
+// attempt sends a delivery and updates it with the outcome, returning the
+// attempt made and the send error.
+func (l *DeliveryLog) attempt(ctx context.Context, d *domain.NotificationDelivery, send func(context.Context) error) (*domain.NotificationAttempt, error) {
+	sendCtx, receipt := ports.WithDeliveryReceipt(ctx)
+	start := time.Now()
+	sendErr := send(sendCtx)
+	now := time.Now()
+
+	a := &domain.NotificationAttempt{
+		ID:          uuid.New(),
+		DeliveryID:  d.ID,
+		Attempt:     d.Attempts + 1,
+		PayloadHash: receipt.PayloadHash,
+		StatusCode:  receipt.StatusCode,
+		LatencyMs:   int(now.Sub(start).Milliseconds()),
+		CreatedAt:   now,
+	}
+	if sendErr != nil {
+		a.Error = sendErr.Error()
+	}
+	d.RecordAttempt(sendErr, now)
+	return a, sendErr
+}
+
+func (l *DeliveryLog) update(ctx context.Context, d *domain.NotificationDelivery) {
+	if err := l.repo.Update(ctx, d); err != nil {
+		l.logger.Error("delivery log: failed to update delivery",
+			slog.String("delivery_id", d.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// createAttempt records an attempt. A failure is logged and does not fail
+// the notification.
+func (l *DeliveryLog) createAttempt(ctx context.Context, a *domain.NotificationAttempt) {
+	if err := l.repo.CreateAttempt(ctx, a); err != nil {
+		l.logger.Warn("delivery log: failed to record attempt",
+			slog.String("delivery_id", a.DeliveryID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// loggedNotifier records the deliveries of the notifier it wraps.
+type loggedNotifier struct {
+	log         *DeliveryLog
+	next        ports.Notifier
+	userID      *uuid.UUID
+	channelID   *uuid.UUID
+	channelName string
+	channelType string
+}
+
+func (n *loggedNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	return n.deliver(ctx, string(domain.AlertEventIncidentOpened), monitor.Name, &incident.ID, incidentPayload(incident, monitor), func(ctx context.Context) error {
+		return n.next.NotifyIncidentOpened(ctx, incident, monitor)
+	})
+}
+
+func (n *loggedNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	return n.deliver(ctx, string(domain.AlertEventIncidentResolved), monitor.Name, &incident.ID, incidentPayload(incident, monitor), func(ctx context.Context) error {
+		return n.next.NotifyIncidentResolved(ctx, incident, monitor)
+	})
+}
+
+func (n *loggedNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	payload := domain.DeferredPayload{Agent: agentPayload(agent), Count: affectedMonitors}
+	return n.deliver(ctx, string(domain.AlertEventAgentOffline), agent.Name, nil, payload, func(ctx context.Context) error {
+		return n.next.NotifyAgentOffline(ctx, agent, affectedMonitors)
+	})
+}
+
+func (n *loggedNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	payload := domain.DeferredPayload{Agent: agentPayload(agent), Count: resolvedIncidents}
+	return n.deliver(ctx, string(domain.AlertEventAgentOnline), agent.Name, nil, payload, func(ctx context.Context) error {
+		return n.next.NotifyAgentOnline(ctx, agent, resolvedIncidents)
+	})
+}
+
+func (n *loggedNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	payload := domain.DeferredPayload{Agent: agentPayload(agent), WindowName: windowName}
+	return n.deliver(ctx, domain.DeferredEventMaintenance, agent.Name, nil, payload, func(ctx context.Context) error {
+		return n.next.NotifyAgentMaintenance(ctx, agent, windowName)
+	})
+}
+
+func (n *loggedNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	subject := fmt.Sprintf("%d events", len(digest.Entries))
+	return n.deliver(ctx, domain.NotificationEventDigest, subject, nil, domain.DeferredPayload{Digest: digest}, func(ctx context.Context) error {
+		return n.next.NotifyDigest(ctx, digest)
+	})
+}
+
+// deliver makes the first attempt of a delivery and records it. A failed
+// delivery that is queued for retry is not reported as an error.
+func (n *loggedNotifier) deliver(
+	ctx context.Context,
+	event, subject string,
+	incidentID *uuid.UUID,
+	payload domain.DeferredPayload,
+	send func(context.Context) error,
+) error {
+	d := domain.NewNotificationDelivery(n.userID, n.channelID, n.channelName, n.channelType, event, subject, payload)
+	d.IncidentID = incidentID
+
+	a, sendErr := n.log.attempt(ctx, d, send)
+	if err := n.log.repo.Create(ctx, d); err != nil {
+		n.log.logger.Warn("delivery log: failed to record delivery",
+			slog.String("channel", n.channelName),
+			slog.String("event", event),
+			slog.String("error", err.Error()),
+		)
+		return sendErr
+	}
+	n.log.createAttempt(ctx, a)
+
+	if sendErr != nil && d.Status == domain.DeliveryStatusRetrying {
+		n.log.logger.Warn("delivery log: notification failed, retrying",
+			slog.String("delivery_id", d.ID.String()),
+			slog.String("channel", n.channelName),
+			slog.String("event", event),
+			slog.Time("next_attempt_at", *d.NextAttemptAt),
+			slog.String("error", sendErr.Error()),
+		)
+		return nil
+	}
+	return sendErr
+}
+
+// agentPayload copies the agent fields notifiers read.
+func agentPayload(agent *domain.Agent) *domain.Agent {
+	return &domain.Agent{ID: agent.ID, UserID: agent.UserID, Name: agent.Name}
+}
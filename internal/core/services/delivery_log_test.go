@@ -0,0 +1,222 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+// memDeliveries is an in-memory notification delivery repository.
+type memDeliveries struct {
+	deliveries map[uuid.UUID]*domain.NotificationDelivery
+	attempts   []*domain.NotificationAttempt
+}
+
+func (m *memDeliveries) repo() *mocks.MockNotificationDeliveryRepository {
+	m.deliveries = make(map[uuid.UUID]*domain.NotificationDelivery)
+	return &mocks.MockNotificationDeliveryRepository{
+		CreateFn: func(_ context.Context, d *domain.NotificationDelivery) error {
+			m.deliveries[d.ID] = d
+			return nil
+		},
+		CreateAttemptFn: func(_ context.Context, a *domain.NotificationAttempt) error {
+			m.attempts = append(m.attempts, a)
+			return nil
+		},
+		GetDueFn: func(_ context.Context, now time.Time) ([]*domain.NotificationDelivery, error) {
+			var due []*domain.NotificationDelivery
+			for _, d := range m.deliveries {
+				if d.Status == domain.DeliveryStatusRetrying && !d.NextAttemptAt.After(now) {
+					due = append(due, d)
+				}
+			}
+			return due, nil
+		},
+	}
+}
+
+// only returns the single recorded delivery.
+func (m *memDeliveries) only(t *testing.T) *domain.NotificationDelivery {
+	t.Helper()
+	require.Len(t, m.deliveries, 1)
+	for _, d := range m.deliveries {
+		return d
+	}
+	return nil
+}
+
+func newDeliveryLog(store *memDeliveries, channel *domain.AlertChannel, inner *mocks.MockNotifier) *services.DeliveryLog {
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.AlertChannel, error) {
+			return channel, nil
+		},
+	}
+	factory := &mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(_ *domain.AlertChannel) (ports.Notifier, error) {
+			return inner, nil
+		},
+	}
+	return services.NewDeliveryLog(store.repo(), channelRepo, factory, slog.Default())
+}
+
+func TestDeliveryLog_RecordsDelivery(t *testing.T) {
+	channel := throttledSlack(map[string]string{})
+	inner := &mocks.MockNotifier{
+		NotifyIncidentOpenedFn: func(ctx context.Context, _ *domain.Incident, _ *domain.Monitor) error {
+			receipt := ports.DeliveryReceiptFromContext(ctx)
+			receipt.SetPayload([]byte(`{"text":"down"}`))
+			receipt.SetStatus(http.StatusOK)
+			return nil
+		},
+	}
+	store := &memDeliveries{}
+	deliveries := newDeliveryLog(store, channel, inner)
+
+	notifier, err := deliveries.BuildFromChannel(channel)
+	require.NoError(t, err)
+	incident := &domain.Incident{ID: uuid.New(), MonitorID: uuid.New(), Status: domain.IncidentStatusOpen}
+	require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), incident, &domain.Monitor{ID: incident.MonitorID, Name: "api"}))
+
+	d := store.only(t)
+	assert.Equal(t, domain.DeliveryStatusDelivered, d.Status)
+	assert.Equal(t, channel.ID, *d.ChannelID)
+	assert.Equal(t, channel.UserID, *d.UserID)
+	assert.Equal(t, incident.ID, *d.IncidentID)
+	assert.Equal(t, "api", d.Subject)
+	require.Len(t, store.attempts, 1)
+	assert.Equal(t, 1, store.attempts[0].Attempt)
+	assert.Equal(t, http.StatusOK, store.attempts[0].StatusCode)
+	assert.Len(t, store.attempts[0].PayloadHash, 64)
+}
+
+func TestDeliveryLog_RetriesThenDeadLetters(t *testing.T) {
+	channel := throttledSlack(map[string]string{})
+	sends := 0
+	inner := &mocks.MockNotifier{
+		NotifyAgentOfflineFn: func(ctx context.Context, _ *domain.Agent, _ int) error {
+			sends++
+			ports.DeliveryReceiptFromContext(ctx).SetStatus(http.StatusInternalServerError)
+			return errors.New("unexpected status code: 500")
+		},
+	}
+	store := &memDeliveries{}
+	deliveries := newDeliveryLog(store, channel, inner)
+	ctx := context.Background()
+
+	notifier, err := deliveries.BuildFromChannel(channel)
+	require.NoError(t, err)
+	require.NoError(t, notifier.NotifyAgentOffline(ctx, &domain.Agent{ID: uuid.New(), Name: "edge"}, 2),
+		"a queued retry is not reported as a failure")
+
+	d := store.only(t)
+	for attempt := 1; attempt < domain.MaxDeliveryAttempts; attempt++ {
+		assert.Equal(t, domain.DeliveryStatusRetrying, d.Status)
+		assert.Equal(t, attempt, d.Attempts)
+		delivered, err := deliveries.RetryDue(ctx, *d.NextAttemptAt)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	}
+
+	assert.Equal(t, domain.DeliveryStatusDead, d.Status)
+	assert.Nil(t, d.NextAttemptAt)
+	assert.Equal(t, domain.MaxDeliveryAttempts, sends)
+	require.Len(t, store.attempts, domain.MaxDeliveryAttempts)
+	assert.Equal(t, http.StatusInternalServerError, store.attempts[4].StatusCode)
+	assert.Equal(t, "unexpected status code: 500", store.attempts[4].Error)
+}
+
+func TestDeliveryLog_Redeliver(t *testing.T) {
+	channel := throttledSlack(map[string]string{})
+	failing := true
+	inner := &mocks.MockNotifier{
+		NotifyAgentOnlineFn: func(_ context.Context, agent *domain.Agent, resolved int) error {
+			assert.Equal(t, "edge", agent.Name)
+			assert.Equal(t, 3, resolved)
+			if failing {
+				return errors.New("slack is down")
+			}
+			return nil
+		},
+	}
+	store := &memDeliveries{}
+	deliveries := newDeliveryLog(store, channel, inner)
+	ctx := context.Background()
+
+	d := domain.NewNotificationDelivery(&channel.UserID, &channel.ID, channel.Name, string(channel.Type),
+		string(domain.AlertEventAgentOnline), "edge", domain.DeferredPayload{Agent: &domain.Agent{Name: "edge"}, Count: 3})
+	d.DeadLetter("slack is down", time.Now())
+
+	a, err := deliveries.Redeliver(ctx, d)
+	require.NoError(t, err, "a failed attempt is not an error")
+	assert.Equal(t, "slack is down", a.Error)
+	assert.Equal(t, domain.DeliveryStatusRetrying, d.Status)
+
+	failing = false
+	a, err = deliveries.Redeliver(ctx, d)
+	require.NoError(t, err)
+	assert.Empty(t, a.Error)
+	assert.Equal(t, 2, a.Attempt)
+	assert.Equal(t, domain.DeliveryStatusDelivered, d.Status)
+}
+
+func TestDeliveryLog_DeletedChannel(t *testing.T) {
+	store := &memDeliveries{}
+	deliveries := newDeliveryLog(store, nil, &mocks.MockNotifier{})
+	ctx := context.Background()
+
+	channelID, userID := uuid.New(), uuid.New()
+	d := domain.NewNotificationDelivery(&userID, &channelID, "ops", string(domain.AlertChannelSlack),
+		string(domain.AlertEventAgentOffline), "edge", domain.DeferredPayload{Agent: &domain.Agent{Name: "edge"}})
+	d.RecordAttempt(errors.New("timeout"), time.Now())
+	store.deliveries[d.ID] = d
+
+	_, err := deliveries.Redeliver(ctx, d)
+	assert.True(t, errors.Is(err, domain.ErrDeliveryTargetGone))
+
+	_, err = deliveries.RetryDue(ctx, *d.NextAttemptAt)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusDead, d.Status)
+	assert.Equal(t, 1, d.Attempts, "dead-lettered without another attempt")
+}
+
+func TestDeliveryLog_RetriesGlobalNotifier(t *testing.T) {
+	sends := 0
+	global := &mocks.MockNotifier{
+		NotifyAgentOfflineFn: func(_ context.Context, _ *domain.Agent, _ int) error {
+			sends++
+			if sends == 1 {
+				return errors.New("smtp: connection refused")
+			}
+			return nil
+		},
+	}
+	store := &memDeliveries{}
+	deliveries := newDeliveryLog(store, nil, &mocks.MockNotifier{})
+	ctx := context.Background()
+
+	notifier := deliveries.WrapGlobal("email", global)
+	require.NoError(t, notifier.NotifyAgentOffline(ctx, &domain.Agent{ID: uuid.New(), Name: "edge"}, 1))
+
+	d := store.only(t)
+	assert.Nil(t, d.UserID)
+	assert.Nil(t, d.ChannelID)
+	assert.Equal(t, "email", d.ChannelName)
+
+	delivered, err := deliveries.RetryDue(ctx, *d.NextAttemptAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, domain.DeliveryStatusDelivered, d.Status)
+	assert.Equal(t, 2, sends)
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// DeliveryScheduler wraps a NotifierFactory so channels with a delivery
+// schedule only send inside its windows. Outside them an event is dropped,
+// held in a persisted queue until the next window opens, or sent only if it
+// is about a critical incident, as the schedule's policy says.
+type DeliveryScheduler struct {
+	deferredRepo ports.DeferredNotificationRepository
+	logRepo      ports.NotificationLogRepository
+	channelRepo  ports.AlertChannelRepository
+	factory      ports.NotifierFactory
+	logger       *slog.Logger
+}
+
+// NewDeliveryScheduler creates a new DeliveryScheduler. Dropped events are
+// recorded in the notification log.
+func NewDeliveryScheduler(
+	deferredRepo ports.DeferredNotificationRepository,
+	logRepo ports.NotificationLogRepository,
+	channelRepo ports.AlertChannelRepository,
+	factory ports.NotifierFactory,
+	logger *slog.Logger,
+) *DeliveryScheduler {
+	return &DeliveryScheduler{
+		deferredRepo: deferredRepo,
+		logRepo:      logRepo,
+		channelRepo:  channelRepo,
+		factory:      factory,
+		logger:       logger,
+	}
+}
+
+// BuildFromChannel creates a Notifier for the channel that follows its
+// delivery schedule.
+func (s *DeliveryScheduler) BuildFromChannel(channel *domain.AlertChannel) (ports.Notifier, error) {
+	notifier, err := s.factory.BuildFromChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+	if channel.DeliverySchedule == nil {
+		return notifier, nil
+	}
+	return &scheduledNotifier{scheduler: s, next: notifier, channel: channel}, nil
+}
+
+// DeliverDue sends the deferred notifications due by now and returns how
+// many were delivered. A failed delivery is retried with a growing delay up
+// to domain.MaxDeferredAttempts times.
+func (s *DeliveryScheduler) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.deferredRepo.GetDue(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("deliveryScheduler.DeliverDue: %w", err)
+	}
+
+	delivered := 0
+	for _, n := range due {
+		if s.deliver(ctx, n, now) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver sends one deferred notification and removes it from the queue,
+// or reschedules it.
+func (s *DeliveryScheduler) deliver(ctx context.Context, n *domain.DeferredNotification, now time.Time) bool {
+	logger := s.logger.With(
+		slog.String("notification_id", n.ID.String()),
+		slog.String("channel_id", n.ChannelID.String()),
+	)
+
+	channel, err := s.channelRepo.GetByID(ctx, n.ChannelID)
+	if err != nil {
+		logger.Error("delivery schedule: failed to get channel", slog.String("error", err.Error()))
+		return false
+	}
+	if channel == nil || !channel.Enabled {
+		s.remove(ctx, n)
+		return false
+	}
+	// The schedule may have changed since the notification was deferred.
+	if sched := channel.DeliverySchedule; sched != nil && !sched.IsOpen(now) {
+		if err := s.deferredRepo.Reschedule(ctx, n.ID, sched.NextOpen(now), n.Attempts); err != nil {
+			logger.Error("delivery schedule: failed to reschedule", slog.String("error", err.Error()))
+		}
+		return false
+	}
+
+	notifier, err := s.factory.BuildFromChannel(channel)
+	if err == nil {
+		err = sendPayload(ctx, notifier, n.Event, n.Payload)
+	}
+	if err != nil {
+		attempts := n.Attempts + 1
+		if attempts >= domain.MaxDeferredAttempts {
+			logger.Error("delivery schedule: giving up on deferred notification",
+				slog.Int("attempts", attempts),
+				slog.String("error", err.Error()),
+			)
+			s.remove(ctx, n)
+			return false
+		}
+		logger.Warn("delivery schedule: deferred notification failed, retrying",
+			slog.Int("attempts", attempts),
+			slog.String("error", err.Error()),
+		)
+		if err := s.deferredRepo.Reschedule(ctx, n.ID, now.Add(time.Duration(attempts)*time.Minute), attempts); err != nil {
+			logger.Error("delivery schedule: failed to reschedule", slog.String("error", err.Error()))
+		}
+		return false
+	}
+
+	s.remove(ctx, n)
+	return true
+}
+
+func (s *DeliveryScheduler) remove(ctx context.Context, n *domain.DeferredNotification) {
+	if err := s.deferredRepo.Delete(ctx, n.ID); err != nil {
+		s.logger.Error("delivery schedule: failed to remove deferred notification",
+			slog.String("notification_id", n.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// sendPayload sends an event held in a payload: a deferred notification,
+// or a delivery that is retried or redelivered.
+func sendPayload(ctx context.Context, notifier ports.Notifier, event string, p domain.DeferredPayload) error {
+	switch event {
+	case string(domain.AlertEventIncidentOpened), string(domain.AlertEventIncidentResolved):
+		if p.Incident == nil || p.Monitor == nil {
+			return fmt.Errorf("%s payload has no incident", event)
+		}
+		p.Incident.AlertContext = p.AlertContext
+		if event == string(domain.AlertEventIncidentOpened) {
+			return notifier.NotifyIncidentOpened(ctx, p.Incident, p.Monitor)
+		}
+		return notifier.NotifyIncidentResolved(ctx, p.Incident, p.Monitor)
+	case string(domain.AlertEventAgentOffline), string(domain.AlertEventAgentOnline), domain.DeferredEventMaintenance:
+		if p.Agent == nil {
+			return fmt.Errorf("%s payload has no agent", event)
+		}
+		switch event {
+		case string(domain.AlertEventAgentOffline):
+			return notifier.NotifyAgentOffline(ctx, p.Agent, p.Count)
+		case string(domain.AlertEventAgentOnline):
+			return notifier.NotifyAgentOnline(ctx, p.Agent, p.Count)
+		}
+		return notifier.NotifyAgentMaintenance(ctx, p.Agent, p.WindowName)
+	case domain.NotificationEventDigest:
+		if p.Digest == nil {
+			return fmt.Errorf("%s payload has no digest", event)
+		}
+		return notifier.NotifyDigest(ctx, p.Digest)
+	}
+	return fmt.Errorf("unknown event %q", event)
+}
+
+// incidentPayload returns the payload an incident event is sent again from.
+func incidentPayload(incident *domain.Incident, monitor *domain.Monitor) domain.DeferredPayload {
+	return domain.DeferredPayload{
+		Incident:     incident,
+		AlertContext: incident.AlertContext,
+		Monitor: &domain.Monitor{
+			ID: monitor.ID, AgentID: monitor.AgentID, Name: monitor.Name,
+			Type: monitor.Type, Target: monitor.Target, Metadata: monitor.Metadata,
+		},
+	}
+}
+
+// scheduledNotifier applies its channel's delivery schedule to the notifier
+// it wraps.
+type scheduledNotifier struct {
+	scheduler *DeliveryScheduler
+	next      ports.Notifier
+	channel   *domain.AlertChannel
+}
+
+func (n *scheduledNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	return n.notifyIncident(ctx, domain.AlertEventIncidentOpened, incident, monitor, n.next.NotifyIncidentOpened)
+}
+
+func (n *scheduledNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	return n.notifyIncident(ctx, domain.AlertEventIncidentResolved, incident, monitor, n.next.NotifyIncidentResolved)
+}
+
+func (n *scheduledNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	return n.notifyAgent(ctx, string(domain.AlertEventAgentOffline), agent, domain.DeferredPayload{Count: affectedMonitors}, func() error {
+		return n.next.NotifyAgentOffline(ctx, agent, affectedMonitors)
+	})
+}
+
+func (n *scheduledNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	return n.notifyAgent(ctx, string(domain.AlertEventAgentOnline), agent, domain.DeferredPayload{Count: resolvedIncidents}, func() error {
+		return n.next.NotifyAgentOnline(ctx, agent, resolvedIncidents)
+	})
+}
+
+func (n *scheduledNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	return n.notifyAgent(ctx, domain.DeferredEventMaintenance, agent, domain.DeferredPayload{WindowName: windowName}, func() error {
+		return n.next.NotifyAgentMaintenance(ctx, agent, windowName)
+	})
+}
+
+// NotifyDigest is not scheduled here: digests wait for the window to open
+// before they are flushed.
+func (n *scheduledNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	return n.next.NotifyDigest(ctx, digest)
+}
+
+func (n *scheduledNotifier) notifyIncident(
+	ctx context.Context,
+	kind domain.AlertEventKind,
+	incident *domain.Incident,
+	monitor *domain.Monitor,
+	send func(context.Context, *domain.Incident, *domain.Monitor) error,
+) error {
+	severity := incident.Severity
+	if ac := incident.AlertContext; ac != nil && ac.Severity.IsValid() {
+		severity = ac.Severity
+	}
+
+	now := time.Now()
+	decision, deliverAt := n.channel.DeliverySchedule.Decide(now, severity == domain.IncidentSeverityCritical)
+	switch decision {
+	case domain.DeliveryDefer:
+		return n.deferUntil(ctx, string(kind), incidentPayload(incident, monitor), deliverAt)
+	case domain.DeliveryDrop:
+		entry := domain.NewNotificationLogEntry(&n.channel.UserID, &n.channel.ID, n.channel.Name, string(kind), monitor.Name)
+		entry.IncidentID = &incident.ID
+		entry.Severity = severity
+		n.drop(ctx, entry)
+		return nil
+	}
+	return send(ctx, incident, monitor)
+}
+
+// notifyAgent applies the schedule to an agent event. Agent events are
+// never critical, so critical_only drops them.
+func (n *scheduledNotifier) notifyAgent(ctx context.Context, event string, agent *domain.Agent, payload domain.DeferredPayload, send func() error) error {
+	decision, deliverAt := n.channel.DeliverySchedule.Decide(time.Now(), false)
+	switch decision {
+	case domain.DeliveryDefer:
+		payload.Agent = agentPayload(agent)
+		return n.deferUntil(ctx, event, payload, deliverAt)
+	case domain.DeliveryDrop:
+		n.drop(ctx, domain.NewNotificationLogEntry(&n.channel.UserID, &n.channel.ID, n.channel.Name, event, agent.Name))
+		return nil
+	}
+	return send()
+}
+
+func (n *scheduledNotifier) deferUntil(ctx context.Context, event string, payload domain.DeferredPayload, deliverAt time.Time) error {
+	deferred := domain.NewDeferredNotification(n.channel.ID, event, payload, deliverAt)
+	if err := n.scheduler.deferredRepo.Create(ctx, deferred); err != nil {
+		return fmt.Errorf("defer %s until %s: %w", event, deliverAt.Format(time.RFC3339), err)
+	}
+	return nil
+}
+
+// drop records an event dropped outside the schedule's windows.
+func (n *scheduledNotifier) drop(ctx context.Context, entry *domain.NotificationLogEntry) {
+	entry.Status = domain.NotificationStatusQuietHours
+	if err := n.scheduler.logRepo.Create(ctx, entry); err != nil {
+		n.scheduler.logger.Warn("delivery schedule: failed to write notification log",
+			slog.String("channel_id", n.channel.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
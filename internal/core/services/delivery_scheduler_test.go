@@ -0,0 +1,194 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+// closedSchedule returns a schedule whose only window opens two hours from now.
+func closedSchedule(outside domain.DeliveryPolicy) *domain.DeliverySchedule {
+	now := time.Now().UTC()
+	return &domain.DeliverySchedule{
+		Timezone: "UTC",
+		Windows: []domain.DeliveryWindow{{
+			Start: now.Add(2 * time.Hour).Format(domain.AlertRuleTimeLayout),
+			End:   now.Add(3 * time.Hour).Format(domain.AlertRuleTimeLayout),
+		}},
+		Outside: outside,
+	}
+}
+
+type schedulerFixture struct {
+	scheduler *services.DeliveryScheduler
+	notifier  ports.Notifier
+	log       *memNotificationLog
+	deferred  map[uuid.UUID]*domain.DeferredNotification
+	attempts  map[uuid.UUID]int
+}
+
+func newSchedulerFixture(t *testing.T, channel *domain.AlertChannel, inner *mocks.MockNotifier) *schedulerFixture {
+	t.Helper()
+	f := &schedulerFixture{
+		log:      &memNotificationLog{},
+		deferred: make(map[uuid.UUID]*domain.DeferredNotification),
+		attempts: make(map[uuid.UUID]int),
+	}
+	deferredRepo := &mocks.MockDeferredNotificationRepository{
+		CreateFn: func(_ context.Context, n *domain.DeferredNotification) error {
+			f.deferred[n.ID] = n
+			return nil
+		},
+		GetDueFn: func(_ context.Context, now time.Time) ([]*domain.DeferredNotification, error) {
+			var due []*domain.DeferredNotification
+			for _, n := range f.deferred {
+				if !n.DeliverAt.After(now) {
+					due = append(due, n)
+				}
+			}
+			return due, nil
+		},
+		RescheduleFn: func(_ context.Context, id uuid.UUID, deliverAt time.Time, attempts int) error {
+			f.deferred[id].DeliverAt = deliverAt
+			f.deferred[id].Attempts = attempts
+			f.attempts[id] = attempts
+			return nil
+		},
+		DeleteFn: func(_ context.Context, id uuid.UUID) error {
+			delete(f.deferred, id)
+			return nil
+		},
+	}
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.AlertChannel, error) {
+			return channel, nil
+		},
+	}
+	factory := &mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(_ *domain.AlertChannel) (ports.Notifier, error) {
+			return inner, nil
+		},
+	}
+	f.scheduler = services.NewDeliveryScheduler(deferredRepo, f.log.repo(), channelRepo, factory, slog.Default())
+	notifier, err := f.scheduler.BuildFromChannel(channel)
+	require.NoError(t, err)
+	f.notifier = notifier
+	return f
+}
+
+func TestDeliveryScheduler_DefersUntilWindowOpens(t *testing.T) {
+	channel := throttledSlack(map[string]string{})
+	channel.DeliverySchedule = closedSchedule(domain.DeliveryPolicyDefer)
+	var sent []*domain.Incident
+	inner := &mocks.MockNotifier{
+		NotifyIncidentOpenedFn: func(_ context.Context, incident *domain.Incident, _ *domain.Monitor) error {
+			sent = append(sent, incident)
+			return nil
+		},
+	}
+	f := newSchedulerFixture(t, channel, inner)
+	ctx := context.Background()
+
+	monitor := &domain.Monitor{ID: uuid.New(), Name: "blog"}
+	incident := domain.NewIncident(monitor.ID)
+	incident.AlertContext = &domain.AlertContext{Severity: domain.IncidentSeverityMinor}
+	require.NoError(t, f.notifier.NotifyIncidentOpened(ctx, incident, monitor))
+	assert.Empty(t, sent, "held outside the window")
+	require.Len(t, f.deferred, 1)
+
+	var deliverAt time.Time
+	for _, n := range f.deferred {
+		deliverAt = n.DeliverAt
+	}
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), deliverAt, 2*time.Minute)
+
+	delivered, err := f.scheduler.DeliverDue(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered, "not due yet")
+
+	delivered, err = f.scheduler.DeliverDue(ctx, deliverAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	require.Len(t, sent, 1)
+	assert.Equal(t, incident.ID, sent[0].ID)
+	require.NotNil(t, sent[0].AlertContext, "alert context survives the queue")
+	assert.Equal(t, domain.IncidentSeverityMinor, sent[0].AlertContext.Severity)
+	assert.Empty(t, f.deferred)
+}
+
+func TestDeliveryScheduler_CriticalOnly(t *testing.T) {
+	channel := throttledSlack(map[string]string{})
+	channel.DeliverySchedule = closedSchedule(domain.DeliveryPolicyCriticalOnly)
+	var opened, offline int
+	inner := &mocks.MockNotifier{
+		NotifyIncidentOpenedFn: func(_ context.Context, _ *domain.Incident, _ *domain.Monitor) error {
+			opened++
+			return nil
+		},
+		NotifyAgentOfflineFn: func(_ context.Context, _ *domain.Agent, _ int) error {
+			offline++
+			return nil
+		},
+	}
+	f := newSchedulerFixture(t, channel, inner)
+	ctx := context.Background()
+
+	monitor := &domain.Monitor{ID: uuid.New(), Name: "api"}
+	for _, severity := range []domain.IncidentSeverity{domain.IncidentSeverityCritical, domain.IncidentSeverityMinor} {
+		incident := domain.NewIncident(monitor.ID)
+		incident.Severity = severity
+		require.NoError(t, f.notifier.NotifyIncidentOpened(ctx, incident, monitor))
+	}
+	require.NoError(t, f.notifier.NotifyAgentOffline(ctx, &domain.Agent{ID: uuid.New(), Name: "edge"}, 3))
+
+	assert.Equal(t, 1, opened, "only the critical incident is sent")
+	assert.Equal(t, 0, offline)
+	assert.Equal(t, 2, f.log.count(domain.NotificationStatusQuietHours), "dropped events are logged")
+	assert.Empty(t, f.deferred)
+}
+
+func TestDeliveryScheduler_RetriesFailedDelivery(t *testing.T) {
+	channel := throttledSlack(map[string]string{})
+	inner := &mocks.MockNotifier{
+		NotifyAgentOfflineFn: func(_ context.Context, _ *domain.Agent, _ int) error {
+			return errors.New("slack is down")
+		},
+	}
+	f := newSchedulerFixture(t, channel, inner)
+	ctx := context.Background()
+
+	n := domain.NewDeferredNotification(channel.ID, string(domain.AlertEventAgentOffline),
+		domain.DeferredPayload{Agent: &domain.Agent{ID: uuid.New(), Name: "edge"}, Count: 2}, time.Now())
+	f.deferred[n.ID] = n
+
+	now := time.Now()
+	for attempt := 1; attempt < domain.MaxDeferredAttempts; attempt++ {
+		delivered, err := f.scheduler.DeliverDue(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		require.Contains(t, f.deferred, n.ID)
+		assert.Equal(t, attempt, f.attempts[n.ID])
+		now = f.deferred[n.ID].DeliverAt
+	}
+
+	_, err := f.scheduler.DeliverDue(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, f.deferred, "given up after the last attempt")
+}
+
+func TestDeliveryScheduler_UnscheduledChannelIsUnwrapped(t *testing.T) {
+	inner := &mocks.MockNotifier{}
+	f := newSchedulerFixture(t, throttledSlack(map[string]string{}), inner)
+	assert.Same(t, inner, f.notifier)
+}
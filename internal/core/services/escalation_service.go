@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/workflows"
+)
+
+// EscalationService manages escalation policies and runs them for incidents
+// that stay unacknowledged. Each escalation is a durable workflow that parks
+// on a waiting step for every level's delay; ResumeDue wakes the ones whose
+// delay has passed, and acknowledging or resolving the incident stops it.
+type EscalationService struct {
+	escalationRepo   ports.EscalationRepository
+	agentRepo        ports.AgentRepository
+	alertChannelRepo ports.AlertChannelRepository
+	userRepo         ports.UserRepository
+	workflowEngine   ports.WorkflowEngine // optional: escalations only run with the engine
+	onCallSvc        ports.OnCallService  // optional: levels that page on-call schedules
+	logger           *slog.Logger
+}
+
+// NewEscalationService creates a new EscalationService.
+func NewEscalationService(
+	escalationRepo ports.EscalationRepository,
+	agentRepo ports.AgentRepository,
+	alertChannelRepo ports.AlertChannelRepository,
+	userRepo ports.UserRepository,
+	logger *slog.Logger,
+) *EscalationService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &EscalationService{
+		escalationRepo:   escalationRepo,
+		agentRepo:        agentRepo,
+		alertChannelRepo: alertChannelRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+// SetWorkflowEngine enables escalations. Without an engine policies can be
+// managed but are never run.
+func (s *EscalationService) SetWorkflowEngine(engine ports.WorkflowEngine) {
+	s.workflowEngine = engine
+}
+
+// SetOnCallService enables levels that page whoever is on call for a schedule.
+func (s *EscalationService) SetOnCallService(svc ports.OnCallService) {
+	s.onCallSvc = svc
+}
+
+// ListPolicies returns a user's escalation policies.
+func (s *EscalationService) ListPolicies(ctx context.Context, userID uuid.UUID) ([]*domain.EscalationPolicy, error) {
+	policies, err := s.escalationRepo.GetPoliciesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("escalationService.ListPolicies: %w", err)
+	}
+	return policies, nil
+}
+
+// GetPolicy returns an escalation policy, or nil when it does not exist.
+func (s *EscalationService) GetPolicy(ctx context.Context, id uuid.UUID) (*domain.EscalationPolicy, error) {
+	policy, err := s.escalationRepo.GetPolicyByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("escalationService.GetPolicy: %w", err)
+	}
+	return policy, nil
+}
+
+// CreatePolicy validates and stores a new escalation policy.
+func (s *EscalationService) CreatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error {
+	if err := s.validate(ctx, policy); err != nil {
+		return fmt.Errorf("escalationService.CreatePolicy: %w", err)
+	}
+	if err := s.escalationRepo.CreatePolicy(ctx, policy); err != nil {
+		return fmt.Errorf("escalationService.CreatePolicy: %w", err)
+	}
+	return nil
+}
+
+// UpdatePolicy validates and stores an edited escalation policy. Escalations
+// already running keep the levels they started with.
+func (s *EscalationService) UpdatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error {
+	if err := s.validate(ctx, policy); err != nil {
+		return fmt.Errorf("escalationService.UpdatePolicy: %w", err)
+	}
+	policy.UpdatedAt = time.Now()
+	if err := s.escalationRepo.UpdatePolicy(ctx, policy); err != nil {
+		return fmt.Errorf("escalationService.UpdatePolicy: %w", err)
+	}
+	return nil
+}
+
+// DeletePolicy removes an escalation policy.
+func (s *EscalationService) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	if err := s.escalationRepo.DeletePolicy(ctx, id); err != nil {
+		return fmt.Errorf("escalationService.DeletePolicy: %w", err)
+	}
+	return nil
+}
+
+// GetEscalation returns the escalation running for an incident, or nil.
+func (s *EscalationService) GetEscalation(ctx context.Context, incidentID uuid.UUID) (*domain.Escalation, error) {
+	esc, err := s.escalationRepo.GetEscalation(ctx, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("escalationService.GetEscalation: %w", err)
+	}
+	return esc, nil
+}
+
+// validate checks the policy, that its name is unused and that every
+// channel and schedule belongs to its owner and every user exists.
+func (s *EscalationService) validate(ctx context.Context, policy *domain.EscalationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.escalationRepo.GetPolicyByName(ctx, policy.UserID, policy.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != policy.ID {
+		return fmt.Errorf("%w: a policy named %q already exists", domain.ErrInvalidEscalationPolicy, policy.Name)
+	}
+	for _, level := range policy.Levels {
+		for _, id := range level.ChannelIDs {
+			ch, err := s.alertChannelRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if ch == nil || ch.UserID != policy.UserID {
+				return fmt.Errorf("%w: alert channel %s not found", domain.ErrInvalidEscalationPolicy, id)
+			}
+		}
+		for _, id := range level.UserIDs {
+			user, err := s.userRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return fmt.Errorf("%w: user %s not found", domain.ErrInvalidEscalationPolicy, id)
+			}
+		}
+		for _, id := range level.ScheduleIDs {
+			if s.onCallSvc == nil {
+				return fmt.Errorf("%w: on-call schedules are not available", domain.ErrInvalidEscalationPolicy)
+			}
+			schedule, err := s.onCallSvc.GetSchedule(ctx, id)
+			if err != nil {
+				return err
+			}
+			if schedule == nil || schedule.UserID != policy.UserID {
+				return fmt.Errorf("%w: on-call schedule %s not found", domain.ErrInvalidEscalationPolicy, id)
+			}
+		}
+	}
+	return nil
+}
+
+// PolicyForMonitor returns the policy a monitor escalates with: the one it
+// references, else the owner's policy named by its escalation_policy tag.
+// Returns nil when neither is set.
+func (s *EscalationService) PolicyForMonitor(ctx context.Context, monitor *domain.Monitor) (*domain.EscalationPolicy, error) {
+	if monitor.EscalationPolicyID != nil {
+		policy, err := s.escalationRepo.GetPolicyByID(ctx, *monitor.EscalationPolicyID)
+		if err != nil {
+			return nil, fmt.Errorf("escalationService.PolicyForMonitor: %w", err)
+		}
+		if policy != nil {
+			return policy, nil
+		}
+	}
+
+	name := monitor.Metadata[domain.EscalationPolicyTag]
+	if name == "" {
+		return nil, nil
+	}
+	agent, err := s.agentRepo.GetByID(ctx, monitor.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("escalationService.PolicyForMonitor: get agent: %w", err)
+	}
+	if agent == nil {
+		return nil, nil
+	}
+	policy, err := s.escalationRepo.GetPolicyByName(ctx, agent.UserID, name)
+	if err != nil {
+		return nil, fmt.Errorf("escalationService.PolicyForMonitor: %w", err)
+	}
+	return policy, nil
+}
+
+// StartEscalation submits the escalation workflow for a newly opened
+// incident when its monitor has a policy.
+func (s *EscalationService) StartEscalation(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	if s.workflowEngine == nil {
+		return nil
+	}
+	policy, err := s.PolicyForMonitor(ctx, monitor)
+	if err != nil {
+		return fmt.Errorf("escalationService.StartEscalation: %w", err)
+	}
+	if policy == nil {
+		return nil
+	}
+
+	// The row is written before the workflow so its first step finds it.
+	esc := domain.NewEscalation(incident.ID, policy)
+	if err := s.escalationRepo.CreateEscalation(ctx, esc); err != nil {
+		return fmt.Errorf("escalationService.StartEscalation: %w", err)
+	}
+
+	input, err := json.Marshal(workflows.EscalationInput{IncidentID: incident.ID, MonitorID: monitor.ID})
+	if err != nil {
+		return fmt.Errorf("escalationService.StartEscalation: marshal input: %w", err)
+	}
+	wfID, err := s.workflowEngine.Submit(ctx, workflows.EscalationDef(esc, len(policy.Levels)), input)
+	if err != nil {
+		if delErr := s.escalationRepo.DeleteEscalation(ctx, incident.ID); delErr != nil {
+			s.logger.Error("failed to clean up escalation", slog.String("incident_id", incident.ID.String()), slog.String("error", delErr.Error()))
+		}
+		return fmt.Errorf("escalationService.StartEscalation: submit workflow: %w", err)
+	}
+	if err := s.escalationRepo.SetEscalationWorkflow(ctx, incident.ID, wfID); err != nil {
+		return fmt.Errorf("escalationService.StartEscalation: %w", err)
+	}
+
+	s.logger.Info("escalation started",
+		slog.String("incident_id", incident.ID.String()),
+		slog.String("policy", policy.Name),
+		slog.String("workflow_id", wfID.String()),
+	)
+	return nil
+}
+
+// StopEscalation stops an incident's escalation, if one is running.
+func (s *EscalationService) StopEscalation(ctx context.Context, incidentID uuid.UUID) error {
+	esc, err := s.escalationRepo.GetEscalation(ctx, incidentID)
+	if err != nil {
+		return fmt.Errorf("escalationService.StopEscalation: %w", err)
+	}
+	if esc == nil {
+		return nil
+	}
+
+	// Deleting the row first makes any step that still runs fall through.
+	if err := s.escalationRepo.DeleteEscalation(ctx, incidentID); err != nil {
+		return fmt.Errorf("escalationService.StopEscalation: %w", err)
+	}
+	if s.workflowEngine != nil && esc.WorkflowID != uuid.Nil {
+		if err := s.workflowEngine.Cancel(ctx, esc.WorkflowID); err != nil {
+			return fmt.Errorf("escalationService.StopEscalation: cancel workflow: %w", err)
+		}
+	}
+
+	s.logger.Info("escalation stopped",
+		slog.String("incident_id", incidentID.String()),
+		slog.Int("levels_paged", esc.Step),
+	)
+	return nil
+}
+
+// ResumeDue resumes the escalations whose delay has passed so they page
+// their next level. Escalations whose workflow has ended are forgotten.
+// Returns the number of escalations resumed.
+func (s *EscalationService) ResumeDue(ctx context.Context, now time.Time) (int, error) {
+	if s.workflowEngine == nil {
+		return 0, nil
+	}
+	due, err := s.escalationRepo.GetDueEscalations(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("escalationService.ResumeDue: %w", err)
+	}
+
+	resumed := 0
+	for _, esc := range due {
+		key := workflows.EscalationCorrelationKey(esc.IncidentID, esc.Step)
+		if err := s.workflowEngine.ResumeStep(ctx, key, nil, nil); err != nil {
+			s.logger.Warn("failed to resume escalation",
+				slog.String("incident_id", esc.IncidentID.String()),
+				slog.String("error", err.Error()),
+			)
+			s.forgetIfEnded(ctx, esc)
+			continue
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// forgetIfEnded deletes an escalation whose workflow is no longer running,
+// e.g. after it timed out or failed.
+func (s *EscalationService) forgetIfEnded(ctx context.Context, esc *domain.Escalation) {
+	if esc.WorkflowID != uuid.Nil {
+		wf, err := s.workflowEngine.Status(ctx, esc.WorkflowID)
+		if err == nil && (wf.Status == domain.WorkflowStatusPending || wf.Status == domain.WorkflowStatusRunning) {
+			return
+		}
+	}
+	if err := s.escalationRepo.DeleteEscalation(ctx, esc.IncidentID); err != nil {
+		s.logger.Error("failed to delete ended escalation",
+			slog.String("incident_id", esc.IncidentID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
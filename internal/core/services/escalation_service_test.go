@@ -0,0 +1,275 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+	"github.com/sylvester-francis/watchdog/internal/workflows"
+)
+
+func newTestEscalationService(repo *mocks.MockEscalationRepository, agentRepo *mocks.MockAgentRepository, channelRepo *mocks.MockAlertChannelRepository) *services.EscalationService {
+	return services.NewEscalationService(repo, agentRepo, channelRepo, &mocks.MockUserRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.User, error) {
+			return &domain.User{ID: id}, nil
+		},
+	}, slog.Default())
+}
+
+func TestEscalationService_CreatePolicy_Validates(t *testing.T) {
+	userID := uuid.New()
+	ownChannel, otherChannel := uuid.New(), uuid.New()
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.AlertChannel, error) {
+			switch id {
+			case ownChannel:
+				return &domain.AlertChannel{ID: id, UserID: userID}, nil
+			case otherChannel:
+				return &domain.AlertChannel{ID: id, UserID: uuid.New()}, nil
+			}
+			return nil, nil
+		},
+	}
+	existing := &domain.EscalationPolicy{ID: uuid.New(), UserID: userID, Name: "taken"}
+	created := 0
+	repo := &mocks.MockEscalationRepository{
+		GetPolicyByNameFn: func(_ context.Context, _ uuid.UUID, name string) (*domain.EscalationPolicy, error) {
+			if name == existing.Name {
+				return existing, nil
+			}
+			return nil, nil
+		},
+		CreatePolicyFn: func(_ context.Context, _ *domain.EscalationPolicy) error {
+			created++
+			return nil
+		},
+	}
+	svc := newTestEscalationService(repo, &mocks.MockAgentRepository{}, channelRepo)
+
+	policy := &domain.EscalationPolicy{ID: uuid.New(), UserID: userID, Name: "primary", Levels: []domain.EscalationLevel{{ChannelIDs: []uuid.UUID{ownChannel}}}}
+	require.NoError(t, svc.CreatePolicy(context.Background(), policy))
+	assert.Equal(t, 1, created)
+
+	foreign := &domain.EscalationPolicy{ID: uuid.New(), UserID: userID, Name: "foreign", Levels: []domain.EscalationLevel{{ChannelIDs: []uuid.UUID{otherChannel}}}}
+	err := svc.CreatePolicy(context.Background(), foreign)
+	assert.True(t, errors.Is(err, domain.ErrInvalidEscalationPolicy))
+
+	duplicate := &domain.EscalationPolicy{ID: uuid.New(), UserID: userID, Name: "taken", Levels: []domain.EscalationLevel{{ChannelIDs: []uuid.UUID{ownChannel}}}}
+	err = svc.CreatePolicy(context.Background(), duplicate)
+	assert.True(t, errors.Is(err, domain.ErrInvalidEscalationPolicy))
+	assert.Equal(t, 1, created)
+
+	// Renaming a policy to its own name is not a duplicate.
+	existing.Levels = []domain.EscalationLevel{{ChannelIDs: []uuid.UUID{ownChannel}}}
+	require.NoError(t, svc.UpdatePolicy(context.Background(), existing))
+}
+
+func TestEscalationService_PolicyForMonitor(t *testing.T) {
+	userID, agentID := uuid.New(), uuid.New()
+	byID := &domain.EscalationPolicy{ID: uuid.New(), UserID: userID, Name: "direct"}
+	byTag := &domain.EscalationPolicy{ID: uuid.New(), UserID: userID, Name: "database"}
+	repo := &mocks.MockEscalationRepository{
+		GetPolicyByIDFn: func(_ context.Context, id uuid.UUID) (*domain.EscalationPolicy, error) {
+			if id == byID.ID {
+				return byID, nil
+			}
+			return nil, nil
+		},
+		GetPolicyByNameFn: func(_ context.Context, owner uuid.UUID, name string) (*domain.EscalationPolicy, error) {
+			if owner == userID && name == byTag.Name {
+				return byTag, nil
+			}
+			return nil, nil
+		},
+	}
+	agentRepo := &mocks.MockAgentRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Agent, error) {
+			return &domain.Agent{ID: id, UserID: userID}, nil
+		},
+	}
+	svc := newTestEscalationService(repo, agentRepo, &mocks.MockAlertChannelRepository{})
+	ctx := context.Background()
+
+	got, err := svc.PolicyForMonitor(ctx, &domain.Monitor{AgentID: agentID, EscalationPolicyID: &byID.ID})
+	require.NoError(t, err)
+	assert.Same(t, byID, got)
+
+	got, err = svc.PolicyForMonitor(ctx, &domain.Monitor{AgentID: agentID, Metadata: map[string]string{domain.EscalationPolicyTag: "database"}})
+	require.NoError(t, err)
+	assert.Same(t, byTag, got)
+
+	got, err = svc.PolicyForMonitor(ctx, &domain.Monitor{AgentID: agentID})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestEscalationService_StartEscalation(t *testing.T) {
+	policy := &domain.EscalationPolicy{
+		ID:     uuid.New(),
+		Name:   "primary",
+		Levels: []domain.EscalationLevel{{DelayMinutes: 10, ChannelIDs: []uuid.UUID{uuid.New()}}},
+		Repeat: 1,
+	}
+	monitor := &domain.Monitor{ID: uuid.New(), EscalationPolicyID: &policy.ID}
+	incident := domain.NewIncident(monitor.ID)
+
+	var created *domain.Escalation
+	var workflowID uuid.UUID
+	repo := &mocks.MockEscalationRepository{
+		GetPolicyByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.EscalationPolicy, error) {
+			return policy, nil
+		},
+		CreateEscalationFn: func(_ context.Context, esc *domain.Escalation) error {
+			created = esc
+			return nil
+		},
+		SetEscalationWorkflowFn: func(_ context.Context, _, wfID uuid.UUID) error {
+			workflowID = wfID
+			return nil
+		},
+	}
+	submittedID := uuid.New()
+	var submitted ports.WorkflowDefinition
+	var input workflows.EscalationInput
+	engine := &mocks.MockWorkflowEngine{
+		SubmitFn: func(_ context.Context, def ports.WorkflowDefinition, raw json.RawMessage) (uuid.UUID, error) {
+			submitted = def
+			require.NoError(t, json.Unmarshal(raw, &input))
+			return submittedID, nil
+		},
+	}
+	svc := newTestEscalationService(repo, &mocks.MockAgentRepository{}, &mocks.MockAlertChannelRepository{})
+
+	// Without a workflow engine nothing is started.
+	require.NoError(t, svc.StartEscalation(context.Background(), incident, monitor))
+	assert.Nil(t, created)
+
+	svc.SetWorkflowEngine(engine)
+	require.NoError(t, svc.StartEscalation(context.Background(), incident, monitor))
+	require.NotNil(t, created)
+	assert.Equal(t, incident.ID, created.IncidentID)
+	assert.Len(t, created.Steps, 2)
+	assert.Equal(t, submittedID, workflowID)
+	assert.Equal(t, "escalation", submitted.Name)
+	assert.Equal(t, workflows.EscalationInput{IncidentID: incident.ID, MonitorID: monitor.ID}, input)
+}
+
+func TestEscalationService_StartEscalation_SubmitFails(t *testing.T) {
+	policy := &domain.EscalationPolicy{ID: uuid.New(), Levels: []domain.EscalationLevel{{ChannelIDs: []uuid.UUID{uuid.New()}}}}
+	deleted := false
+	repo := &mocks.MockEscalationRepository{
+		GetPolicyByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.EscalationPolicy, error) {
+			return policy, nil
+		},
+		DeleteEscalationFn: func(_ context.Context, _ uuid.UUID) error {
+			deleted = true
+			return nil
+		},
+	}
+	svc := newTestEscalationService(repo, &mocks.MockAgentRepository{}, &mocks.MockAlertChannelRepository{})
+	svc.SetWorkflowEngine(&mocks.MockWorkflowEngine{
+		SubmitFn: func(_ context.Context, _ ports.WorkflowDefinition, _ json.RawMessage) (uuid.UUID, error) {
+			return uuid.Nil, errors.New("db down")
+		},
+	})
+
+	monitor := &domain.Monitor{ID: uuid.New(), EscalationPolicyID: &policy.ID}
+	err := svc.StartEscalation(context.Background(), domain.NewIncident(monitor.ID), monitor)
+	require.Error(t, err)
+	assert.True(t, deleted, "the escalation row should be cleaned up")
+}
+
+func TestEscalationService_StopEscalation(t *testing.T) {
+	incidentID, workflowID := uuid.New(), uuid.New()
+	deleted := false
+	repo := &mocks.MockEscalationRepository{
+		GetEscalationFn: func(_ context.Context, id uuid.UUID) (*domain.Escalation, error) {
+			return &domain.Escalation{IncidentID: id, WorkflowID: workflowID}, nil
+		},
+		DeleteEscalationFn: func(_ context.Context, id uuid.UUID) error {
+			deleted = id == incidentID
+			return nil
+		},
+	}
+	var cancelled uuid.UUID
+	svc := newTestEscalationService(repo, &mocks.MockAgentRepository{}, &mocks.MockAlertChannelRepository{})
+	svc.SetWorkflowEngine(&mocks.MockWorkflowEngine{
+		CancelFn: func(_ context.Context, id uuid.UUID) error {
+			cancelled = id
+			return nil
+		},
+	})
+
+	require.NoError(t, svc.StopEscalation(context.Background(), incidentID))
+	assert.True(t, deleted)
+	assert.Equal(t, workflowID, cancelled)
+}
+
+func TestEscalationService_ResumeDue(t *testing.T) {
+	running := &domain.Escalation{IncidentID: uuid.New(), WorkflowID: uuid.New(), Step: 1}
+	ended := &domain.Escalation{IncidentID: uuid.New(), WorkflowID: uuid.New()}
+	var deleted []uuid.UUID
+	repo := &mocks.MockEscalationRepository{
+		GetDueEscalationsFn: func(_ context.Context, _ time.Time) ([]*domain.Escalation, error) {
+			return []*domain.Escalation{running, ended}, nil
+		},
+		DeleteEscalationFn: func(_ context.Context, id uuid.UUID) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+	var resumed []string
+	svc := newTestEscalationService(repo, &mocks.MockAgentRepository{}, &mocks.MockAlertChannelRepository{})
+	svc.SetWorkflowEngine(&mocks.MockWorkflowEngine{
+		ResumeStepFn: func(_ context.Context, key string, _ json.RawMessage, _ error) error {
+			if key == workflows.EscalationCorrelationKey(ended.IncidentID, ended.Step) {
+				return errors.New("no awaiting step")
+			}
+			resumed = append(resumed, key)
+			return nil
+		},
+		StatusFn: func(_ context.Context, id uuid.UUID) (*domain.Workflow, error) {
+			return &domain.Workflow{ID: id, Status: domain.WorkflowStatusFailed}, nil
+		},
+	})
+
+	n, err := svc.ResumeDue(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{workflows.EscalationCorrelationKey(running.IncidentID, 1)}, resumed)
+	assert.Equal(t, []uuid.UUID{ended.IncidentID}, deleted)
+}
+
+type stubEscalator struct {
+	stopped []uuid.UUID
+}
+
+func (s *stubEscalator) StartEscalation(context.Context, *domain.Incident, *domain.Monitor) error {
+	return nil
+}
+
+func (s *stubEscalator) StopEscalation(_ context.Context, incidentID uuid.UUID) error {
+	s.stopped = append(s.stopped, incidentID)
+	return nil
+}
+
+func TestAcknowledgeIncident_StopsEscalation(t *testing.T) {
+	incidentID := uuid.New()
+	escalator := &stubEscalator{}
+	svc := newTestIncidentService(&mocks.MockIncidentRepository{}, &mocks.MockMonitorRepository{}, &mocks.MockNotifier{}, &mocks.MockTransactor{})
+	svc.SetEscalator(escalator)
+
+	require.NoError(t, svc.AcknowledgeIncident(context.Background(), incidentID, uuid.New()))
+	assert.Equal(t, []uuid.UUID{incidentID}, escalator.stopped)
+}
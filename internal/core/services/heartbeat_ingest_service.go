@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog-proto/protocol"
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+var (
+	// ErrIngestQueueFull is returned by Enqueue when the tenant's queue is
+	// full and the heartbeat was dropped.
+	ErrIngestQueueFull = errors.New("heartbeat ingest queue full")
+	// ErrIngestStopped is returned by Enqueue after Stop.
+	ErrIngestStopped = errors.New("heartbeat ingest stopped")
+)
+
+// Reasons a heartbeat is dropped, reported to the IngestObserver.
+const (
+	IngestDropQueueFull   = "queue_full"
+	IngestDropStoreFailed = "store_failed"
+)
+
+// IngestItem is a check result queued for batch processing. Payload is the
+// agent's report the heartbeat was built from; QueuedAt is set by Enqueue.
+type IngestItem struct {
+	AgentID   uuid.UUID
+	Heartbeat *domain.Heartbeat
+	Payload   *protocol.HeartbeatPayload
+	QueuedAt  time.Time
+}
+
+// IngestObserver receives heartbeat ingest metrics. Queue depth is read
+// from QueueDepth instead.
+type IngestObserver interface {
+	AddDroppedHeartbeats(reason string, n int)
+	ObserveHeartbeatBatch(size int)
+}
+
+// HeartbeatIngestConfig sizes the ingest pipeline. QueueSize bounds each
+// tenant's queue; a batch is flushed once it reaches BatchSize heartbeats or
+// FlushInterval has passed.
+type HeartbeatIngestConfig struct {
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// HeartbeatIngestService buffers agent heartbeats in a bounded queue per
+// tenant and stores them in batches, so connection goroutines never wait on
+// the database. Each flush stores the batch with one COPY, evaluates the
+// latest heartbeat of every monitor in it, runs the result hook for every
+// heartbeat and updates each reporting agent's last-seen time once.
+type HeartbeatIngestService struct {
+	heartbeatRepo ports.HeartbeatRepository
+	agentRepo     ports.AgentRepository
+	monitorSvc    ports.MonitorService
+	transactor    ports.Transactor
+	tenantContext func(ctx context.Context, tenantID string) context.Context
+	cfg           HeartbeatIngestConfig
+	logger        *slog.Logger
+
+	resultHook func(ctx context.Context, item IngestItem) // optional
+	observer   IngestObserver                             // optional
+
+	mu      sync.Mutex
+	ctx     context.Context // set by Start
+	queues  map[string]chan IngestItem
+	stopped bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+	depth   atomic.Int64
+}
+
+// NewHeartbeatIngestService creates a new HeartbeatIngestService.
+// tenantContext returns a context scoped to the given tenant; batches are
+// stored and evaluated under it.
+func NewHeartbeatIngestService(
+	heartbeatRepo ports.HeartbeatRepository,
+	agentRepo ports.AgentRepository,
+	monitorSvc ports.MonitorService,
+	transactor ports.Transactor,
+	tenantContext func(ctx context.Context, tenantID string) context.Context,
+	cfg HeartbeatIngestConfig,
+	logger *slog.Logger,
+) *HeartbeatIngestService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &HeartbeatIngestService{
+		heartbeatRepo: heartbeatRepo,
+		agentRepo:     agentRepo,
+		monitorSvc:    monitorSvc,
+		transactor:    transactor,
+		tenantContext: tenantContext,
+		cfg:           cfg,
+		logger:        logger,
+		queues:        make(map[string]chan IngestItem),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// SetResultHook sets a function called for every heartbeat after its batch
+// is stored and evaluated.
+func (s *HeartbeatIngestService) SetResultHook(hook func(ctx context.Context, item IngestItem)) {
+	s.resultHook = hook
+}
+
+// SetObserver sets the optional receiver of ingest metrics.
+func (s *HeartbeatIngestService) SetObserver(o IngestObserver) {
+	s.observer = o
+}
+
+// Start runs a flush worker for every tenant queue until ctx is cancelled or
+// Stop is called. Queues created later get their worker on creation.
+func (s *HeartbeatIngestService) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx = ctx
+	for tenantID, queue := range s.queues {
+		s.startWorker(tenantID, queue)
+	}
+}
+
+// Stop flushes the heartbeats still queued and waits for the workers to exit.
+func (s *HeartbeatIngestService) Stop() {
+	s.markStopped()
+	s.wg.Wait()
+}
+
+// markStopped stops Enqueue from queueing and tells the workers to drain.
+// Enqueue sends under s.mu, so every heartbeat it queued is in its queue
+// before the workers see stopCh closed.
+func (s *HeartbeatIngestService) markStopped() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	close(s.stopCh)
+}
+
+// Enqueue queues a heartbeat for the tenant without blocking. It returns
+// ErrIngestQueueFull, dropping the heartbeat, when the tenant's queue is
+// full, and ErrIngestStopped, without queueing it, once Stop was called or
+// the context passed to Start was cancelled.
+func (s *HeartbeatIngestService) Enqueue(tenantID string, item IngestItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return ErrIngestStopped
+	}
+	queue, ok := s.queues[tenantID]
+	if !ok {
+		queue = make(chan IngestItem, s.cfg.QueueSize)
+		s.queues[tenantID] = queue
+		if s.ctx != nil {
+			s.startWorker(tenantID, queue)
+		}
+	}
+
+	item.QueuedAt = time.Now()
+	// Counted before the send so a worker never takes the depth below zero.
+	s.depth.Add(1)
+	select {
+	case queue <- item:
+		return nil
+	default:
+		s.depth.Add(-1)
+		if s.observer != nil {
+			s.observer.AddDroppedHeartbeats(IngestDropQueueFull, 1)
+		}
+		return ErrIngestQueueFull
+	}
+}
+
+// QueueDepth returns the number of heartbeats queued across all tenants.
+func (s *HeartbeatIngestService) QueueDepth() int {
+	return int(s.depth.Load())
+}
+
+// startWorker must be called with s.mu held.
+func (s *HeartbeatIngestService) startWorker(tenantID string, queue chan IngestItem) {
+	s.wg.Add(1)
+	go s.run(s.ctx, tenantID, queue)
+}
+
+func (s *HeartbeatIngestService) run(ctx context.Context, tenantID string, queue chan IngestItem) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]IngestItem, 0, s.cfg.BatchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) > 0 {
+			s.flush(ctx, tenantID, batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case item := <-queue:
+			s.depth.Add(-1)
+			batch = append(batch, item)
+			if len(batch) >= s.cfg.BatchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			s.markStopped()
+			s.drain(context.WithoutCancel(ctx), tenantID, queue, batch)
+			return
+		case <-s.stopCh:
+			s.drain(context.WithoutCancel(ctx), tenantID, queue, batch)
+			return
+		}
+	}
+}
+
+// drain flushes the current batch and everything still queued.
+func (s *HeartbeatIngestService) drain(ctx context.Context, tenantID string, queue chan IngestItem, batch []IngestItem) {
+	for {
+		select {
+		case item := <-queue:
+			s.depth.Add(-1)
+			batch = append(batch, item)
+			if len(batch) >= s.cfg.BatchSize {
+				s.flush(ctx, tenantID, batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				s.flush(ctx, tenantID, batch)
+			}
+			return
+		}
+	}
+}
+
+// flush stores a tenant's batch and processes its heartbeats.
+func (s *HeartbeatIngestService) flush(ctx context.Context, tenantID string, batch []IngestItem) {
+	tCtx := s.tenantContext(ctx, tenantID)
+	stored := s.store(tCtx, tenantID, batch)
+
+	// Evaluation reads the monitor's recent heartbeats, which now include the
+	// whole batch, so only the latest heartbeat of each monitor is evaluated.
+	latest := make(map[uuid.UUID]int, len(stored))
+	for i, item := range stored {
+		latest[item.Heartbeat.MonitorID] = i
+	}
+	for i, item := range stored {
+		if latest[item.Heartbeat.MonitorID] == i {
+			if err := s.monitorSvc.EvaluateHeartbeat(tCtx, item.Heartbeat); err != nil {
+				s.logger.Error("failed to process heartbeat",
+					slog.String("monitor_id", item.Heartbeat.MonitorID.String()),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+		if s.resultHook != nil {
+			s.resultHook(tCtx, item)
+		}
+	}
+
+	now := time.Now()
+	seen := make(map[uuid.UUID]bool)
+	for _, item := range batch {
+		if seen[item.AgentID] {
+			continue
+		}
+		seen[item.AgentID] = true
+		if err := s.agentRepo.UpdateLastSeen(tCtx, item.AgentID, now); err != nil {
+			s.logger.Warn("failed to update agent last seen",
+				slog.String("agent_id", item.AgentID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	if s.observer != nil {
+		s.observer.ObserveHeartbeatBatch(len(batch))
+	}
+}
+
+// store writes the batch with one COPY and returns the stored items. A
+// single bad row, such as a heartbeat for a monitor deleted while it was
+// queued, fails the whole COPY, so the batch is then retried row by row.
+func (s *HeartbeatIngestService) store(ctx context.Context, tenantID string, batch []IngestItem) []IngestItem {
+	heartbeats := make([]*domain.Heartbeat, len(batch))
+	for i, item := range batch {
+		heartbeats[i] = item.Heartbeat
+	}
+	err := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+		return s.heartbeatRepo.CreateBatch(txCtx, heartbeats)
+	})
+	if err == nil {
+		return batch
+	}
+	s.logger.Warn("heartbeat batch insert failed, retrying row by row",
+		slog.String("tenant_id", tenantID),
+		slog.Int("size", len(batch)),
+		slog.String("error", err.Error()),
+	)
+
+	stored := make([]IngestItem, 0, len(batch))
+	for _, item := range batch {
+		err := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+			return s.heartbeatRepo.Create(txCtx, item.Heartbeat)
+		})
+		if err != nil {
+			s.logger.Error("failed to store heartbeat",
+				slog.String("monitor_id", item.Heartbeat.MonitorID.String()),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		stored = append(stored, item)
+	}
+	if dropped := len(batch) - len(stored); dropped > 0 && s.observer != nil {
+		s.observer.AddDroppedHeartbeats(IngestDropStoreFailed, dropped)
+	}
+	return stored
+}
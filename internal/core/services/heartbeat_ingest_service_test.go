@@ -0,0 +1,246 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+type tenantKey struct{}
+
+type ingestObserver struct {
+	mu      sync.Mutex
+	dropped map[string]int
+	batches []int
+}
+
+func (o *ingestObserver) AddDroppedHeartbeats(reason string, n int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.dropped == nil {
+		o.dropped = make(map[string]int)
+	}
+	o.dropped[reason] += n
+}
+
+func (o *ingestObserver) ObserveHeartbeatBatch(size int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.batches = append(o.batches, size)
+}
+
+func newIngestService(heartbeatRepo *mocks.MockHeartbeatRepository, agentRepo *mocks.MockAgentRepository, monitorSvc *mocks.MockMonitorService, cfg services.HeartbeatIngestConfig) *services.HeartbeatIngestService {
+	withTenant := func(ctx context.Context, tenantID string) context.Context {
+		return context.WithValue(ctx, tenantKey{}, tenantID)
+	}
+	return services.NewHeartbeatIngestService(heartbeatRepo, agentRepo, monitorSvc, &mocks.MockTransactor{}, withTenant, cfg, slog.Default())
+}
+
+func ingestItem(agentID, monitorID uuid.UUID, success bool) services.IngestItem {
+	hb := domain.NewFailureHeartbeat(monitorID, agentID, domain.HeartbeatStatusDown, "timeout")
+	if success {
+		hb = domain.NewSuccessHeartbeat(monitorID, agentID, 10)
+	}
+	return services.IngestItem{AgentID: agentID, Heartbeat: hb}
+}
+
+func TestHeartbeatIngestService_FlushesBatch(t *testing.T) {
+	agentID, api, web := uuid.New(), uuid.New(), uuid.New()
+
+	var stored []*domain.Heartbeat
+	var tenant any
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateBatchFn: func(ctx context.Context, hbs []*domain.Heartbeat) error {
+			stored = append(stored, hbs...)
+			tenant = ctx.Value(tenantKey{})
+			return nil
+		},
+	}
+	var lastSeen int
+	agentRepo := &mocks.MockAgentRepository{
+		UpdateLastSeenFn: func(_ context.Context, id uuid.UUID, _ time.Time) error {
+			assert.Equal(t, agentID, id)
+			lastSeen++
+			return nil
+		},
+	}
+	var evaluated []*domain.Heartbeat
+	monitorSvc := &mocks.MockMonitorService{
+		EvaluateHeartbeatFn: func(_ context.Context, hb *domain.Heartbeat) error {
+			evaluated = append(evaluated, hb)
+			return nil
+		},
+	}
+
+	svc := newIngestService(heartbeatRepo, agentRepo, monitorSvc, services.HeartbeatIngestConfig{QueueSize: 10, BatchSize: 3, FlushInterval: time.Hour})
+	var hooked int
+	svc.SetResultHook(func(_ context.Context, item services.IngestItem) {
+		assert.False(t, item.QueuedAt.IsZero(), "queue time is kept for the processing latency")
+		hooked++
+	})
+
+	items := []services.IngestItem{
+		ingestItem(agentID, api, false),
+		ingestItem(agentID, web, true),
+		ingestItem(agentID, api, true),
+	}
+	for _, item := range items {
+		require.NoError(t, svc.Enqueue("acme", item))
+	}
+	svc.Start(context.Background())
+	svc.Stop()
+
+	assert.Len(t, stored, 3, "stored with one COPY")
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, []*domain.Heartbeat{items[1].Heartbeat, items[2].Heartbeat}, evaluated, "only the latest heartbeat of each monitor is evaluated")
+	assert.Equal(t, 3, hooked)
+	assert.Equal(t, 1, lastSeen, "last seen updated once per agent")
+	assert.Zero(t, svc.QueueDepth())
+}
+
+func TestHeartbeatIngestService_QueueFull(t *testing.T) {
+	observer := &ingestObserver{}
+	svc := newIngestService(&mocks.MockHeartbeatRepository{}, &mocks.MockAgentRepository{}, &mocks.MockMonitorService{},
+		services.HeartbeatIngestConfig{QueueSize: 2, BatchSize: 10, FlushInterval: time.Hour})
+	svc.SetObserver(observer)
+
+	agentID, monitorID := uuid.New(), uuid.New()
+	require.NoError(t, svc.Enqueue("default", ingestItem(agentID, monitorID, true)))
+	require.NoError(t, svc.Enqueue("default", ingestItem(agentID, monitorID, true)))
+	err := svc.Enqueue("default", ingestItem(agentID, monitorID, true))
+	assert.True(t, errors.Is(err, services.ErrIngestQueueFull))
+
+	require.NoError(t, svc.Enqueue("other", ingestItem(agentID, monitorID, true)), "queues are per tenant")
+	assert.Equal(t, 3, svc.QueueDepth())
+	assert.Equal(t, 1, observer.dropped[services.IngestDropQueueFull])
+
+	svc.Stop()
+	assert.True(t, errors.Is(svc.Enqueue("default", ingestItem(agentID, monitorID, true)), services.ErrIngestStopped))
+}
+
+func TestHeartbeatIngestService_FallsBackToRowInserts(t *testing.T) {
+	agentID, good, deleted := uuid.New(), uuid.New(), uuid.New()
+
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateBatchFn: func(context.Context, []*domain.Heartbeat) error {
+			return errors.New("violates foreign key constraint")
+		},
+		CreateFn: func(_ context.Context, hb *domain.Heartbeat) error {
+			if hb.MonitorID == deleted {
+				return errors.New("violates foreign key constraint")
+			}
+			return nil
+		},
+	}
+	var evaluated []uuid.UUID
+	monitorSvc := &mocks.MockMonitorService{
+		EvaluateHeartbeatFn: func(_ context.Context, hb *domain.Heartbeat) error {
+			evaluated = append(evaluated, hb.MonitorID)
+			return nil
+		},
+	}
+	observer := &ingestObserver{}
+
+	svc := newIngestService(heartbeatRepo, &mocks.MockAgentRepository{}, monitorSvc, services.HeartbeatIngestConfig{QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	svc.SetObserver(observer)
+	require.NoError(t, svc.Enqueue("default", ingestItem(agentID, good, true)))
+	require.NoError(t, svc.Enqueue("default", ingestItem(agentID, deleted, false)))
+	svc.Start(context.Background())
+	svc.Stop()
+
+	assert.Equal(t, []uuid.UUID{good}, evaluated, "unstored heartbeats are not evaluated")
+	assert.Equal(t, 1, observer.dropped[services.IngestDropStoreFailed])
+	assert.Equal(t, []int{2}, observer.batches)
+}
+
+func TestHeartbeatIngestService_FlushInterval(t *testing.T) {
+	flushed := make(chan int, 1)
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateBatchFn: func(_ context.Context, hbs []*domain.Heartbeat) error {
+			flushed <- len(hbs)
+			return nil
+		},
+	}
+	svc := newIngestService(heartbeatRepo, &mocks.MockAgentRepository{}, &mocks.MockMonitorService{},
+		services.HeartbeatIngestConfig{QueueSize: 10, BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc.Start(ctx)
+	defer svc.Stop()
+
+	require.NoError(t, svc.Enqueue("default", ingestItem(uuid.New(), uuid.New(), true)))
+	select {
+	case n := <-flushed:
+		assert.Equal(t, 1, n, "partial batch flushed on the interval")
+	case <-time.After(2 * time.Second):
+		t.Fatal("batch was not flushed")
+	}
+}
+
+func TestHeartbeatIngestService_StopKeepsQueuedHeartbeats(t *testing.T) {
+	var mu sync.Mutex
+	stored := 0
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateBatchFn: func(_ context.Context, hbs []*domain.Heartbeat) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stored += len(hbs)
+			return nil
+		},
+	}
+	svc := newIngestService(heartbeatRepo, &mocks.MockAgentRepository{}, &mocks.MockMonitorService{},
+		services.HeartbeatIngestConfig{QueueSize: 1 << 16, BatchSize: 10, FlushInterval: time.Hour})
+	svc.Start(context.Background())
+
+	// Heartbeats keep arriving while the service stops: every one Enqueue
+	// accepts must be stored, and the rest refused.
+	var queued atomic.Int64
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				err := svc.Enqueue("default", ingestItem(uuid.New(), uuid.New(), true))
+				if errors.Is(err, services.ErrIngestStopped) {
+					return
+				}
+				assert.NoError(t, err)
+				queued.Add(1)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	svc.Stop()
+	wg.Wait()
+
+	assert.Equal(t, int(queued.Load()), stored)
+	assert.Zero(t, svc.QueueDepth())
+}
+
+func TestHeartbeatIngestService_CancelledRefusesHeartbeats(t *testing.T) {
+	svc := newIngestService(&mocks.MockHeartbeatRepository{}, &mocks.MockAgentRepository{}, &mocks.MockMonitorService{},
+		services.HeartbeatIngestConfig{QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	svc.Start(ctx)
+	require.NoError(t, svc.Enqueue("default", ingestItem(uuid.New(), uuid.New(), true)))
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		return errors.Is(svc.Enqueue("default", ingestItem(uuid.New(), uuid.New(), true)), services.ErrIngestStopped)
+	}, 2*time.Second, time.Millisecond, "no worker drains the queue once the context is cancelled")
+	svc.Stop()
+}
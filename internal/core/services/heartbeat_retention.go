@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Heartbeat retention settings in system_settings, seeded by migration 114.
+// Raw heartbeats and their rollups are pruned separately so long-range
+// charts and SLA reports outlive the raw rows.
+const (
+	HeartbeatRetentionSettingKey       = "heartbeat_retention_days"
+	HeartbeatRollupRetentionSettingKey = "heartbeat_rollup_retention_days"
+)
+
+// Fallbacks when a setting is missing, unparseable, or non-positive.
+const (
+	DefaultHeartbeatRetentionDays       = 90
+	DefaultHeartbeatRollupRetentionDays = 400
+)
+
+// MinHeartbeatRetentionDays is the shortest raw retention accepted. The
+// rollup refresh policies recompute up to 3 days back, so raw rows must
+// outlive that window or a refresh would empty already materialized buckets.
+const MinHeartbeatRetentionDays = 7
+
+// HeartbeatRetentionTickInterval is how often the worker rechecks the
+// settings and prunes. Hourly matches trace and log retention.
+const HeartbeatRetentionTickInterval = 1 * time.Hour
+
+// HeartbeatRetention prunes raw heartbeats and heartbeat rollups according
+// to system_settings, and backfills the rollups from the raw rows that are
+// kept. Raw heartbeats are deleted per tenant via
+// HeartbeatRepository.DeleteOlderThan; rollup chunks are dropped for all
+// tenants at once.
+type HeartbeatRetention struct {
+	heartbeats    ports.HeartbeatRepository
+	settings      ports.SystemSettingsRepository
+	tenants       func(ctx context.Context) []string
+	tenantContext func(ctx context.Context, tenantID string) context.Context
+	logger        *slog.Logger
+}
+
+// NewHeartbeatRetention builds a HeartbeatRetention worker. tenants lists
+// the tenants whose raw heartbeats are pruned and tenantContext scopes a
+// context to one of them.
+func NewHeartbeatRetention(
+	heartbeats ports.HeartbeatRepository,
+	settings ports.SystemSettingsRepository,
+	tenants func(ctx context.Context) []string,
+	tenantContext func(ctx context.Context, tenantID string) context.Context,
+	logger *slog.Logger,
+) *HeartbeatRetention {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &HeartbeatRetention{
+		heartbeats:    heartbeats,
+		settings:      settings,
+		tenants:       tenants,
+		tenantContext: tenantContext,
+		logger:        logger,
+	}
+}
+
+// Start launches the periodic loop and returns immediately. The loop
+// exits when ctx is cancelled.
+func (r *HeartbeatRetention) Start(ctx context.Context) {
+	go r.loop(ctx)
+}
+
+func (r *HeartbeatRetention) loop(ctx context.Context) {
+	if err := r.RunOnce(ctx, time.Now()); err != nil {
+		r.logger.Error("heartbeat retention initial run failed", slog.String("error", err.Error()))
+	}
+	t := time.NewTicker(HeartbeatRetentionTickInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := r.RunOnce(ctx, time.Now()); err != nil {
+				r.logger.Error("heartbeat retention run failed", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// RunOnce executes a single prune cycle using the supplied wall time as the
+// reference point. A failing tenant does not stop the others; the rollups
+// are still pruned and refreshed.
+//
+// The refresh starts at the raw cutoff, so it only recomputes buckets whose
+// raw rows are all kept, and ends an hour ago, leaving recent buckets to the
+// refresh policies. Buckets materialized before are skipped unless new
+// heartbeats arrived for them, so after the first backfill this is cheap.
+func (r *HeartbeatRetention) RunOnce(ctx context.Context, now time.Time) error {
+	rawDays := r.readDays(ctx, HeartbeatRetentionSettingKey, DefaultHeartbeatRetentionDays)
+	if rawDays < MinHeartbeatRetentionDays {
+		r.logger.Warn("heartbeat retention: raw days below minimum, clamping",
+			slog.Int("got", rawDays),
+			slog.Int("min", MinHeartbeatRetentionDays),
+		)
+		rawDays = MinHeartbeatRetentionDays
+	}
+	rollupDays := r.readDays(ctx, HeartbeatRollupRetentionSettingKey, DefaultHeartbeatRollupRetentionDays)
+	if rollupDays < rawDays {
+		// The refresh would otherwise rebuild pruned rollups from raw rows.
+		r.logger.Warn("heartbeat retention: rollup days below raw days, using raw days",
+			slog.Int("got", rollupDays),
+			slog.Int("raw_days", rawDays),
+		)
+		rollupDays = rawDays
+	}
+	rawCutoff := now.Add(-time.Duration(rawDays) * 24 * time.Hour)
+	rollupCutoff := now.Add(-time.Duration(rollupDays) * 24 * time.Hour)
+
+	var errs []error
+	var deleted int64
+	for _, tenantID := range r.tenants(ctx) {
+		n, err := r.heartbeats.DeleteOlderThan(r.tenantContext(ctx, tenantID), rawCutoff)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("delete heartbeats older than %s for tenant %s: %w", rawCutoff.Format(time.RFC3339), tenantID, err))
+			continue
+		}
+		deleted += n
+	}
+	if err := r.heartbeats.DeleteRollupsOlderThan(ctx, rollupCutoff); err != nil {
+		errs = append(errs, fmt.Errorf("delete heartbeat rollups older than %s: %w", rollupCutoff.Format(time.RFC3339), err))
+	}
+	if err := r.heartbeats.RefreshRollups(ctx, rawCutoff, now.Add(-time.Hour)); err != nil {
+		errs = append(errs, fmt.Errorf("refresh heartbeat rollups: %w", err))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	r.logger.Info("heartbeat retention run",
+		slog.Int("raw_days", rawDays),
+		slog.Int("rollup_days", rollupDays),
+		slog.Int64("deleted", deleted),
+	)
+	return nil
+}
+
+func (r *HeartbeatRetention) readDays(ctx context.Context, key string, def int) int {
+	raw, err := r.settings.Get(ctx, key)
+	if err != nil {
+		r.logger.Debug("heartbeat retention: using default days",
+			slog.String("key", key),
+			slog.String("reason", err.Error()),
+		)
+		return def
+	}
+
+	var days int
+	if err := json.Unmarshal(raw, &days); err != nil {
+		r.logger.Warn("heartbeat retention: setting is not a JSON number, using default",
+			slog.String("key", key),
+			slog.String("raw", string(raw)),
+		)
+		return def
+	}
+	if days <= 0 {
+		r.logger.Warn("heartbeat retention: non-positive days rejected, using default",
+			slog.String("key", key),
+			slog.Int("got", days),
+		)
+		return def
+	}
+	return days
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+type keyedSettings map[string][]byte
+
+func (s keyedSettings) Get(_ context.Context, key string) ([]byte, error) {
+	if v, ok := s[key]; ok {
+		return v, nil
+	}
+	return nil, errors.New("not found")
+}
+func (s keyedSettings) Set(context.Context, string, []byte, uuid.UUID) error { return nil }
+
+type heartbeatRetentionCalls struct {
+	rawCutoffs    map[any]time.Time
+	rollupCutoffs []time.Time
+	refreshes     [][2]time.Time
+}
+
+func newHeartbeatRetention(settings keyedSettings, rawErr error) (*services.HeartbeatRetention, *heartbeatRetentionCalls) {
+	calls := &heartbeatRetentionCalls{rawCutoffs: make(map[any]time.Time)}
+	repo := &mocks.MockHeartbeatRepository{
+		DeleteOlderThanFn: func(ctx context.Context, before time.Time) (int64, error) {
+			tenant := ctx.Value(tenantKey{})
+			if rawErr != nil && tenant == "acme" {
+				return 0, rawErr
+			}
+			calls.rawCutoffs[tenant] = before
+			return 1, nil
+		},
+		DeleteRollupsOlderThanFn: func(_ context.Context, before time.Time) error {
+			calls.rollupCutoffs = append(calls.rollupCutoffs, before)
+			return nil
+		},
+		RefreshRollupsFn: func(_ context.Context, from, to time.Time) error {
+			calls.refreshes = append(calls.refreshes, [2]time.Time{from, to})
+			return nil
+		},
+	}
+	tenants := func(context.Context) []string { return []string{"default", "acme"} }
+	withTenant := func(ctx context.Context, tenantID string) context.Context {
+		return context.WithValue(ctx, tenantKey{}, tenantID)
+	}
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	return services.NewHeartbeatRetention(repo, settings, tenants, withTenant, logger), calls
+}
+
+func TestHeartbeatRetention_UsesConfiguredDays(t *testing.T) {
+	r, calls := newHeartbeatRetention(keyedSettings{
+		services.HeartbeatRetentionSettingKey:       []byte(`30`),
+		services.HeartbeatRollupRetentionSettingKey: []byte(`365`),
+	}, nil)
+	now := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, r.RunOnce(context.Background(), now))
+
+	rawCutoff := now.Add(-30 * 24 * time.Hour)
+	assert.Equal(t, map[any]time.Time{"default": rawCutoff, "acme": rawCutoff}, calls.rawCutoffs)
+	assert.Equal(t, []time.Time{now.Add(-365 * 24 * time.Hour)}, calls.rollupCutoffs)
+	assert.Equal(t, [][2]time.Time{{rawCutoff, now.Add(-time.Hour)}}, calls.refreshes,
+		"rollups are refreshed only from kept raw rows")
+}
+
+func TestHeartbeatRetention_Defaults(t *testing.T) {
+	r, calls := newHeartbeatRetention(keyedSettings{
+		services.HeartbeatRetentionSettingKey: []byte(`"ninety"`),
+	}, nil)
+	now := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, r.RunOnce(context.Background(), now))
+
+	assert.Equal(t, now.Add(-90*24*time.Hour), calls.rawCutoffs["default"])
+	assert.Equal(t, []time.Time{now.Add(-400 * 24 * time.Hour)}, calls.rollupCutoffs)
+}
+
+func TestHeartbeatRetention_Clamps(t *testing.T) {
+	r, calls := newHeartbeatRetention(keyedSettings{
+		services.HeartbeatRetentionSettingKey:       []byte(`1`),
+		services.HeartbeatRollupRetentionSettingKey: []byte(`3`),
+	}, nil)
+	now := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, r.RunOnce(context.Background(), now))
+
+	minCutoff := now.Add(-services.MinHeartbeatRetentionDays * 24 * time.Hour)
+	assert.Equal(t, minCutoff, calls.rawCutoffs["default"], "raw rows outlive the refresh window")
+	assert.Equal(t, []time.Time{minCutoff}, calls.rollupCutoffs, "rollups are kept at least as long as raw rows")
+}
+
+func TestHeartbeatRetention_TenantErrorDoesNotStopOthers(t *testing.T) {
+	r, calls := newHeartbeatRetention(keyedSettings{}, errors.New("db down"))
+	err := r.RunOnce(context.Background(), time.Now())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tenant acme")
+	assert.Contains(t, calls.rawCutoffs, "default")
+	assert.Len(t, calls.rollupCutoffs, 1)
+	assert.Len(t, calls.refreshes, 1)
+}
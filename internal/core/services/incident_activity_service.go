@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// ErrIncidentActivityNotFound is returned when an activity entry does not
+// exist or belongs to another incident.
+var ErrIncidentActivityNotFound = errors.New("incident activity not found")
+
+// IncidentActivityService records what responders do on an incident: notes,
+// links, status changes and the postmortem written after resolution.
+type IncidentActivityService struct {
+	activityRepo ports.IncidentActivityRepository
+	incidentRepo ports.IncidentRepository
+}
+
+// NewIncidentActivityService creates a new IncidentActivityService.
+func NewIncidentActivityService(activityRepo ports.IncidentActivityRepository, incidentRepo ports.IncidentRepository) *IncidentActivityService {
+	return &IncidentActivityService{
+		activityRepo: activityRepo,
+		incidentRepo: incidentRepo,
+	}
+}
+
+// ListActivity returns an incident's activity log, oldest first.
+func (s *IncidentActivityService) ListActivity(ctx context.Context, incidentID uuid.UUID) ([]*domain.IncidentActivity, error) {
+	activity, err := s.activityRepo.GetByIncidentID(ctx, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentActivityService.ListActivity: %w", err)
+	}
+	return activity, nil
+}
+
+// AddNote stores a note or link built with domain.NewIncidentNote.
+func (s *IncidentActivityService) AddNote(ctx context.Context, note *domain.IncidentActivity) error {
+	if err := s.activityRepo.Create(ctx, note); err != nil {
+		return fmt.Errorf("incidentActivityService.AddNote: %w", err)
+	}
+	return nil
+}
+
+// EditNote replaces the body and URL of one of the incident's notes or links.
+func (s *IncidentActivityService) EditNote(ctx context.Context, incidentID, noteID uuid.UUID, body, link string) (*domain.IncidentActivity, error) {
+	note, err := s.getNote(ctx, incidentID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentActivityService.EditNote: %w", err)
+	}
+	if err := note.Edit(body, link); err != nil {
+		return nil, fmt.Errorf("incidentActivityService.EditNote: %w", err)
+	}
+	if err := s.activityRepo.Update(ctx, note); err != nil {
+		return nil, fmt.Errorf("incidentActivityService.EditNote: %w", err)
+	}
+	return note, nil
+}
+
+// DeleteNote removes one of the incident's notes or links.
+func (s *IncidentActivityService) DeleteNote(ctx context.Context, incidentID, noteID uuid.UUID) error {
+	note, err := s.getNote(ctx, incidentID, noteID)
+	if err != nil {
+		return fmt.Errorf("incidentActivityService.DeleteNote: %w", err)
+	}
+	if !note.Kind.IsEditable() {
+		return fmt.Errorf("incidentActivityService.DeleteNote: %w", domain.ErrIncidentActivityImmutable)
+	}
+	if err := s.activityRepo.Delete(ctx, note.ID); err != nil {
+		return fmt.Errorf("incidentActivityService.DeleteNote: %w", err)
+	}
+	return nil
+}
+
+// RecordStatusChange logs a responder moving an incident to status.
+func (s *IncidentActivityService) RecordStatusChange(ctx context.Context, incidentID, authorID uuid.UUID, status domain.IncidentStatus) error {
+	if err := s.activityRepo.Create(ctx, domain.NewIncidentStatusChange(incidentID, authorID, status)); err != nil {
+		return fmt.Errorf("incidentActivityService.RecordStatusChange: %w", err)
+	}
+	return nil
+}
+
+// GetPostmortem returns the incident's postmortem, or nil when none was written.
+func (s *IncidentActivityService) GetPostmortem(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentPostmortem, error) {
+	p, err := s.activityRepo.GetPostmortem(ctx, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentActivityService.GetPostmortem: %w", err)
+	}
+	return p, nil
+}
+
+// SavePostmortem validates and stores the postmortem of a resolved
+// incident, replacing any earlier version. The first author and creation
+// time are kept across edits.
+func (s *IncidentActivityService) SavePostmortem(ctx context.Context, p *domain.IncidentPostmortem, authorID uuid.UUID) error {
+	incident, err := s.incidentRepo.GetByID(ctx, p.IncidentID)
+	if err != nil {
+		return fmt.Errorf("incidentActivityService.SavePostmortem: get incident: %w", err)
+	}
+	if incident == nil {
+		return fmt.Errorf("incidentActivityService.SavePostmortem: incident not found")
+	}
+	if !incident.IsResolved() {
+		return fmt.Errorf("incidentActivityService.SavePostmortem: %w", domain.ErrPostmortemUnresolved)
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("incidentActivityService.SavePostmortem: %w", err)
+	}
+
+	existing, err := s.activityRepo.GetPostmortem(ctx, p.IncidentID)
+	if err != nil {
+		return fmt.Errorf("incidentActivityService.SavePostmortem: %w", err)
+	}
+	now := time.Now()
+	if existing != nil {
+		p.AuthorID = existing.AuthorID
+		p.AuthorEmail = existing.AuthorEmail
+		p.CreatedAt = existing.CreatedAt
+	} else {
+		p.AuthorID = &authorID
+		p.CreatedAt = now
+	}
+	p.UpdatedAt = now
+
+	if err := s.activityRepo.UpsertPostmortem(ctx, p); err != nil {
+		return fmt.Errorf("incidentActivityService.SavePostmortem: %w", err)
+	}
+	return nil
+}
+
+// getNote loads an activity entry, checking that it belongs to the incident.
+func (s *IncidentActivityService) getNote(ctx context.Context, incidentID, noteID uuid.UUID) (*domain.IncidentActivity, error) {
+	note, err := s.activityRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil || note.IncidentID != incidentID {
+		return nil, ErrIncidentActivityNotFound
+	}
+	return note, nil
+}
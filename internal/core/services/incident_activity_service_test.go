@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+func resolvedIncidentRepo(incidentID uuid.UUID, resolved bool) *mocks.MockIncidentRepository {
+	return &mocks.MockIncidentRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Incident, error) {
+			inc := domain.NewIncident(uuid.New())
+			inc.ID = id
+			if resolved {
+				_ = inc.Resolve()
+			}
+			return inc, nil
+		},
+	}
+}
+
+func TestIncidentActivityService_EditNote(t *testing.T) {
+	incidentID := uuid.New()
+	note, err := domain.NewIncidentNote(incidentID, uuid.New(), domain.IncidentActivityNote, "investigating", "")
+	require.NoError(t, err)
+	change := domain.NewIncidentStatusChange(incidentID, uuid.New(), domain.IncidentStatusAcknowledged)
+
+	var updated *domain.IncidentActivity
+	repo := &mocks.MockIncidentActivityRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.IncidentActivity, error) {
+			switch id {
+			case note.ID:
+				return note, nil
+			case change.ID:
+				return change, nil
+			}
+			return nil, nil
+		},
+		UpdateFn: func(_ context.Context, a *domain.IncidentActivity) error {
+			updated = a
+			return nil
+		},
+	}
+	svc := services.NewIncidentActivityService(repo, &mocks.MockIncidentRepository{})
+
+	edited, err := svc.EditNote(context.Background(), incidentID, note.ID, "found the bad deploy", "")
+	require.NoError(t, err)
+	assert.Equal(t, "found the bad deploy", edited.Body)
+	assert.Same(t, note, updated)
+
+	_, err = svc.EditNote(context.Background(), uuid.New(), note.ID, "other incident", "")
+	assert.ErrorIs(t, err, services.ErrIncidentActivityNotFound)
+
+	_, err = svc.EditNote(context.Background(), incidentID, change.ID, "rewritten", "")
+	assert.ErrorIs(t, err, domain.ErrIncidentActivityImmutable)
+
+	err = svc.DeleteNote(context.Background(), incidentID, change.ID)
+	assert.ErrorIs(t, err, domain.ErrIncidentActivityImmutable)
+}
+
+func TestIncidentActivityService_SavePostmortem(t *testing.T) {
+	incidentID, firstAuthor, editor := uuid.New(), uuid.New(), uuid.New()
+
+	var stored *domain.IncidentPostmortem
+	repo := &mocks.MockIncidentActivityRepository{
+		GetPostmortemFn: func(context.Context, uuid.UUID) (*domain.IncidentPostmortem, error) {
+			return stored, nil
+		},
+		UpsertPostmortemFn: func(_ context.Context, p *domain.IncidentPostmortem) error {
+			copied := *p
+			stored = &copied
+			return nil
+		},
+	}
+	svc := services.NewIncidentActivityService(repo, resolvedIncidentRepo(incidentID, true))
+
+	first := &domain.IncidentPostmortem{IncidentID: incidentID, RootCause: "expired cert", Impact: "API down 5m"}
+	require.NoError(t, svc.SavePostmortem(context.Background(), first, firstAuthor))
+	require.NotNil(t, stored)
+	assert.Equal(t, firstAuthor, *stored.AuthorID)
+	created := stored.CreatedAt
+
+	time.Sleep(time.Millisecond)
+	second := &domain.IncidentPostmortem{
+		IncidentID:  incidentID,
+		RootCause:   "expired cert",
+		Impact:      "API down 5m",
+		ActionItems: []domain.PostmortemActionItem{{Description: "monitor cert expiry", Owner: "platform"}},
+	}
+	require.NoError(t, svc.SavePostmortem(context.Background(), second, editor))
+	assert.Equal(t, firstAuthor, *stored.AuthorID, "the first author is kept")
+	assert.Equal(t, created, stored.CreatedAt)
+	assert.True(t, stored.UpdatedAt.After(created))
+	assert.Len(t, stored.ActionItems, 1)
+
+	invalid := &domain.IncidentPostmortem{IncidentID: incidentID, Impact: "API down 5m"}
+	assert.ErrorIs(t, svc.SavePostmortem(context.Background(), invalid, editor), domain.ErrInvalidPostmortem)
+}
+
+func TestIncidentActivityService_SavePostmortem_Unresolved(t *testing.T) {
+	incidentID := uuid.New()
+	repo := &mocks.MockIncidentActivityRepository{
+		UpsertPostmortemFn: func(context.Context, *domain.IncidentPostmortem) error {
+			return errors.New("must not be stored")
+		},
+	}
+	svc := services.NewIncidentActivityService(repo, resolvedIncidentRepo(incidentID, false))
+
+	p := &domain.IncidentPostmortem{IncidentID: incidentID, RootCause: "unknown", Impact: "ongoing"}
+	err := svc.SavePostmortem(context.Background(), p, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrPostmortemUnresolved)
+}
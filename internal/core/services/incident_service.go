@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,6 +23,21 @@ type IncidentOpenedNotifier interface {
 	OnIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor)
 }
 
+// IncidentEscalator is an optional hook that escalates incidents nobody
+// acknowledges. Implemented by *EscalationService.
+type IncidentEscalator interface {
+	StartEscalation(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error
+	StopEscalation(ctx context.Context, incidentID uuid.UUID) error
+}
+
+// IncidentGrouper is an optional hook that correlates incidents into alert
+// groups announced by a single notification. Implemented by
+// *AlertGroupService.
+type IncidentGrouper interface {
+	GroupIncident(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) bool
+	IncidentResolved(ctx context.Context, incident *domain.Incident) bool
+}
+
 // IncidentService implements ports.IncidentService for incident lifecycle management.
 type IncidentService struct {
 	incidentRepo       ports.IncidentRepository
@@ -29,10 +45,14 @@ type IncidentService struct {
 	agentRepo          ports.AgentRepository
 	heartbeatRepo      ports.HeartbeatRepository
 	alertChannelRepo   ports.AlertChannelRepository
-	notifier           ports.Notifier         // global notifier (env-based, server admin fallback)
-	notifierFactory    ports.NotifierFactory  // builds per-user notifiers from alert channels
-	workflowEngine     ports.WorkflowEngine   // optional: durable alert dispatch
-	subscriberNotifier IncidentOpenedNotifier // optional: status page subscriber emails
+	notifier           ports.Notifier             // global notifier (env-based, server admin fallback)
+	notifierFactory    ports.NotifierFactory      // builds per-user notifiers from alert channels
+	workflowEngine     ports.WorkflowEngine       // optional: durable alert dispatch
+	subscriberNotifier IncidentOpenedNotifier     // optional: status page subscriber emails
+	dependencyRepo     ports.DependencyRepository // optional: suppress incidents under upstream ones
+	escalator          IncidentEscalator          // optional: escalation policies
+	grouper            IncidentGrouper            // optional: alert grouping
+	alertRouter        ports.AlertRouter          // optional: alert routing rules
 	transactor         ports.Transactor
 	logger             *slog.Logger
 }
@@ -79,6 +99,31 @@ func (s *IncidentService) SetSubscriberNotifier(notifier IncidentOpenedNotifier)
 	s.subscriberNotifier = notifier
 }
 
+// SetDependencyRepo enables dependency-aware suppression: incidents on a
+// monitor whose upstream dependency already has an active incident are
+// attached to it as silent sub-incidents.
+func (s *IncidentService) SetDependencyRepo(repo ports.DependencyRepository) {
+	s.dependencyRepo = repo
+}
+
+// SetEscalator enables escalation policies: opened incidents start their
+// monitor's policy, which stops once the incident is acknowledged or resolved.
+func (s *IncidentService) SetEscalator(escalator IncidentEscalator) {
+	s.escalator = escalator
+}
+
+// SetGrouper enables alert grouping: opened incidents are held for their
+// alert group's notification instead of being announced one by one.
+func (s *IncidentService) SetGrouper(grouper IncidentGrouper) {
+	s.grouper = grouper
+}
+
+// SetAlertRouter enables alert routing rules: per-user notifications go to
+// the channels the router picks instead of every enabled channel.
+func (s *IncidentService) SetAlertRouter(router ports.AlertRouter) {
+	s.alertRouter = router
+}
+
 // GetIncident retrieves an incident by ID.
 func (s *IncidentService) GetIncident(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
 	incident, err := s.incidentRepo.GetByID(ctx, id)
@@ -129,6 +174,7 @@ func (s *IncidentService) AcknowledgeIncident(ctx context.Context, id uuid.UUID,
 	if err := s.incidentRepo.Acknowledge(ctx, id, userID); err != nil {
 		return fmt.Errorf("incidentService.AcknowledgeIncident: %w", err)
 	}
+	s.stopEscalation(ctx, id)
 	return nil
 }
 
@@ -142,6 +188,9 @@ func (s *IncidentService) ResolveIncident(ctx context.Context, id uuid.UUID) err
 	if incident == nil {
 		return fmt.Errorf("incidentService.ResolveIncident: incident not found")
 	}
+	if incident.IsDeclared() {
+		return s.resolveDeclared(ctx, incident)
+	}
 
 	// Get the monitor for notification context
 	monitor, err := s.monitorRepo.GetByID(ctx, incident.MonitorID)
@@ -173,14 +222,73 @@ func (s *IncidentService) ResolveIncident(ctx context.Context, id uuid.UUID) err
 		s.logger.Warn("failed to refresh incident for notification", "error", err)
 	}
 
-	// Send notifications (global + per-user, don't fail the operation)
-	if monitor != nil && incident != nil {
+	// Send notifications (global + per-user, don't fail the operation).
+	// Suppressed incidents never announced an opening, so stay quiet here too,
+	// as do grouped incidents: their group announces the recovery.
+	if monitor != nil && incident != nil && !incident.IsSuppressed() && !s.groupAnnouncesRecovery(ctx, incident) {
 		s.dispatchAlert(ctx, incident, monitor, false)
 	}
 
+	s.stopEscalation(ctx, id)
+	s.releaseSuppressed(ctx, id)
+
+	return nil
+}
+
+// resolveDeclared resolves a declared incident. It has no monitor status to
+// restore, so only the incident changes.
+func (s *IncidentService) resolveDeclared(ctx context.Context, incident *domain.Incident) error {
+	if err := s.incidentRepo.Resolve(ctx, incident.ID); err != nil {
+		return fmt.Errorf("incidentService.ResolveIncident: resolve incident: %w", err)
+	}
+
+	resolved, err := s.incidentRepo.GetByID(ctx, incident.ID)
+	if err != nil || resolved == nil {
+		s.logger.Warn("failed to refresh incident for notification", "error", err)
+		resolved = incident
+	}
+	s.dispatchAlert(ctx, resolved, resolved.DeclaredMonitor(), false)
+	s.stopEscalation(ctx, incident.ID)
+
+	return nil
+}
+
+// DeclareIncident opens an incident declared by an operator and notifies the
+// declaring user's alert channels. Declared incidents bypass alert grouping
+// and dependency suppression: an operator declared them on purpose.
+func (s *IncidentService) DeclareIncident(ctx context.Context, incident *domain.Incident) error {
+	if !incident.IsDeclared() {
+		return fmt.Errorf("incidentService.DeclareIncident: %w: not a declared incident", domain.ErrInvalidDeclaredIncident)
+	}
+	if err := s.incidentRepo.Create(ctx, incident); err != nil {
+		return fmt.Errorf("incidentService.DeclareIncident: %w", err)
+	}
+	s.dispatchAlert(ctx, incident, incident.DeclaredMonitor(), true)
+	return nil
+}
+
+// SetIncidentOwner hands an incident to another user, or clears its owner
+// when ownerID is nil.
+func (s *IncidentService) SetIncidentOwner(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error {
+	if err := s.incidentRepo.SetOwner(ctx, id, ownerID); err != nil {
+		return fmt.Errorf("incidentService.SetIncidentOwner: %w", err)
+	}
 	return nil
 }
 
+// GetDeclaredIncidentsAffecting returns declared incidents affecting any of
+// the monitors that are still active or started since the given time.
+func (s *IncidentService) GetDeclaredIncidentsAffecting(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error) {
+	if len(monitorIDs) == 0 {
+		return nil, nil
+	}
+	incidents, err := s.incidentRepo.GetDeclaredAffecting(ctx, monitorIDs, since)
+	if err != nil {
+		return nil, fmt.Errorf("incidentService.GetDeclaredIncidentsAffecting: %w", err)
+	}
+	return incidents, nil
+}
+
 // CreateIncidentIfNeeded creates a new incident for a monitor if there isn't already an open one.
 // Returns the existing incident if one is already open, or the newly created incident.
 func (s *IncidentService) CreateIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error) {
@@ -205,28 +313,173 @@ func (s *IncidentService) CreateIncidentIfNeeded(ctx context.Context, monitorID
 
 	// Create new incident in a transaction with monitor status update
 	incident := domain.NewIncident(monitorID)
+	incident.DeriveSeverity(monitor)
+	s.suppressUnderParent(ctx, incident)
+	if err := s.openIncident(ctx, incident); err != nil {
+		return nil, fmt.Errorf("incidentService.CreateIncidentIfNeeded: %w", err)
+	}
 
-	err = s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
-		// Create the incident
+	// Send notifications (global + per-user, don't fail the operation)
+	if !incident.IsSuppressed() {
+		s.announceOpened(ctx, incident, monitor)
+	}
+
+	return incident, nil
+}
+
+// CreateDegradedIncidentIfNeeded opens a degraded incident for a monitor that is
+// still responding but breaching its degraded rules. If any incident is already
+// active it is returned unchanged — an outage always outranks degradation.
+func (s *IncidentService) CreateDegradedIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error) {
+	existing, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentService.CreateDegradedIncidentIfNeeded: check existing: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	monitor, err := s.monitorRepo.GetByID(ctx, monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentService.CreateDegradedIncidentIfNeeded: get monitor: %w", err)
+	}
+	if monitor == nil {
+		return nil, fmt.Errorf("incidentService.CreateDegradedIncidentIfNeeded: monitor not found")
+	}
+
+	incident := domain.NewDegradedIncident(monitorID)
+	incident.DeriveSeverity(monitor)
+	s.suppressUnderParent(ctx, incident)
+	if err := s.openIncident(ctx, incident); err != nil {
+		return nil, fmt.Errorf("incidentService.CreateDegradedIncidentIfNeeded: %w", err)
+	}
+
+	if !incident.IsSuppressed() {
+		s.announceOpened(ctx, incident, monitor)
+	}
+
+	return incident, nil
+}
+
+// CreateAnomalyIncidentIfNeeded opens an anomaly incident for a monitor whose
+// latency deviates from its learned baseline. Any active incident is returned
+// unchanged. With notify unset the incident only marks the monitor degraded
+// and sends no alerts.
+func (s *IncidentService) CreateAnomalyIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID, notify bool) (*domain.Incident, error) {
+	existing, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentService.CreateAnomalyIncidentIfNeeded: check existing: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	monitor, err := s.monitorRepo.GetByID(ctx, monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("incidentService.CreateAnomalyIncidentIfNeeded: get monitor: %w", err)
+	}
+	if monitor == nil {
+		return nil, fmt.Errorf("incidentService.CreateAnomalyIncidentIfNeeded: monitor not found")
+	}
+
+	incident := domain.NewAnomalyIncident(monitorID)
+	incident.DeriveSeverity(monitor)
+	s.suppressUnderParent(ctx, incident)
+	if err := s.openIncident(ctx, incident); err != nil {
+		return nil, fmt.Errorf("incidentService.CreateAnomalyIncidentIfNeeded: %w", err)
+	}
+
+	if notify && !incident.IsSuppressed() {
+		s.announceOpened(ctx, incident, monitor)
+	}
+
+	return incident, nil
+}
+
+// openIncident persists a new incident and moves the monitor to the status its
+// kind implies, in a single transaction.
+func (s *IncidentService) openIncident(ctx context.Context, incident *domain.Incident) error {
+	return s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
 		if err := s.incidentRepo.Create(txCtx, incident); err != nil {
 			return fmt.Errorf("create incident: %w", err)
 		}
-
-		// Update monitor status to down
-		if err := s.monitorRepo.UpdateStatus(txCtx, monitorID, domain.MonitorStatusDown); err != nil {
+		if err := s.monitorRepo.UpdateStatus(txCtx, incident.MonitorID, incident.Kind.MonitorStatus()); err != nil {
 			return fmt.Errorf("update monitor status: %w", err)
 		}
-
 		return nil
 	})
+}
+
+// suppressUnderParent attaches a new incident to an active incident on one of
+// its monitor's upstream dependencies, if any. Lookup failures are logged and
+// leave the incident unsuppressed so alerts are never lost.
+func (s *IncidentService) suppressUnderParent(ctx context.Context, incident *domain.Incident) {
+	if parent := s.activeParentIncident(ctx, incident.MonitorID); parent != nil {
+		incident.SuppressUnder(parent.ID)
+		s.logger.Info("suppressing incident under upstream dependency",
+			"monitor_id", incident.MonitorID,
+			"parent_incident_id", parent.ID,
+			"parent_monitor_id", parent.MonitorID,
+		)
+	}
+}
+
+// activeParentIncident returns the first active incident on any monitor the
+// given monitor depends on.
+func (s *IncidentService) activeParentIncident(ctx context.Context, monitorID uuid.UUID) *domain.Incident {
+	if s.dependencyRepo == nil {
+		return nil
+	}
+	parentIDs, err := s.dependencyRepo.GetParentIDs(ctx, monitorID)
 	if err != nil {
-		return nil, fmt.Errorf("incidentService.CreateIncidentIfNeeded: %w", err)
+		s.logger.Error("failed to get monitor dependencies", "monitor_id", monitorID, "error", err)
+		return nil
+	}
+	for _, parentID := range parentIDs {
+		parent, err := s.incidentRepo.GetActiveByMonitorID(ctx, parentID)
+		if err != nil {
+			s.logger.Error("failed to get upstream incident", "monitor_id", parentID, "error", err)
+			continue
+		}
+		if parent != nil {
+			return parent
+		}
+	}
+	return nil
+}
+
+// releaseSuppressed handles sub-incidents left active after their parent
+// incident resolved. Each moves under another active upstream incident if
+// one exists; otherwise it stands on its own and is announced, since the
+// downstream failure is no longer explained by its dependency.
+func (s *IncidentService) releaseSuppressed(ctx context.Context, parentIncidentID uuid.UUID) {
+	children, err := s.incidentRepo.GetActiveByParentID(ctx, parentIncidentID)
+	if err != nil {
+		s.logger.Error("failed to get suppressed incidents", "parent_incident_id", parentIncidentID, "error", err)
+		return
 	}
 
-	// Send notifications (global + per-user, don't fail the operation)
-	s.dispatchAlert(ctx, incident, monitor, true)
+	for _, child := range children {
+		var newParentID *uuid.UUID
+		if parent := s.activeParentIncident(ctx, child.MonitorID); parent != nil {
+			newParentID = &parent.ID
+		}
+		if err := s.incidentRepo.SetParent(ctx, child.ID, newParentID); err != nil {
+			s.logger.Error("failed to release suppressed incident", "incident_id", child.ID, "error", err)
+			continue
+		}
+		child.ParentIncidentID = newParentID
+		if child.IsSuppressed() {
+			continue
+		}
 
-	return incident, nil
+		monitor, err := s.monitorRepo.GetByID(ctx, child.MonitorID)
+		if err != nil || monitor == nil {
+			s.logger.Error("failed to get monitor for released incident", "monitor_id", child.MonitorID, "error", err)
+			continue
+		}
+		s.announceOpened(ctx, child, monitor)
+	}
 }
 
 // ResolveIncidentSilently resolves an incident without sending per-monitor notifications.
@@ -254,24 +507,59 @@ func (s *IncidentService) ResolveIncidentSilently(ctx context.Context, id uuid.U
 		return fmt.Errorf("incidentService.ResolveIncidentSilently: %w", err)
 	}
 
+	s.groupAnnouncesRecovery(ctx, incident)
+	s.stopEscalation(ctx, id)
+	s.releaseSuppressed(ctx, id)
+
+	return nil
+}
+
+// SetIncidentFlapping moves an active incident into or out of the flapping
+// state. No notifications are sent for either transition: flapping exists to
+// hold back the open/resolve alerts an oscillating monitor would otherwise emit.
+func (s *IncidentService) SetIncidentFlapping(ctx context.Context, id uuid.UUID, flapping bool) error {
+	if err := s.incidentRepo.SetFlapping(ctx, id, flapping); err != nil {
+		return fmt.Errorf("incidentService.SetIncidentFlapping: %w", err)
+	}
+	return nil
+}
+
+// SetIncidentSeverity overrides the severity derived for an incident.
+func (s *IncidentService) SetIncidentSeverity(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error {
+	if !severity.IsValid() {
+		return fmt.Errorf("incidentService.SetIncidentSeverity: %w", domain.ErrInvalidIncidentSeverity)
+	}
+	if err := s.incidentRepo.SetSeverity(ctx, id, severity); err != nil {
+		return fmt.Errorf("incidentService.SetIncidentSeverity: %w", err)
+	}
 	return nil
 }
 
 // CreateIncidentSilently creates an incident without sending notifications.
 // Used when an agent disconnects — individual monitor alerts are suppressed
-// in favor of a single agent-level notification.
+// in favor of a single agent-level notification. An active outage incident
+// is returned unchanged; an active degraded incident is silently superseded
+// by the outage incident.
 func (s *IncidentService) CreateIncidentSilently(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error) {
 	existing, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitorID)
 	if err != nil {
 		return nil, fmt.Errorf("incidentService.CreateIncidentSilently: check existing: %w", err)
 	}
-	if existing != nil {
+	if existing != nil && !existing.IsDegraded() {
 		return existing, nil
 	}
 
 	incident := domain.NewIncident(monitorID)
+	if monitor, err := s.monitorRepo.GetByID(ctx, monitorID); err == nil && monitor != nil {
+		incident.DeriveSeverity(monitor)
+	}
 
 	err = s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+		if existing != nil {
+			if err := s.incidentRepo.Resolve(txCtx, existing.ID); err != nil {
+				return fmt.Errorf("resolve degraded incident: %w", err)
+			}
+		}
 		if err := s.incidentRepo.Create(txCtx, incident); err != nil {
 			return fmt.Errorf("create incident: %w", err)
 		}
@@ -284,6 +572,12 @@ func (s *IncidentService) CreateIncidentSilently(ctx context.Context, monitorID
 		return nil, fmt.Errorf("incidentService.CreateIncidentSilently: %w", err)
 	}
 
+	if existing != nil {
+		s.groupAnnouncesRecovery(ctx, existing)
+		s.stopEscalation(ctx, existing.ID)
+		s.releaseSuppressed(ctx, existing.ID)
+	}
+
 	return incident, nil
 }
 
@@ -305,7 +599,7 @@ func (s *IncidentService) NotifyAgentOffline(ctx context.Context, agentID uuid.U
 	}
 
 	// Per-user channels
-	channels, err := s.alertChannelRepo.GetEnabledByUserID(ctx, agent.UserID)
+	channels, err := s.routedChannels(ctx, domain.NewAgentAlertEvent(agent.UserID, agent.ID, false))
 	if err != nil {
 		s.logger.Error("failed to get alert channels for agent offline", "user_id", agent.UserID, "error", err)
 		return
@@ -346,7 +640,7 @@ func (s *IncidentService) NotifyAgentOnline(ctx context.Context, agentID uuid.UU
 	}
 
 	// Per-user channels
-	channels, err := s.alertChannelRepo.GetEnabledByUserID(ctx, agent.UserID)
+	channels, err := s.routedChannels(ctx, domain.NewAgentAlertEvent(agent.UserID, agent.ID, true))
 	if err != nil {
 		s.logger.Error("failed to get alert channels for agent online", "user_id", agent.UserID, "error", err)
 		return
@@ -397,6 +691,28 @@ func (s *IncidentService) NotifyAgentMaintenance(ctx context.Context, agentID uu
 	}
 }
 
+// AnnounceIncident sends an incident's notifications and, for an opened
+// incident, starts its escalation. Alert groups use it to announce incidents
+// they held back.
+func (s *IncidentService) AnnounceIncident(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor, opened bool) {
+	s.dispatchAlert(ctx, incident, monitor, opened)
+}
+
+// announceOpened hands a newly opened incident to its alert group, or
+// dispatches its alerts right away when grouping is off or fails.
+func (s *IncidentService) announceOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) {
+	if s.grouper != nil && s.grouper.GroupIncident(ctx, incident, monitor) {
+		return
+	}
+	s.dispatchAlert(ctx, incident, monitor, true)
+}
+
+// groupAnnouncesRecovery tells an incident's alert group it resolved.
+// Returns true when the group announces the recovery in the incident's place.
+func (s *IncidentService) groupAnnouncesRecovery(ctx context.Context, incident *domain.Incident) bool {
+	return s.grouper != nil && s.grouper.IncidentResolved(ctx, incident)
+}
+
 // dispatchAlert routes notifications through the workflow engine if available,
 // falling back to direct dispatch for backward compatibility.
 func (s *IncidentService) dispatchAlert(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor, opened bool) {
@@ -406,6 +722,14 @@ func (s *IncidentService) dispatchAlert(ctx context.Context, incident *domain.In
 	if opened && s.subscriberNotifier != nil {
 		go s.subscriberNotifier.OnIncidentOpened(context.Background(), incident, monitor)
 	}
+	if opened && s.escalator != nil {
+		if err := s.escalator.StartEscalation(ctx, incident, monitor); err != nil {
+			s.logger.Error("failed to start escalation",
+				slog.String("incident_id", incident.ID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
 	if s.workflowEngine != nil {
 		s.submitAlertWorkflow(ctx, incident, monitor, opened)
 		return
@@ -413,6 +737,20 @@ func (s *IncidentService) dispatchAlert(ctx context.Context, incident *domain.In
 	s.notifyAll(ctx, incident, monitor, opened)
 }
 
+// stopEscalation stops the incident's escalation, if any. Failures are
+// logged: the escalation's steps skip incidents that are no longer open.
+func (s *IncidentService) stopEscalation(ctx context.Context, incidentID uuid.UUID) {
+	if s.escalator == nil {
+		return
+	}
+	if err := s.escalator.StopEscalation(ctx, incidentID); err != nil {
+		s.logger.Error("failed to stop escalation",
+			slog.String("incident_id", incidentID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
 // submitAlertWorkflow creates a durable alert dispatch workflow.
 func (s *IncidentService) submitAlertWorkflow(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor, opened bool) {
 	input := workflows.AlertDispatchInput{
@@ -421,6 +759,9 @@ func (s *IncidentService) submitAlertWorkflow(ctx context.Context, incident *dom
 		AgentID:    monitor.AgentID,
 		Opened:     opened,
 	}
+	if incident.AlertContext != nil {
+		input.Group = incident.AlertContext.Group
+	}
 
 	inputJSON, err := json.Marshal(input)
 	if err != nil {
@@ -430,7 +771,7 @@ func (s *IncidentService) submitAlertWorkflow(ctx context.Context, incident *dom
 		return
 	}
 
-	channelTypes := []string{"global", "discord", "slack", "email", "telegram", "pagerduty", "webhook"}
+	channelTypes := []string{"global", "discord", "slack", "email", "telegram", "pagerduty", "webhook", "teams", "mattermost", "opsgenie", "splunk_oncall"}
 	def := workflows.AlertDispatchDef(channelTypes)
 
 	wfID, err := s.workflowEngine.Submit(ctx, def, inputJSON)
@@ -455,6 +796,15 @@ func (s *IncidentService) populateAlertContext(ctx context.Context, incident *do
 	actx := &domain.AlertContext{
 		Interval:  monitor.IntervalSeconds,
 		Threshold: monitor.FailureThreshold,
+		Severity:  incident.Severity,
+	}
+	if incident.AlertContext != nil {
+		actx.Group = incident.AlertContext.Group
+	}
+	if incident.IsDeclared() {
+		// No checks or agent behind a declared incident.
+		incident.AlertContext = actx
+		return
 	}
 
 	// Fetch latest heartbeat for error message and latency
@@ -505,20 +855,17 @@ func (s *IncidentService) notifyAll(ctx context.Context, incident *domain.Incide
 		)
 	}
 
-	// 2. Per-user notifications: monitor → agent → user → channels
-	agent, err := s.agentRepo.GetByID(ctx, monitor.AgentID)
-	if err != nil || agent == nil {
-		s.logger.Error("failed to get agent for per-user notifications",
-			"agent_id", monitor.AgentID,
-			"error", err,
-		)
+	// 2. Per-user notifications: monitor → agent → user → channels, or the
+	// declaring user's channels for a declared incident
+	userID, ok := s.notifiedUserID(ctx, incident, monitor)
+	if !ok {
 		return
 	}
 
-	channels, err := s.alertChannelRepo.GetEnabledByUserID(ctx, agent.UserID)
+	channels, err := s.routedChannels(ctx, domain.NewIncidentAlertEvent(userID, incident, monitor, opened))
 	if err != nil {
 		s.logger.Error("failed to get alert channels",
-			"user_id", agent.UserID,
+			"user_id", userID,
 			"error", err,
 		)
 		return
@@ -526,7 +873,7 @@ func (s *IncidentService) notifyAll(ctx context.Context, incident *domain.Incide
 
 	if len(channels) == 0 {
 		s.logger.Warn("no enabled alert channels found for user",
-			"user_id", agent.UserID,
+			"user_id", userID,
 			"incident_id", incident.ID,
 			"alert_type", alertType,
 		)
@@ -568,3 +915,43 @@ func (s *IncidentService) notifyAll(ctx context.Context, incident *domain.Incide
 		}
 	}
 }
+
+// routedChannels returns the per-user channels an event goes to: those the
+// alert router picks, or every enabled channel of the user without one.
+func (s *IncidentService) routedChannels(ctx context.Context, event *domain.AlertEvent) ([]*domain.AlertChannel, error) {
+	if s.alertRouter == nil {
+		return s.alertChannelRepo.GetEnabledByUserID(ctx, event.UserID)
+	}
+	routing, err := s.alertRouter.RouteChannels(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	if routing.Suppressed {
+		s.logger.Info("alert suppressed by routing rule",
+			"user_id", event.UserID,
+			"event", event.Kind,
+			"rule", routing.Hits[len(routing.Hits)-1].Name,
+		)
+	}
+	return routing.Channels, nil
+}
+
+// notifiedUserID returns the user whose alert channels receive the
+// incident's notifications.
+func (s *IncidentService) notifiedUserID(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) (uuid.UUID, bool) {
+	if incident.IsDeclared() {
+		if incident.UserID == nil {
+			return uuid.Nil, false
+		}
+		return *incident.UserID, true
+	}
+	agent, err := s.agentRepo.GetByID(ctx, monitor.AgentID)
+	if err != nil || agent == nil {
+		s.logger.Error("failed to get agent for per-user notifications",
+			"agent_id", monitor.AgentID,
+			"error", err,
+		)
+		return uuid.Nil, false
+	}
+	return agent.UserID, true
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
 	"github.com/sylvester-francis/watchdog/internal/core/services"
 	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
 )
@@ -363,6 +364,95 @@ func TestCreateIncidentIfNeeded_New(t *testing.T) {
 	assert.True(t, notified)
 }
 
+func TestCreateIncidentIfNeeded_ParentDown_SuppressesWithoutNotifying(t *testing.T) {
+	monitorID := uuid.New()
+	parentMonitorID := uuid.New()
+	parentIncident := domain.NewIncident(parentMonitorID)
+
+	var created *domain.Incident
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, id uuid.UUID) (*domain.Incident, error) {
+			if id == parentMonitorID {
+				return parentIncident, nil
+			}
+			return nil, nil
+		},
+		CreateFn: func(_ context.Context, incident *domain.Incident) error {
+			created = incident
+			return nil
+		},
+	}
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Monitor, error) {
+			return &domain.Monitor{ID: monitorID, Name: "Test"}, nil
+		},
+	}
+	notifier := &mocks.MockNotifier{
+		NotifyIncidentOpenedFn: func(_ context.Context, _ *domain.Incident, _ *domain.Monitor) error {
+			t.Fatal("suppressed incident should not notify")
+			return nil
+		},
+	}
+
+	svc := newTestIncidentService(incidentRepo, monitorRepo, notifier, &mocks.MockTransactor{})
+	svc.SetDependencyRepo(&mocks.MockDependencyRepository{
+		GetParentIDsFn: func(_ context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+			assert.Equal(t, monitorID, id)
+			return []uuid.UUID{parentMonitorID}, nil
+		},
+	})
+
+	incident, err := svc.CreateIncidentIfNeeded(context.Background(), monitorID)
+
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.True(t, incident.IsSuppressed())
+	assert.Equal(t, parentIncident.ID, *created.ParentIncidentID)
+}
+
+func TestResolveIncident_PromotesSuppressedChildren(t *testing.T) {
+	parentIncident := domain.NewIncident(uuid.New())
+	childMonitorID := uuid.New()
+	child := domain.NewIncident(childMonitorID)
+	child.SuppressUnder(parentIncident.ID)
+
+	detached := false
+	var notifiedMonitors []uuid.UUID
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return parentIncident, nil
+		},
+		GetActiveByParentIDFn: func(_ context.Context, id uuid.UUID) ([]*domain.Incident, error) {
+			assert.Equal(t, parentIncident.ID, id)
+			return []*domain.Incident{child}, nil
+		},
+		SetParentFn: func(_ context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+			assert.Equal(t, child.ID, id)
+			assert.Nil(t, parentID)
+			detached = true
+			return nil
+		},
+	}
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			return &domain.Monitor{ID: id, Name: "Test"}, nil
+		},
+	}
+	notifier := &mocks.MockNotifier{
+		NotifyIncidentOpenedFn: func(_ context.Context, _ *domain.Incident, m *domain.Monitor) error {
+			notifiedMonitors = append(notifiedMonitors, m.ID)
+			return nil
+		},
+	}
+
+	svc := newTestIncidentService(incidentRepo, monitorRepo, notifier, &mocks.MockTransactor{})
+	svc.SetDependencyRepo(&mocks.MockDependencyRepository{})
+
+	require.NoError(t, svc.ResolveIncident(context.Background(), parentIncident.ID))
+	assert.True(t, detached)
+	assert.Equal(t, []uuid.UUID{childMonitorID}, notifiedMonitors, "child still down is announced on its own")
+}
+
 func TestCreateIncidentIfNeeded_AlreadyOpen(t *testing.T) {
 	monitorID := uuid.New()
 	existingIncident := domain.NewIncident(monitorID)
@@ -535,3 +625,144 @@ func TestCreateIncidentIfNeeded_NotificationFails_StillSucceeds(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, incident)
 }
+
+// --- Severity ---
+
+func TestCreateIncidentIfNeeded_DerivesSeverityFromMonitor(t *testing.T) {
+	monitorID := uuid.New()
+	var stored *domain.Incident
+
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return nil, nil
+		},
+		CreateFn: func(_ context.Context, inc *domain.Incident) error {
+			stored = inc
+			return nil
+		},
+	}
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Monitor, error) {
+			return &domain.Monitor{ID: monitorID, Name: "Test", Severity: domain.IncidentSeverityMinor}, nil
+		},
+		UpdateStatusFn: func(_ context.Context, _ uuid.UUID, _ domain.MonitorStatus) error { return nil },
+	}
+
+	svc := newTestIncidentService(incidentRepo, monitorRepo, &mocks.MockNotifier{}, &mocks.MockTransactor{})
+
+	_, err := svc.CreateIncidentIfNeeded(context.Background(), monitorID)
+
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, domain.IncidentSeverityMinor, stored.Severity)
+}
+
+func TestSetIncidentSeverity(t *testing.T) {
+	incidentID := uuid.New()
+	var set domain.IncidentSeverity
+	incidentRepo := &mocks.MockIncidentRepository{
+		SetSeverityFn: func(_ context.Context, id uuid.UUID, severity domain.IncidentSeverity) error {
+			assert.Equal(t, incidentID, id)
+			set = severity
+			return nil
+		},
+	}
+	svc := newTestIncidentService(incidentRepo, &mocks.MockMonitorRepository{}, &mocks.MockNotifier{}, &mocks.MockTransactor{})
+
+	require.NoError(t, svc.SetIncidentSeverity(context.Background(), incidentID, domain.IncidentSeverityMajor))
+	assert.Equal(t, domain.IncidentSeverityMajor, set)
+
+	err := svc.SetIncidentSeverity(context.Background(), incidentID, "sev1")
+	assert.ErrorIs(t, err, domain.ErrInvalidIncidentSeverity)
+	assert.Equal(t, domain.IncidentSeverityMajor, set, "invalid severity never reaches the repository")
+}
+
+// --- Declared incidents ---
+
+func TestDeclareIncident_NotifiesDeclaringUser(t *testing.T) {
+	userID := uuid.New()
+	incident, err := domain.NewDeclaredIncident(userID, "Payment provider outage", "Card payments failing", domain.IncidentSeverityMajor, nil)
+	require.NoError(t, err)
+
+	created := false
+	incidentRepo := &mocks.MockIncidentRepository{
+		CreateFn: func(_ context.Context, inc *domain.Incident) error {
+			assert.Equal(t, uuid.Nil, inc.MonitorID)
+			created = true
+			return nil
+		},
+	}
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetEnabledByUserIDFn: func(_ context.Context, id uuid.UUID) ([]*domain.AlertChannel, error) {
+			assert.Equal(t, userID, id)
+			return []*domain.AlertChannel{{ID: uuid.New(), Type: domain.AlertChannelSlack}}, nil
+		},
+	}
+	var sent *domain.Monitor
+	factory := &mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(_ *domain.AlertChannel) (ports.Notifier, error) {
+			return &mocks.MockNotifier{
+				NotifyIncidentOpenedFn: func(_ context.Context, _ *domain.Incident, m *domain.Monitor) error {
+					sent = m
+					return nil
+				},
+			}, nil
+		},
+	}
+	agentRepo := &mocks.MockAgentRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Agent, error) {
+			t.Fatal("declared incidents have no agent")
+			return nil, nil
+		},
+	}
+	svc := services.NewIncidentService(incidentRepo, &mocks.MockMonitorRepository{}, agentRepo, &mocks.MockHeartbeatRepository{}, channelRepo, &mocks.MockNotifier{}, factory, &mocks.MockTransactor{}, slog.Default())
+
+	require.NoError(t, svc.DeclareIncident(context.Background(), incident))
+
+	assert.True(t, created)
+	require.NotNil(t, sent)
+	assert.Equal(t, "Payment provider outage", sent.Name)
+	assert.Equal(t, domain.IncidentSeverityMajor, incident.AlertContext.Severity)
+
+	err = svc.DeclareIncident(context.Background(), domain.NewIncident(uuid.New()))
+	assert.ErrorIs(t, err, domain.ErrInvalidDeclaredIncident)
+}
+
+func TestResolveIncident_Declared_SkipsMonitor(t *testing.T) {
+	incident, err := domain.NewDeclaredIncident(uuid.New(), "Security event", "", "", nil)
+	require.NoError(t, err)
+
+	resolved := false
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return incident, nil
+		},
+		ResolveFn: func(_ context.Context, _ uuid.UUID) error {
+			resolved = true
+			return nil
+		},
+	}
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Monitor, error) {
+			t.Fatal("declared incidents have no monitor")
+			return nil, nil
+		},
+		UpdateStatusFn: func(_ context.Context, _ uuid.UUID, _ domain.MonitorStatus) error {
+			t.Fatal("declared incidents have no monitor")
+			return nil
+		},
+	}
+	notified := false
+	notifier := &mocks.MockNotifier{
+		NotifyIncidentResolvedFn: func(_ context.Context, _ *domain.Incident, m *domain.Monitor) error {
+			assert.Equal(t, "Security event", m.Name)
+			notified = true
+			return nil
+		},
+	}
+	svc := newTestIncidentService(incidentRepo, monitorRepo, notifier, &mocks.MockTransactor{})
+
+	require.NoError(t, svc.ResolveIncident(context.Background(), incident.ID))
+	assert.True(t, resolved)
+	assert.True(t, notified)
+}
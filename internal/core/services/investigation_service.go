@@ -21,6 +21,9 @@ type InvestigationService struct {
 	agentRepo       ports.AgentRepository
 	heartbeatRepo   ports.HeartbeatRepository
 	certDetailsRepo ports.CertDetailsRepository
+	dependencyRepo  ports.DependencyRepository       // optional: dependency tree and root cause
+	anomalyRepo     ports.AnomalyRepository          // optional: latency anomaly timeline events
+	activityRepo    ports.IncidentActivityRepository // optional: responder activity and postmortem
 	logger          *slog.Logger
 }
 
@@ -43,6 +46,22 @@ func NewInvestigationService(
 	}
 }
 
+// SetDependencyRepo enables the dependency tree and root-cause view.
+func (s *InvestigationService) SetDependencyRepo(repo ports.DependencyRepository) {
+	s.dependencyRepo = repo
+}
+
+// SetAnomalyRepo adds latency anomaly detections to the timeline.
+func (s *InvestigationService) SetAnomalyRepo(repo ports.AnomalyRepository) {
+	s.anomalyRepo = repo
+}
+
+// SetActivityRepo merges responder notes, status changes and the
+// postmortem into the timeline.
+func (s *InvestigationService) SetActivityRepo(repo ports.IncidentActivityRepository) {
+	s.activityRepo = repo
+}
+
 // Investigate builds an IncidentInvestigation by aggregating data from existing repos.
 func (s *InvestigationService) Investigate(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentInvestigation, error) {
 	// 1. Get incident
@@ -170,6 +189,35 @@ func (s *InvestigationService) Investigate(ctx context.Context, incidentID uuid.
 
 	// 11. Build timeline
 	timeline := buildTimeline(incident, heartbeats)
+	if s.anomalyRepo != nil {
+		timeline = s.addAnomalyEvents(ctx, incident, timeline, windowStart, windowEnd)
+	}
+	var postmortem *domain.IncidentPostmortem
+	if s.activityRepo != nil {
+		timeline, postmortem = s.addActivityEvents(ctx, incident, timeline)
+	}
+
+	// 12. Per-location breakdown for multi-location monitors
+	var locations []domain.LocationStatus
+	if monitor.IsMultiLocation() {
+		asOf := windowEnd
+		if now := time.Now(); asOf.After(now) {
+			asOf = now
+		}
+		locations = monitor.LocationBreakdown(heartbeats, asOf)
+		for i := range locations {
+			if a, err := s.agentRepo.GetByID(ctx, locations[i].AgentID); err == nil && a != nil {
+				locations[i].AgentName = a.Name
+			}
+		}
+	}
+
+	// 13. Dependency tree and root cause
+	var dependencyTree *domain.DependencyNode
+	var rootCauseMonitorID *uuid.UUID
+	if s.dependencyRepo != nil {
+		dependencyTree, rootCauseMonitorID = s.dependencyTree(ctx, incident)
+	}
 
 	// Build agent summary
 	var agentSummary domain.AgentSummary
@@ -182,21 +230,188 @@ func (s *InvestigationService) Investigate(ctx context.Context, incidentID uuid.
 	}
 
 	return &domain.IncidentInvestigation{
-		Incident:          incident,
-		Monitor:           monitor,
-		Agent:             agent,
-		AgentSummary:      agentSummary,
-		Heartbeats:        heartbeats,
-		SiblingMonitors:   siblings,
-		PreviousIncidents: previousIncidents,
-		RecurrencePattern: pattern,
-		MTTRSeconds:       mttr,
-		SystemMetrics:     systemMetrics,
-		CertDetails:       certDetails,
-		Timeline:          timeline,
+		Incident:           incident,
+		Monitor:            monitor,
+		Agent:              agent,
+		AgentSummary:       agentSummary,
+		Heartbeats:         heartbeats,
+		SiblingMonitors:    siblings,
+		PreviousIncidents:  previousIncidents,
+		RecurrencePattern:  pattern,
+		MTTRSeconds:        mttr,
+		SystemMetrics:      systemMetrics,
+		CertDetails:        certDetails,
+		Timeline:           timeline,
+		Locations:          locations,
+		DependencyTree:     dependencyTree,
+		RootCauseMonitorID: rootCauseMonitorID,
+		Postmortem:         postmortem,
 	}, nil
 }
 
+// dependencyTree builds the dependency tree around the incident's monitor and
+// marks the root cause: the monitor whose incident heads the chain of
+// suppressed incidents this one belongs to. Returns nil when the monitor has
+// no dependencies.
+func (s *InvestigationService) dependencyTree(ctx context.Context, incident *domain.Incident) (*domain.DependencyNode, *uuid.UUID) {
+	edges, err := s.dependencyRepo.GetByTenant(ctx)
+	if err != nil {
+		s.logger.Error("failed to get monitor dependencies",
+			slog.String("incident_id", incident.ID.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, nil
+	}
+
+	parents := make(map[uuid.UUID][]uuid.UUID)
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		parents[e.MonitorID] = append(parents[e.MonitorID], e.ParentID)
+		children[e.ParentID] = append(children[e.ParentID], e.MonitorID)
+	}
+	if len(parents[incident.MonitorID]) == 0 && len(children[incident.MonitorID]) == 0 {
+		return nil, nil
+	}
+
+	rootMonitorID := s.rootCauseMonitor(ctx, incident)
+
+	var node func(id uuid.UUID, depth int, upstream bool, seen map[uuid.UUID]bool) *domain.DependencyNode
+	node = func(id uuid.UUID, depth int, upstream bool, seen map[uuid.UUID]bool) *domain.DependencyNode {
+		n := s.describeDependency(ctx, id)
+		n.RootCause = id == rootMonitorID
+		if depth >= domain.MaxDependencyDepth || seen[id] {
+			return n
+		}
+		seen[id] = true
+		if upstream {
+			for _, p := range parents[id] {
+				n.Parents = append(n.Parents, node(p, depth+1, true, seen))
+			}
+		} else {
+			for _, c := range children[id] {
+				n.Children = append(n.Children, node(c, depth+1, false, seen))
+			}
+		}
+		return n
+	}
+
+	tree := s.describeDependency(ctx, incident.MonitorID)
+	tree.RootCause = incident.MonitorID == rootMonitorID
+	for _, p := range parents[incident.MonitorID] {
+		tree.Parents = append(tree.Parents, node(p, 1, true, map[uuid.UUID]bool{incident.MonitorID: true}))
+	}
+	for _, c := range children[incident.MonitorID] {
+		tree.Children = append(tree.Children, node(c, 1, false, map[uuid.UUID]bool{incident.MonitorID: true}))
+	}
+
+	return tree, &rootMonitorID
+}
+
+// rootCauseMonitor follows the incident's parent links up to the incident
+// that is not itself suppressed and returns that incident's monitor.
+func (s *InvestigationService) rootCauseMonitor(ctx context.Context, incident *domain.Incident) uuid.UUID {
+	current := incident
+	for i := 0; i < domain.MaxDependencyDepth && current.IsSuppressed(); i++ {
+		parent, err := s.incidentRepo.GetByID(ctx, *current.ParentIncidentID)
+		if err != nil || parent == nil {
+			break
+		}
+		current = parent
+	}
+	return current.MonitorID
+}
+
+// describeDependency returns a tree node for a monitor with its current
+// status and active incident, if any.
+func (s *InvestigationService) describeDependency(ctx context.Context, monitorID uuid.UUID) *domain.DependencyNode {
+	n := &domain.DependencyNode{MonitorID: monitorID}
+	if m, err := s.monitorRepo.GetByID(ctx, monitorID); err == nil && m != nil {
+		n.Name = m.Name
+		n.Status = m.Status
+	}
+	if inc, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitorID); err == nil && inc != nil {
+		id := inc.ID
+		n.IncidentID = &id
+		n.Suppressed = inc.IsSuppressed()
+	}
+	return n
+}
+
+// addAnomalyEvents merges the monitor's latency anomaly detections in the
+// investigation window into the timeline.
+func (s *InvestigationService) addAnomalyEvents(ctx context.Context, incident *domain.Incident, timeline []domain.TimelineEvent, from, to time.Time) []domain.TimelineEvent {
+	anomalies, err := s.anomalyRepo.GetAnomaliesInRange(ctx, incident.MonitorID, from, to)
+	if err != nil {
+		s.logger.Error("failed to get latency anomalies",
+			slog.String("incident_id", incident.ID.String()),
+			slog.String("error", err.Error()),
+		)
+		return timeline
+	}
+	if len(anomalies) == 0 {
+		return timeline
+	}
+	for _, a := range anomalies {
+		timeline = append(timeline, domain.TimelineEvent{
+			Time: a.DetectedAt,
+			Type: "latency_anomaly",
+			Description: fmt.Sprintf("Latency anomaly: p95 %dms vs baseline %dms (%.1fσ)",
+				a.ObservedP95, a.BaselineP95, a.Sigma),
+			Severity: "warning",
+		})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(timeline[j].Time)
+	})
+	return timeline
+}
+
+// addActivityEvents merges the incident's activity log and postmortem into
+// the timeline. A responder's status change replaces the matching lifecycle
+// event so the timeline shows who made it. Returns the postmortem, if any.
+func (s *InvestigationService) addActivityEvents(ctx context.Context, incident *domain.Incident, timeline []domain.TimelineEvent) ([]domain.TimelineEvent, *domain.IncidentPostmortem) {
+	activity, err := s.activityRepo.GetByIncidentID(ctx, incident.ID)
+	if err != nil {
+		s.logger.Error("failed to get incident activity",
+			slog.String("incident_id", incident.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+	postmortem, err := s.activityRepo.GetPostmortem(ctx, incident.ID)
+	if err != nil {
+		s.logger.Error("failed to get incident postmortem",
+			slog.String("incident_id", incident.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+	if len(activity) == 0 && postmortem == nil {
+		return timeline, postmortem
+	}
+
+	replaced := make(map[string]bool)
+	for _, a := range activity {
+		if a.Kind == domain.IncidentActivityStatusChange {
+			replaced[a.TimelineEvent().Type] = true
+		}
+	}
+	merged := make([]domain.TimelineEvent, 0, len(timeline)+len(activity)+1)
+	for _, ev := range timeline {
+		if !replaced[ev.Type] {
+			merged = append(merged, ev)
+		}
+	}
+	for _, a := range activity {
+		merged = append(merged, a.TimelineEvent())
+	}
+	if postmortem != nil {
+		merged = append(merged, postmortem.TimelineEvent())
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	return merged, postmortem
+}
+
 // detectRecurrencePattern classifies the incident recurrence pattern.
 func detectRecurrencePattern(previousCount int) string {
 	switch {
@@ -230,12 +445,21 @@ func buildTimeline(incident *domain.Incident, heartbeats []*domain.Heartbeat) []
 	var events []domain.TimelineEvent
 
 	// Add incident lifecycle events
-	events = append(events, domain.TimelineEvent{
+	opened := domain.TimelineEvent{
 		Time:        incident.StartedAt,
 		Type:        "incident_opened",
 		Description: "Incident opened",
 		Severity:    "error",
-	})
+	}
+	switch {
+	case incident.IsAnomaly():
+		opened.Description = "Latency anomaly incident opened"
+		opened.Severity = "warning"
+	case incident.IsDegraded():
+		opened.Description = "Degraded performance incident opened"
+		opened.Severity = "warning"
+	}
+	events = append(events, opened)
 
 	if incident.AcknowledgedAt != nil {
 		events = append(events, domain.TimelineEvent{
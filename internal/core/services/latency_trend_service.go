@@ -57,3 +57,12 @@ func (s *LatencyTrendService) GetTrend(ctx context.Context, monitorID uuid.UUID,
 		Previous:       previous,
 	}, nil
 }
+
+// GetSummary returns p50/p95/p99 and the sample count over [from, to].
+func (s *LatencyTrendService) GetSummary(ctx context.Context, monitorID uuid.UUID, from, to time.Time) (domain.LatencyTrendSummary, error) {
+	summary, err := s.heartbeats.GetLatencyPercentileSummary(ctx, monitorID, from, to)
+	if err != nil {
+		return summary, fmt.Errorf("latency summary: %w", err)
+	}
+	return summary, nil
+}
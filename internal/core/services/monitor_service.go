@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,6 +23,7 @@ type MonitorService struct {
 	maintenanceRepo ports.MaintenanceWindowRepository // optional, set by extensions
 	auditSvc        ports.AuditService               // optional, set by extensions
 	transactor      ports.Transactor                  // optional, needed for RLS-safe maintenance checks
+	monitorCache    ports.MonitorCache                // optional, serves monitor reads on the heartbeat path
 	logger          *slog.Logger
 }
 
@@ -67,6 +69,21 @@ func (s *MonitorService) SetTransactor(t ports.Transactor) {
 	s.transactor = t
 }
 
+// SetMonitorCache sets the optional cache heartbeat evaluation reads monitors
+// from. Monitors updated or deleted through this service are invalidated.
+func (s *MonitorService) SetMonitorCache(cache ports.MonitorCache) {
+	s.monitorCache = cache
+}
+
+// heartbeatMonitor reads a monitor for heartbeat evaluation, from the cache
+// when one is set.
+func (s *MonitorService) heartbeatMonitor(ctx context.Context, id uuid.UUID) (*domain.Monitor, error) {
+	if s.monitorCache != nil {
+		return s.monitorCache.Get(ctx, id)
+	}
+	return s.monitorRepo.GetByID(ctx, id)
+}
+
 // CreateMonitor creates a new monitor for an agent, enforcing plan limits.
 func (s *MonitorService) CreateMonitor(ctx context.Context, userID uuid.UUID, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, metadata map[string]string) (*domain.Monitor, error) {
 	// Enforce plan limits
@@ -103,6 +120,13 @@ func (s *MonitorService) CreateMonitor(ctx context.Context, userID uuid.UUID, ag
 	if metadata != nil {
 		monitor.Metadata = metadata
 	}
+	if monitor.IsPush() {
+		token, err := domain.GeneratePushToken()
+		if err != nil {
+			return nil, fmt.Errorf("monitorService.CreateMonitor: %w", err)
+		}
+		monitor.EnablePush(token)
+	}
 
 	if err := s.monitorRepo.Create(ctx, monitor); err != nil {
 		return nil, fmt.Errorf("monitorService.CreateMonitor: %w", err)
@@ -144,11 +168,17 @@ func (s *MonitorService) GetMonitorsByAgent(ctx context.Context, agentID uuid.UU
 	return monitors, nil
 }
 
-// UpdateMonitor updates an existing monitor.
+// UpdateMonitor updates an existing monitor, including its set of probing agents.
 func (s *MonitorService) UpdateMonitor(ctx context.Context, monitor *domain.Monitor) error {
 	if err := s.monitorRepo.Update(ctx, monitor); err != nil {
 		return fmt.Errorf("monitorService.UpdateMonitor: %w", err)
 	}
+	if err := s.monitorRepo.SetLocations(ctx, monitor.ID, monitor.LocationAgentIDs); err != nil {
+		return fmt.Errorf("monitorService.UpdateMonitor: %w", err)
+	}
+	if s.monitorCache != nil {
+		s.monitorCache.Invalidate(monitor.ID)
+	}
 	return nil
 }
 
@@ -157,28 +187,154 @@ func (s *MonitorService) DeleteMonitor(ctx context.Context, id uuid.UUID) error
 	if err := s.monitorRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("monitorService.DeleteMonitor: %w", err)
 	}
+	if s.monitorCache != nil {
+		s.monitorCache.Invalidate(id)
+	}
 	return nil
 }
 
-// ProcessHeartbeat processes a heartbeat from an agent.
-// This is the core method that implements the 3-strike rule:
-// - If the heartbeat is successful, check if we should resolve an open incident
-// - If the heartbeat is a failure, check if we've hit the failure threshold
+// ProcessHeartbeat stores a heartbeat from an agent and evaluates it.
 func (s *MonitorService) ProcessHeartbeat(ctx context.Context, heartbeat *domain.Heartbeat) error {
-	// 1. Store the heartbeat
 	if err := s.heartbeatRepo.Create(ctx, heartbeat); err != nil {
 		return fmt.Errorf("monitorService.ProcessHeartbeat: store heartbeat: %w", err)
 	}
+	return s.EvaluateHeartbeat(ctx, heartbeat)
+}
 
-	// 2. Handle success or failure
-	if heartbeat.IsSuccess() {
+// EvaluateHeartbeat updates incident state for an already stored heartbeat.
+// This is the core method that implements the 3-strike rule:
+// - If the heartbeat is successful, check if we should resolve an open incident
+// - If the heartbeat is a failure, check if we've hit the failure threshold
+//
+// A multi-location monitor is up or down by the quorum of its probing agents
+// instead of by the heartbeat alone; either way the verdict goes through the
+// same flapping, degraded and recovery handling.
+func (s *MonitorService) EvaluateHeartbeat(ctx context.Context, heartbeat *domain.Heartbeat) error {
+	monitor, err := s.heartbeatMonitor(ctx, heartbeat.MonitorID)
+	if err != nil {
+		return fmt.Errorf("monitorService.EvaluateHeartbeat: get monitor: %w", err)
+	}
+
+	// 1. Multi-location monitors follow a quorum of probing agents
+	up := heartbeat.IsSuccess()
+	if monitor != nil && monitor.IsMultiLocation() {
+		down, err := s.quorumDown(ctx, monitor)
+		if err != nil {
+			return fmt.Errorf("monitorService.EvaluateHeartbeat: %w", err)
+		}
+		up = !down
+	}
+
+	// 2. Hold flapping incidents until the monitor stabilises
+	held, err := s.holdIfFlapping(ctx, monitor)
+	if err != nil {
+		return fmt.Errorf("monitorService.EvaluateHeartbeat: %w", err)
+	}
+	if held {
+		return nil
+	}
+
+	// 3. Handle success or failure
+	if up {
 		return s.handleRecovery(ctx, heartbeat.MonitorID)
 	}
 
 	return s.handleFailure(ctx, heartbeat.MonitorID)
 }
 
-// handleRecovery handles a successful heartbeat, resolving any active incident (open or acknowledged).
+// holdIfFlapping runs the monitor's flap detector against its active incident.
+// A flapping monitor moves the incident into the flapping state and the
+// heartbeat is absorbed (true), so no open/resolve notifications are sent.
+// Once the state-change rate drops below the threshold the incident returns
+// to open and normal threshold handling resumes.
+func (s *MonitorService) holdIfFlapping(ctx context.Context, monitor *domain.Monitor) (bool, error) {
+	if monitor == nil || monitor.FlapWindow == 0 {
+		return false, nil
+	}
+	monitorID := monitor.ID
+
+	incident, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitorID)
+	if err != nil {
+		return false, fmt.Errorf("check active incident: %w", err)
+	}
+	if incident == nil {
+		return false, nil
+	}
+
+	flapping, err := s.isFlapping(ctx, monitor)
+	if err != nil {
+		return false, fmt.Errorf("get recent heartbeats: %w", err)
+	}
+
+	switch {
+	case flapping && incident.IsFlapping():
+		return true, nil
+	case flapping:
+		if err := s.incidentSvc.SetIncidentFlapping(ctx, incident.ID, true); err != nil {
+			return false, fmt.Errorf("mark incident flapping: %w", err)
+		}
+		s.logger.Info("monitor is flapping, holding incident notifications",
+			"incident_id", incident.ID,
+			"monitor_id", monitorID,
+			"flap_window", monitor.FlapWindow,
+		)
+		return true, nil
+	case incident.IsFlapping():
+		if err := s.incidentSvc.SetIncidentFlapping(ctx, incident.ID, false); err != nil {
+			return false, fmt.Errorf("clear incident flapping: %w", err)
+		}
+		s.logger.Info("monitor stabilised, incident no longer flapping",
+			"incident_id", incident.ID,
+			"monitor_id", monitorID,
+		)
+	}
+
+	return false, nil
+}
+
+// isFlapping runs the monitor's flap detector over its last FlapWindow checks,
+// or, for a multi-location monitor, over each location's.
+func (s *MonitorService) isFlapping(ctx context.Context, monitor *domain.Monitor) (bool, error) {
+	if monitor.IsMultiLocation() {
+		recent, err := s.heartbeatRepo.GetRecentPerAgent(ctx, monitor.ID, monitor.FlapWindow)
+		if err != nil {
+			return false, err
+		}
+		return monitor.LocationsFlapping(recent), nil
+	}
+
+	recent, err := s.heartbeatRepo.GetByMonitorID(ctx, monitor.ID, monitor.FlapWindow)
+	if err != nil {
+		return false, err
+	}
+	return monitor.IsFlapping(recent), nil
+}
+
+// quorumDown reports whether a multi-location monitor is down. Each probing
+// agent's recent heartbeats yield a per-location verdict; the monitor is down
+// once the failing locations reach its quorum.
+func (s *MonitorService) quorumDown(ctx context.Context, monitor *domain.Monitor) (bool, error) {
+	threshold := monitor.FailureThreshold
+	if threshold < 1 {
+		threshold = domain.DefaultFailureThreshold
+	}
+	recent, err := s.heartbeatRepo.GetRecentPerAgent(ctx, monitor.ID, threshold)
+	if err != nil {
+		return false, fmt.Errorf("get recent heartbeats: %w", err)
+	}
+	failing, down := monitor.EvaluateQuorum(monitor.LocationBreakdown(recent, time.Now()))
+
+	s.logger.Debug("location quorum evaluated",
+		"monitor_id", monitor.ID,
+		"failing_locations", failing,
+		"quorum", monitor.EffectiveQuorum(),
+	)
+	return down, nil
+}
+
+// handleRecovery handles a successful heartbeat, resolving any active incident once
+// the monitor's recovery threshold of consecutive successes is met.
+// A monitor that answers but breaches its degraded rules moves to (or stays in) a degraded incident.
 func (s *MonitorService) handleRecovery(ctx context.Context, monitorID uuid.UUID) error {
 	// Check for an active incident (open or acknowledged)
 	incident, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitorID)
@@ -186,8 +342,17 @@ func (s *MonitorService) handleRecovery(ctx context.Context, monitorID uuid.UUID
 		return fmt.Errorf("check active incident: %w", err)
 	}
 
-	// No active incident, just update status to up
+	monitor, err := s.heartbeatMonitor(ctx, monitorID)
+	if err != nil {
+		return fmt.Errorf("get monitor: %w", err)
+	}
+	degraded, reason := s.evaluateDegraded(ctx, monitor)
+
+	// No active incident, just update status to up (or open a degraded incident)
 	if incident == nil {
+		if degraded {
+			return s.openDegradedIncident(ctx, monitor, reason)
+		}
 		if err := s.monitorRepo.UpdateStatus(ctx, monitorID, domain.MonitorStatusUp); err != nil {
 			s.logger.Warn("failed to update monitor status to up",
 				"monitor_id", monitorID,
@@ -197,6 +362,26 @@ func (s *MonitorService) handleRecovery(ctx context.Context, monitorID uuid.UUID
 		return nil
 	}
 
+	// Still degraded — keep the existing degraded incident open
+	if incident.IsDegraded() && degraded {
+		return nil
+	}
+
+	// Anomaly incidents are resolved by the anomaly detector once latency
+	// is back within baseline, not by individual successful checks
+	if incident.IsAnomaly() {
+		return nil
+	}
+
+	// Not enough consecutive successes yet — keep the incident open
+	recovered, err := s.hasRecovered(ctx, monitor)
+	if err != nil {
+		return fmt.Errorf("get recent heartbeats: %w", err)
+	}
+	if !recovered {
+		return nil
+	}
+
 	// Resolve the incident (this also updates monitor status)
 	if err := s.incidentSvc.ResolveIncident(ctx, incident.ID); err != nil {
 		return fmt.Errorf("resolve incident: %w", err)
@@ -207,14 +392,21 @@ func (s *MonitorService) handleRecovery(ctx context.Context, monitorID uuid.UUID
 		"monitor_id", monitorID,
 	)
 
+	// Back from an outage but not yet healthy
+	if degraded {
+		return s.openDegradedIncident(ctx, monitor, reason)
+	}
+
 	return nil
 }
 
 // handleFailure handles a failed heartbeat, potentially creating an incident.
 // Uses the monitor's configurable failure threshold (defaults to 3-strike rule).
+// Failures below the threshold may still trip the partial-failure degraded rule,
+// and an active degraded incident is escalated once the threshold is hit.
 func (s *MonitorService) handleFailure(ctx context.Context, monitorID uuid.UUID) error {
 	// Fetch the monitor to get its configurable failure threshold
-	monitor, err := s.monitorRepo.GetByID(ctx, monitorID)
+	monitor, err := s.heartbeatMonitor(ctx, monitorID)
 	if err != nil {
 		return fmt.Errorf("get monitor: %w", err)
 	}
@@ -232,76 +424,59 @@ func (s *MonitorService) handleFailure(ctx context.Context, monitorID uuid.UUID)
 		return fmt.Errorf("check existing incident: %w", err)
 	}
 
-	// If incident already exists, just update status and return
-	if existing != nil {
-		// Incident already open, nothing more to do
+	// If an outage incident already exists there is nothing more to do
+	if existing != nil && !existing.IsDegraded() {
 		return nil
 	}
 
-	// Check recent heartbeats to see if we've hit the threshold
-	// We need to verify we have threshold consecutive failures
-	recentHeartbeats, err := s.heartbeatRepo.GetByMonitorID(ctx, monitorID, threshold)
-	if err != nil {
-		return fmt.Errorf("get recent heartbeats: %w", err)
-	}
+	// A multi-location monitor's quorum already counts the threshold of
+	// failures at each location
+	if !monitor.IsMultiLocation() {
+		// Check recent heartbeats to see if we've hit the threshold
+		// We need to verify we have threshold consecutive failures
+		recentHeartbeats, err := s.heartbeatRepo.GetByMonitorID(ctx, monitorID, threshold)
+		if err != nil {
+			return fmt.Errorf("get recent heartbeats: %w", err)
+		}
 
-	// Not enough heartbeats yet
-	if len(recentHeartbeats) < threshold {
-		s.logger.Debug("not enough heartbeats for threshold",
-			"monitor_id", monitorID,
-			"count", len(recentHeartbeats),
-			"threshold", threshold,
-		)
-		return nil
-	}
+		// Not enough heartbeats yet
+		if len(recentHeartbeats) < threshold {
+			s.logger.Debug("not enough heartbeats for threshold",
+				"monitor_id", monitorID,
+				"count", len(recentHeartbeats),
+				"threshold", threshold,
+			)
+			return s.markDegradedIfNeeded(ctx, monitor, existing)
+		}
 
-	// Check if all recent heartbeats are failures
-	allFailures := true
-	for _, hb := range recentHeartbeats {
-		if hb.IsSuccess() {
-			allFailures = false
-			break
+		// Check if all recent heartbeats are failures
+		allFailures := true
+		for _, hb := range recentHeartbeats {
+			if hb.IsSuccess() {
+				allFailures = false
+				break
+			}
+		}
+
+		if !allFailures {
+			// Not enough consecutive failures yet
+			s.logger.Debug("not enough consecutive failures",
+				"monitor_id", monitorID,
+				"threshold", threshold,
+			)
+			return s.markDegradedIfNeeded(ctx, monitor, existing)
 		}
 	}
 
-	if !allFailures {
-		// Not enough consecutive failures yet
-		s.logger.Debug("not enough consecutive failures",
-			"monitor_id", monitorID,
-			"threshold", threshold,
-		)
+	// Check if agent is in a maintenance window — suppress incident creation if so.
+	if s.inMaintenance(ctx, monitor) {
 		return nil
 	}
 
-	// Check if agent is in a maintenance window — suppress incident creation if so.
-	// Must run inside a transaction so SET LOCAL app.tenant_id is applied for RLS.
-	if s.maintenanceRepo != nil {
-		var window *domain.MaintenanceWindow
-		var mwErr error
-		if s.transactor != nil {
-			if txErr := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
-				window, mwErr = s.maintenanceRepo.GetActiveByAgentID(txCtx, monitor.AgentID)
-				return mwErr
-			}); txErr != nil {
-				mwErr = txErr
-			}
-		} else {
-			window, mwErr = s.maintenanceRepo.GetActiveByAgentID(ctx, monitor.AgentID)
-		}
-		if mwErr != nil {
-			s.logger.Warn("failed to check maintenance window, proceeding with incident",
-				"monitor_id", monitorID,
-				"agent_id", monitor.AgentID,
-				"error", mwErr,
-			)
-		} else if window != nil {
-			s.logger.Info("suppressing incident during maintenance window",
-				"monitor_id", monitorID,
-				"agent_id", monitor.AgentID,
-				"window_id", window.ID,
-				"window_name", window.Name,
-			)
-			return nil
+	// Escalate: the degraded incident is superseded by the outage incident
+	if existing != nil {
+		if err := s.incidentSvc.ResolveIncidentSilently(ctx, existing.ID); err != nil {
+			return fmt.Errorf("resolve degraded incident: %w", err)
 		}
 	}
 
@@ -320,6 +495,126 @@ func (s *MonitorService) handleFailure(ctx context.Context, monitorID uuid.UUID)
 	return nil
 }
 
+// hasRecovered reports whether the monitor's last RecoveryThreshold heartbeats
+// all succeeded, or for a multi-location monitor, whether enough of its
+// locations' did. A threshold of one resolves on the first success.
+func (s *MonitorService) hasRecovered(ctx context.Context, monitor *domain.Monitor) (bool, error) {
+	if monitor == nil || monitor.RecoveryThreshold <= 1 {
+		return true, nil
+	}
+	if monitor.IsMultiLocation() {
+		recent, err := s.heartbeatRepo.GetRecentPerAgent(ctx, monitor.ID, monitor.RecoveryThreshold)
+		if err != nil {
+			return false, err
+		}
+		return monitor.LocationsRecovered(recent), nil
+	}
+
+	recent, err := s.heartbeatRepo.GetByMonitorID(ctx, monitor.ID, monitor.RecoveryThreshold)
+	if err != nil {
+		return false, err
+	}
+	if len(recent) < monitor.RecoveryThreshold {
+		return false, nil
+	}
+	for _, hb := range recent {
+		if !hb.IsSuccess() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// markDegradedIfNeeded opens a degraded incident when the monitor has no active
+// incident and breaches one of its degraded rules.
+func (s *MonitorService) markDegradedIfNeeded(ctx context.Context, monitor *domain.Monitor, existing *domain.Incident) error {
+	if existing != nil {
+		return nil
+	}
+	degraded, reason := s.evaluateDegraded(ctx, monitor)
+	if !degraded {
+		return nil
+	}
+	return s.openDegradedIncident(ctx, monitor, reason)
+}
+
+// evaluateDegraded checks the monitor's degraded rules against its recent heartbeats.
+// Lookup errors are logged and treated as "not degraded".
+func (s *MonitorService) evaluateDegraded(ctx context.Context, monitor *domain.Monitor) (bool, string) {
+	if monitor == nil || !monitor.HasDegradedRules() {
+		return false, ""
+	}
+	recent, err := s.heartbeatRepo.GetByMonitorID(ctx, monitor.ID, monitor.DegradedHistorySize())
+	if err != nil {
+		s.logger.Warn("failed to load heartbeats for degraded rules",
+			"monitor_id", monitor.ID,
+			"error", err,
+		)
+		return false, ""
+	}
+	return monitor.EvaluateDegraded(recent)
+}
+
+// openDegradedIncident opens a degraded incident unless the agent is in maintenance.
+func (s *MonitorService) openDegradedIncident(ctx context.Context, monitor *domain.Monitor, reason string) error {
+	if s.inMaintenance(ctx, monitor) {
+		return nil
+	}
+
+	incident, err := s.incidentSvc.CreateDegradedIncidentIfNeeded(ctx, monitor.ID)
+	if err != nil {
+		return fmt.Errorf("create degraded incident: %w", err)
+	}
+	if incident != nil {
+		s.logger.Info("degraded incident opened",
+			"incident_id", incident.ID,
+			"monitor_id", monitor.ID,
+			"reason", reason,
+		)
+	}
+	return nil
+}
+
+// inMaintenance reports whether the monitor's agent is in an active maintenance window.
+// Must run inside a transaction so SET LOCAL app.tenant_id is applied for RLS.
+// Lookup errors fail open (returns false).
+func (s *MonitorService) inMaintenance(ctx context.Context, monitor *domain.Monitor) bool {
+	if s.maintenanceRepo == nil {
+		return false
+	}
+
+	var window *domain.MaintenanceWindow
+	var mwErr error
+	if s.transactor != nil {
+		if txErr := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+			window, mwErr = s.maintenanceRepo.GetActiveByAgentID(txCtx, monitor.AgentID)
+			return mwErr
+		}); txErr != nil {
+			mwErr = txErr
+		}
+	} else {
+		window, mwErr = s.maintenanceRepo.GetActiveByAgentID(ctx, monitor.AgentID)
+	}
+	if mwErr != nil {
+		s.logger.Warn("failed to check maintenance window, proceeding with incident",
+			"monitor_id", monitor.ID,
+			"agent_id", monitor.AgentID,
+			"error", mwErr,
+		)
+		return false
+	}
+	if window != nil {
+		s.logger.Info("suppressing incident during maintenance window",
+			"monitor_id", monitor.ID,
+			"agent_id", monitor.AgentID,
+			"window_id", window.ID,
+			"window_name", window.Name,
+		)
+		return true
+	}
+	return false
+}
+
 // MarkAgentMonitorsDown marks all enabled, healthy monitors for a disconnected agent as down.
 // Creates incidents silently (no per-monitor alerts) and sends a single agent-offline notification.
 // If a maintenance window is active for the agent, alerts are suppressed (fail-open on error).
@@ -371,7 +666,13 @@ func (s *MonitorService) MarkAgentMonitorsDown(ctx context.Context, agentID uuid
 
 	marked := 0
 	for _, monitor := range monitors {
-		if !monitor.Enabled || monitor.Status != domain.MonitorStatusUp {
+		// A degraded monitor is still reachable, so it goes down with its agent.
+		if !monitor.Enabled || (monitor.Status != domain.MonitorStatusUp && monitor.Status != domain.MonitorStatusDegraded) {
+			continue
+		}
+		// One agent is only one location; the quorum decides for the others.
+		// Push monitors are pinged by their jobs, not checked by the agent.
+		if monitor.IsMultiLocation() || monitor.IsPush() {
 			continue
 		}
 
@@ -407,6 +708,9 @@ func (s *MonitorService) ResolveAgentMonitors(ctx context.Context, agentID uuid.
 
 	resolved := 0
 	for _, monitor := range monitors {
+		if monitor.IsMultiLocation() || monitor.IsPush() {
+			continue
+		}
 		incident, err := s.incidentRepo.GetActiveByMonitorID(ctx, monitor.ID)
 		if err != nil {
 			s.logger.Error("failed to check active incident",
@@ -441,7 +745,8 @@ func (s *MonitorService) ResolveAgentMonitors(ctx context.Context, agentID uuid.
 	return nil
 }
 
-// GetEnabledMonitorsByAgent retrieves all enabled monitors for an agent.
+// GetEnabledMonitorsByAgent retrieves all enabled monitors an agent should run,
+// including multi-location monitors it probes for another owner agent.
 // This is used for task distribution when an agent connects.
 func (s *MonitorService) GetEnabledMonitorsByAgent(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error) {
 	monitors, err := s.monitorRepo.GetEnabledByAgentID(ctx, agentID)
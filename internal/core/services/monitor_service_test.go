@@ -484,3 +484,545 @@ func TestListMonitorsByTags_RepoErrorIsWrapped(t *testing.T) {
 	assert.Nil(t, monitors)
 	assert.ErrorIs(t, err, storeErr, "repo error must be wrapped, not swallowed")
 }
+
+// --- Degraded rules ---
+
+func mockMonitorRepoWithLatencyRule(thresholdMs, checks int) *mocks.MockMonitorRepository {
+	return &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			m := domain.NewMonitor(uuid.New(), "test", domain.MonitorTypeHTTP, "example.com")
+			m.ID = id
+			m.SetDegradedLatency(thresholdMs, checks)
+			return m, nil
+		},
+	}
+}
+
+func TestProcessHeartbeat_SlowSuccesses_OpensDegradedIncident(t *testing.T) {
+	monitorID := uuid.New()
+	degradedOpened := false
+
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetByMonitorIDFn: func(_ context.Context, _ uuid.UUID, limit int) ([]*domain.Heartbeat, error) {
+			assert.Equal(t, 2, limit)
+			return []*domain.Heartbeat{
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 900),
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 800),
+			}, nil
+		},
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return nil, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		CreateDegradedIncidentIfNeededFn: func(_ context.Context, id uuid.UUID) (*domain.Incident, error) {
+			assert.Equal(t, monitorID, id)
+			degradedOpened = true
+			return domain.NewDegradedIncident(id), nil
+		},
+	}
+
+	svc := newTestMonitorService(mockMonitorRepoWithLatencyRule(500, 2), heartbeatRepo, incidentRepo, incidentSvc)
+
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewSuccessHeartbeat(monitorID, uuid.New(), 900))
+
+	require.NoError(t, err)
+	assert.True(t, degradedOpened)
+}
+
+func TestProcessHeartbeat_FastSuccess_ResolvesDegradedIncident(t *testing.T) {
+	monitorID := uuid.New()
+	degraded := domain.NewDegradedIncident(monitorID)
+	resolved := false
+
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetByMonitorIDFn: func(_ context.Context, _ uuid.UUID, _ int) ([]*domain.Heartbeat, error) {
+			return []*domain.Heartbeat{
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 800),
+			}, nil
+		},
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return degraded, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		ResolveIncidentFn: func(_ context.Context, id uuid.UUID) error {
+			assert.Equal(t, degraded.ID, id)
+			resolved = true
+			return nil
+		},
+	}
+
+	svc := newTestMonitorService(mockMonitorRepoWithLatencyRule(500, 2), heartbeatRepo, incidentRepo, incidentSvc)
+
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50))
+
+	require.NoError(t, err)
+	assert.True(t, resolved)
+}
+
+func TestProcessHeartbeat_ThresholdHit_EscalatesDegradedIncident(t *testing.T) {
+	monitorID := uuid.New()
+	degraded := domain.NewDegradedIncident(monitorID)
+	var calls []string
+
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetByMonitorIDFn: func(_ context.Context, _ uuid.UUID, _ int) ([]*domain.Heartbeat, error) {
+			return []*domain.Heartbeat{
+				domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"),
+				domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"),
+				domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"),
+			}, nil
+		},
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return degraded, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		ResolveIncidentSilentlyFn: func(_ context.Context, id uuid.UUID) error {
+			assert.Equal(t, degraded.ID, id)
+			calls = append(calls, "resolve_degraded")
+			return nil
+		},
+		CreateIncidentIfNeededFn: func(_ context.Context, id uuid.UUID) (*domain.Incident, error) {
+			calls = append(calls, "open_down")
+			return domain.NewIncident(id), nil
+		},
+	}
+
+	svc := newTestMonitorService(mockMonitorRepoWithThreshold(monitorID, domain.DefaultFailureThreshold), heartbeatRepo, incidentRepo, incidentSvc)
+
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"resolve_degraded", "open_down"}, calls)
+}
+
+// --- Recovery threshold and flap detection ---
+
+func mockMonitorRepoWith(configure func(m *domain.Monitor)) *mocks.MockMonitorRepository {
+	return &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			m := domain.NewMonitor(uuid.New(), "test", domain.MonitorTypeHTTP, "example.com")
+			m.ID = id
+			configure(m)
+			return m, nil
+		},
+	}
+}
+
+func TestProcessHeartbeat_Success_BelowRecoveryThreshold_KeepsIncident(t *testing.T) {
+	monitorID := uuid.New()
+	incident := domain.NewIncident(monitorID)
+
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetByMonitorIDFn: func(_ context.Context, _ uuid.UUID, _ int) ([]*domain.Heartbeat, error) {
+			return []*domain.Heartbeat{
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+				domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"),
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+			}, nil
+		},
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return incident, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		ResolveIncidentFn: func(_ context.Context, _ uuid.UUID) error {
+			t.Fatal("incident must not resolve before the recovery threshold is met")
+			return nil
+		},
+	}
+	monitorRepo := mockMonitorRepoWith(func(m *domain.Monitor) { m.SetRecoveryThreshold(3) })
+
+	svc := newTestMonitorService(monitorRepo, heartbeatRepo, incidentRepo, incidentSvc)
+
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50))
+
+	require.NoError(t, err)
+}
+
+func TestProcessHeartbeat_Flapping_HoldsIncidentWithoutNotifying(t *testing.T) {
+	monitorID := uuid.New()
+	incident := domain.NewIncident(monitorID)
+	var flapping *bool
+
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetByMonitorIDFn: func(_ context.Context, _ uuid.UUID, _ int) ([]*domain.Heartbeat, error) {
+			return []*domain.Heartbeat{
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+				domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"),
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+				domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"),
+			}, nil
+		},
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return incident, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		SetIncidentFlappingFn: func(_ context.Context, id uuid.UUID, f bool) error {
+			assert.Equal(t, incident.ID, id)
+			flapping = &f
+			return nil
+		},
+		ResolveIncidentFn: func(_ context.Context, _ uuid.UUID) error {
+			t.Fatal("flapping incident must not resolve")
+			return nil
+		},
+	}
+	monitorRepo := mockMonitorRepoWith(func(m *domain.Monitor) { m.SetFlapDetection(4, 50) })
+
+	svc := newTestMonitorService(monitorRepo, heartbeatRepo, incidentRepo, incidentSvc)
+
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50))
+
+	require.NoError(t, err)
+	require.NotNil(t, flapping)
+	assert.True(t, *flapping)
+}
+
+func TestProcessHeartbeat_FlappingStabilised_ResolvesIncident(t *testing.T) {
+	monitorID := uuid.New()
+	incident := domain.NewIncident(monitorID)
+	incident.Status = domain.IncidentStatusFlapping
+	var calls []string
+
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetByMonitorIDFn: func(_ context.Context, _ uuid.UUID, limit int) ([]*domain.Heartbeat, error) {
+			recent := []*domain.Heartbeat{
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+				domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50),
+				domain.NewFailureHeartbeat(monitorID, uuid.New(), domain.HeartbeatStatusDown, "err"),
+			}
+			return recent[:limit], nil
+		},
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return incident, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		SetIncidentFlappingFn: func(_ context.Context, _ uuid.UUID, f bool) error {
+			assert.False(t, f)
+			calls = append(calls, "clear_flapping")
+			return nil
+		},
+		ResolveIncidentFn: func(_ context.Context, _ uuid.UUID) error {
+			calls = append(calls, "resolve")
+			return nil
+		},
+	}
+	monitorRepo := mockMonitorRepoWith(func(m *domain.Monitor) {
+		m.SetFlapDetection(4, 50)
+		m.SetRecoveryThreshold(3)
+	})
+
+	svc := newTestMonitorService(monitorRepo, heartbeatRepo, incidentRepo, incidentSvc)
+
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewSuccessHeartbeat(monitorID, uuid.New(), 50))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"clear_flapping", "resolve"}, calls)
+}
+
+// --- Multi-location quorum ---
+
+func TestProcessHeartbeat_MultiLocation_QuorumOpensIncident(t *testing.T) {
+	monitorID, owner, a, b := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	created := false
+
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			m := domain.NewMonitor(owner, "test", domain.MonitorTypeHTTP, "example.com")
+			m.ID = id
+			m.FailureThreshold = 1
+			m.SetLocations([]uuid.UUID{a, b}, 2)
+			return m, nil
+		},
+	}
+	recent := []*domain.Heartbeat{
+		domain.NewFailureHeartbeat(monitorID, a, domain.HeartbeatStatusDown, "err"),
+		domain.NewSuccessHeartbeat(monitorID, b, 40),
+	}
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetRecentPerAgentFn: func(_ context.Context, _ uuid.UUID, _ int) ([]*domain.Heartbeat, error) {
+			return recent, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		CreateIncidentIfNeededFn: func(_ context.Context, id uuid.UUID) (*domain.Incident, error) {
+			created = true
+			return domain.NewIncident(id), nil
+		},
+	}
+
+	svc := newTestMonitorService(monitorRepo, heartbeatRepo, &mocks.MockIncidentRepository{}, incidentSvc)
+
+	// One of three locations failing is below the quorum of two.
+	require.NoError(t, svc.ProcessHeartbeat(context.Background(), recent[0]))
+	assert.False(t, created)
+
+	// A second failing location reaches the quorum.
+	recent = append([]*domain.Heartbeat{domain.NewFailureHeartbeat(monitorID, owner, domain.HeartbeatStatusDown, "err")}, recent...)
+	require.NoError(t, svc.ProcessHeartbeat(context.Background(), recent[0]))
+	assert.True(t, created)
+}
+
+func TestProcessHeartbeat_MultiLocation_BelowQuorumResolvesIncident(t *testing.T) {
+	monitorID, owner, a := uuid.New(), uuid.New(), uuid.New()
+	incident := domain.NewIncident(monitorID)
+	resolved := false
+
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			m := domain.NewMonitor(owner, "test", domain.MonitorTypeHTTP, "example.com")
+			m.ID = id
+			m.FailureThreshold = 1
+			m.SetLocations([]uuid.UUID{a}, 0)
+			return m, nil
+		},
+	}
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetRecentPerAgentFn: func(_ context.Context, _ uuid.UUID, _ int) ([]*domain.Heartbeat, error) {
+			return []*domain.Heartbeat{
+				domain.NewSuccessHeartbeat(monitorID, owner, 40),
+				domain.NewFailureHeartbeat(monitorID, a, domain.HeartbeatStatusDown, "err"),
+			}, nil
+		},
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return incident, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		ResolveIncidentFn: func(_ context.Context, id uuid.UUID) error {
+			assert.Equal(t, incident.ID, id)
+			resolved = true
+			return nil
+		},
+	}
+
+	svc := newTestMonitorService(monitorRepo, heartbeatRepo, incidentRepo, incidentSvc)
+
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewSuccessHeartbeat(monitorID, owner, 40))
+
+	require.NoError(t, err)
+	assert.True(t, resolved, "one of two locations failing is below the majority quorum")
+}
+
+// perAgentHeartbeats serves each agent's heartbeats (newest first) the way
+// GetRecentPerAgent does, up to perAgent of them each.
+func perAgentHeartbeats(byAgent map[uuid.UUID][]*domain.Heartbeat) func(context.Context, uuid.UUID, int) ([]*domain.Heartbeat, error) {
+	return func(_ context.Context, _ uuid.UUID, perAgent int) ([]*domain.Heartbeat, error) {
+		var recent []*domain.Heartbeat
+		for _, hbs := range byAgent {
+			recent = append(recent, hbs[:min(perAgent, len(hbs))]...)
+		}
+		return recent, nil
+	}
+}
+
+func TestProcessHeartbeat_MultiLocation_FlappingHoldsIncident(t *testing.T) {
+	monitorID, owner, a, b := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	incident := domain.NewIncident(monitorID)
+	var flapping *bool
+
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			m := domain.NewMonitor(owner, "test", domain.MonitorTypeHTTP, "example.com")
+			m.ID = id
+			m.FailureThreshold = 1
+			m.SetLocations([]uuid.UUID{a, b}, 2)
+			m.SetFlapDetection(4, 50)
+			return m, nil
+		},
+	}
+	flap := func(agentID uuid.UUID) []*domain.Heartbeat {
+		return []*domain.Heartbeat{
+			domain.NewSuccessHeartbeat(monitorID, agentID, 50),
+			domain.NewFailureHeartbeat(monitorID, agentID, domain.HeartbeatStatusDown, "err"),
+			domain.NewSuccessHeartbeat(monitorID, agentID, 50),
+			domain.NewFailureHeartbeat(monitorID, agentID, domain.HeartbeatStatusDown, "err"),
+		}
+	}
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetRecentPerAgentFn: perAgentHeartbeats(map[uuid.UUID][]*domain.Heartbeat{
+			owner: flap(owner),
+			a:     flap(a),
+			b:     {domain.NewSuccessHeartbeat(monitorID, b, 50)},
+		}),
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return incident, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		SetIncidentFlappingFn: func(_ context.Context, id uuid.UUID, f bool) error {
+			assert.Equal(t, incident.ID, id)
+			flapping = &f
+			return nil
+		},
+		ResolveIncidentFn: func(_ context.Context, _ uuid.UUID) error {
+			t.Fatal("flapping incident must not resolve")
+			return nil
+		},
+	}
+
+	svc := newTestMonitorService(monitorRepo, heartbeatRepo, incidentRepo, incidentSvc)
+
+	// Every location's latest check succeeded, so the quorum is up, but two
+	// of three locations keep changing state.
+	err := svc.ProcessHeartbeat(context.Background(), domain.NewSuccessHeartbeat(monitorID, owner, 50))
+
+	require.NoError(t, err)
+	require.NotNil(t, flapping)
+	assert.True(t, *flapping)
+}
+
+func TestProcessHeartbeat_MultiLocation_BelowRecoveryThreshold_KeepsIncident(t *testing.T) {
+	monitorID, owner, a, b := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	incident := domain.NewIncident(monitorID)
+
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+			m := domain.NewMonitor(owner, "test", domain.MonitorTypeHTTP, "example.com")
+			m.ID = id
+			m.FailureThreshold = 1
+			m.SetLocations([]uuid.UUID{a, b}, 2)
+			m.SetRecoveryThreshold(2)
+			return m, nil
+		},
+	}
+	byAgent := map[uuid.UUID][]*domain.Heartbeat{
+		owner: {
+			domain.NewSuccessHeartbeat(monitorID, owner, 50),
+			domain.NewFailureHeartbeat(monitorID, owner, domain.HeartbeatStatusDown, "err"),
+		},
+		a: {
+			domain.NewSuccessHeartbeat(monitorID, a, 50),
+			domain.NewFailureHeartbeat(monitorID, a, domain.HeartbeatStatusDown, "err"),
+		},
+		b: {domain.NewFailureHeartbeat(monitorID, b, domain.HeartbeatStatusDown, "err")},
+	}
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn:            func(_ context.Context, _ *domain.Heartbeat) error { return nil },
+		GetRecentPerAgentFn: perAgentHeartbeats(byAgent),
+	}
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return incident, nil
+		},
+	}
+	resolved := false
+	incidentSvc := &mocks.MockIncidentService{
+		ResolveIncidentFn: func(_ context.Context, _ uuid.UUID) error {
+			resolved = true
+			return nil
+		},
+	}
+
+	svc := newTestMonitorService(monitorRepo, heartbeatRepo, incidentRepo, incidentSvc)
+
+	// One failing location is below the quorum, but no location has two
+	// successes in a row yet.
+	require.NoError(t, svc.ProcessHeartbeat(context.Background(), byAgent[a][0]))
+	assert.False(t, resolved)
+
+	// A second success at two locations meets the recovery threshold there.
+	for _, agentID := range []uuid.UUID{owner, a} {
+		byAgent[agentID] = append([]*domain.Heartbeat{domain.NewSuccessHeartbeat(monitorID, agentID, 50)}, byAgent[agentID]...)
+		byAgent[agentID] = byAgent[agentID][:2]
+	}
+	require.NoError(t, svc.ProcessHeartbeat(context.Background(), byAgent[a][0]))
+	assert.True(t, resolved)
+}
+
+func TestMarkAgentMonitorsDown_DegradedMonitor(t *testing.T) {
+	agentID := uuid.New()
+	monitor := domain.NewMonitor(agentID, "api", domain.MonitorTypeHTTP, "https://example.com")
+	monitor.Status = domain.MonitorStatusDegraded
+	degraded := domain.NewDegradedIncident(monitor.ID)
+
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByAgentIDFn: func(_ context.Context, _ uuid.UUID) ([]*domain.Monitor, error) {
+			return []*domain.Monitor{monitor}, nil
+		},
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Monitor, error) {
+			return monitor, nil
+		},
+		UpdateStatusFn: func(_ context.Context, _ uuid.UUID, status domain.MonitorStatus) error {
+			monitor.Status = status
+			return nil
+		},
+	}
+	active := degraded
+	incidentRepo := &mocks.MockIncidentRepository{
+		GetActiveByMonitorIDFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			return active, nil
+		},
+		ResolveFn: func(_ context.Context, id uuid.UUID) error {
+			require.Equal(t, degraded.ID, id)
+			active = nil
+			return nil
+		},
+		CreateFn: func(_ context.Context, incident *domain.Incident) error {
+			require.Nil(t, active, "the degraded incident is resolved before the outage opens")
+			active = incident
+			return nil
+		},
+	}
+	agentRepo := &mocks.MockAgentRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Agent, error) {
+			return &domain.Agent{ID: id, Name: "edge"}, nil
+		},
+	}
+	var affected int
+	notifier := &mocks.MockNotifier{
+		NotifyAgentOfflineFn: func(_ context.Context, _ *domain.Agent, affectedMonitors int) error {
+			affected = affectedMonitors
+			return nil
+		},
+	}
+	transactions := 0
+	transactor := &mocks.MockTransactor{
+		WithTransactionFn: func(ctx context.Context, fn func(context.Context) error) error {
+			transactions++
+			return fn(ctx)
+		},
+	}
+
+	incidentSvc := services.NewIncidentService(incidentRepo, monitorRepo, agentRepo, &mocks.MockHeartbeatRepository{}, &mocks.MockAlertChannelRepository{}, notifier, &mocks.MockNotifierFactory{}, transactor, slog.Default())
+	svc := services.NewMonitorService(monitorRepo, &mocks.MockHeartbeatRepository{}, incidentRepo, incidentSvc, &mocks.MockUserRepository{}, &mocks.MockUsageEventRepository{}, slog.Default())
+	require.NoError(t, svc.MarkAgentMonitorsDown(context.Background(), agentID))
+
+	assert.Equal(t, domain.MonitorStatusDown, monitor.Status, "a degraded monitor goes down with its agent")
+	require.NotNil(t, active)
+	assert.Equal(t, domain.IncidentKindDown, active.Kind)
+	assert.Equal(t, 1, transactions, "the degraded incident is superseded in one transaction")
+	assert.Equal(t, 1, affected)
+}
@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// NotificationThrottle wraps a NotifierFactory and the global notifier so
+// channels with a notification policy have their events rate limited,
+// deduplicated and, for low-severity incidents, batched into digests. Every
+// throttled event is recorded in the notification log, and the number
+// suppressed is reported in the channel's next message.
+type NotificationThrottle struct {
+	logRepo     ports.NotificationLogRepository
+	channelRepo ports.AlertChannelRepository
+	factory     ports.NotifierFactory
+	global      ports.Notifier
+	logger      *slog.Logger
+
+	// locks serialises the throttle decisions of each channel so concurrent
+	// events cannot both pass a dedup or rate limit check.
+	locks sync.Map // channel key -> *sync.Mutex
+}
+
+// NewNotificationThrottle creates a new NotificationThrottle. The global
+// notifier is throttled by globalPolicy.
+func NewNotificationThrottle(
+	logRepo ports.NotificationLogRepository,
+	channelRepo ports.AlertChannelRepository,
+	factory ports.NotifierFactory,
+	global ports.Notifier,
+	globalPolicy domain.NotificationPolicy,
+	logger *slog.Logger,
+) *NotificationThrottle {
+	t := &NotificationThrottle{
+		logRepo:     logRepo,
+		channelRepo: channelRepo,
+		factory:     factory,
+		logger:      logger,
+	}
+	t.global = global
+	if !globalPolicy.IsZero() {
+		t.global = &throttledNotifier{throttle: t, next: global, channelName: "global", policy: globalPolicy}
+	}
+	return t
+}
+
+// Global returns the throttled global notifier.
+func (t *NotificationThrottle) Global() ports.Notifier {
+	return t.global
+}
+
+// BuildFromChannel creates a Notifier for the channel, throttled by the
+// channel's notification policy.
+func (t *NotificationThrottle) BuildFromChannel(channel *domain.AlertChannel) (ports.Notifier, error) {
+	notifier, err := t.factory.BuildFromChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := channel.NotificationPolicy()
+	if err != nil {
+		t.logger.Warn("notification throttle: ignoring invalid channel policy",
+			slog.String("channel_id", channel.ID.String()),
+			slog.String("error", err.Error()),
+		)
+		return notifier, nil
+	}
+	if policy.IsZero() {
+		return notifier, nil
+	}
+
+	return &throttledNotifier{
+		throttle:    t,
+		next:        notifier,
+		userID:      &channel.UserID,
+		channelID:   &channel.ID,
+		channelName: channel.Name,
+		policy:      policy,
+	}, nil
+}
+
+// FlushDigests sends every digest due by now and returns how many were sent.
+// Digests that fail to send stay pending and are retried on the next call.
+func (t *NotificationThrottle) FlushDigests(ctx context.Context, now time.Time) (int, error) {
+	entries, err := t.logRepo.GetDueDigests(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("notificationThrottle.FlushDigests: %w", err)
+	}
+
+	var order []string
+	byChannel := make(map[string][]*domain.NotificationLogEntry)
+	for _, e := range entries {
+		key := channelKey(e.ChannelID)
+		if _, ok := byChannel[key]; !ok {
+			order = append(order, key)
+		}
+		byChannel[key] = append(byChannel[key], e)
+	}
+
+	sent := 0
+	for _, key := range order {
+		if t.flushDigest(ctx, byChannel[key], now) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// flushDigest sends one channel's pending entries as a digest.
+func (t *NotificationThrottle) flushDigest(ctx context.Context, entries []*domain.NotificationLogEntry, now time.Time) bool {
+	first := entries[0]
+	ids := make([]uuid.UUID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+
+	notifier, channelName, err := t.digestNotifier(ctx, first.ChannelID, now)
+	if errors.Is(err, errDigestHeld) {
+		return false
+	}
+	if err != nil {
+		t.logger.Error("notification throttle: failed to build digest notifier",
+			slog.String("channel", channelKey(first.ChannelID)),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	if notifier == nil {
+		// The channel was deleted or disabled since; drop its digest.
+		if err := t.logRepo.MarkDigested(ctx, ids); err != nil {
+			t.logger.Error("notification throttle: failed to drop digest",
+				slog.String("channel", channelKey(first.ChannelID)),
+				slog.String("error", err.Error()),
+			)
+		}
+		return false
+	}
+
+	unlock := t.lock(first.ChannelID)
+	defer unlock()
+
+	checkedAt := time.Now()
+	digest := &domain.NotificationDigest{
+		ChannelName: channelName,
+		Entries:     entries,
+		Suppressed:  t.unreported(ctx, first.ChannelID),
+	}
+	sendErr := notifier.NotifyDigest(ctx, digest)
+
+	entry := domain.NewNotificationLogEntry(first.UserID, first.ChannelID, channelName,
+		domain.NotificationEventDigest, fmt.Sprintf("%d events", len(entries)))
+	t.record(ctx, entry, sendErr)
+	if sendErr != nil {
+		t.logger.Error("notification throttle: failed to send digest",
+			slog.String("channel", channelKey(first.ChannelID)),
+			slog.String("error", sendErr.Error()),
+		)
+		return false
+	}
+
+	if err := t.logRepo.MarkDigested(ctx, ids); err != nil {
+		t.logger.Error("notification throttle: failed to mark digest sent",
+			slog.String("channel", channelKey(first.ChannelID)),
+			slog.String("error", err.Error()),
+		)
+	}
+	t.markReported(ctx, first.ChannelID, digest.Suppressed, checkedAt)
+	return true
+}
+
+// errDigestHeld is returned by digestNotifier while the channel's delivery
+// schedule is closed; the digest stays pending until it opens.
+var errDigestHeld = errors.New("digest held until delivery window opens")
+
+// digestNotifier returns the unthrottled notifier a digest is sent through,
+// or nil when its channel no longer exists or is disabled.
+func (t *NotificationThrottle) digestNotifier(ctx context.Context, channelID *uuid.UUID, now time.Time) (ports.Notifier, string, error) {
+	if channelID == nil {
+		if tn, ok := t.global.(*throttledNotifier); ok {
+			return tn.next, tn.channelName, nil
+		}
+		return t.global, "global", nil
+	}
+
+	channel, err := t.channelRepo.GetByID(ctx, *channelID)
+	if err != nil {
+		return nil, "", err
+	}
+	if channel == nil || !channel.Enabled {
+		return nil, "", nil
+	}
+	if channel.DeliverySchedule != nil && !channel.DeliverySchedule.IsOpen(now) {
+		return nil, "", errDigestHeld
+	}
+	notifier, err := t.factory.BuildFromChannel(channel)
+	if err != nil {
+		return nil, "", err
+	}
+	return notifier, channel.Name, nil
+}
+
+// Prune deletes notification log entries older than before.
+func (t *NotificationThrottle) Prune(ctx context.Context, before time.Time) (int64, error) {
+	n, err := t.logRepo.DeleteBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("notificationThrottle.Prune: %w", err)
+	}
+	return n, nil
+}
+
+// lock takes the channel's throttle lock and returns its release.
+func (t *NotificationThrottle) lock(channelID *uuid.UUID) func() {
+	mu, _ := t.locks.LoadOrStore(channelKey(channelID), &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// unreported counts the channel's suppressed notifications not yet reported.
+func (t *NotificationThrottle) unreported(ctx context.Context, channelID *uuid.UUID) int {
+	n, err := t.logRepo.CountUnreported(ctx, channelID)
+	if err != nil {
+		t.logger.Warn("notification throttle: failed to count suppressed notifications",
+			slog.String("channel", channelKey(channelID)),
+			slog.String("error", err.Error()),
+		)
+		return 0
+	}
+	return n
+}
+
+// markReported marks the suppressed notifications a message counted.
+func (t *NotificationThrottle) markReported(ctx context.Context, channelID *uuid.UUID, suppressed int, before time.Time) {
+	if suppressed == 0 {
+		return
+	}
+	if err := t.logRepo.MarkReported(ctx, channelID, before); err != nil {
+		t.logger.Warn("notification throttle: failed to mark suppressed notifications reported",
+			slog.String("channel", channelKey(channelID)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// record writes a log entry with the status a send produced. A failure to
+// write the log is logged and does not fail the notification.
+func (t *NotificationThrottle) record(ctx context.Context, entry *domain.NotificationLogEntry, sendErr error) {
+	if entry.Status == "" {
+		entry.Status = domain.NotificationStatusSent
+		if sendErr != nil {
+			entry.Status = domain.NotificationStatusFailed
+			entry.Error = sendErr.Error()
+		}
+	}
+	if err := t.logRepo.Create(ctx, entry); err != nil {
+		t.logger.Warn("notification throttle: failed to write notification log",
+			slog.String("channel", channelKey(entry.ChannelID)),
+			slog.String("status", string(entry.Status)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func channelKey(channelID *uuid.UUID) string {
+	if channelID == nil {
+		return "global"
+	}
+	return channelID.String()
+}
+
+// throttledNotifier applies a notification policy to the notifier it wraps.
+type throttledNotifier struct {
+	throttle    *NotificationThrottle
+	next        ports.Notifier
+	userID      *uuid.UUID
+	channelID   *uuid.UUID
+	channelName string
+	policy      domain.NotificationPolicy
+}
+
+func (n *throttledNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	return n.notifyIncident(ctx, domain.AlertEventIncidentOpened, incident, monitor, n.next.NotifyIncidentOpened)
+}
+
+func (n *throttledNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	return n.notifyIncident(ctx, domain.AlertEventIncidentResolved, incident, monitor, n.next.NotifyIncidentResolved)
+}
+
+func (n *throttledNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	return n.notifyAgent(ctx, domain.AlertEventAgentOffline, agent, func(ctx context.Context) error {
+		return n.next.NotifyAgentOffline(ctx, agent, affectedMonitors)
+	})
+}
+
+func (n *throttledNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	return n.notifyAgent(ctx, domain.AlertEventAgentOnline, agent, func(ctx context.Context) error {
+		return n.next.NotifyAgentOnline(ctx, agent, resolvedIncidents)
+	})
+}
+
+// NotifyAgentMaintenance is not throttled: it is sent once per window.
+func (n *throttledNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	return n.next.NotifyAgentMaintenance(ctx, agent, windowName)
+}
+
+func (n *throttledNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	return n.next.NotifyDigest(ctx, digest)
+}
+
+func (n *throttledNotifier) notifyIncident(
+	ctx context.Context,
+	kind domain.AlertEventKind,
+	incident *domain.Incident,
+	monitor *domain.Monitor,
+	send func(context.Context, *domain.Incident, *domain.Monitor) error,
+) error {
+	severity := incident.Severity
+	if ac := incident.AlertContext; ac != nil && ac.Severity.IsValid() {
+		severity = ac.Severity
+	}
+	subjectID := incident.MonitorID
+	if incident.IsDeclared() {
+		subjectID = incident.ID
+	}
+
+	entry := domain.NewNotificationLogEntry(n.userID, n.channelID, n.channelName, string(kind), monitor.Name)
+	entry.IncidentID = &incident.ID
+	entry.Severity = severity
+	entry.DedupKey = fmt.Sprintf("%s:%s", kind, subjectID)
+
+	unlock := n.throttle.lock(n.channelID)
+	defer unlock()
+
+	if n.deduplicated(ctx, entry) {
+		return nil
+	}
+	if n.policy.DigestInterval > 0 && severity.IsLow() {
+		n.queueForDigest(ctx, entry)
+		return nil
+	}
+	if n.rateLimited(ctx, entry) {
+		return nil
+	}
+
+	checkedAt := time.Now()
+	suppressed := n.throttle.unreported(ctx, n.channelID)
+	if suppressed > 0 {
+		withCount := *incident
+		ac := domain.AlertContext{}
+		if incident.AlertContext != nil {
+			ac = *incident.AlertContext
+		}
+		ac.Suppressed = suppressed
+		withCount.AlertContext = &ac
+		incident = &withCount
+	}
+
+	err := send(ctx, incident, monitor)
+	n.throttle.record(ctx, entry, err)
+	if err == nil {
+		n.throttle.markReported(ctx, n.channelID, suppressed, checkedAt)
+	}
+	return err
+}
+
+// notifyAgent throttles an agent event. Agent messages carry no suppressed
+// count, so one is reported in the channel's next incident message instead.
+func (n *throttledNotifier) notifyAgent(ctx context.Context, kind domain.AlertEventKind, agent *domain.Agent, send func(context.Context) error) error {
+	entry := domain.NewNotificationLogEntry(n.userID, n.channelID, n.channelName, string(kind), agent.Name)
+	entry.DedupKey = fmt.Sprintf("%s:%s", kind, agent.ID)
+
+	unlock := n.throttle.lock(n.channelID)
+	defer unlock()
+
+	if n.deduplicated(ctx, entry) || n.rateLimited(ctx, entry) {
+		return nil
+	}
+
+	err := send(ctx)
+	n.throttle.record(ctx, entry, err)
+	return err
+}
+
+// deduplicated records and returns true if an identical event went through
+// the channel within the dedup window. A failed check lets the event through.
+func (n *throttledNotifier) deduplicated(ctx context.Context, entry *domain.NotificationLogEntry) bool {
+	if n.policy.DedupWindow == 0 {
+		return false
+	}
+	recent, err := n.throttle.logRepo.HasRecent(ctx, n.channelID, entry.DedupKey, entry.CreatedAt.Add(-n.policy.DedupWindow))
+	if err != nil {
+		n.throttle.logger.Warn("notification throttle: dedup check failed",
+			slog.String("channel", channelKey(n.channelID)),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	if !recent {
+		return false
+	}
+	entry.Status = domain.NotificationStatusDeduplicated
+	n.throttle.record(ctx, entry, nil)
+	return true
+}
+
+// rateLimited records and returns true if the channel has sent its hourly
+// limit of messages. A failed check lets the event through.
+func (n *throttledNotifier) rateLimited(ctx context.Context, entry *domain.NotificationLogEntry) bool {
+	if n.policy.RateLimit == 0 {
+		return false
+	}
+	sent, err := n.throttle.logRepo.CountSent(ctx, n.channelID, entry.CreatedAt.Add(-time.Hour))
+	if err != nil {
+		n.throttle.logger.Warn("notification throttle: rate limit check failed",
+			slog.String("channel", channelKey(n.channelID)),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	if sent < n.policy.RateLimit {
+		return false
+	}
+	entry.Status = domain.NotificationStatusRateLimited
+	n.throttle.record(ctx, entry, nil)
+	return true
+}
+
+// queueForDigest records the entry for the channel's next digest, which is
+// due one digest interval after the first event it holds.
+func (n *throttledNotifier) queueForDigest(ctx context.Context, entry *domain.NotificationLogEntry) {
+	due, err := n.throttle.logRepo.GetDigestDueAt(ctx, n.channelID)
+	if err != nil {
+		n.throttle.logger.Warn("notification throttle: failed to look up pending digest",
+			slog.String("channel", channelKey(n.channelID)),
+			slog.String("error", err.Error()),
+		)
+	}
+	if due == nil {
+		next := entry.CreatedAt.Add(n.policy.DigestInterval)
+		due = &next
+	}
+	entry.Status = domain.NotificationStatusDigestPending
+	entry.DigestDueAt = due
+	n.throttle.record(ctx, entry, nil)
+}
@@ -0,0 +1,215 @@
+package services_test
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+// memNotificationLog is a notification log held in memory.
+type memNotificationLog struct {
+	entries []*domain.NotificationLogEntry
+}
+
+func (l *memNotificationLog) repo() *mocks.MockNotificationLogRepository {
+	sameChannel := func(e *domain.NotificationLogEntry, channelID *uuid.UUID) bool {
+		if e.ChannelID == nil || channelID == nil {
+			return e.ChannelID == channelID
+		}
+		return *e.ChannelID == *channelID
+	}
+	return &mocks.MockNotificationLogRepository{
+		CreateFn: func(_ context.Context, entry *domain.NotificationLogEntry) error {
+			l.entries = append(l.entries, entry)
+			return nil
+		},
+		CountSentFn: func(_ context.Context, channelID *uuid.UUID, since time.Time) (int, error) {
+			n := 0
+			for _, e := range l.entries {
+				if sameChannel(e, channelID) && e.Status == domain.NotificationStatusSent && !e.CreatedAt.Before(since) {
+					n++
+				}
+			}
+			return n, nil
+		},
+		HasRecentFn: func(_ context.Context, channelID *uuid.UUID, dedupKey string, since time.Time) (bool, error) {
+			for _, e := range l.entries {
+				if sameChannel(e, channelID) && e.DedupKey == dedupKey && !e.Status.IsSuppressed() &&
+					e.Status != domain.NotificationStatusFailed && !e.CreatedAt.Before(since) {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		CountUnreportedFn: func(_ context.Context, channelID *uuid.UUID) (int, error) {
+			n := 0
+			for _, e := range l.entries {
+				if sameChannel(e, channelID) && e.Status.IsSuppressed() && !e.Reported {
+					n++
+				}
+			}
+			return n, nil
+		},
+		MarkReportedFn: func(_ context.Context, channelID *uuid.UUID, before time.Time) error {
+			for _, e := range l.entries {
+				if sameChannel(e, channelID) && e.Status.IsSuppressed() && !e.CreatedAt.After(before) {
+					e.Reported = true
+				}
+			}
+			return nil
+		},
+		GetDigestDueAtFn: func(_ context.Context, channelID *uuid.UUID) (*time.Time, error) {
+			for _, e := range l.entries {
+				if sameChannel(e, channelID) && e.Status == domain.NotificationStatusDigestPending {
+					return e.DigestDueAt, nil
+				}
+			}
+			return nil, nil
+		},
+		GetDueDigestsFn: func(_ context.Context, now time.Time) ([]*domain.NotificationLogEntry, error) {
+			var due []*domain.NotificationLogEntry
+			for _, e := range l.entries {
+				if e.Status == domain.NotificationStatusDigestPending && !e.DigestDueAt.After(now) {
+					due = append(due, e)
+				}
+			}
+			return due, nil
+		},
+		MarkDigestedFn: func(_ context.Context, ids []uuid.UUID) error {
+			for _, e := range l.entries {
+				if slices.Contains(ids, e.ID) {
+					e.Status = domain.NotificationStatusDigested
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func (l *memNotificationLog) count(status domain.NotificationStatus) int {
+	n := 0
+	for _, e := range l.entries {
+		if e.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func throttledSlack(config map[string]string) *domain.AlertChannel {
+	config["webhook_url"] = "https://hooks.slack.test/x"
+	return &domain.AlertChannel{ID: uuid.New(), UserID: uuid.New(), Type: domain.AlertChannelSlack, Name: "ops", Config: config, Enabled: true}
+}
+
+func newThrottleFixture(t *testing.T, log *memNotificationLog, channel *domain.AlertChannel, inner *mocks.MockNotifier) (*services.NotificationThrottle, ports.Notifier) {
+	t.Helper()
+	factory := &mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(_ *domain.AlertChannel) (ports.Notifier, error) {
+			return inner, nil
+		},
+	}
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.AlertChannel, error) {
+			return channel, nil
+		},
+	}
+	throttle := services.NewNotificationThrottle(log.repo(), channelRepo, factory, &mocks.MockNotifier{}, domain.NotificationPolicy{}, slog.Default())
+	notifier, err := throttle.BuildFromChannel(channel)
+	require.NoError(t, err)
+	return throttle, notifier
+}
+
+func TestNotificationThrottle_DedupAndRateLimit(t *testing.T) {
+	channel := throttledSlack(map[string]string{
+		domain.RateLimitConfigKey:   "2",
+		domain.DedupWindowConfigKey: "10",
+	})
+	var suppressed []int
+	inner := &mocks.MockNotifier{
+		NotifyIncidentOpenedFn: func(_ context.Context, incident *domain.Incident, _ *domain.Monitor) error {
+			count := 0
+			if incident.AlertContext != nil {
+				count = incident.AlertContext.Suppressed
+			}
+			suppressed = append(suppressed, count)
+			return nil
+		},
+	}
+	log := &memNotificationLog{}
+	_, notifier := newThrottleFixture(t, log, channel, inner)
+	ctx := context.Background()
+
+	flapping := &domain.Monitor{ID: uuid.New(), Name: "api"}
+	for range 3 {
+		require.NoError(t, notifier.NotifyIncidentOpened(ctx, domain.NewIncident(flapping.ID), flapping))
+	}
+	assert.Equal(t, []int{0}, suppressed, "identical events within the window are dropped")
+	assert.Equal(t, 2, log.count(domain.NotificationStatusDeduplicated))
+
+	other := &domain.Monitor{ID: uuid.New(), Name: "db"}
+	require.NoError(t, notifier.NotifyIncidentOpened(ctx, domain.NewIncident(other.ID), other))
+	assert.Equal(t, []int{0, 2}, suppressed, "next message reports the suppressed count")
+
+	third := &domain.Monitor{ID: uuid.New(), Name: "cache"}
+	require.NoError(t, notifier.NotifyIncidentOpened(ctx, domain.NewIncident(third.ID), third))
+	assert.Len(t, suppressed, 2, "hourly limit reached")
+	assert.Equal(t, 1, log.count(domain.NotificationStatusRateLimited))
+	assert.Equal(t, 2, log.count(domain.NotificationStatusSent))
+}
+
+func TestNotificationThrottle_Digest(t *testing.T) {
+	channel := throttledSlack(map[string]string{domain.DigestIntervalConfigKey: "5"})
+	var opened int
+	var digests []*domain.NotificationDigest
+	inner := &mocks.MockNotifier{
+		NotifyIncidentOpenedFn: func(_ context.Context, _ *domain.Incident, _ *domain.Monitor) error {
+			opened++
+			return nil
+		},
+		NotifyDigestFn: func(_ context.Context, digest *domain.NotificationDigest) error {
+			digests = append(digests, digest)
+			return nil
+		},
+	}
+	log := &memNotificationLog{}
+	throttle, notifier := newThrottleFixture(t, log, channel, inner)
+	ctx := context.Background()
+
+	monitor := &domain.Monitor{ID: uuid.New(), Name: "blog"}
+	for _, severity := range []domain.IncidentSeverity{domain.IncidentSeverityMinor, domain.IncidentSeverityInfo, domain.IncidentSeverityCritical} {
+		incident := domain.NewIncident(monitor.ID)
+		incident.Severity = severity
+		require.NoError(t, notifier.NotifyIncidentOpened(ctx, incident, monitor))
+	}
+	assert.Equal(t, 1, opened, "only the critical incident is sent right away")
+	assert.Equal(t, 2, log.count(domain.NotificationStatusDigestPending))
+
+	sent, err := throttle.FlushDigests(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent, "digest not due yet")
+
+	sent, err = throttle.FlushDigests(ctx, time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, digests, 1)
+	assert.Len(t, digests[0].Entries, 2)
+	assert.Equal(t, "ops", digests[0].ChannelName)
+	assert.Equal(t, 2, log.count(domain.NotificationStatusDigested))
+}
+
+func TestNotificationThrottle_ZeroPolicyIsUnwrapped(t *testing.T) {
+	inner := &mocks.MockNotifier{}
+	_, notifier := newThrottleFixture(t, &memNotificationLog{}, throttledSlack(map[string]string{}), inner)
+	assert.Same(t, inner, notifier)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// OnCallNotifierFactory wraps a NotifierFactory so channels that page an
+// on-call schedule send to whoever is on call at the time of each
+// notification. Other channels are built by the wrapped factory as is.
+type OnCallNotifierFactory struct {
+	factory ports.NotifierFactory
+	onCall  ports.OnCallService
+}
+
+// NewOnCallNotifierFactory creates a new OnCallNotifierFactory.
+func NewOnCallNotifierFactory(factory ports.NotifierFactory, onCall ports.OnCallService) *OnCallNotifierFactory {
+	return &OnCallNotifierFactory{factory: factory, onCall: onCall}
+}
+
+// BuildFromChannel creates a Notifier for the channel.
+func (f *OnCallNotifierFactory) BuildFromChannel(channel *domain.AlertChannel) (ports.Notifier, error) {
+	if !channel.IsOnCall() {
+		return f.factory.BuildFromChannel(channel)
+	}
+	return &onCallNotifier{factory: f.factory, onCall: f.onCall, channel: channel}, nil
+}
+
+// onCallNotifier resolves its channel's recipient when it sends, since
+// only then is the context needed to look up the schedule available.
+type onCallNotifier struct {
+	factory ports.NotifierFactory
+	onCall  ports.OnCallService
+	channel *domain.AlertChannel
+}
+
+func (n *onCallNotifier) notifier(ctx context.Context) (ports.Notifier, error) {
+	resolved, err := n.onCall.ResolveChannel(ctx, n.channel)
+	if err != nil {
+		return nil, err
+	}
+	return n.factory.BuildFromChannel(resolved)
+}
+
+func (n *onCallNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	notifier, err := n.notifier(ctx)
+	if err != nil {
+		return err
+	}
+	return notifier.NotifyIncidentOpened(ctx, incident, monitor)
+}
+
+func (n *onCallNotifier) NotifyIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
+	notifier, err := n.notifier(ctx)
+	if err != nil {
+		return err
+	}
+	return notifier.NotifyIncidentResolved(ctx, incident, monitor)
+}
+
+func (n *onCallNotifier) NotifyAgentOffline(ctx context.Context, agent *domain.Agent, affectedMonitors int) error {
+	notifier, err := n.notifier(ctx)
+	if err != nil {
+		return err
+	}
+	return notifier.NotifyAgentOffline(ctx, agent, affectedMonitors)
+}
+
+func (n *onCallNotifier) NotifyAgentOnline(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error {
+	notifier, err := n.notifier(ctx)
+	if err != nil {
+		return err
+	}
+	return notifier.NotifyAgentOnline(ctx, agent, resolvedIncidents)
+}
+
+func (n *onCallNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain.Agent, windowName string) error {
+	notifier, err := n.notifier(ctx)
+	if err != nil {
+		return err
+	}
+	return notifier.NotifyAgentMaintenance(ctx, agent, windowName)
+}
+
+func (n *onCallNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	notifier, err := n.notifier(ctx)
+	if err != nil {
+		return err
+	}
+	return notifier.NotifyDigest(ctx, digest)
+}
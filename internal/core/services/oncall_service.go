@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// OnCallService manages on-call schedules and resolves who is on call,
+// both for the API and for channels and escalation levels that page the
+// current on-call user.
+type OnCallService struct {
+	onCallRepo       ports.OnCallRepository
+	userRepo         ports.UserRepository
+	alertChannelRepo ports.AlertChannelRepository
+	logger           *slog.Logger
+}
+
+// NewOnCallService creates a new OnCallService.
+func NewOnCallService(
+	onCallRepo ports.OnCallRepository,
+	userRepo ports.UserRepository,
+	alertChannelRepo ports.AlertChannelRepository,
+	logger *slog.Logger,
+) *OnCallService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &OnCallService{
+		onCallRepo:       onCallRepo,
+		userRepo:         userRepo,
+		alertChannelRepo: alertChannelRepo,
+		logger:           logger,
+	}
+}
+
+// ListSchedules returns a user's on-call schedules.
+func (s *OnCallService) ListSchedules(ctx context.Context, userID uuid.UUID) ([]*domain.OnCallSchedule, error) {
+	schedules, err := s.onCallRepo.GetSchedulesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.ListSchedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// GetSchedule returns an on-call schedule, or nil when it does not exist.
+func (s *OnCallService) GetSchedule(ctx context.Context, id uuid.UUID) (*domain.OnCallSchedule, error) {
+	schedule, err := s.onCallRepo.GetScheduleByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.GetSchedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// CreateSchedule validates and stores a new on-call schedule.
+func (s *OnCallService) CreateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	if err := s.validate(ctx, schedule); err != nil {
+		return fmt.Errorf("onCallService.CreateSchedule: %w", err)
+	}
+	if err := s.onCallRepo.CreateSchedule(ctx, schedule); err != nil {
+		return fmt.Errorf("onCallService.CreateSchedule: %w", err)
+	}
+	return nil
+}
+
+// UpdateSchedule validates and stores an edited on-call schedule.
+func (s *OnCallService) UpdateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	if err := s.validate(ctx, schedule); err != nil {
+		return fmt.Errorf("onCallService.UpdateSchedule: %w", err)
+	}
+	schedule.UpdatedAt = time.Now()
+	if err := s.onCallRepo.UpdateSchedule(ctx, schedule); err != nil {
+		return fmt.Errorf("onCallService.UpdateSchedule: %w", err)
+	}
+	return nil
+}
+
+// DeleteSchedule removes an on-call schedule and its overrides.
+func (s *OnCallService) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	if err := s.onCallRepo.DeleteSchedule(ctx, id); err != nil {
+		return fmt.Errorf("onCallService.DeleteSchedule: %w", err)
+	}
+	return nil
+}
+
+// ListOverrides returns a schedule's overrides that overlap [from, to).
+func (s *OnCallService) ListOverrides(ctx context.Context, scheduleID uuid.UUID, from, to time.Time) ([]*domain.OnCallOverride, error) {
+	overrides, err := s.onCallRepo.GetOverrides(ctx, scheduleID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.ListOverrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// GetOverride returns an override, or nil when it does not exist.
+func (s *OnCallService) GetOverride(ctx context.Context, id uuid.UUID) (*domain.OnCallOverride, error) {
+	override, err := s.onCallRepo.GetOverrideByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.GetOverride: %w", err)
+	}
+	return override, nil
+}
+
+// CreateOverride stores an override after checking its user exists.
+func (s *OnCallService) CreateOverride(ctx context.Context, override *domain.OnCallOverride) error {
+	user, err := s.userRepo.GetByID(ctx, override.UserID)
+	if err != nil {
+		return fmt.Errorf("onCallService.CreateOverride: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("onCallService.CreateOverride: %w: user %s not found", domain.ErrInvalidOnCallOverride, override.UserID)
+	}
+	if err := s.onCallRepo.CreateOverride(ctx, override); err != nil {
+		return fmt.Errorf("onCallService.CreateOverride: %w", err)
+	}
+	return nil
+}
+
+// DeleteOverride removes an override.
+func (s *OnCallService) DeleteOverride(ctx context.Context, id uuid.UUID) error {
+	if err := s.onCallRepo.DeleteOverride(ctx, id); err != nil {
+		return fmt.Errorf("onCallService.DeleteOverride: %w", err)
+	}
+	return nil
+}
+
+// WhoIsOnCall returns the shift covering at for a schedule, or nil when the
+// schedule does not exist or nobody is on call.
+func (s *OnCallService) WhoIsOnCall(ctx context.Context, scheduleID uuid.UUID, at time.Time) (*domain.OnCallShift, error) {
+	schedule, err := s.onCallRepo.GetScheduleByID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.WhoIsOnCall: %w", err)
+	}
+	if schedule == nil {
+		return nil, nil
+	}
+	// Overrides are stored with microsecond precision.
+	overrides, err := s.onCallRepo.GetOverrides(ctx, scheduleID, at, at.Add(time.Microsecond))
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.WhoIsOnCall: %w", err)
+	}
+	shift, ok := schedule.ShiftAt(at, overrides)
+	if !ok {
+		return nil, nil
+	}
+	return &shift, nil
+}
+
+// Shifts returns who is on call for a schedule between from and to.
+func (s *OnCallService) Shifts(ctx context.Context, schedule *domain.OnCallSchedule, from, to time.Time) ([]domain.OnCallShift, error) {
+	overrides, err := s.onCallRepo.GetOverrides(ctx, schedule.ID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.Shifts: %w", err)
+	}
+	return schedule.Shifts(from, to, overrides), nil
+}
+
+// ResolveChannel returns the channel to send through right now. Channels
+// that page a schedule get the on-call user's email address (email) or the
+// chat of their own Telegram channel (Telegram); other channels are
+// returned unchanged.
+func (s *OnCallService) ResolveChannel(ctx context.Context, channel *domain.AlertChannel) (*domain.AlertChannel, error) {
+	if !channel.IsOnCall() {
+		return channel, nil
+	}
+	scheduleID, ok := channel.OnCallScheduleID()
+	if !ok {
+		return nil, fmt.Errorf("onCallService.ResolveChannel: invalid %s", domain.OnCallScheduleConfigKey)
+	}
+	schedule, err := s.onCallRepo.GetScheduleByID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.ResolveChannel: %w", err)
+	}
+	if schedule == nil || schedule.UserID != channel.UserID {
+		return nil, fmt.Errorf("onCallService.ResolveChannel: schedule %s not found", scheduleID)
+	}
+	shift, err := s.WhoIsOnCall(ctx, scheduleID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("onCallService.ResolveChannel: %w", err)
+	}
+	if shift == nil {
+		return nil, fmt.Errorf("onCallService.ResolveChannel: schedule %q: %w", schedule.Name, domain.ErrNobodyOnCall)
+	}
+
+	resolved := *channel
+	resolved.Config = maps.Clone(channel.Config)
+	switch channel.Type {
+	case domain.AlertChannelEmail:
+		user, err := s.userRepo.GetByID(ctx, shift.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("onCallService.ResolveChannel: %w", err)
+		}
+		if user == nil {
+			return nil, fmt.Errorf("onCallService.ResolveChannel: on-call user %s not found", shift.UserID)
+		}
+		resolved.Config["to"] = user.Email
+
+	case domain.AlertChannelTelegram:
+		chatID, err := s.telegramChat(ctx, shift.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("onCallService.ResolveChannel: %w", err)
+		}
+		resolved.Config["chat_id"] = chatID
+
+	default:
+		return nil, fmt.Errorf("onCallService.ResolveChannel: %s channels cannot page a schedule", channel.Type)
+	}
+	return &resolved, nil
+}
+
+// telegramChat returns the chat of a user's own enabled Telegram channel.
+func (s *OnCallService) telegramChat(ctx context.Context, userID uuid.UUID) (string, error) {
+	channels, err := s.alertChannelRepo.GetEnabledByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	for _, ch := range channels {
+		if ch.Type == domain.AlertChannelTelegram && !ch.IsOnCall() && ch.Config["chat_id"] != "" {
+			return ch.Config["chat_id"], nil
+		}
+	}
+	return "", fmt.Errorf("on-call user %s has no enabled telegram channel", userID)
+}
+
+// validate checks the schedule and that every participant exists.
+func (s *OnCallService) validate(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, layer := range schedule.Layers {
+		for _, id := range layer.Participants {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			user, err := s.userRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return fmt.Errorf("%w: user %s not found", domain.ErrInvalidOnCallSchedule, id)
+			}
+		}
+	}
+	return nil
+}
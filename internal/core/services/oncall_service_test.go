@@ -0,0 +1,173 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+// alwaysOnCall returns a schedule owned by ownerID on which userID is
+// always on call.
+func alwaysOnCall(ownerID, userID uuid.UUID) *domain.OnCallSchedule {
+	return &domain.OnCallSchedule{
+		ID:       uuid.New(),
+		UserID:   ownerID,
+		Name:     "primary",
+		Timezone: "UTC",
+		Layers: []domain.OnCallLayer{{
+			Rotation: domain.OnCallRotationWeekly, StartDate: "2020-01-06", HandoffTime: "09:00",
+			Participants: []uuid.UUID{userID},
+		}},
+	}
+}
+
+func newTestOnCallService(schedule *domain.OnCallSchedule, overrides []*domain.OnCallOverride, channelRepo *mocks.MockAlertChannelRepository) *services.OnCallService {
+	repo := &mocks.MockOnCallRepository{
+		GetScheduleByIDFn: func(_ context.Context, id uuid.UUID) (*domain.OnCallSchedule, error) {
+			if schedule != nil && id == schedule.ID {
+				return schedule, nil
+			}
+			return nil, nil
+		},
+		GetOverridesFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) ([]*domain.OnCallOverride, error) {
+			return overrides, nil
+		},
+	}
+	userRepo := &mocks.MockUserRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.User, error) {
+			return &domain.User{ID: id, Email: id.String()[:8] + "@example.com"}, nil
+		},
+	}
+	return services.NewOnCallService(repo, userRepo, channelRepo, slog.Default())
+}
+
+func TestOnCallService_WhoIsOnCall_Override(t *testing.T) {
+	ownerID, alice, bob := uuid.New(), uuid.New(), uuid.New()
+	schedule := alwaysOnCall(ownerID, alice)
+	override, err := domain.NewOnCallOverride(schedule.ID, bob, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	svc := newTestOnCallService(schedule, []*domain.OnCallOverride{override}, &mocks.MockAlertChannelRepository{})
+	shift, err := svc.WhoIsOnCall(context.Background(), schedule.ID, time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, shift)
+	assert.Equal(t, bob, shift.UserID)
+
+	shift, err = svc.WhoIsOnCall(context.Background(), uuid.New(), time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, shift)
+}
+
+func TestOnCallService_ResolveChannel(t *testing.T) {
+	ownerID, alice := uuid.New(), uuid.New()
+	schedule := alwaysOnCall(ownerID, alice)
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetEnabledByUserIDFn: func(_ context.Context, userID uuid.UUID) ([]*domain.AlertChannel, error) {
+			if userID != alice {
+				return nil, nil
+			}
+			return []*domain.AlertChannel{
+				{Type: domain.AlertChannelSlack, Config: map[string]string{"webhook_url": "https://hooks.slack.com/x"}},
+				{Type: domain.AlertChannelTelegram, Config: map[string]string{"bot_token": "alice-bot", "chat_id": "4242"}},
+			}, nil
+		},
+	}
+	svc := newTestOnCallService(schedule, nil, channelRepo)
+	ctx := context.Background()
+
+	email := domain.NewAlertChannel(ownerID, domain.AlertChannelEmail, "on-call", map[string]string{
+		"host": "smtp.example.com", "from": "alerts@example.com", domain.OnCallScheduleConfigKey: schedule.ID.String(),
+	})
+	resolved, err := svc.ResolveChannel(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, alice.String()[:8]+"@example.com", resolved.Config["to"])
+	assert.Empty(t, email.Config["to"], "the stored channel is left untouched")
+
+	telegram := domain.NewAlertChannel(ownerID, domain.AlertChannelTelegram, "on-call", map[string]string{
+		"bot_token": "team-bot", domain.OnCallScheduleConfigKey: schedule.ID.String(),
+	})
+	resolved, err = svc.ResolveChannel(ctx, telegram)
+	require.NoError(t, err)
+	assert.Equal(t, "4242", resolved.Config["chat_id"])
+	assert.Equal(t, "team-bot", resolved.Config["bot_token"])
+
+	// Another user's schedule cannot be paged.
+	foreign := domain.NewAlertChannel(uuid.New(), domain.AlertChannelEmail, "on-call", email.Config)
+	_, err = svc.ResolveChannel(ctx, foreign)
+	assert.Error(t, err)
+
+	// Channels without a schedule are returned as is.
+	plain := domain.NewAlertChannel(ownerID, domain.AlertChannelSlack, "ops", map[string]string{"webhook_url": "https://hooks.slack.com/y"})
+	resolved, err = svc.ResolveChannel(ctx, plain)
+	require.NoError(t, err)
+	assert.Same(t, plain, resolved)
+}
+
+func TestOnCallService_ResolveChannel_NobodyOnCall(t *testing.T) {
+	ownerID := uuid.New()
+	schedule := alwaysOnCall(ownerID, uuid.New())
+	schedule.Layers[0].StartDate = "2999-01-01"
+	svc := newTestOnCallService(schedule, nil, &mocks.MockAlertChannelRepository{})
+
+	email := domain.NewAlertChannel(ownerID, domain.AlertChannelEmail, "on-call", map[string]string{
+		"host": "smtp.example.com", "from": "alerts@example.com", domain.OnCallScheduleConfigKey: schedule.ID.String(),
+	})
+	_, err := svc.ResolveChannel(context.Background(), email)
+	assert.True(t, errors.Is(err, domain.ErrNobodyOnCall))
+}
+
+func TestOnCallNotifierFactory_SendsToCurrentOnCallUser(t *testing.T) {
+	ownerID, alice := uuid.New(), uuid.New()
+	schedule := alwaysOnCall(ownerID, alice)
+	svc := newTestOnCallService(schedule, nil, &mocks.MockAlertChannelRepository{})
+
+	var built []*domain.AlertChannel
+	factory := services.NewOnCallNotifierFactory(&mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(ch *domain.AlertChannel) (ports.Notifier, error) {
+			built = append(built, ch)
+			return &mocks.MockNotifier{}, nil
+		},
+	}, svc)
+
+	email := domain.NewAlertChannel(ownerID, domain.AlertChannelEmail, "on-call", map[string]string{
+		"host": "smtp.example.com", "from": "alerts@example.com", domain.OnCallScheduleConfigKey: schedule.ID.String(),
+	})
+	notifier, err := factory.BuildFromChannel(email)
+	require.NoError(t, err)
+	assert.Empty(t, built, "on-call channels are resolved when sending")
+
+	require.NoError(t, notifier.NotifyIncidentOpened(context.Background(), domain.NewIncident(uuid.New()), &domain.Monitor{}))
+	require.Len(t, built, 1)
+	assert.Equal(t, alice.String()[:8]+"@example.com", built[0].Config["to"])
+}
+
+func TestOnCallService_CreateSchedule_UnknownParticipant(t *testing.T) {
+	userRepo := &mocks.MockUserRepository{
+		GetByIDFn: func(_ context.Context, _ uuid.UUID) (*domain.User, error) {
+			return nil, nil
+		},
+	}
+	created := false
+	repo := &mocks.MockOnCallRepository{
+		CreateScheduleFn: func(_ context.Context, _ *domain.OnCallSchedule) error {
+			created = true
+			return nil
+		},
+	}
+	svc := services.NewOnCallService(repo, userRepo, &mocks.MockAlertChannelRepository{}, slog.Default())
+
+	err := svc.CreateSchedule(context.Background(), alwaysOnCall(uuid.New(), uuid.New()))
+	assert.True(t, errors.Is(err, domain.ErrInvalidOnCallSchedule))
+	assert.False(t, created)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// PushService ingests pings from push monitors and opens incidents for the
+// ones whose jobs stop pinging. It runs entirely on the hub, so deadlines are
+// enforced even while the owning agent is offline.
+type PushService struct {
+	monitorRepo   ports.MonitorRepository
+	heartbeatRepo ports.HeartbeatRepository
+	monitorSvc    ports.MonitorService
+	incidentSvc   ports.IncidentService
+	transactor    ports.Transactor
+	logger        *slog.Logger
+}
+
+// NewPushService creates a new PushService.
+func NewPushService(
+	monitorRepo ports.MonitorRepository,
+	heartbeatRepo ports.HeartbeatRepository,
+	monitorSvc ports.MonitorService,
+	incidentSvc ports.IncidentService,
+	transactor ports.Transactor,
+	logger *slog.Logger,
+) *PushService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PushService{
+		monitorRepo:   monitorRepo,
+		heartbeatRepo: heartbeatRepo,
+		monitorSvc:    monitorSvc,
+		incidentSvc:   incidentSvc,
+		transactor:    transactor,
+		logger:        logger,
+	}
+}
+
+// RecordPing handles a signal from a push monitor's job. A start signal only
+// opens a run; success and fail complete it and are processed like any other
+// heartbeat. When no duration is supplied, it is measured from the last start
+// signal if one is pending. The log excerpt is kept with failed runs.
+func (s *PushService) RecordPing(ctx context.Context, monitor *domain.Monitor, signal domain.PushSignal, durationMs *int, logExcerpt string) error {
+	now := time.Now()
+
+	if signal == domain.PushSignalStart {
+		if err := s.monitorRepo.RecordPing(ctx, monitor.ID, now, true); err != nil {
+			return fmt.Errorf("pushService.RecordPing: %w", err)
+		}
+		return nil
+	}
+
+	if durationMs == nil && monitor.PushStartedAt != nil &&
+		(monitor.LastPingAt == nil || monitor.PushStartedAt.After(*monitor.LastPingAt)) {
+		ms := int(now.Sub(*monitor.PushStartedAt).Milliseconds())
+		durationMs = &ms
+	}
+
+	var heartbeat *domain.Heartbeat
+	if signal == domain.PushSignalFail {
+		msg := domain.TruncatePushLog(logExcerpt)
+		if msg == "" {
+			msg = "job reported failure"
+		}
+		heartbeat = domain.NewFailureHeartbeat(monitor.ID, monitor.AgentID, domain.HeartbeatStatusError, msg)
+	} else {
+		heartbeat = domain.NewHeartbeat(monitor.ID, monitor.AgentID, domain.HeartbeatStatusUp)
+	}
+	heartbeat.Time = now
+	heartbeat.LatencyMs = durationMs
+
+	if err := s.monitorRepo.RecordPing(ctx, monitor.ID, now, false); err != nil {
+		return fmt.Errorf("pushService.RecordPing: %w", err)
+	}
+	if err := s.monitorSvc.ProcessHeartbeat(ctx, heartbeat); err != nil {
+		return fmt.Errorf("pushService.RecordPing: %w", err)
+	}
+
+	return nil
+}
+
+// CheckOverdue opens an incident for every push monitor in the context's
+// tenant that missed its deadline as of now. A missed deadline is recorded as
+// a timeout heartbeat so it shows up in the monitor's history. Returns the
+// number of monitors marked down.
+func (s *PushService) CheckOverdue(ctx context.Context, now time.Time) (int, error) {
+	var overdue []*domain.Monitor
+	err := s.transactor.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		overdue, err = s.monitorRepo.GetOverduePush(txCtx, now)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pushService.CheckOverdue: %w", err)
+	}
+
+	marked := 0
+	for _, monitor := range overdue {
+		msg := fmt.Sprintf("no ping received by %s", monitor.PushDeadline().UTC().Format(time.RFC3339))
+		heartbeat := domain.NewFailureHeartbeat(monitor.ID, monitor.AgentID, domain.HeartbeatStatusTimeout, msg)
+		heartbeat.Time = now
+		if err := s.heartbeatRepo.Create(ctx, heartbeat); err != nil {
+			s.logger.Error("failed to record missed push deadline",
+				"monitor_id", monitor.ID,
+				"error", err,
+			)
+			continue
+		}
+
+		incident, err := s.incidentSvc.CreateIncidentIfNeeded(ctx, monitor.ID)
+		if err != nil {
+			s.logger.Error("failed to open incident for missed push deadline",
+				"monitor_id", monitor.ID,
+				"error", err,
+			)
+			continue
+		}
+		s.logger.Info("push monitor missed its deadline",
+			"monitor_id", monitor.ID,
+			"incident_id", incident.ID,
+			"deadline", monitor.PushDeadline(),
+		)
+		marked++
+	}
+
+	return marked, nil
+}
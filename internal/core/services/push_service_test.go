@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/internal/core/services"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+)
+
+func newPushMonitor(t *testing.T) *domain.Monitor {
+	t.Helper()
+	monitor := domain.NewMonitor(uuid.New(), "nightly backup", domain.MonitorTypePush, "")
+	monitor.EnablePush("wd_push_test")
+	require.True(t, monitor.SetPushSchedule(3600, 300))
+	return monitor
+}
+
+func TestPushService_RecordPing_StartOnlyMarksRun(t *testing.T) {
+	monitor := newPushMonitor(t)
+
+	var start bool
+	monitorRepo := &mocks.MockMonitorRepository{
+		RecordPingFn: func(_ context.Context, id uuid.UUID, _ time.Time, s bool) error {
+			assert.Equal(t, monitor.ID, id)
+			start = s
+			return nil
+		},
+	}
+	monitorSvc := &mocks.MockMonitorService{
+		ProcessHeartbeatFn: func(_ context.Context, _ *domain.Heartbeat) error {
+			t.Fatal("start signal must not produce a heartbeat")
+			return nil
+		},
+	}
+
+	svc := services.NewPushService(monitorRepo, &mocks.MockHeartbeatRepository{}, monitorSvc, &mocks.MockIncidentService{}, &mocks.MockTransactor{}, slog.Default())
+	require.NoError(t, svc.RecordPing(context.Background(), monitor, domain.PushSignalStart, nil, ""))
+	assert.True(t, start)
+}
+
+func TestPushService_RecordPing_FailProcessesFailureHeartbeat(t *testing.T) {
+	monitor := newPushMonitor(t)
+	started := time.Now().Add(-2 * time.Second)
+	monitor.PushStartedAt = &started
+
+	var pinged bool
+	monitorRepo := &mocks.MockMonitorRepository{
+		RecordPingFn: func(_ context.Context, _ uuid.UUID, _ time.Time, start bool) error {
+			assert.False(t, start)
+			pinged = true
+			return nil
+		},
+	}
+	var processed *domain.Heartbeat
+	monitorSvc := &mocks.MockMonitorService{
+		ProcessHeartbeatFn: func(_ context.Context, hb *domain.Heartbeat) error {
+			processed = hb
+			return nil
+		},
+	}
+
+	svc := services.NewPushService(monitorRepo, &mocks.MockHeartbeatRepository{}, monitorSvc, &mocks.MockIncidentService{}, &mocks.MockTransactor{}, slog.Default())
+	require.NoError(t, svc.RecordPing(context.Background(), monitor, domain.PushSignalFail, nil, "pg_dump: connection refused"))
+
+	assert.True(t, pinged)
+	require.NotNil(t, processed)
+	assert.Equal(t, monitor.ID, processed.MonitorID)
+	assert.False(t, processed.IsSuccess())
+	require.NotNil(t, processed.ErrorMessage)
+	assert.Equal(t, "pg_dump: connection refused", *processed.ErrorMessage)
+	require.NotNil(t, processed.LatencyMs, "duration measured from the start signal")
+	assert.GreaterOrEqual(t, *processed.LatencyMs, 2000)
+}
+
+func TestPushService_CheckOverdue_OpensIncident(t *testing.T) {
+	monitor := newPushMonitor(t)
+	now := time.Now()
+
+	var inTx bool
+	transactor := &mocks.MockTransactor{
+		WithTransactionFn: func(ctx context.Context, fn func(ctx context.Context) error) error {
+			inTx = true
+			return fn(ctx)
+		},
+	}
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetOverduePushFn: func(_ context.Context, at time.Time) ([]*domain.Monitor, error) {
+			assert.Equal(t, now, at)
+			return []*domain.Monitor{monitor}, nil
+		},
+	}
+	var stored *domain.Heartbeat
+	heartbeatRepo := &mocks.MockHeartbeatRepository{
+		CreateFn: func(_ context.Context, hb *domain.Heartbeat) error {
+			stored = hb
+			return nil
+		},
+	}
+	var opened uuid.UUID
+	incidentSvc := &mocks.MockIncidentService{
+		CreateIncidentIfNeededFn: func(_ context.Context, monitorID uuid.UUID) (*domain.Incident, error) {
+			opened = monitorID
+			return domain.NewIncident(monitorID), nil
+		},
+	}
+
+	svc := services.NewPushService(monitorRepo, heartbeatRepo, &mocks.MockMonitorService{}, incidentSvc, transactor, slog.Default())
+	marked, err := svc.CheckOverdue(context.Background(), now)
+	require.NoError(t, err)
+
+	assert.True(t, inTx, "overdue scan must run inside a transaction for RLS")
+	assert.Equal(t, 1, marked)
+	assert.Equal(t, monitor.ID, opened)
+	require.NotNil(t, stored)
+	assert.Equal(t, domain.HeartbeatStatusTimeout, stored.Status)
+}
+
+func TestMarkAgentMonitorsDown_SkipsPushMonitors(t *testing.T) {
+	agentID := uuid.New()
+	monitor := domain.NewMonitor(agentID, "nightly backup", domain.MonitorTypePush, "")
+	monitor.Status = domain.MonitorStatusUp
+
+	monitorRepo := &mocks.MockMonitorRepository{
+		GetByAgentIDFn: func(_ context.Context, _ uuid.UUID) ([]*domain.Monitor, error) {
+			return []*domain.Monitor{monitor}, nil
+		},
+	}
+	incidentSvc := &mocks.MockIncidentService{
+		CreateIncidentSilentlyFn: func(_ context.Context, _ uuid.UUID) (*domain.Incident, error) {
+			t.Fatal("push monitors must not go down with their agent")
+			return nil, nil
+		},
+	}
+
+	svc := newTestMonitorService(monitorRepo, &mocks.MockHeartbeatRepository{}, &mocks.MockIncidentRepository{}, incidentSvc)
+	require.NoError(t, svc.MarkAgentMonitorsDown(context.Background(), agentID))
+}
@@ -2,6 +2,7 @@ package defaults
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/sylvester-francis/watchdog/core/domain"
 	"github.com/sylvester-francis/watchdog/core/ports"
@@ -15,13 +16,16 @@ var (
 	_ ports.AlertRouter = (*alertModule)(nil)
 )
 
-// alertModule wraps the existing Notifier for alert routing.
+// alertModule wraps the existing Notifier for alert routing and picks
+// per-user channels with the user's alert rules.
 type alertModule struct {
-	notifier ports.Notifier
+	notifier    ports.Notifier
+	ruleRepo    ports.AlertRuleRepository
+	channelRepo ports.AlertChannelRepository
 }
 
-func newAlertModule(notifier ports.Notifier) *alertModule {
-	return &alertModule{notifier: notifier}
+func newAlertModule(notifier ports.Notifier, ruleRepo ports.AlertRuleRepository, channelRepo ports.AlertChannelRepository) *alertModule {
+	return &alertModule{notifier: notifier, ruleRepo: ruleRepo, channelRepo: channelRepo}
 }
 
 func (m *alertModule) Name() string                    { return moduleAlertRouter }
@@ -36,3 +40,27 @@ func (m *alertModule) RouteIncidentOpened(ctx context.Context, incident *domain.
 func (m *alertModule) RouteIncidentResolved(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
 	return m.notifier.NotifyIncidentResolved(ctx, incident, monitor)
 }
+
+// RouteChannels evaluates the user's alert rules against the event and
+// resolves the enabled channels it goes to. Users without rules get every
+// enabled channel.
+func (m *alertModule) RouteChannels(ctx context.Context, event *domain.AlertEvent) (*domain.AlertRouting, error) {
+	var rules []*domain.AlertRule
+	if m.ruleRepo != nil {
+		var err error
+		if rules, err = m.ruleRepo.GetByUserID(ctx, event.UserID); err != nil {
+			return nil, fmt.Errorf("alertModule.RouteChannels: %w", err)
+		}
+	}
+
+	routing := domain.RouteAlert(rules, event)
+	if routing.Suppressed {
+		return routing, nil
+	}
+	channels, err := m.channelRepo.GetEnabledByUserID(ctx, event.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("alertModule.RouteChannels: %w", err)
+	}
+	routing.Channels = routing.Select(channels)
+	return routing, nil
+}
@@ -15,6 +15,8 @@ type Deps struct {
 	AgentAuth      ports.AgentAuthService
 	AgentRepo      ports.AgentRepository
 	Notifier       ports.Notifier
+	AlertRuleRepo  ports.AlertRuleRepository
+	ChannelRepo    ports.AlertChannelRepository
 	AuditService   ports.AuditService
 	StatusPageRepo ports.StatusPageRepository
 	DB             ports.Transactor
@@ -29,7 +31,7 @@ func RegisterAll(reg *registry.Registry, deps Deps) {
 	reg.Register(newStorageModule(deps.DB))
 	reg.Register(newAuthModule(deps.AuthService))
 	reg.Register(newAgentModule(deps.AgentAuth, deps.AgentRepo))
-	reg.Register(newAlertModule(deps.Notifier))
+	reg.Register(newAlertModule(deps.Notifier, deps.AlertRuleRepo, deps.ChannelRepo))
 	reg.Register(newAuditModule(deps.AuditService))
 	reg.Register(newStatusModule(deps.StatusPageRepo))
 	if deps.Pool != nil && deps.DurableAlerts {
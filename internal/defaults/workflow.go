@@ -498,6 +498,10 @@ func (m *workflowModule) getFailurePolicy(handler string) domain.FailurePolicy {
 	if handler == "alert.record_dispatch" {
 		return domain.FailurePolicySkip
 	}
+	// Finishing an escalation only cleans up its tracking row
+	if handler == "escalation.finish" {
+		return domain.FailurePolicySkip
+	}
 	// Discovery process_result retries on failure
 	if handler == "discovery.process_result" {
 		return domain.FailurePolicyRetry
@@ -29,6 +29,8 @@ func TestWorkflowModule_GetFailurePolicy(t *testing.T) {
 		{"alert.send_webhook", domain.FailurePolicySkip},
 		{"alert.record_dispatch", domain.FailurePolicySkip},
 		{"alert.resolve_channels", domain.FailurePolicyAbort},
+		{"escalation.finish", domain.FailurePolicySkip},
+		{"escalation.page", domain.FailurePolicyAbort},
 		{"some.other.handler", domain.FailurePolicyAbort},
 		{"alert.send", domain.FailurePolicyAbort}, // too short for send_ prefix
 	}
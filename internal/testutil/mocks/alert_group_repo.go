@@ -0,0 +1,98 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.AlertGroupRepository = (*MockAlertGroupRepository)(nil)
+
+// MockAlertGroupRepository is a mock implementation of ports.AlertGroupRepository.
+type MockAlertGroupRepository struct {
+	CreateFn           func(ctx context.Context, group *domain.AlertGroup) error
+	UpdateFn           func(ctx context.Context, group *domain.AlertGroup) error
+	DeleteFn           func(ctx context.Context, id uuid.UUID) error
+	ResolveFn          func(ctx context.Context, id uuid.UUID) (bool, error)
+	GetByIDFn          func(ctx context.Context, id uuid.UUID) (*domain.AlertGroup, error)
+	GetByUserIDFn      func(ctx context.Context, userID uuid.UUID, status domain.AlertGroupStatus) ([]*domain.AlertGroup, error)
+	GetAcceptingFn     func(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.AlertGroup, error)
+	GetDueFn           func(ctx context.Context, createdBefore time.Time) ([]*domain.AlertGroup, error)
+	GetIncidentsFn     func(ctx context.Context, groupID uuid.UUID) ([]*domain.Incident, error)
+	SetIncidentGroupFn func(ctx context.Context, incidentIDs []uuid.UUID, groupID *uuid.UUID) error
+}
+
+func (m *MockAlertGroupRepository) Create(ctx context.Context, group *domain.AlertGroup) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, group)
+	}
+	return nil
+}
+
+func (m *MockAlertGroupRepository) Update(ctx context.Context, group *domain.AlertGroup) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, group)
+	}
+	return nil
+}
+
+func (m *MockAlertGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockAlertGroupRepository) Resolve(ctx context.Context, id uuid.UUID) (bool, error) {
+	if m.ResolveFn != nil {
+		return m.ResolveFn(ctx, id)
+	}
+	return true, nil
+}
+
+func (m *MockAlertGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AlertGroup, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockAlertGroupRepository) GetByUserID(ctx context.Context, userID uuid.UUID, status domain.AlertGroupStatus) ([]*domain.AlertGroup, error) {
+	if m.GetByUserIDFn != nil {
+		return m.GetByUserIDFn(ctx, userID, status)
+	}
+	return nil, nil
+}
+
+func (m *MockAlertGroupRepository) GetAccepting(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.AlertGroup, error) {
+	if m.GetAcceptingFn != nil {
+		return m.GetAcceptingFn(ctx, userID, since)
+	}
+	return nil, nil
+}
+
+func (m *MockAlertGroupRepository) GetDue(ctx context.Context, createdBefore time.Time) ([]*domain.AlertGroup, error) {
+	if m.GetDueFn != nil {
+		return m.GetDueFn(ctx, createdBefore)
+	}
+	return nil, nil
+}
+
+func (m *MockAlertGroupRepository) GetIncidents(ctx context.Context, groupID uuid.UUID) ([]*domain.Incident, error) {
+	if m.GetIncidentsFn != nil {
+		return m.GetIncidentsFn(ctx, groupID)
+	}
+	return nil, nil
+}
+
+func (m *MockAlertGroupRepository) SetIncidentGroup(ctx context.Context, incidentIDs []uuid.UUID, groupID *uuid.UUID) error {
+	if m.SetIncidentGroupFn != nil {
+		return m.SetIncidentGroupFn(ctx, incidentIDs, groupID)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.AlertRuleRepository = (*MockAlertRuleRepository)(nil)
+
+// MockAlertRuleRepository is a mock implementation of ports.AlertRuleRepository.
+type MockAlertRuleRepository struct {
+	CreateFn      func(ctx context.Context, rule *domain.AlertRule) error
+	UpdateFn      func(ctx context.Context, rule *domain.AlertRule) error
+	DeleteFn      func(ctx context.Context, id uuid.UUID) error
+	GetByIDFn     func(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error)
+	GetByUserIDFn func(ctx context.Context, userID uuid.UUID) ([]*domain.AlertRule, error)
+}
+
+func (m *MockAlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, rule)
+	}
+	return nil
+}
+
+func (m *MockAlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, rule)
+	}
+	return nil
+}
+
+func (m *MockAlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockAlertRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockAlertRuleRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.AlertRule, error) {
+	if m.GetByUserIDFn != nil {
+		return m.GetByUserIDFn(ctx, userID)
+	}
+	return nil, nil
+}
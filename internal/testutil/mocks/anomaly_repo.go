@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.AnomalyRepository = (*MockAnomalyRepository)(nil)
+
+// MockAnomalyRepository is a mock implementation of ports.AnomalyRepository.
+type MockAnomalyRepository struct {
+	GetBaselinesFn        func(ctx context.Context, monitorID uuid.UUID) ([]*domain.LatencyBaseline, error)
+	UpsertBaselineFn      func(ctx context.Context, baseline *domain.LatencyBaseline) error
+	CreateAnomalyFn       func(ctx context.Context, anomaly *domain.LatencyAnomaly) error
+	GetAnomaliesInRangeFn func(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]*domain.LatencyAnomaly, error)
+}
+
+func (m *MockAnomalyRepository) GetBaselines(ctx context.Context, monitorID uuid.UUID) ([]*domain.LatencyBaseline, error) {
+	if m.GetBaselinesFn != nil {
+		return m.GetBaselinesFn(ctx, monitorID)
+	}
+	return nil, nil
+}
+
+func (m *MockAnomalyRepository) UpsertBaseline(ctx context.Context, baseline *domain.LatencyBaseline) error {
+	if m.UpsertBaselineFn != nil {
+		return m.UpsertBaselineFn(ctx, baseline)
+	}
+	return nil
+}
+
+func (m *MockAnomalyRepository) CreateAnomaly(ctx context.Context, anomaly *domain.LatencyAnomaly) error {
+	if m.CreateAnomalyFn != nil {
+		return m.CreateAnomalyFn(ctx, anomaly)
+	}
+	return nil
+}
+
+func (m *MockAnomalyRepository) GetAnomaliesInRange(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]*domain.LatencyAnomaly, error) {
+	if m.GetAnomaliesInRangeFn != nil {
+		return m.GetAnomaliesInRangeFn(ctx, monitorID, from, to)
+	}
+	return nil, nil
+}
@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.DeferredNotificationRepository = (*MockDeferredNotificationRepository)(nil)
+
+// MockDeferredNotificationRepository is a mock implementation of ports.DeferredNotificationRepository.
+type MockDeferredNotificationRepository struct {
+	CreateFn           func(ctx context.Context, n *domain.DeferredNotification) error
+	GetDueFn           func(ctx context.Context, now time.Time) ([]*domain.DeferredNotification, error)
+	CountByChannelIDFn func(ctx context.Context, channelID uuid.UUID) (int, error)
+	RescheduleFn       func(ctx context.Context, id uuid.UUID, deliverAt time.Time, attempts int) error
+	DeleteFn           func(ctx context.Context, id uuid.UUID) error
+}
+
+func (m *MockDeferredNotificationRepository) Create(ctx context.Context, n *domain.DeferredNotification) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, n)
+	}
+	return nil
+}
+
+func (m *MockDeferredNotificationRepository) GetDue(ctx context.Context, now time.Time) ([]*domain.DeferredNotification, error) {
+	if m.GetDueFn != nil {
+		return m.GetDueFn(ctx, now)
+	}
+	return nil, nil
+}
+
+func (m *MockDeferredNotificationRepository) CountByChannelID(ctx context.Context, channelID uuid.UUID) (int, error) {
+	if m.CountByChannelIDFn != nil {
+		return m.CountByChannelIDFn(ctx, channelID)
+	}
+	return 0, nil
+}
+
+func (m *MockDeferredNotificationRepository) Reschedule(ctx context.Context, id uuid.UUID, deliverAt time.Time, attempts int) error {
+	if m.RescheduleFn != nil {
+		return m.RescheduleFn(ctx, id, deliverAt, attempts)
+	}
+	return nil
+}
+
+func (m *MockDeferredNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.DependencyRepository = (*MockDependencyRepository)(nil)
+
+// MockDependencyRepository is a mock implementation of ports.DependencyRepository.
+type MockDependencyRepository struct {
+	CreateFn       func(ctx context.Context, dep *domain.MonitorDependency) error
+	DeleteFn       func(ctx context.Context, monitorID, parentID uuid.UUID) error
+	GetParentIDsFn func(ctx context.Context, monitorID uuid.UUID) ([]uuid.UUID, error)
+	GetByTenantFn  func(ctx context.Context) ([]*domain.MonitorDependency, error)
+}
+
+func (m *MockDependencyRepository) Create(ctx context.Context, dep *domain.MonitorDependency) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, dep)
+	}
+	return nil
+}
+
+func (m *MockDependencyRepository) Delete(ctx context.Context, monitorID, parentID uuid.UUID) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, monitorID, parentID)
+	}
+	return nil
+}
+
+func (m *MockDependencyRepository) GetParentIDs(ctx context.Context, monitorID uuid.UUID) ([]uuid.UUID, error) {
+	if m.GetParentIDsFn != nil {
+		return m.GetParentIDsFn(ctx, monitorID)
+	}
+	return nil, nil
+}
+
+func (m *MockDependencyRepository) GetByTenant(ctx context.Context) ([]*domain.MonitorDependency, error) {
+	if m.GetByTenantFn != nil {
+		return m.GetByTenantFn(ctx)
+	}
+	return nil, nil
+}
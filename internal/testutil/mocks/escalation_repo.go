@@ -0,0 +1,114 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.EscalationRepository = (*MockEscalationRepository)(nil)
+
+// MockEscalationRepository is a mock implementation of ports.EscalationRepository.
+type MockEscalationRepository struct {
+	CreatePolicyFn          func(ctx context.Context, policy *domain.EscalationPolicy) error
+	UpdatePolicyFn          func(ctx context.Context, policy *domain.EscalationPolicy) error
+	DeletePolicyFn          func(ctx context.Context, id uuid.UUID) error
+	GetPolicyByIDFn         func(ctx context.Context, id uuid.UUID) (*domain.EscalationPolicy, error)
+	GetPolicyByNameFn       func(ctx context.Context, userID uuid.UUID, name string) (*domain.EscalationPolicy, error)
+	GetPoliciesByUserIDFn   func(ctx context.Context, userID uuid.UUID) ([]*domain.EscalationPolicy, error)
+	CreateEscalationFn      func(ctx context.Context, escalation *domain.Escalation) error
+	UpdateEscalationFn      func(ctx context.Context, escalation *domain.Escalation) error
+	SetEscalationWorkflowFn func(ctx context.Context, incidentID, workflowID uuid.UUID) error
+	DeleteEscalationFn      func(ctx context.Context, incidentID uuid.UUID) error
+	GetEscalationFn         func(ctx context.Context, incidentID uuid.UUID) (*domain.Escalation, error)
+	GetDueEscalationsFn     func(ctx context.Context, now time.Time) ([]*domain.Escalation, error)
+}
+
+func (m *MockEscalationRepository) CreatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error {
+	if m.CreatePolicyFn != nil {
+		return m.CreatePolicyFn(ctx, policy)
+	}
+	return nil
+}
+
+func (m *MockEscalationRepository) UpdatePolicy(ctx context.Context, policy *domain.EscalationPolicy) error {
+	if m.UpdatePolicyFn != nil {
+		return m.UpdatePolicyFn(ctx, policy)
+	}
+	return nil
+}
+
+func (m *MockEscalationRepository) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	if m.DeletePolicyFn != nil {
+		return m.DeletePolicyFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockEscalationRepository) GetPolicyByID(ctx context.Context, id uuid.UUID) (*domain.EscalationPolicy, error) {
+	if m.GetPolicyByIDFn != nil {
+		return m.GetPolicyByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockEscalationRepository) GetPolicyByName(ctx context.Context, userID uuid.UUID, name string) (*domain.EscalationPolicy, error) {
+	if m.GetPolicyByNameFn != nil {
+		return m.GetPolicyByNameFn(ctx, userID, name)
+	}
+	return nil, nil
+}
+
+func (m *MockEscalationRepository) GetPoliciesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.EscalationPolicy, error) {
+	if m.GetPoliciesByUserIDFn != nil {
+		return m.GetPoliciesByUserIDFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockEscalationRepository) CreateEscalation(ctx context.Context, escalation *domain.Escalation) error {
+	if m.CreateEscalationFn != nil {
+		return m.CreateEscalationFn(ctx, escalation)
+	}
+	return nil
+}
+
+func (m *MockEscalationRepository) UpdateEscalation(ctx context.Context, escalation *domain.Escalation) error {
+	if m.UpdateEscalationFn != nil {
+		return m.UpdateEscalationFn(ctx, escalation)
+	}
+	return nil
+}
+
+func (m *MockEscalationRepository) SetEscalationWorkflow(ctx context.Context, incidentID, workflowID uuid.UUID) error {
+	if m.SetEscalationWorkflowFn != nil {
+		return m.SetEscalationWorkflowFn(ctx, incidentID, workflowID)
+	}
+	return nil
+}
+
+func (m *MockEscalationRepository) DeleteEscalation(ctx context.Context, incidentID uuid.UUID) error {
+	if m.DeleteEscalationFn != nil {
+		return m.DeleteEscalationFn(ctx, incidentID)
+	}
+	return nil
+}
+
+func (m *MockEscalationRepository) GetEscalation(ctx context.Context, incidentID uuid.UUID) (*domain.Escalation, error) {
+	if m.GetEscalationFn != nil {
+		return m.GetEscalationFn(ctx, incidentID)
+	}
+	return nil, nil
+}
+
+func (m *MockEscalationRepository) GetDueEscalations(ctx context.Context, now time.Time) ([]*domain.Escalation, error) {
+	if m.GetDueEscalationsFn != nil {
+		return m.GetDueEscalationsFn(ctx, now)
+	}
+	return nil, nil
+}
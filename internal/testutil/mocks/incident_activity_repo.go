@@ -0,0 +1,73 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.IncidentActivityRepository = (*MockIncidentActivityRepository)(nil)
+
+// MockIncidentActivityRepository is a mock implementation of ports.IncidentActivityRepository.
+type MockIncidentActivityRepository struct {
+	CreateFn           func(ctx context.Context, activity *domain.IncidentActivity) error
+	UpdateFn           func(ctx context.Context, activity *domain.IncidentActivity) error
+	DeleteFn           func(ctx context.Context, id uuid.UUID) error
+	GetByIDFn          func(ctx context.Context, id uuid.UUID) (*domain.IncidentActivity, error)
+	GetByIncidentIDFn  func(ctx context.Context, incidentID uuid.UUID) ([]*domain.IncidentActivity, error)
+	GetPostmortemFn    func(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentPostmortem, error)
+	UpsertPostmortemFn func(ctx context.Context, postmortem *domain.IncidentPostmortem) error
+}
+
+func (m *MockIncidentActivityRepository) Create(ctx context.Context, activity *domain.IncidentActivity) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, activity)
+	}
+	return nil
+}
+
+func (m *MockIncidentActivityRepository) Update(ctx context.Context, activity *domain.IncidentActivity) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, activity)
+	}
+	return nil
+}
+
+func (m *MockIncidentActivityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockIncidentActivityRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.IncidentActivity, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockIncidentActivityRepository) GetByIncidentID(ctx context.Context, incidentID uuid.UUID) ([]*domain.IncidentActivity, error) {
+	if m.GetByIncidentIDFn != nil {
+		return m.GetByIncidentIDFn(ctx, incidentID)
+	}
+	return nil, nil
+}
+
+func (m *MockIncidentActivityRepository) GetPostmortem(ctx context.Context, incidentID uuid.UUID) (*domain.IncidentPostmortem, error) {
+	if m.GetPostmortemFn != nil {
+		return m.GetPostmortemFn(ctx, incidentID)
+	}
+	return nil, nil
+}
+
+func (m *MockIncidentActivityRepository) UpsertPostmortem(ctx context.Context, postmortem *domain.IncidentPostmortem) error {
+	if m.UpsertPostmortemFn != nil {
+		return m.UpsertPostmortemFn(ctx, postmortem)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.MessageTemplateRepository = (*MockMessageTemplateRepository)(nil)
+
+// MockMessageTemplateRepository is a mock implementation of ports.MessageTemplateRepository.
+type MockMessageTemplateRepository struct {
+	CreateFn      func(ctx context.Context, t *domain.MessageTemplate) error
+	UpdateFn      func(ctx context.Context, t *domain.MessageTemplate) error
+	DeleteFn      func(ctx context.Context, id uuid.UUID) error
+	GetByIDFn     func(ctx context.Context, id uuid.UUID) (*domain.MessageTemplate, error)
+	GetByUserIDFn func(ctx context.Context, userID uuid.UUID) ([]*domain.MessageTemplate, error)
+	ResolveFn     func(ctx context.Context, channel *domain.AlertChannel, event string) (*domain.MessageTemplate, error)
+}
+
+func (m *MockMessageTemplateRepository) Create(ctx context.Context, t *domain.MessageTemplate) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, t)
+	}
+	return nil
+}
+
+func (m *MockMessageTemplateRepository) Update(ctx context.Context, t *domain.MessageTemplate) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, t)
+	}
+	return nil
+}
+
+func (m *MockMessageTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockMessageTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MessageTemplate, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageTemplateRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.MessageTemplate, error) {
+	if m.GetByUserIDFn != nil {
+		return m.GetByUserIDFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageTemplateRepository) Resolve(ctx context.Context, channel *domain.AlertChannel, event string) (*domain.MessageTemplate, error) {
+	if m.ResolveFn != nil {
+		return m.ResolveFn(ctx, channel, event)
+	}
+	return nil, nil
+}
@@ -0,0 +1,82 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.NotificationDeliveryRepository = (*MockNotificationDeliveryRepository)(nil)
+
+// MockNotificationDeliveryRepository is a mock implementation of ports.NotificationDeliveryRepository.
+type MockNotificationDeliveryRepository struct {
+	CreateFn        func(ctx context.Context, d *domain.NotificationDelivery) error
+	UpdateFn        func(ctx context.Context, d *domain.NotificationDelivery) error
+	CreateAttemptFn func(ctx context.Context, a *domain.NotificationAttempt) error
+	GetByIDFn       func(ctx context.Context, id uuid.UUID) (*domain.NotificationDelivery, error)
+	GetAttemptsFn   func(ctx context.Context, deliveryID uuid.UUID) ([]*domain.NotificationAttempt, error)
+	ListFn          func(ctx context.Context, filter domain.NotificationDeliveryFilter) ([]*domain.NotificationDelivery, error)
+	GetDueFn        func(ctx context.Context, now time.Time) ([]*domain.NotificationDelivery, error)
+	DeleteBeforeFn  func(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (m *MockNotificationDeliveryRepository) Create(ctx context.Context, d *domain.NotificationDelivery) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, d)
+	}
+	return nil
+}
+
+func (m *MockNotificationDeliveryRepository) Update(ctx context.Context, d *domain.NotificationDelivery) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, d)
+	}
+	return nil
+}
+
+func (m *MockNotificationDeliveryRepository) CreateAttempt(ctx context.Context, a *domain.NotificationAttempt) error {
+	if m.CreateAttemptFn != nil {
+		return m.CreateAttemptFn(ctx, a)
+	}
+	return nil
+}
+
+func (m *MockNotificationDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.NotificationDelivery, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationDeliveryRepository) GetAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*domain.NotificationAttempt, error) {
+	if m.GetAttemptsFn != nil {
+		return m.GetAttemptsFn(ctx, deliveryID)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationDeliveryRepository) List(ctx context.Context, filter domain.NotificationDeliveryFilter) ([]*domain.NotificationDelivery, error) {
+	if m.ListFn != nil {
+		return m.ListFn(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationDeliveryRepository) GetDue(ctx context.Context, now time.Time) ([]*domain.NotificationDelivery, error) {
+	if m.GetDueFn != nil {
+		return m.GetDueFn(ctx, now)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationDeliveryRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	if m.DeleteBeforeFn != nil {
+		return m.DeleteBeforeFn(ctx, before)
+	}
+	return 0, nil
+}
@@ -0,0 +1,90 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.NotificationLogRepository = (*MockNotificationLogRepository)(nil)
+
+// MockNotificationLogRepository is a mock implementation of ports.NotificationLogRepository.
+type MockNotificationLogRepository struct {
+	CreateFn          func(ctx context.Context, entry *domain.NotificationLogEntry) error
+	CountSentFn       func(ctx context.Context, channelID *uuid.UUID, since time.Time) (int, error)
+	HasRecentFn       func(ctx context.Context, channelID *uuid.UUID, dedupKey string, since time.Time) (bool, error)
+	CountUnreportedFn func(ctx context.Context, channelID *uuid.UUID) (int, error)
+	MarkReportedFn    func(ctx context.Context, channelID *uuid.UUID, before time.Time) error
+	GetDigestDueAtFn  func(ctx context.Context, channelID *uuid.UUID) (*time.Time, error)
+	GetDueDigestsFn   func(ctx context.Context, now time.Time) ([]*domain.NotificationLogEntry, error)
+	MarkDigestedFn    func(ctx context.Context, ids []uuid.UUID) error
+	DeleteBeforeFn    func(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (m *MockNotificationLogRepository) Create(ctx context.Context, entry *domain.NotificationLogEntry) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entry)
+	}
+	return nil
+}
+
+func (m *MockNotificationLogRepository) CountSent(ctx context.Context, channelID *uuid.UUID, since time.Time) (int, error) {
+	if m.CountSentFn != nil {
+		return m.CountSentFn(ctx, channelID, since)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationLogRepository) HasRecent(ctx context.Context, channelID *uuid.UUID, dedupKey string, since time.Time) (bool, error) {
+	if m.HasRecentFn != nil {
+		return m.HasRecentFn(ctx, channelID, dedupKey, since)
+	}
+	return false, nil
+}
+
+func (m *MockNotificationLogRepository) CountUnreported(ctx context.Context, channelID *uuid.UUID) (int, error) {
+	if m.CountUnreportedFn != nil {
+		return m.CountUnreportedFn(ctx, channelID)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationLogRepository) MarkReported(ctx context.Context, channelID *uuid.UUID, before time.Time) error {
+	if m.MarkReportedFn != nil {
+		return m.MarkReportedFn(ctx, channelID, before)
+	}
+	return nil
+}
+
+func (m *MockNotificationLogRepository) GetDigestDueAt(ctx context.Context, channelID *uuid.UUID) (*time.Time, error) {
+	if m.GetDigestDueAtFn != nil {
+		return m.GetDigestDueAtFn(ctx, channelID)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationLogRepository) GetDueDigests(ctx context.Context, now time.Time) ([]*domain.NotificationLogEntry, error) {
+	if m.GetDueDigestsFn != nil {
+		return m.GetDueDigestsFn(ctx, now)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationLogRepository) MarkDigested(ctx context.Context, ids []uuid.UUID) error {
+	if m.MarkDigestedFn != nil {
+		return m.MarkDigestedFn(ctx, ids)
+	}
+	return nil
+}
+
+func (m *MockNotificationLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	if m.DeleteBeforeFn != nil {
+		return m.DeleteBeforeFn(ctx, before)
+	}
+	return 0, nil
+}
@@ -0,0 +1,90 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.OnCallRepository = (*MockOnCallRepository)(nil)
+
+// MockOnCallRepository is a mock implementation of ports.OnCallRepository.
+type MockOnCallRepository struct {
+	CreateScheduleFn       func(ctx context.Context, schedule *domain.OnCallSchedule) error
+	UpdateScheduleFn       func(ctx context.Context, schedule *domain.OnCallSchedule) error
+	DeleteScheduleFn       func(ctx context.Context, id uuid.UUID) error
+	GetScheduleByIDFn      func(ctx context.Context, id uuid.UUID) (*domain.OnCallSchedule, error)
+	GetSchedulesByUserIDFn func(ctx context.Context, userID uuid.UUID) ([]*domain.OnCallSchedule, error)
+	CreateOverrideFn       func(ctx context.Context, override *domain.OnCallOverride) error
+	DeleteOverrideFn       func(ctx context.Context, id uuid.UUID) error
+	GetOverrideByIDFn      func(ctx context.Context, id uuid.UUID) (*domain.OnCallOverride, error)
+	GetOverridesFn         func(ctx context.Context, scheduleID uuid.UUID, from, to time.Time) ([]*domain.OnCallOverride, error)
+}
+
+func (m *MockOnCallRepository) CreateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	if m.CreateScheduleFn != nil {
+		return m.CreateScheduleFn(ctx, schedule)
+	}
+	return nil
+}
+
+func (m *MockOnCallRepository) UpdateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) error {
+	if m.UpdateScheduleFn != nil {
+		return m.UpdateScheduleFn(ctx, schedule)
+	}
+	return nil
+}
+
+func (m *MockOnCallRepository) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteScheduleFn != nil {
+		return m.DeleteScheduleFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockOnCallRepository) GetScheduleByID(ctx context.Context, id uuid.UUID) (*domain.OnCallSchedule, error) {
+	if m.GetScheduleByIDFn != nil {
+		return m.GetScheduleByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockOnCallRepository) GetSchedulesByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.OnCallSchedule, error) {
+	if m.GetSchedulesByUserIDFn != nil {
+		return m.GetSchedulesByUserIDFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockOnCallRepository) CreateOverride(ctx context.Context, override *domain.OnCallOverride) error {
+	if m.CreateOverrideFn != nil {
+		return m.CreateOverrideFn(ctx, override)
+	}
+	return nil
+}
+
+func (m *MockOnCallRepository) DeleteOverride(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteOverrideFn != nil {
+		return m.DeleteOverrideFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockOnCallRepository) GetOverrideByID(ctx context.Context, id uuid.UUID) (*domain.OnCallOverride, error) {
+	if m.GetOverrideByIDFn != nil {
+		return m.GetOverrideByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockOnCallRepository) GetOverrides(ctx context.Context, scheduleID uuid.UUID, from, to time.Time) ([]*domain.OnCallOverride, error) {
+	if m.GetOverridesFn != nil {
+		return m.GetOverridesFn(ctx, scheduleID, from, to)
+	}
+	return nil, nil
+}
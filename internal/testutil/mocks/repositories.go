@@ -148,6 +148,7 @@ type MockAgentRepository struct {
 	UpdateFingerprintFn func(ctx context.Context, id uuid.UUID, fingerprint map[string]string) error
 	UpdateVersionFn     func(ctx context.Context, id uuid.UUID, version string) error
 	CountByUserIDFn     func(ctx context.Context, userID uuid.UUID) (int, error)
+	GetHubAgentsFn      func(ctx context.Context) ([]*domain.Agent, error)
 }
 
 func (m *MockAgentRepository) Create(ctx context.Context, agent *domain.Agent) error {
@@ -234,20 +235,34 @@ func (m *MockAgentRepository) CountByUserID(ctx context.Context, userID uuid.UUI
 	return 0, nil
 }
 
+func (m *MockAgentRepository) GetHubAgents(ctx context.Context) ([]*domain.Agent, error) {
+	if m.GetHubAgentsFn != nil {
+		return m.GetHubAgentsFn(ctx)
+	}
+	return nil, nil
+}
+
 // MockMonitorRepository is a mock implementation of ports.MonitorRepository.
 type MockMonitorRepository struct {
-	CreateFn              func(ctx context.Context, monitor *domain.Monitor) error
-	GetByIDFn             func(ctx context.Context, id uuid.UUID) (*domain.Monitor, error)
-	GetByAgentIDFn        func(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
-	GetEnabledByAgentIDFn func(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
+	CreateFn                 func(ctx context.Context, monitor *domain.Monitor) error
+	GetByIDFn                func(ctx context.Context, id uuid.UUID) (*domain.Monitor, error)
+	GetByAgentIDFn           func(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
+	GetEnabledByAgentIDFn    func(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
 	GetAllInTenantFn         func(ctx context.Context) ([]*domain.Monitor, error)
 	GetAllInTenantWithTagsFn func(ctx context.Context, tags map[string]string) ([]*domain.Monitor, error)
 	GetByUserIDWithTagsFn    func(ctx context.Context, userID uuid.UUID, tags map[string]string) ([]*domain.Monitor, error)
-	UpdateFn              func(ctx context.Context, monitor *domain.Monitor) error
-	DeleteFn              func(ctx context.Context, id uuid.UUID) error
-	UpdateStatusFn        func(ctx context.Context, id uuid.UUID, status domain.MonitorStatus) error
-	CountByUserIDFn       func(ctx context.Context, userID uuid.UUID) (int, error)
-	UpdateMetadataFn      func(ctx context.Context, id uuid.UUID, metadata map[string]string) error
+	UpdateFn                 func(ctx context.Context, monitor *domain.Monitor) error
+	DeleteFn                 func(ctx context.Context, id uuid.UUID) error
+	UpdateStatusFn           func(ctx context.Context, id uuid.UUID, status domain.MonitorStatus) error
+	CountByUserIDFn          func(ctx context.Context, userID uuid.UUID) (int, error)
+	UpdateMetadataFn         func(ctx context.Context, id uuid.UUID, metadata map[string]string) error
+	MergeMetadataFn          func(ctx context.Context, id uuid.UUID, metadata map[string]string) error
+	SetLocationsFn           func(ctx context.Context, monitorID uuid.UUID, agentIDs []uuid.UUID) error
+	GetByPushTokenGlobalFn   func(ctx context.Context, token string) (*domain.Monitor, error)
+	RecordPingFn             func(ctx context.Context, id uuid.UUID, at time.Time, start bool) error
+	GetOverduePushFn         func(ctx context.Context, now time.Time) ([]*domain.Monitor, error)
+	UpdateTransactionRunFn   func(ctx context.Context, id uuid.UUID, run *domain.TransactionRun) error
+	GetTransactionVersionsFn func(ctx context.Context, monitorID uuid.UUID) ([]*domain.TransactionVersion, error)
 }
 
 func (m *MockMonitorRepository) Create(ctx context.Context, monitor *domain.Monitor) error {
@@ -334,6 +349,55 @@ func (m *MockMonitorRepository) UpdateMetadata(ctx context.Context, id uuid.UUID
 	return nil
 }
 
+func (m *MockMonitorRepository) MergeMetadata(ctx context.Context, id uuid.UUID, metadata map[string]string) error {
+	if m.MergeMetadataFn != nil {
+		return m.MergeMetadataFn(ctx, id, metadata)
+	}
+	return nil
+}
+
+func (m *MockMonitorRepository) SetLocations(ctx context.Context, monitorID uuid.UUID, agentIDs []uuid.UUID) error {
+	if m.SetLocationsFn != nil {
+		return m.SetLocationsFn(ctx, monitorID, agentIDs)
+	}
+	return nil
+}
+
+func (m *MockMonitorRepository) GetByPushTokenGlobal(ctx context.Context, token string) (*domain.Monitor, error) {
+	if m.GetByPushTokenGlobalFn != nil {
+		return m.GetByPushTokenGlobalFn(ctx, token)
+	}
+	return nil, nil
+}
+
+func (m *MockMonitorRepository) RecordPing(ctx context.Context, id uuid.UUID, at time.Time, start bool) error {
+	if m.RecordPingFn != nil {
+		return m.RecordPingFn(ctx, id, at, start)
+	}
+	return nil
+}
+
+func (m *MockMonitorRepository) GetOverduePush(ctx context.Context, now time.Time) ([]*domain.Monitor, error) {
+	if m.GetOverduePushFn != nil {
+		return m.GetOverduePushFn(ctx, now)
+	}
+	return nil, nil
+}
+
+func (m *MockMonitorRepository) UpdateTransactionRun(ctx context.Context, id uuid.UUID, run *domain.TransactionRun) error {
+	if m.UpdateTransactionRunFn != nil {
+		return m.UpdateTransactionRunFn(ctx, id, run)
+	}
+	return nil
+}
+
+func (m *MockMonitorRepository) GetTransactionVersions(ctx context.Context, monitorID uuid.UUID) ([]*domain.TransactionVersion, error) {
+	if m.GetTransactionVersionsFn != nil {
+		return m.GetTransactionVersionsFn(ctx, monitorID)
+	}
+	return nil, nil
+}
+
 // MockIncidentRepository is a mock implementation of ports.IncidentRepository.
 type MockIncidentRepository struct {
 	CreateFn               func(ctx context.Context, incident *domain.Incident) error
@@ -346,6 +410,12 @@ type MockIncidentRepository struct {
 	UpdateFn               func(ctx context.Context, incident *domain.Incident) error
 	AcknowledgeFn          func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	ResolveFn              func(ctx context.Context, id uuid.UUID) error
+	SetFlappingFn          func(ctx context.Context, id uuid.UUID, flapping bool) error
+	GetActiveByParentIDFn  func(ctx context.Context, parentIncidentID uuid.UUID) ([]*domain.Incident, error)
+	SetParentFn            func(ctx context.Context, id uuid.UUID, parentIncidentID *uuid.UUID) error
+	SetSeverityFn          func(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error
+	SetOwnerFn             func(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error
+	GetDeclaredAffectingFn func(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error)
 }
 
 func (m *MockIncidentRepository) Create(ctx context.Context, incident *domain.Incident) error {
@@ -418,19 +488,64 @@ func (m *MockIncidentRepository) Resolve(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+func (m *MockIncidentRepository) SetFlapping(ctx context.Context, id uuid.UUID, flapping bool) error {
+	if m.SetFlappingFn != nil {
+		return m.SetFlappingFn(ctx, id, flapping)
+	}
+	return nil
+}
+
+func (m *MockIncidentRepository) GetActiveByParentID(ctx context.Context, parentIncidentID uuid.UUID) ([]*domain.Incident, error) {
+	if m.GetActiveByParentIDFn != nil {
+		return m.GetActiveByParentIDFn(ctx, parentIncidentID)
+	}
+	return nil, nil
+}
+
+func (m *MockIncidentRepository) SetParent(ctx context.Context, id uuid.UUID, parentIncidentID *uuid.UUID) error {
+	if m.SetParentFn != nil {
+		return m.SetParentFn(ctx, id, parentIncidentID)
+	}
+	return nil
+}
+
+func (m *MockIncidentRepository) SetSeverity(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error {
+	if m.SetSeverityFn != nil {
+		return m.SetSeverityFn(ctx, id, severity)
+	}
+	return nil
+}
+
+func (m *MockIncidentRepository) SetOwner(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error {
+	if m.SetOwnerFn != nil {
+		return m.SetOwnerFn(ctx, id, ownerID)
+	}
+	return nil
+}
+
+func (m *MockIncidentRepository) GetDeclaredAffecting(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error) {
+	if m.GetDeclaredAffectingFn != nil {
+		return m.GetDeclaredAffectingFn(ctx, monitorIDs, since)
+	}
+	return nil, nil
+}
+
 // MockHeartbeatRepository is a mock implementation of ports.HeartbeatRepository.
 type MockHeartbeatRepository struct {
-	CreateFn                func(ctx context.Context, heartbeat *domain.Heartbeat) error
-	CreateBatchFn           func(ctx context.Context, heartbeats []*domain.Heartbeat) error
-	GetByMonitorIDFn        func(ctx context.Context, monitorID uuid.UUID, limit int) ([]*domain.Heartbeat, error)
-	GetByMonitorIDInRangeFn func(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]*domain.Heartbeat, error)
-	GetLatestByMonitorIDFn  func(ctx context.Context, monitorID uuid.UUID) (*domain.Heartbeat, error)
-	GetRecentFailuresFn     func(ctx context.Context, monitorID uuid.UUID, count int) ([]*domain.Heartbeat, error)
-	GetUptimePercentFn      func(ctx context.Context, monitorID uuid.UUID, since time.Time) (float64, error)
+	CreateFn                      func(ctx context.Context, heartbeat *domain.Heartbeat) error
+	CreateBatchFn                 func(ctx context.Context, heartbeats []*domain.Heartbeat) error
+	GetByMonitorIDFn              func(ctx context.Context, monitorID uuid.UUID, limit int) ([]*domain.Heartbeat, error)
+	GetByMonitorIDInRangeFn       func(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]*domain.Heartbeat, error)
+	GetRecentPerAgentFn           func(ctx context.Context, monitorID uuid.UUID, perAgent int) ([]*domain.Heartbeat, error)
+	GetLatestByMonitorIDFn        func(ctx context.Context, monitorID uuid.UUID) (*domain.Heartbeat, error)
+	GetRecentFailuresFn           func(ctx context.Context, monitorID uuid.UUID, count int) ([]*domain.Heartbeat, error)
+	GetUptimePercentFn            func(ctx context.Context, monitorID uuid.UUID, since time.Time) (float64, error)
 	GetLatencyHistoryFn           func(ctx context.Context, monitorID uuid.UUID, since time.Time, bucketInterval string) ([]domain.LatencyPoint, error)
 	GetLatencyPercentilesFn       func(ctx context.Context, monitorID uuid.UUID, from, to time.Time, bucketInterval string) ([]domain.LatencyPercentilePoint, error)
 	GetLatencyPercentileSummaryFn func(ctx context.Context, monitorID uuid.UUID, from, to time.Time) (domain.LatencyTrendSummary, error)
 	DeleteOlderThanFn             func(ctx context.Context, before time.Time) (int64, error)
+	DeleteRollupsOlderThanFn      func(ctx context.Context, before time.Time) error
+	RefreshRollupsFn              func(ctx context.Context, from, to time.Time) error
 }
 
 func (m *MockHeartbeatRepository) Create(ctx context.Context, heartbeat *domain.Heartbeat) error {
@@ -461,6 +576,13 @@ func (m *MockHeartbeatRepository) GetByMonitorIDInRange(ctx context.Context, mon
 	return nil, nil
 }
 
+func (m *MockHeartbeatRepository) GetRecentPerAgent(ctx context.Context, monitorID uuid.UUID, perAgent int) ([]*domain.Heartbeat, error) {
+	if m.GetRecentPerAgentFn != nil {
+		return m.GetRecentPerAgentFn(ctx, monitorID, perAgent)
+	}
+	return nil, nil
+}
+
 func (m *MockHeartbeatRepository) GetLatestByMonitorID(ctx context.Context, monitorID uuid.UUID) (*domain.Heartbeat, error) {
 	if m.GetLatestByMonitorIDFn != nil {
 		return m.GetLatestByMonitorIDFn(ctx, monitorID)
@@ -510,6 +632,20 @@ func (m *MockHeartbeatRepository) DeleteOlderThan(ctx context.Context, before ti
 	return 0, nil
 }
 
+func (m *MockHeartbeatRepository) DeleteRollupsOlderThan(ctx context.Context, before time.Time) error {
+	if m.DeleteRollupsOlderThanFn != nil {
+		return m.DeleteRollupsOlderThanFn(ctx, before)
+	}
+	return nil
+}
+
+func (m *MockHeartbeatRepository) RefreshRollups(ctx context.Context, from, to time.Time) error {
+	if m.RefreshRollupsFn != nil {
+		return m.RefreshRollupsFn(ctx, from, to)
+	}
+	return nil
+}
+
 // MockUsageEventRepository is a mock implementation of ports.UsageEventRepository.
 type MockUsageEventRepository struct {
 	CreateFn           func(ctx context.Context, event *domain.UsageEvent) error
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -62,15 +63,17 @@ func (m *MockAgentAuthService) CreateAgent(ctx context.Context, userID string, n
 
 // MockMonitorService is a mock implementation of ports.MonitorService.
 type MockMonitorService struct {
-	CreateMonitorFn         func(ctx context.Context, userID uuid.UUID, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, metadata map[string]string) (*domain.Monitor, error)
-	GetMonitorFn            func(ctx context.Context, id uuid.UUID) (*domain.Monitor, error)
-	GetMonitorsByAgentFn    func(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
-	ListMonitorsByTagsFn    func(ctx context.Context, userID uuid.UUID, tags map[string]string) ([]*domain.Monitor, error)
-	UpdateMonitorFn         func(ctx context.Context, monitor *domain.Monitor) error
-	DeleteMonitorFn         func(ctx context.Context, id uuid.UUID) error
-	ProcessHeartbeatFn      func(ctx context.Context, heartbeat *domain.Heartbeat) error
-	MarkAgentMonitorsDownFn  func(ctx context.Context, agentID uuid.UUID) error
-	ResolveAgentMonitorsFn  func(ctx context.Context, agentID uuid.UUID) error
+	CreateMonitorFn             func(ctx context.Context, userID uuid.UUID, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, metadata map[string]string) (*domain.Monitor, error)
+	GetMonitorFn                func(ctx context.Context, id uuid.UUID) (*domain.Monitor, error)
+	GetMonitorsByAgentFn        func(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
+	GetEnabledMonitorsByAgentFn func(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error)
+	ListMonitorsByTagsFn        func(ctx context.Context, userID uuid.UUID, tags map[string]string) ([]*domain.Monitor, error)
+	UpdateMonitorFn             func(ctx context.Context, monitor *domain.Monitor) error
+	DeleteMonitorFn             func(ctx context.Context, id uuid.UUID) error
+	ProcessHeartbeatFn          func(ctx context.Context, heartbeat *domain.Heartbeat) error
+	EvaluateHeartbeatFn         func(ctx context.Context, heartbeat *domain.Heartbeat) error
+	MarkAgentMonitorsDownFn     func(ctx context.Context, agentID uuid.UUID) error
+	ResolveAgentMonitorsFn      func(ctx context.Context, agentID uuid.UUID) error
 }
 
 func (m *MockMonitorService) CreateMonitor(ctx context.Context, userID uuid.UUID, agentID uuid.UUID, name string, monitorType domain.MonitorType, target string, metadata map[string]string) (*domain.Monitor, error) {
@@ -101,6 +104,13 @@ func (m *MockMonitorService) GetMonitorsByAgent(ctx context.Context, agentID uui
 	return nil, nil
 }
 
+func (m *MockMonitorService) GetEnabledMonitorsByAgent(ctx context.Context, agentID uuid.UUID) ([]*domain.Monitor, error) {
+	if m.GetEnabledMonitorsByAgentFn != nil {
+		return m.GetEnabledMonitorsByAgentFn(ctx, agentID)
+	}
+	return nil, nil
+}
+
 func (m *MockMonitorService) UpdateMonitor(ctx context.Context, monitor *domain.Monitor) error {
 	if m.UpdateMonitorFn != nil {
 		return m.UpdateMonitorFn(ctx, monitor)
@@ -122,6 +132,13 @@ func (m *MockMonitorService) ProcessHeartbeat(ctx context.Context, heartbeat *do
 	return nil
 }
 
+func (m *MockMonitorService) EvaluateHeartbeat(ctx context.Context, heartbeat *domain.Heartbeat) error {
+	if m.EvaluateHeartbeatFn != nil {
+		return m.EvaluateHeartbeatFn(ctx, heartbeat)
+	}
+	return nil
+}
+
 func (m *MockMonitorService) MarkAgentMonitorsDown(ctx context.Context, agentID uuid.UUID) error {
 	if m.MarkAgentMonitorsDownFn != nil {
 		return m.MarkAgentMonitorsDownFn(ctx, agentID)
@@ -138,19 +155,26 @@ func (m *MockMonitorService) ResolveAgentMonitors(ctx context.Context, agentID u
 
 // MockIncidentService is a mock implementation of ports.IncidentService.
 type MockIncidentService struct {
-	GetIncidentFn            func(ctx context.Context, id uuid.UUID) (*domain.Incident, error)
-	GetActiveIncidentsFn     func(ctx context.Context) ([]*domain.Incident, error)
-	GetResolvedIncidentsFn   func(ctx context.Context) ([]*domain.Incident, error)
-	GetAllIncidentsFn        func(ctx context.Context) ([]*domain.Incident, error)
-	GetIncidentsByMonitorFn  func(ctx context.Context, monitorID uuid.UUID) ([]*domain.Incident, error)
-	AcknowledgeIncidentFn    func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
-	ResolveIncidentFn        func(ctx context.Context, id uuid.UUID) error
-	CreateIncidentIfNeededFn  func(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
-	CreateIncidentSilentlyFn  func(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
-	ResolveIncidentSilentlyFn func(ctx context.Context, id uuid.UUID) error
-	NotifyAgentOfflineFn       func(ctx context.Context, agentID uuid.UUID, affectedMonitors int)
-	NotifyAgentOnlineFn        func(ctx context.Context, agentID uuid.UUID, resolvedIncidents int)
-	NotifyAgentMaintenanceFn   func(ctx context.Context, agentID uuid.UUID, windowName string)
+	GetIncidentFn                    func(ctx context.Context, id uuid.UUID) (*domain.Incident, error)
+	GetActiveIncidentsFn             func(ctx context.Context) ([]*domain.Incident, error)
+	GetResolvedIncidentsFn           func(ctx context.Context) ([]*domain.Incident, error)
+	GetAllIncidentsFn                func(ctx context.Context) ([]*domain.Incident, error)
+	GetIncidentsByMonitorFn          func(ctx context.Context, monitorID uuid.UUID) ([]*domain.Incident, error)
+	AcknowledgeIncidentFn            func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	ResolveIncidentFn                func(ctx context.Context, id uuid.UUID) error
+	CreateIncidentIfNeededFn         func(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
+	CreateDegradedIncidentIfNeededFn func(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
+	CreateAnomalyIncidentIfNeededFn  func(ctx context.Context, monitorID uuid.UUID, notify bool) (*domain.Incident, error)
+	CreateIncidentSilentlyFn         func(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error)
+	ResolveIncidentSilentlyFn        func(ctx context.Context, id uuid.UUID) error
+	SetIncidentFlappingFn            func(ctx context.Context, id uuid.UUID, flapping bool) error
+	SetIncidentSeverityFn            func(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error
+	DeclareIncidentFn                func(ctx context.Context, incident *domain.Incident) error
+	SetIncidentOwnerFn               func(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error
+	GetDeclaredIncidentsAffectingFn  func(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error)
+	NotifyAgentOfflineFn             func(ctx context.Context, agentID uuid.UUID, affectedMonitors int)
+	NotifyAgentOnlineFn              func(ctx context.Context, agentID uuid.UUID, resolvedIncidents int)
+	NotifyAgentMaintenanceFn         func(ctx context.Context, agentID uuid.UUID, windowName string)
 }
 
 func (m *MockIncidentService) GetIncident(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
@@ -209,6 +233,20 @@ func (m *MockIncidentService) CreateIncidentIfNeeded(ctx context.Context, monito
 	return nil, nil
 }
 
+func (m *MockIncidentService) CreateDegradedIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error) {
+	if m.CreateDegradedIncidentIfNeededFn != nil {
+		return m.CreateDegradedIncidentIfNeededFn(ctx, monitorID)
+	}
+	return nil, nil
+}
+
+func (m *MockIncidentService) CreateAnomalyIncidentIfNeeded(ctx context.Context, monitorID uuid.UUID, notify bool) (*domain.Incident, error) {
+	if m.CreateAnomalyIncidentIfNeededFn != nil {
+		return m.CreateAnomalyIncidentIfNeededFn(ctx, monitorID, notify)
+	}
+	return nil, nil
+}
+
 func (m *MockIncidentService) CreateIncidentSilently(ctx context.Context, monitorID uuid.UUID) (*domain.Incident, error) {
 	if m.CreateIncidentSilentlyFn != nil {
 		return m.CreateIncidentSilentlyFn(ctx, monitorID)
@@ -223,6 +261,41 @@ func (m *MockIncidentService) ResolveIncidentSilently(ctx context.Context, id uu
 	return nil
 }
 
+func (m *MockIncidentService) SetIncidentFlapping(ctx context.Context, id uuid.UUID, flapping bool) error {
+	if m.SetIncidentFlappingFn != nil {
+		return m.SetIncidentFlappingFn(ctx, id, flapping)
+	}
+	return nil
+}
+
+func (m *MockIncidentService) SetIncidentSeverity(ctx context.Context, id uuid.UUID, severity domain.IncidentSeverity) error {
+	if m.SetIncidentSeverityFn != nil {
+		return m.SetIncidentSeverityFn(ctx, id, severity)
+	}
+	return nil
+}
+
+func (m *MockIncidentService) DeclareIncident(ctx context.Context, incident *domain.Incident) error {
+	if m.DeclareIncidentFn != nil {
+		return m.DeclareIncidentFn(ctx, incident)
+	}
+	return nil
+}
+
+func (m *MockIncidentService) SetIncidentOwner(ctx context.Context, id uuid.UUID, ownerID *uuid.UUID) error {
+	if m.SetIncidentOwnerFn != nil {
+		return m.SetIncidentOwnerFn(ctx, id, ownerID)
+	}
+	return nil
+}
+
+func (m *MockIncidentService) GetDeclaredIncidentsAffecting(ctx context.Context, monitorIDs []uuid.UUID, since time.Time) ([]*domain.Incident, error) {
+	if m.GetDeclaredIncidentsAffectingFn != nil {
+		return m.GetDeclaredIncidentsAffectingFn(ctx, monitorIDs, since)
+	}
+	return nil, nil
+}
+
 func (m *MockIncidentService) NotifyAgentOffline(ctx context.Context, agentID uuid.UUID, affectedMonitors int) {
 	if m.NotifyAgentOfflineFn != nil {
 		m.NotifyAgentOfflineFn(ctx, agentID, affectedMonitors)
@@ -259,6 +332,7 @@ type MockNotifier struct {
 	NotifyAgentOfflineFn      func(ctx context.Context, agent *domain.Agent, affectedMonitors int) error
 	NotifyAgentOnlineFn       func(ctx context.Context, agent *domain.Agent, resolvedIncidents int) error
 	NotifyAgentMaintenanceFn  func(ctx context.Context, agent *domain.Agent, windowName string) error
+	NotifyDigestFn            func(ctx context.Context, digest *domain.NotificationDigest) error
 }
 
 func (m *MockNotifier) NotifyIncidentOpened(ctx context.Context, incident *domain.Incident, monitor *domain.Monitor) error {
@@ -296,6 +370,13 @@ func (m *MockNotifier) NotifyAgentMaintenance(ctx context.Context, agent *domain
 	return nil
 }
 
+func (m *MockNotifier) NotifyDigest(ctx context.Context, digest *domain.NotificationDigest) error {
+	if m.NotifyDigestFn != nil {
+		return m.NotifyDigestFn(ctx, digest)
+	}
+	return nil
+}
+
 // MockNotifierFactory is a mock implementation of ports.NotifierFactory.
 type MockNotifierFactory struct {
 	BuildFromChannelFn func(channel *domain.AlertChannel) (ports.Notifier, error)
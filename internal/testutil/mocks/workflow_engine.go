@@ -0,0 +1,73 @@
+package mocks
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// Compile-time interface check.
+var _ ports.WorkflowEngine = (*MockWorkflowEngine)(nil)
+
+// MockWorkflowEngine is a mock implementation of ports.WorkflowEngine.
+type MockWorkflowEngine struct {
+	SubmitFn          func(ctx context.Context, def ports.WorkflowDefinition, input json.RawMessage) (uuid.UUID, error)
+	StatusFn          func(ctx context.Context, id uuid.UUID) (*domain.Workflow, error)
+	CancelFn          func(ctx context.Context, id uuid.UUID) error
+	RetryFn           func(ctx context.Context, id uuid.UUID) error
+	ListFn            func(ctx context.Context, status *domain.WorkflowStatus, limit int) ([]*domain.Workflow, error)
+	RegisterHandlerFn func(name string, handler ports.StepHandler)
+	ResumeStepFn      func(ctx context.Context, correlationKey string, output json.RawMessage, stepErr error) error
+}
+
+func (m *MockWorkflowEngine) Submit(ctx context.Context, def ports.WorkflowDefinition, input json.RawMessage) (uuid.UUID, error) {
+	if m.SubmitFn != nil {
+		return m.SubmitFn(ctx, def, input)
+	}
+	return uuid.New(), nil
+}
+
+func (m *MockWorkflowEngine) Status(ctx context.Context, id uuid.UUID) (*domain.Workflow, error) {
+	if m.StatusFn != nil {
+		return m.StatusFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockWorkflowEngine) Cancel(ctx context.Context, id uuid.UUID) error {
+	if m.CancelFn != nil {
+		return m.CancelFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockWorkflowEngine) Retry(ctx context.Context, id uuid.UUID) error {
+	if m.RetryFn != nil {
+		return m.RetryFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockWorkflowEngine) List(ctx context.Context, status *domain.WorkflowStatus, limit int) ([]*domain.Workflow, error) {
+	if m.ListFn != nil {
+		return m.ListFn(ctx, status, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockWorkflowEngine) RegisterHandler(name string, handler ports.StepHandler) {
+	if m.RegisterHandlerFn != nil {
+		m.RegisterHandlerFn(name, handler)
+	}
+}
+
+func (m *MockWorkflowEngine) ResumeStep(ctx context.Context, correlationKey string, output json.RawMessage, stepErr error) error {
+	if m.ResumeStepFn != nil {
+		return m.ResumeStepFn(ctx, correlationKey, output, stepErr)
+	}
+	return nil
+}
@@ -18,6 +18,8 @@ type AlertDispatchInput struct {
 	MonitorID  uuid.UUID `json:"monitor_id"`
 	AgentID    uuid.UUID `json:"agent_id"`
 	Opened     bool      `json:"opened"`
+	// Group is set when the dispatch announces an alert group.
+	Group *domain.AlertGroupSummary `json:"group,omitempty"`
 }
 
 // AlertDispatchDef returns the workflow definition for alert dispatch.
@@ -58,6 +60,7 @@ func RegisterAlertHandlers(
 	engine ports.WorkflowEngine,
 	notifier ports.Notifier,
 	notifierFactory ports.NotifierFactory,
+	alertRouter ports.AlertRouter,
 	agentRepo ports.AgentRepository,
 	heartbeatRepo ports.HeartbeatRepository,
 	alertChannelRepo ports.AlertChannelRepository,
@@ -66,6 +69,7 @@ func RegisterAlertHandlers(
 	logger *slog.Logger,
 ) {
 	engine.RegisterHandler("alert.resolve_channels", &resolveChannelsHandler{
+		alertRouter:      alertRouter,
 		agentRepo:        agentRepo,
 		heartbeatRepo:    heartbeatRepo,
 		alertChannelRepo: alertChannelRepo,
@@ -85,6 +89,10 @@ func RegisterAlertHandlers(
 	engine.RegisterHandler("alert.send_telegram", &sendChannelHandler{factory: notifierFactory, alertChannelRepo: alertChannelRepo, channelType: "telegram", logger: logger})
 	engine.RegisterHandler("alert.send_pagerduty", &sendChannelHandler{factory: notifierFactory, alertChannelRepo: alertChannelRepo, channelType: "pagerduty", logger: logger})
 	engine.RegisterHandler("alert.send_webhook", &sendChannelHandler{factory: notifierFactory, alertChannelRepo: alertChannelRepo, channelType: "webhook", logger: logger})
+	engine.RegisterHandler("alert.send_teams", &sendChannelHandler{factory: notifierFactory, alertChannelRepo: alertChannelRepo, channelType: "teams", logger: logger})
+	engine.RegisterHandler("alert.send_mattermost", &sendChannelHandler{factory: notifierFactory, alertChannelRepo: alertChannelRepo, channelType: "mattermost", logger: logger})
+	engine.RegisterHandler("alert.send_opsgenie", &sendChannelHandler{factory: notifierFactory, alertChannelRepo: alertChannelRepo, channelType: "opsgenie", logger: logger})
+	engine.RegisterHandler("alert.send_splunk_oncall", &sendChannelHandler{factory: notifierFactory, alertChannelRepo: alertChannelRepo, channelType: "splunk_oncall", logger: logger})
 
 	engine.RegisterHandler("alert.record_dispatch", &recordDispatchHandler{logger: logger})
 }
@@ -99,10 +107,19 @@ type resolveChannelsPayload struct {
 	Incident   *domain.Incident `json:"incident"`
 	Monitor    *domain.Monitor  `json:"monitor"`
 	ChannelIDs []uuid.UUID      `json:"channel_ids"`
+	// AlertContext is carried separately: Incident does not serialize it.
+	AlertContext *domain.AlertContext `json:"alert_context,omitempty"`
+}
+
+// incident returns the payload's incident with its alert context attached.
+func (p *resolveChannelsPayload) incident() *domain.Incident {
+	p.Incident.AlertContext = p.AlertContext
+	return p.Incident
 }
 
 // resolveChannelsHandler looks up the incident, monitor, and alert channels.
 type resolveChannelsHandler struct {
+	alertRouter      ports.AlertRouter // optional: routing rules pick the channels
 	agentRepo        ports.AgentRepository
 	heartbeatRepo    ports.HeartbeatRepository
 	alertChannelRepo ports.AlertChannelRepository
@@ -124,6 +141,9 @@ func (h *resolveChannelsHandler) Execute(ctx context.Context, input json.RawMess
 	if incident == nil {
 		return nil, fmt.Errorf("resolve_channels: incident %s not found", in.IncidentID)
 	}
+	if incident.IsDeclared() {
+		return h.resolveDeclared(ctx, in, incident)
+	}
 
 	monitor, err := h.monitorRepo.GetByID(ctx, in.MonitorID)
 	if err != nil {
@@ -138,14 +158,9 @@ func (h *resolveChannelsHandler) Execute(ctx context.Context, input json.RawMess
 		return nil, fmt.Errorf("resolve_channels: get agent %s: %w", in.AgentID, err)
 	}
 
-	channels, err := h.alertChannelRepo.GetEnabledByUserID(ctx, agent.UserID)
+	channelIDs, err := h.channelIDs(ctx, domain.NewIncidentAlertEvent(agent.UserID, incident, monitor, in.Opened))
 	if err != nil {
-		return nil, fmt.Errorf("resolve_channels: get channels: %w", err)
-	}
-
-	channelIDs := make([]uuid.UUID, len(channels))
-	for i, ch := range channels {
-		channelIDs[i] = ch.ID
+		return nil, err
 	}
 
 	// Populate AlertContext for notifiers
@@ -153,6 +168,8 @@ func (h *resolveChannelsHandler) Execute(ctx context.Context, input json.RawMess
 		AgentName: agent.Name,
 		Interval:  monitor.IntervalSeconds,
 		Threshold: monitor.FailureThreshold,
+		Severity:  incident.Severity,
+		Group:     in.Group,
 	}
 	if hb, hbErr := h.heartbeatRepo.GetLatestByMonitorID(ctx, monitor.ID); hbErr == nil && hb != nil {
 		if hb.ErrorMessage != nil {
@@ -163,17 +180,71 @@ func (h *resolveChannelsHandler) Execute(ctx context.Context, input json.RawMess
 	incident.AlertContext = actx
 
 	payload := resolveChannelsPayload{
-		IncidentID: in.IncidentID,
-		MonitorID:  in.MonitorID,
-		Opened:     in.Opened,
-		Incident:   incident,
-		Monitor:    monitor,
-		ChannelIDs: channelIDs,
+		IncidentID:   in.IncidentID,
+		MonitorID:    in.MonitorID,
+		Opened:       in.Opened,
+		Incident:     incident,
+		Monitor:      monitor,
+		ChannelIDs:   channelIDs,
+		AlertContext: actx,
 	}
 
 	return json.Marshal(payload)
 }
 
+// resolveDeclared resolves the channels of a declared incident, which has
+// no monitor or agent: they are the declaring user's, and the incident's
+// stand-in monitor renders the notifications.
+func (h *resolveChannelsHandler) resolveDeclared(ctx context.Context, in AlertDispatchInput, incident *domain.Incident) (json.RawMessage, error) {
+	if incident.UserID == nil {
+		return nil, fmt.Errorf("resolve_channels: declared incident %s has no user", incident.ID)
+	}
+	monitor := incident.DeclaredMonitor()
+	channelIDs, err := h.channelIDs(ctx, domain.NewIncidentAlertEvent(*incident.UserID, incident, monitor, in.Opened))
+	if err != nil {
+		return nil, err
+	}
+
+	actx := &domain.AlertContext{Severity: incident.Severity}
+	incident.AlertContext = actx
+
+	return json.Marshal(resolveChannelsPayload{
+		IncidentID:   in.IncidentID,
+		Opened:       in.Opened,
+		Incident:     incident,
+		Monitor:      monitor,
+		ChannelIDs:   channelIDs,
+		AlertContext: actx,
+	})
+}
+
+// channelIDs returns the channels an event goes to: those the alert router
+// picks, or every enabled channel of the user without one.
+func (h *resolveChannelsHandler) channelIDs(ctx context.Context, event *domain.AlertEvent) ([]uuid.UUID, error) {
+	var channels []*domain.AlertChannel
+	if h.alertRouter != nil {
+		routing, err := h.alertRouter.RouteChannels(ctx, event)
+		if err != nil {
+			return nil, fmt.Errorf("resolve_channels: route: %w", err)
+		}
+		if routing.Suppressed {
+			h.logger.Info("alert suppressed by routing rule", slog.String("user_id", event.UserID.String()), slog.String("event", string(event.Kind)))
+		}
+		channels = routing.Channels
+	} else {
+		var err error
+		if channels, err = h.alertChannelRepo.GetEnabledByUserID(ctx, event.UserID); err != nil {
+			return nil, fmt.Errorf("resolve_channels: get channels: %w", err)
+		}
+	}
+
+	ids := make([]uuid.UUID, len(channels))
+	for i, ch := range channels {
+		ids[i] = ch.ID
+	}
+	return ids, nil
+}
+
 // sendGlobalHandler sends via the global (env-based) notifier.
 type sendGlobalHandler struct {
 	notifier ports.Notifier
@@ -188,9 +259,9 @@ func (h *sendGlobalHandler) Execute(ctx context.Context, input json.RawMessage)
 
 	var err error
 	if payload.Opened {
-		err = h.notifier.NotifyIncidentOpened(ctx, payload.Incident, payload.Monitor)
+		err = h.notifier.NotifyIncidentOpened(ctx, payload.incident(), payload.Monitor)
 	} else {
-		err = h.notifier.NotifyIncidentResolved(ctx, payload.Incident, payload.Monitor)
+		err = h.notifier.NotifyIncidentResolved(ctx, payload.incident(), payload.Monitor)
 	}
 	if err != nil {
 		h.logger.Error("global notification failed", slog.String("error", err.Error()))
@@ -241,9 +312,9 @@ func (h *sendChannelHandler) Execute(ctx context.Context, input json.RawMessage)
 
 		var notifyErr error
 		if payload.Opened {
-			notifyErr = notifier.NotifyIncidentOpened(ctx, payload.Incident, payload.Monitor)
+			notifyErr = notifier.NotifyIncidentOpened(ctx, payload.incident(), payload.Monitor)
 		} else {
-			notifyErr = notifier.NotifyIncidentResolved(ctx, payload.Incident, payload.Monitor)
+			notifyErr = notifier.NotifyIncidentResolved(ctx, payload.incident(), payload.Monitor)
 		}
 		if notifyErr != nil {
 			h.logger.Error("channel notification failed",
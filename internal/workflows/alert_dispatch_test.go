@@ -60,6 +60,7 @@ func TestResolveChannelsHandler_Success(t *testing.T) {
 		engine,
 		&mocks.MockNotifier{},
 		&mocks.MockNotifierFactory{},
+		nil,
 		&mocks.MockAgentRepository{
 			GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Agent, error) {
 				assert.Equal(t, agentID, id)
@@ -123,6 +124,7 @@ func TestSendGlobalHandler_Opened(t *testing.T) {
 			},
 		},
 		&mocks.MockNotifierFactory{},
+		nil,
 		&mocks.MockAgentRepository{},
 		&mocks.MockHeartbeatRepository{},
 		&mocks.MockAlertChannelRepository{},
@@ -156,6 +158,7 @@ func TestRecordDispatchHandler_LogsCompletion(t *testing.T) {
 		engine,
 		&mocks.MockNotifier{},
 		&mocks.MockNotifierFactory{},
+		nil,
 		&mocks.MockAgentRepository{},
 		&mocks.MockHeartbeatRepository{},
 		&mocks.MockAlertChannelRepository{},
@@ -189,6 +192,7 @@ func TestAllHandlersRegistered(t *testing.T) {
 		engine,
 		&mocks.MockNotifier{},
 		&mocks.MockNotifierFactory{},
+		nil,
 		&mocks.MockAgentRepository{},
 		&mocks.MockHeartbeatRepository{},
 		&mocks.MockAlertChannelRepository{},
@@ -206,6 +210,10 @@ func TestAllHandlersRegistered(t *testing.T) {
 		"alert.send_telegram",
 		"alert.send_pagerduty",
 		"alert.send_webhook",
+		"alert.send_teams",
+		"alert.send_mattermost",
+		"alert.send_opsgenie",
+		"alert.send_splunk_oncall",
 		"alert.record_dispatch",
 	}
 
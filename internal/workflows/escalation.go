@@ -0,0 +1,304 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+)
+
+// escalationTimeoutMargin is added to the sum of a policy's delays to give
+// the paging steps time to run before the workflow times out.
+const escalationTimeoutMargin = time.Hour
+
+// EscalationInput is the input to the escalation workflow. Every step
+// receives it unchanged; the escalation's progress is kept in the
+// incident_escalations row so it survives hub restarts.
+type EscalationInput struct {
+	IncidentID uuid.UUID `json:"incident_id"`
+	MonitorID  uuid.UUID `json:"monitor_id"`
+}
+
+// EscalationCorrelationKey returns the correlation key of the step that
+// waits out the delay before the given escalation step is paged.
+func EscalationCorrelationKey(incidentID uuid.UUID, step int) string {
+	return fmt.Sprintf("escalation:%s:%d", incidentID, step)
+}
+
+// EscalationDef returns the workflow definition that pages an incident's
+// escalation steps in order. Steps with a delay are preceded by a waiting
+// step that is resumed once the delay has passed. levelsPerRound is the
+// number of levels in the policy, used to name the steps.
+func EscalationDef(escalation *domain.Escalation, levelsPerRound int) ports.WorkflowDefinition {
+	var steps []ports.StepDefinition
+	var total time.Duration
+	for i, level := range escalation.Steps {
+		name := fmt.Sprintf("level %d", i+1)
+		if levelsPerRound > 0 {
+			name = fmt.Sprintf("level %d (round %d)", i%levelsPerRound+1, i/levelsPerRound+1)
+		}
+		if level.DelayMinutes > 0 {
+			total += level.Delay()
+			steps = append(steps, ports.StepDefinition{
+				Name:           fmt.Sprintf("Wait %dm before %s", level.DelayMinutes, name),
+				Handler:        "escalation.wait",
+				OnFailure:      domain.FailurePolicyAbort,
+				CorrelationKey: EscalationCorrelationKey(escalation.IncidentID, i),
+			})
+		}
+		steps = append(steps, ports.StepDefinition{
+			Name:      "Page " + name,
+			Handler:   "escalation.page",
+			OnFailure: domain.FailurePolicyAbort,
+		})
+	}
+
+	steps = append(steps, ports.StepDefinition{
+		Name:      "Finish Escalation",
+		Handler:   "escalation.finish",
+		OnFailure: domain.FailurePolicySkip,
+	})
+
+	return ports.WorkflowDefinition{
+		Name:       "escalation",
+		Timeout:    int((total + escalationTimeoutMargin).Seconds()),
+		MaxRetries: 1,
+		Steps:      steps,
+	}
+}
+
+// RegisterEscalationHandlers registers the escalation step handlers with the workflow engine.
+func RegisterEscalationHandlers(
+	engine ports.WorkflowEngine,
+	escalationRepo ports.EscalationRepository,
+	incidentRepo ports.IncidentRepository,
+	monitorRepo ports.MonitorRepository,
+	alertChannelRepo ports.AlertChannelRepository,
+	notifierFactory ports.NotifierFactory,
+	onCall ports.OnCallService,
+	logger *slog.Logger,
+) {
+	engine.RegisterHandler("escalation.wait", &escalationWaitHandler{
+		escalationRepo: escalationRepo,
+		logger:         logger,
+	})
+
+	engine.RegisterHandler("escalation.page", &escalationPageHandler{
+		escalationRepo:   escalationRepo,
+		incidentRepo:     incidentRepo,
+		monitorRepo:      monitorRepo,
+		alertChannelRepo: alertChannelRepo,
+		factory:          notifierFactory,
+		onCall:           onCall,
+		logger:           logger,
+	})
+
+	engine.RegisterHandler("escalation.finish", &escalationFinishHandler{
+		escalationRepo: escalationRepo,
+	})
+}
+
+// escalationWaitHandler records when the next level is due and parks the
+// workflow until the escalation ticker resumes it. An escalation that was
+// stopped in the meantime falls through without waiting.
+type escalationWaitHandler struct {
+	escalationRepo ports.EscalationRepository
+	logger         *slog.Logger
+}
+
+func (h *escalationWaitHandler) Execute(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+	var in EscalationInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, fmt.Errorf("escalation.wait: unmarshal: %w", err)
+	}
+
+	esc, err := h.escalationRepo.GetEscalation(ctx, in.IncidentID)
+	if err != nil {
+		return nil, fmt.Errorf("escalation.wait: get escalation: %w", err)
+	}
+	if esc == nil {
+		return input, nil
+	}
+	level, ok := esc.Current()
+	if !ok {
+		return input, nil
+	}
+
+	resumeAt := time.Now().Add(level.Delay())
+	esc.ResumeAt = &resumeAt
+	if err := h.escalationRepo.UpdateEscalation(ctx, esc); err != nil {
+		return nil, fmt.Errorf("escalation.wait: update escalation: %w", err)
+	}
+
+	h.logger.Info("escalation waiting for acknowledgement",
+		slog.String("incident_id", in.IncidentID.String()),
+		slog.Int("step", esc.Step),
+		slog.Time("resume_at", resumeAt),
+	)
+
+	return nil, ports.ErrStepAwaiting
+}
+
+// escalationPageHandler pages the escalation's current level and moves on
+// to the next one. Nothing is sent once the incident is no longer open.
+type escalationPageHandler struct {
+	escalationRepo   ports.EscalationRepository
+	incidentRepo     ports.IncidentRepository
+	monitorRepo      ports.MonitorRepository
+	alertChannelRepo ports.AlertChannelRepository
+	factory          ports.NotifierFactory
+	onCall           ports.OnCallService // optional: resolves schedule targets
+	logger           *slog.Logger
+}
+
+func (h *escalationPageHandler) Execute(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+	var in EscalationInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, fmt.Errorf("escalation.page: unmarshal: %w", err)
+	}
+
+	esc, err := h.escalationRepo.GetEscalation(ctx, in.IncidentID)
+	if err != nil {
+		return nil, fmt.Errorf("escalation.page: get escalation: %w", err)
+	}
+	if esc == nil {
+		return input, nil
+	}
+	level, ok := esc.Current()
+	if !ok {
+		return input, nil
+	}
+
+	incident, err := h.incidentRepo.GetByID(ctx, in.IncidentID)
+	if err != nil {
+		return nil, fmt.Errorf("escalation.page: get incident: %w", err)
+	}
+	if incident == nil || !incident.IsOpen() {
+		// Acknowledged or resolved without the escalation being stopped:
+		// forget it so the remaining steps fall through.
+		if err := h.escalationRepo.DeleteEscalation(ctx, in.IncidentID); err != nil {
+			return nil, fmt.Errorf("escalation.page: delete escalation: %w", err)
+		}
+		return input, nil
+	}
+	monitor, err := h.monitorRepo.GetByID(ctx, in.MonitorID)
+	if err != nil || monitor == nil {
+		return nil, fmt.Errorf("escalation.page: get monitor %s: %w", in.MonitorID, err)
+	}
+	incident.AlertContext = &domain.AlertContext{
+		Interval:  monitor.IntervalSeconds,
+		Threshold: monitor.FailureThreshold,
+		Severity:  incident.Severity,
+	}
+
+	sent := 0
+	for _, ch := range h.levelChannels(ctx, level) {
+		notifier, err := h.factory.BuildFromChannel(ch)
+		if err != nil {
+			h.logger.Error("failed to build escalation notifier",
+				slog.String("channel_id", ch.ID.String()),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if err := notifier.NotifyIncidentOpened(ctx, incident, monitor); err != nil {
+			h.logger.Error("escalation notification failed",
+				slog.String("channel_id", ch.ID.String()),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		sent++
+	}
+
+	esc.Step++
+	esc.ResumeAt = nil
+	if err := h.escalationRepo.UpdateEscalation(ctx, esc); err != nil {
+		return nil, fmt.Errorf("escalation.page: update escalation: %w", err)
+	}
+
+	h.logger.Info("escalation level paged",
+		slog.String("incident_id", in.IncidentID.String()),
+		slog.Int("step", esc.Step),
+		slog.Int("channels", sent),
+	)
+
+	return input, nil
+}
+
+// levelChannels returns the enabled channels a level pages: its own
+// channels plus every enabled channel of its users and of whoever is on
+// call for its schedules, without duplicates.
+func (h *escalationPageHandler) levelChannels(ctx context.Context, level domain.EscalationLevel) []*domain.AlertChannel {
+	seen := make(map[uuid.UUID]bool)
+	var channels []*domain.AlertChannel
+	add := func(ch *domain.AlertChannel) {
+		if ch == nil || !ch.Enabled || seen[ch.ID] {
+			return
+		}
+		seen[ch.ID] = true
+		channels = append(channels, ch)
+	}
+
+	for _, id := range level.ChannelIDs {
+		ch, err := h.alertChannelRepo.GetByID(ctx, id)
+		if err != nil {
+			h.logger.Error("failed to fetch escalation channel",
+				slog.String("channel_id", id.String()),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		add(ch)
+	}
+	userIDs := append([]uuid.UUID(nil), level.UserIDs...)
+	for _, scheduleID := range level.ScheduleIDs {
+		if h.onCall == nil {
+			break
+		}
+		shift, err := h.onCall.WhoIsOnCall(ctx, scheduleID, time.Now())
+		if err != nil || shift == nil {
+			h.logger.Warn("nobody on call for escalation schedule",
+				slog.String("schedule_id", scheduleID.String()),
+			)
+			continue
+		}
+		userIDs = append(userIDs, shift.UserID)
+	}
+	for _, userID := range userIDs {
+		userChannels, err := h.alertChannelRepo.GetEnabledByUserID(ctx, userID)
+		if err != nil {
+			h.logger.Error("failed to fetch escalation user channels",
+				slog.String("user_id", userID.String()),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		for _, ch := range userChannels {
+			add(ch)
+		}
+	}
+	return channels
+}
+
+// escalationFinishHandler forgets the escalation once every level was paged.
+type escalationFinishHandler struct {
+	escalationRepo ports.EscalationRepository
+}
+
+func (h *escalationFinishHandler) Execute(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+	var in EscalationInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, fmt.Errorf("escalation.finish: unmarshal: %w", err)
+	}
+	if err := h.escalationRepo.DeleteEscalation(ctx, in.IncidentID); err != nil {
+		return nil, fmt.Errorf("escalation.finish: %w", err)
+	}
+	return input, nil
+}
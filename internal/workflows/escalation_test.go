@@ -0,0 +1,137 @@
+package workflows_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sylvester-francis/watchdog/core/domain"
+	"github.com/sylvester-francis/watchdog/core/ports"
+	"github.com/sylvester-francis/watchdog/internal/testutil/mocks"
+	"github.com/sylvester-francis/watchdog/internal/workflows"
+)
+
+func TestEscalationDef_WaitsBeforeDelayedLevels(t *testing.T) {
+	policy := &domain.EscalationPolicy{
+		Levels: []domain.EscalationLevel{
+			{DelayMinutes: 10, ChannelIDs: []uuid.UUID{uuid.New()}},
+			{DelayMinutes: 0, ChannelIDs: []uuid.UUID{uuid.New()}},
+		},
+		Repeat: 1,
+	}
+	esc := domain.NewEscalation(uuid.New(), policy)
+	def := workflows.EscalationDef(esc, len(policy.Levels))
+
+	// wait+page, page, wait+page, page, finish
+	require.Len(t, def.Steps, 7)
+	assert.Equal(t, "escalation.wait", def.Steps[0].Handler)
+	assert.Equal(t, workflows.EscalationCorrelationKey(esc.IncidentID, 0), def.Steps[0].CorrelationKey)
+	assert.Equal(t, "Page level 1 (round 1)", def.Steps[1].Name)
+	assert.Equal(t, "Page level 2 (round 1)", def.Steps[2].Name)
+	assert.Equal(t, workflows.EscalationCorrelationKey(esc.IncidentID, 2), def.Steps[3].CorrelationKey)
+	assert.Equal(t, "Page level 2 (round 2)", def.Steps[5].Name)
+	assert.Equal(t, "escalation.finish", def.Steps[6].Handler)
+	assert.Equal(t, 20*60+3600, def.Timeout)
+}
+
+func TestEscalationPageHandler_PagesLevelAndAdvances(t *testing.T) {
+	incidentID, monitorID, userID := uuid.New(), uuid.New(), uuid.New()
+	channelID := uuid.New()
+	esc := &domain.Escalation{
+		IncidentID: incidentID,
+		Steps:      []domain.EscalationLevel{{ChannelIDs: []uuid.UUID{channelID}, UserIDs: []uuid.UUID{userID}}},
+	}
+
+	var updated *domain.Escalation
+	escalationRepo := &mocks.MockEscalationRepository{
+		GetEscalationFn: func(_ context.Context, _ uuid.UUID) (*domain.Escalation, error) {
+			return esc, nil
+		},
+		UpdateEscalationFn: func(_ context.Context, e *domain.Escalation) error {
+			updated = e
+			return nil
+		},
+	}
+	channelRepo := &mocks.MockAlertChannelRepository{
+		GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.AlertChannel, error) {
+			return &domain.AlertChannel{ID: id, Enabled: true}, nil
+		},
+		GetEnabledByUserIDFn: func(_ context.Context, _ uuid.UUID) ([]*domain.AlertChannel, error) {
+			// The level's own channel is also one of the user's: paged once.
+			return []*domain.AlertChannel{{ID: channelID, Enabled: true}, {ID: uuid.New(), Enabled: true}}, nil
+		},
+	}
+	paged := 0
+	factory := &mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(_ *domain.AlertChannel) (ports.Notifier, error) {
+			return &mocks.MockNotifier{
+				NotifyIncidentOpenedFn: func(_ context.Context, _ *domain.Incident, _ *domain.Monitor) error {
+					paged++
+					return nil
+				},
+			}, nil
+		},
+	}
+
+	engine := &mockWorkflowEngine{handlers: make(map[string]ports.StepHandler)}
+	workflows.RegisterEscalationHandlers(engine, escalationRepo,
+		&mocks.MockIncidentRepository{
+			GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Incident, error) {
+				return &domain.Incident{ID: id, MonitorID: monitorID, Status: domain.IncidentStatusOpen}, nil
+			},
+		},
+		&mocks.MockMonitorRepository{
+			GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Monitor, error) {
+				return &domain.Monitor{ID: id}, nil
+			},
+		},
+		channelRepo, factory, nil, slog.Default(),
+	)
+
+	input, _ := json.Marshal(workflows.EscalationInput{IncidentID: incidentID, MonitorID: monitorID})
+	_, err := engine.handlers["escalation.page"].Execute(context.Background(), input)
+	require.NoError(t, err)
+	assert.Equal(t, 2, paged)
+	require.NotNil(t, updated)
+	assert.Equal(t, 1, updated.Step)
+}
+
+func TestEscalationPageHandler_AcknowledgedIncidentIsNotPaged(t *testing.T) {
+	incidentID := uuid.New()
+	deleted := false
+	escalationRepo := &mocks.MockEscalationRepository{
+		GetEscalationFn: func(_ context.Context, id uuid.UUID) (*domain.Escalation, error) {
+			return &domain.Escalation{IncidentID: id, Steps: []domain.EscalationLevel{{ChannelIDs: []uuid.UUID{uuid.New()}}}}, nil
+		},
+		DeleteEscalationFn: func(_ context.Context, _ uuid.UUID) error {
+			deleted = true
+			return nil
+		},
+	}
+	factory := &mocks.MockNotifierFactory{
+		BuildFromChannelFn: func(_ *domain.AlertChannel) (ports.Notifier, error) {
+			t.Fatal("acknowledged incidents must not be paged")
+			return nil, nil
+		},
+	}
+
+	engine := &mockWorkflowEngine{handlers: make(map[string]ports.StepHandler)}
+	workflows.RegisterEscalationHandlers(engine, escalationRepo,
+		&mocks.MockIncidentRepository{
+			GetByIDFn: func(_ context.Context, id uuid.UUID) (*domain.Incident, error) {
+				return &domain.Incident{ID: id, Status: domain.IncidentStatusAcknowledged}, nil
+			},
+		},
+		&mocks.MockMonitorRepository{}, &mocks.MockAlertChannelRepository{}, factory, nil, slog.Default(),
+	)
+
+	input, _ := json.Marshal(workflows.EscalationInput{IncidentID: incidentID})
+	_, err := engine.handlers["escalation.page"].Execute(context.Background(), input)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+}
@@ -0,0 +1,9 @@
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_kind;
+ALTER TABLE incidents DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_degraded_failure_percent;
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_degraded_latency_ms;
+ALTER TABLE monitors DROP COLUMN IF EXISTS degraded_window;
+ALTER TABLE monitors DROP COLUMN IF EXISTS degraded_failure_percent;
+ALTER TABLE monitors DROP COLUMN IF EXISTS degraded_latency_checks;
+ALTER TABLE monitors DROP COLUMN IF EXISTS degraded_latency_ms;
@@ -0,0 +1,10 @@
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS degraded_latency_ms INT;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS degraded_latency_checks INT NOT NULL DEFAULT 3;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS degraded_failure_percent INT;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS degraded_window INT NOT NULL DEFAULT 10;
+
+ALTER TABLE monitors ADD CONSTRAINT chk_degraded_latency_ms CHECK (degraded_latency_ms IS NULL OR degraded_latency_ms > 0);
+ALTER TABLE monitors ADD CONSTRAINT chk_degraded_failure_percent CHECK (degraded_failure_percent IS NULL OR degraded_failure_percent BETWEEN 1 AND 100);
+
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'down';
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_kind CHECK (kind IN ('down', 'degraded'));
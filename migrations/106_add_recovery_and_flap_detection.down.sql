@@ -0,0 +1,22 @@
+UPDATE incidents
+SET status = CASE WHEN acknowledged_at IS NOT NULL THEN 'acknowledged' ELSE 'open' END
+WHERE status = 'flapping';
+
+ALTER TABLE incidents DROP CONSTRAINT chk_acknowledged;
+ALTER TABLE incidents ADD CONSTRAINT chk_acknowledged
+    CHECK (
+        status = 'open'
+        OR (status = 'acknowledged' AND acknowledged_by IS NOT NULL AND acknowledged_at IS NOT NULL)
+        OR status = 'resolved'
+    );
+
+ALTER TABLE incidents DROP CONSTRAINT chk_incident_status;
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_status
+    CHECK (status IN ('open', 'acknowledged', 'resolved'));
+
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_flap_threshold_percent;
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_flap_window;
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_recovery_threshold;
+ALTER TABLE monitors DROP COLUMN IF EXISTS flap_threshold_percent;
+ALTER TABLE monitors DROP COLUMN IF EXISTS flap_window;
+ALTER TABLE monitors DROP COLUMN IF EXISTS recovery_threshold;
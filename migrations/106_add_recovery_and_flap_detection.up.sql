@@ -0,0 +1,20 @@
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS recovery_threshold INT NOT NULL DEFAULT 1;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS flap_window INT NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS flap_threshold_percent INT NOT NULL DEFAULT 50;
+
+ALTER TABLE monitors ADD CONSTRAINT chk_recovery_threshold CHECK (recovery_threshold BETWEEN 1 AND 20);
+ALTER TABLE monitors ADD CONSTRAINT chk_flap_window CHECK (flap_window = 0 OR flap_window BETWEEN 4 AND 100);
+ALTER TABLE monitors ADD CONSTRAINT chk_flap_threshold_percent CHECK (flap_threshold_percent BETWEEN 1 AND 100);
+
+-- Flapping incidents are still active but hold notifications until the monitor stabilises.
+ALTER TABLE incidents DROP CONSTRAINT chk_incident_status;
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_status
+    CHECK (status IN ('open', 'acknowledged', 'resolved', 'flapping'));
+
+ALTER TABLE incidents DROP CONSTRAINT chk_acknowledged;
+ALTER TABLE incidents ADD CONSTRAINT chk_acknowledged
+    CHECK (
+        status IN ('open', 'flapping')
+        OR (status = 'acknowledged' AND acknowledged_by IS NOT NULL AND acknowledged_at IS NOT NULL)
+        OR status = 'resolved'
+    );
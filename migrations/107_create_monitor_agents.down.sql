@@ -0,0 +1,4 @@
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_quorum;
+ALTER TABLE monitors DROP COLUMN IF EXISTS quorum;
+
+DROP TABLE IF EXISTS monitor_agents;
@@ -0,0 +1,13 @@
+-- Multi-location monitors: every agent listed here runs the monitor's check.
+-- The monitor's own agent_id remains the owner and is always part of the set.
+CREATE TABLE IF NOT EXISTS monitor_agents (
+    monitor_id UUID NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    agent_id   UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    sort_order INT  NOT NULL DEFAULT 0,
+    PRIMARY KEY (monitor_id, agent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_agents_agent ON monitor_agents(agent_id);
+
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS quorum INT NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD CONSTRAINT chk_quorum CHECK (quorum >= 0);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_incidents_parent;
+ALTER TABLE incidents DROP COLUMN IF EXISTS parent_incident_id;
+
+DROP TABLE IF EXISTS monitor_dependencies;
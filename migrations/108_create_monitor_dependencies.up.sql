@@ -0,0 +1,16 @@
+-- Parent/child dependency edges between monitors: monitor_id depends on parent_id.
+CREATE TABLE IF NOT EXISTS monitor_dependencies (
+    monitor_id UUID         NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    parent_id  UUID         NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    tenant_id  VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (monitor_id, parent_id),
+    CONSTRAINT chk_dependency_not_self CHECK (monitor_id <> parent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_dependencies_parent ON monitor_dependencies(parent_id);
+CREATE INDEX IF NOT EXISTS idx_monitor_dependencies_tenant ON monitor_dependencies(tenant_id);
+
+-- Suppressed sub-incidents point at the upstream incident that caused them.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS parent_incident_id UUID REFERENCES incidents(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_parent ON incidents(parent_incident_id) WHERE parent_incident_id IS NOT NULL;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_monitors_push_enabled;
+DROP INDEX IF EXISTS idx_monitors_push_token;
+
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_push_grace_seconds;
+ALTER TABLE monitors DROP COLUMN IF EXISTS push_started_at;
+ALTER TABLE monitors DROP COLUMN IF EXISTS last_ping_at;
+ALTER TABLE monitors DROP COLUMN IF EXISTS push_grace_seconds;
+ALTER TABLE monitors DROP COLUMN IF EXISTS push_token;
+
+DELETE FROM monitors WHERE type = 'push';
+ALTER TABLE monitors DROP CONSTRAINT chk_monitor_type;
+ALTER TABLE monitors ADD CONSTRAINT chk_monitor_type
+    CHECK (type IN ('ping','http','tcp','dns','tls','docker','database','system','service','port_scan','snmp'));
@@ -0,0 +1,16 @@
+ALTER TABLE monitors DROP CONSTRAINT chk_monitor_type;
+ALTER TABLE monitors ADD CONSTRAINT chk_monitor_type
+    CHECK (type IN ('ping','http','tcp','dns','tls','docker','database','system','service','port_scan','snmp','push'));
+
+-- Push monitors are pinged by the job they watch at /api/v1/push/:token.
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS push_token VARCHAR(64);
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS push_grace_seconds INT NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS last_ping_at TIMESTAMPTZ;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS push_started_at TIMESTAMPTZ;
+
+ALTER TABLE monitors ADD CONSTRAINT chk_push_grace_seconds
+    CHECK (push_grace_seconds BETWEEN 0 AND 86400);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_monitors_push_token ON monitors(push_token) WHERE push_token IS NOT NULL;
+-- Deadline scans only look at enabled push monitors.
+CREATE INDEX IF NOT EXISTS idx_monitors_push_enabled ON monitors(tenant_id) WHERE type = 'push' AND enabled = true;
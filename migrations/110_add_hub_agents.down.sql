@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_agents_hub;
+ALTER TABLE agents DROP COLUMN IF EXISTS is_hub;
@@ -0,0 +1,4 @@
+-- Hub agents are virtual: their http/tcp/dns/tls/ping checks run inside the hub.
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS is_hub BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_agents_hub ON agents(tenant_id) WHERE is_hub = true;
@@ -0,0 +1,2 @@
+-- HTTP assertions evaluated on the hub against reported check results.
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS assertions JSONB;
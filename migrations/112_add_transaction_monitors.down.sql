@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS monitor_transaction_versions;
+
+ALTER TABLE monitors DROP COLUMN IF EXISTS transaction_last_run;
+ALTER TABLE monitors DROP COLUMN IF EXISTS transaction;
+
+DELETE FROM monitors WHERE type = 'transaction';
+ALTER TABLE monitors DROP CONSTRAINT chk_monitor_type;
+ALTER TABLE monitors ADD CONSTRAINT chk_monitor_type
+    CHECK (type IN ('ping','http','tcp','dns','tls','docker','database','system','service','port_scan','snmp','push'));
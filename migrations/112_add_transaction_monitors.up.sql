@@ -0,0 +1,20 @@
+ALTER TABLE monitors DROP CONSTRAINT chk_monitor_type;
+ALTER TABLE monitors ADD CONSTRAINT chk_monitor_type
+    CHECK (type IN ('ping','http','tcp','dns','tls','docker','database','system','service','port_scan','snmp','push','transaction'));
+
+-- Current definition of a transaction monitor and its latest per-step results.
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS transaction JSONB;
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS transaction_last_run JSONB;
+
+-- Every definition a transaction monitor has had, so runs can be tied back
+-- to the steps they executed.
+CREATE TABLE IF NOT EXISTS monitor_transaction_versions (
+    monitor_id UUID         NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    version    INT          NOT NULL,
+    steps      JSONB        NOT NULL,
+    tenant_id  VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (monitor_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_transaction_versions_tenant ON monitor_transaction_versions(tenant_id);
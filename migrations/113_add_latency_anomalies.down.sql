@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS latency_anomalies;
+DROP TABLE IF EXISTS latency_baselines;
+
+UPDATE incidents SET kind = 'degraded' WHERE kind = 'anomaly';
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_kind;
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_kind CHECK (kind IN ('down', 'degraded'));
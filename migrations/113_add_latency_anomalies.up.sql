@@ -0,0 +1,37 @@
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_kind;
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_kind CHECK (kind IN ('down', 'degraded', 'anomaly'));
+
+-- Seasonal latency baseline per monitor: one row per hour of the week
+-- (0-167) plus an all-hours row (168).
+CREATE TABLE IF NOT EXISTS latency_baselines (
+    monitor_id   UUID             NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    hour_of_week SMALLINT         NOT NULL,
+    p50_mean     DOUBLE PRECISION NOT NULL,
+    p50_var      DOUBLE PRECISION NOT NULL,
+    p95_mean     DOUBLE PRECISION NOT NULL,
+    p95_var      DOUBLE PRECISION NOT NULL,
+    samples      INT              NOT NULL DEFAULT 0,
+    last_bucket  TIMESTAMPTZ      NOT NULL,
+    tenant_id    VARCHAR(255)     NOT NULL DEFAULT 'default',
+    PRIMARY KEY (monitor_id, hour_of_week),
+    CONSTRAINT chk_baseline_hour CHECK (hour_of_week BETWEEN 0 AND 168)
+);
+
+CREATE INDEX IF NOT EXISTS idx_latency_baselines_tenant ON latency_baselines(tenant_id);
+
+-- Every latency anomaly the detector raised.
+CREATE TABLE IF NOT EXISTS latency_anomalies (
+    id           UUID PRIMARY KEY,
+    monitor_id   UUID             NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    detected_at  TIMESTAMPTZ      NOT NULL,
+    hour_of_week SMALLINT         NOT NULL,
+    observed_p50 INT              NOT NULL,
+    observed_p95 INT              NOT NULL,
+    baseline_p95 INT              NOT NULL,
+    sigma        DOUBLE PRECISION NOT NULL,
+    incident_id  UUID             REFERENCES incidents(id) ON DELETE SET NULL,
+    tenant_id    VARCHAR(255)     NOT NULL DEFAULT 'default'
+);
+
+CREATE INDEX IF NOT EXISTS idx_latency_anomalies_monitor ON latency_anomalies(monitor_id, detected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_latency_anomalies_tenant ON latency_anomalies(tenant_id);
@@ -0,0 +1,7 @@
+DELETE FROM system_settings WHERE key IN ('heartbeat_retention_days', 'heartbeat_rollup_retention_days');
+
+SELECT add_retention_policy('heartbeats', INTERVAL '90 days', if_not_exists => true);
+
+DROP MATERIALIZED VIEW IF EXISTS heartbeats_1d;
+DROP MATERIALIZED VIEW IF EXISTS heartbeats_1h;
+DROP MATERIALIZED VIEW IF EXISTS heartbeats_1m;
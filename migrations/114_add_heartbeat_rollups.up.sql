@@ -0,0 +1,78 @@
+-- Continuous aggregates that downsample heartbeats for long-range charts and
+-- SLA reports. Each rollup is computed from the raw hypertable so its p95 is
+-- exact; real-time aggregation fills in the buckets not yet materialized.
+CREATE MATERIALIZED VIEW heartbeats_1m
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT time_bucket(INTERVAL '1 minute', time) AS bucket,
+    tenant_id,
+    monitor_id,
+    COUNT(*) FILTER (WHERE status = 'up') AS up_count,
+    COUNT(*) FILTER (WHERE status <> 'up') AS down_count,
+    COUNT(latency_ms) AS latency_count,
+    MIN(latency_ms) AS latency_min,
+    AVG(latency_ms) AS latency_avg,
+    MAX(latency_ms) AS latency_max,
+    percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms) AS latency_p95
+FROM heartbeats
+GROUP BY bucket, tenant_id, monitor_id
+WITH NO DATA;
+
+CREATE MATERIALIZED VIEW heartbeats_1h
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT time_bucket(INTERVAL '1 hour', time) AS bucket,
+    tenant_id,
+    monitor_id,
+    COUNT(*) FILTER (WHERE status = 'up') AS up_count,
+    COUNT(*) FILTER (WHERE status <> 'up') AS down_count,
+    COUNT(latency_ms) AS latency_count,
+    MIN(latency_ms) AS latency_min,
+    AVG(latency_ms) AS latency_avg,
+    MAX(latency_ms) AS latency_max,
+    percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms) AS latency_p95
+FROM heartbeats
+GROUP BY bucket, tenant_id, monitor_id
+WITH NO DATA;
+
+CREATE MATERIALIZED VIEW heartbeats_1d
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT time_bucket(INTERVAL '1 day', time) AS bucket,
+    tenant_id,
+    monitor_id,
+    COUNT(*) FILTER (WHERE status = 'up') AS up_count,
+    COUNT(*) FILTER (WHERE status <> 'up') AS down_count,
+    COUNT(latency_ms) AS latency_count,
+    MIN(latency_ms) AS latency_min,
+    AVG(latency_ms) AS latency_avg,
+    MAX(latency_ms) AS latency_max,
+    percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms) AS latency_p95
+FROM heartbeats
+GROUP BY bucket, tenant_id, monitor_id
+WITH NO DATA;
+
+-- Refresh windows stay well inside the minimum raw retention (7 days) so a
+-- refresh never recomputes a bucket whose raw rows were already pruned.
+SELECT add_continuous_aggregate_policy('heartbeats_1m',
+    start_offset => INTERVAL '2 hours',
+    end_offset => INTERVAL '1 minute',
+    schedule_interval => INTERVAL '1 minute');
+
+SELECT add_continuous_aggregate_policy('heartbeats_1h',
+    start_offset => INTERVAL '6 hours',
+    end_offset => INTERVAL '1 hour',
+    schedule_interval => INTERVAL '30 minutes');
+
+SELECT add_continuous_aggregate_policy('heartbeats_1d',
+    start_offset => INTERVAL '3 days',
+    end_offset => INTERVAL '1 day',
+    schedule_interval => INTERVAL '1 hour');
+
+-- Retention is now applied by the app from system_settings, separately for
+-- raw heartbeats and the rollups. Backfilling the rollups is left to the
+-- retention worker because refresh_continuous_aggregate cannot run inside
+-- the migration's transaction.
+SELECT remove_retention_policy('heartbeats', if_exists => true);
+
+INSERT INTO system_settings (key, value) VALUES
+    ('heartbeat_retention_days', '90'::jsonb),
+    ('heartbeat_rollup_retention_days', '400'::jsonb)
+ON CONFLICT (key) DO NOTHING;
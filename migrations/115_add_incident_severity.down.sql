@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_incidents_severity;
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_severity;
+ALTER TABLE incidents DROP COLUMN IF EXISTS severity;
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_monitor_severity;
+ALTER TABLE monitors DROP COLUMN IF EXISTS severity;
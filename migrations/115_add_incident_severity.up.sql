@@ -0,0 +1,11 @@
+-- Default severity for incidents opened by a monitor.
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS severity VARCHAR(20) NOT NULL DEFAULT 'critical';
+ALTER TABLE monitors DROP CONSTRAINT IF EXISTS chk_monitor_severity;
+ALTER TABLE monitors ADD CONSTRAINT chk_monitor_severity CHECK (severity IN ('critical', 'major', 'minor', 'info'));
+
+-- Severity of each incident, derived from its monitor and overridable.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS severity VARCHAR(20) NOT NULL DEFAULT 'critical';
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_severity;
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_severity CHECK (severity IN ('critical', 'major', 'minor', 'info'));
+
+CREATE INDEX IF NOT EXISTS idx_incidents_severity ON incidents(tenant_id, severity);
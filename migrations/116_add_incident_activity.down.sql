@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS incident_postmortems;
+DROP TABLE IF EXISTS incident_activity;
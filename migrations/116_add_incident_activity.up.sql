@@ -0,0 +1,31 @@
+-- Responder activity on incidents: notes, links and status changes.
+CREATE TABLE IF NOT EXISTS incident_activity (
+    id          UUID PRIMARY KEY,
+    incident_id UUID         NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    kind        VARCHAR(20)  NOT NULL,
+    author_id   UUID         REFERENCES users(id) ON DELETE SET NULL,
+    body        TEXT         NOT NULL DEFAULT '',
+    url         TEXT         NOT NULL DEFAULT '',
+    status      VARCHAR(20)  NOT NULL DEFAULT '',
+    tenant_id   VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_incident_activity_kind CHECK (kind IN ('note', 'link', 'status_change'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_activity_incident ON incident_activity(incident_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_incident_activity_tenant ON incident_activity(tenant_id);
+
+-- One structured postmortem per resolved incident.
+CREATE TABLE IF NOT EXISTS incident_postmortems (
+    incident_id  UUID PRIMARY KEY REFERENCES incidents(id) ON DELETE CASCADE,
+    root_cause   TEXT         NOT NULL,
+    impact       TEXT         NOT NULL,
+    action_items JSONB        NOT NULL DEFAULT '[]',
+    author_id    UUID         REFERENCES users(id) ON DELETE SET NULL,
+    tenant_id    VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_postmortems_tenant ON incident_postmortems(tenant_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS incident_escalations;
+ALTER TABLE monitors DROP COLUMN IF EXISTS escalation_policy_id;
+DROP TABLE IF EXISTS escalation_policies;
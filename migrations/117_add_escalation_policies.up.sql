@@ -0,0 +1,33 @@
+-- Escalation policies: ordered levels of channels and users paged while an
+-- incident stays unacknowledged.
+CREATE TABLE IF NOT EXISTS escalation_policies (
+    id         UUID PRIMARY KEY,
+    user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    levels     JSONB        NOT NULL DEFAULT '[]',
+    repeat     INTEGER      NOT NULL DEFAULT 0,
+    tenant_id  VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_escalation_policy_repeat CHECK (repeat BETWEEN 0 AND 5)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_escalation_policies_name ON escalation_policies(tenant_id, user_id, name);
+
+ALTER TABLE monitors ADD COLUMN IF NOT EXISTS escalation_policy_id UUID REFERENCES escalation_policies(id) ON DELETE SET NULL;
+
+-- Escalations in flight, one per open incident. The workflow running the
+-- escalation parks on a waiting step; resume_at is when it is due to page
+-- the next level.
+CREATE TABLE IF NOT EXISTS incident_escalations (
+    incident_id UUID PRIMARY KEY REFERENCES incidents(id) ON DELETE CASCADE,
+    policy_id   UUID         NOT NULL,
+    workflow_id UUID,
+    steps       JSONB        NOT NULL DEFAULT '[]',
+    step        INTEGER      NOT NULL DEFAULT 0,
+    resume_at   TIMESTAMPTZ,
+    tenant_id   VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_escalations_due ON incident_escalations(tenant_id, resume_at) WHERE resume_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS oncall_overrides;
+DROP TABLE IF EXISTS oncall_schedules;
@@ -0,0 +1,28 @@
+-- On-call schedules: layers of daily or weekly rotations, stored as JSON,
+-- evaluated in the schedule's timezone.
+CREATE TABLE IF NOT EXISTS oncall_schedules (
+    id         UUID PRIMARY KEY,
+    user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    timezone   VARCHAR(64)  NOT NULL DEFAULT 'UTC',
+    layers     JSONB        NOT NULL DEFAULT '[]',
+    tenant_id  VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oncall_schedules_user ON oncall_schedules(tenant_id, user_id);
+
+-- Overrides temporarily put a user on call, taking precedence over every layer.
+CREATE TABLE IF NOT EXISTS oncall_overrides (
+    id          UUID PRIMARY KEY,
+    schedule_id UUID         NOT NULL REFERENCES oncall_schedules(id) ON DELETE CASCADE,
+    user_id     UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at   TIMESTAMPTZ  NOT NULL,
+    ends_at     TIMESTAMPTZ  NOT NULL,
+    tenant_id   VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_oncall_override_range CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_oncall_overrides_schedule ON oncall_overrides(schedule_id, ends_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_incidents_alert_group;
+ALTER TABLE incidents DROP COLUMN IF EXISTS alert_group_id;
+DROP TABLE IF EXISTS alert_groups;
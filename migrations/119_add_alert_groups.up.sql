@@ -0,0 +1,24 @@
+-- Alert groups: incidents opened close together that share an agent,
+-- dependency, subnet or tag, announced by one notification and resolved
+-- together. keys holds the correlation keys of the group's incidents.
+CREATE TABLE IF NOT EXISTS alert_groups (
+    id               UUID PRIMARY KEY,
+    user_id          UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason           VARCHAR(20)  NOT NULL DEFAULT '',
+    title            VARCHAR(255) NOT NULL DEFAULT '',
+    keys             TEXT[]       NOT NULL DEFAULT '{}',
+    lead_incident_id UUID         REFERENCES incidents(id) ON DELETE SET NULL,
+    status           VARCHAR(20)  NOT NULL DEFAULT 'open',
+    tenant_id        VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    last_incident_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    notified_at      TIMESTAMPTZ,
+    resolved_at      TIMESTAMPTZ,
+    CONSTRAINT chk_alert_group_status CHECK (status IN ('open', 'resolved'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_groups_user ON alert_groups(tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_alert_groups_pending ON alert_groups(tenant_id, created_at) WHERE notified_at IS NULL AND status = 'open';
+
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS alert_group_id UUID REFERENCES alert_groups(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_alert_group ON incidents(alert_group_id) WHERE alert_group_id IS NOT NULL;
@@ -0,0 +1,11 @@
+DELETE FROM incidents WHERE kind = 'declared';
+DROP INDEX IF EXISTS idx_incidents_declared;
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_source;
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_kind;
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_kind CHECK (kind IN ('down', 'degraded', 'anomaly'));
+ALTER TABLE incidents DROP COLUMN IF EXISTS affected_monitor_ids;
+ALTER TABLE incidents DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE incidents DROP COLUMN IF EXISTS description;
+ALTER TABLE incidents DROP COLUMN IF EXISTS title;
+ALTER TABLE incidents DROP COLUMN IF EXISTS user_id;
+ALTER TABLE incidents ALTER COLUMN monitor_id SET NOT NULL;
@@ -0,0 +1,19 @@
+-- Declared incidents: opened by an operator for events no monitor detects,
+-- such as vendor outages or security events. They have no monitor and are
+-- owned by user_id instead. owner_id, the user responsible for an incident,
+-- applies to every incident.
+ALTER TABLE incidents ALTER COLUMN monitor_id DROP NOT NULL;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS title VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS affected_monitor_ids JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incident_kind;
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_kind CHECK (kind IN ('down', 'degraded', 'anomaly', 'declared'));
+ALTER TABLE incidents ADD CONSTRAINT chk_incident_source CHECK (
+    (kind = 'declared' AND monitor_id IS NULL AND user_id IS NOT NULL)
+    OR (kind <> 'declared' AND monitor_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_declared ON incidents(tenant_id, started_at DESC) WHERE kind = 'declared';
@@ -0,0 +1,18 @@
+-- Alert routing rules, evaluated per user in position order to pick the
+-- channels an alert goes to.
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id          UUID PRIMARY KEY,
+    user_id     UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    position    INTEGER      NOT NULL DEFAULT 0,
+    enabled     BOOLEAN      NOT NULL DEFAULT TRUE,
+    conditions  JSONB        NOT NULL DEFAULT '{}',
+    action      VARCHAR(20)  NOT NULL,
+    channel_ids JSONB        NOT NULL DEFAULT '[]',
+    tenant_id   VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_alert_rule_action CHECK (action IN ('route', 'suppress', 'continue'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_user ON alert_rules(tenant_id, user_id, position);
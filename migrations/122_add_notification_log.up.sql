@@ -0,0 +1,25 @@
+-- Notification log: one row per notification to a channel, including those
+-- dropped by a rate limit or deduplication. Rows awaiting a digest double as
+-- its queue. Rows of the global notifier have no channel or user.
+CREATE TABLE IF NOT EXISTS notification_log (
+    id            UUID PRIMARY KEY,
+    user_id       UUID         REFERENCES users(id) ON DELETE CASCADE,
+    channel_id    UUID,
+    channel_name  VARCHAR(255) NOT NULL DEFAULT '',
+    event         VARCHAR(30)  NOT NULL,
+    subject       VARCHAR(255) NOT NULL DEFAULT '',
+    incident_id   UUID,
+    severity      VARCHAR(20)  NOT NULL DEFAULT '',
+    dedup_key     VARCHAR(255) NOT NULL DEFAULT '',
+    status        VARCHAR(20)  NOT NULL,
+    error         TEXT         NOT NULL DEFAULT '',
+    reported      BOOLEAN      NOT NULL DEFAULT FALSE,
+    digest_due_at TIMESTAMPTZ,
+    tenant_id     VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_notification_log_status CHECK (status IN ('sent', 'failed', 'rate_limited', 'deduplicated', 'digest_pending', 'digested'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_log_channel ON notification_log(tenant_id, channel_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notification_log_user ON notification_log(tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notification_log_digest ON notification_log(tenant_id, digest_due_at) WHERE status = 'digest_pending';
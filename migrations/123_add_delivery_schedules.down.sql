@@ -0,0 +1,7 @@
+DELETE FROM notification_log WHERE status = 'quiet_hours';
+ALTER TABLE notification_log DROP CONSTRAINT IF EXISTS chk_notification_log_status;
+ALTER TABLE notification_log ADD CONSTRAINT chk_notification_log_status
+    CHECK (status IN ('sent', 'failed', 'rate_limited', 'deduplicated', 'digest_pending', 'digested'));
+
+DROP TABLE IF EXISTS deferred_notifications;
+ALTER TABLE alert_channels DROP COLUMN IF EXISTS delivery_schedule;
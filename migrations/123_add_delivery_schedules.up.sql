@@ -0,0 +1,22 @@
+-- Per-channel delivery schedules: weekly windows in a timezone outside of
+-- which events are dropped, deferred or limited to critical incidents.
+ALTER TABLE alert_channels ADD COLUMN IF NOT EXISTS delivery_schedule JSONB;
+
+-- Events held until their channel's next delivery window opens.
+CREATE TABLE IF NOT EXISTS deferred_notifications (
+    id         UUID PRIMARY KEY,
+    channel_id UUID        NOT NULL REFERENCES alert_channels(id) ON DELETE CASCADE,
+    event      VARCHAR(30) NOT NULL,
+    payload    JSONB       NOT NULL,
+    deliver_at TIMESTAMPTZ NOT NULL,
+    attempts   INTEGER     NOT NULL DEFAULT 0,
+    tenant_id  VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deferred_notifications_due ON deferred_notifications(tenant_id, deliver_at);
+
+-- Events dropped outside a delivery window are logged like other suppressions.
+ALTER TABLE notification_log DROP CONSTRAINT IF EXISTS chk_notification_log_status;
+ALTER TABLE notification_log ADD CONSTRAINT chk_notification_log_status
+    CHECK (status IN ('sent', 'failed', 'rate_limited', 'deduplicated', 'digest_pending', 'digested', 'quiet_hours'));
@@ -0,0 +1,19 @@
+-- Message templates override the built-in layout of a user's notifications,
+-- for one channel or for all channels of a type. An empty event matches every
+-- event without a template of its own.
+CREATE TABLE IF NOT EXISTS message_templates (
+    id           UUID PRIMARY KEY,
+    user_id      UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel_type VARCHAR(20)  NOT NULL,
+    channel_id   UUID         REFERENCES alert_channels(id) ON DELETE CASCADE,
+    event        VARCHAR(30)  NOT NULL DEFAULT '',
+    subject      TEXT         NOT NULL DEFAULT '',
+    body         TEXT         NOT NULL,
+    tenant_id    VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_templates_scope ON message_templates(
+    tenant_id, user_id, channel_type, COALESCE(channel_id, '00000000-0000-0000-0000-000000000000'::uuid), event
+);
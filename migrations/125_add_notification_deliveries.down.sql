@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notification_attempts;
+DROP TABLE IF EXISTS notification_deliveries;
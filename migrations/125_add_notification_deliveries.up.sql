@@ -0,0 +1,40 @@
+-- Every notification sent to a channel, with its retry state. Failed
+-- deliveries are retried with backoff and then dead-lettered.
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id              UUID PRIMARY KEY,
+    user_id         UUID REFERENCES users(id) ON DELETE CASCADE,
+    channel_id      UUID REFERENCES alert_channels(id) ON DELETE SET NULL,
+    channel_name    VARCHAR(255) NOT NULL DEFAULT '',
+    channel_type    VARCHAR(30)  NOT NULL DEFAULT '',
+    event           VARCHAR(30)  NOT NULL,
+    subject         VARCHAR(255) NOT NULL DEFAULT '',
+    incident_id     UUID,
+    payload         JSONB        NOT NULL,
+    status          VARCHAR(20)  NOT NULL,
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_error      TEXT         NOT NULL DEFAULT '',
+    tenant_id       VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_notification_deliveries_status CHECK (status IN ('delivered', 'retrying', 'dead'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_user ON notification_deliveries(tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_due ON notification_deliveries(tenant_id, next_attempt_at)
+    WHERE status = 'retrying';
+
+-- Each attempt to send a delivery.
+CREATE TABLE IF NOT EXISTS notification_attempts (
+    id           UUID PRIMARY KEY,
+    delivery_id  UUID        NOT NULL REFERENCES notification_deliveries(id) ON DELETE CASCADE,
+    attempt      INTEGER     NOT NULL,
+    payload_hash VARCHAR(64) NOT NULL DEFAULT '',
+    status_code  INTEGER     NOT NULL DEFAULT 0,
+    error        TEXT        NOT NULL DEFAULT '',
+    latency_ms   INTEGER     NOT NULL DEFAULT 0,
+    tenant_id    VARCHAR(255) NOT NULL DEFAULT 'default',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_attempts_delivery ON notification_attempts(delivery_id, attempt);
@@ -21,6 +21,12 @@ export function createSSE(onEvent: SSECallback) {
 			} catch { /* ignore parse errors */ }
 		});
 
+		eventSource.addEventListener('monitor-status', (e) => {
+			try {
+				onEvent('monitor-status', JSON.parse(e.data));
+			} catch { /* ignore parse errors */ }
+		});
+
 		eventSource.addEventListener('incident-count', (e) => {
 			try {
 				onEvent('incident-count', JSON.parse(e.data));
@@ -11,6 +11,7 @@ export interface Agent {
 	id: string;
 	name: string;
 	status: 'online' | 'offline';
+	hub?: boolean;
 	last_seen_at: string | null;
 	created_at: string;
 }
@@ -29,10 +30,112 @@ export interface Monitor {
 	failure_threshold: number;
 	metadata?: Record<string, string>;
 	sla_target_percent?: number;
+	degraded?: DegradedRules;
+	recovery_threshold?: number;
+	flap_detection?: FlapDetection;
+	locations?: MonitorLocations;
+	push?: PushSettings;
+	assertions?: Assertion[];
+	transaction?: TransactionDefinition;
+	severity?: IncidentSeverity;
 	created_at: string;
 }
 
-export type MonitorType = 'ping' | 'http' | 'tcp' | 'dns' | 'tls' | 'docker' | 'database' | 'system' | 'service' | 'port_scan' | 'snmp';
+export interface TransactionExtraction {
+	variable: string;
+	source: 'json_path' | 'header' | 'regex';
+	expression: string;
+}
+
+export interface TransactionStep {
+	name: string;
+	method: 'GET' | 'POST' | 'PUT' | 'PATCH' | 'DELETE' | 'HEAD';
+	url: string;
+	headers?: Record<string, string>;
+	body?: string;
+	extract?: TransactionExtraction[];
+	assertions?: Assertion[];
+}
+
+export interface TransactionWaterfallStep {
+	name: string;
+	status: 'passed' | 'failed' | 'skipped';
+	offset_ms: number;
+	duration_ms: number;
+	status_code?: number;
+	error?: string;
+}
+
+export interface TransactionDefinition {
+	version?: number;
+	steps: TransactionStep[];
+	last_run?: {
+		version: number;
+		at: string;
+		duration_ms: number;
+		waterfall: TransactionWaterfallStep[];
+	};
+}
+
+export type AssertionType = 'status_code' | 'header' | 'body' | 'json_path' | 'response_size';
+
+export type AssertionOperator =
+	| 'equals'
+	| 'not_equals'
+	| 'contains'
+	| 'not_contains'
+	| 'matches'
+	| 'less_than'
+	| 'greater_than'
+	| 'between'
+	| 'exists';
+
+export interface Assertion {
+	type: AssertionType;
+	property?: string;
+	operator: AssertionOperator;
+	target?: string;
+}
+
+export interface PushSettings {
+	url: string;
+	grace_seconds: number;
+	last_ping_at?: string;
+	next_due_at?: string;
+}
+
+export interface MonitorLocations {
+	agent_ids: string[];
+	quorum: number;
+	breakdown?: LocationStatus[];
+}
+
+export type LocationState = 'up' | 'down' | 'unknown';
+
+export interface LocationStatus {
+	agent_id: string;
+	agent_name: string;
+	state: LocationState;
+	checks: number;
+	failures: number;
+	latency_ms?: number;
+	last_check_at?: string;
+	last_error?: string;
+}
+
+export interface FlapDetection {
+	window: number;
+	threshold_percent: number;
+}
+
+export interface DegradedRules {
+	latency_ms?: number;
+	latency_checks?: number;
+	failure_percent?: number;
+	window?: number;
+}
+
+export type MonitorType = 'ping' | 'http' | 'tcp' | 'dns' | 'tls' | 'docker' | 'database' | 'system' | 'service' | 'port_scan' | 'snmp' | 'push' | 'transaction';
 export type MonitorStatus = 'pending' | 'up' | 'down' | 'degraded';
 
 export interface Incident {
@@ -44,9 +147,14 @@ export interface Incident {
 	resolved_at: string | null;
 	acknowledged_at: string | null;
 	ttr_seconds: number | null;
+	kind?: IncidentKind;
+	severity?: IncidentSeverity;
+	parent_incident_id?: string;
 }
 
-export type IncidentStatus = 'open' | 'acknowledged' | 'resolved';
+export type IncidentStatus = 'open' | 'acknowledged' | 'resolved' | 'flapping';
+export type IncidentKind = 'down' | 'degraded' | 'anomaly';
+export type IncidentSeverity = 'critical' | 'major' | 'minor' | 'info';
 
 export interface AlertChannel {
 	id: string;
@@ -58,7 +166,7 @@ export interface AlertChannel {
 	updated_at: string;
 }
 
-export type AlertChannelType = 'discord' | 'slack' | 'email' | 'telegram' | 'pagerduty' | 'webhook';
+export type AlertChannelType = 'discord' | 'slack' | 'email' | 'telegram' | 'pagerduty' | 'webhook' | 'teams' | 'mattermost' | 'opsgenie' | 'splunk_oncall';
 
 export interface APIToken {
 	id: string;
@@ -121,6 +229,7 @@ export interface DashboardStats {
 	total_monitors: number;
 	monitors_up: number;
 	monitors_down: number;
+	monitors_degraded?: number;
 	active_incidents: number;
 	total_agents: number;
 	online_agents: number;
@@ -289,6 +398,8 @@ export interface PublicIncidentData {
 	resolved_at: string | null;
 	duration_seconds: number;
 	status: string;
+	kind: string;
+	severity: string;
 	is_active: boolean;
 }
 
@@ -306,6 +417,20 @@ export interface IncidentInvestigation {
 	system_metrics: SystemMetricSnapshot[];
 	cert_details: CertDetails | null;
 	timeline: TimelineEvent[];
+	locations?: LocationStatus[] | null;
+	dependency_tree?: DependencyNode | null;
+	root_cause_monitor_id?: string | null;
+}
+
+export interface DependencyNode {
+	monitor_id: string;
+	name: string;
+	status: MonitorStatus;
+	incident_id?: string;
+	suppressed: boolean;
+	root_cause: boolean;
+	parents?: DependencyNode[];
+	children?: DependencyNode[];
 }
 
 export interface AgentSummary {